@@ -0,0 +1,22 @@
+{{/*
+Create a default fully qualified app name.
+We truncate at 63 chars because some Kubernetes name fields are limited to this (by the DNS naming spec).
+If release name contains chart name it will be used as a full name.
+*/}}
+{{- define "memoryBroker.fullname" -}}
+{{ if .Values.memoryBroker.fullNameOverride -}}
+{{ .Values.memoryBroker.fullNameOverride }}
+{{ else -}}
+{{ printf "%s-%s" .Release.Name .Values.memoryBroker.name }}
+{{- end }}
+{{- end }}
+
+{{- define "memoryBroker.labels"}}
+{{- include "common.labels" $ }}
+app: {{ include "memoryBroker.fullname" $ }}
+app.kubernetes.io/name: {{ .Values.memoryBroker.name | default "memory-broker" }}
+{{- end -}}
+
+{{- define "memoryBroker.healthcheck" -}}
+{{ include "common.healthcheck" .Values.memoryBroker }}
+{{- end -}}
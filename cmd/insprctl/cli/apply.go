@@ -27,6 +27,10 @@ Apply takes a file or a directory and applies the defined components on the conn
 It can be called with the flag --update for updating instead of creating a new dApp.
 
 It can be called with the flag --dry-run so the changes that would be made are shown, but not applied on the cluster
+
+Updated components must carry the resource version they were read with, and they are only updated if they weren't
+changed on the cluster since that version.
+It can be called with the flag --force for updating them regardless of their resource version.
 		`).
 		WithExample("Applies a structure component defined in a file", "apply -f app.yaml").
 		WithExample("Applies components defined in a specific folder", "apply -k randfolder/").
@@ -59,6 +63,14 @@ It can be called with the flag --dry-run so the changes that would be made are s
 				FlagAddMethod: "BoolVar",
 				DefinedOn:     []string{"apply"},
 			},
+			{
+				Name:          "force",
+				Usage:         "insprctl apply (-f FILENAME | -k DIRECTORY) --update --force",
+				Value:         &cmd.InsprOptions.Force,
+				DefValue:      false,
+				FlagAddMethod: "BoolVar",
+				DefinedOn:     []string{"apply"},
+			},
 		}...).
 		WithCommonFlags().
 		WithOptions(cliutils.AddDefaultFlagCompletion()).
@@ -155,6 +167,11 @@ func applyValidFiles(path string, files []string, out io.Writer) []applied {
 		if err != nil {
 			ierrors.Wrap(err, file.fileName)
 			fmt.Fprint(out, ierrors.FormatError(err))
+			printReactionsReport(out, err)
+			if ierrors.HasCode(err, ierrors.Conflict) {
+				fmt.Fprintf(out, "%v was changed on the cluster since it was read, "+
+					"apply its current version or use --force to overwrite it\n", file.fileName)
+			}
 			continue
 		}
 
@@ -165,7 +182,7 @@ func applyValidFiles(path string, files []string, out io.Writer) []applied {
 }
 
 func getOrderedFiles(path string, files []string) []applied {
-	var apps, channels, types, aliases []applied
+	var apps, channels, types, aliases, templates, instances []applied
 	for _, file := range files {
 		if isYaml(file) {
 			comp := meta.Component{}
@@ -188,11 +205,40 @@ func getOrderedFiles(path string, files []string) []applied {
 				types = append(types, applied{component: comp, fileName: file, content: f})
 			} else if comp.Kind == "alias" {
 				aliases = append(aliases, applied{component: comp, fileName: file, content: f})
+			} else if comp.Kind == "template" {
+				templates = append(templates, applied{component: comp, fileName: file, content: f})
+			} else if comp.Kind == "instance" {
+				instances = append(instances, applied{component: comp, fileName: file, content: f})
 			}
 		}
 	}
 	ordered := append(apps, types...)
 	ordered = append(ordered, channels...)
 	ordered = append(ordered, aliases...)
+	ordered = append(ordered, templates...)
+	ordered = append(ordered, instances...)
 	return ordered
 }
+
+// updateVersion checks the resource version of a component that is going to be
+// updated. Updates must carry the version the component was read with, so that
+// they are rejected if it changed since then, unless they are forced, in which
+// case the version is dropped and the component is updated unconditionally.
+func updateVersion(metadata *meta.Metadata) error {
+	if !cmd.InsprOptions.Update {
+		return nil
+	}
+
+	if cmd.InsprOptions.Force {
+		metadata.ResourceVersion = 0
+		return nil
+	}
+
+	if metadata.ResourceVersion == 0 {
+		return ierrors.New(
+			"'%v' has no resource version, set the one it was read with or use --force to overwrite it",
+			metadata.Name,
+		).BadRequest()
+	}
+	return nil
+}
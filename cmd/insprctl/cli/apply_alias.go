@@ -40,6 +40,10 @@ func NewApplyAlias() RunMethod {
 			return err
 		}
 
+		if err = updateVersion(&alias.Meta); err != nil {
+			return err
+		}
+
 		// creates or updates it
 		if flagIsUpdate {
 			log, err = c.Update(context.Background(), parentScope, &alias, flagDryRun)
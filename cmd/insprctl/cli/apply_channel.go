@@ -46,6 +46,10 @@ func NewApplyChannel() RunMethod {
 			return err
 		}
 
+		if err = updateVersion(&channel.Meta); err != nil {
+			return err
+		}
+
 		// creates or updates it
 		if flagIsUpdate {
 			log, err = c.Update(context.Background(), parentScope, &channel, flagDryRun)
@@ -1,13 +1,21 @@
 package cli
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"gopkg.in/yaml.v2"
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/cmd"
 	cliutils "inspr.dev/inspr/pkg/cmd/utils"
 	"inspr.dev/inspr/pkg/ierrors"
 	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+	"inspr.dev/inspr/pkg/rest"
 )
 
 func TestNewApplyChannel(t *testing.T) {
@@ -63,3 +71,74 @@ func TestNewApplyChannel(t *testing.T) {
 		})
 	}
 }
+
+func TestNewApplyChannel_force(t *testing.T) {
+	prepareToken(t)
+	defer func() {
+		cmd.InsprOptions.Update = false
+		cmd.InsprOptions.Force = false
+	}()
+	tests := []struct {
+		name        string
+		version     int
+		force       bool
+		wantErr     bool
+		wantVersion int
+	}{
+		{
+			name:        "sends the resource version",
+			version:     2,
+			wantVersion: 2,
+		},
+		{
+			name:        "drops the resource version when forced",
+			version:     2,
+			force:       true,
+			wantVersion: 0,
+		},
+		{
+			name:    "requires a resource version",
+			wantErr: true,
+		},
+		{
+			name:        "updates without a resource version when forced",
+			force:       true,
+			wantVersion: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd.InsprOptions.Update = true
+			cmd.InsprOptions.Force = tt.force
+
+			chanBytes, _ := yaml.Marshal(meta.Channel{
+				Meta: meta.Metadata{Name: "mock", ResourceVersion: tt.version},
+			})
+
+			got := -1
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				data := models.ChannelDI{}
+				json.NewDecoder(r.Body).Decode(&data)
+				got = data.Channel.Meta.ResourceVersion
+				rest.JSON(w, http.StatusOK, diff.Changelog{})
+			}
+			server := httptest.NewServer(http.HandlerFunc(handler))
+			defer server.Close()
+			cliutils.SetClient(server.URL, "")
+
+			err := NewApplyChannel()(chanBytes, &bytes.Buffer{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewApplyChannel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if got != -1 {
+					t.Errorf("NewApplyChannel() sent an update without a resource version")
+				}
+				return
+			}
+			if got != tt.wantVersion {
+				t.Errorf("NewApplyChannel() sent version %v, want %v", got, tt.wantVersion)
+			}
+		})
+	}
+}
@@ -44,6 +44,10 @@ func NewApplyApp() RunMethod {
 		if err != nil {
 			return err
 		}
+		if err = updateVersion(&app.Meta); err != nil {
+			return err
+		}
+
 		// creates or updates it
 		if flagIsUpdate {
 			updateQuery, errQuery := metautils.JoinScopes(scope, app.Meta.Name)
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"context"
+	"io"
+
+	"gopkg.in/yaml.v2"
+	"inspr.dev/inspr/pkg/cmd"
+	cliutils "inspr.dev/inspr/pkg/cmd/utils"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	metautils "inspr.dev/inspr/pkg/meta/utils"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+)
+
+// NewApplyTemplate receives a controller TemplateInterface and calls it's methods
+// depending on the flags values
+func NewApplyTemplate() RunMethod {
+	return func(data []byte, out io.Writer) error {
+		c := cliutils.GetCliClient().Templates()
+		template := meta.Template{
+			Meta: meta.Metadata{Annotations: make(map[string]string)},
+		}
+
+		// unmarshal into a Template
+		if err := yaml.Unmarshal(data, &template); err != nil {
+			return err
+		}
+		if template.Meta.Name == "" {
+			return ierrors.New("template without name")
+		}
+
+		err := recursiveSchemaInjection(&template.App)
+		if err != nil {
+			return err
+		}
+
+		flagDryRun := cmd.InsprOptions.DryRun
+		flagIsUpdate := cmd.InsprOptions.Update
+
+		var log diff.Changelog
+		scope, err := metautils.JoinScopes(cmd.InsprOptions.Scope, template.Meta.Parent)
+		if err != nil {
+			return err
+		}
+		if err = updateVersion(&template.Meta); err != nil {
+			return err
+		}
+
+		// creates or updates it, updates roll out to the template's instances
+		if flagIsUpdate {
+			log, err = c.Update(context.Background(), scope, &template, flagDryRun)
+		} else {
+			log, err = c.Create(context.Background(), scope, &template, flagDryRun)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		// prints differences
+		log.Print(out)
+
+		return nil
+	}
+}
+
+// NewApplyInstance receives a controller InstanceInterface and calls it's methods
+// depending on the flags values
+func NewApplyInstance() RunMethod {
+	return func(data []byte, out io.Writer) error {
+		c := cliutils.GetCliClient().Instances()
+		instance := meta.TemplateInstance{
+			Meta: meta.Metadata{Annotations: make(map[string]string)},
+		}
+
+		// unmarshal into an instance
+		if err := yaml.Unmarshal(data, &instance); err != nil {
+			return err
+		}
+		if instance.Meta.Name == "" {
+			return ierrors.New("instance without name")
+		}
+		if instance.Template == "" {
+			return ierrors.New("instance without template")
+		}
+
+		flagDryRun := cmd.InsprOptions.DryRun
+		flagIsUpdate := cmd.InsprOptions.Update
+
+		var log diff.Changelog
+		scope, err := metautils.JoinScopes(cmd.InsprOptions.Scope, instance.Meta.Parent)
+		if err != nil {
+			return err
+		}
+
+		// creates or updates it
+		if flagIsUpdate {
+			log, err = c.Update(context.Background(), scope, &instance, flagDryRun)
+		} else {
+			log, err = c.Create(context.Background(), scope, &instance, flagDryRun)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		// prints differences
+		log.Print(out)
+
+		return nil
+	}
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+	cliutils "inspr.dev/inspr/pkg/cmd/utils"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+)
+
+func TestNewApplyTemplate(t *testing.T) {
+	prepareToken(t)
+	templateWithoutNameBytes, _ := yaml.Marshal(meta.Template{})
+	templateDefaultBytes, _ := yaml.Marshal(meta.Template{
+		Meta:       meta.Metadata{Name: "pipeline"},
+		Parameters: []meta.TemplateParameter{{Name: "customer", Required: true}},
+		App: meta.App{
+			Spec: meta.AppSpec{
+				Node: meta.Node{Spec: meta.NodeSpec{Image: "{{ .customer }}/image"}},
+			},
+		},
+	})
+	tests := []struct {
+		name string
+		b    []byte
+		want error
+	}{
+		{
+			name: "default_test",
+			b:    templateDefaultBytes,
+			want: nil,
+		},
+		{
+			name: "template_without_name",
+			b:    templateWithoutNameBytes,
+			want: ierrors.New("template without name"),
+		},
+		{
+			name: "error_testing",
+			b:    templateDefaultBytes,
+			want: errors.New("new error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cliutils.SetMockedClient(tt.want)
+			got := NewApplyTemplate()
+
+			r := got(tt.b, nil)
+
+			if r != nil && tt.want != nil {
+				if r.Error() != tt.want.Error() {
+					t.Errorf("NewApplyTemplate() = %v, want %v", r.Error(), tt.want.Error())
+				}
+			} else if r != tt.want {
+				t.Errorf("NewApplyTemplate() = %v, want %v", r, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewApplyInstance(t *testing.T) {
+	prepareToken(t)
+	instanceWithoutNameBytes, _ := yaml.Marshal(meta.TemplateInstance{Template: "pipeline"})
+	instanceWithoutTemplateBytes, _ := yaml.Marshal(meta.TemplateInstance{
+		Meta: meta.Metadata{Name: "acme"},
+	})
+	instanceDefaultBytes, _ := yaml.Marshal(meta.TemplateInstance{
+		Meta:     meta.Metadata{Name: "acme"},
+		Template: "pipeline",
+		Values:   map[string]string{"customer": "acme"},
+	})
+	tests := []struct {
+		name string
+		b    []byte
+		want error
+	}{
+		{
+			name: "default_test",
+			b:    instanceDefaultBytes,
+			want: nil,
+		},
+		{
+			name: "instance_without_name",
+			b:    instanceWithoutNameBytes,
+			want: ierrors.New("instance without name"),
+		},
+		{
+			name: "instance_without_template",
+			b:    instanceWithoutTemplateBytes,
+			want: ierrors.New("instance without template"),
+		},
+		{
+			name: "error_testing",
+			b:    instanceDefaultBytes,
+			want: errors.New("new error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cliutils.SetMockedClient(tt.want)
+			got := NewApplyInstance()
+
+			r := got(tt.b, nil)
+
+			if r != nil && tt.want != nil {
+				if r.Error() != tt.want.Error() {
+					t.Errorf("NewApplyInstance() = %v, want %v", r.Error(), tt.want.Error())
+				}
+			} else if r != tt.want {
+				t.Errorf("NewApplyInstance() = %v, want %v", r, tt.want)
+			}
+		})
+	}
+}
@@ -54,6 +54,15 @@ func createAliasYaml() string {
 	return string(data)
 }
 
+func createComponentYaml(kind string) string {
+	comp := meta.Component{
+		Kind:       kind,
+		APIVersion: "v1",
+	}
+	data, _ := yaml.Marshal(&comp)
+	return string(data)
+}
+
 func createInvalidYaml() string {
 	comp := meta.Component{
 		Kind:       "none",
@@ -359,6 +368,19 @@ func Test_applyValidFiles(t *testing.T) {
 				filePath,
 			)),
 		},
+		{
+			name: "conflict_error",
+			args: args{
+				path:  "",
+				files: tempFiles,
+			},
+			funcErr: ierrors.New("conflict").Conflict(),
+			errMsg: ierrors.FormatError(ierrors.Wrap(
+				ierrors.New("conflict").Conflict(),
+				filePath,
+			)) + filePath + " was changed on the cluster since it was read, " +
+				"apply its current version or use --force to overwrite it\n",
+		},
 		{
 			name: "Unknown_error",
 			args: args{
@@ -414,8 +436,10 @@ func Test_getOrderedFiles(t *testing.T) {
 	defer os.Remove("ct.yml")
 	defer os.Remove("al.yml")
 	defer os.Remove("invalid.yml")
-	tempFiles := []string{"app.yml", "invalid.yml",
-		"ch.yml", "ct.yml", "al.yml"}
+	defer os.Remove("inst.yml")
+	defer os.Remove("tpl.yml")
+	tempFiles := []string{"inst.yml", "app.yml", "invalid.yml",
+		"ch.yml", "ct.yml", "al.yml", "tpl.yml"}
 	// creates a file with the expected syntax
 	ioutil.WriteFile(
 		"app.yml",
@@ -442,6 +466,16 @@ func Test_getOrderedFiles(t *testing.T) {
 		[]byte(createInvalidYaml()),
 		os.ModePerm,
 	)
+	ioutil.WriteFile(
+		"tpl.yml",
+		[]byte(createComponentYaml("template")),
+		os.ModePerm,
+	)
+	ioutil.WriteFile(
+		"inst.yml",
+		[]byte(createComponentYaml("instance")),
+		os.ModePerm,
+	)
 
 	type args struct {
 		path  string
@@ -504,6 +538,22 @@ func orderedContent() []applied {
 			},
 			content: []byte(createAliasYaml()),
 		},
+		{
+			fileName: "tpl.yml",
+			component: meta.Component{
+				Kind:       "template",
+				APIVersion: "v1",
+			},
+			content: []byte(createComponentYaml("template")),
+		},
+		{
+			fileName: "inst.yml",
+			component: meta.Component{
+				Kind:       "instance",
+				APIVersion: "v1",
+			},
+			content: []byte(createComponentYaml("instance")),
+		},
 	}
 
 	return ordered
@@ -59,6 +59,10 @@ func NewApplyType() RunMethod {
 			return err
 		}
 
+		if err = updateVersion(&insprType.Meta); err != nil {
+			return err
+		}
+
 		// creates or updates it
 		if flagIsUpdate {
 			log, err = c.Update(context.Background(), parentScope, &insprType, flagDryRun)
@@ -19,13 +19,19 @@ func NewBrokerCmd() *cobra.Command {
 		WithExample("install kafka broker from a kafka.yaml", "brokers kafka <file>").
 		ExactArgs(1, kafkaConfig)
 
+	memoryCmd := cmd.NewCmd("memory").
+		WithDescription("Configures an in-memory broker on insprd by importing a valid yaml file carring the address of the in-memory broker service").
+		WithExample("install the in-memory broker from a memory.yaml", "brokers memory <file>").
+		ExactArgs(1, memoryConfig)
+
 	return cmd.NewCmd("brokers").
 		WithDescription("Retrieves brokers currently installed").
 		WithLongDescription(`Broker is the command that returns the brokers already installed on the cluster.
 It also has subcommands which installs a specific broker on the cluster`).
 		WithExample("get brokers already installed on the cluster", "brokers").
 		WithExample("install kafka broker from a kafka.yaml", "brokers kafka <file>").
-		AddSubCommand(kafkaCmd).
+		WithExample("install the in-memory broker from a memory.yaml", "brokers memory <file>").
+		AddSubCommand(kafkaCmd, memoryCmd).
 		NoArgs(getBrokers)
 }
 
@@ -52,6 +58,10 @@ func kafkaConfig(c context.Context, args []string) error {
 	return brokerConfig("kafka", args[0])
 }
 
+func memoryConfig(c context.Context, args []string) error {
+	return brokerConfig("memory", args[0])
+}
+
 func brokerConfig(brokerName, filePath string) error {
 	client := utils.GetCliClient()
 	output := utils.GetCliOutput()
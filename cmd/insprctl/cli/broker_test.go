@@ -112,6 +112,14 @@ func Test_brokerConfig(t *testing.T) {
 	os.WriteFile(kafkaFile, kafkaConfigBytes, 0777)
 	defer os.Remove(kafkaFile)
 
+	// memory yml preparation
+	memoryFile := dir + "/memoryConfig.yml"
+	memoryConfigBytes, _ := yaml.Marshal(sidecars.MemoryConfig{
+		Address: "mock_address",
+	})
+	os.WriteFile(memoryFile, memoryConfigBytes, 0777)
+	defer os.Remove(memoryFile)
+
 	// invalid yml preparation
 	invalidFile := dir + "/invalidConfig.yml"
 	os.WriteFile(invalidFile, []byte{1}, 0777)
@@ -168,6 +176,12 @@ func Test_brokerConfig(t *testing.T) {
 			wantMsg: "successfully installed broker on insprd\n",
 			wantErr: false,
 		},
+		{
+			name:    "working_memory",
+			args:    []string{"memory", memoryFile},
+			wantMsg: "successfully installed broker on insprd\n",
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -23,6 +23,13 @@ func NewInsprCommand(out, err io.Writer, version string) *cobra.Command {
 			completionCmd,
 			NewClusterCommand(),
 			NewBrokerCmd(),
+			NewHistoryCmd(),
+			NewRollbackCmd(),
+			NewExportCmd(),
+			NewImportCmd(),
+			NewStatusCmd(),
+			NewGraphCmd(),
+			NewDeadLettersCmd(),
 			initCommand,
 		).
 		Version(version).
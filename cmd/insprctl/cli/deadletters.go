@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/cmd"
+	cliutils "inspr.dev/inspr/pkg/cmd/utils"
+	"inspr.dev/inspr/pkg/ierrors"
+)
+
+// maxDeadLetterMessageSize is the amount of bytes of each message that are shown
+const maxDeadLetterMessageSize = 40
+
+// NewDeadLettersCmd creates deadletters command for Inspr CLI
+func NewDeadLettersCmd() *cobra.Command {
+	return cmd.NewCmd("deadletters").
+		WithDescription("Retrieves the messages of a channel that couldn't be delivered").
+		WithLongDescription(`Deadletters lists the messages read from a channel that couldn't be delivered
+to a node and were sent to the dead-letter channel of the channel, along with the node
+that failed to handle them, the number of attempts and the last error.
+
+Only the latest messages of the dead-letter channel are looked at, as many as the flag --limit.`).
+		WithExample("list the dead-letters of a channel", "deadletters app1.ch1").
+		WithExample("list the dead-letters of a channel in a scope", "deadletters ch1 --scope app1 --limit 50").
+		WithFlags(&cmd.Flag{
+			Name:      "limit",
+			Shorthand: "l",
+			Usage:     "number of messages of the dead-letter channel to look at",
+			Value:     &cmd.InsprOptions.DeadLetterLimit,
+			DefValue:  models.DefaultDeadLetterLimit,
+			DefinedOn: []string{"deadletters"},
+		}).
+		WithCommonFlags().
+		ExactArgs(1, getDeadLetters)
+}
+
+func getDeadLetters(_ context.Context, args []string) error {
+	client := cliutils.GetCliClient()
+	out := cliutils.GetCliOutput()
+
+	scope, err := cliutils.GetScope()
+	if err != nil {
+		fmt.Fprintln(out, "invalid scope")
+		return err
+	}
+
+	path, chName, err := cliutils.ProcessArg(args[0], scope)
+	if err != nil {
+		fmt.Fprintf(out, "%v\n", ierrors.FormatError(err))
+		return err
+	}
+
+	deadLetters, err := client.Channels().DeadLetters(
+		context.Background(),
+		path,
+		chName,
+		cmd.InsprOptions.DeadLetterLimit,
+	)
+	if err != nil {
+		fmt.Fprintf(out, "%v\n", ierrors.FormatError(err))
+		return err
+	}
+
+	lines := []string{"FAILED AT\t NODE\t ATTEMPTS\t ERROR\t MESSAGE\n"}
+	for _, deadLetter := range deadLetters {
+		message := deadLetter.Message
+		if len(message) > maxDeadLetterMessageSize {
+			message = message[:maxDeadLetterMessageSize]
+		}
+		lines = append(lines, fmt.Sprintf(
+			"%s\t %s\t %d\t %s\t %s\n",
+			deadLetter.FailedAt.Local().Format(time.RFC3339),
+			deadLetter.Node,
+			deadLetter.Attempts,
+			deadLetter.Error,
+			strconv.Quote(string(message)),
+		))
+	}
+	printTab(&lines)
+	return nil
+}
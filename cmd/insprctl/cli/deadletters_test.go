@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/cmd"
+	cliutils "inspr.dev/inspr/pkg/cmd/utils"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/rest"
+)
+
+func Test_getDeadLetters(t *testing.T) {
+	prepareToken(t)
+	defer restartScopeFlag()
+	defer func() { cmd.InsprOptions.DeadLetterLimit = models.DefaultDeadLetterLimit }()
+
+	failedAt := time.Date(2021, 9, 1, 12, 0, 0, 0, time.UTC)
+	deadLetters := []meta.DeadLetterMessage{
+		{
+			Channel:  "ch1",
+			Node:     "app1-node",
+			Error:    "node responded with status 500",
+			Attempts: 3,
+			FailedAt: failedAt,
+			Message:  []byte(`{"id":"order-1"}`),
+		},
+	}
+
+	expected := bytes.NewBufferString("")
+	cliutils.SetOutput(expected)
+	printTab(&[]string{
+		"FAILED AT\t NODE\t ATTEMPTS\t ERROR\t MESSAGE\n",
+		failedAt.Local().Format(time.RFC3339) + "\t app1-node\t 3\t node responded with status 500\t \"{\\\"id\\\":\\\"order-1\\\"}\"\n",
+	})
+
+	tests := []struct {
+		name           string
+		args           []string
+		wantScope      string
+		wantLimit      int
+		expectedOutput string
+	}{
+		{
+			name:           "Should list the dead-letters of the channel path",
+			args:           []string{"app1.ch1"},
+			wantScope:      "app1",
+			wantLimit:      models.DefaultDeadLetterLimit,
+			expectedOutput: expected.String(),
+		},
+		{
+			name:           "Should list the dead-letters of the channel in the scope",
+			args:           []string{"ch1", "--scope", "app1", "--limit", "5"},
+			wantScope:      "app1",
+			wantLimit:      5,
+			expectedOutput: expected.String(),
+		},
+		{
+			name:           "Should return error",
+			args:           []string{"app2.ch1"},
+			expectedOutput: ierrors.FormatError(ierrors.New("wrong request").BadRequest()) + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd.InsprOptions.DeadLetterLimit = models.DefaultDeadLetterLimit
+
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				data := models.DeadLetterQueryDI{}
+				json.NewDecoder(r.Body).Decode(&data)
+				if r.URL.Path != "/channels/deadletters" || r.Header.Get(rest.HeaderScopeKey) != tt.wantScope ||
+					data.ChName != "ch1" || data.Limit != tt.wantLimit {
+					rest.ERROR(w, ierrors.New("wrong request").BadRequest())
+					return
+				}
+				rest.JSON(w, http.StatusOK, deadLetters)
+			}
+			server := httptest.NewServer(http.HandlerFunc(handler))
+			cliutils.SetClient(server.URL, "")
+			defer server.Close()
+
+			buf := bytes.NewBufferString("")
+			cliutils.SetOutput(buf)
+			deadLettersCmd := NewDeadLettersCmd()
+			deadLettersCmd.SetArgs(tt.args)
+			deadLettersCmd.Execute()
+
+			if got := buf.String(); got != tt.expectedOutput {
+				t.Errorf("getDeadLetters() = %v, want %v", got, tt.expectedOutput)
+			}
+		})
+	}
+}
@@ -90,6 +90,7 @@ func deleteApps(_ context.Context, args []string) error {
 		)
 		if err != nil {
 			fmt.Fprintf(out, "%v\n", ierrors.FormatError(err))
+			printReactionsReport(out, err)
 			return err
 		}
 		cl.Print(out)
@@ -120,6 +121,7 @@ func deleteChannels(_ context.Context, args []string) error {
 		)
 		if err != nil {
 			fmt.Fprintf(out, "%v\n", ierrors.FormatError(err))
+			printReactionsReport(out, err)
 			return err
 		}
 		cl.Print(out)
@@ -151,6 +153,7 @@ func deleteTypes(_ context.Context, args []string) error {
 		)
 		if err != nil {
 			fmt.Fprintf(out, "%v\n", ierrors.FormatError(err))
+			printReactionsReport(out, err)
 			return err
 		}
 		cl.Print(out)
@@ -182,6 +185,7 @@ func deleteAlias(_ context.Context, args []string) error {
 		)
 		if err != nil {
 			fmt.Fprintf(out, "%v\n", ierrors.FormatError(err))
+			printReactionsReport(out, err)
 			return err
 		}
 		cl.Print(out)
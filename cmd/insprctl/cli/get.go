@@ -16,6 +16,17 @@ import (
 	"inspr.dev/inspr/pkg/meta"
 )
 
+// selectorFlag filters the components listed by the get subcommands by their annotations
+var selectorFlag = &cmd.Flag{
+	Name:          "selector",
+	Shorthand:     "l",
+	Usage:         "insprctl get <component> -l key=value",
+	Value:         &cmd.InsprOptions.Selector,
+	DefValue:      "",
+	FlagAddMethod: "",
+	DefinedOn:     []string{"apps", "channels", "types", "alias"},
+}
+
 // NewGetCmd creates get command for Inspr CLI
 func NewGetCmd() *cobra.Command {
 	getApps := cmd.NewCmd("apps").
@@ -23,12 +34,16 @@ func NewGetCmd() *cobra.Command {
 		WithAliases("a").
 		WithExample("Get apps from the default scope", "get apps ").
 		WithExample("Get apps from a custom scope", "get apps --scope app1.app2").
+		WithExample("Get apps with an annotation from the whole scope", "get apps -l env=prod").
+		WithFlags(selectorFlag).
 		WithCommonFlags().
 		NoArgs(getApps)
 	getChannels := cmd.NewCmd("channels").
 		WithDescription("Get channels from context").
 		WithExample("Get channels from the default scope", "get channels ").
 		WithExample("Get channels from a custom scope", "get channels --scope app1.app2").
+		WithExample("Get channels with an annotation from the whole scope", "get channels -l env=prod").
+		WithFlags(selectorFlag).
 		WithAliases("ch").
 		WithCommonFlags().
 		NoArgs(getChannels)
@@ -36,6 +51,8 @@ func NewGetCmd() *cobra.Command {
 		WithDescription("Get types from context").
 		WithExample("Get types from the default scope", "get types ").
 		WithExample("Get types from a custom scope", "get types --scope app1.app2").
+		WithExample("Get types with an annotation from the whole scope", "get types -l env=prod").
+		WithFlags(selectorFlag).
 		WithAliases("t").
 		WithCommonFlags().
 		NoArgs(getTypes)
@@ -50,6 +67,8 @@ func NewGetCmd() *cobra.Command {
 		WithDescription("Get alias from context").
 		WithExample("Get alias from the default scope", "get alias ").
 		WithExample("Get alias from a custom scope", "get alias --scope app1.app2").
+		WithExample("Get alias with an annotation from the whole scope", "get alias -l env=prod").
+		WithFlags(selectorFlag).
 		WithAliases("al").
 		WithCommonFlags().
 		NoArgs(getAlias)
@@ -61,6 +80,7 @@ func NewGetCmd() *cobra.Command {
 		WithExample("gets types from cluster", "get t --scope <scope>").
 		WithExample("gets nodes from cluster", "get nodes --scope <scope>").
 		WithExample("gets alias from cluster", "get alias --scope <scope>").
+		WithExample("gets channels with an annotation from cluster", "get ch --scope <scope> -l key=value").
 		WithLongDescription("get takes a component type (apps | channels | types | nodes | alias) and displays names for those components is a scope)").
 		WithAliases("list").
 		AddSubCommand(getApps).
@@ -83,7 +103,18 @@ func getApps(_ context.Context) error {
 
 	lines := make([]string, 0)
 	initTab(&lines)
-	err = getObj(printApps, &lines, client, out, scope)
+	if cmd.InsprOptions.Selector != "" {
+		err = getSelected(func(selector string) ([]meta.Metadata, error) {
+			apps, err := client.Apps().List(context.Background(), scope, selector)
+			metadata := []meta.Metadata{}
+			for _, app := range apps {
+				metadata = append(metadata, app.Meta)
+			}
+			return metadata, err
+		}, &lines, out)
+	} else {
+		err = getObj(printApps, &lines, client, out, scope)
+	}
 	if err != nil {
 		return err
 	}
@@ -109,7 +140,18 @@ func getChannels(_ context.Context) error {
 	lines := make([]string, 0)
 	initTab(&lines)
 
-	err = getObj(printChannels, &lines, client, out, scope)
+	if cmd.InsprOptions.Selector != "" {
+		err = getSelected(func(selector string) ([]meta.Metadata, error) {
+			channels, err := client.Channels().List(context.Background(), scope, selector)
+			metadata := []meta.Metadata{}
+			for _, ch := range channels {
+				metadata = append(metadata, ch.Meta)
+			}
+			return metadata, err
+		}, &lines, out)
+	} else {
+		err = getObj(printChannels, &lines, client, out, scope)
+	}
 	if err != nil {
 		return err
 	}
@@ -135,7 +177,18 @@ func getTypes(_ context.Context) error {
 	lines := make([]string, 0)
 	initTab(&lines)
 
-	err = getObj(printTypes, &lines, client, out, scope)
+	if cmd.InsprOptions.Selector != "" {
+		err = getSelected(func(selector string) ([]meta.Metadata, error) {
+			types, err := client.Types().List(context.Background(), scope, selector)
+			metadata := []meta.Metadata{}
+			for _, t := range types {
+				metadata = append(metadata, t.Meta)
+			}
+			return metadata, err
+		}, &lines, out)
+	} else {
+		err = getObj(printTypes, &lines, client, out, scope)
+	}
 	if err != nil {
 		return err
 	}
@@ -162,7 +215,18 @@ func getAlias(_ context.Context) error {
 	lines := make([]string, 0)
 	initTab(&lines)
 
-	err = getObj(printAliases, &lines, client, out, scope)
+	if cmd.InsprOptions.Selector != "" {
+		err = getSelected(func(selector string) ([]meta.Metadata, error) {
+			aliases, err := client.Alias().List(context.Background(), scope, selector)
+			metadata := []meta.Metadata{}
+			for _, alias := range aliases {
+				metadata = append(metadata, alias.Meta)
+			}
+			return metadata, err
+		}, &lines, out)
+	} else {
+		err = getObj(printAliases, &lines, client, out, scope)
+	}
 	if err != nil {
 		return err
 	}
@@ -203,6 +267,21 @@ func getObj(printObj func(*meta.App, *[]string), lines *[]string, client control
 	return nil
 }
 
+// getSelected adds to the lines the names of the components returned by the
+// given function, which lists them with the selector given on the command flags
+func getSelected(list func(selector string) ([]meta.Metadata, error), lines *[]string, out io.Writer) error {
+	components, err := list(cmd.InsprOptions.Selector)
+	if err != nil {
+		fmt.Fprintf(out, "%v\n", ierrors.FormatError(err))
+		return err
+	}
+
+	for _, component := range components {
+		printLine(component.Name, lines)
+	}
+	return nil
+}
+
 func printApps(app *meta.App, lines *[]string) {
 	if app.Meta.Name != "" {
 		printLine(app.Meta.Name, lines)
@@ -13,6 +13,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/cmd"
 	cliutils "inspr.dev/inspr/pkg/cmd/utils"
 	"inspr.dev/inspr/pkg/ierrors"
 	"inspr.dev/inspr/pkg/meta"
@@ -711,3 +712,45 @@ func Test_getAlias(t *testing.T) {
 		})
 	}
 }
+
+func Test_getChannels_selector(t *testing.T) {
+	prepareToken(t)
+	bufResp := bytes.NewBufferString("")
+	tabWriter := tabwriter.NewWriter(bufResp, 0, 0, 3, ' ', tabwriter.AlignRight|tabwriter.Debug)
+	fmt.Fprint(tabWriter, "NAME\n")
+	fmt.Fprint(tabWriter, "ch1\n")
+	fmt.Fprint(tabWriter, "ch3\n")
+	tabWriter.Flush()
+
+	cmd.InsprOptions.Selector = "env=prod"
+	defer func() { cmd.InsprOptions.Selector = "" }()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		data := models.ChannelQueryDI{}
+		json.NewDecoder(r.Body).Decode(&data)
+		if data.Selector == nil {
+			rest.JSON(w, http.StatusOK, meta.Channel{})
+			return
+		}
+		if *data.Selector != "env=prod" {
+			t.Errorf("getChannels() selector = %v, want env=prod", *data.Selector)
+		}
+		rest.JSON(w, http.StatusOK, []meta.Channel{
+			{Meta: meta.Metadata{Name: "ch1", Parent: "app1"}},
+			{Meta: meta.Metadata{Name: "ch3", Parent: "app1.app2"}},
+		})
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+	cliutils.SetClient(server.URL, "")
+	buf := bytes.NewBufferString("")
+	cliutils.SetOutput(buf)
+
+	if err := getChannels(context.Background()); err != nil {
+		t.Errorf("getChannels() error = %v", err)
+	}
+	if got := buf.String(); got != bufResp.String() {
+		t.Errorf("getChannels() = %v, want %v", got, bufResp.String())
+	}
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/cmd"
+	cliutils "inspr.dev/inspr/pkg/cmd/utils"
+	"inspr.dev/inspr/pkg/ierrors"
+)
+
+// NewGraphCmd creates graph command for Inspr CLI
+func NewGraphCmd() *cobra.Command {
+	return cmd.NewCmd("graph").
+		WithDescription("Renders the data flow between the nodes of a scope").
+		WithLongDescription(`
+Graph renders the resolved data flow of the scope given by the flag --scope, or of the whole cluster if no scope
+is given: the nodes, the channels they read from and write to, and the routes they call and serve.
+Boundaries are resolved through aliases, and the connections resolved through them are dashed.
+
+The graph can be rendered in the dot and mermaid formats, to be used in diagrams, or as json.
+		`).
+		WithExample("Renders the data flow of the whole cluster in dot", "graph").
+		WithExample("Renders the data flow of a scope as a png", "graph --scope app1 | dot -Tpng -o app1.png").
+		WithExample("Renders the data flow of a scope in mermaid", "graph --scope app1 --format mermaid").
+		WithFlags(&cmd.Flag{
+			Name:          "format",
+			Shorthand:     "f",
+			Usage:         "format of the graph: dot, mermaid or json",
+			Value:         &cmd.InsprOptions.GraphFormat,
+			DefValue:      models.GraphDOT,
+			FlagAddMethod: "",
+			DefinedOn:     []string{"graph"},
+		}).
+		WithCommonFlags().
+		NoArgs(getGraph)
+}
+
+func getGraph(_ context.Context) error {
+	client := cliutils.GetCliClient()
+	out := cliutils.GetCliOutput()
+
+	scope, err := cliutils.GetScope()
+	if err != nil {
+		fmt.Fprintln(out, "invalid scope")
+		return err
+	}
+
+	format := cmd.InsprOptions.GraphFormat
+	switch format {
+	case models.GraphJSON:
+		graph, err := client.Graph().Get(context.Background(), scope)
+		if err != nil {
+			fmt.Fprintf(out, "%v\n", ierrors.FormatError(err))
+			return err
+		}
+		data, _ := json.MarshalIndent(graph, "", "  ")
+		fmt.Fprintln(out, string(data))
+
+	case models.GraphDOT, models.GraphMermaid:
+		graph, err := client.Graph().Render(context.Background(), scope, format)
+		if err != nil {
+			fmt.Fprintf(out, "%v\n", ierrors.FormatError(err))
+			return err
+		}
+		fmt.Fprint(out, graph)
+
+	default:
+		fmt.Fprintln(out, "Invalid command call\nFor help, type 'insprctl graph --help'")
+		return ierrors.New("invalid format %v, use dot, mermaid or json", format).BadRequest()
+	}
+	return nil
+}
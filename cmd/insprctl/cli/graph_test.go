@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/cmd"
+	cliutils "inspr.dev/inspr/pkg/cmd/utils"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/rest"
+)
+
+func Test_getGraph(t *testing.T) {
+	prepareToken(t)
+	defer restartScopeFlag()
+	defer func() { cmd.InsprOptions.GraphFormat = models.GraphDOT }()
+
+	graph := models.Graph{
+		Scope: "app1",
+		Vertices: []models.GraphVertex{
+			{ID: "channel:app1.ch1", Kind: models.GraphChannel, Scope: "app1", Name: "ch1"},
+			{ID: "node:app1.node", Kind: models.GraphNode, Scope: "app1", Name: "node"},
+		},
+		Edges: []models.GraphEdge{
+			{From: "node:app1.node", To: "channel:app1.ch1", Kind: models.GraphOutput, Boundary: "ch1"},
+		},
+	}
+	graphJSON, _ := json.MarshalIndent(graph, "", "  ")
+
+	tests := []struct {
+		name           string
+		args           []string
+		wantFormat     string
+		expectedOutput string
+	}{
+		{
+			name:           "Should render the graph in dot",
+			args:           []string{"--scope", "app1"},
+			wantFormat:     models.GraphDOT,
+			expectedOutput: graph.DOT(),
+		},
+		{
+			name:           "Should render the graph in mermaid",
+			args:           []string{"--scope", "app1", "--format", "mermaid"},
+			wantFormat:     models.GraphMermaid,
+			expectedOutput: graph.Mermaid(),
+		},
+		{
+			name:           "Should print the graph as json",
+			args:           []string{"--scope", "app1", "-f", "json"},
+			wantFormat:     models.GraphJSON,
+			expectedOutput: string(graphJSON) + "\n",
+		},
+		{
+			name:           "Should reject an invalid format",
+			args:           []string{"--format", "svg"},
+			expectedOutput: "Invalid command call\nFor help, type 'insprctl graph --help'\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd.InsprOptions.GraphFormat = models.GraphDOT
+
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				data := models.GraphQueryDI{}
+				json.NewDecoder(r.Body).Decode(&data)
+				if r.URL.Path != "/graph" || data.Format != tt.wantFormat {
+					rest.ERROR(w, ierrors.New("wrong request %v %v", r.URL.Path, data.Format).BadRequest())
+					return
+				}
+				switch data.Format {
+				case models.GraphDOT:
+					rest.JSON(w, http.StatusOK, graph.DOT())
+				case models.GraphMermaid:
+					rest.JSON(w, http.StatusOK, graph.Mermaid())
+				default:
+					rest.JSON(w, http.StatusOK, graph)
+				}
+			}
+			server := httptest.NewServer(http.HandlerFunc(handler))
+			cliutils.SetClient(server.URL, "")
+			defer server.Close()
+
+			buf := bytes.NewBufferString("")
+			cliutils.SetOutput(buf)
+			graphCmd := NewGraphCmd()
+			graphCmd.SetArgs(tt.args)
+			graphCmd.Execute()
+
+			if got := buf.String(); got != tt.expectedOutput {
+				t.Errorf("getGraph() = %v, want %v", got, tt.expectedOutput)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"inspr.dev/inspr/pkg/api/models"
+	metautils "inspr.dev/inspr/pkg/meta/utils"
+	"inspr.dev/inspr/pkg/rest/request"
+)
+
+// printReactionsReport prints the report of the reactions that insprd applied
+// and undid when it fails to apply the changes of a request, if the error has one
+func printReactionsReport(out io.Writer, err error) {
+	var respErr *request.ResponseError
+	if !errors.As(err, &respErr) {
+		return
+	}
+
+	reactionsErr := models.ReactionsErrorDI{}
+	if json.Unmarshal(respErr.Body, &reactionsErr) != nil || len(reactionsErr.Report.Steps) == 0 {
+		return
+	}
+
+	fmt.Fprintf(out, "%d of %d applied reactions undone:\n",
+		reactionsErr.Report.Reverted(), len(reactionsErr.Report.Steps))
+
+	tabWriter := tabwriter.NewWriter(out, 0, 0, 3, ' ', tabwriter.AlignRight|tabwriter.Debug)
+	fmt.Fprint(tabWriter, "REACTION\t COMPONENT\t STATUS\n")
+	for _, step := range reactionsErr.Report.Steps {
+		component, _ := metautils.JoinScopes(step.Scope, step.Name)
+		status := "applied"
+		if step.Error != "" {
+			status = "failed: " + step.Error
+		}
+		if step.Reverted {
+			status += ", undone"
+		} else if step.RevertError != "" {
+			status += ", not undone: " + step.RevertError
+		}
+		fmt.Fprintf(tabWriter, "%s\t %s\t %s\n", step.Reaction, component, status)
+	}
+	tabWriter.Flush()
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+	"inspr.dev/inspr/pkg/rest/request"
+)
+
+func Test_printReactionsReport(t *testing.T) {
+	body, _ := json.Marshal(models.ReactionsErrorDI{
+		Stack: "node failed",
+		Code:  ierrors.InternalServer,
+		Report: diff.ReactionsReport{Steps: []diff.ReactionStep{
+			{Reaction: "createdChannels", Scope: "app1", Name: "ch1", Reverted: true},
+			{Reaction: "createdNodes", Scope: "app1", Error: "node failed", RevertError: "timeout"},
+		}},
+	})
+
+	tests := []struct {
+		name string
+		err  error
+		want []string
+	}{
+		{
+			name: "error with a reactions report",
+			err:  &request.ResponseError{Err: ierrors.New("node failed"), Body: body},
+			want: []string{
+				"1 of 2 applied reactions undone",
+				"app1.ch1",
+				"applied, undone",
+				"failed: node failed, not undone: timeout",
+			},
+		},
+		{
+			name: "error without a report",
+			err:  ierrors.New("node failed"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := bytes.NewBufferString("")
+			printReactionsReport(buf, tt.err)
+
+			got := buf.String()
+			if len(tt.want) == 0 && got != "" {
+				t.Errorf("printReactionsReport() = %v, want no output", got)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("printReactionsReport() = %v, want it to contain %v", got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"inspr.dev/inspr/pkg/cmd"
+	cliutils "inspr.dev/inspr/pkg/cmd/utils"
+	"inspr.dev/inspr/pkg/ierrors"
+)
+
+// NewHistoryCmd creates history command for Inspr CLI
+func NewHistoryCmd() *cobra.Command {
+	return cmd.NewCmd("history").
+		WithDescription("Retrieves the revisions committed on the cluster").
+		WithLongDescription(`History lists every change committed on the cluster as a numbered revision,
+along with the user that made it and when it was made.
+
+Any of these revisions can be restored with the rollback command.`).
+		WithExample("list the cluster revisions", "history").
+		WithCommonFlags().
+		NoArgs(getHistory)
+}
+
+// NewRollbackCmd creates rollback command for Inspr CLI
+func NewRollbackCmd() *cobra.Command {
+	return cmd.NewCmd("rollback").
+		WithDescription("Reverts the cluster to a previous revision").
+		WithLongDescription(`Rollback takes a revision number, as listed by the history command, and reverts
+the cluster to the state it had on that revision. The rollback is committed as a new revision.
+
+It can be called with the flag --dry-run so the changes that would be made are shown, but not applied on the cluster`).
+		WithExample("reverts the cluster to the revision 3", "rollback 3").
+		WithExample("shows the changes needed to revert to the revision 3", "rollback 3 --dry-run").
+		WithCommonFlags().
+		ExactArgs(1, doRollback)
+}
+
+func getHistory(_ context.Context) error {
+	client := cliutils.GetCliClient()
+	out := cliutils.GetCliOutput()
+
+	revisions, err := client.Revisions().History(context.Background())
+	if err != nil {
+		fmt.Fprintf(out, "%v\n", ierrors.FormatError(err))
+		return err
+	}
+
+	lines := []string{"REVISION\t AUTHOR\t TIMESTAMP\t CHANGES\n"}
+	for _, revision := range revisions {
+		lines = append(lines, fmt.Sprintf(
+			"%d\t %s\t %s\t %d\n",
+			revision.Number,
+			revision.Author,
+			revision.Timestamp.Local().Format(time.RFC3339),
+			len(revision.Changes),
+		))
+	}
+	printTab(&lines)
+	return nil
+}
+
+func doRollback(_ context.Context, args []string) error {
+	client := cliutils.GetCliClient()
+	out := cliutils.GetCliOutput()
+
+	revision, err := strconv.Atoi(args[0])
+	if err != nil || revision <= 0 {
+		fmt.Fprint(out, "invalid args: revision must be a positive number\n")
+		return ierrors.New("invalid revision %v", args[0]).BadRequest()
+	}
+
+	cl, err := client.Revisions().Rollback(
+		context.Background(),
+		revision,
+		cmd.InsprOptions.DryRun,
+	)
+	if err != nil {
+		fmt.Fprintf(out, "%v\n", ierrors.FormatError(err))
+		printReactionsReport(out, err)
+		return err
+	}
+	cl.Print(out)
+	return nil
+}
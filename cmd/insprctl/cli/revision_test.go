@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"inspr.dev/inspr/pkg/api/models"
+	cliutils "inspr.dev/inspr/pkg/cmd/utils"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+	"inspr.dev/inspr/pkg/rest"
+)
+
+func Test_getHistory(t *testing.T) {
+	prepareToken(t)
+	defer restartScopeFlag()
+
+	timestamp := time.Date(2021, 9, 1, 12, 0, 0, 0, time.UTC)
+	revisions := []models.Revision{
+		{
+			Number:    1,
+			Author:    "user1",
+			Timestamp: timestamp,
+			Changes:   diff.Changelog{{Scope: "app1"}},
+		},
+	}
+
+	expected := bytes.NewBufferString("")
+	cliutils.SetOutput(expected)
+	printTab(&[]string{
+		"REVISION\t AUTHOR\t TIMESTAMP\t CHANGES\n",
+		"1\t user1\t " + timestamp.Local().Format(time.RFC3339) + "\t 1\n",
+	})
+
+	tests := []struct {
+		name           string
+		handlerFunc    func(w http.ResponseWriter, r *http.Request)
+		expectedOutput string
+	}{
+		{
+			name: "Should list the revisions",
+			handlerFunc: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/revisions" || r.Method != http.MethodGet {
+					rest.ERROR(w, ierrors.New("wrong request").BadRequest())
+					return
+				}
+				rest.JSON(w, http.StatusOK, revisions)
+			},
+			expectedOutput: expected.String(),
+		},
+		{
+			name: "Should return error",
+			handlerFunc: func(w http.ResponseWriter, r *http.Request) {
+				rest.ERROR(w, ierrors.New("mock_error"))
+			},
+			expectedOutput: ierrors.FormatError(ierrors.New("mock_error")) + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewHistoryCmd()
+			buf := bytes.NewBufferString("")
+			cliutils.SetOutput(buf)
+
+			server := httptest.NewServer(http.HandlerFunc(tt.handlerFunc))
+			cliutils.SetClient(server.URL, "")
+			defer server.Close()
+
+			cmd.Execute()
+			got := buf.String()
+
+			if !reflect.DeepEqual(got, tt.expectedOutput) {
+				t.Errorf("getHistory() = %v, want %v", got, tt.expectedOutput)
+			}
+		})
+	}
+}
+
+func Test_doRollback(t *testing.T) {
+	prepareToken(t)
+	defer restartScopeFlag()
+
+	bufResp := bytes.NewBufferString("")
+	changelog, _ := diff.Diff(getMockApp(), getMockAppWithoutApp1())
+	changelog.Print(bufResp)
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		data := models.RevisionQueryDI{}
+		json.NewDecoder(r.Body).Decode(&data)
+
+		if r.URL.Path != "/revisions/rollback" || r.Method != http.MethodPut {
+			rest.ERROR(w, ierrors.New("wrong request").BadRequest())
+			return
+		}
+		if data.Revision != 3 {
+			rest.ERROR(w, ierrors.New("revision not found").BadRequest())
+			return
+		}
+		rest.JSON(w, http.StatusOK, changelog)
+	}
+
+	tests := []struct {
+		name           string
+		flagsAndArgs   []string
+		expectedOutput string
+	}{
+		{
+			name:           "Should rollback and return the diff",
+			flagsAndArgs:   []string{"3"},
+			expectedOutput: bufResp.String(),
+		},
+		{
+			name:           "Should return the server error",
+			flagsAndArgs:   []string{"4"},
+			expectedOutput: ierrors.FormatError(ierrors.New("revision not found").BadRequest()) + "\n",
+		},
+		{
+			name:           "Invalid revision",
+			flagsAndArgs:   []string{"three"},
+			expectedOutput: "invalid args: revision must be a positive number\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewRollbackCmd()
+			buf := bytes.NewBufferString("")
+			cliutils.SetOutput(buf)
+			cmd.SetArgs(tt.flagsAndArgs)
+
+			server := httptest.NewServer(http.HandlerFunc(handler))
+			cliutils.SetClient(server.URL, "")
+			defer server.Close()
+
+			cmd.Execute()
+			got := buf.String()
+
+			if !reflect.DeepEqual(got, tt.expectedOutput) {
+				t.Errorf("doRollback() = %v, want %v", got, tt.expectedOutput)
+			}
+		})
+	}
+}
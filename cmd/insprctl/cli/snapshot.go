@@ -0,0 +1,476 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/cmd"
+	cliutils "inspr.dev/inspr/pkg/cmd/utils"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	metautils "inspr.dev/inspr/pkg/meta/utils"
+)
+
+// snapshotFileName is the file in which export writes the scope and the brokers of the snapshot
+const snapshotFileName = "snapshot.yaml"
+
+// snapshotFile holds the parts of a snapshot that aren't components of the tree.
+// Its kind isn't handled by apply, so apply skips it.
+type snapshotFile struct {
+	meta.Component `yaml:",inline"`
+	Scope          string                   `yaml:"scope"`
+	DefaultBroker  string                   `yaml:"defaultBroker,omitempty"`
+	Brokers        map[string]yaml.MapSlice `yaml:"brokers,omitempty"`
+}
+
+// NewExportCmd creates export command for Inspr CLI
+func NewExportCmd() *cobra.Command {
+	exportCmd := cmd.NewCmd("export").
+		WithDescription("Exports a subtree of the cluster and its brokers to a directory").
+		WithLongDescription(`
+Export takes a snapshot of the scope given by the flag --scope, or of the whole cluster if no scope is given,
+and writes it to a directory as yaml files, one for each of the components of the scope.
+
+The files have the same kinds that apply takes, so they can be applied on another cluster.
+The configured brokers are written to the snapshot.yaml file, which apply skips.
+
+Use the import command to restore the whole snapshot, brokers included, in a single transaction.
+		`).
+		WithExample("Exports the whole cluster", "export -o backup/").
+		WithExample("Exports a scope", "export --scope app1.app2 -o backup/").
+		WithFlags(&cmd.Flag{
+			Name:          "output",
+			Shorthand:     "o",
+			Usage:         "insprctl export -o backup/",
+			Value:         &cmd.InsprOptions.ExportFolder,
+			DefValue:      "",
+			FlagAddMethod: "",
+			DefinedOn:     []string{"export"},
+		}).
+		WithCommonFlags().
+		NoArgs(doExport)
+
+	exportCmd.MarkFlagDirname("output")
+	return exportCmd
+}
+
+// NewImportCmd creates import command for Inspr CLI
+func NewImportCmd() *cobra.Command {
+	return cmd.NewCmd("import").
+		WithDescription("Restores a snapshot written by export on the cluster").
+		WithLongDescription(`
+Import takes a directory written by the export command and restores it on the cluster in a single transaction.
+The subtree of the exported scope is replaced by the one in the directory, and the brokers that aren't
+configured on the cluster are configured.
+
+It can be called with the flag --dry-run so the changes that would be made are shown, but not applied on the cluster
+		`).
+		WithExample("Restores a snapshot", "import backup/").
+		WithExample("Shows the changes needed to restore a snapshot", "import backup/ --dry-run").
+		WithCommonFlags().
+		ExactArgs(1, doImport)
+}
+
+func doExport(_ context.Context) error {
+	client := cliutils.GetCliClient()
+	out := cliutils.GetCliOutput()
+
+	if cmd.InsprOptions.ExportFolder == "" {
+		fmt.Fprintln(out, "Invalid command call\nFor help, type 'insprctl export --help'")
+		return ierrors.New("an output directory must be given").BadRequest()
+	}
+
+	scope, err := cliutils.GetScope()
+	if err != nil {
+		fmt.Fprintln(out, "invalid scope")
+		return err
+	}
+
+	snapshot, err := client.Snapshots().Export(context.Background(), scope)
+	if err != nil {
+		fmt.Fprint(out, ierrors.FormatError(err))
+		return err
+	}
+
+	written, err := writeSnapshot(cmd.InsprOptions.ExportFolder, snapshot)
+	if err != nil {
+		fmt.Fprintf(out, "unable to write snapshot: %v\n", err)
+		return err
+	}
+
+	fmt.Fprint(out, "\nExported:\n")
+	for _, file := range written {
+		fmt.Fprint(out, file.fileName+" | "+file.component.Kind+" | "+file.component.APIVersion+"\n")
+	}
+	return nil
+}
+
+func doImport(_ context.Context, args []string) error {
+	client := cliutils.GetCliClient()
+	out := cliutils.GetCliOutput()
+
+	snapshot, err := readSnapshot(args[0])
+	if err != nil {
+		fmt.Fprintf(out, "unable to read snapshot: %v\n", ierrors.FormatError(err))
+		return err
+	}
+
+	cl, err := client.Snapshots().Import(
+		context.Background(),
+		snapshot,
+		cmd.InsprOptions.DryRun,
+	)
+	if err != nil {
+		fmt.Fprint(out, ierrors.FormatError(err))
+		printReactionsReport(out, err)
+		return err
+	}
+	cl.Print(out)
+	return nil
+}
+
+// writeSnapshot writes the snapshot to the given directory. The dApp of a scope is
+// written with all of its subtree, while the components of the root dApp are
+// written each one to its own file, since the root can't be applied.
+func writeSnapshot(dir string, snapshot *models.Snapshot) ([]applied, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	files := []applied{}
+	write := func(fileName, kind string, component interface{}) error {
+		file := applied{
+			fileName:  fileName,
+			component: meta.Component{Kind: kind, APIVersion: "v1"},
+		}
+		content, err := yaml.Marshal(withKind(kind, component))
+		if err != nil {
+			return err
+		}
+		files = append(files, file)
+		return ioutil.WriteFile(filepath.Join(dir, fileName), content, 0644)
+	}
+
+	brokers := map[string]yaml.MapSlice{}
+	for _, broker := range snapshot.Brokers {
+		var config yaml.MapSlice
+		if err := yaml.Unmarshal(broker.FileContents, &config); err != nil {
+			return nil, err
+		}
+		brokers[broker.BrokerName] = config
+	}
+	err := write(snapshotFileName, "snapshot", &snapshotFile{
+		Component:     meta.Component{Kind: "snapshot", APIVersion: "v1"},
+		Scope:         snapshot.Scope,
+		DefaultBroker: snapshot.DefaultBroker,
+		Brokers:       brokers,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	app := snapshot.App
+	exportable(app)
+	if snapshot.Scope != "" {
+		return files, write(app.Meta.Name+".app.yaml", "dapp", app)
+	}
+
+	for _, name := range sortedNames(app.Spec.Types) {
+		t := app.Spec.Types[name]
+		t.Meta.Name, t.Meta.Parent = name, ""
+		if err = write(name+".type.yaml", "type", t); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range sortedNames(app.Spec.Channels) {
+		ch := app.Spec.Channels[name]
+		ch.Meta.Name, ch.Meta.Parent = name, ""
+		if err = write(name+".ch.yaml", "channel", ch); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range sortedNames(app.Spec.Apps) {
+		child := app.Spec.Apps[name]
+		child.Meta.Name, child.Meta.Parent = name, ""
+		if err = write(name+".app.yaml", "dapp", child); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range sortedNames(app.Spec.Aliases) {
+		alias := app.Spec.Aliases[name]
+		alias.Meta.Name, alias.Meta.Parent = name, ""
+		if err = write(name+".alias.yaml", "alias", alias); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// readSnapshot reads a snapshot written by writeSnapshot from the given directory.
+// The components are placed in the exported dApp according to their parents,
+// so components added to the directory with apply's syntax are imported as well.
+func readSnapshot(dir string) (*models.Snapshot, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, snapshotFileName))
+	if err != nil {
+		return nil, err
+	}
+	file := snapshotFile{}
+	if err = yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	snapshot := &models.Snapshot{
+		Scope:         file.Scope,
+		Brokers:       []models.BrokerConfigDI{},
+		DefaultBroker: file.DefaultBroker,
+	}
+	for _, name := range sortedNames(file.Brokers) {
+		contents, err := yaml.Marshal(file.Brokers[name])
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Brokers = append(snapshot.Brokers, models.BrokerConfigDI{
+			BrokerName:   name,
+			FileContents: contents,
+		})
+	}
+
+	names, err := getFilesFromFolder(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := getOrderedFiles(dir, names)
+
+	// dApps are placed before their children, so that these have where to be placed
+	apps := 0
+	for apps < len(files) && files[apps].component.Kind == "dapp" {
+		apps++
+	}
+	sort.SliceStable(files[:apps], func(i, j int) bool {
+		return componentDepth(files[i].content) < componentDepth(files[j].content)
+	})
+
+	root := &meta.App{}
+	if snapshot.Scope != "" {
+		root = nil
+	}
+	for _, file := range files {
+		if root == nil {
+			root, err = scopeApp(file, snapshot.Scope)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err = placeComponent(root, snapshot.Scope, file); err != nil {
+			return nil, ierrors.Wrap(err, file.fileName)
+		}
+	}
+	if root == nil {
+		return nil, ierrors.New("no dApp file found for the scope '%v'", snapshot.Scope).BadRequest()
+	}
+
+	if err = recursiveSchemaInjection(root); err != nil {
+		return nil, err
+	}
+	snapshot.App = root
+	return snapshot, nil
+}
+
+// scopeApp returns the dApp of the given file, which must be the dApp of the scope
+func scopeApp(file applied, scope string) (*meta.App, error) {
+	app := &meta.App{}
+	if file.component.Kind == "dapp" {
+		if err := yaml.Unmarshal(file.content, app); err != nil {
+			return nil, ierrors.Wrap(err, file.fileName)
+		}
+	}
+
+	query, _ := metautils.JoinScopes(app.Meta.Parent, app.Meta.Name)
+	if file.component.Kind != "dapp" || query != scope {
+		return nil, ierrors.New(
+			"%v: the first dApp of the snapshot must be the dApp of the scope '%v'",
+			file.fileName, scope,
+		).BadRequest()
+	}
+	return app, nil
+}
+
+// placeComponent places the component of the given file in the dApp of its parent,
+// looked up in the subtree of the given dApp, which is the dApp of the given scope
+func placeComponent(root *meta.App, scope string, file applied) error {
+	var component struct {
+		Meta meta.Metadata `yaml:"meta"`
+	}
+	if err := yaml.Unmarshal(file.content, &component); err != nil {
+		return err
+	}
+	name := component.Meta.Name
+	if name == "" {
+		return ierrors.New("%v without name", file.component.Kind).BadRequest()
+	}
+
+	parent, err := subtreeApp(root, scope, component.Meta.Parent)
+	if err != nil {
+		return err
+	}
+
+	switch file.component.Kind {
+	case "dapp":
+		app := &meta.App{}
+		err = yaml.Unmarshal(file.content, app)
+		if parent.Spec.Apps == nil {
+			parent.Spec.Apps = map[string]*meta.App{}
+		}
+		parent.Spec.Apps[name] = app
+	case "type":
+		t := &meta.Type{}
+		err = yaml.Unmarshal(file.content, t)
+		if parent.Spec.Types == nil {
+			parent.Spec.Types = map[string]*meta.Type{}
+		}
+		parent.Spec.Types[name] = t
+	case "channel":
+		ch := &meta.Channel{}
+		err = yaml.Unmarshal(file.content, ch)
+		if parent.Spec.Channels == nil {
+			parent.Spec.Channels = map[string]*meta.Channel{}
+		}
+		parent.Spec.Channels[name] = ch
+	case "alias":
+		alias := &meta.Alias{}
+		err = yaml.Unmarshal(file.content, alias)
+		if parent.Spec.Aliases == nil {
+			parent.Spec.Aliases = map[string]*meta.Alias{}
+		}
+		parent.Spec.Aliases[name] = alias
+	}
+	return err
+}
+
+// subtreeApp returns the dApp in the given scope, looked up in the
+// subtree of the given dApp, which is the dApp of the root scope
+func subtreeApp(app *meta.App, rootScope, scope string) (*meta.App, error) {
+	path := scope
+	if rootScope != "" {
+		if scope != rootScope && !strings.HasPrefix(scope, rootScope+".") {
+			return nil, ierrors.New("'%v' is outside of the snapshot scope '%v'", scope, rootScope).BadRequest()
+		}
+		path = strings.TrimPrefix(strings.TrimPrefix(scope, rootScope), ".")
+	}
+
+	if path == "" {
+		return app, nil
+	}
+	for _, name := range strings.Split(path, ".") {
+		child, ok := app.Spec.Apps[name]
+		if !ok {
+			return nil, ierrors.New("dApp '%v' not found in the snapshot", scope).NotFound()
+		}
+		app = child
+	}
+	return app, nil
+}
+
+// componentDepth returns the amount of dApps in the parent scope of the component in the given file
+func componentDepth(content []byte) int {
+	var component struct {
+		Meta meta.Metadata `yaml:"meta"`
+	}
+	yaml.Unmarshal(content, &component)
+	if component.Meta.Parent == "" {
+		return 0
+	}
+	return len(strings.Split(component.Meta.Parent, "."))
+}
+
+// exportable removes the fields that are set by insprd from the
+// components of the given dApp, so that they can be applied anew
+func exportable(app *meta.App) {
+	app.Meta.UUID = ""
+	app.Meta.ResourceVersion = 0
+	app.Spec.Node.Meta.UUID = ""
+	for _, t := range app.Spec.Types {
+		t.Meta.UUID = ""
+		t.Meta.ResourceVersion = 0
+		t.ConnectedChannels = nil
+	}
+	for _, ch := range app.Spec.Channels {
+		ch.Meta.UUID = ""
+		ch.Meta.ResourceVersion = 0
+		ch.ConnectedApps = nil
+		ch.Spec.SelectedBroker = ""
+	}
+	for _, alias := range app.Spec.Aliases {
+		alias.Meta.UUID = ""
+		alias.Meta.ResourceVersion = 0
+	}
+	for _, child := range app.Spec.Apps {
+		exportable(child)
+	}
+}
+
+// withKind returns the given component along with the header that tells apply its kind
+func withKind(kind string, component interface{}) interface{} {
+	header := meta.Component{Kind: kind, APIVersion: "v1"}
+	switch c := component.(type) {
+	case *meta.App:
+		return struct {
+			meta.Component `yaml:",inline"`
+			meta.App       `yaml:",inline"`
+		}{header, *c}
+	case *meta.Type:
+		return struct {
+			meta.Component `yaml:",inline"`
+			meta.Type      `yaml:",inline"`
+		}{header, *c}
+	case *meta.Channel:
+		return struct {
+			meta.Component `yaml:",inline"`
+			meta.Channel   `yaml:",inline"`
+		}{header, *c}
+	case *meta.Alias:
+		return struct {
+			meta.Component `yaml:",inline"`
+			meta.Alias     `yaml:",inline"`
+		}{header, *c}
+	}
+	return component
+}
+
+// sortedNames returns the sorted keys of a map of components
+func sortedNames(components interface{}) []string {
+	names := []string{}
+	switch c := components.(type) {
+	case map[string]*meta.App:
+		for name := range c {
+			names = append(names, name)
+		}
+	case map[string]*meta.Type:
+		for name := range c {
+			names = append(names, name)
+		}
+	case map[string]*meta.Channel:
+		for name := range c {
+			names = append(names, name)
+		}
+	case map[string]*meta.Alias:
+		for name := range c {
+			names = append(names, name)
+		}
+	case map[string]yaml.MapSlice:
+		for name := range c {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,207 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/cmd"
+	cliutils "inspr.dev/inspr/pkg/cmd/utils"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+	"inspr.dev/inspr/pkg/rest"
+)
+
+func snapshotMock(scope string) *models.Snapshot {
+	app := &meta.App{
+		Meta: meta.Metadata{Name: "app1", UUID: "uuid", ResourceVersion: 2},
+		Spec: meta.AppSpec{
+			Types: map[string]*meta.Type{
+				"t1": {
+					Meta:              meta.Metadata{Name: "t1", Parent: "app1"},
+					Schema:            `{"type":"string"}`,
+					ConnectedChannels: []string{"ch1"},
+				},
+			},
+			Channels: map[string]*meta.Channel{
+				"ch1": {
+					Meta:          meta.Metadata{Name: "ch1", Parent: "app1"},
+					Spec:          meta.ChannelSpec{Type: "t1", SelectedBroker: "kafka"},
+					ConnectedApps: []string{"app1.node"},
+				},
+			},
+			Apps: map[string]*meta.App{
+				"node": {
+					Meta: meta.Metadata{Name: "node", Parent: "app1"},
+					Spec: meta.AppSpec{
+						Node: meta.Node{Spec: meta.NodeSpec{Image: "image", Replicas: 1}},
+						Boundary: meta.AppBoundary{
+							Channels: meta.Boundary{Input: []string{"ch1"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	snapshot := &models.Snapshot{
+		Scope: scope,
+		App:   app,
+		Brokers: []models.BrokerConfigDI{{
+			BrokerName:   "kafka",
+			FileContents: []byte("bootstrapServers: kafka:9092\n"),
+		}},
+		DefaultBroker: "kafka",
+	}
+	if scope == "" {
+		app.Meta.Name = ""
+		snapshot.App = &meta.App{
+			Spec: meta.AppSpec{
+				Types:    app.Spec.Types,
+				Channels: app.Spec.Channels,
+				Apps:     map[string]*meta.App{"app2": {Meta: meta.Metadata{Name: "app2"}}},
+				Aliases: map[string]*meta.Alias{
+					"a1": {Meta: meta.Metadata{Name: "a1"}, Resource: "ch2", Source: "app2"},
+				},
+			},
+		}
+	}
+	return snapshot
+}
+
+func Test_writeSnapshot(t *testing.T) {
+	tests := []struct {
+		name  string
+		scope string
+		want  []string
+	}{
+		{
+			name:  "scope snapshot",
+			scope: "app1",
+			want:  []string{"snapshot.yaml", "app1.app.yaml"},
+		},
+		{
+			name:  "root snapshot",
+			scope: "",
+			want: []string{
+				"snapshot.yaml", "t1.type.yaml", "ch1.ch.yaml", "app2.app.yaml", "a1.alias.yaml",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, _ := ioutil.TempDir("", "export")
+			defer os.RemoveAll(dir)
+
+			snapshot := snapshotMock(tt.scope)
+			files, err := writeSnapshot(dir, snapshot)
+			if err != nil {
+				t.Fatalf("writeSnapshot() error = %v", err)
+			}
+			written := []string{}
+			for _, file := range files {
+				written = append(written, file.fileName)
+			}
+			if !reflect.DeepEqual(written, tt.want) {
+				t.Errorf("writeSnapshot() = %v, want %v", written, tt.want)
+			}
+
+			// the component files can be read by apply
+			names, _ := getFilesFromFolder(dir)
+			if ordered := getOrderedFiles(dir, names); len(ordered) != len(tt.want)-1 {
+				t.Errorf("writeSnapshot() wrote %v files that apply reads, want %v", len(ordered), len(tt.want)-1)
+			}
+
+			read, err := readSnapshot(dir)
+			if err != nil {
+				t.Fatalf("readSnapshot() error = %v", err)
+			}
+			got, _ := yaml.Marshal(read.App)
+			exported, _ := yaml.Marshal(snapshot.App)
+			if string(got) != string(exported) {
+				t.Errorf("readSnapshot() = %v, want %v", string(got), string(exported))
+			}
+			if read.Scope != tt.scope || read.DefaultBroker != "kafka" || len(read.Brokers) != 1 {
+				t.Errorf("readSnapshot() = %v, want the snapshot brokers", read)
+			}
+
+			config := map[string]string{}
+			yaml.Unmarshal(read.Brokers[0].FileContents, &config)
+			if config["bootstrapServers"] != "kafka:9092" {
+				t.Errorf("readSnapshot() broker configs = %v", config)
+			}
+		})
+	}
+}
+
+func Test_readSnapshot_invalid(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "import")
+	defer os.RemoveAll(dir)
+
+	if _, err := readSnapshot(dir); err == nil {
+		t.Errorf("readSnapshot() expected an error without the snapshot file")
+	}
+
+	writeSnapshot(dir, snapshotMock("app1"))
+	os.Remove(filepath.Join(dir, "app1.app.yaml"))
+	if _, err := readSnapshot(dir); err == nil {
+		t.Errorf("readSnapshot() expected an error without the dApp of the scope")
+	}
+}
+
+func Test_exportAndImport(t *testing.T) {
+	prepareToken(t)
+	defer restartScopeFlag()
+	defer func() {
+		cmd.InsprOptions.DryRun = false
+		cmd.InsprOptions.ExportFolder = ""
+	}()
+
+	dir, _ := ioutil.TempDir("", "export")
+	defer os.RemoveAll(dir)
+
+	var imported models.SnapshotQueryDI
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/snapshot":
+			rest.JSON(w, http.StatusOK, snapshotMock(r.Header.Get(rest.HeaderScopeKey)))
+		case "/snapshot/import":
+			json.NewDecoder(r.Body).Decode(&imported)
+			rest.JSON(w, http.StatusOK, diff.Changelog{})
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	cliutils.SetClient(server.URL, "")
+	defer server.Close()
+
+	buf := bytes.NewBufferString("")
+	cliutils.SetOutput(buf)
+	exportCmd := NewExportCmd()
+	exportCmd.SetArgs([]string{"--scope", "app1", "-o", dir})
+	if err := exportCmd.Execute(); err != nil {
+		t.Fatalf("export error = %v, output %v", err, buf.String())
+	}
+	want := "\nExported:\nsnapshot.yaml | snapshot | v1\napp1.app.yaml | dapp | v1\n"
+	if buf.String() != want {
+		t.Errorf("export output = %v, want %v", buf.String(), want)
+	}
+
+	importCmd := NewImportCmd()
+	importCmd.SetArgs([]string{dir, "--dry-run"})
+	if err := importCmd.Execute(); err != nil {
+		t.Fatalf("import error = %v", err)
+	}
+	if !imported.DryRun || imported.Snapshot.Scope != "app1" || imported.Snapshot.App.Meta.Name != "app1" {
+		t.Errorf("import sent %v, want the exported snapshot", imported)
+	}
+	if imported.Snapshot.App.Meta.UUID != "" || len(imported.Snapshot.App.Spec.Channels["ch1"].ConnectedApps) > 0 {
+		t.Errorf("import sent fields set by insprd: %v", imported.Snapshot.App)
+	}
+}
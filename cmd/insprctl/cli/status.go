@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/cmd"
+	cliutils "inspr.dev/inspr/pkg/cmd/utils"
+	"inspr.dev/inspr/pkg/ierrors"
+	metautils "inspr.dev/inspr/pkg/meta/utils"
+)
+
+// NewStatusCmd creates status command for Inspr CLI
+func NewStatusCmd() *cobra.Command {
+	return cmd.NewCmd("status").
+		WithDescription("Reports the drifts between the cluster and its runtime").
+		WithLongDescription(`
+Status reports the channels and nodes of the scope given by the flag --scope whose resources,
+such as kafka topics and k8s deployments, are missing from the runtime.
+
+The runtime is periodically reconciled with the cluster by insprd, and status shows the result of the
+last reconciliation. Use the flag --reconcile to reconcile the runtime right away, and the flag --repair
+to also create again the missing resources.
+		`).
+		WithExample("Reports the drifts of the whole cluster", "status").
+		WithExample("Reconciles the runtime and reports the drifts of a scope", "status --scope app1 --reconcile").
+		WithExample("Reconciles the runtime and repairs the drifts", "status --repair").
+		WithFlags(
+			&cmd.Flag{
+				Name:          "reconcile",
+				Usage:         "reconcile the runtime before reporting its drifts",
+				Value:         &cmd.InsprOptions.Reconcile,
+				DefValue:      false,
+				FlagAddMethod: "BoolVar",
+				DefinedOn:     []string{"status"},
+			},
+			&cmd.Flag{
+				Name:          "repair",
+				Usage:         "reconcile the runtime, creating again the missing resources",
+				Value:         &cmd.InsprOptions.Repair,
+				DefValue:      false,
+				FlagAddMethod: "BoolVar",
+				DefinedOn:     []string{"status"},
+			},
+		).
+		WithCommonFlags().
+		NoArgs(getStatus)
+}
+
+func getStatus(_ context.Context) error {
+	client := cliutils.GetCliClient()
+	out := cliutils.GetCliOutput()
+
+	scope, err := cliutils.GetScope()
+	if err != nil {
+		fmt.Fprintln(out, "invalid scope")
+		return err
+	}
+
+	var report *models.DriftReport
+	if cmd.InsprOptions.Reconcile || cmd.InsprOptions.Repair {
+		report, err = client.Status().Reconcile(context.Background(), scope, cmd.InsprOptions.Repair)
+	} else {
+		report, err = client.Status().Get(context.Background(), scope)
+	}
+	if err != nil {
+		fmt.Fprintf(out, "%v\n", ierrors.FormatError(err))
+		return err
+	}
+
+	printReport(report)
+	return nil
+}
+
+func printReport(report *models.DriftReport) {
+	out := cliutils.GetCliOutput()
+	if report.Timestamp.IsZero() {
+		fmt.Fprintln(out, "The runtime hasn't been reconciled yet")
+		return
+	}
+
+	fmt.Fprintf(out, "Reconciled at %v, %d channels and %d nodes checked\n",
+		report.Timestamp.Local().Format(time.RFC3339), report.Channels, report.Nodes)
+
+	for _, err := range report.Errors {
+		fmt.Fprintf(out, "unable to check: %v\n", err)
+	}
+
+	if len(report.Drifts) == 0 {
+		fmt.Fprintln(out, "No drifts found")
+		return
+	}
+
+	lines := []string{"KIND\t COMPONENT\t STATUS\t REASON\n"}
+	for _, drift := range report.Drifts {
+		component, _ := metautils.JoinScopes(drift.Scope, drift.Name)
+		status := "missing"
+		if drift.Repaired {
+			status = "repaired"
+		} else if drift.RepairError != "" {
+			status = "repair failed: " + drift.RepairError
+		}
+		lines = append(lines, fmt.Sprintf("%s\t %s\t %s\t %s\n", drift.Kind, component, status, drift.Reason))
+	}
+	printTab(&lines)
+}
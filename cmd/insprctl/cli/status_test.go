@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/cmd"
+	cliutils "inspr.dev/inspr/pkg/cmd/utils"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/rest"
+)
+
+func Test_getStatus(t *testing.T) {
+	prepareToken(t)
+	defer restartScopeFlag()
+	defer func() {
+		cmd.InsprOptions.Reconcile = false
+		cmd.InsprOptions.Repair = false
+	}()
+
+	timestamp := time.Date(2021, 9, 1, 12, 0, 0, 0, time.UTC)
+	report := models.DriftReport{
+		Timestamp: timestamp,
+		Channels:  2,
+		Nodes:     1,
+		Drifts: []models.Drift{
+			{Kind: models.DriftChannel, Scope: "app1", Name: "ch1", Reason: "topic not found"},
+			{Kind: models.DriftNode, Scope: "app1", Name: "node", Reason: "deployment not found", Repaired: true},
+		},
+	}
+	header := "Reconciled at " + timestamp.Local().Format(time.RFC3339) + ", 2 channels and 1 nodes checked\n"
+
+	expected := bytes.NewBufferString(header)
+	cliutils.SetOutput(expected)
+	printTab(&[]string{
+		"KIND\t COMPONENT\t STATUS\t REASON\n",
+		"channel\t app1.ch1\t missing\t topic not found\n",
+		"node\t app1.node\t repaired\t deployment not found\n",
+	})
+
+	tests := []struct {
+		name           string
+		args           []string
+		report         models.DriftReport
+		wantPath       string
+		wantRepair     bool
+		expectedOutput string
+	}{
+		{
+			name:           "Should report the last reconciliation",
+			args:           []string{"--scope", "app1"},
+			report:         report,
+			wantPath:       "/status",
+			expectedOutput: expected.String(),
+		},
+		{
+			name:           "Should report that no reconciliation ran",
+			report:         models.DriftReport{Drifts: []models.Drift{}},
+			wantPath:       "/status",
+			expectedOutput: "The runtime hasn't been reconciled yet\n",
+		},
+		{
+			name:           "Should reconcile the runtime",
+			args:           []string{"--reconcile"},
+			report:         models.DriftReport{Timestamp: timestamp, Channels: 2, Nodes: 1},
+			wantPath:       "/status/reconcile",
+			expectedOutput: header + "No drifts found\n",
+		},
+		{
+			name: "Should repair the runtime",
+			args: []string{"--repair"},
+			report: models.DriftReport{
+				Timestamp: timestamp,
+				Channels:  2,
+				Nodes:     1,
+				Errors:    []string{"kafka unavailable"},
+			},
+			wantPath:       "/status/reconcile",
+			wantRepair:     true,
+			expectedOutput: header + "unable to check: kafka unavailable\nNo drifts found\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd.InsprOptions.Reconcile = false
+			cmd.InsprOptions.Repair = false
+
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.wantPath {
+					rest.ERROR(w, ierrors.New("wrong path %v", r.URL.Path).BadRequest())
+					return
+				}
+				if r.Method == http.MethodPut {
+					data := models.ReconcileQueryDI{}
+					json.NewDecoder(r.Body).Decode(&data)
+					if data.Repair != tt.wantRepair {
+						rest.ERROR(w, ierrors.New("wrong repair").BadRequest())
+						return
+					}
+				}
+				rest.JSON(w, http.StatusOK, tt.report)
+			}
+			server := httptest.NewServer(http.HandlerFunc(handler))
+			cliutils.SetClient(server.URL, "")
+			defer server.Close()
+
+			buf := bytes.NewBufferString("")
+			cliutils.SetOutput(buf)
+			statusCmd := NewStatusCmd()
+			statusCmd.SetArgs(tt.args)
+			statusCmd.Execute()
+
+			if got := buf.String(); got != tt.expectedOutput {
+				t.Errorf("getStatus() = %v, want %v", got, tt.expectedOutput)
+			}
+		})
+	}
+}
@@ -37,5 +37,15 @@ func main() {
 		Kind:       "alias",
 	}, cli.NewApplyAlias())
 
+	cli.GetFactory().Subscribe(meta.Component{
+		APIVersion: "v1",
+		Kind:       "template",
+	}, cli.NewApplyTemplate())
+
+	cli.GetFactory().Subscribe(meta.Component{
+		APIVersion: "v1",
+		Kind:       "instance",
+	}, cli.NewApplyInstance())
+
 	cli.NewInsprCommand(os.Stdout, os.Stderr, version).Execute()
 }
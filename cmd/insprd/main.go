@@ -4,6 +4,9 @@ import (
 	"os"
 
 	"inspr.dev/inspr/cmd/insprd/memory"
+	"inspr.dev/inspr/cmd/insprd/memory/brokers"
+	"inspr.dev/inspr/cmd/insprd/memory/storage"
+	"inspr.dev/inspr/cmd/insprd/memory/tree"
 	"inspr.dev/inspr/cmd/insprd/operators"
 	"inspr.dev/inspr/pkg/api"
 	"inspr.dev/inspr/pkg/auth"
@@ -37,5 +40,30 @@ func main() {
 		}
 	}
 
+	store, err := getStorage()
+	if err != nil {
+		panic(err)
+	}
+
+	// the brokers are restored first, since the channels of the tree use them
+	err = brokers.Restore(store)
+	if err != nil {
+		panic(err)
+	}
+
+	err = tree.Restore(store)
+	if err != nil {
+		panic(err)
+	}
+
 	api.Run(memoryManager, operator, authenticator)
 }
+
+// getStorage returns the storage in which insprd persists its memory. If the
+// INSPR_STORAGE_PATH variable is not set the memory is lost on restarts.
+func getStorage() (storage.Storage, error) {
+	if path, ok := os.LookupEnv("INSPR_STORAGE_PATH"); ok && path != "" {
+		return storage.NewFileStorage(path)
+	}
+	return storage.NewMemoryStorage(), nil
+}
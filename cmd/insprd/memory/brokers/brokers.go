@@ -38,7 +38,8 @@ func (bmm *brokerMemoryManager) get() (*brokers.Brokers, error) {
 	return bmm.broker, nil
 }
 
-// Create configures a new broker on insprd
+// Create configures a new broker on insprd, which becomes the default broker
+// if there's none. The change is persisted before it's applied
 func (bmm *brokerMemoryManager) Create(config brokers.BrokerConfiguration) error {
 	l := logger.With(
 		zap.String("operation", "create"),
@@ -46,9 +47,10 @@ func (bmm *brokerMemoryManager) Create(config brokers.BrokerConfiguration) error
 	)
 	bmm.available.Lock()
 	l.Debug("available mutex locked", zap.String("type", "mutex"))
-
 	defer l.Debug("available mutex unlocked", zap.String("type", "mutex"))
 	defer bmm.available.Unlock()
+	bmm.def.Lock()
+	defer bmm.def.Unlock()
 
 	l.Info("creating new broker")
 	mem, err := bmm.get()
@@ -62,15 +64,10 @@ func (bmm *brokerMemoryManager) Create(config brokers.BrokerConfiguration) error
 		return ierrors.New("broker %s is already configured on memory", broker)
 	}
 
-	var factory models.SidecarFactory
-	switch broker {
-	case brokers.Kafka:
-		l.Debug("configuring broker as kafka brojer")
-		obj, _ := config.(*sidecars.KafkaConfig)
-		factory = sidecars.SimpleKafkaToDeployment(*obj)
-	default:
+	factory, err := sidecarFactory(config)
+	if err != nil {
 		l.Debug("found unsupported broker config, rejecting request")
-		return ierrors.New("broker %s is not supported", broker)
+		return err
 	}
 
 	l.Debug("subscribing broker to sidecar factory")
@@ -80,17 +77,32 @@ func (bmm *brokerMemoryManager) Create(config brokers.BrokerConfiguration) error
 		return err
 	}
 
-	mem.Available[broker] = config
-	if mem.Default == "" {
+	available := brokers.BrokerStatusArray{broker: config}
+	for name, config := range mem.Available {
+		available[name] = config
+	}
+	def := mem.Default
+	if def == "" {
 		l.Debug("no default broker found - setting broker as default")
-		bmm.SetDefault(broker)
+		def = broker
+	}
+
+	if err = bmm.persist(available, def); err != nil {
+		l.Error("unable to persist brokers", zap.Error(err))
+		bmm.Factory().Unsubscribe(broker)
+		return err
 	}
+
+	mem.Available[broker] = config
+	mem.Default = def
 	return nil
 }
 
 // SetDefault sets a previously configured broker as insprd's default broker
 func (bmm *brokerMemoryManager) SetDefault(broker string) error {
 	l := logger.With(zap.String("operation", "set-default"), zap.String("broker", broker))
+	bmm.available.Lock()
+	defer bmm.available.Unlock()
 	bmm.def.Lock()
 	l.Debug("def mutex locked", zap.String("type", "mutex"))
 
@@ -109,11 +121,78 @@ func (bmm *brokerMemoryManager) SetDefault(broker string) error {
 		return ierrors.New("broker %s is not configured on memory", broker)
 	}
 
+	if err = bmm.persist(mem.Available, broker); err != nil {
+		l.Error("unable to persist brokers", zap.Error(err))
+		return err
+	}
+
 	mem.Default = broker
 	l.Debug("default broker set")
 	return nil
 }
 
+// Delete removes a configured broker from insprd, along with its sidecar factory.
+// If it was the default broker insprd is left without a default one.
+func (bmm *brokerMemoryManager) Delete(broker string) error {
+	l := logger.With(zap.String("operation", "delete"), zap.String("broker", broker))
+	bmm.available.Lock()
+	defer bmm.available.Unlock()
+	bmm.def.Lock()
+	defer bmm.def.Unlock()
+
+	l.Info("deleting broker")
+	mem, err := bmm.get()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := mem.Available[broker]; !ok {
+		l.Debug("broker not configured")
+		return ierrors.New("broker %s is not configured on memory", broker).NotFound()
+	}
+
+	available := brokers.BrokerStatusArray{}
+	for name, config := range mem.Available {
+		if name != broker {
+			available[name] = config
+		}
+	}
+	def := mem.Default
+	if def == broker {
+		def = ""
+	}
+
+	factory, _ := bmm.Factory().Get(broker)
+	if err = bmm.Factory().Unsubscribe(broker); err != nil {
+		l.Error("unable to unsubscribe broker")
+		return err
+	}
+
+	if err = bmm.persist(available, def); err != nil {
+		l.Error("unable to persist brokers", zap.Error(err))
+		bmm.Factory().Subscribe(broker, factory)
+		return err
+	}
+
+	delete(mem.Available, broker)
+	mem.Default = def
+	return nil
+}
+
+// sidecarFactory returns the factory of the sidecars of the configured broker
+func sidecarFactory(config brokers.BrokerConfiguration) (models.SidecarFactory, error) {
+	switch broker := config.Broker(); broker {
+	case brokers.Kafka:
+		obj, _ := config.(*sidecars.KafkaConfig)
+		return sidecars.SimpleKafkaToDeployment(*obj), nil
+	case brokers.Memory:
+		obj, _ := config.(*sidecars.MemoryConfig)
+		return sidecars.MemoryToDeployment(*obj), nil
+	default:
+		return nil, ierrors.New("broker %s is not supported", broker)
+	}
+}
+
 // Factory provides the struct implementation for Sidecarfactory
 func (bmm *brokerMemoryManager) Factory() SidecarManager {
 	return bmm.factory
@@ -7,6 +7,7 @@ import (
 
 	"inspr.dev/inspr/cmd/sidecars"
 	apimodels "inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
 	"inspr.dev/inspr/pkg/meta/brokers"
 )
 
@@ -158,6 +159,34 @@ func TestBrokersMemoryManager_Create_and_SetDefault(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid delete",
+			bmm:  &brokerMemoryManager{},
+			exec: func(bmm Manager) error {
+				if err := bmm.Delete(brokers.Kafka); err != nil {
+					return err
+				}
+				if available, _ := bmm.Get(); len(available.Available) > 0 || available.Default != "" {
+					return ierrors.New("broker %v not deleted", available)
+				}
+				return nil
+			},
+		},
+		{
+			name: "invalid delete - broker not configured",
+			bmm:  &brokerMemoryManager{},
+			exec: func(bmm Manager) error {
+				return bmm.Delete(brokers.Kafka)
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid create of a deleted broker",
+			bmm:  &brokerMemoryManager{},
+			exec: func(bmm Manager) error {
+				return bmm.Create(&kafkaStructMock)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -14,6 +14,11 @@ func GetSidecarConnectionVars(broker string) *models.ConnectionVariables {
 			ReadEnvVar:  "INSPR_LBSIDECAR_READ_PORT",
 			WriteEnvVar: "INSPR_SIDECAR_KAFKA_WRITE_PORT",
 		}
+	case brokers.Memory:
+		return &models.ConnectionVariables{
+			ReadEnvVar:  "INSPR_LBSIDECAR_READ_PORT",
+			WriteEnvVar: "INSPR_SIDECAR_MEMORY_WRITE_PORT",
+		}
 	default:
 		return nil
 	}
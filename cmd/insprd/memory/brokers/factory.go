@@ -26,6 +26,15 @@ func (abf *AbstractBrokerFactory) Subscribe(broker string, factory models.Sideca
 	return ierrors.New("%s broker already subscribed", broker)
 }
 
+// Unsubscribe removes a broker specific factory from the Abstract broker factory
+func (abf *AbstractBrokerFactory) Unsubscribe(broker string) error {
+	if _, ok := factories[broker]; !ok {
+		return ierrors.New("%s broker not subscribed", broker).NotFound()
+	}
+	delete(factories, broker)
+	return nil
+}
+
 // Get returns a factory for the specifyed broker
 func (abf *AbstractBrokerFactory) Get(broker string) (models.SidecarFactory, error) {
 	if factories == nil {
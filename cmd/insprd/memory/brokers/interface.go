@@ -15,6 +15,7 @@ type Manager interface {
 	Get() (*apimodels.BrokersDI, error)
 	Create(config brokers.BrokerConfiguration) error
 	SetDefault(broker string) error
+	Delete(broker string) error
 	Factory() SidecarManager
 	Configs(broker string) (brokers.BrokerConfiguration, error)
 }
@@ -24,4 +25,5 @@ type Manager interface {
 type SidecarManager interface {
 	Get(broker string) (models.SidecarFactory, error)
 	Subscribe(broker string, factory models.SidecarFactory) error
+	Unsubscribe(broker string) error
 }
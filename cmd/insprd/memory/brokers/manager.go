@@ -4,6 +4,7 @@ import (
 	"sync"
 
 	"go.uber.org/zap"
+	"inspr.dev/inspr/cmd/insprd/memory/storage"
 	"inspr.dev/inspr/pkg/logs"
 	"inspr.dev/inspr/pkg/meta/brokers"
 )
@@ -26,6 +27,9 @@ type brokerMemoryManager struct {
 	broker    *brokers.Brokers
 	available sync.Mutex
 	def       sync.Mutex
+	// storage persists the configured brokers, they're only kept in memory
+	// while it isn't set
+	storage storage.Storage
 }
 
 var brokerMemory *brokerMemoryManager
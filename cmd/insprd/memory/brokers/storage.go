@@ -0,0 +1,130 @@
+package brokers
+
+import (
+	"encoding/json"
+
+	"go.uber.org/zap"
+	"inspr.dev/inspr/cmd/insprd/memory/storage"
+	"inspr.dev/inspr/cmd/sidecars"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta/brokers"
+)
+
+// brokersStorageKey is the key in which the configured brokers are persisted
+const brokersStorageKey = "brokers"
+
+// storedBrokers is how the configured brokers are persisted, with the
+// configuration of each broker encoded by its own type
+type storedBrokers struct {
+	Default string                     `json:"default"`
+	Configs map[string]json.RawMessage `json:"configs"`
+}
+
+// Restore sets the storage in which the configured brokers are persisted on
+// every change and configures again the brokers stored on it, if there are any.
+// It should be called before the tree memory is restored, so that the restored
+// channels find their brokers.
+func Restore(store storage.Storage) error {
+	GetBrokerMemory()
+	return brokerMemory.restore(store)
+}
+
+func (bmm *brokerMemoryManager) restore(store storage.Storage) error {
+	l := logger.With(zap.String("operation", "restore"))
+	bmm.available.Lock()
+	defer bmm.available.Unlock()
+	bmm.def.Lock()
+	defer bmm.def.Unlock()
+
+	bmm.storage = store
+	data, err := store.Get(brokersStorageKey)
+	if ierrors.HasCode(err, ierrors.NotFound) {
+		l.Info("no stored brokers found, starting without brokers")
+		return nil
+	}
+	if err != nil {
+		l.Error("unable to read brokers from storage", zap.Error(err))
+		return err
+	}
+
+	stored := storedBrokers{}
+	if err = json.Unmarshal(data, &stored); err != nil {
+		l.Error("unable to decode stored brokers", zap.Error(err))
+		return ierrors.Wrap(
+			ierrors.New(err).InternalServer(),
+			"unable to decode stored brokers",
+		)
+	}
+
+	mem, err := bmm.get()
+	if err != nil {
+		return err
+	}
+
+	for broker, data := range stored.Configs {
+		config, err := decodeConfig(broker, data)
+		if err != nil {
+			l.Error("unable to decode stored broker", zap.String("broker", broker), zap.Error(err))
+			return err
+		}
+
+		factory, err := sidecarFactory(config)
+		if err == nil {
+			err = bmm.Factory().Subscribe(broker, factory)
+		}
+		if err != nil {
+			l.Error("unable to subscribe stored broker", zap.String("broker", broker), zap.Error(err))
+			return err
+		}
+		mem.Available[broker] = config
+	}
+	mem.Default = stored.Default
+
+	l.Info("brokers restored from storage", zap.Strings("brokers", mem.Available.Brokers()))
+	return nil
+}
+
+// persist writes the given brokers to the storage, if one is set. It's called
+// before the changes are applied to the memory, so that they're dropped
+// when they can't be persisted
+func (bmm *brokerMemoryManager) persist(available brokers.BrokerStatusArray, def string) error {
+	if bmm.storage == nil {
+		return nil
+	}
+
+	stored := storedBrokers{
+		Default: def,
+		Configs: map[string]json.RawMessage{},
+	}
+	for broker, config := range available {
+		data, err := json.Marshal(config)
+		if err != nil {
+			return ierrors.New(err).InternalServer()
+		}
+		stored.Configs[broker] = data
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return ierrors.New(err).InternalServer()
+	}
+	return bmm.storage.Set(brokersStorageKey, data)
+}
+
+// decodeConfig decodes the stored configuration of a broker into its type
+func decodeConfig(broker string, data []byte) (brokers.BrokerConfiguration, error) {
+	var config brokers.BrokerConfiguration
+	switch broker {
+	case brokers.Kafka:
+		config = &sidecars.KafkaConfig{}
+	case brokers.Memory:
+		config = &sidecars.MemoryConfig{}
+	default:
+		return nil, ierrors.New("broker %s is not supported", broker).InternalServer()
+	}
+
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, ierrors.New(err).InternalServer()
+	}
+	return config, nil
+}
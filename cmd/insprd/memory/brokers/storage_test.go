@@ -0,0 +1,143 @@
+package brokers
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"inspr.dev/inspr/cmd/insprd/memory/storage"
+	"inspr.dev/inspr/cmd/sidecars"
+	apimodels "inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta/brokers"
+)
+
+// failingStorage is a storage in which nothing can be written
+type failingStorage struct {
+	storage.Storage
+}
+
+func (failingStorage) Set(key string, value []byte) error {
+	return ierrors.New("storage unavailable").InternalServer()
+}
+
+// resetBrokerStorage starts the tests with a new broker memory and no
+// subscribed factories, which are shared by every broker memory
+func resetBrokerStorage(t *testing.T) {
+	resetBrokers()
+	factories = nil
+	t.Cleanup(func() {
+		resetBrokers()
+		factories = nil
+	})
+}
+
+func getBrokers(t *testing.T) *apimodels.BrokersDI {
+	got, err := GetBrokerMemory().Get()
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	sort.Strings(got.Available)
+	return got
+}
+
+func TestRestore(t *testing.T) {
+	kafka := &sidecars.KafkaConfig{BootstrapServers: "kafka:9092", SidecarImage: "inspr/kafka"}
+	memory := &sidecars.MemoryConfig{Address: "http://inspr-memory-broker:8080"}
+
+	resetBrokerStorage(t)
+	store := storage.NewMemoryStorage()
+	if err := Restore(store); err != nil {
+		t.Fatalf("Restore() of an empty storage error = %v", err)
+	}
+	bmm := GetBrokerMemory()
+	bmm.Create(memory)
+	bmm.Create(kafka)
+	bmm.SetDefault(brokers.Kafka)
+
+	// a restart of insprd
+	resetBrokers()
+	factories = nil
+	if err := Restore(store); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	want := &apimodels.BrokersDI{
+		Available: []string{brokers.Kafka, brokers.Memory},
+		Default:   brokers.Kafka,
+	}
+	if got := getBrokers(t); !reflect.DeepEqual(got, want) {
+		t.Errorf("Restore() brokers = %v, want %v", got, want)
+	}
+	if got, _ := GetBrokerMemory().Configs(brokers.Kafka); !reflect.DeepEqual(got, kafka) {
+		t.Errorf("Restore() kafka config = %v, want %v", got, kafka)
+	}
+	if _, err := GetBrokerMemory().Factory().Get(brokers.Memory); err != nil {
+		t.Errorf("Restore() didn't subscribe the sidecar factory of the broker: %v", err)
+	}
+
+	// deletions are persisted as well
+	GetBrokerMemory().Delete(brokers.Kafka)
+	resetBrokers()
+	factories = nil
+	Restore(store)
+	want = &apimodels.BrokersDI{Available: []string{brokers.Memory}}
+	if got := getBrokers(t); !reflect.DeepEqual(got, want) {
+		t.Errorf("Restore() brokers after delete = %v, want %v", got, want)
+	}
+}
+
+func TestRestore_corrupted(t *testing.T) {
+	tests := []struct {
+		name   string
+		stored string
+	}{
+		{name: "not json", stored: "{not json"},
+		{name: "unsupported broker", stored: `{"configs":{"rabbitmq":{}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetBrokerStorage(t)
+			store := storage.NewMemoryStorage()
+			store.Set(brokersStorageKey, []byte(tt.stored))
+
+			if err := Restore(store); err == nil {
+				t.Errorf("Restore() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestBrokersMemoryManager_persist_failure(t *testing.T) {
+	resetBrokerStorage(t)
+	store := storage.NewMemoryStorage()
+	Restore(store)
+	bmm := GetBrokerMemory()
+	bmm.Create(&sidecars.MemoryConfig{})
+
+	brokerMemory.storage = failingStorage{store}
+	if err := bmm.Create(&kafkaStructMock); err == nil {
+		t.Errorf("Create() error = nil with a failing storage")
+	}
+	if err := bmm.SetDefault(brokers.Memory); err == nil {
+		t.Errorf("SetDefault() error = nil with a failing storage")
+	}
+	if err := bmm.Delete(brokers.Memory); err == nil {
+		t.Errorf("Delete() error = nil with a failing storage")
+	}
+
+	// the changes that couldn't be persisted are dropped
+	want := &apimodels.BrokersDI{
+		Available: []string{brokers.Memory},
+		Default:   brokers.Memory,
+	}
+	if got := getBrokers(t); !reflect.DeepEqual(got, want) {
+		t.Errorf("brokers = %v, want %v", got, want)
+	}
+	if _, err := bmm.Factory().Get(brokers.Kafka); err == nil {
+		t.Errorf("Create() kept the sidecar factory of a broker that wasn't persisted")
+	}
+	if _, err := bmm.Factory().Get(brokers.Memory); err != nil {
+		t.Errorf("Delete() dropped the sidecar factory of a broker that wasn't deleted: %v", err)
+	}
+}
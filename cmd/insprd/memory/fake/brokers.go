@@ -47,6 +47,19 @@ func (bks *BrokersMock) SetDefault(broker string) error {
 	return nil
 }
 
+// Delete removes a mocked broker, and the default one if it is the deleted broker
+func (bks *BrokersMock) Delete(broker string) error {
+	if bks.fail != nil {
+		return bks.fail
+	}
+
+	delete(bks.broker.Available, broker)
+	if bks.broker.Default == broker {
+		bks.broker.Default = ""
+	}
+	return nil
+}
+
 // Factory mock of factory interface
 func (bks *BrokersMock) Factory() memory.SidecarManager {
 	return bks.factory
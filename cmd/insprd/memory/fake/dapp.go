@@ -69,6 +69,15 @@ func (a *Apps) Update(scope string, app *meta.App, brokers *apimodels.BrokersDI)
 	return nil
 }
 
+// Replace - simple mock
+func (a *Apps) Replace(query string, app *meta.App, brokers *apimodels.BrokersDI) error {
+	if a.fail != nil {
+		return a.fail
+	}
+	a.apps[query] = app
+	return nil
+}
+
 // ResolveBoundary mock
 func (*Apps) ResolveBoundary(app *meta.App, usePermTree bool) (map[string]string, map[string]string, error) {
 	ret := map[string]string{}
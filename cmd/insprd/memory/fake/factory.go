@@ -17,6 +17,15 @@ func (f *Factory) Subscribe(broker string, factory models.SidecarFactory) error
 	return nil
 }
 
+// Unsubscribe mock of factory unsubscription method
+func (f *Factory) Unsubscribe(broker string) error {
+	if f.fail != nil {
+		return f.fail
+	}
+	delete(f.abstract, broker)
+	return nil
+}
+
 // Get mock of factory get method
 func (f *Factory) Get(broker string) (models.SidecarFactory, error) {
 	if f.fail != nil {
@@ -0,0 +1,55 @@
+package fake
+
+import (
+	"sort"
+
+	apimodels "inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
+)
+
+// Revisions - mocks the implementation of the RevisionMemory interface methods
+type Revisions struct {
+	fail      error
+	revisions map[int]*apimodels.Revision
+}
+
+// History - simple mock
+func (r *Revisions) History() ([]apimodels.Revision, error) {
+	if r.fail != nil {
+		return nil, r.fail
+	}
+	revisions := []apimodels.Revision{}
+	for _, revision := range r.revisions {
+		rev := *revision
+		rev.Tree = nil
+		revisions = append(revisions, rev)
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Number < revisions[j].Number
+	})
+	return revisions, nil
+}
+
+// Get - simple mock
+func (r *Revisions) Get(number int) (*apimodels.Revision, error) {
+	if r.fail != nil {
+		return nil, r.fail
+	}
+	revision, ok := r.revisions[number]
+	if !ok {
+		return nil, ierrors.New("revision %v not found", number).NotFound()
+	}
+	return revision, nil
+}
+
+// Rollback - simple mock
+func (r *Revisions) Rollback(number int) error {
+	_, err := r.Get(number)
+	return err
+}
+
+// Subscribe - simple mock, no revisions are ever sent
+func (r *Revisions) Subscribe() (int, <-chan apimodels.Revision, func()) {
+	watcher := make(chan apimodels.Revision)
+	return len(r.revisions), watcher, func() { close(watcher) }
+}
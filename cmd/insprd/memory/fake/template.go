@@ -0,0 +1,90 @@
+package fake
+
+import (
+	"fmt"
+	"sort"
+
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/meta/utils"
+)
+
+// Templates - mocks the implementation of the TemplateMemory interface methods
+type Templates struct {
+	*TreeMemoryMock
+	fail      error
+	templates map[string]*meta.Template
+}
+
+// Get - simple mock
+func (t *Templates) Get(scope, name string) (*meta.Template, error) {
+	if t.fail != nil {
+		return nil, t.fail
+	}
+	query := fmt.Sprintf("%s.%s", scope, name)
+	tmpl, ok := t.templates[query]
+	if !ok {
+		return nil, ierrors.New("template %s not found", query).NotFound()
+	}
+	return tmpl, nil
+}
+
+// Create - simple mock
+func (t *Templates) Create(scope string, tmpl *meta.Template) error {
+	if t.fail != nil {
+		return t.fail
+	}
+	query := fmt.Sprintf("%s.%s", scope, tmpl.Meta.Name)
+	_, ok := t.templates[query]
+	if ok {
+		return ierrors.New("template %s already exists", query).AlreadyExists()
+	}
+	t.templates[query] = tmpl
+	return nil
+}
+
+// Delete - simple mock
+func (t *Templates) Delete(scope, name string) error {
+	if t.fail != nil {
+		return t.fail
+	}
+	query := fmt.Sprintf("%s.%s", scope, name)
+	_, ok := t.templates[query]
+	if !ok {
+		return ierrors.New("template %s not found", query).NotFound()
+	}
+
+	delete(t.templates, query)
+	return nil
+}
+
+// Update - simple mock
+func (t *Templates) Update(scope string, tmpl *meta.Template) error {
+	if t.fail != nil {
+		return t.fail
+	}
+	query := fmt.Sprintf("%s.%s", scope, tmpl.Meta.Name)
+	_, ok := t.templates[query]
+	if !ok {
+		return ierrors.New("template %s not found", query).NotFound()
+	}
+	t.templates[query] = tmpl
+	return nil
+}
+
+// Instances - simple mock, returns the queries of the mocked dApps
+// annotated with the path of the template
+func (t *Templates) Instances(scope, name string) ([]string, error) {
+	if t.fail != nil {
+		return nil, t.fail
+	}
+	path, _ := utils.JoinScopes(scope, name)
+	instances := []string{}
+	for query, app := range t.app.apps {
+		if app.Meta.Annotations[utils.TemplateAnnotation] == path {
+			instances = append(instances, query)
+		}
+	}
+	sort.Strings(instances)
+	return instances, nil
+}
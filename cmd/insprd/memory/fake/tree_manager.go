@@ -2,6 +2,7 @@ package fake
 
 import (
 	"inspr.dev/inspr/cmd/insprd/memory/tree"
+	apimodels "inspr.dev/inspr/pkg/api/models"
 	"inspr.dev/inspr/pkg/meta/utils/diff"
 
 	"inspr.dev/inspr/pkg/meta"
@@ -14,6 +15,8 @@ type TreeMemoryMock struct {
 	channel   Channels
 	app       Apps
 	alias     Alias
+	templates Templates
+	revisions Revisions
 }
 
 // LookupMemManager mocks getter for roots
@@ -39,9 +42,14 @@ func (l LookupMemManager) Alias() tree.AliasGetInterface {
 	return &l.alias
 }
 
+// Templates mocks a Template getter
+func (l LookupMemManager) Templates() tree.TemplateGetInterface {
+	return &l.templates
+}
+
 // MockTreeMemory mock exported with propagated error through the functions
 func MockTreeMemory(failErr error) tree.Manager {
-	return &TreeMemoryMock{
+	mock := &TreeMemoryMock{
 		insprType: Types{
 			fail:       failErr,
 			insprTypes: make(map[string]*meta.Type),
@@ -58,7 +66,17 @@ func MockTreeMemory(failErr error) tree.Manager {
 			fail:  failErr,
 			alias: make(map[string]*meta.Alias),
 		},
+		templates: Templates{
+			fail:      failErr,
+			templates: make(map[string]*meta.Template),
+		},
+		revisions: Revisions{
+			fail:      failErr,
+			revisions: make(map[int]*apimodels.Revision),
+		},
 	}
+	mock.templates.TreeMemoryMock = mock
+	return mock
 }
 
 // Perm mocks a root getter interface
@@ -86,11 +104,21 @@ func (mm *TreeMemoryMock) Alias() tree.AliasMemory {
 	return &mm.alias
 }
 
+// Templates returns manager's Templates
+func (mm *TreeMemoryMock) Templates() tree.TemplateMemory {
+	return &mm.templates
+}
+
+// Revisions returns manager's Revisions
+func (mm *TreeMemoryMock) Revisions() tree.RevisionMemory {
+	return &mm.revisions
+}
+
 //InitTransaction mock interface structure
 func (mm *TreeMemoryMock) InitTransaction() {}
 
 //Commit mock interface structure
-func (mm *TreeMemoryMock) Commit() {}
+func (mm *TreeMemoryMock) Commit(author string) error { return nil }
 
 //Cancel mock interface structure
 func (mm *TreeMemoryMock) Cancel() {}
@@ -0,0 +1,293 @@
+package storage
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/logs"
+)
+
+var logger *zap.Logger
+
+// init is called after all the variable declarations in the package have evaluated
+// their initializers, and those are evaluated only after all the imported packages
+// have been initialized
+func init() {
+	logger, _ = logs.Logger(zap.Fields(zap.String("section", "memory-storage")))
+}
+
+const (
+	// tempPrefix is the prefix of the files used to stage writes
+	// before they are atomically moved into place
+	tempPrefix = ".tmp-"
+	// journalFile is the file in which a batch is recorded before being
+	// applied, so that a crash in the middle of it can be completed
+	journalFile = ".journal"
+)
+
+// fileStorage is a Storage that keeps each key in a file inside a directory.
+// Writes are staged on a temporary file, synced and then renamed over the
+// previous value, so a crash never leaves a partially written key behind.
+// Batches are recorded on a journal before being applied, and a journal left
+// by a crash is applied when the storage is opened again.
+type fileStorage struct {
+	dir string
+	sync.RWMutex
+}
+
+// NewFileStorage returns a Storage that persists its values on the given
+// directory, creating it if it doesn't exist
+func NewFileStorage(dir string) (Storage, error) {
+	l := logger.With(zap.String("operation", "new-file-storage"), zap.String("dir", dir))
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		l.Error("unable to create storage directory", zap.Error(err))
+		return nil, ierrors.Wrap(
+			ierrors.New(err).InternalServer(),
+			"unable to create storage directory",
+		)
+	}
+
+	fs := &fileStorage{dir: dir}
+	if err := fs.recover(); err != nil {
+		l.Error("unable to apply the journal of an interrupted batch", zap.Error(err))
+		return nil, err
+	}
+
+	l.Info("using file storage")
+	return fs, nil
+}
+
+// Get returns the value stored on the given key
+func (fs *fileStorage) Get(key string) ([]byte, error) {
+	fs.RLock()
+	defer fs.RUnlock()
+
+	value, err := ioutil.ReadFile(fs.path(key))
+	if os.IsNotExist(err) {
+		return nil, ierrors.New("key %v not found on storage", key).NotFound()
+	}
+	if err != nil {
+		return nil, ierrors.New(err).InternalServer()
+	}
+	return value, nil
+}
+
+// Set atomically stores the value on the given key
+func (fs *fileStorage) Set(key string, value []byte) error {
+	fs.Lock()
+	defer fs.Unlock()
+
+	if err := fs.writeFile(fs.path(key), value); err != nil {
+		logger.Error("unable to write key", zap.String("key", key), zap.Error(err))
+		return err
+	}
+	return fs.syncDir()
+}
+
+// Write records the batch on the journal and then applies it. If applying it
+// fails the previous values are restored, and if that fails as well the journal
+// is kept, to be applied when the storage is opened again.
+func (fs *fileStorage) Write(batch Batch) error {
+	l := logger.With(zap.String("operation", "write"))
+	fs.Lock()
+	defer fs.Unlock()
+
+	previous, err := fs.read(batch)
+	if err != nil {
+		l.Error("unable to read the values changed by the batch", zap.Error(err))
+		return err
+	}
+
+	data, err := json.Marshal(batch)
+	if err != nil {
+		return ierrors.New(err).InternalServer()
+	}
+	if err = fs.writeFile(fs.journal(), data); err == nil {
+		err = fs.syncDir()
+	}
+	if err != nil {
+		l.Error("unable to write the batch journal", zap.Error(err))
+		return err
+	}
+
+	if err = fs.apply(batch); err != nil {
+		l.Error("unable to apply batch, restoring the previous values", zap.Error(err))
+		if undoErr := fs.apply(previous); undoErr != nil {
+			l.Error("unable to restore the previous values, the batch will be applied on restart",
+				zap.Error(undoErr))
+			return err
+		}
+	}
+
+	if removeErr := os.Remove(fs.journal()); removeErr != nil {
+		l.Error("unable to remove the batch journal", zap.Error(removeErr))
+		if err == nil {
+			err = ierrors.New(removeErr).InternalServer()
+		}
+	}
+	if syncErr := fs.syncDir(); err == nil {
+		err = syncErr
+	}
+	return err
+}
+
+// Delete removes the given key from the storage
+func (fs *fileStorage) Delete(key string) error {
+	fs.Lock()
+	defer fs.Unlock()
+
+	err := os.Remove(fs.path(key))
+	if os.IsNotExist(err) {
+		return ierrors.New("key %v not found on storage", key).NotFound()
+	}
+	if err != nil {
+		return ierrors.New(err).InternalServer()
+	}
+	return fs.syncDir()
+}
+
+// Keys returns the sorted list of stored keys with the given prefix
+func (fs *fileStorage) Keys(prefix string) ([]string, error) {
+	fs.RLock()
+	defer fs.RUnlock()
+
+	files, err := ioutil.ReadDir(fs.dir)
+	if err != nil {
+		return nil, ierrors.New(err).InternalServer()
+	}
+
+	keys := []string{}
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), tempPrefix) || file.Name() == journalFile {
+			continue
+		}
+
+		key, err := url.PathUnescape(file.Name())
+		if err != nil || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// path returns the file in which the given key is stored, keys are
+// escaped so that they can contain slashes and other separators
+func (fs *fileStorage) path(key string) string {
+	return filepath.Join(fs.dir, url.PathEscape(key))
+}
+
+// journal returns the path of the batch journal
+func (fs *fileStorage) journal() string {
+	return filepath.Join(fs.dir, journalFile)
+}
+
+// writeFile stages the value on a temporary file and moves it into place
+func (fs *fileStorage) writeFile(path string, value []byte) error {
+	tmp, err := ioutil.TempFile(fs.dir, tempPrefix)
+	if err != nil {
+		return ierrors.New(err).InternalServer()
+	}
+	// removing fails harmlessly once the file is renamed
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(value); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return ierrors.New(err).InternalServer()
+	}
+	return nil
+}
+
+// read returns a batch that restores the current values of the keys changed
+// by the given batch
+func (fs *fileStorage) read(batch Batch) (Batch, error) {
+	previous := NewBatch()
+	keys := append([]string{}, batch.Delete...)
+	for key := range batch.Set {
+		keys = append(keys, key)
+	}
+
+	for _, key := range keys {
+		value, err := ioutil.ReadFile(fs.path(key))
+		if os.IsNotExist(err) {
+			previous.Delete = append(previous.Delete, key)
+			continue
+		}
+		if err != nil {
+			return Batch{}, ierrors.New(err).InternalServer()
+		}
+		previous.Set[key] = value
+	}
+	return previous, nil
+}
+
+// apply writes every change of the batch
+func (fs *fileStorage) apply(batch Batch) error {
+	for key, value := range batch.Set {
+		if err := fs.writeFile(fs.path(key), value); err != nil {
+			return err
+		}
+	}
+	for _, key := range batch.Delete {
+		if err := os.Remove(fs.path(key)); err != nil && !os.IsNotExist(err) {
+			return ierrors.New(err).InternalServer()
+		}
+	}
+	return fs.syncDir()
+}
+
+// recover applies the batch of the journal left by an interrupted write
+func (fs *fileStorage) recover() error {
+	data, err := ioutil.ReadFile(fs.journal())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return ierrors.New(err).InternalServer()
+	}
+
+	batch := Batch{}
+	if err = json.Unmarshal(data, &batch); err != nil {
+		// the journal is only renamed into place once fully written
+		return ierrors.Wrap(ierrors.New(err).InternalServer(), "corrupted batch journal")
+	}
+	logger.Info("applying the journal of an interrupted batch")
+	if err = fs.apply(batch); err != nil {
+		return err
+	}
+	if err = os.Remove(fs.journal()); err != nil {
+		return ierrors.New(err).InternalServer()
+	}
+	return fs.syncDir()
+}
+
+// syncDir flushes the directory entries, making renames and removals durable
+func (fs *fileStorage) syncDir() error {
+	dir, err := os.Open(fs.dir)
+	if err != nil {
+		return ierrors.New(err).InternalServer()
+	}
+	defer dir.Close()
+
+	if err = dir.Sync(); err != nil {
+		return ierrors.New(err).InternalServer()
+	}
+	return nil
+}
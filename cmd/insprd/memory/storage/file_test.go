@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"inspr.dev/inspr/pkg/ierrors"
+)
+
+func TestNewFileStorage(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		dir     string
+		wantErr bool
+	}{
+		{
+			name: "creates storage on existing directory",
+			dir:  dir,
+		},
+		{
+			name: "creates missing directories",
+			dir:  filepath.Join(dir, "nested", "storage"),
+		},
+		{
+			name:    "fails when path is a file",
+			dir:     filepath.Join(dir, "file"),
+			wantErr: true,
+		},
+	}
+	ioutil.WriteFile(filepath.Join(dir, "file"), []byte{}, 0600)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFileStorage(tt.dir)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewFileStorage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFileStorage(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStorage(dir)
+	if err != nil {
+		t.Fatalf("NewFileStorage() error = %v", err)
+	}
+
+	if _, err = store.Get("tree"); !ierrors.HasCode(err, ierrors.NotFound) {
+		t.Errorf("Get() on missing key error = %v, want NotFound", err)
+	}
+
+	values := map[string]string{
+		"tree":          "first",
+		"revisions/001": "rev1",
+		"revisions/002": "rev2",
+	}
+	for key, value := range values {
+		if err = store.Set(key, []byte(value)); err != nil {
+			t.Fatalf("Set(%v) error = %v", key, err)
+		}
+	}
+
+	if err = store.Set("tree", []byte("second")); err != nil {
+		t.Fatalf("Set() overwrite error = %v", err)
+	}
+
+	// a new storage on the same directory must see the same values
+	reopened, _ := NewFileStorage(dir)
+	got, err := reopened.Get("tree")
+	if err != nil || string(got) != "second" {
+		t.Errorf("Get() = %s, %v, want second", got, err)
+	}
+
+	keys, err := reopened.Keys("revisions/")
+	if err != nil || !reflect.DeepEqual(keys, []string{"revisions/001", "revisions/002"}) {
+		t.Errorf("Keys() = %v, %v", keys, err)
+	}
+
+	if err = reopened.Delete("revisions/001"); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+	if err = reopened.Delete("revisions/001"); !ierrors.HasCode(err, ierrors.NotFound) {
+		t.Errorf("Delete() on missing key error = %v, want NotFound", err)
+	}
+
+	files, _ := ioutil.ReadDir(dir)
+	for _, file := range files {
+		if strings.HasPrefix(file.Name(), tempPrefix) {
+			t.Errorf("temporary file %v left behind", file.Name())
+		}
+	}
+}
+
+func TestFileStorage_Get_unreadable(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewFileStorage(dir)
+	os.Mkdir(filepath.Join(dir, "dir"), 0700)
+
+	if _, err := store.Get("dir"); !ierrors.HasCode(err, ierrors.InternalServer) {
+		t.Errorf("Get() error = %v, want InternalServer", err)
+	}
+}
+
+func TestFileStorage_Write(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewFileStorage(dir)
+	store.Set("revisions/001", []byte("rev1"))
+
+	batch := NewBatch()
+	batch.Set["tree"] = []byte("tree")
+	batch.Set["revisions/002"] = []byte("rev2")
+	batch.Delete = append(batch.Delete, "revisions/001", "missing")
+	if err := store.Write(batch); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	keys, _ := store.Keys("")
+	if !reflect.DeepEqual(keys, []string{"revisions/002", "tree"}) {
+		t.Errorf("Write() keys = %v", keys)
+	}
+	if _, err := os.Stat(filepath.Join(dir, journalFile)); !os.IsNotExist(err) {
+		t.Errorf("Write() left the journal behind, error = %v", err)
+	}
+}
+
+func TestNewFileStorage_recover(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewFileStorage(dir)
+	store.Set("revisions/001", []byte("rev1"))
+
+	// a batch interrupted after its journal was written is applied on open
+	data, _ := json.Marshal(Batch{
+		Set:    map[string][]byte{"tree": []byte("tree")},
+		Delete: []string{"revisions/001"},
+	})
+	ioutil.WriteFile(filepath.Join(dir, journalFile), data, 0600)
+
+	reopened, err := NewFileStorage(dir)
+	if err != nil {
+		t.Fatalf("NewFileStorage() error = %v", err)
+	}
+	keys, _ := reopened.Keys("")
+	if !reflect.DeepEqual(keys, []string{"tree"}) {
+		t.Errorf("NewFileStorage() keys = %v, want the journal applied", keys)
+	}
+
+	ioutil.WriteFile(filepath.Join(dir, journalFile), []byte("{not json"), 0600)
+	if _, err = NewFileStorage(dir); err == nil {
+		t.Errorf("NewFileStorage() with a corrupted journal didn't fail")
+	}
+}
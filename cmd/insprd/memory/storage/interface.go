@@ -0,0 +1,31 @@
+package storage
+
+// Storage is the interface that allows insprd to persist its memory.
+// It works as a simple key value store, in which writing a single key
+// must be atomic: readers either see the previous value or the new one.
+type Storage interface {
+	// Get returns the value stored on the given key, or a NotFound
+	// error if the key doesn't exist
+	Get(key string) ([]byte, error)
+	// Set atomically stores the value on the given key
+	Set(key string, value []byte) error
+	// Delete removes the given key from the storage
+	Delete(key string) error
+	// Keys returns the sorted list of stored keys with the given prefix
+	Keys(prefix string) ([]string, error)
+	// Write atomically applies every change of the batch: readers, and
+	// insprd after a restart, either see none of them or all of them
+	Write(batch Batch) error
+}
+
+// Batch is a set of changes written at once by Storage.Write. Deleting a key
+// that doesn't exist is not an error.
+type Batch struct {
+	Set    map[string][]byte `json:"set,omitempty"`
+	Delete []string          `json:"delete,omitempty"`
+}
+
+// NewBatch returns an empty batch
+func NewBatch() Batch {
+	return Batch{Set: map[string][]byte{}, Delete: []string{}}
+}
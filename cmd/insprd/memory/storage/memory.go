@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"inspr.dev/inspr/pkg/ierrors"
+)
+
+// memoryStorage is a process-local Storage, the values stored
+// in it are lost when insprd restarts
+type memoryStorage struct {
+	values map[string][]byte
+	sync.RWMutex
+}
+
+// NewMemoryStorage returns a Storage that keeps its values in memory.
+// It is meant to be used in tests and when no durable storage is configured.
+func NewMemoryStorage() Storage {
+	return &memoryStorage{
+		values: map[string][]byte{},
+	}
+}
+
+// Get returns the value stored on the given key
+func (ms *memoryStorage) Get(key string) ([]byte, error) {
+	ms.RLock()
+	defer ms.RUnlock()
+
+	value, ok := ms.values[key]
+	if !ok {
+		return nil, ierrors.New("key %v not found on storage", key).NotFound()
+	}
+	return append([]byte(nil), value...), nil
+}
+
+// Set stores the value on the given key
+func (ms *memoryStorage) Set(key string, value []byte) error {
+	ms.Lock()
+	defer ms.Unlock()
+
+	ms.values[key] = append([]byte(nil), value...)
+	return nil
+}
+
+// Delete removes the given key from the storage
+func (ms *memoryStorage) Delete(key string) error {
+	ms.Lock()
+	defer ms.Unlock()
+
+	if _, ok := ms.values[key]; !ok {
+		return ierrors.New("key %v not found on storage", key).NotFound()
+	}
+	delete(ms.values, key)
+	return nil
+}
+
+// Keys returns the sorted list of stored keys with the given prefix
+func (ms *memoryStorage) Keys(prefix string) ([]string, error) {
+	ms.RLock()
+	defer ms.RUnlock()
+
+	keys := []string{}
+	for key := range ms.values {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Write applies the changes of the batch while holding the storage lock
+func (ms *memoryStorage) Write(batch Batch) error {
+	ms.Lock()
+	defer ms.Unlock()
+
+	for key, value := range batch.Set {
+		ms.values[key] = append([]byte(nil), value...)
+	}
+	for _, key := range batch.Delete {
+		delete(ms.values, key)
+	}
+	return nil
+}
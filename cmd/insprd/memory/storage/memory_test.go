@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+
+	"inspr.dev/inspr/pkg/ierrors"
+)
+
+func TestMemoryStorage(t *testing.T) {
+	store := NewMemoryStorage()
+
+	if _, err := store.Get("tree"); !ierrors.HasCode(err, ierrors.NotFound) {
+		t.Errorf("Get() on missing key error = %v, want NotFound", err)
+	}
+
+	value := []byte("value")
+	store.Set("tree", value)
+	store.Set("revisions/002", []byte("rev2"))
+	store.Set("revisions/001", []byte("rev1"))
+
+	// the stored value must not change with the given slice
+	value[0] = 'X'
+	got, err := store.Get("tree")
+	if err != nil || string(got) != "value" {
+		t.Errorf("Get() = %s, %v, want value", got, err)
+	}
+
+	keys, _ := store.Keys("revisions/")
+	if !reflect.DeepEqual(keys, []string{"revisions/001", "revisions/002"}) {
+		t.Errorf("Keys() = %v", keys)
+	}
+
+	if err = store.Delete("tree"); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+	if err = store.Delete("tree"); !ierrors.HasCode(err, ierrors.NotFound) {
+		t.Errorf("Delete() on missing key error = %v, want NotFound", err)
+	}
+}
+
+func TestMemoryStorage_Write(t *testing.T) {
+	store := NewMemoryStorage()
+	store.Set("revisions/001", []byte("rev1"))
+
+	batch := NewBatch()
+	batch.Set["tree"] = []byte("tree")
+	batch.Set["revisions/002"] = []byte("rev2")
+	batch.Delete = append(batch.Delete, "revisions/001", "missing")
+	if err := store.Write(batch); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	keys, _ := store.Keys("")
+	if !reflect.DeepEqual(keys, []string{"revisions/002", "tree"}) {
+		t.Errorf("Write() keys = %v", keys)
+	}
+}
@@ -46,7 +46,7 @@ func (amm *AliasMemoryManager) Get(scope, name string) (*meta.Alias, error) {
 		).NotFound()
 	}
 
-	return app.Spec.Aliases[name], nil
+	return amm.writableAlias(app, name), nil
 }
 
 // Create receives a scope that defines a path to the dapp and an Alias
@@ -112,6 +112,12 @@ func (amm *AliasMemoryManager) Update(scope string, alias *meta.Alias) error {
 		return ierrors.New("alias was not found in dApp").NotFound()
 	}
 
+	err = utils.ValidateResourceVersion(selectedAlias.Meta, alias.Meta.ResourceVersion)
+	if err != nil {
+		l.Debug("outdated Alias resource version", zap.Int("version", selectedAlias.Meta.ResourceVersion))
+		return err
+	}
+
 	err = amm.CheckSource(scope, app, alias)
 	if err != nil {
 		return err
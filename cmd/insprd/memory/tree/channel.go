@@ -1,6 +1,9 @@
 package tree
 
 import (
+	"strings"
+	"time"
+
 	"go.uber.org/zap"
 	apimodels "inspr.dev/inspr/pkg/api/models"
 	"inspr.dev/inspr/pkg/ierrors"
@@ -46,9 +49,9 @@ func (chh *ChannelMemoryManager) Get(scope, name string) (*meta.Channel, error)
 	}
 
 	if parentApp.Spec.Channels != nil {
-		if ch, ok := parentApp.Spec.Channels[name]; ok {
+		if _, ok := parentApp.Spec.Channels[name]; ok {
 			l.Debug("channel found")
-			return ch, nil
+			return chh.writableChannel(parentApp, name), nil
 		}
 	}
 
@@ -104,10 +107,34 @@ func (chh *ChannelMemoryManager) Create(scope string, ch *meta.Channel, brokers
 		).InvalidChannel()
 	}
 
-	connectedChannels := parentApp.Spec.Types[ch.Spec.Type].ConnectedChannels
-	if !utils.Includes(connectedChannels, ch.Meta.Name) {
-		connectedChannels = append(connectedChannels, ch.Meta.Name)
-		parentApp.Spec.Types[ch.Spec.Type].ConnectedChannels = connectedChannels
+	if _, _, err = metautils.TypeSchema(parentApp.Spec.Types[ch.Spec.Type], ch.Spec.TypeVersion); err != nil {
+		l.Debug("channel's type version is invalid")
+		return ierrors.New(err).InvalidChannel()
+	}
+
+	if err = validDeadLetter(parentApp, ch); err != nil {
+		l.Debug("channel's dead-letter is invalid")
+		return err
+	}
+
+	if _, err = metautils.DeliveryPolicyOf(ch); err != nil {
+		l.Debug("channel's delivery policy is invalid")
+		return err
+	}
+
+	if err = validConcurrency(ch); err != nil {
+		l.Debug("channel's concurrency is invalid")
+		return err
+	}
+
+	if err = validBatch(ch); err != nil {
+		l.Debug("channel's batch is invalid")
+		return err
+	}
+
+	insprType := chh.writableType(parentApp, ch.Spec.Type)
+	if !utils.Includes(insprType.ConnectedChannels, ch.Meta.Name) {
+		insprType.ConnectedChannels = append(insprType.ConnectedChannels, ch.Meta.Name)
 	}
 
 	l.Debug("channel broker priority list", zap.Any("list", ch.Spec.BrokerPriorityList))
@@ -164,7 +191,15 @@ func (chh *ChannelMemoryManager) Delete(scope, name string) error {
 		).BadRequest()
 	}
 
-	insprType := parentApp.Spec.Types[channel.Spec.Type]
+	if deadLetterOf := deadLetterUsers(parentApp, name); len(deadLetterOf) > 0 {
+		l.Debug("unable to delete Channel for it's the dead-letter of other channels")
+		return ierrors.New(
+			"channel cannot be deleted as it is the dead-letter of the channels %v",
+			deadLetterOf,
+		).BadRequest()
+	}
+
+	insprType := chh.writableType(parentApp, channel.Spec.Type)
 
 	l.Debug("removing Channel from Type connected channels list",
 		zap.String("type", insprType.Meta.Name))
@@ -202,6 +237,12 @@ func (chh *ChannelMemoryManager) Update(scope string, ch *meta.Channel) error {
 		return newError
 	}
 
+	err = metautils.ValidateResourceVersion(oldCh.Meta, ch.Meta.ResourceVersion)
+	if err != nil {
+		l.Debug("outdated Channel resource version", zap.Int("version", oldCh.Meta.ResourceVersion))
+		return err
+	}
+
 	ch.ConnectedApps = oldCh.ConnectedApps
 	ch.Meta.UUID = oldCh.Meta.UUID
 
@@ -220,13 +261,161 @@ func (chh *ChannelMemoryManager) Update(scope string, ch *meta.Channel) error {
 		).InvalidChannel()
 	}
 
+	if _, _, err = metautils.TypeSchema(parentApp.Spec.Types[ch.Spec.Type], ch.Spec.TypeVersion); err != nil {
+		l.Debug("unable to update Channel for it references an invalid Type version",
+			zap.Int("version", ch.Spec.TypeVersion))
+		return ierrors.New(err).InvalidChannel()
+	}
+
+	if _, err = metautils.DeliveryPolicyOf(ch); err != nil {
+		l.Debug("unable to update Channel for its delivery policy is invalid")
+		return err
+	}
+
+	if err = validConcurrency(ch); err != nil {
+		l.Debug("unable to update Channel for its concurrency is invalid")
+		return err
+	}
+
+	if err = validBatch(ch); err != nil {
+		l.Debug("unable to update Channel for its batch is invalid")
+		return err
+	}
+
 	l.Debug("replacing old Channel with the new one in dApps 'Channels'")
 
 	parentApp.Spec.Channels[ch.Meta.Name] = ch
 
+	// the channel may be the dead-letter of other channels, which are checked
+	// against its new definition as well
+	if err = validDeadLetters(parentApp); err != nil {
+		l.Debug("unable to update Channel for it breaks a dead-letter")
+		parentApp.Spec.Channels[ch.Meta.Name] = oldCh
+		return err
+	}
+
+	return nil
+}
+
+// validDeadLetters checks the dead-letter configuration of every channel of the dApp
+func validDeadLetters(app *meta.App) error {
+	for _, ch := range app.Spec.Channels {
+		if err := validDeadLetter(app, ch); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// validDeadLetter checks that the dead-letter of a channel is another
+// channel of the same dApp, whose Type is in the jsonschema format so
+// that the dead-letter messages can be written to it
+func validDeadLetter(app *meta.App, ch *meta.Channel) error {
+	deadLetter := ch.Spec.DeadLetter
+	if deadLetter == nil {
+		return nil
+	}
+
+	if deadLetter.MaxAttempts < 0 {
+		return ierrors.New(
+			"dead-letter of channel '%v' has negative max attempts", ch.Meta.Name,
+		).InvalidChannel()
+	}
+
+	if deadLetter.Channel == "" || deadLetter.Channel == ch.Meta.Name {
+		return ierrors.New(
+			"dead-letter of channel '%v' must be another channel", ch.Meta.Name,
+		).InvalidChannel()
+	}
+
+	dlq, ok := app.Spec.Channels[deadLetter.Channel]
+	if !ok {
+		return ierrors.New(
+			"dead-letter of channel '%v' references a channel that doesn't exist in the dApp: '%v'",
+			ch.Meta.Name, deadLetter.Channel,
+		).InvalidChannel()
+	}
+
+	dlqType, ok := app.Spec.Types[dlq.Spec.Type]
+	if !ok || metautils.TypeFormat(dlqType) != meta.FormatJSONSchema {
+		return ierrors.New(
+			"dead-letter channel '%v' must have a Type in the %v format",
+			deadLetter.Channel, meta.FormatJSONSchema,
+		).InvalidChannel()
+	}
+	return nil
+}
+
+// validConcurrency checks that a channel doesn't have negative messages in
+// flight and that its ordering key is a path of fields, such as "user.id"
+func validConcurrency(ch *meta.Channel) error {
+	concurrency := ch.Spec.Concurrency
+	if concurrency == nil {
+		return nil
+	}
+
+	if concurrency.MaxInFlight < 0 {
+		return ierrors.New(
+			"concurrency of channel '%v' has negative max in flight messages", ch.Meta.Name,
+		).InvalidChannel()
+	}
+
+	if concurrency.OrderingKey == "" {
+		return nil
+	}
+	for _, field := range strings.Split(concurrency.OrderingKey, ".") {
+		if field == "" {
+			return ierrors.New(
+				"ordering key of channel '%v' is an invalid path of fields: '%v'",
+				ch.Meta.Name, concurrency.OrderingKey,
+			).InvalidChannel()
+		}
+	}
+	return nil
+}
+
+// validBatch checks that a channel doesn't have a negative batch size or
+// linger, and that its messages aren't both batched and handled concurrently
+func validBatch(ch *meta.Channel) error {
+	batch := ch.Spec.Batch
+	if batch == nil {
+		return nil
+	}
+
+	if batch.MaxSize < 0 {
+		return ierrors.New(
+			"batch of channel '%v' has a negative max size", ch.Meta.Name,
+		).InvalidChannel()
+	}
+
+	if batch.Linger != "" {
+		if linger, err := time.ParseDuration(batch.Linger); err != nil || linger < 0 {
+			return ierrors.New(
+				"batch of channel '%v' has an invalid linger '%v', must be a positive duration such as \"250ms\"",
+				ch.Meta.Name, batch.Linger,
+			).InvalidChannel()
+		}
+	}
+
+	if batch.MaxSize > 1 && ch.Spec.Concurrency != nil && ch.Spec.Concurrency.MaxInFlight > 1 {
+		return ierrors.New(
+			"channel '%v' can't have both batches and concurrency", ch.Meta.Name,
+		).InvalidChannel()
+	}
+	return nil
+}
+
+// deadLetterUsers returns the channels of the dApp that have the given channel as their dead-letter
+func deadLetterUsers(app *meta.App, chName string) utils.StringArray {
+	users := utils.StringArray{}
+	for name, ch := range app.Spec.Channels {
+		if ch.Spec.DeadLetter != nil && ch.Spec.DeadLetter.Channel == chName {
+			users = append(users, name)
+		}
+	}
+	return users.Sorted()
+}
+
 func (cmm *ChannelMemoryManager) isChannelUsed(app *meta.App, chName string) bool {
 	for _, child := range app.Spec.Apps {
 		if child.Spec.Boundary.Channels.Input.Union(child.Spec.Boundary.Channels.Output).Contains(chName) {
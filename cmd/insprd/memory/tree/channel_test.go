@@ -754,3 +754,308 @@ func getMockChannels() *meta.App {
 	}
 	return &root
 }
+
+func TestChannelMemoryManager_CreateTypeVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int
+		wantErr bool
+	}{
+		{name: "latest version", version: 0},
+		{name: "pinned version", version: 1},
+		{name: "unknown version", version: 3, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmm := newTreeMemory()
+			tmm.tree.Spec.Types["user"] = &meta.Type{
+				Meta:   meta.Metadata{Name: "user"},
+				Schema: `"long"`,
+				Versions: []meta.TypeVersion{
+					{Version: 1, Schema: `"int"`},
+					{Version: 2, Schema: `"long"`},
+				},
+			}
+			tmm.InitTransaction()
+			defer tmm.Cancel()
+
+			err := tmm.Channels().Create("", &meta.Channel{
+				Meta: meta.Metadata{Name: "users"},
+				Spec: meta.ChannelSpec{Type: "user", TypeVersion: tt.version},
+			}, &apimodels.BrokersDI{Available: []string{"some_broker"}, Default: "some_broker"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ChannelMemoryManager.Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !ierrors.HasCode(err, ierrors.InvalidChannel) {
+				t.Errorf("ChannelMemoryManager.Create() error = %v, want an invalid channel", err)
+			}
+		})
+	}
+}
+
+func TestChannelMemoryManager_DeadLetter(t *testing.T) {
+	brokers := &apimodels.BrokersDI{Available: []string{"some_broker"}, Default: "some_broker"}
+	newTree := func() *treeMemoryManager {
+		tmm := newTreeMemory()
+		tmm.tree.Spec.Types["user"] = &meta.Type{
+			Meta:   meta.Metadata{Name: "user"},
+			Schema: `"string"`,
+		}
+		tmm.tree.Spec.Types["failure"] = &meta.Type{
+			Meta:   meta.Metadata{Name: "failure"},
+			Format: meta.FormatJSONSchema,
+			Schema: `{"type":"object"}`,
+		}
+		tmm.tree.Spec.Channels["users"] = &meta.Channel{
+			Meta: meta.Metadata{Name: "users"},
+			Spec: meta.ChannelSpec{Type: "user"},
+		}
+		tmm.tree.Spec.Channels["failures"] = &meta.Channel{
+			Meta: meta.Metadata{Name: "failures"},
+			Spec: meta.ChannelSpec{Type: "failure"},
+		}
+		return tmm
+	}
+
+	createTests := []struct {
+		name       string
+		deadLetter *meta.DeadLetter
+		wantErr    bool
+	}{
+		{
+			name:       "valid dead-letter",
+			deadLetter: &meta.DeadLetter{Channel: "failures", MaxAttempts: 5},
+		},
+		{
+			name:       "dead-letter that doesn't exist",
+			deadLetter: &meta.DeadLetter{Channel: "missing"},
+			wantErr:    true,
+		},
+		{
+			name:       "dead-letter isn't jsonschema",
+			deadLetter: &meta.DeadLetter{Channel: "users"},
+			wantErr:    true,
+		},
+		{
+			name:       "dead-letter is the channel itself",
+			deadLetter: &meta.DeadLetter{Channel: "orders"},
+			wantErr:    true,
+		},
+		{
+			name:       "negative attempts",
+			deadLetter: &meta.DeadLetter{Channel: "failures", MaxAttempts: -1},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range createTests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmm := newTree()
+			tmm.InitTransaction()
+			defer tmm.Cancel()
+
+			err := tmm.Channels().Create("", &meta.Channel{
+				Meta: meta.Metadata{Name: "orders"},
+				Spec: meta.ChannelSpec{Type: "user", DeadLetter: tt.deadLetter},
+			}, brokers)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ChannelMemoryManager.Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !ierrors.HasCode(err, ierrors.InvalidChannel) {
+				t.Errorf("ChannelMemoryManager.Create() error = %v, want an invalid channel", err)
+			}
+		})
+	}
+
+	t.Run("dead-letter can't be deleted or broken", func(t *testing.T) {
+		tmm := newTree()
+		tmm.tree.Spec.Channels["users"].Spec.DeadLetter = &meta.DeadLetter{Channel: "failures"}
+		tmm.InitTransaction()
+		defer tmm.Cancel()
+
+		if err := tmm.Channels().Delete("", "failures"); !ierrors.HasCode(err, ierrors.BadRequest) {
+			t.Errorf("ChannelMemoryManager.Delete() error = %v, want BadRequest", err)
+		}
+
+		err := tmm.Channels().Update("", &meta.Channel{
+			Meta: meta.Metadata{Name: "failures"},
+			Spec: meta.ChannelSpec{Type: "user"},
+		})
+		if !ierrors.HasCode(err, ierrors.InvalidChannel) {
+			t.Errorf("ChannelMemoryManager.Update() error = %v, want an invalid channel", err)
+		}
+
+		ch, _ := tmm.Channels().Get("", "failures")
+		if ch.Spec.Type != "failure" {
+			t.Errorf("ChannelMemoryManager.Update() changed the dead-letter type to %v", ch.Spec.Type)
+		}
+	})
+}
+
+func TestChannelMemoryManager_DeliveryPolicy(t *testing.T) {
+	brokers := &apimodels.BrokersDI{Available: []string{"some_broker"}, Default: "some_broker"}
+	tests := []struct {
+		name        string
+		policy      *meta.DeliveryPolicy
+		annotations map[string]string
+		wantErr     bool
+	}{
+		{
+			name: "valid delivery policy",
+			policy: &meta.DeliveryPolicy{
+				MaxAttempts:    5,
+				InitialBackoff: "200ms",
+				MaxBackoff:     "5s",
+				Timeout:        "1s",
+			},
+		},
+		{
+			name:        "valid delivery policy annotations",
+			annotations: map[string]string{metautils.DeliveryMaxAttemptsAnnotation: "4"},
+		},
+		{
+			name:    "invalid backoff",
+			policy:  &meta.DeliveryPolicy{InitialBackoff: "-1s"},
+			wantErr: true,
+		},
+		{
+			name:        "invalid max attempts annotation",
+			annotations: map[string]string{metautils.DeliveryMaxAttemptsAnnotation: "-2"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmm := newTreeMemory()
+			tmm.tree.Spec.Types["user"] = &meta.Type{
+				Meta:   meta.Metadata{Name: "user"},
+				Schema: `"string"`,
+			}
+			tmm.InitTransaction()
+			defer tmm.Cancel()
+
+			err := tmm.Channels().Create("", &meta.Channel{
+				Meta: meta.Metadata{Name: "orders", Annotations: tt.annotations},
+				Spec: meta.ChannelSpec{Type: "user", DeliveryPolicy: tt.policy},
+			}, brokers)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ChannelMemoryManager.Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !ierrors.HasCode(err, ierrors.InvalidChannel) {
+				t.Errorf("ChannelMemoryManager.Create() error = %v, want an invalid channel", err)
+			}
+			if err != nil {
+				return
+			}
+
+			err = tmm.Channels().Update("", &meta.Channel{
+				Meta: meta.Metadata{Name: "orders"},
+				Spec: meta.ChannelSpec{
+					Type:           "user",
+					DeliveryPolicy: &meta.DeliveryPolicy{MaxBackoff: "1ms"},
+				},
+			})
+			if !ierrors.HasCode(err, ierrors.InvalidChannel) {
+				t.Errorf("ChannelMemoryManager.Update() error = %v, want an invalid channel", err)
+			}
+		})
+	}
+}
+
+func TestChannelMemoryManager_Concurrency(t *testing.T) {
+	brokers := &apimodels.BrokersDI{Available: []string{"some_broker"}, Default: "some_broker"}
+	tests := []struct {
+		name        string
+		concurrency *meta.Concurrency
+		wantErr     bool
+	}{
+		{
+			name:        "valid concurrency",
+			concurrency: &meta.Concurrency{MaxInFlight: 8, OrderingKey: "user.id"},
+		},
+		{
+			name:        "negative max in flight",
+			concurrency: &meta.Concurrency{MaxInFlight: -1},
+			wantErr:     true,
+		},
+		{
+			name:        "invalid ordering key",
+			concurrency: &meta.Concurrency{MaxInFlight: 8, OrderingKey: "user..id"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmm := newTreeMemory()
+			tmm.tree.Spec.Types["user"] = &meta.Type{
+				Meta:   meta.Metadata{Name: "user"},
+				Schema: `"string"`,
+			}
+			tmm.InitTransaction()
+			defer tmm.Cancel()
+
+			err := tmm.Channels().Create("", &meta.Channel{
+				Meta: meta.Metadata{Name: "orders"},
+				Spec: meta.ChannelSpec{Type: "user", Concurrency: tt.concurrency},
+			}, brokers)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ChannelMemoryManager.Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !ierrors.HasCode(err, ierrors.InvalidChannel) {
+				t.Errorf("ChannelMemoryManager.Create() error = %v, want an invalid channel", err)
+			}
+		})
+	}
+}
+
+func TestChannelMemoryManager_Batch(t *testing.T) {
+	brokers := &apimodels.BrokersDI{Available: []string{"some_broker"}, Default: "some_broker"}
+	tests := []struct {
+		name        string
+		batch       *meta.Batch
+		concurrency *meta.Concurrency
+		wantErr     bool
+	}{
+		{
+			name:  "valid batch",
+			batch: &meta.Batch{MaxSize: 100, Linger: "250ms"},
+		},
+		{
+			name:    "negative max size",
+			batch:   &meta.Batch{MaxSize: -1},
+			wantErr: true,
+		},
+		{
+			name:    "invalid linger",
+			batch:   &meta.Batch{MaxSize: 100, Linger: "a while"},
+			wantErr: true,
+		},
+		{
+			name:        "batch with concurrency",
+			batch:       &meta.Batch{MaxSize: 100},
+			concurrency: &meta.Concurrency{MaxInFlight: 4},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmm := newTreeMemory()
+			tmm.tree.Spec.Types["user"] = &meta.Type{
+				Meta:   meta.Metadata{Name: "user"},
+				Schema: `"string"`,
+			}
+			tmm.InitTransaction()
+			defer tmm.Cancel()
+
+			err := tmm.Channels().Create("", &meta.Channel{
+				Meta: meta.Metadata{Name: "orders"},
+				Spec: meta.ChannelSpec{Type: "user", Batch: tt.batch, Concurrency: tt.concurrency},
+			}, brokers)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ChannelMemoryManager.Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !ierrors.HasCode(err, ierrors.InvalidChannel) {
+				t.Errorf("ChannelMemoryManager.Create() error = %v, want an invalid channel", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package tree
+
+import (
+	"inspr.dev/inspr/pkg/meta"
+)
+
+// Transactions don't copy the whole tree when they start. The transaction root
+// shares every component with the committed tree, and a component is only
+// copied when it is first retrieved for changes, along with the dApps in its
+// path. Copies are recorded as owned by the transaction, so that they are
+// changed in place from then on. The committed tree is never changed, which
+// lets it be read without waiting for the running transaction.
+
+// own records the given component as a copy that belongs to the current transaction
+func (tmm *treeMemoryManager) own(component interface{}) {
+	if tmm.owned == nil {
+		tmm.owned = map[interface{}]struct{}{}
+	}
+	tmm.owned[component] = struct{}{}
+}
+
+// isOwned returns whether the given component belongs to the current transaction
+func (tmm *treeMemoryManager) isOwned(component interface{}) bool {
+	_, ok := tmm.owned[component]
+	return ok
+}
+
+// writableApp returns the child dApp with the given name of a dApp owned by
+// the transaction, copying it into its parent if it's shared with the
+// committed tree. It returns nil if there is no such child.
+func (tmm *treeMemoryManager) writableApp(parent *meta.App, name string) *meta.App {
+	app := parent.Spec.Apps[name]
+	if app == nil || tmm.isOwned(app) {
+		return app
+	}
+	app = copyApp(app)
+	tmm.own(app)
+	parent.Spec.Apps[name] = app
+	return app
+}
+
+// writableChannel is the channel counterpart of writableApp
+func (tmm *treeMemoryManager) writableChannel(parent *meta.App, name string) *meta.Channel {
+	ch := parent.Spec.Channels[name]
+	if ch == nil || tmm.isOwned(ch) {
+		return ch
+	}
+	copied := *ch
+	ch = &copied
+	tmm.own(ch)
+	parent.Spec.Channels[name] = ch
+	return ch
+}
+
+// writableType is the type counterpart of writableApp
+func (tmm *treeMemoryManager) writableType(parent *meta.App, name string) *meta.Type {
+	t := parent.Spec.Types[name]
+	if t == nil || tmm.isOwned(t) {
+		return t
+	}
+	copied := *t
+	t = &copied
+	tmm.own(t)
+	parent.Spec.Types[name] = t
+	return t
+}
+
+// writableAlias is the alias counterpart of writableApp
+func (tmm *treeMemoryManager) writableAlias(parent *meta.App, name string) *meta.Alias {
+	alias := parent.Spec.Aliases[name]
+	if alias == nil || tmm.isOwned(alias) {
+		return alias
+	}
+	copied := *alias
+	alias = &copied
+	tmm.own(alias)
+	parent.Spec.Aliases[name] = alias
+	return alias
+}
+
+// writableTemplate is the template counterpart of writableApp
+func (tmm *treeMemoryManager) writableTemplate(parent *meta.App, name string) *meta.Template {
+	t := parent.Spec.Templates[name]
+	if t == nil || tmm.isOwned(t) {
+		return t
+	}
+	copied := *t
+	t = &copied
+	tmm.own(t)
+	parent.Spec.Templates[name] = t
+	return t
+}
+
+// copyApp returns a shallow copy of the given dApp, in which the maps of
+// components are copied as well, so that adding or removing components
+// from the copy doesn't change the original dApp
+func copyApp(app *meta.App) *meta.App {
+	copied := *app
+	if app.Spec.Apps != nil {
+		copied.Spec.Apps = make(map[string]*meta.App, len(app.Spec.Apps))
+		for name, child := range app.Spec.Apps {
+			copied.Spec.Apps[name] = child
+		}
+	}
+	if app.Spec.Channels != nil {
+		copied.Spec.Channels = make(map[string]*meta.Channel, len(app.Spec.Channels))
+		for name, ch := range app.Spec.Channels {
+			copied.Spec.Channels[name] = ch
+		}
+	}
+	if app.Spec.Types != nil {
+		copied.Spec.Types = make(map[string]*meta.Type, len(app.Spec.Types))
+		for name, t := range app.Spec.Types {
+			copied.Spec.Types[name] = t
+		}
+	}
+	if app.Spec.Aliases != nil {
+		copied.Spec.Aliases = make(map[string]*meta.Alias, len(app.Spec.Aliases))
+		for name, alias := range app.Spec.Aliases {
+			copied.Spec.Aliases[name] = alias
+		}
+	}
+	if app.Spec.Templates != nil {
+		copied.Spec.Templates = make(map[string]*meta.Template, len(app.Spec.Templates))
+		for name, t := range app.Spec.Templates {
+			copied.Spec.Templates[name] = t
+		}
+	}
+	return &copied
+}
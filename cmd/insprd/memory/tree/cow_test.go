@@ -0,0 +1,199 @@
+package tree
+
+import (
+	"fmt"
+	"testing"
+
+	apimodels "inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/meta/utils"
+)
+
+// syntheticTree returns a tree with the given amount of dApps under the root,
+// each one with a child dApp holding a type and the given amount of channels
+func syntheticTree(apps, channels int) *meta.App {
+	root := newTreeMemory().tree
+	for i := 0; i < apps; i++ {
+		name := fmt.Sprintf("app%d", i)
+		child := &meta.App{
+			Meta: meta.Metadata{Name: "node", Parent: name, Annotations: map[string]string{}},
+			Spec: meta.AppSpec{
+				Channels: map[string]*meta.Channel{},
+				Types: map[string]*meta.Type{
+					"t1": {Meta: meta.Metadata{Name: "t1"}, Schema: `{"type":"string"}`},
+				},
+			},
+		}
+		for j := 0; j < channels; j++ {
+			chName := fmt.Sprintf("ch%d", j)
+			child.Spec.Channels[chName] = &meta.Channel{
+				Meta: meta.Metadata{Name: chName, Parent: name + ".node"},
+				Spec: meta.ChannelSpec{Type: "t1", SelectedBroker: "kafka"},
+			}
+			child.Spec.Types["t1"].ConnectedChannels = append(child.Spec.Types["t1"].ConnectedChannels, chName)
+		}
+		root.Spec.Apps[name] = &meta.App{
+			Meta: meta.Metadata{Name: name, Annotations: map[string]string{}},
+			Spec: meta.AppSpec{Apps: map[string]*meta.App{"node": child}},
+		}
+	}
+	return root
+}
+
+func TestTreeMemoryManager_copyOnWrite(t *testing.T) {
+	tmm := newTreeMemory()
+	tmm.tree = syntheticTree(3, 2)
+	committed := tmm.tree
+	committedChild := committed.Spec.Apps["app1"].Spec.Apps["node"]
+
+	tmm.InitTransaction()
+	ch, err := tmm.Channels().Get("app1.node", "ch0")
+	if err != nil {
+		t.Fatalf("ChannelMemoryManager.Get() error = %v", err)
+	}
+	ch.Meta.Annotations = map[string]string{"k": "v"}
+	if err = tmm.Apps().Delete("app2"); err != nil {
+		t.Fatalf("AppMemoryManager.Delete() error = %v", err)
+	}
+
+	if committedChild.Spec.Channels["ch0"].Meta.Annotations != nil {
+		t.Errorf("transaction changed a channel of the committed tree")
+	}
+	if _, ok := committed.Spec.Apps["app2"]; !ok {
+		t.Errorf("transaction deleted a dApp of the committed tree")
+	}
+	if perm, _ := tmm.Perm().Apps().Get("app1.node"); perm != committedChild {
+		t.Errorf("Perm() didn't return the committed tree during the transaction")
+	}
+	if tmm.root.Spec.Apps["app0"] != committed.Spec.Apps["app0"] {
+		t.Errorf("transaction copied a dApp it didn't retrieve")
+	}
+
+	tmm.Cancel()
+	if tmm.tree != committed || len(tmm.tree.Spec.Apps) != 3 {
+		t.Errorf("Cancel() changed the committed tree")
+	}
+
+	tmm.InitTransaction()
+	ch, _ = tmm.Channels().Get("app1.node", "ch0")
+	ch.Meta.Annotations = map[string]string{"k": "v"}
+	if err = tmm.Commit("user1"); err != nil {
+		t.Fatalf("treeMemoryManager.Commit() error = %v", err)
+	}
+
+	got, _ := tmm.Perm().Channels().Get("app1.node", "ch0")
+	if got.Meta.Annotations["k"] != "v" {
+		t.Errorf("Commit() didn't apply the channel changes")
+	}
+	if committedChild.Spec.Channels["ch0"].Meta.Annotations != nil {
+		t.Errorf("Commit() changed the previously committed tree")
+	}
+	if tmm.tree.Spec.Apps["app0"] != committed.Spec.Apps["app0"] {
+		t.Errorf("Commit() didn't share the unchanged dApps with the previous tree")
+	}
+}
+
+func TestChannelMemoryManager_Create_copiesType(t *testing.T) {
+	tmm := newTreeMemory()
+	tmm.tree = syntheticTree(1, 1)
+	committedType := tmm.tree.Spec.Apps["app0"].Spec.Apps["node"].Spec.Types["t1"]
+
+	tmm.InitTransaction()
+	defer tmm.Cancel()
+	err := tmm.Channels().Create("app0.node", &meta.Channel{
+		Meta: meta.Metadata{Name: "ch9"},
+		Spec: meta.ChannelSpec{Type: "t1"},
+	}, &apimodels.BrokersDI{Available: []string{"kafka"}, Default: "kafka"})
+	if err != nil {
+		t.Fatalf("ChannelMemoryManager.Create() error = %v", err)
+	}
+
+	if len(committedType.ConnectedChannels) != 1 {
+		t.Errorf("Create() connected the channel to the committed type")
+	}
+	insprType, _ := tmm.Types().Get("app0.node", "t1")
+	if len(insprType.ConnectedChannels) != 2 {
+		t.Errorf("Create() didn't connect the channel to the transaction type")
+	}
+}
+
+var benchmarkSizes = []int{100, 1000, 5000}
+
+// BenchmarkTransaction_deepCopy measures transactions as they were done before
+// the copy-on-write tree, with the whole tree copied when they start
+func BenchmarkTransaction_deepCopy(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("apps=%d", size), func(b *testing.B) {
+			tree := syntheticTree(size, 10)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				var root *meta.App
+				utils.DeepCopy(tree, &root)
+				ch := root.Spec.Apps["app1"].Spec.Apps["node"].Spec.Channels["ch0"]
+				ch.Meta.Annotations = map[string]string{"k": "v"}
+			}
+		})
+	}
+}
+
+// BenchmarkTransaction_copyOnWrite measures the same transactions with the
+// copy-on-write tree, in which only the changed path is copied
+func BenchmarkTransaction_copyOnWrite(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("apps=%d", size), func(b *testing.B) {
+			tmm := newTreeMemory()
+			tmm.tree = syntheticTree(size, 10)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tmm.InitTransaction()
+				ch, _ := tmm.Channels().Get("app1.node", "ch0")
+				ch.Meta.Annotations = map[string]string{"k": "v"}
+				tmm.Cancel()
+			}
+		})
+	}
+}
+
+// BenchmarkCommit measures committing a transaction that changes a single
+// channel, both with the default storage, which persists the revision of
+// every commit, and without a storage, which measures the tree alone
+func BenchmarkCommit(b *testing.B) {
+	for _, persisted := range []bool{true, false} {
+		for _, size := range benchmarkSizes {
+			name := fmt.Sprintf("storage=default/apps=%d", size)
+			if !persisted {
+				name = fmt.Sprintf("storage=none/apps=%d", size)
+			}
+			b.Run(name, func(b *testing.B) {
+				tmm := newTreeMemory()
+				if !persisted {
+					tmm.storage = nil
+				}
+				tmm.tree = syntheticTree(size, 10)
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					tmm.InitTransaction()
+					ch, _ := tmm.Channels().Get("app1.node", "ch0")
+					ch.Meta.Annotations = map[string]string{"k": fmt.Sprint(i)}
+					tmm.Commit("user1")
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkPermGet measures reading the committed tree while a transaction
+// holds the tree lock, which used to block readers
+func BenchmarkPermGet(b *testing.B) {
+	tmm := newTreeMemory()
+	tmm.tree = syntheticTree(1000, 10)
+	tmm.InitTransaction()
+	defer tmm.Cancel()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			tmm.Perm().Channels().Get("app1.node", "ch0")
+		}
+	})
+}
@@ -34,6 +34,8 @@ func (tmm *treeMemoryManager) Apps() AppMemory {
 // memory tree until it finds the dApp which name is equal to the last query element.
 // The root dApp is returned if the query string is an empty string.
 // If the specified dApp is found, it is returned. Otherwise, returns an error.
+// The dApps in the query path are copied into the transaction, if they weren't
+// yet, so the returned dApp can be changed without affecting the committed tree.
 func (amm *AppMemoryManager) Get(query string) (*meta.App, error) {
 	l := amm.logger.With(zap.String("operation", "get"), zap.String("query", query))
 	l.Debug("received dapp get request")
@@ -57,7 +59,7 @@ func (amm *AppMemoryManager) Get(query string) (*meta.App, error) {
 			l.Debug("dApp for given query is null", zap.String("app", element))
 			return nil, err
 		}
-		nextApp = nextApp.Spec.Apps[element]
+		nextApp = amm.writableApp(nextApp, element)
 		if nextApp == nil {
 			l.Debug("unable to find dApp for given query", zap.String("app", element))
 			return nil, err
@@ -200,6 +202,11 @@ func (amm *AppMemoryManager) Update(query string, app *meta.App, brokers *apimod
 		return err
 	}
 
+	if err = metautils.ValidateResourceVersion(currentApp.Meta, app.Meta.ResourceVersion); err != nil {
+		l.Debug("outdated dApp resource version", zap.Int("version", currentApp.Meta.ResourceVersion))
+		return err
+	}
+
 	l.Debug("validating new dApp structure")
 	if currentApp.Meta.Name != app.Meta.Name {
 		l.Debug("invalid name change operation", zap.String("old-dapp", currentApp.Meta.Name))
@@ -219,12 +226,23 @@ func (amm *AppMemoryManager) Update(query string, app *meta.App, brokers *apimod
 		return errParent
 	}
 
+	if err = updateTypes(currentApp, app); err != nil {
+		l.Debug("unable to update dApp - incompatible Type schemas")
+		return err
+	}
+
 	appErr := amm.checkApp(app, parent, brokers)
 	if appErr != nil {
 		l.Debug("unable to update dApp - invalid structure")
 		return appErr
 	}
 
+	// Templates are applied on their own, so a dApp that doesn't
+	// define them keeps the ones it had
+	if app.Spec.Templates == nil {
+		app.Spec.Templates = currentApp.Spec.Templates
+	}
+
 	l.Debug("deleting old dApp")
 	delete(parent.Spec.Apps, currentApp.Meta.Name)
 
@@ -234,6 +252,120 @@ func (amm *AppMemoryManager) Update(query string, app *meta.App, brokers *apimod
 	return nil
 }
 
+// Replace receives a dApp and the path in which it must be placed in the memory tree,
+// replacing the dApp found in the path along with all of its subtree, or creating it
+// if there is none. The root dApp can't be replaced as a whole, so when the path is
+// an empty string its components are replaced by the ones of the given dApp instead.
+// The given dApp is validated as it would be when created.
+func (amm *AppMemoryManager) Replace(query string, app *meta.App, brokers *apimodels.BrokersDI) error {
+	l := amm.logger.With(
+		zap.String("operation", "replace"),
+		zap.String("query", query),
+	)
+	l.Debug("received request for dapp replacement")
+
+	if query == "" {
+		l.Debug("replacing the components of the root dApp")
+		return amm.replaceRoot(app, brokers)
+	}
+
+	scope, name, err := metautils.RemoveLastPartInScope(query)
+	if err != nil {
+		return err
+	}
+	app.Meta.Name = name
+	// the dApp is replaced regardless of the current version
+	app.Meta.ResourceVersion = 0
+
+	_, err = amm.Get(query)
+	if ierrors.HasCode(err, ierrors.NotFound) {
+		l.Debug("dApp not found, creating it")
+		return amm.Create(scope, app, brokers)
+	}
+	if err != nil {
+		return err
+	}
+
+	return amm.Update(query, app, brokers)
+}
+
+// replaceRoot replaces the components of the root dApp by the ones of the given dApp.
+// Aliases are only validated once the child dApps, which they refer to, are created.
+func (amm *AppMemoryManager) replaceRoot(app *meta.App, brokers *apimodels.BrokersDI) error {
+	root, err := amm.Get("")
+	if err != nil {
+		return err
+	}
+
+	root.Spec.Apps = map[string]*meta.App{}
+	root.Spec.Channels = map[string]*meta.Channel{}
+	root.Spec.Types = map[string]*meta.Type{}
+	root.Spec.Aliases = map[string]*meta.Alias{}
+	root.Spec.Templates = map[string]*meta.Template{}
+
+	for name, insprType := range app.Spec.Types {
+		insprType.Meta.Name = name
+		insprType.ConnectedChannels = nil
+		if err = amm.Types().Create("", insprType); err != nil {
+			return err
+		}
+	}
+
+	for name, template := range app.Spec.Templates {
+		template.Meta.Name = name
+		if err = amm.Templates().Create("", template); err != nil {
+			return err
+		}
+	}
+
+	for name, ch := range app.Spec.Channels {
+		ch.Meta.Name = name
+		ch.ConnectedApps = nil
+		if err = amm.Channels().Create("", ch, brokers); err != nil {
+			return err
+		}
+	}
+
+	for name, alias := range app.Spec.Aliases {
+		alias.Meta.Name = name
+		root.Spec.Aliases[name] = alias
+	}
+
+	// the child dApps are created together, as their boundaries may be
+	// resolved through aliases that refer to each other
+	merr := ierrors.MultiError{
+		Errors: []error{},
+	}
+	for name, child := range app.Spec.Apps {
+		child.Meta.Name = name
+		amm.addAppInTree(child, root)
+	}
+	for _, child := range app.Spec.Apps {
+		merr.Add(amm.checkApp(child, root, brokers))
+	}
+	if !merr.Empty() {
+		return &merr
+	}
+	for _, child := range app.Spec.Apps {
+		merr.Add(amm.recursiveBoundaryValidation(child))
+	}
+	if !merr.Empty() {
+		return &merr
+	}
+
+	for _, alias := range root.Spec.Aliases {
+		if err = amm.Alias().CheckSource("", root, alias); err != nil {
+			return err
+		}
+		if err = amm.Alias().CheckDestination(root, alias); err != nil {
+			return err
+		}
+		alias.Meta = metautils.InjectUUID(alias.Meta)
+	}
+
+	return nil
+}
+
 // AppPermTreeGetter returns a getter that gets apps from the root structure of the app, without the current changes.
 // The getter does not allow changes in the structure, just visualization.
 type AppPermTreeGetter struct {
@@ -366,9 +498,9 @@ func (amm *AppMemoryManager) recursivelyResolveDown(app *meta.App, requester str
 
 func (amm *AppMemoryManager) checkForResource(app *meta.App, resource string, resourceType int) (string, bool) {
 	if resourceType == ROUTE {
-		if route, ok := app.Spec.Routes[resource]; ok {
+		if _, ok := app.Spec.Routes[resource]; ok {
 			scope, _ := metautils.JoinScopes(app.Meta.Parent, app.Meta.Name)
-			scope, _ = metautils.JoinScopes(scope, route.Meta.Name)
+			scope, _ = metautils.JoinScopes(scope, resource)
 			return scope, true
 		}
 	}
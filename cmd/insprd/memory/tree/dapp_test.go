@@ -2857,3 +2857,114 @@ func getIsAppUsedNotUsed() *meta.App {
 		},
 	}
 }
+
+func TestAppMemoryManager_Replace(t *testing.T) {
+	brokersDI := &apimodels.BrokersDI{
+		Available: []string{"kafka"},
+		Default:   "kafka",
+	}
+	snapshot := func() *meta.App {
+		return &meta.App{
+			Spec: meta.AppSpec{
+				Types: map[string]*meta.Type{
+					"t1": {Schema: `{"type":"string"}`},
+				},
+				Channels: map[string]*meta.Channel{
+					"ch1": {Spec: meta.ChannelSpec{Type: "t1"}},
+				},
+				Aliases: map[string]*meta.Alias{
+					"srcout": {Resource: "out", Source: "src", Destination: "dst"},
+				},
+				Apps: map[string]*meta.App{
+					"dst": {
+						Spec: meta.AppSpec{
+							Node: meta.Node{Spec: meta.NodeSpec{Image: "image"}},
+							Boundary: meta.AppBoundary{
+								Channels: meta.Boundary{
+									Input: []string{"ch1", "srcout"},
+								},
+							},
+						},
+					},
+					"src": {
+						Spec: meta.AppSpec{
+							Types: map[string]*meta.Type{
+								"t2": {Meta: meta.Metadata{Name: "t2"}, Schema: `{"type":"string"}`},
+							},
+							Channels: map[string]*meta.Channel{
+								"out": {Meta: meta.Metadata{Name: "out"}, Spec: meta.ChannelSpec{Type: "t2"}},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("replaces the root components", func(t *testing.T) {
+		tmm := newTreeMemory()
+		tmm.tree = syntheticTree(2, 1)
+		committed := tmm.tree
+
+		tmm.InitTransaction()
+		defer tmm.Cancel()
+		if err := tmm.Apps().Replace("", snapshot(), brokersDI); err != nil {
+			t.Fatalf("AppMemoryManager.Replace() error = %v", err)
+		}
+
+		root, _ := tmm.Apps().Get("")
+		if len(root.Spec.Apps) != 2 || root.Spec.Apps["app0"] != nil {
+			t.Errorf("Replace() didn't replace the root dApps, got %v", root.Spec.Apps)
+		}
+		ch, err := tmm.Channels().Get("", "ch1")
+		if err != nil || !utils.Includes(ch.ConnectedApps, "dst") {
+			t.Errorf("Replace() didn't connect the dApp to the root channel, got %v", ch)
+		}
+		out, err := tmm.Channels().Get("src", "out")
+		if err != nil || out.Meta.UUID == "" {
+			t.Errorf("Replace() didn't create the channels of the child dApps, got %v", out)
+		}
+		if alias := root.Spec.Aliases["srcout"]; alias == nil || alias.Meta.UUID == "" {
+			t.Errorf("Replace() didn't create the root aliases, got %v", alias)
+		}
+		if len(committed.Spec.Apps) != 2 || committed.Spec.Apps["app0"] == nil {
+			t.Errorf("Replace() changed the committed tree")
+		}
+	})
+
+	t.Run("rejects invalid root aliases", func(t *testing.T) {
+		tmm := newTreeMemory()
+		app := snapshot()
+		app.Spec.Aliases["srcout"].Source = "unknown"
+
+		tmm.InitTransaction()
+		defer tmm.Cancel()
+		if err := tmm.Apps().Replace("", app, brokersDI); err == nil {
+			t.Errorf("AppMemoryManager.Replace() expected an error for an invalid alias")
+		}
+	})
+
+	t.Run("replaces and creates dApps", func(t *testing.T) {
+		tmm := newTreeMemory()
+		tmm.tree = syntheticTree(2, 1)
+
+		tmm.InitTransaction()
+		defer tmm.Cancel()
+		replaced := snapshot().Spec.Apps["src"]
+		replaced.Meta.ResourceVersion = 7
+		if err := tmm.Apps().Replace("app0.node", replaced, brokersDI); err != nil {
+			t.Fatalf("AppMemoryManager.Replace() error = %v", err)
+		}
+		if err := tmm.Apps().Replace("app1.new", snapshot().Spec.Apps["src"], brokersDI); err != nil {
+			t.Fatalf("AppMemoryManager.Replace() error = %v", err)
+		}
+
+		node, _ := tmm.Apps().Get("app0.node")
+		if _, ok := node.Spec.Channels["ch0"]; ok || node.Spec.Channels["out"] == nil {
+			t.Errorf("Replace() didn't replace the dApp subtree, got %v", node.Spec.Channels)
+		}
+		if _, err := tmm.Apps().Get("app1.new"); err != nil {
+			t.Errorf("Replace() didn't create the dApp, got %v", err)
+		}
+	})
+}
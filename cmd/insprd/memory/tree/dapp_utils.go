@@ -85,6 +85,15 @@ func (amm *AppMemoryManager) validAppStructure(app, parentApp *meta.App, brokers
 		merr.Add(ierrors.New("unable to create dApp for its parent is a Node"))
 	}
 
+	switch app.Spec.Node.Spec.SidecarPort.Transport {
+	case "", meta.TransportHTTP, meta.TransportGRPC:
+	default:
+		merr.Add(ierrors.New(
+			"invalid sidecar transport '%s', it must be either %s or %s",
+			app.Spec.Node.Spec.SidecarPort.Transport, meta.TransportHTTP, meta.TransportGRPC,
+		).InvalidApp())
+	}
+
 	merr.Add(checkAndUpdates(app, brokers))
 	//merr.Add(amm.validAliases(app))
 
@@ -242,6 +251,31 @@ func (amm *AppMemoryManager) updateUUID(app *meta.App, parentStr string) {
 	}
 }
 
+// updateTypes checks the schemas of the Types of the updated dApp, and of its
+// children, against the ones of the Types with the same names they replace,
+// keeping the versions of the replaced Types
+func updateTypes(current, updated *meta.App) error {
+	errs := ierrors.MultiError{
+		Errors: []error{},
+	}
+	for name, insprType := range updated.Spec.Types {
+		if currentType, ok := current.Spec.Types[name]; ok {
+			errs.Add(metautils.CheckTypeCompatibility(currentType, insprType))
+			metautils.AddTypeVersion(currentType, insprType)
+		}
+	}
+	for name, child := range updated.Spec.Apps {
+		if currentChild, ok := current.Spec.Apps[name]; ok {
+			errs.Add(updateTypes(currentChild, child))
+		}
+	}
+
+	if !errs.Empty() {
+		return ierrors.New(&errs).BadRequest()
+	}
+	return nil
+}
+
 func nodeIsEmpty(node meta.Node) bool {
 	noAnnotations := node.Meta.Annotations == nil
 	noName := node.Meta.Name == ""
@@ -321,11 +355,12 @@ func checkAndUpdates(app *meta.App, brokers *apimodels.BrokersDI) error {
 	channels := app.Spec.Channels
 	types := app.Spec.Types
 
-	for typeName := range types {
+	for typeName, insprType := range types {
 		nameErr := metautils.StructureNameIsValid(typeName)
 		if nameErr != nil {
 			return ierrors.New("invalid type name '%v'", typeName)
 		}
+		insprType.Versions = metautils.TypeHistory(insprType)
 	}
 
 	for channelName, channel := range channels {
@@ -342,6 +377,22 @@ func checkAndUpdates(app *meta.App, brokers *apimodels.BrokersDI) error {
 				)
 			}
 
+			if _, _, err := metautils.TypeSchema(types[channel.Spec.Type], channel.Spec.TypeVersion); err != nil {
+				return ierrors.Wrap(err, fmt.Sprintf("invalid type version of channel '%v'", channelName))
+			}
+
+			if _, err := metautils.DeliveryPolicyOf(channel); err != nil {
+				return err
+			}
+
+			if err := validConcurrency(channel); err != nil {
+				return err
+			}
+
+			if err := validBatch(channel); err != nil {
+				return err
+			}
+
 			connectedChannels := types[channel.Spec.Type].ConnectedChannels
 			if !utils.Includes(connectedChannels, channelName) {
 				types[channel.Spec.Type].ConnectedChannels = append(connectedChannels, channelName)
@@ -362,7 +413,7 @@ func checkAndUpdates(app *meta.App, brokers *apimodels.BrokersDI) error {
 			)
 		}
 	}
-	return nil
+	return validDeadLetters(app)
 }
 
 func (amm *AppMemoryManager) validAliases(app *meta.App) error {
@@ -165,6 +165,55 @@ func Test_validAppStructure(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalidapp - unknown sidecar transport",
+			fields: fields{
+				root: getMockApp(),
+			},
+			args: args{
+				brokers: &apimodels.BrokersDI{
+					Available: []string{"some_broker"},
+					Default:   "some_broker",
+				},
+				app: meta.App{
+					Meta: meta.Metadata{
+						Name:        "app5",
+						Reference:   "app2.app5",
+						Annotations: map[string]string{},
+						Parent:      "app2",
+						UUID:        "",
+					},
+					Spec: meta.AppSpec{
+						Node: meta.Node{
+							Meta: meta.Metadata{
+								Name:        "nodeApp5",
+								Reference:   "app5.nodeApp5",
+								Annotations: map[string]string{},
+								Parent:      "app2",
+								UUID:        "",
+							},
+							Spec: meta.NodeSpec{
+								Image: "imageNodeApp5",
+								SidecarPort: meta.SidecarPort{
+									Transport: "websocket",
+								},
+							},
+						},
+						Apps:     map[string]*meta.App{},
+						Channels: map[string]*meta.Channel{},
+						Types:    map[string]*meta.Type{},
+						Boundary: meta.AppBoundary{
+							Channels: meta.Boundary{
+								Input:  []string{"ch1app2"},
+								Output: []string{"ch2app2"},
+							},
+						},
+					},
+				},
+				parentApp: *getMockApp().Spec.Apps["app2"],
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalidapp - parent has Node structure",
 			fields: fields{
@@ -1295,3 +1344,50 @@ func testRoutes(got, want *meta.App) bool {
 	}
 	return true
 }
+
+func Test_updateTypes(t *testing.T) {
+	appWithType := func(schema string) *meta.App {
+		return &meta.App{
+			Spec: meta.AppSpec{
+				Types: map[string]*meta.Type{
+					"user": {
+						Meta: meta.Metadata{
+							Name: "user",
+							Annotations: map[string]string{
+								metautils.SchemaCompatibilityAnnotation: metautils.CompatibilityBackward,
+							},
+						},
+						Schema: schema,
+					},
+				},
+			},
+		}
+	}
+	current := &meta.App{
+		Spec: meta.AppSpec{Apps: map[string]*meta.App{
+			"child": appWithType(`{"type":"record","name":"User","fields":[{"name":"name","type":"string"}]}`),
+		}},
+	}
+
+	compatible := &meta.App{
+		Spec: meta.AppSpec{Apps: map[string]*meta.App{
+			"child": appWithType(`{"type":"record","name":"User","fields":[{"name":"name","type":"bytes"}]}`),
+			"other": appWithType(`{"type":"int"}`),
+		}},
+	}
+	if err := updateTypes(current, compatible); err != nil {
+		t.Errorf("updateTypes() error = %v", err)
+	}
+	if versions := compatible.Spec.Apps["child"].Spec.Types["user"].Versions; len(versions) != 2 {
+		t.Errorf("updateTypes() versions = %v, want the replaced and the new schemas", versions)
+	}
+
+	incompatible := &meta.App{
+		Spec: meta.AppSpec{Apps: map[string]*meta.App{
+			"child": appWithType(`{"type":"record","name":"User","fields":[{"name":"id","type":"long"}]}`),
+		}},
+	}
+	if err := updateTypes(current, incompatible); err == nil {
+		t.Errorf("updateTypes() didn't find the incompatible Type of the child dApp")
+	}
+}
@@ -31,6 +31,7 @@ type AppMemory interface {
 	Create(scope string, app *meta.App, brokers *apimodels.BrokersDI) error
 	Delete(query string) error
 	Update(query string, app *meta.App, brokers *apimodels.BrokersDI) error
+	Replace(query string, app *meta.App, brokers *apimodels.BrokersDI) error
 	ResolveBoundary(app *meta.App, usePermTree bool) (map[string]string, map[string]string, error)
 }
 
@@ -71,6 +72,33 @@ type AliasGetInterface interface {
 	Get(scope, name string) (*meta.Alias, error)
 }
 
+// TemplateMemory is the interface that allows to obtain or
+// change information related to the current state of the
+// Templates in the cluster
+type TemplateMemory interface {
+	TransactionInterface
+	TemplateGetInterface
+	Create(scope string, template *meta.Template) error
+	Delete(scope, name string) error
+	Update(scope string, template *meta.Template) error
+	Instances(scope, name string) ([]string, error)
+}
+
+// TemplateGetInterface is an interface to get Templates from memory
+type TemplateGetInterface interface {
+	Get(scope, name string) (*meta.Template, error)
+}
+
+// RevisionMemory is the interface that allows to obtain the
+// history of transactions committed on the tree, and to roll
+// the tree back to any of them
+type RevisionMemory interface {
+	History() ([]apimodels.Revision, error)
+	Get(number int) (*apimodels.Revision, error)
+	Rollback(number int) error
+	Subscribe() (int, <-chan apimodels.Revision, func())
+}
+
 // Manager is the interface that allows the management
 // of the current state of the cluster. Permiting the
 // modification of Channels, DApps and Types
@@ -80,6 +108,8 @@ type Manager interface {
 	Channels() ChannelMemory
 	Types() TypeMemory
 	Alias() AliasMemory
+	Templates() TemplateMemory
+	Revisions() RevisionMemory
 	Perm() GetInterface
 }
 
@@ -89,11 +119,12 @@ type GetInterface interface {
 	Channels() ChannelGetInterface
 	Types() TypeGetInterface
 	Alias() AliasGetInterface
+	Templates() TemplateGetInterface
 }
 
 // TransactionInterface makes transactions on a Memory manager
 type TransactionInterface interface {
-	Commit()
+	Commit(author string) error
 	GetTransactionChanges() (diff.Changelog, error)
 	InitTransaction()
 	Cancel()
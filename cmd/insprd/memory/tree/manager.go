@@ -1,12 +1,15 @@
 package tree
 
 import (
+	"encoding/json"
 	"sync"
 
 	"go.uber.org/zap"
+	"inspr.dev/inspr/cmd/insprd/memory/storage"
+	apimodels "inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
 	"inspr.dev/inspr/pkg/logs"
 	"inspr.dev/inspr/pkg/meta"
-	"inspr.dev/inspr/pkg/meta/utils"
 	"inspr.dev/inspr/pkg/meta/utils/diff"
 )
 
@@ -19,11 +22,32 @@ func init() {
 	logger, _ = logs.Logger(zap.Fields(zap.String("section", "memory-tree")))
 }
 
+// treeStorageKey is the key in which the committed tree is persisted
+const treeStorageKey = "tree"
+
 // treeMemoryManager defines a memory manager interface
 type treeMemoryManager struct {
-	root *meta.App
-	tree *meta.App
+	root     *meta.App
+	tree     *meta.App
+	storage  storage.Storage
+	revision int
 	sync.Mutex
+
+	// treeData is the JSON of the committed tree, as it was persisted
+	treeData []byte
+	// snapshot is the number of the newest revision stored with its whole tree
+	snapshot int
+	// retention is the amount of revisions kept on the storage, or 0 to keep all
+	retention int
+
+	// owned holds the components copied by the current transaction
+	owned map[interface{}]struct{}
+	// treeLock guards the reference to the committed tree, which is
+	// replaced on commits and read without starting a transaction
+	treeLock sync.RWMutex
+
+	watchers     map[chan apimodels.Revision]struct{}
+	watchersLock sync.Mutex
 }
 
 var dapptree *treeMemoryManager
@@ -40,6 +64,8 @@ func GetTreeMemory() Manager {
 func newTreeMemory() *treeMemoryManager {
 	logger.Info("initializing memory tree")
 	return &treeMemoryManager{
+		storage:   storage.NewMemoryStorage(),
+		retention: defaultRevisionRetention,
 		tree: &meta.App{
 			Meta: meta.Metadata{
 				Annotations: map[string]string{},
@@ -62,27 +88,163 @@ func setTree(tmm *treeMemoryManager) {
 	dapptree = tmm
 }
 
-//InitTransaction copies and reserves the current tree structure so that changes can be reversed
+// Restore sets the storage in which the tree memory is persisted on every
+// commit and reloads the last committed tree from it, if there is one.
+// It should be called before the tree memory starts serving requests.
+func Restore(store storage.Storage) error {
+	GetTreeMemory()
+	return dapptree.restore(store)
+}
+
+func (tmm *treeMemoryManager) restore(store storage.Storage) error {
+	l := logger.With(zap.String("operation", "restore"))
+	tmm.Lock()
+	defer tmm.Unlock()
+
+	tmm.storage = store
+	revision, err := tmm.lastRevision()
+	if err != nil {
+		l.Error("unable to read revisions from storage", zap.Error(err))
+		return err
+	}
+	tmm.revision = revision
+
+	tmm.snapshot, err = tmm.lastSnapshot(revision)
+	if err != nil {
+		l.Error("unable to read snapshots from storage", zap.Error(err))
+		return err
+	}
+
+	data, err := store.Get(treeStorageKey)
+	if ierrors.HasCode(err, ierrors.NotFound) {
+		l.Info("no stored tree found, starting with an empty tree")
+		return nil
+	}
+	if err != nil {
+		l.Error("unable to read tree from storage", zap.Error(err))
+		return err
+	}
+
+	tree := &meta.App{}
+	if err = json.Unmarshal(data, tree); err != nil {
+		l.Error("unable to decode stored tree", zap.Error(err))
+		return ierrors.Wrap(
+			ierrors.New(err).InternalServer(),
+			"unable to decode stored tree",
+		)
+	}
+
+	tmm.replaceTree(tree)
+	tmm.treeData = data
+	l.Info("tree restored from storage")
+	return nil
+}
+
+// persist writes the transaction tree to the storage, if one is set, recording
+// its changes as a new revision, which is returned. The tree and its revision are
+// written in a single batch, and the revision is only taken when it is published.
+func (tmm *treeMemoryManager) persist(author string) (*apimodels.Revision, error) {
+	if tmm.storage == nil {
+		tmm.treeData = nil
+		return nil, nil
+	}
+
+	changes, err := diff.Diff(tmm.tree, tmm.root)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(tmm.root)
+	if err != nil {
+		return nil, ierrors.New(err).InternalServer()
+	}
+
+	batch := storage.NewBatch()
+	batch.Set[treeStorageKey] = data
+
+	var revision *apimodels.Revision
+	if len(changes) > 0 {
+		revision, err = tmm.addRevision(&batch, author, changes, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tmm.storage.Write(batch); err != nil {
+		return nil, err
+	}
+
+	tmm.treeData = data
+	if revision != nil {
+		if _, ok := batch.Set[snapshotKey(revision.Number)]; ok {
+			tmm.snapshot = revision.Number
+		}
+	}
+	return revision, nil
+}
+
+//InitTransaction reserves the current tree structure so that changes can be reversed.
+// The transaction root shares its components with the committed tree, which are
+// copied as they are retrieved for changes.
 func (tmm *treeMemoryManager) InitTransaction() {
 	tmm.Lock()
 	logger.Debug("locked mutex", zap.String("operation", "InitTransaction"), zap.String("type", "mutex"))
-	utils.DeepCopy(tmm.tree, &tmm.root)
+	tmm.owned = map[interface{}]struct{}{}
+	tmm.root = copyApp(tmm.tree)
+	tmm.own(tmm.root)
 	logger.Debug("transaction initialized", zap.String("operation", "InitTransaction"), zap.String("type", "mutex"))
 }
 
-//Commit applies changes from a transaction in to the tree structure
-func (tmm *treeMemoryManager) Commit() {
+//Commit applies changes from a transaction in to the tree structure.
+// The resource versions of the changed components are increased, and the
+// new tree is persisted before being applied, along with a revision
+// authored by the given user. If that fails the transaction is discarded
+// and the error is returned.
+func (tmm *treeMemoryManager) Commit(author string) error {
 	defer logger.Debug("freed mutex", zap.String("operation", "Commit"), zap.String("type", "mutex"))
 	defer tmm.Unlock()
-	tmm.tree = tmm.root
-	tmm.root = nil
+	defer tmm.endTransaction()
+
+	stampVersions(tmm.tree, tmm.root)
+
+	revision, err := tmm.persist(author)
+	if err != nil {
+		logger.Error("unable to persist tree, discarding transaction", zap.Error(err))
+		return ierrors.Wrap(err, "unable to persist tree")
+	}
+	tmm.replaceTree(tmm.root)
+
+	if revision != nil {
+		tmm.publish(*revision)
+	}
+	return nil
 }
 
 //Cancel discarts changes made in the last transaction
 func (tmm *treeMemoryManager) Cancel() {
 	defer logger.Debug("freed mutex", zap.String("operation", "Cancel"), zap.String("type", "mutex"))
 	defer tmm.Unlock()
+	tmm.endTransaction()
+}
+
+// endTransaction discards the transaction root and the copies it owns
+func (tmm *treeMemoryManager) endTransaction() {
 	tmm.root = nil
+	tmm.owned = nil
+}
+
+// replaceTree replaces the committed tree
+func (tmm *treeMemoryManager) replaceTree(tree *meta.App) {
+	tmm.treeLock.Lock()
+	defer tmm.treeLock.Unlock()
+	tmm.tree = tree
+}
+
+// committedTree returns the committed tree, without the current changes
+func (tmm *treeMemoryManager) committedTree() *meta.App {
+	tmm.treeLock.RLock()
+	defer tmm.treeLock.RUnlock()
+	return tmm.tree
 }
 
 //GetTransactionChanges returns the changelog resulting from the current transaction.
@@ -129,10 +291,20 @@ func (ptg *PermTreeGetter) Alias() AliasGetInterface {
 	}
 }
 
+// Templates returns a getter for Templates on the root
+func (ptg *PermTreeGetter) Templates() TemplateGetInterface {
+	return &TemplatePermTreeGetter{
+		PermTreeGetter: ptg,
+		logs:           logger,
+	}
+}
+
 // Perm returns a getter for objects on the tree without the current changes.
+// It doesn't wait for the running transaction, and the returned components
+// must not be changed.
 func (tmm *treeMemoryManager) Perm() GetInterface {
 	return &PermTreeGetter{
-		tree:   tmm.tree,
+		tree:   tmm.committedTree(),
 		logger: logger,
 	}
 }
@@ -0,0 +1,145 @@
+package tree
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"inspr.dev/inspr/cmd/insprd/memory/storage"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+)
+
+// failingStorage is a storage in which every write fails
+type failingStorage struct {
+	storage.Storage
+}
+
+func (failingStorage) Set(key string, value []byte) error {
+	return ierrors.New("storage unavailable").InternalServer()
+}
+
+func (failingStorage) Write(batch storage.Batch) error {
+	return ierrors.New("storage unavailable").InternalServer()
+}
+
+func TestTreeMemoryManager_Commit(t *testing.T) {
+	tests := []struct {
+		name     string
+		storage  storage.Storage
+		wantErr  bool
+		wantTree bool
+	}{
+		{
+			name:     "commits without a storage",
+			wantTree: true,
+		},
+		{
+			name:     "commits and persists the tree",
+			storage:  storage.NewMemoryStorage(),
+			wantTree: true,
+		},
+		{
+			name:    "discards the transaction when persisting fails",
+			storage: failingStorage{storage.NewMemoryStorage()},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmm := newTreeMemory()
+			tmm.storage = tt.storage
+			previous := tmm.tree
+
+			tmm.InitTransaction()
+			tmm.root.Spec.Apps["app1"] = &meta.App{Meta: meta.Metadata{Name: "app1"}}
+			changed := tmm.root
+
+			err := tmm.Commit("uid")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("treeMemoryManager.Commit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantTree && tmm.tree != changed {
+				t.Errorf("treeMemoryManager.Commit() didn't apply the transaction")
+			}
+			if !tt.wantTree && tmm.tree != previous {
+				t.Errorf("treeMemoryManager.Commit() applied a failed transaction")
+			}
+			if tmm.root != nil {
+				t.Errorf("treeMemoryManager.Commit() didn't clear the transaction")
+			}
+
+			if tt.wantTree && tt.storage != nil {
+				data, _ := tt.storage.Get(treeStorageKey)
+				stored := &meta.App{}
+				json.Unmarshal(data, stored)
+				if _, ok := stored.Spec.Apps["app1"]; !ok {
+					t.Errorf("treeMemoryManager.Commit() didn't persist the tree")
+				}
+			}
+
+			// the mutex must be free after the commit
+			tmm.InitTransaction()
+			tmm.Cancel()
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	stored := getMockApp()
+	data, _ := json.Marshal(stored)
+
+	withTree := storage.NewMemoryStorage()
+	withTree.Set(treeStorageKey, data)
+
+	corrupted := storage.NewMemoryStorage()
+	corrupted.Set(treeStorageKey, []byte("{not json"))
+
+	tests := []struct {
+		name    string
+		storage storage.Storage
+		want    *meta.App
+		wantErr bool
+	}{
+		{
+			name:    "restores the stored tree",
+			storage: withTree,
+			want:    stored,
+		},
+		{
+			name:    "keeps an empty tree when nothing is stored",
+			storage: storage.NewMemoryStorage(),
+			want:    newTreeMemory().tree,
+		},
+		{
+			name:    "fails on a corrupted tree",
+			storage: corrupted,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTree(newTreeMemory())
+			defer setTree(nil)
+
+			err := Restore(tt.storage)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Restore() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got, _ := json.Marshal(dapptree.tree)
+			want, _ := json.Marshal(tt.want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Restore() tree = %s, want %s", got, want)
+			}
+			if dapptree.storage != tt.storage {
+				t.Errorf("Restore() didn't set the storage")
+			}
+		})
+	}
+}
@@ -34,6 +34,11 @@ func (mock *MockAppManager) Update(scope string, app *meta.App, brokers *apimode
 	return nil
 }
 
+// Replace Mock
+func (mock *MockAppManager) Replace(scope string, app *meta.App, brokers *apimodels.BrokersDI) error {
+	return nil
+}
+
 // ResolveBoundary Mock
 func (mock *MockAppManager) ResolveBoundary(app *meta.App, usePermTree bool) (map[string]string, map[string]string, error) {
 	return nil, nil, nil
@@ -57,7 +57,7 @@ func (tmm *MockManager) Apps() AppMemory {
 func (tmm *MockManager) InitTransaction() {}
 
 //Commit mock interface structure
-func (tmm *MockManager) Commit() {}
+func (tmm *MockManager) Commit(author string) error { return nil }
 
 //Cancel mock interface structure
 func (tmm *MockManager) Cancel() {}
@@ -0,0 +1,355 @@
+package tree
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"go.uber.org/zap"
+	"inspr.dev/inspr/cmd/insprd/memory/storage"
+	apimodels "inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+)
+
+const (
+	// revisionPrefix is the prefix of the keys in which the revisions are stored
+	revisionPrefix = "revisions/"
+	// patchPrefix is the prefix of the keys in which the patch from the tree of
+	// the previous revision to the tree of each revision is stored
+	patchPrefix = "patches/"
+	// snapshotPrefix is the prefix of the keys in which the whole tree of some
+	// revisions is stored, the trees of the others are patched from them
+	snapshotPrefix = "snapshots/"
+	// snapshotInterval is the amount of revisions between two snapshots
+	snapshotInterval = 10
+	// defaultRevisionRetention is the amount of revisions kept on the storage,
+	// older ones are pruned as new snapshots are taken
+	defaultRevisionRetention = 100
+	// watcherBufferSize is the amount of revisions a subscriber can fall behind
+	watcherBufferSize = 64
+)
+
+func revisionKey(number int) string {
+	return fmt.Sprintf("%s%010d", revisionPrefix, number)
+}
+
+func patchKey(number int) string {
+	return fmt.Sprintf("%s%010d", patchPrefix, number)
+}
+
+func snapshotKey(number int) string {
+	return fmt.Sprintf("%s%010d", snapshotPrefix, number)
+}
+
+// keyNumber returns the revision number of a key with the given prefix
+func keyNumber(key, prefix string) (int, error) {
+	number, err := strconv.Atoi(strings.TrimPrefix(key, prefix))
+	if err != nil {
+		return 0, ierrors.New("invalid revision key %v", key).InternalServer()
+	}
+	return number, nil
+}
+
+// RevisionMemoryManager implements the RevisionMemory interface
+// and provides methods for operating on the tree's revisions
+type RevisionMemoryManager struct {
+	*treeMemoryManager
+	logger *zap.Logger
+}
+
+// Revisions is a MemoryManager method that provides an access point for revisions
+func (tmm *treeMemoryManager) Revisions() RevisionMemory {
+	logger.Debug("recovering revision manager on the memory tree")
+	return &RevisionMemoryManager{
+		treeMemoryManager: tmm,
+		logger:            logger.With(zap.String("subSection", "revisions")),
+	}
+}
+
+// History returns every revision committed on the tree, ordered from
+// the oldest to the newest. The trees of the revisions are not returned.
+func (rmm *RevisionMemoryManager) History() ([]apimodels.Revision, error) {
+	l := rmm.logger.With(zap.String("operation", "history"))
+	l.Debug("received revision history request")
+
+	revisions := []apimodels.Revision{}
+	if rmm.storage == nil {
+		return revisions, nil
+	}
+
+	keys, err := rmm.storage.Keys(revisionPrefix)
+	if err != nil {
+		l.Error("unable to list revisions", zap.Error(err))
+		return nil, err
+	}
+
+	for _, key := range keys {
+		revision := apimodels.Revision{}
+		data, err := rmm.storage.Get(key)
+		if err == nil {
+			err = json.Unmarshal(data, &revision)
+		}
+		if err != nil {
+			l.Error("unable to read revision", zap.String("key", key), zap.Error(err))
+			return nil, ierrors.Wrap(err, "unable to read revision "+key)
+		}
+		revisions = append(revisions, revision)
+	}
+	return revisions, nil
+}
+
+// Get returns the given revision, along with the tree committed on it
+func (rmm *RevisionMemoryManager) Get(number int) (*apimodels.Revision, error) {
+	l := rmm.logger.With(zap.String("operation", "get"), zap.Int("revision", number))
+	l.Debug("received revision get request")
+
+	if rmm.storage == nil {
+		return nil, ierrors.New("revision %v not found", number).NotFound()
+	}
+
+	data, err := rmm.storage.Get(revisionKey(number))
+	if ierrors.HasCode(err, ierrors.NotFound) {
+		l.Debug("revision not found")
+		return nil, ierrors.New("revision %v not found", number).NotFound()
+	}
+	if err != nil {
+		l.Error("unable to read revision", zap.Error(err))
+		return nil, err
+	}
+
+	revision := &apimodels.Revision{}
+	if err = json.Unmarshal(data, revision); err != nil {
+		return nil, ierrors.New(err).InternalServer()
+	}
+
+	data, err = rmm.revisionTree(number)
+	if err != nil {
+		l.Error("unable to read revision tree", zap.Error(err))
+		return nil, ierrors.Wrap(err, "unable to read the tree of revision "+strconv.Itoa(number))
+	}
+
+	revision.Tree = &meta.App{}
+	if err = json.Unmarshal(data, revision.Tree); err != nil {
+		return nil, ierrors.New(err).InternalServer()
+	}
+	return revision, nil
+}
+
+// revisionTree returns the tree of the given revision, applying the patches
+// of the revisions after the newest snapshot taken before it
+func (rmm *RevisionMemoryManager) revisionTree(number int) ([]byte, error) {
+	base, err := rmm.lastSnapshot(number)
+	if err != nil {
+		return nil, err
+	}
+	if base == 0 {
+		return nil, ierrors.New("no snapshot found before revision %v", number).InternalServer()
+	}
+
+	data, err := rmm.storage.Get(snapshotKey(base))
+	if err != nil {
+		return nil, err
+	}
+	for i := base + 1; i <= number; i++ {
+		patch, err := rmm.storage.Get(patchKey(i))
+		if err != nil {
+			return nil, err
+		}
+		if data, err = jsonpatch.MergePatch(data, patch); err != nil {
+			return nil, ierrors.Wrap(
+				ierrors.New(err).InternalServer(),
+				"unable to apply the patch of revision "+strconv.Itoa(i),
+			)
+		}
+	}
+	return data, nil
+}
+
+// Rollback replaces the tree of the current transaction with the tree
+// committed on the given revision. The changes are only applied on Commit.
+func (rmm *RevisionMemoryManager) Rollback(number int) error {
+	l := rmm.logger.With(zap.String("operation", "rollback"), zap.Int("revision", number))
+	l.Info("received tree rollback request")
+
+	revision, err := rmm.Get(number)
+	if err != nil {
+		l.Debug("unable to get revision")
+		return err
+	}
+
+	rmm.root = revision.Tree
+	rmm.own(rmm.root)
+	l.Debug("transaction tree replaced by the revision tree")
+	return nil
+}
+
+// Subscribe returns the number of the last committed revision, a channel on
+// which every revision committed from now on is sent, and a function that ends
+// the subscription. Subscribers that don't keep up with the commits have their
+// channel closed, and should resume from the last revision they received by
+// reading the history.
+func (rmm *RevisionMemoryManager) Subscribe() (int, <-chan apimodels.Revision, func()) {
+	rmm.watchersLock.Lock()
+	defer rmm.watchersLock.Unlock()
+
+	if rmm.watchers == nil {
+		rmm.watchers = map[chan apimodels.Revision]struct{}{}
+	}
+	watcher := make(chan apimodels.Revision, watcherBufferSize)
+	rmm.watchers[watcher] = struct{}{}
+	rmm.logger.Debug("new revision subscriber", zap.Int("subscribers", len(rmm.watchers)))
+
+	return rmm.revision, watcher, func() {
+		rmm.watchersLock.Lock()
+		defer rmm.watchersLock.Unlock()
+		if _, ok := rmm.watchers[watcher]; ok {
+			delete(rmm.watchers, watcher)
+			close(watcher)
+		}
+	}
+}
+
+// publish takes the given revision as the last committed one and sends it to
+// every subscriber, dropping the ones whose buffer is full instead of blocking
+func (tmm *treeMemoryManager) publish(revision apimodels.Revision) {
+	tmm.watchersLock.Lock()
+	defer tmm.watchersLock.Unlock()
+
+	tmm.revision = revision.Number
+
+	for watcher := range tmm.watchers {
+		select {
+		case watcher <- revision:
+		default:
+			logger.Info("revision subscriber is too slow, dropping it",
+				zap.Int("revision", revision.Number))
+			delete(tmm.watchers, watcher)
+			close(watcher)
+		}
+	}
+}
+
+// addRevision adds the changes of the current transaction to the batch as a new
+// revision, along with the patch from the committed tree to the transaction
+// tree, whose JSON is given. Every snapshotInterval revisions the whole tree is
+// stored instead, and the revisions past the retention are pruned.
+func (tmm *treeMemoryManager) addRevision(
+	batch *storage.Batch,
+	author string,
+	changes diff.Changelog,
+	tree []byte,
+) (*apimodels.Revision, error) {
+	number := tmm.revision + 1
+
+	revision := &apimodels.Revision{
+		Number:    number,
+		Author:    author,
+		Timestamp: time.Now().UTC(),
+		Changes:   changes,
+	}
+	data, err := json.Marshal(revision)
+	if err != nil {
+		return nil, ierrors.New(err).InternalServer()
+	}
+	batch.Set[revisionKey(number)] = data
+
+	if tmm.snapshot == 0 || number-tmm.snapshot >= snapshotInterval {
+		batch.Set[snapshotKey(number)] = tree
+		if err = tmm.prune(batch, number); err != nil {
+			return nil, err
+		}
+		return revision, nil
+	}
+
+	previous, err := tmm.committedData()
+	if err != nil {
+		return nil, err
+	}
+	patch, err := jsonpatch.CreateMergePatch(previous, tree)
+	if err != nil {
+		return nil, ierrors.New(err).InternalServer()
+	}
+	batch.Set[patchKey(number)] = patch
+	return revision, nil
+}
+
+// prune adds to the batch the removal of the revisions older than the retention.
+// Revisions are only removed along with the snapshot their trees are patched
+// from, so the retention is kept by the newest snapshot taken before it.
+func (tmm *treeMemoryManager) prune(batch *storage.Batch, number int) error {
+	if tmm.retention <= 0 {
+		return nil
+	}
+	oldest := number - tmm.retention + 1
+
+	base := 0
+	if number <= oldest {
+		base = number
+	} else {
+		last, err := tmm.lastSnapshot(oldest)
+		if err != nil {
+			return err
+		}
+		base = last
+	}
+
+	for _, prefix := range []string{revisionPrefix, patchPrefix, snapshotPrefix} {
+		keys, err := tmm.storage.Keys(prefix)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if n, err := keyNumber(key, prefix); err == nil && n < base {
+				batch.Delete = append(batch.Delete, key)
+			}
+		}
+	}
+	return nil
+}
+
+// lastSnapshot returns the number of the newest snapshot taken up to the
+// given revision, or 0 if there is none
+func (tmm *treeMemoryManager) lastSnapshot(number int) (int, error) {
+	keys, err := tmm.storage.Keys(snapshotPrefix)
+	if err != nil {
+		return 0, err
+	}
+
+	last := 0
+	for _, key := range keys {
+		n, err := keyNumber(key, snapshotPrefix)
+		if err != nil {
+			return 0, err
+		}
+		if n <= number && n > last {
+			last = n
+		}
+	}
+	return last, nil
+}
+
+// committedData returns the JSON of the committed tree
+func (tmm *treeMemoryManager) committedData() ([]byte, error) {
+	if tmm.treeData != nil {
+		return tmm.treeData, nil
+	}
+	data, err := json.Marshal(tmm.tree)
+	if err != nil {
+		return nil, ierrors.New(err).InternalServer()
+	}
+	return data, nil
+}
+
+// lastRevision returns the number of the newest revision on the storage
+func (tmm *treeMemoryManager) lastRevision() (int, error) {
+	keys, err := tmm.storage.Keys(revisionPrefix)
+	if err != nil || len(keys) == 0 {
+		return 0, err
+	}
+	return keyNumber(keys[len(keys)-1], revisionPrefix)
+}
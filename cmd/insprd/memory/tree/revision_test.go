@@ -0,0 +1,238 @@
+package tree
+
+import (
+	"fmt"
+	"testing"
+
+	"inspr.dev/inspr/cmd/insprd/memory/storage"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+)
+
+// commitApp commits a transaction that adds the given app to the tree root
+func commitApp(t *testing.T, tmm *treeMemoryManager, name, author string) {
+	tmm.InitTransaction()
+	tmm.root.Spec.Apps[name] = &meta.App{Meta: meta.Metadata{Name: name, Parent: ""}}
+	if err := tmm.Commit(author); err != nil {
+		t.Fatalf("treeMemoryManager.Commit() error = %v", err)
+	}
+}
+
+func TestRevisionMemoryManager_History(t *testing.T) {
+	tmm := newTreeMemory()
+	commitApp(t, tmm, "app1", "user1")
+	commitApp(t, tmm, "app2", "user2")
+
+	// transactions without changes don't create revisions
+	tmm.InitTransaction()
+	tmm.Commit("user3")
+
+	got, err := tmm.Revisions().History()
+	if err != nil {
+		t.Fatalf("RevisionMemoryManager.History() error = %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("RevisionMemoryManager.History() = %v, want 2 revisions", got)
+	}
+	for i, author := range []string{"user1", "user2"} {
+		if got[i].Number != i+1 || got[i].Author != author {
+			t.Errorf("RevisionMemoryManager.History()[%d] = %v", i, got[i])
+		}
+		if len(got[i].Changes) == 0 || got[i].Timestamp.IsZero() || got[i].Tree != nil {
+			t.Errorf("RevisionMemoryManager.History()[%d] = %v", i, got[i])
+		}
+	}
+}
+
+func TestRevisionMemoryManager_Get(t *testing.T) {
+	tmm := newTreeMemory()
+	commitApp(t, tmm, "app1", "user1")
+	commitApp(t, tmm, "app2", "user1")
+
+	tests := []struct {
+		name     string
+		number   int
+		wantApps []string
+		wantErr  bool
+	}{
+		{
+			name:     "gets the tree of the first revision",
+			number:   1,
+			wantApps: []string{"app1"},
+		},
+		{
+			name:     "gets the tree of the last revision",
+			number:   2,
+			wantApps: []string{"app1", "app2"},
+		},
+		{
+			name:    "fails on a nonexistent revision",
+			number:  3,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tmm.Revisions().Get(tt.number)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RevisionMemoryManager.Get() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if !ierrors.HasCode(err, ierrors.NotFound) {
+					t.Errorf("RevisionMemoryManager.Get() error = %v, want NotFound", err)
+				}
+				return
+			}
+			if len(got.Tree.Spec.Apps) != len(tt.wantApps) {
+				t.Errorf("RevisionMemoryManager.Get() apps = %v, want %v", got.Tree.Spec.Apps, tt.wantApps)
+			}
+			for _, app := range tt.wantApps {
+				if _, ok := got.Tree.Spec.Apps[app]; !ok {
+					t.Errorf("RevisionMemoryManager.Get() missing app %v", app)
+				}
+			}
+		})
+	}
+}
+
+func TestRevisionMemoryManager_Get_patched(t *testing.T) {
+	tmm := newTreeMemory()
+	for i := 1; i <= snapshotInterval+2; i++ {
+		commitApp(t, tmm, fmt.Sprintf("app%d", i), "user1")
+	}
+
+	// only some revisions are stored with the whole tree
+	snapshots, _ := tmm.storage.Keys(snapshotPrefix)
+	if len(snapshots) != 2 {
+		t.Errorf("treeMemoryManager.Commit() snapshots = %v, want 2", snapshots)
+	}
+
+	for _, number := range []int{2, snapshotInterval, snapshotInterval + 2} {
+		got, err := tmm.Revisions().Get(number)
+		if err != nil {
+			t.Fatalf("RevisionMemoryManager.Get(%d) error = %v", number, err)
+		}
+		if len(got.Tree.Spec.Apps) != number {
+			t.Errorf("RevisionMemoryManager.Get(%d) apps = %v", number, got.Tree.Spec.Apps)
+		}
+	}
+}
+
+func TestRevisionMemoryManager_prune(t *testing.T) {
+	tmm := newTreeMemory()
+	tmm.retention = snapshotInterval + 5
+	last := 3*snapshotInterval + 1
+	for i := 1; i <= last; i++ {
+		commitApp(t, tmm, fmt.Sprintf("app%d", i), "user1")
+	}
+
+	// the revisions are pruned up to the snapshot before the retention
+	history, _ := tmm.Revisions().History()
+	oldest := 2*snapshotInterval + 1 - snapshotInterval
+	if len(history) != last-oldest+1 || history[0].Number != oldest {
+		t.Errorf("treeMemoryManager.Commit() kept %d revisions from %d", len(history), history[0].Number)
+	}
+	if _, err := tmm.Revisions().Get(oldest); err != nil {
+		t.Errorf("RevisionMemoryManager.Get() of the oldest revision error = %v", err)
+	}
+	if _, err := tmm.Revisions().Get(oldest - 1); !ierrors.HasCode(err, ierrors.NotFound) {
+		t.Errorf("RevisionMemoryManager.Get() of a pruned revision error = %v, want NotFound", err)
+	}
+}
+
+func TestRevisionMemoryManager_Rollback(t *testing.T) {
+	tmm := newTreeMemory()
+	commitApp(t, tmm, "app1", "user1")
+	commitApp(t, tmm, "app2", "user1")
+
+	tmm.InitTransaction()
+	if err := tmm.Revisions().Rollback(1); err != nil {
+		t.Fatalf("RevisionMemoryManager.Rollback() error = %v", err)
+	}
+
+	changes, _ := tmm.GetTransactionChanges()
+	if len(changes) == 0 {
+		t.Errorf("RevisionMemoryManager.Rollback() didn't change the transaction tree")
+	}
+	tmm.Commit("user2")
+
+	if _, ok := tmm.tree.Spec.Apps["app2"]; ok {
+		t.Errorf("RevisionMemoryManager.Rollback() kept app2 on the tree")
+	}
+
+	// the rollback is a revision of its own
+	history, _ := tmm.Revisions().History()
+	if len(history) != 3 || history[2].Author != "user2" {
+		t.Errorf("RevisionMemoryManager.Rollback() history = %v", history)
+	}
+
+	tmm.InitTransaction()
+	defer tmm.Cancel()
+	if err := tmm.Revisions().Rollback(10); !ierrors.HasCode(err, ierrors.NotFound) {
+		t.Errorf("RevisionMemoryManager.Rollback() error = %v, want NotFound", err)
+	}
+}
+
+func TestRestore_revisions(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	tmm := newTreeMemory()
+	tmm.storage = store
+	commitApp(t, tmm, "app1", "user1")
+	commitApp(t, tmm, "app2", "user1")
+
+	setTree(newTreeMemory())
+	defer setTree(nil)
+	if err := Restore(store); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	commitApp(t, dapptree, "app3", "user1")
+	history, _ := dapptree.Revisions().History()
+	if len(history) != 3 || history[2].Number != 3 {
+		t.Errorf("Restore() didn't resume the revision count, history = %v", history)
+	}
+}
+
+func TestRevisionMemoryManager_Subscribe(t *testing.T) {
+	tmm := newTreeMemory()
+	commitApp(t, tmm, "app1", "user1")
+
+	current, events, cancel := tmm.Revisions().Subscribe()
+	if current != 1 {
+		t.Errorf("RevisionMemoryManager.Subscribe() revision = %v, want 1", current)
+	}
+
+	commitApp(t, tmm, "app2", "user2")
+	revision := <-events
+	if revision.Number != 2 || revision.Author != "user2" || len(revision.Changes) == 0 {
+		t.Errorf("RevisionMemoryManager.Subscribe() sent %v", revision)
+	}
+
+	cancel()
+	if _, ok := <-events; ok {
+		t.Errorf("RevisionMemoryManager.Subscribe() channel not closed on cancel")
+	}
+	// cancelling twice must not panic
+	cancel()
+}
+
+func TestRevisionMemoryManager_Subscribe_slow(t *testing.T) {
+	tmm := newTreeMemory()
+	_, events, cancel := tmm.Revisions().Subscribe()
+	defer cancel()
+
+	// commits never block on subscribers that fall behind
+	for i := 0; i <= watcherBufferSize; i++ {
+		commitApp(t, tmm, fmt.Sprintf("app%d", i), "user1")
+	}
+
+	received := 0
+	for range events {
+		received++
+	}
+	if received != watcherBufferSize {
+		t.Errorf("RevisionMemoryManager.Subscribe() sent %v revisions, want %v", received, watcherBufferSize)
+	}
+}
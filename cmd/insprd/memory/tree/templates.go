@@ -0,0 +1,250 @@
+package tree
+
+import (
+	"go.uber.org/zap"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/meta/utils"
+)
+
+// TemplateMemoryManager implements the Template interface
+// and provides methods for operating on Templates
+type TemplateMemoryManager struct {
+	logger *zap.Logger
+	*treeMemoryManager
+}
+
+// Templates is a MemoryManager method that provides an access point for Templates
+func (mm *treeMemoryManager) Templates() TemplateMemory {
+	logger.Debug("recovering template manager on the memory tree")
+	return &TemplateMemoryManager{
+		treeMemoryManager: mm,
+		logger:            logger.With(zap.String("subSection", "templates")),
+	}
+}
+
+// Create creates, if it doesn't already exist, a new Template for a given app.
+// template: Template to be created.
+// scope: Path to reference app (x.y.z...)
+func (tmm *TemplateMemoryManager) Create(scope string, template *meta.Template) error {
+	l := tmm.logger.With(
+		zap.String("operation", "create"),
+		zap.String("template", template.Meta.Name),
+		zap.String("scope", scope),
+	)
+	l.Info("received template creation request")
+
+	l.Debug("validating Template structure")
+	err := utils.StructureNameIsValid(template.Meta.Name)
+	if err != nil {
+		l.Error("invalid Template name")
+		return err
+	}
+
+	err = utils.ValidateTemplate(template)
+	if err != nil {
+		l.Error("invalid Template", zap.Error(err))
+		return err
+	}
+
+	l.Debug("checking if Template already exists")
+	_, err = tmm.Get(scope, template.Meta.Name)
+	if err == nil {
+		l.Info("template already exists")
+		return ierrors.New(
+			"target app already has a '%v' Template", template.Meta.Name,
+		).AlreadyExists()
+	}
+
+	l.Debug("getting Template parent dApp")
+	parentApp, err := tmm.Apps().Get(scope)
+	if err != nil {
+		return ierrors.Wrap(
+			err,
+			"couldn't create Template "+template.Meta.Name,
+		)
+	}
+
+	l.Debug("adding Template to dApp")
+	if parentApp.Spec.Templates == nil {
+		parentApp.Spec.Templates = map[string]*meta.Template{}
+	}
+	template.Meta = utils.InjectUUID(template.Meta)
+	template.Meta.Parent = scope
+	parentApp.Spec.Templates[template.Meta.Name] = template
+	l.Debug("template created")
+	return nil
+}
+
+// Get returns, if it exists, a specific Template from a given app.
+// name: Name of desired Template.
+// scope: Path to reference app (x.y.z...)
+func (tmm *TemplateMemoryManager) Get(scope, name string) (*meta.Template, error) {
+	l := tmm.logger.With(
+		zap.String("operation", "get"),
+		zap.String("template", name),
+		zap.String("scope", scope),
+	)
+	l.Debug("received template recovery request")
+
+	parentApp, err := tmm.Apps().Get(scope)
+	if err != nil {
+		l.Debug("parent app does not exist, returning error")
+		return nil, ierrors.Wrap(
+			err,
+			"target dApp doesn't exist",
+		)
+	}
+
+	if _, ok := parentApp.Spec.Templates[name]; ok {
+		l.Debug("recovered template, returning value")
+		return tmm.writableTemplate(parentApp, name), nil
+	}
+
+	l.Debug("unable to get Template in given scope")
+	return nil, ierrors.New("Template not found for given query").NotFound()
+}
+
+// Delete deletes, if it exists and has no instances, a Template from a given app.
+// name: Name of desired Template.
+// scope: Path to reference app (x.y.z...)
+func (tmm *TemplateMemoryManager) Delete(scope, name string) error {
+	l := tmm.logger.With(
+		zap.String("operation", "delete"),
+		zap.String("template", name),
+		zap.String("scope", scope),
+	)
+	l.Info("received template deletion request")
+
+	_, err := tmm.Get(scope, name)
+	if err != nil {
+		l.Debug("unable to find template in tree")
+		return ierrors.New(
+			"target app doesn't contain a '%v' Template", name,
+		).BadRequest()
+	}
+
+	l.Debug("checking if Template can be deleted")
+	instances, err := tmm.Instances(scope, name)
+	if err != nil {
+		return err
+	}
+	if len(instances) > 0 {
+		l.Info("unable to delete Template for it has instances",
+			zap.Strings("instances", instances))
+
+		return ierrors.New(
+			"Template cannot be deleted as it has instances: %v", instances,
+		).BadRequest()
+	}
+
+	parentApp, err := tmm.Apps().Get(scope)
+	if err != nil {
+		l.Info("unable to get dApp from memory tree")
+		return ierrors.Wrap(
+			err,
+			"target app doesn't exist",
+		)
+	}
+
+	l.Info("removing Template from its parents 'Templates' structure")
+	delete(parentApp.Spec.Templates, name)
+	return nil
+}
+
+// Update updates, if it exists, a Template of a given app. The dApps created
+// from the Template are not changed, see Instances.
+// template: Updated Template to be updated on app
+// scope: Path to reference app (x.y.z...)
+func (tmm *TemplateMemoryManager) Update(scope string, template *meta.Template) error {
+	l := tmm.logger.With(
+		zap.String("operation", "update"),
+		zap.String("template", template.Meta.Name),
+		zap.String("scope", scope),
+	)
+	l.Info("received request for template update")
+
+	oldTemplate, err := tmm.Get(scope, template.Meta.Name)
+	if err != nil {
+		l.Debug("unable to find template in the tree")
+		return ierrors.New(
+			"target app doesn't contain a '%v' Template", template.Meta.Name,
+		).BadRequest()
+	}
+
+	err = utils.ValidateResourceVersion(oldTemplate.Meta, template.Meta.ResourceVersion)
+	if err != nil {
+		l.Debug("outdated Template resource version", zap.Int("version", oldTemplate.Meta.ResourceVersion))
+		return err
+	}
+
+	err = utils.ValidateTemplate(template)
+	if err != nil {
+		l.Error("invalid Template", zap.Error(err))
+		return err
+	}
+
+	template.Meta.UUID = oldTemplate.Meta.UUID
+	template.Meta.Parent = scope
+
+	parentApp, err := tmm.Apps().Get(scope)
+	if err != nil {
+		return ierrors.Wrap(
+			err,
+			"target app doesn't exist",
+		)
+	}
+
+	l.Info("replacing old Template with the new one")
+	parentApp.Spec.Templates[template.Meta.Name] = template
+	return nil
+}
+
+// Instances returns the scopes of the dApps created from the given Template,
+// which are annotated with its path
+func (tmm *TemplateMemoryManager) Instances(scope, name string) ([]string, error) {
+	path, err := utils.JoinScopes(scope, name)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := tmm.Apps().Get("")
+	if err != nil {
+		return nil, err
+	}
+	return utils.TemplateInstances(root, "", path), nil
+}
+
+// TemplatePermTreeGetter returns a getter that gets Templates from the root structure of the app, without the current changes.
+// The getter does not allow changes in the structure, just visualization.
+type TemplatePermTreeGetter struct {
+	*PermTreeGetter
+	logs *zap.Logger
+}
+
+// Get receives a scope and a name and returns the Template of that name in
+// the dApp of the given scope, as it is in the cluster, before any modifications.
+func (trg *TemplatePermTreeGetter) Get(scope, name string) (*meta.Template, error) {
+	l := trg.logs.With(
+		zap.String("operation", "get-root"),
+		zap.String("template", name),
+		zap.String("scope", scope),
+	)
+	l.Info("received request for template recovery")
+
+	parentApp, err := trg.Apps().Get(scope)
+	if err != nil {
+		l.Info("unable to find parent dapp")
+		return nil, ierrors.Wrap(
+			err,
+			"target dApp does not exist on root",
+		)
+	}
+
+	if t, ok := parentApp.Spec.Templates[name]; ok {
+		return t, nil
+	}
+
+	l.Info("unable to get Template in given scope (root-tree)")
+	return nil, ierrors.New("Template not found for given query on root").NotFound()
+}
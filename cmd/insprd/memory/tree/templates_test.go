@@ -0,0 +1,156 @@
+package tree
+
+import (
+	"testing"
+
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	metautils "inspr.dev/inspr/pkg/meta/utils"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+)
+
+func mockTemplate(image string) *meta.Template {
+	return &meta.Template{
+		Meta:       meta.Metadata{Name: "pipeline"},
+		Parameters: []meta.TemplateParameter{{Name: "customer", Required: true}},
+		App: meta.App{
+			Spec: meta.AppSpec{
+				Node: meta.Node{Spec: meta.NodeSpec{Image: image}},
+			},
+		},
+	}
+}
+
+func TestTemplateMemoryManager_Create(t *testing.T) {
+	tests := []struct {
+		name     string
+		template *meta.Template
+		wantCode ierrors.ErrCode
+	}{
+		{
+			name:     "valid template",
+			template: mockTemplate("{{ .customer }}/image"),
+		},
+		{
+			name: "invalid template name",
+			template: func() *meta.Template {
+				tmpl := mockTemplate("image")
+				tmpl.Meta.Name = "pipe.line"
+				return tmpl
+			}(),
+			wantCode: ierrors.BadRequest,
+		},
+		{
+			name:     "template with an undefined parameter",
+			template: mockTemplate("{{ .region }}/image"),
+			wantCode: ierrors.BadRequest,
+		},
+		{
+			name: "template that already exists",
+			template: func() *meta.Template {
+				tmpl := mockTemplate("image")
+				tmpl.Meta.Name = "existing"
+				return tmpl
+			}(),
+			wantCode: ierrors.AlreadyExists,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmm := newTreeMemory()
+			tmm.tree.Spec.Templates = map[string]*meta.Template{
+				"existing": {Meta: meta.Metadata{Name: "existing"}},
+			}
+			tmm.InitTransaction()
+			defer tmm.Cancel()
+
+			err := tmm.Templates().Create("", tt.template)
+			if tt.wantCode != 0 {
+				if !ierrors.HasCode(err, tt.wantCode) {
+					t.Errorf("TemplateMemoryManager.Create() error = %v, want code %v", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TemplateMemoryManager.Create() error = %v", err)
+			}
+
+			got, err := tmm.Templates().Get("", tt.template.Meta.Name)
+			if err != nil || got.Meta.UUID == "" {
+				t.Errorf("TemplateMemoryManager.Get() = %v, %v, want the created template", got, err)
+			}
+			if _, err = tmm.Perm().Templates().Get("", tt.template.Meta.Name); err == nil {
+				t.Errorf("TemplatePermTreeGetter.Get() found a template of the running transaction")
+			}
+
+			changes, _ := tmm.GetTransactionChanges()
+			if len(changes) != 1 || changes[0].Kind&diff.TemplateKind == 0 {
+				t.Errorf("treeMemoryManager.GetTransactionChanges() = %v, want the template creation", changes)
+			}
+		})
+	}
+}
+
+func TestTemplateMemoryManager_Update(t *testing.T) {
+	tmm := newTreeMemory()
+	tmm.InitTransaction()
+	if err := tmm.Templates().Create("", mockTemplate("image:v1")); err != nil {
+		t.Fatalf("TemplateMemoryManager.Create() error = %v", err)
+	}
+	if err := tmm.Commit(""); err != nil {
+		t.Fatalf("treeMemoryManager.Commit() error = %v", err)
+	}
+	committed, _ := tmm.Perm().Templates().Get("", "pipeline")
+
+	tmm.InitTransaction()
+	defer tmm.Cancel()
+
+	outdated := mockTemplate("image:v2")
+	outdated.Meta.ResourceVersion = committed.Meta.ResourceVersion + 1
+	if err := tmm.Templates().Update("", outdated); !ierrors.HasCode(err, ierrors.Conflict) {
+		t.Errorf("TemplateMemoryManager.Update() error = %v, want a conflict", err)
+	}
+
+	err := tmm.Templates().Update("", mockTemplate("image:v2"))
+	if err != nil {
+		t.Fatalf("TemplateMemoryManager.Update() error = %v", err)
+	}
+	got, _ := tmm.Templates().Get("", "pipeline")
+	if got.App.Spec.Node.Spec.Image != "image:v2" || got.Meta.UUID != committed.Meta.UUID {
+		t.Errorf("TemplateMemoryManager.Update() = %v, want the new image and the same UUID", got)
+	}
+	if committed.App.Spec.Node.Spec.Image != "image:v1" {
+		t.Errorf("TemplateMemoryManager.Update() changed the committed template")
+	}
+}
+
+func TestTemplateMemoryManager_Delete(t *testing.T) {
+	tmm := newTreeMemory()
+	tmm.tree.Spec.Templates = map[string]*meta.Template{
+		"pipeline": mockTemplate("image"),
+	}
+	tmm.InitTransaction()
+	defer tmm.Cancel()
+
+	tmm.root.Spec.Apps["acme"] = &meta.App{
+		Meta: meta.Metadata{
+			Name:        "acme",
+			Annotations: map[string]string{metautils.TemplateAnnotation: "pipeline"},
+		},
+	}
+	instances, err := tmm.Templates().Instances("", "pipeline")
+	if err != nil || len(instances) != 1 || instances[0] != "acme" {
+		t.Errorf("TemplateMemoryManager.Instances() = %v, %v, want [acme]", instances, err)
+	}
+	if err = tmm.Templates().Delete("", "pipeline"); !ierrors.HasCode(err, ierrors.BadRequest) {
+		t.Errorf("TemplateMemoryManager.Delete() error = %v, want it refused while there are instances", err)
+	}
+
+	delete(tmm.root.Spec.Apps, "acme")
+	if err = tmm.Templates().Delete("", "pipeline"); err != nil {
+		t.Errorf("TemplateMemoryManager.Delete() error = %v", err)
+	}
+	if _, err = tmm.Templates().Get("", "pipeline"); !ierrors.HasCode(err, ierrors.NotFound) {
+		t.Errorf("TemplateMemoryManager.Get() error = %v, want the template deleted", err)
+	}
+}
@@ -1,11 +1,11 @@
 package tree
 
 import (
-	"github.com/linkedin/goavro"
 	"go.uber.org/zap"
 	"inspr.dev/inspr/pkg/ierrors"
 	"inspr.dev/inspr/pkg/meta"
 	"inspr.dev/inspr/pkg/meta/utils"
+	"inspr.dev/inspr/pkg/sidecars/codecs"
 )
 
 // TypeMemoryManager implements the Type interface
@@ -42,7 +42,7 @@ func (tmm *TypeMemoryManager) Create(scope string, insprType *meta.Type) error {
 		return err
 	}
 
-	err = validAvroSchema(insprType.Schema)
+	err = validSchema(insprType)
 	if err != nil {
 		l.Error("invalid Type schema")
 		return err
@@ -73,6 +73,7 @@ func (tmm *TypeMemoryManager) Create(scope string, insprType *meta.Type) error {
 		parentApp.Spec.Types = map[string]*meta.Type{}
 	}
 	insprType.Meta = utils.InjectUUID(insprType.Meta)
+	insprType.Versions = utils.TypeHistory(insprType)
 	parentApp.Spec.Types[insprType.Meta.Name] = insprType
 	l.Debug("type created")
 	return nil
@@ -99,9 +100,9 @@ func (tmm *TypeMemoryManager) Get(scope, name string) (*meta.Type, error) {
 	}
 
 	if parentApp.Spec.Types != nil {
-		if insprType, ok := parentApp.Spec.Types[name]; ok {
+		if _, ok := parentApp.Spec.Types[name]; ok {
 			l.Debug("recovered type, returning value")
-			return insprType, nil
+			return tmm.writableType(parentApp, name), nil
 		}
 	}
 
@@ -173,6 +174,19 @@ func (tmm *TypeMemoryManager) Update(scope string, insprType *meta.Type) error {
 		).BadRequest()
 	}
 
+	err = utils.ValidateResourceVersion(oldChType.Meta, insprType.Meta.ResourceVersion)
+	if err != nil {
+		l.Debug("outdated Type resource version", zap.Int("version", oldChType.Meta.ResourceVersion))
+		return err
+	}
+
+	err = utils.CheckTypeCompatibility(oldChType, insprType)
+	if err != nil {
+		l.Debug("incompatible Type schema", zap.Error(err))
+		return err
+	}
+
+	utils.AddTypeVersion(oldChType, insprType)
 	insprType.ConnectedChannels = oldChType.ConnectedChannels
 	insprType.Meta.UUID = oldChType.Meta.UUID
 
@@ -229,10 +243,7 @@ func (trg *TypePermTreeGetter) Get(scope, name string) (*meta.Type, error) {
 	return nil, ierrors.New("Type not found for given query on root").NotFound()
 }
 
-func validAvroSchema(schema string) error {
-	_, err := goavro.NewCodec(schema)
-	if err != nil {
-		return ierrors.Wrap(err, "invalid avro schema")
-	}
-	return nil
+func validSchema(insprType *meta.Type) error {
+	_, err := codecs.New(insprType.Format, insprType.Schema)
+	return err
 }
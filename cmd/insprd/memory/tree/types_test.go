@@ -219,7 +219,65 @@ func TestTypeMemoryManager_Create(t *testing.T) {
 					UUID:        "",
 				},
 				Schema: "{\"type\":\"string\"}",
+				Versions: []meta.TypeVersion{
+					{Version: 1, Schema: "{\"type\":\"string\"}"},
+				},
+			},
+		},
+		{
+			name: "Creating a new Type with a JSON Schema",
+			fields: fields{
+				root:   getMockTypes(),
+				appErr: nil,
+				mockA:  true,
+				mockC:  true,
+				mockCT: false,
+			},
+			args: args{
+				ct: &meta.Type{
+					Meta: meta.Metadata{
+						Name:        "ct5",
+						Annotations: map[string]string{},
+					},
+					Format: meta.FormatJSONSchema,
+					Schema: "{\"type\":\"object\"}",
+				},
+				context: "",
+			},
+			wantErr: false,
+			want: &meta.Type{
+				Meta: meta.Metadata{
+					Name:        "ct5",
+					Annotations: map[string]string{},
+				},
+				Format: meta.FormatJSONSchema,
+				Schema: "{\"type\":\"object\"}",
+				Versions: []meta.TypeVersion{
+					{Version: 1, Schema: "{\"type\":\"object\"}"},
+				},
+			},
+		},
+		{
+			name: "Trying to create a new Type with an unknown format",
+			fields: fields{
+				root:   getMockTypes(),
+				appErr: nil,
+				mockA:  true,
+				mockC:  true,
+				mockCT: false,
+			},
+			args: args{
+				ct: &meta.Type{
+					Meta: meta.Metadata{
+						Name:        "ct6",
+						Annotations: map[string]string{},
+					},
+					Format: "xml",
+					Schema: "<schema/>",
+				},
+				context: "",
 			},
+			wantErr: true,
 		},
 		{
 			name: "Trying to create an old Type on a valid app",
@@ -508,6 +566,10 @@ func TestTypeMemoryManager_Update(t *testing.T) {
 					UUID:        "",
 				},
 				Schema: string([]byte{0, 1, 0, 1}),
+				Versions: []meta.TypeVersion{
+					{Version: 1, Schema: ""},
+					{Version: 2, Schema: string([]byte{0, 1, 0, 1})},
+				},
 			},
 		},
 		{
@@ -656,3 +718,71 @@ func getMockTypes() *meta.App {
 	}
 	return &root
 }
+
+func TestTypeMemoryManager_UpdateCompatibility(t *testing.T) {
+	current := `{"type":"record","name":"User","fields":[{"name":"name","type":"string"}]}`
+	tests := []struct {
+		name        string
+		schema      string
+		annotations map[string]string
+		wantErr     bool
+	}{
+		{
+			name:   "backward compatible schema",
+			schema: `{"type":"record","name":"User","fields":[{"name":"name","type":"string"},{"name":"age","type":"int","default":0}]}`,
+		},
+		{
+			name:    "backward incompatible schema",
+			schema:  `{"type":"record","name":"User","fields":[{"name":"name","type":"string"},{"name":"age","type":"int"}]}`,
+			wantErr: true,
+			annotations: map[string]string{
+				metautils.SchemaCompatibilityAnnotation: metautils.CompatibilityBackward,
+			},
+		},
+		{
+			name:   "unchecked schema by default",
+			schema: `{"type":"record","name":"User","fields":[{"name":"name","type":"string"},{"name":"age","type":"int"}]}`,
+		},
+		{
+			name:    "forward incompatible schema",
+			schema:  `{"type":"record","name":"User","fields":[]}`,
+			wantErr: true,
+			annotations: map[string]string{
+				metautils.SchemaCompatibilityAnnotation: metautils.CompatibilityForward,
+			},
+		},
+		{
+			name:   "unchecked schema",
+			schema: `{"type":"string"}`,
+			annotations: map[string]string{
+				metautils.SchemaCompatibilityAnnotation: metautils.CompatibilityNone,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmm := newTreeMemory()
+			tmm.tree.Spec.Types = map[string]*meta.Type{
+				"user": {Meta: meta.Metadata{Name: "user"}, Schema: current},
+			}
+			tmm.InitTransaction()
+			defer tmm.Cancel()
+
+			err := tmm.Types().Update("", &meta.Type{
+				Meta:   meta.Metadata{Name: "user", Annotations: tt.annotations},
+				Schema: tt.schema,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TypeMemoryManager.Update() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !ierrors.HasCode(err, ierrors.BadRequest) {
+				t.Errorf("TypeMemoryManager.Update() error = %v, want a bad request", err)
+			}
+
+			got, _ := tmm.Types().Get("", "user")
+			if tt.wantErr && got.Schema != current {
+				t.Errorf("TypeMemoryManager.Update() changed the schema of an incompatible Type")
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package tree
+
+import (
+	"reflect"
+
+	"inspr.dev/inspr/pkg/meta"
+)
+
+// stampVersions sets the resource versions of the given app and of everything
+// under it, based on the app it replaces, which is nil if the app is new.
+// Components that didn't change keep their versions, the ones that did have
+// them increased, and new ones start at 1. An app is taken as changed whenever
+// anything under it changes. It returns whether the app changed. Components
+// shared with the previous tree are left untouched, since they can't change.
+func stampVersions(prev, curr *meta.App) bool {
+	if prev == curr {
+		return false
+	}
+	if prev == nil {
+		prev = &meta.App{}
+	}
+
+	changed := !reflect.DeepEqual(shallowApp(prev), shallowApp(curr))
+
+	for name, app := range curr.Spec.Apps {
+		changed = stampVersions(prev.Spec.Apps[name], app) || changed
+	}
+	for name, ch := range curr.Spec.Channels {
+		changed = stampVersion(prev.Spec.Channels[name], ch, &ch.Meta) || changed
+	}
+	for name, t := range curr.Spec.Types {
+		changed = stampVersion(prev.Spec.Types[name], t, &t.Meta) || changed
+	}
+	for name, alias := range curr.Spec.Aliases {
+		changed = stampVersion(prev.Spec.Aliases[name], alias, &alias.Meta) || changed
+	}
+	for name, t := range curr.Spec.Templates {
+		changed = stampVersion(prev.Spec.Templates[name], t, &t.Meta) || changed
+	}
+
+	changed = changed ||
+		len(prev.Spec.Apps) != len(curr.Spec.Apps) ||
+		len(prev.Spec.Channels) != len(curr.Spec.Channels) ||
+		len(prev.Spec.Types) != len(curr.Spec.Types) ||
+		len(prev.Spec.Aliases) != len(curr.Spec.Aliases) ||
+		len(prev.Spec.Templates) != len(curr.Spec.Templates)
+
+	curr.Meta.ResourceVersion = nextVersion(prev.Meta, changed)
+	return changed
+}
+
+// stampVersion sets the resource version of a channel, type, alias or template, given
+// the component it replaces, and returns whether the component changed
+func stampVersion(prev, curr interface{}, currMeta *meta.Metadata) bool {
+	if prev == curr {
+		return false
+	}
+	prevMeta, isNew := componentMeta(prev)
+	currMeta.ResourceVersion = prevMeta.ResourceVersion
+
+	changed := isNew || !reflect.DeepEqual(prev, curr)
+	currMeta.ResourceVersion = nextVersion(prevMeta, changed)
+	return changed
+}
+
+// nextVersion returns the resource version that follows the given metadata
+func nextVersion(prev meta.Metadata, changed bool) int {
+	if changed {
+		return prev.ResourceVersion + 1
+	}
+	return prev.ResourceVersion
+}
+
+// shallowApp returns a copy of the given app without its versioned
+// components, so that only the app's own fields are compared
+func shallowApp(app *meta.App) meta.App {
+	shallow := *app
+	shallow.Meta.ResourceVersion = 0
+	shallow.Spec.Apps = nil
+	shallow.Spec.Channels = nil
+	shallow.Spec.Types = nil
+	shallow.Spec.Aliases = nil
+	shallow.Spec.Templates = nil
+	return shallow
+}
+
+// componentMeta returns the metadata of the given component,
+// and whether it doesn't exist, i.e. it's a nil pointer
+func componentMeta(component interface{}) (meta.Metadata, bool) {
+	switch c := component.(type) {
+	case *meta.Channel:
+		if c != nil {
+			return c.Meta, false
+		}
+	case *meta.Type:
+		if c != nil {
+			return c.Meta, false
+		}
+	case *meta.Alias:
+		if c != nil {
+			return c.Meta, false
+		}
+	case *meta.Template:
+		if c != nil {
+			return c.Meta, false
+		}
+	}
+	return meta.Metadata{}, true
+}
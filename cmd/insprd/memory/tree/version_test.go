@@ -0,0 +1,94 @@
+package tree
+
+import (
+	"testing"
+
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+)
+
+// versionsOf returns the resource versions of the root, of app1 and of its components
+func versionsOf(tree *meta.App) [4]int {
+	app := tree.Spec.Apps["app1"]
+	return [4]int{
+		tree.Meta.ResourceVersion,
+		app.Meta.ResourceVersion,
+		app.Spec.Channels["ch1"].Meta.ResourceVersion,
+		app.Spec.Types["t1"].Meta.ResourceVersion,
+	}
+}
+
+func TestTreeMemoryManager_Commit_versions(t *testing.T) {
+	tmm := newTreeMemory()
+
+	tmm.InitTransaction()
+	tmm.root.Spec.Apps["app1"] = &meta.App{
+		Meta: meta.Metadata{Name: "app1"},
+		Spec: meta.AppSpec{
+			Channels: map[string]*meta.Channel{
+				"ch1": {
+					Meta: meta.Metadata{Name: "ch1", ResourceVersion: 7},
+					Spec: meta.ChannelSpec{Type: "t1"},
+				},
+			},
+			Types: map[string]*meta.Type{
+				"t1": {Meta: meta.Metadata{Name: "t1"}},
+			},
+		},
+	}
+	tmm.Commit("user1")
+	if got, want := versionsOf(tmm.tree), [4]int{1, 1, 1, 1}; got != want {
+		t.Errorf("new components versions = %v, want %v", got, want)
+	}
+
+	tmm.InitTransaction()
+	tmm.Commit("user1")
+	if got, want := versionsOf(tmm.tree), [4]int{1, 1, 1, 1}; got != want {
+		t.Errorf("unchanged components versions = %v, want %v", got, want)
+	}
+
+	tmm.InitTransaction()
+	ch, _ := tmm.Channels().Get("app1", "ch1")
+	ch.Meta.Annotations = map[string]string{"k": "v"}
+	tmm.Commit("user1")
+	if got, want := versionsOf(tmm.tree), [4]int{2, 2, 2, 1}; got != want {
+		t.Errorf("changed channel versions = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		name    string
+		version int
+		wantErr bool
+	}{
+		{
+			name:    "rejects an update with an outdated version",
+			version: 1,
+			wantErr: true,
+		},
+		{
+			name:    "updates with the current version",
+			version: 2,
+		},
+		{
+			name:    "updates without a version",
+			version: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmm.InitTransaction()
+			defer tmm.Cancel()
+
+			err := tmm.Channels().Update("app1", &meta.Channel{
+				Meta: meta.Metadata{Name: "ch1", ResourceVersion: tt.version},
+				Spec: meta.ChannelSpec{Type: "t1"},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ChannelMemoryManager.Update() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !ierrors.HasCode(err, ierrors.Conflict) {
+				t.Errorf("ChannelMemoryManager.Update() error = %v, want Conflict", err)
+			}
+		})
+	}
+}
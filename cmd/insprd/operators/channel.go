@@ -8,6 +8,7 @@ import (
 	"inspr.dev/inspr/cmd/insprd/memory/brokers"
 	"inspr.dev/inspr/cmd/insprd/memory/tree"
 	kafkaop "inspr.dev/inspr/cmd/insprd/operators/kafka"
+	memoryop "inspr.dev/inspr/cmd/insprd/operators/memory"
 	"inspr.dev/inspr/cmd/sidecars"
 	"inspr.dev/inspr/pkg/ierrors"
 	"inspr.dev/inspr/pkg/meta"
@@ -37,18 +38,18 @@ func NewGeneralOperator(brokers brokers.Manager, memory tree.Manager) *GenOp {
 }
 
 func (g GenOp) getOperator(scope, name string, deleteCmd bool) (ChannelOperatorInterface, error) {
-	var channel *meta.Channel
-	var err error
-
 	// delete should get it's channel information from the unaltered tree,
 	// otherwise the channel wouldnt be found
+	get, fallback := g.memory.Channels().Get, g.memory.Perm().Channels().Get
 	if deleteCmd {
-		channel, err = g.memory.Perm().Channels().Get(scope, name)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		channel, err = g.memory.Channels().Get(scope, name)
+		get, fallback = fallback, get
+	}
+
+	channel, err := get(scope, name)
+	if err != nil {
+		// the channel exists only in the other tree when a reaction is undone,
+		// such as deleting a channel created in the current transaction
+		channel, err = fallback(scope, name)
 		if err != nil {
 			return nil, err
 		}
@@ -85,6 +86,18 @@ func (g GenOp) setOperator(config metabrokers.BrokerConfiguration) error {
 				op:     operator,
 			}
 		}
+	case metabrokers.Memory:
+		memoryConfig := config.(*sidecars.MemoryConfig)
+		operator, err := memoryop.NewOperator(g.memory, *memoryConfig)
+		if err == nil {
+			g.configs[config.Broker()] = struct {
+				config metabrokers.BrokerConfiguration
+				op     ChannelOperatorInterface
+			}{
+				config: config,
+				op:     operator,
+			}
+		}
 	default:
 		err = ierrors.New("")
 	}
@@ -139,3 +152,15 @@ func (g GenOp) Delete(ctx context.Context, scope, name string) error {
 	}
 	return op.Delete(ctx, scope, name)
 }
+
+//Messages executes Messages method of correct operator given the desired channel's broker
+func (g GenOp) Messages(ctx context.Context, scope, name string, limit int) ([][]byte, error) {
+	logger.Info("operator trying to read messages of channel",
+		zap.Any("channel", name),
+		zap.Any("scope", scope))
+	op, err := g.getOperator(scope, name, false)
+	if err != nil {
+		return nil, err
+	}
+	return op.Messages(ctx, scope, name, limit)
+}
@@ -12,6 +12,7 @@ import (
 // ChannelOperator mock
 type ChannelOperator struct {
 	channels map[string]*meta.Channel
+	messages map[string][][]byte
 	err      error
 }
 
@@ -19,10 +20,16 @@ type ChannelOperator struct {
 func NewChannelOperator(err error) operators.ChannelOperatorInterface {
 	return ChannelOperator{
 		channels: make(map[string]*meta.Channel),
+		messages: make(map[string][][]byte),
 		err:      err,
 	}
 }
 
+// WriteMessages mock, adds messages to a channel so that they're returned by Messages
+func (o ChannelOperator) WriteMessages(context string, name string, messages ...[]byte) {
+	o.messages[context+name] = append(o.messages[context+name], messages...)
+}
+
 // Create mock
 func (o ChannelOperator) Create(ctx context.Context, context string, ch *meta.Channel) error {
 	if o.err != nil {
@@ -77,6 +84,24 @@ func (o ChannelOperator) Delete(ctx context.Context, context string, name string
 	return nil
 }
 
+// Messages mock
+func (o ChannelOperator) Messages(ctx context.Context, context string, name string, limit int) ([][]byte, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+
+	channelKey := context + name
+	if _, ok := o.channels[channelKey]; !ok {
+		return nil, ierrors.New("channel %s not found", channelKey).NotFound()
+	}
+
+	messages := o.messages[channelKey]
+	if limit > 0 && len(messages) > limit {
+		messages = messages[len(messages)-limit:]
+	}
+	return messages, nil
+}
+
 // GetAll mock
 func (o ChannelOperator) GetAll(_ context.Context, context string) (ret []*meta.Channel, err error) {
 
@@ -29,6 +29,7 @@ func NewFakeOperator() operators.OperatorInterface {
 		},
 		channels: &ChannelOperator{
 			channels: make(map[string]*meta.Channel),
+			messages: make(map[string][][]byte),
 		},
 	}
 }
@@ -9,6 +9,7 @@ import (
 // NodeOperatorInterface is the interface that allows to obtain or change
 // node information inside a deployment
 type NodeOperatorInterface interface {
+	GetNode(ctx context.Context, app *meta.App) (*meta.Node, error)
 	CreateNode(ctx context.Context, app *meta.App) (*meta.Node, error)
 	UpdateNode(ctx context.Context, app *meta.App) (*meta.Node, error)
 	DeleteNode(ctx context.Context, scope string, name string) error
@@ -21,11 +22,13 @@ type NodeOperatorInterface interface {
 // 	- `Create`: creates a channel in the DApp of the given context
 // 	- `Update`: updates a channel in the DApp of the given context
 // 	- `Delete`: deletes a channel of the specified name in DApp of the given context
+// 	- `Messages`: returns the latest messages of a channel, as they are in the broker, without consuming them
 type ChannelOperatorInterface interface {
 	Get(ctx context.Context, scope string, name string) (*meta.Channel, error)
 	Create(ctx context.Context, scope string, channel *meta.Channel) error
 	Update(ctx context.Context, scope string, channel *meta.Channel) error
 	Delete(ctx context.Context, scope string, name string) error
+	Messages(ctx context.Context, scope string, name string, limit int) ([][]byte, error)
 }
 
 // OperatorInterface is an interface for inspr runtime operators
@@ -3,6 +3,7 @@ package kafkaop
 import (
 	"context"
 	"os"
+	"strings"
 
 	"go.uber.org/zap"
 	"gopkg.in/confluentinc/confluent-kafka-go.v1/kafka"
@@ -24,9 +25,10 @@ func init() {
 
 // ChannelOperator is a client for channel operations on kafka
 type ChannelOperator struct {
-	k      kafkaAdminClient
-	logger *zap.Logger
-	mem    tree.Manager
+	k        kafkaAdminClient
+	messages kafkaMessageReader
+	logger   *zap.Logger
+	mem      tree.Manager
 }
 
 // NewOperator returns an initialized operator from the environment variables
@@ -35,9 +37,11 @@ func NewOperator(mem tree.Manager, config sidecars.KafkaConfig) (*ChannelOperato
 	var kafkaConfig *kafka.ConfigMap
 	var err error
 	var adminClient kafkaAdminClient
+	var messageReader kafkaMessageReader
 	if _, exists := os.LookupEnv("DEBUG"); exists {
 		logger.Debug("initializing kafka admin with debug configs")
 		adminClient = &mockAdminClient{}
+		messageReader = &mockMessageReader{}
 	} else {
 		bootstrap := config.BootstrapServers
 		logger.Debug("initializing kafka admin with production configs",
@@ -51,26 +55,32 @@ func NewOperator(mem tree.Manager, config sidecars.KafkaConfig) (*ChannelOperato
 			logger.Error("unable to create kafka admin client", zap.Error(err))
 			return nil, err
 		}
+		messageReader = &topicReader{bootstrapServers: bootstrap}
 	}
 
 	return &ChannelOperator{
-		k:      adminClient,
-		logger: logger,
-		mem:    mem,
+		k:        adminClient,
+		messages: messageReader,
+		logger:   logger,
+		mem:      mem,
 	}, err
 }
 
-// Get gets a channel from kafka
+// Get gets a channel from kafka, returning a NotFound error if its topic doesn't exist
 func (c *ChannelOperator) Get(ctx context.Context, context string, name string) (*meta.Channel, error) {
-	channel, _ := c.mem.Perm().Channels().Get(context, name)
 	l := logger.With(
 		zap.String("channel", name),
 		zap.String("context", context))
 
 	l.Debug("trying to get Channel from Kafka Topic")
+	channel, err := c.mem.Perm().Channels().Get(context, name)
+	if err != nil {
+		l.Error("unable to get Channel from memory", zap.Error(err))
+		return nil, err
+	}
 
 	topic := toTopic(channel)
-	meta, err := c.k.GetMetadata(&topic, false, 1000)
+	metadata, err := c.k.GetMetadata(&topic, false, 1000)
 	if err != nil {
 		l.Error("unable to get Kafka Topic", zap.Error(err))
 		return nil, ierrors.Wrap(
@@ -79,11 +89,17 @@ func (c *ChannelOperator) Get(ctx context.Context, context string, name string)
 		)
 	}
 
-	return fromTopic(name, meta), err
+	topicMetadata, ok := metadata.Topics[topic]
+	if !ok || topicMetadata.Error.Code() == kafka.ErrUnknownTopicOrPart {
+		l.Debug("Kafka Topic not found", zap.String("topic", topic))
+		return nil, ierrors.New("kafka topic %v not found", topic).NotFound()
+	}
 
+	return fromTopic(channel, topicMetadata), nil
 }
 
-// GetAll gets all channels from kafka
+// GetAll gets all channels from kafka. As the name of their topics is
+// the only information kafka has on them, only their UUIDs are set.
 func (c *ChannelOperator) GetAll(ctx context.Context, context string) (ret []*meta.Channel, err error) {
 	logger.Info("trying to get all Channels from Kafka Topics",
 		zap.String("context", context))
@@ -96,9 +112,17 @@ func (c *ChannelOperator) GetAll(ctx context.Context, context string) (ret []*me
 			"unable to get topics from kafka",
 		)
 	}
-	for _, topic := range metas.Topics {
-		ch := fromTopic(topic.Topic, metas)
-		ret = append(ret, ch)
+	for name, topic := range metas.Topics {
+		if !strings.HasPrefix(name, topicPrefix) {
+			continue
+		}
+		ch := &meta.Channel{
+			Meta: meta.Metadata{
+				Name: name,
+				UUID: strings.TrimPrefix(name, topicPrefix),
+			},
+		}
+		ret = append(ret, fromTopic(ch, topic))
 	}
 	return
 }
@@ -146,13 +170,21 @@ func (c *ChannelOperator) Update(ctx context.Context, context string, channel *m
 
 // Delete deletes a channel from kafka
 func (c *ChannelOperator) Delete(ctx context.Context, context string, name string) error {
-	channel, _ := c.mem.Perm().Channels().Get(context, name)
+	channel, err := c.mem.Perm().Channels().Get(context, name)
+	if err != nil {
+		// the channel may have been created in the current transaction,
+		// when its creation is being undone
+		channel, err = c.mem.Channels().Get(context, name)
+		if err != nil {
+			return err
+		}
+	}
 	topics := []string{toTopic(channel)}
 	logger.Info("trying to delete a Channel from Kafka Topics",
 		zap.String("channel", name),
 		zap.String("context", context))
 
-	_, err := c.k.DeleteTopics(ctx, topics)
+	_, err = c.k.DeleteTopics(ctx, topics)
 	if err != nil {
 		logger.Error("error deleting Kafka Topic", zap.Any("error", err))
 		return ierrors.Wrap(
@@ -163,6 +195,32 @@ func (c *ChannelOperator) Delete(ctx context.Context, context string, name strin
 	return nil
 }
 
+// Messages returns the latest messages of a channel's topic, up to the given
+// limit, oldest first. The messages are read without being consumed
+func (c *ChannelOperator) Messages(ctx context.Context, context string, name string, limit int) ([][]byte, error) {
+	l := logger.With(
+		zap.String("channel", name),
+		zap.String("context", context),
+		zap.Int("limit", limit))
+
+	l.Debug("trying to read the messages of a Channel from Kafka")
+	channel, err := c.mem.Perm().Channels().Get(context, name)
+	if err != nil {
+		l.Error("unable to get Channel from memory", zap.Error(err))
+		return nil, err
+	}
+
+	messages, err := c.messages.ReadMessages(ctx, toTopic(channel), limit)
+	if err != nil {
+		l.Error("unable to read the messages of the Kafka Topic", zap.Error(err))
+		return nil, ierrors.Wrap(
+			ierrors.New(err).InternalServer(),
+			"unable to read messages from kafka",
+		)
+	}
+	return messages, nil
+}
+
 type kafkaAdminClient interface {
 	DeleteTopics(ctx context.Context, topics []string, options ...kafka.DeleteTopicsAdminOption) (result []kafka.TopicResult, err error)
 	CreateTopics(ctx context.Context, topics []kafka.TopicSpecification, options ...kafka.CreateTopicsAdminOption) (result []kafka.TopicResult, err error)
@@ -179,6 +237,22 @@ func (*mockAdminClient) DeleteTopics(ctx context.Context, topics []string, optio
 func (*mockAdminClient) CreateTopics(ctx context.Context, topics []kafka.TopicSpecification, options ...kafka.CreateTopicsAdminOption) (result []kafka.TopicResult, err error) {
 	return nil, nil
 }
+
 func (*mockAdminClient) GetMetadata(topic *string, allTopics bool, timeoutMs int) (*kafka.Metadata, error) {
-	return nil, nil
+	metadata := &kafka.Metadata{Topics: map[string]kafka.TopicMetadata{}}
+	if topic != nil {
+		metadata.Topics[*topic] = kafka.TopicMetadata{Topic: *topic}
+	}
+	return metadata, nil
+}
+
+type kafkaMessageReader interface {
+	ReadMessages(ctx context.Context, topic string, limit int) ([][]byte, error)
+}
+
+type mockMessageReader struct {
+}
+
+func (*mockMessageReader) ReadMessages(ctx context.Context, topic string, limit int) ([][]byte, error) {
+	return [][]byte{}, nil
 }
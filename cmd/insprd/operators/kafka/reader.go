@@ -0,0 +1,100 @@
+package kafkaop
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"gopkg.in/confluentinc/confluent-kafka-go.v1/kafka"
+	"inspr.dev/inspr/pkg/ierrors"
+)
+
+// readTimeout bounds the time spent reading the messages of a topic
+const readTimeout = 10 * time.Second
+
+// topicReader reads the latest messages of topics with a consumer that
+// doesn't join a group, so that the offsets of the channel aren't changed
+type topicReader struct {
+	bootstrapServers string
+}
+
+func (r *topicReader) ReadMessages(ctx context.Context, topic string, limit int) ([][]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers":  r.bootstrapServers,
+		"group.id":           "insprd-" + topic,
+		"enable.auto.commit": false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer consumer.Close()
+
+	metadata, err := consumer.GetMetadata(&topic, false, 1000)
+	if err != nil {
+		return nil, err
+	}
+	topicMetadata, ok := metadata.Topics[topic]
+	if !ok {
+		return nil, ierrors.New("kafka topic %v not found", topic).NotFound()
+	}
+
+	// each partition is read from the last 'limit' messages up to its current end
+	assignments := []kafka.TopicPartition{}
+	ends := map[int32]int64{}
+	for _, partition := range topicMetadata.Partitions {
+		low, high, err := consumer.QueryWatermarkOffsets(topic, partition.ID, 1000)
+		if err != nil {
+			return nil, err
+		}
+		start := high - int64(limit)
+		if limit <= 0 || start < low {
+			start = low
+		}
+		if start >= high {
+			continue
+		}
+		assignments = append(assignments, kafka.TopicPartition{
+			Topic:     &topic,
+			Partition: partition.ID,
+			Offset:    kafka.Offset(start),
+		})
+		ends[partition.ID] = high
+	}
+	if err = consumer.Assign(assignments); err != nil {
+		return nil, err
+	}
+
+	read := []*kafka.Message{}
+	for len(ends) > 0 {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		switch event := consumer.Poll(100).(type) {
+		case *kafka.Message:
+			read = append(read, event)
+			partition := event.TopicPartition.Partition
+			if int64(event.TopicPartition.Offset)+1 >= ends[partition] {
+				delete(ends, partition)
+			}
+		case kafka.Error:
+			return nil, event
+		}
+	}
+
+	sort.SliceStable(read, func(i, j int) bool {
+		return read[i].Timestamp.Before(read[j].Timestamp)
+	})
+	if limit > 0 && len(read) > limit {
+		read = read[len(read)-limit:]
+	}
+
+	messages := make([][]byte, len(read))
+	for i, message := range read {
+		messages[i] = message.Value
+	}
+	return messages, nil
+}
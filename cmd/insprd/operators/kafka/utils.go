@@ -2,7 +2,6 @@ package kafkaop
 
 import (
 	"strconv"
-	"strings"
 
 	"go.uber.org/zap"
 	"gopkg.in/confluentinc/confluent-kafka-go.v1/kafka"
@@ -53,26 +52,27 @@ func configFromChannel(ch *meta.Channel) (kafkaConfiguration, error) {
 	return config, nil
 }
 
+// topicPrefix is the prefix of the names of the topics of inspr channels
+const topicPrefix = "INSPR_"
+
 func toTopic(ch *meta.Channel) string {
 	logger.Debug("getting Kafka Topic name given a Channel name and context",
 		zap.String("context", ch.Meta.Parent),
 		zap.String("channel", ch.Meta.Name))
 
-	return "INSPR_" + ch.Meta.UUID
+	return topicPrefix + ch.Meta.UUID
 }
 
-func fromTopic(name string, meta *kafka.Metadata) (ch *meta.Channel) {
-	logger.Debug("getting Channel given a Kafka Topic name",
-		zap.String("topic", name))
+// fromTopic returns a copy of the given channel with the configurations of its topic
+func fromTopic(channel *meta.Channel, topic kafka.TopicMetadata) *meta.Channel {
+	logger.Debug("getting Channel given a Kafka Topic",
+		zap.String("topic", topic.Topic))
 
-	ch.Meta.Annotations["kafka.partition.number"] = strconv.Itoa(len(meta.Topics[name].Partitions))
-	splitName := strings.Split(name, "-")
-	if len(splitName) == 4 {
-		ch.Meta.Name = splitName[3]
-		ch.Meta.Parent = splitName[2]
-	} else if len(splitName) == 3 {
-		ch.Meta.Name = splitName[2]
-		ch.Meta.Parent = splitName[1]
+	ch := *channel
+	ch.Meta.Annotations = map[string]string{}
+	for key, value := range channel.Meta.Annotations {
+		ch.Meta.Annotations[key] = value
 	}
-	return
+	ch.Meta.Annotations["kafka.partition.number"] = strconv.Itoa(len(topic.Partitions))
+	return &ch
 }
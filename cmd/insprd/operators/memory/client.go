@@ -0,0 +1,163 @@
+package memoryop
+
+import (
+	"context"
+	"os"
+
+	"go.uber.org/zap"
+	"inspr.dev/inspr/cmd/insprd/memory/tree"
+	"inspr.dev/inspr/cmd/sidecars"
+	memorysc "inspr.dev/inspr/cmd/sidecars/memory/client"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/logs"
+	"inspr.dev/inspr/pkg/meta"
+)
+
+var logger *zap.Logger
+
+// init is called after all the variable declarations in the package have evaluated
+// their initializers, and those are evaluated only after all the imported packages
+// have been initialized
+func init() {
+	logger, _ = logs.Logger(zap.Fields(zap.String("section", "memory-channel-operator")))
+}
+
+// topicPrefix is the prefix of the names of the topics of inspr channels
+const topicPrefix = "INSPR_"
+
+// ChannelOperator is a client for channel operations on the in-memory broker
+type ChannelOperator struct {
+	topics topicClient
+	mem    tree.Manager
+}
+
+// NewOperator returns an operator of the in-memory broker at the address of
+// the config
+func NewOperator(mem tree.Manager, config sidecars.MemoryConfig) (*ChannelOperator, error) {
+	logger.Debug("initializing operator")
+	var topics topicClient
+	if _, exists := os.LookupEnv("DEBUG"); exists {
+		logger.Debug("initializing in-memory broker embedded in insprd")
+		topics = memorysc.NewBroker()
+	} else {
+		if config.Address == "" {
+			return nil, ierrors.New("the address of the memory broker is empty").BadRequest()
+		}
+		logger.Debug("initializing in-memory broker client",
+			zap.String("memory-broker-address", config.Address))
+		topics = memorysc.NewClient(config.Address)
+	}
+
+	return &ChannelOperator{
+		topics: topics,
+		mem:    mem,
+	}, nil
+}
+
+// Get gets a channel from the in-memory broker, returning a NotFound error if
+// its topic doesn't exist
+func (c *ChannelOperator) Get(ctx context.Context, context string, name string) (*meta.Channel, error) {
+	l := logger.With(
+		zap.String("channel", name),
+		zap.String("context", context))
+
+	l.Debug("trying to get Channel from memory topic")
+	channel, err := c.mem.Perm().Channels().Get(context, name)
+	if err != nil {
+		l.Error("unable to get Channel from memory", zap.Error(err))
+		return nil, err
+	}
+
+	topic := toTopic(channel)
+	if _, err := c.topics.Topic(ctx, topic); err != nil {
+		if ierrors.HasCode(err, ierrors.NotFound) {
+			l.Debug("memory topic not found", zap.String("topic", topic))
+			return nil, ierrors.New("memory topic %v not found", topic).NotFound()
+		}
+		l.Error("unable to get memory topic", zap.Error(err))
+		return nil, ierrors.Wrap(err, "unable to get topic from the memory broker")
+	}
+	return channel, nil
+}
+
+// Create creates a channel in the in-memory broker
+func (c *ChannelOperator) Create(ctx context.Context, context string, channel *meta.Channel) error {
+	logger.Info("trying to create a Channel in the memory broker",
+		zap.String("channel", channel.Meta.Name),
+		zap.String("context", context))
+
+	if err := c.topics.CreateTopic(ctx, toTopic(channel)); err != nil {
+		logger.Error("error creating memory topic", zap.Error(err))
+		return ierrors.Wrap(err, "unable to create memory topic")
+	}
+	return nil
+}
+
+// Update updates a channel in the in-memory broker
+func (c *ChannelOperator) Update(ctx context.Context, context string, channel *meta.Channel) error {
+	logger.Info("trying to update a Channel in the memory broker",
+		zap.String("channel", channel.Meta.Name),
+		zap.String("context", context))
+	// topics of the memory broker have no configuration, so it's enough
+	// that the topic exists
+	return c.Create(ctx, context, channel)
+}
+
+// Delete deletes a channel from the in-memory broker
+func (c *ChannelOperator) Delete(ctx context.Context, context string, name string) error {
+	channel, err := c.mem.Perm().Channels().Get(context, name)
+	if err != nil {
+		// the channel may have been created in the current transaction,
+		// when its creation is being undone
+		channel, err = c.mem.Channels().Get(context, name)
+		if err != nil {
+			return err
+		}
+	}
+	logger.Info("trying to delete a Channel from the memory broker",
+		zap.String("channel", name),
+		zap.String("context", context))
+
+	err = c.topics.DeleteTopic(ctx, toTopic(channel))
+	if err != nil && !ierrors.HasCode(err, ierrors.NotFound) {
+		logger.Error("error deleting memory topic", zap.Error(err))
+		return ierrors.Wrap(err, "unable to delete memory topic")
+	}
+	return nil
+}
+
+// Messages returns the latest messages of a channel's topic, up to the given
+// limit, oldest first. The messages are read without being consumed
+func (c *ChannelOperator) Messages(ctx context.Context, context string, name string, limit int) ([][]byte, error) {
+	l := logger.With(
+		zap.String("channel", name),
+		zap.String("context", context),
+		zap.Int("limit", limit))
+
+	l.Debug("trying to read the messages of a Channel from the memory broker")
+	channel, err := c.mem.Perm().Channels().Get(context, name)
+	if err != nil {
+		l.Error("unable to get Channel from memory", zap.Error(err))
+		return nil, err
+	}
+
+	messages, err := c.topics.Messages(ctx, toTopic(channel), limit)
+	if err != nil {
+		l.Error("unable to read the messages of the memory topic", zap.Error(err))
+		return nil, ierrors.Wrap(err, "unable to read messages from the memory broker")
+	}
+	return messages, nil
+}
+
+func toTopic(ch *meta.Channel) string {
+	return topicPrefix + ch.Meta.UUID
+}
+
+// topicClient manages the topics of the in-memory broker, either through the
+// broker service or on a broker embedded in insprd
+type topicClient interface {
+	CreateTopic(ctx context.Context, topic string) error
+	DeleteTopic(ctx context.Context, topic string) error
+	Topic(ctx context.Context, topic string) (memorysc.TopicInfo, error)
+	Messages(ctx context.Context, topic string, limit int) ([][]byte, error)
+}
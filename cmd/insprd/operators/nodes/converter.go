@@ -1,6 +1,7 @@
 package nodes
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
@@ -10,8 +11,11 @@ import (
 	"inspr.dev/inspr/pkg/auth"
 	"inspr.dev/inspr/pkg/environment"
 	"inspr.dev/inspr/pkg/meta"
+	metabrokers "inspr.dev/inspr/pkg/meta/brokers"
 	metautils "inspr.dev/inspr/pkg/meta/utils"
 	"inspr.dev/inspr/pkg/operator/k8s"
+	"inspr.dev/inspr/pkg/sidecars/models"
+	"inspr.dev/inspr/pkg/sidecars/transport"
 	"inspr.dev/inspr/pkg/utils"
 
 	corev1 "k8s.io/api/core/v1"
@@ -21,6 +25,10 @@ import (
 
 var lbsidecarPort int32
 
+// socketPath is where the volume in which the node and its load balancer
+// sidecar create the Unix sockets they communicate through is mounted
+const socketPath = "/inspr/sockets"
+
 func (no *NodeOperator) dappToService(app *meta.App) *kubeService {
 	logger.Info("creating kubernetes service")
 
@@ -94,6 +102,7 @@ func (no *NodeOperator) dAppToDeployment(app *meta.App, usePermTree bool) *kubeD
 			k8s.WithContainer(
 				append(scContainers, nodeContainer)...,
 			),
+			withSocketVolume(appDeployName),
 			k8s.WithReplicas(app.Spec.Node.Spec.Replicas),
 		))
 }
@@ -102,17 +111,26 @@ func (no *NodeOperator) withAllSidecarsContainers(app *meta.App, appDeployName s
 	var containers []corev1.Container
 	var sidecarAddrs []corev1.EnvVar
 
-	factory, err := no.brokers.Factory().Get("kafka")
-	if err != nil {
-		panic(fmt.Sprintf("broker %v not allowed: %v", "kafka", err))
+	// the LB sidecar is built by the factory of the first configured broker,
+	// and the factories of the others add their configuration to it
+	var factories []models.SidecarFactory
+	for _, broker := range metabrokers.SupportedBrokers {
+		if factory, err := no.brokers.Factory().Get(broker); err == nil && factory != nil {
+			factories = append(factories, factory)
+		}
+	}
+	if len(factories) == 0 {
+		panic(fmt.Sprintf("no broker allowed, supported brokers are %v", metabrokers.SupportedBrokers))
 	}
 
-	lbSidecar, _ := factory(app, nil,
+	lbSidecar, _ := factories[0](app, nil,
 		no.withLBSidecarName(),
 		no.withLBSidecarImage(),
 		no.withBoundary(app, usePermTree),
 		no.withRoutes(app),
 		overwritePortEnvs(app),
+		withTransport(app),
+		withSocketMount(appDeployName),
 		withLBPort(),
 		withLBSidecarConfiguration(),
 		k8s.ContainerWithEnv(sidecarAddrs...),
@@ -124,6 +142,11 @@ func (no *NodeOperator) withAllSidecarsContainers(app *meta.App, appDeployName s
 		k8s.ContainerWithPullPolicy(corev1.PullAlways),
 	)
 
+	for _, factory := range factories[1:] {
+		brokerConfig, _ := factory(app, nil)
+		lbSidecar.Env = append(lbSidecar.Env, brokerConfig.Env...)
+	}
+
 	containers = append(containers, lbSidecar)
 
 	return containers
@@ -215,35 +238,99 @@ func (no *NodeOperator) withBoundary(app *meta.App, usePermTree bool) k8s.Contai
 		channels.Map(func(boundary string) string {
 			resolved := resolvedChannel[boundary]
 			parent, chName, _ := metautils.RemoveLastPartInScope(resolved)
-			var ch *meta.Channel
-			var ct *meta.Type
-			var cterr, cherr error
-			if usePermTree {
-				ch, cherr = no.memory.Perm().Channels().Get(parent, chName)
-				ct, cterr = no.memory.Perm().Types().Get(parent, ch.Spec.Type)
-			} else {
-				ch, cherr = no.memory.Channels().Get(parent, chName)
-				ct, cterr = no.memory.Types().Get(parent, ch.Spec.Type)
-			}
-			if cherr != nil {
-				logger.Error("Unable to get channel to resolve with boundary", zap.String("channel", chName))
-				panic(err)
+			ch := no.channelSchemaEnv(env, parent, chName, usePermTree)
+			env[boundary+"_RESOLVED"] = "INSPR_" + ch.Meta.UUID
+
+			if deadLetter := ch.Spec.DeadLetter; deadLetter != nil && input.Contains(boundary) {
+				// the dead-letter channel is written to by the sidecar as an output
+				// channel that can't be mistaken for a boundary of the dApp
+				dlq := no.channelSchemaEnv(env, parent, deadLetter.Channel, usePermTree)
+				dlqBoundary := boundary + environment.DeadLetterSuffix
+				outputEnv = append(outputEnv, fmt.Sprintf("%s@%s", dlqBoundary, dlq.Spec.SelectedBroker))
+
+				attempts := deadLetter.MaxAttempts
+				if attempts <= 0 {
+					attempts = meta.DefaultDeadLetterAttempts
+				}
+				env[dlqBoundary+"_RESOLVED"] = "INSPR_" + dlq.Meta.UUID
+				env[boundary+"_DEADLETTER"] = dlqBoundary
+				env[boundary+"_DEADLETTER_ATTEMPTS"] = strconv.Itoa(attempts)
 			}
-			if cterr != nil {
-				logger.Error("Unable to get channel type to resolve with boundary", zap.String("type", ch.Spec.Type))
-				panic(err)
+
+			if input.Contains(boundary) {
+				policy, err := metautils.DeliveryPolicyOf(ch)
+				if err != nil {
+					logger.Error("invalid delivery policy of channel", zap.String("channel", chName))
+					panic(err)
+				}
+				if policy != nil {
+					value, _ := json.Marshal(policy)
+					env[boundary+"_DELIVERY_POLICY"] = string(value)
+				}
+
+				if concurrency := ch.Spec.Concurrency; concurrency != nil && concurrency.MaxInFlight > 1 {
+					env[boundary+"_CONCURRENCY"] = strconv.Itoa(concurrency.MaxInFlight)
+					if concurrency.OrderingKey != "" {
+						env[boundary+"_ORDERING_KEY"] = concurrency.OrderingKey
+					}
+				}
+
+				if batch := ch.Spec.Batch; batch != nil && batch.MaxSize > 1 {
+					env[boundary+"_BATCH_SIZE"] = strconv.Itoa(batch.MaxSize)
+					if batch.Linger != "" {
+						env[boundary+"_BATCH_LINGER"] = batch.Linger
+					}
+				}
 			}
-			resolved = "INSPR_" + ch.Meta.UUID
-			env[resolved+"_SCHEMA"] = ct.Schema
-			env[boundary+"_RESOLVED"] = resolved
 			return boundary
 		})
+		env["INSPR_OUTPUT_CHANNELS"] = outputEnv.Join(";")
 		logger.Debug("resolved with Node Boundary", zap.Bool("useperm", usePermTree))
 
 		c.Env = append(c.Env, env.ParseToK8sArrEnv()...)
 	}
 }
 
+// channelSchemaEnv adds the schema of a channel to the environment of the sidecar,
+// under the name the channel is resolved to, and returns the channel
+func (no *NodeOperator) channelSchemaEnv(env utils.EnvironmentMap, parent, chName string, usePermTree bool) *meta.Channel {
+	var ch *meta.Channel
+	var ct *meta.Type
+	var cterr, cherr error
+	if usePermTree {
+		ch, cherr = no.memory.Perm().Channels().Get(parent, chName)
+	} else {
+		ch, cherr = no.memory.Channels().Get(parent, chName)
+	}
+	if cherr != nil {
+		logger.Error("Unable to get channel to resolve with boundary", zap.String("channel", chName))
+		panic(cherr)
+	}
+	if usePermTree {
+		ct, cterr = no.memory.Perm().Types().Get(parent, ch.Spec.Type)
+	} else {
+		ct, cterr = no.memory.Types().Get(parent, ch.Spec.Type)
+	}
+	if cterr != nil {
+		logger.Error("Unable to get channel type to resolve with boundary", zap.String("type", ch.Spec.Type))
+		panic(cterr)
+	}
+	schema, version, verr := metautils.TypeSchema(ct, ch.Spec.TypeVersion)
+	if verr != nil {
+		logger.Error("Unable to get the schema version of the channel type",
+			zap.String("type", ch.Spec.Type), zap.Int("version", ch.Spec.TypeVersion))
+		panic(verr)
+	}
+	history, _ := json.Marshal(metautils.TypeHistory(ct))
+
+	resolved := "INSPR_" + ch.Meta.UUID
+	env[resolved+"_SCHEMA"] = schema
+	env[resolved+"_SCHEMA_FORMAT"] = metautils.TypeFormat(ct)
+	env[resolved+"_SCHEMA_VERSION"] = strconv.Itoa(version)
+	env[resolved+"_SCHEMA_HISTORY"] = string(history)
+	return ch
+}
+
 // withLBSidecarImage adds the sidecar image to the dApp
 func (no *NodeOperator) withLBSidecarImage() k8s.ContainerOption {
 	return func(c *corev1.Container) {
@@ -320,6 +407,8 @@ func createNodeContainer(app *meta.App, appDeployName string) corev1.Container {
 		appDeployName,
 		app.Spec.Node.Spec.Image,
 		overwritePortEnvs(app),
+		withTransport(app),
+		withSocketMount(appDeployName),
 		withNodePort(),
 		withSecretDefinition(app),
 		k8s.ContainerWithEnv(corev1.EnvVar{
@@ -382,6 +471,49 @@ func overwritePortEnvs(app *meta.App) k8s.ContainerOption {
 	}
 }
 
+// withTransport sets the transport through which the node and its load balancer
+// sidecar communicate, when it's defined in the dApp definitions, so that both
+// containers use the same one
+func withTransport(app *meta.App) k8s.ContainerOption {
+	return func(c *corev1.Container) {
+		if app.Spec.Node.Spec.SidecarPort.Transport == "" {
+			return
+		}
+		c.Env = append(c.Env, corev1.EnvVar{
+			Name:  transport.EnvVar,
+			Value: app.Spec.Node.Spec.SidecarPort.Transport,
+		})
+	}
+}
+
+// withSocketVolume adds the in-memory volume shared by the node and its load
+// balancer sidecar, in which they create the Unix sockets they communicate through
+func withSocketVolume(appDeployName string) k8s.DeploymentOption {
+	return k8s.WithVolumes(corev1.Volume{
+		Name: appDeployName + "-sockets",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{
+				Medium: corev1.StorageMediumMemory,
+			},
+		},
+	})
+}
+
+// withSocketMount mounts the volume of the Unix sockets on the container and
+// sets the directory in which they are created
+func withSocketMount(appDeployName string) k8s.ContainerOption {
+	return func(c *corev1.Container) {
+		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+			Name:      appDeployName + "-sockets",
+			MountPath: socketPath,
+		})
+		c.Env = append(c.Env, corev1.EnvVar{
+			Name:  "INSPR_UNIX_SOCKET",
+			Value: socketPath,
+		})
+	}
+}
+
 func withNodeID(app *meta.App) k8s.ContainerOption {
 	return k8s.ContainerWithEnv(corev1.EnvVar{
 		Name:  "INSPR_APP_ID",
@@ -14,6 +14,7 @@ import (
 	authmock "inspr.dev/inspr/pkg/auth/mocks"
 	"inspr.dev/inspr/pkg/meta"
 	"inspr.dev/inspr/pkg/utils"
+	kubeApp "k8s.io/api/apps/v1"
 	kubeCore "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -119,7 +120,48 @@ func TestNodeOperator_withBoundary(t *testing.T) { //TODO needs more testing, lo
 			SelectedBroker: "someBroker",
 		},
 	}, nil)
+	mem.Channels().Create("", &meta.Channel{
+		Meta: meta.Metadata{
+			Name: "channel3",
+			UUID: "channel3_UUID",
+		},
+		Spec: meta.ChannelSpec{
+			Type:           "channel1type",
+			SelectedBroker: "someBroker",
+			DeadLetter:     &meta.DeadLetter{Channel: "failures"},
+			DeliveryPolicy: &meta.DeliveryPolicy{MaxAttempts: 5, Timeout: "2s"},
+			Concurrency:    &meta.Concurrency{MaxInFlight: 4, OrderingKey: "user.id"},
+		},
+	}, nil)
+	mem.Channels().Create("", &meta.Channel{
+		Meta: meta.Metadata{
+			Name: "channel4",
+			UUID: "channel4_UUID",
+		},
+		Spec: meta.ChannelSpec{
+			Type:           "channel1type",
+			SelectedBroker: "someBroker",
+			Batch:          &meta.Batch{MaxSize: 50, Linger: "1s"},
+		},
+	}, nil)
+	mem.Channels().Create("", &meta.Channel{
+		Meta: meta.Metadata{
+			Name: "failures",
+			UUID: "failures_UUID",
+		},
+		Spec: meta.ChannelSpec{
+			Type:           "failuretype",
+			SelectedBroker: "otherBroker",
+		},
+	}, nil)
 
+	mem.Types().Create("", &meta.Type{
+		Meta: meta.Metadata{
+			Name: "failuretype",
+		},
+		Format: meta.FormatJSONSchema,
+		Schema: `{"type":"object"}`,
+	})
 	mem.Types().Create("", &meta.Type{
 		Meta: meta.Metadata{
 			Name: "channel1type",
@@ -194,6 +236,18 @@ func TestNodeOperator_withBoundary(t *testing.T) { //TODO needs more testing, lo
 						Name:  "INSPR_channel1_UUID_SCHEMA",
 						Value: "channel1type",
 					},
+					{
+						Name:  "INSPR_channel1_UUID_SCHEMA_FORMAT",
+						Value: "avro",
+					},
+					{
+						Name:  "INSPR_channel1_UUID_SCHEMA_VERSION",
+						Value: "1",
+					},
+					{
+						Name:  "INSPR_channel1_UUID_SCHEMA_HISTORY",
+						Value: `[{"version":1,"schema":"channel1type"}]`,
+					},
 					{
 						Name:  "channel1_RESOLVED",
 						Value: "INSPR_channel1_UUID",
@@ -202,6 +256,18 @@ func TestNodeOperator_withBoundary(t *testing.T) { //TODO needs more testing, lo
 						Name:  "INSPR_channel2_UUID_SCHEMA",
 						Value: "channel2type",
 					},
+					{
+						Name:  "INSPR_channel2_UUID_SCHEMA_FORMAT",
+						Value: "avro",
+					},
+					{
+						Name:  "INSPR_channel2_UUID_SCHEMA_VERSION",
+						Value: "1",
+					},
+					{
+						Name:  "INSPR_channel2_UUID_SCHEMA_HISTORY",
+						Value: `[{"version":1,"schema":"channel2type"}]`,
+					},
 					{
 						Name:  "channel2_RESOLVED",
 						Value: "INSPR_channel2_UUID",
@@ -256,6 +322,18 @@ func TestNodeOperator_withBoundary(t *testing.T) { //TODO needs more testing, lo
 						Name:  "INSPR_channel1_UUID_SCHEMA",
 						Value: "channel1type",
 					},
+					{
+						Name:  "INSPR_channel1_UUID_SCHEMA_FORMAT",
+						Value: "avro",
+					},
+					{
+						Name:  "INSPR_channel1_UUID_SCHEMA_VERSION",
+						Value: "1",
+					},
+					{
+						Name:  "INSPR_channel1_UUID_SCHEMA_HISTORY",
+						Value: `[{"version":1,"schema":"channel1type"}]`,
+					},
 					{
 						Name:  "channel1_RESOLVED",
 						Value: "INSPR_channel1_UUID",
@@ -264,6 +342,18 @@ func TestNodeOperator_withBoundary(t *testing.T) { //TODO needs more testing, lo
 						Name:  "INSPR_channel2_UUID_SCHEMA",
 						Value: "channel2type",
 					},
+					{
+						Name:  "INSPR_channel2_UUID_SCHEMA_FORMAT",
+						Value: "avro",
+					},
+					{
+						Name:  "INSPR_channel2_UUID_SCHEMA_VERSION",
+						Value: "1",
+					},
+					{
+						Name:  "INSPR_channel2_UUID_SCHEMA_HISTORY",
+						Value: `[{"version":1,"schema":"channel2type"}]`,
+					},
 					{
 						Name:  "channel2_RESOLVED",
 						Value: "INSPR_channel2_UUID",
@@ -271,6 +361,164 @@ func TestNodeOperator_withBoundary(t *testing.T) { //TODO needs more testing, lo
 				},
 			},
 		},
+		{
+			name: "input channel with dead-letter, delivery policy and concurrency",
+			fields: fields{
+				clientSet: kfake.NewSimpleClientset(),
+				memory:    mem,
+			},
+			args: args{
+				app: &meta.App{
+					Meta: meta.Metadata{
+						Name: "app3",
+					},
+					Spec: meta.AppSpec{
+						Boundary: meta.AppBoundary{
+							Channels: meta.Boundary{
+								Input: []string{
+									"channel3",
+								},
+							},
+						},
+					},
+				},
+			},
+			want: &kubeCore.Container{
+				Env: []kubeCore.EnvVar{
+					{
+						Name:  "INSPR_INPUT_CHANNELS",
+						Value: "channel3@someBroker",
+					},
+					{
+						Name:  "INSPR_OUTPUT_CHANNELS",
+						Value: "channel3.deadletter@otherBroker",
+					},
+					{
+						Name:  "INSPR_channel3_UUID_SCHEMA",
+						Value: "channel1type",
+					},
+					{
+						Name:  "INSPR_channel3_UUID_SCHEMA_FORMAT",
+						Value: "avro",
+					},
+					{
+						Name:  "INSPR_channel3_UUID_SCHEMA_VERSION",
+						Value: "1",
+					},
+					{
+						Name:  "INSPR_channel3_UUID_SCHEMA_HISTORY",
+						Value: `[{"version":1,"schema":"channel1type"}]`,
+					},
+					{
+						Name:  "channel3_RESOLVED",
+						Value: "INSPR_channel3_UUID",
+					},
+					{
+						Name:  "INSPR_failures_UUID_SCHEMA",
+						Value: `{"type":"object"}`,
+					},
+					{
+						Name:  "INSPR_failures_UUID_SCHEMA_FORMAT",
+						Value: "jsonschema",
+					},
+					{
+						Name:  "INSPR_failures_UUID_SCHEMA_VERSION",
+						Value: "1",
+					},
+					{
+						Name:  "INSPR_failures_UUID_SCHEMA_HISTORY",
+						Value: `[{"version":1,"schema":"{\"type\":\"object\"}"}]`,
+					},
+					{
+						Name:  "channel3.deadletter_RESOLVED",
+						Value: "INSPR_failures_UUID",
+					},
+					{
+						Name:  "channel3_DEADLETTER",
+						Value: "channel3.deadletter",
+					},
+					{
+						Name:  "channel3_DEADLETTER_ATTEMPTS",
+						Value: "3",
+					},
+					{
+						Name:  "channel3_DELIVERY_POLICY",
+						Value: `{"maxattempts":5,"timeout":"2s"}`,
+					},
+					{
+						Name:  "channel3_CONCURRENCY",
+						Value: "4",
+					},
+					{
+						Name:  "channel3_ORDERING_KEY",
+						Value: "user.id",
+					},
+				},
+			},
+		},
+		{
+			name: "input channel with batch",
+			fields: fields{
+				clientSet: kfake.NewSimpleClientset(),
+				memory:    mem,
+			},
+			args: args{
+				app: &meta.App{
+					Meta: meta.Metadata{
+						Name: "app4",
+					},
+					Spec: meta.AppSpec{
+						Boundary: meta.AppBoundary{
+							Channels: meta.Boundary{
+								Input: []string{
+									"channel4",
+								},
+							},
+						},
+					},
+				},
+			},
+			want: &kubeCore.Container{
+				Env: []kubeCore.EnvVar{
+					{
+						Name:  "INSPR_INPUT_CHANNELS",
+						Value: "channel4@someBroker",
+					},
+					{
+						Name:  "INSPR_OUTPUT_CHANNELS",
+						Value: "",
+					},
+					{
+						Name:  "INSPR_channel4_UUID_SCHEMA",
+						Value: "channel1type",
+					},
+					{
+						Name:  "INSPR_channel4_UUID_SCHEMA_FORMAT",
+						Value: "avro",
+					},
+					{
+						Name:  "INSPR_channel4_UUID_SCHEMA_VERSION",
+						Value: "1",
+					},
+					{
+						Name:  "INSPR_channel4_UUID_SCHEMA_HISTORY",
+						Value: `[{"version":1,"schema":"channel1type"}]`,
+					},
+					{
+						Name:  "channel4_RESOLVED",
+						Value: "INSPR_channel4_UUID",
+					},
+					{
+						Name:  "channel4_BATCH_SIZE",
+						Value: "50",
+					},
+					{
+						Name:  "channel4_BATCH_LINGER",
+						Value: "1s",
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -455,6 +703,53 @@ func Test_withLBSidecarPorts(t *testing.T) {
 	}
 }
 
+func Test_withTransport(t *testing.T) {
+	tests := []struct {
+		name      string
+		transport string
+		want      *kubeCore.Container
+	}{
+		{
+			name:      "grpc transport",
+			transport: "grpc",
+			want: &kubeCore.Container{
+				Env: []kubeCore.EnvVar{
+					{
+						Name:  "INSPR_SIDECAR_TRANSPORT",
+						Value: "grpc",
+					},
+				},
+			},
+		},
+		{
+			name: "transport not defined",
+			want: &kubeCore.Container{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &meta.App{
+				Spec: meta.AppSpec{
+					Node: meta.Node{
+						Spec: meta.NodeSpec{
+							SidecarPort: meta.SidecarPort{
+								Transport: tt.transport,
+							},
+						},
+					},
+				},
+			}
+			option := withTransport(app)
+			got := &kubeCore.Container{}
+			option(got)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("withTransport() got = %v, want = %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestNodeOperator_withLBSidecarImage(t *testing.T) {
 	type args struct {
 	}
@@ -657,3 +952,71 @@ func Test_WithLBPort(t *testing.T) {
 		})
 	}
 }
+
+func Test_withSocketMount(t *testing.T) {
+	tests := []struct {
+		name string
+		want *kubeCore.Container
+	}{
+		{
+			name: "injection",
+			want: &kubeCore.Container{
+				VolumeMounts: []kubeCore.VolumeMount{
+					{
+						Name:      "node-uuid-sockets",
+						MountPath: "/inspr/sockets",
+					},
+				},
+				Env: []kubeCore.EnvVar{
+					{
+						Name:  "INSPR_UNIX_SOCKET",
+						Value: "/inspr/sockets",
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := withSocketMount("node-uuid")
+			got := &kubeCore.Container{}
+			options(got)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("withSocketMount() got = %v, want = %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_withSocketVolume(t *testing.T) {
+	tests := []struct {
+		name string
+		want []kubeCore.Volume
+	}{
+		{
+			name: "injection",
+			want: []kubeCore.Volume{
+				{
+					Name: "node-uuid-sockets",
+					VolumeSource: kubeCore.VolumeSource{
+						EmptyDir: &kubeCore.EmptyDirVolumeSource{
+							Medium: kubeCore.StorageMediumMemory,
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := withSocketVolume("node-uuid")
+			got := &kubeApp.Deployment{}
+			options(got)
+
+			if !reflect.DeepEqual(got.Spec.Template.Spec.Volumes, tt.want) {
+				t.Errorf("withSocketVolume() got = %v, want = %v", got.Spec.Template.Spec.Volumes, tt.want)
+			}
+		})
+	}
+}
@@ -8,8 +8,11 @@ import (
 	"inspr.dev/inspr/cmd/insprd/memory/brokers"
 	"inspr.dev/inspr/cmd/insprd/memory/tree"
 	"inspr.dev/inspr/pkg/auth"
+	"inspr.dev/inspr/pkg/ierrors"
 	"inspr.dev/inspr/pkg/meta"
 	"inspr.dev/inspr/pkg/meta/utils"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
 
 	"k8s.io/client-go/kubernetes"
@@ -47,8 +50,33 @@ func (no *NodeOperator) Deployments() v1.DeploymentInterface {
 	return no.clientSet.AppsV1().Deployments(appsNamespace)
 }
 
+// GetNode returns the node of the given dApp if all of its k8s resources are
+// deployed. Otherwise, returns a NotFound error naming the missing resource.
+func (no *NodeOperator) GetNode(ctx context.Context, app *meta.App) (*meta.Node, error) {
+	logger.Debug("getting a Node structure from k8s",
+		zap.String("node", app.Meta.Name),
+		zap.String("context", app.Meta.Parent))
+
+	name := toDeploymentName(app)
+	_, err := no.Deployments().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fromKubeError(err, "deployment", name)
+	}
+	_, err = no.Services().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fromKubeError(err, "service", name)
+	}
+	_, err = no.Secrets().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fromKubeError(err, "secret", name)
+	}
+
+	return &app.Spec.Node, nil
+}
+
 // CreateNode deploys a new node structure, if it's information is valid.
-// Otherwise, returns an error
+// Otherwise, returns an error. Resources of the node that are already
+// deployed are updated, so that a partially deleted node can be restored.
 func (no *NodeOperator) CreateNode(ctx context.Context, app *meta.App) (*meta.Node, error) {
 	logger.Info("deploying a Node structure in k8s",
 		zap.Any("node", app), zap.String("operation", "create"))
@@ -58,6 +86,9 @@ func (no *NodeOperator) CreateNode(ctx context.Context, app *meta.App) (*meta.No
 			continue
 		}
 		err := applicable.create(no)
+		if k8serrors.IsAlreadyExists(err) {
+			err = applicable.update(no)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -90,17 +121,25 @@ func (no *NodeOperator) DeleteNode(ctx context.Context, nodeContext string, node
 
 	logger.Debug("getting name of the k8s deployment to be deleted")
 	scope, _ := utils.JoinScopes(nodeContext, nodeName)
+	usePermTree := true
 	app, err := no.memory.Perm().Apps().Get(scope)
 	if err != nil {
+		// the node may have been created in the current transaction, when
+		// its creation is being undone
 		logger.Info("Error while getting app inside DeleteNode",
 			zap.String("scope", scope),
 		)
+		usePermTree = false
+		app, err = no.memory.Apps().Get(scope)
+		if err != nil {
+			return err
+		}
 	}
 
 	logger.Debug("deleting a Node structure in k8s",
 		zap.Any("node", app))
 
-	for _, applicable := range no.dappApplications(app, true) {
+	for _, applicable := range no.dappApplications(app, usePermTree) {
 		err := applicable.del(no)
 		if err != nil {
 			return err
@@ -109,6 +148,17 @@ func (no *NodeOperator) DeleteNode(ctx context.Context, nodeContext string, node
 	return nil
 }
 
+// fromKubeError converts an error returned by k8s when getting a resource of a node
+func fromKubeError(err error, resource, name string) error {
+	if k8serrors.IsNotFound(err) {
+		return ierrors.New("%v %v not found", resource, name).NotFound()
+	}
+	return ierrors.Wrap(
+		ierrors.New(err).ExternalErr(),
+		"unable to get "+resource+" "+name,
+	)
+}
+
 // NewNodeOperator initializes a k8s based node operator with in cluster configuration
 func NewNodeOperator(memory tree.Manager, authenticator auth.Auth, broker brokers.Manager) (nop *NodeOperator, err error) {
 	nop = &NodeOperator{
@@ -0,0 +1,229 @@
+package operators
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"inspr.dev/inspr/cmd/insprd/memory/tree"
+	apimodels "inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	metautils "inspr.dev/inspr/pkg/meta/utils"
+)
+
+// defaultReconcileInterval is the interval between reconciliations when
+// the INSPR_RECONCILE_INTERVAL variable is not set
+const defaultReconcileInterval = time.Minute
+
+// Reconciler compares the channels and nodes of the tree with the resources
+// the operators keep in the runtime, reporting the ones that are missing and,
+// if set to repair them, creating them again
+type Reconciler struct {
+	memory   tree.Manager
+	operator OperatorInterface
+	interval time.Duration
+	repair   bool
+
+	report     apimodels.DriftReport
+	reportLock sync.RWMutex
+}
+
+// NewReconciler creates a reconciler for the given tree and operator. The interval
+// between reconciliations is read from INSPR_RECONCILE_INTERVAL, a zero interval
+// disabling them, and drifts are only repaired if INSPR_RECONCILE_REPAIR is true.
+func NewReconciler(memory tree.Manager, operator OperatorInterface) *Reconciler {
+	r := &Reconciler{
+		memory:   memory,
+		operator: operator,
+		interval: defaultReconcileInterval,
+		report:   apimodels.DriftReport{Drifts: []apimodels.Drift{}},
+	}
+
+	if value, ok := os.LookupEnv("INSPR_RECONCILE_INTERVAL"); ok && value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil {
+			logger.Error("invalid INSPR_RECONCILE_INTERVAL, using the default interval",
+				zap.String("interval", value), zap.Error(err))
+		} else {
+			r.interval = interval
+		}
+	}
+
+	if value, ok := os.LookupEnv("INSPR_RECONCILE_REPAIR"); ok && value != "" {
+		repair, err := strconv.ParseBool(value)
+		if err != nil {
+			logger.Error("invalid INSPR_RECONCILE_REPAIR, drifts won't be repaired",
+				zap.String("repair", value), zap.Error(err))
+		}
+		r.repair = repair
+	}
+	return r
+}
+
+// Run reconciles the runtime with the tree on every interval, until the context is done
+func (r *Reconciler) Run(ctx context.Context) {
+	l := logger.With(zap.String("operation", "reconcile"), zap.Duration("interval", r.interval))
+	if r.interval <= 0 {
+		l.Info("periodic reconciliation disabled")
+		return
+	}
+
+	l.Info("starting periodic reconciliation", zap.Bool("repair", r.repair))
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Reconcile(ctx, r.repair)
+		}
+	}
+}
+
+// Status returns the report of the last reconciliation
+func (r *Reconciler) Status() apimodels.DriftReport {
+	r.reportLock.RLock()
+	defer r.reportLock.RUnlock()
+	return r.report
+}
+
+// Reconcile checks that every channel and node of the committed tree exists in the
+// runtime, creating the missing ones again if repair is set. It reads the committed
+// tree without starting a transaction, so requests aren't blocked on the runtime
+// calls, and components removed by a commit made meanwhile are reported as drifts
+// but not repaired.
+func (r *Reconciler) Reconcile(ctx context.Context, repair bool) apimodels.DriftReport {
+	l := logger.With(zap.String("operation", "reconcile"), zap.Bool("repair", repair))
+	l.Debug("reconciling runtime with the tree")
+
+	report := apimodels.DriftReport{
+		Timestamp: time.Now(),
+		Drifts:    []apimodels.Drift{},
+	}
+
+	root, err := r.memory.Perm().Apps().Get("")
+	if err != nil {
+		l.Error("unable to get the tree to reconcile", zap.Error(err))
+		report.Errors = append(report.Errors, err.Error())
+	} else {
+		r.reconcileApp(ctx, root, "", repair, &report)
+	}
+
+	if len(report.Drifts) > 0 || len(report.Errors) > 0 {
+		l.Warn("runtime drifted from the tree",
+			zap.Any("drifts", report.Drifts), zap.Strings("errors", report.Errors))
+	}
+
+	r.reportLock.Lock()
+	r.report = report
+	r.reportLock.Unlock()
+	return report
+}
+
+// reconcileApp reconciles the channels and node of the given dApp, which is in the
+// given scope, and then the ones of its children
+func (r *Reconciler) reconcileApp(
+	ctx context.Context,
+	app *meta.App,
+	scope string,
+	repair bool,
+	report *apimodels.DriftReport,
+) {
+	for _, name := range sortedKeys(metautils.MChannels(app.Spec.Channels)) {
+		report.Channels++
+		_, err := r.operator.Channels().Get(ctx, scope, name)
+		drift, ok := r.check(err, apimodels.DriftChannel, scope, name, report)
+		if ok && repair && r.committed(scope, name, &drift) {
+			drift.RepairError = errorString(
+				r.operator.Channels().Create(ctx, scope, app.Spec.Channels[name]),
+			)
+			drift.Repaired = drift.RepairError == ""
+		}
+		if ok {
+			report.Drifts = append(report.Drifts, drift)
+		}
+	}
+
+	if scope != "" && app.Spec.Node.Spec.Image != "" {
+		report.Nodes++
+		parent, name, _ := metautils.RemoveLastPartInScope(scope)
+		_, err := r.operator.Nodes().GetNode(ctx, app)
+		drift, ok := r.check(err, apimodels.DriftNode, parent, name, report)
+		if ok && repair && r.committed(scope, "", &drift) {
+			_, err = r.operator.Nodes().CreateNode(ctx, app)
+			drift.RepairError = errorString(err)
+			drift.Repaired = drift.RepairError == ""
+		}
+		if ok {
+			report.Drifts = append(report.Drifts, drift)
+		}
+	}
+
+	for _, name := range sortedKeys(metautils.MApps(app.Spec.Apps)) {
+		childScope, _ := metautils.JoinScopes(scope, name)
+		r.reconcileApp(ctx, app.Spec.Apps[name], childScope, repair, report)
+	}
+}
+
+// committed returns whether the dApp of the given scope, or its channel if a name
+// is given, is still on the committed tree, recording on the drift why it isn't
+// repaired otherwise
+func (r *Reconciler) committed(scope, channel string, drift *apimodels.Drift) bool {
+	app, err := r.memory.Perm().Apps().Get("")
+	if err == nil && scope != "" {
+		for _, name := range strings.Split(scope, ".") {
+			if app = app.Spec.Apps[name]; app == nil {
+				break
+			}
+		}
+	}
+
+	if err != nil || app == nil || (channel != "" && app.Spec.Channels[channel] == nil) {
+		drift.RepairError = "the component was removed from the tree"
+		return false
+	}
+	return true
+}
+
+// check returns the drift of a component if the error of getting it from the
+// runtime shows that it's missing, recording any other error on the report
+func (r *Reconciler) check(
+	err error,
+	kind, scope, name string,
+	report *apimodels.DriftReport,
+) (apimodels.Drift, bool) {
+	drift := apimodels.Drift{Kind: kind, Scope: scope, Name: name}
+	if err == nil {
+		return drift, false
+	}
+
+	if !ierrors.HasCode(err, ierrors.NotFound) {
+		report.Errors = append(report.Errors, ierrors.Wrap(
+			err, "unable to check "+kind+" "+name+" in scope '"+scope+"'",
+		).Error())
+		return drift, false
+	}
+	drift.Reason = err.Error()
+	return drift, true
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func sortedKeys(components interface{}) []string {
+	set, _ := metautils.MakeStrSet(components)
+	keys := set.ToArray()
+	sort.Strings(keys)
+	return keys
+}
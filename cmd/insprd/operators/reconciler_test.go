@@ -0,0 +1,265 @@
+package operators
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"inspr.dev/inspr/cmd/insprd/memory/fake"
+	"inspr.dev/inspr/cmd/insprd/memory/tree"
+	apimodels "inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+)
+
+// runtimeMock is a channel and node operator that keeps the names of the
+// components it has created, failing to get the ones in failing
+type runtimeMock struct {
+	resources map[string]bool
+	failing   map[string]bool
+}
+
+func (m *runtimeMock) Nodes() NodeOperatorInterface       { return m }
+func (m *runtimeMock) Channels() ChannelOperatorInterface { return m }
+
+func (m *runtimeMock) get(key string) error {
+	if m.failing[key] {
+		return ierrors.New("runtime unavailable").ExternalErr()
+	}
+	if !m.resources[key] {
+		return ierrors.New("%v not found", key).NotFound()
+	}
+	return nil
+}
+
+func (m *runtimeMock) Get(ctx context.Context, scope, name string) (*meta.Channel, error) {
+	return nil, m.get(scope + "/" + name)
+}
+
+func (m *runtimeMock) Create(ctx context.Context, scope string, ch *meta.Channel) error {
+	m.resources[scope+"/"+ch.Meta.Name] = true
+	return nil
+}
+
+func (m *runtimeMock) Update(ctx context.Context, scope string, ch *meta.Channel) error {
+	return nil
+}
+
+func (m *runtimeMock) Delete(ctx context.Context, scope, name string) error {
+	delete(m.resources, scope+"/"+name)
+	return nil
+}
+
+func (m *runtimeMock) Messages(ctx context.Context, scope, name string, limit int) ([][]byte, error) {
+	return nil, m.get(scope + "/" + name)
+}
+
+func (m *runtimeMock) GetNode(ctx context.Context, app *meta.App) (*meta.Node, error) {
+	return &app.Spec.Node, m.get(app.Meta.Parent + "/" + app.Meta.Name)
+}
+
+func (m *runtimeMock) CreateNode(ctx context.Context, app *meta.App) (*meta.Node, error) {
+	m.resources[app.Meta.Parent+"/"+app.Meta.Name] = true
+	return &app.Spec.Node, nil
+}
+
+func (m *runtimeMock) UpdateNode(ctx context.Context, app *meta.App) (*meta.Node, error) {
+	return &app.Spec.Node, nil
+}
+
+func (m *runtimeMock) DeleteNode(ctx context.Context, scope, name string) error {
+	delete(m.resources, scope+"/"+name)
+	return nil
+}
+
+func reconcilerTree() tree.Manager {
+	mem := fake.MockTreeMemory(nil)
+	mem.Apps().Create("", &meta.App{
+		Spec: meta.AppSpec{
+			Channels: map[string]*meta.Channel{
+				"ch1": {Meta: meta.Metadata{Name: "ch1"}},
+			},
+			Apps: map[string]*meta.App{
+				"app1": {
+					Meta: meta.Metadata{Name: "app1"},
+					Spec: meta.AppSpec{
+						Channels: map[string]*meta.Channel{
+							"ch2": {Meta: meta.Metadata{Name: "ch2", Parent: "app1"}},
+						},
+						Apps: map[string]*meta.App{
+							"node": {
+								Meta: meta.Metadata{Name: "node", Parent: "app1"},
+								Spec: meta.AppSpec{
+									Node: meta.Node{Spec: meta.NodeSpec{Image: "image"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, nil)
+	return mem
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources []string
+		failing   []string
+		repair    bool
+		want      []apimodels.Drift
+		wantErrs  int
+	}{
+		{
+			name:      "runtime in sync",
+			resources: []string{"/ch1", "app1/ch2", "app1/node"},
+			want:      []apimodels.Drift{},
+		},
+		{
+			name:      "missing resources",
+			resources: []string{"/ch1"},
+			want: []apimodels.Drift{
+				{Kind: apimodels.DriftChannel, Scope: "app1", Name: "ch2"},
+				{Kind: apimodels.DriftNode, Scope: "app1", Name: "node"},
+			},
+		},
+		{
+			name:      "repaired resources",
+			resources: []string{"app1/node"},
+			repair:    true,
+			want: []apimodels.Drift{
+				{Kind: apimodels.DriftChannel, Scope: "", Name: "ch1", Repaired: true},
+				{Kind: apimodels.DriftChannel, Scope: "app1", Name: "ch2", Repaired: true},
+			},
+		},
+		{
+			name:      "unreachable runtime",
+			resources: []string{"/ch1", "app1/ch2"},
+			failing:   []string{"app1/node"},
+			want:      []apimodels.Drift{},
+			wantErrs:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtime := &runtimeMock{resources: map[string]bool{}, failing: map[string]bool{}}
+			for _, key := range tt.resources {
+				runtime.resources[key] = true
+			}
+			for _, key := range tt.failing {
+				runtime.failing[key] = true
+			}
+			r := NewReconciler(reconcilerTree(), runtime)
+
+			report := r.Reconcile(context.Background(), tt.repair)
+			if report.Channels != 2 || report.Nodes != 1 {
+				t.Errorf("Reconcile() checked %v channels and %v nodes, want 2 and 1",
+					report.Channels, report.Nodes)
+			}
+			if len(report.Errors) != tt.wantErrs {
+				t.Errorf("Reconcile() errors = %v, want %v of them", report.Errors, tt.wantErrs)
+			}
+			if len(report.Drifts) != len(tt.want) {
+				t.Fatalf("Reconcile() drifts = %v, want %v", report.Drifts, tt.want)
+			}
+			for i, drift := range report.Drifts {
+				drift.Reason = ""
+				if drift != tt.want[i] {
+					t.Errorf("Reconcile() drift = %v, want %v", drift, tt.want[i])
+				}
+			}
+
+			if status := r.Status(); status.Timestamp != report.Timestamp {
+				t.Errorf("Status() = %v, want the last report", status)
+			}
+			if tt.repair {
+				if report = r.Reconcile(context.Background(), false); len(report.Drifts) > 0 {
+					t.Errorf("Reconcile() didn't repair the drifts %v", report.Drifts)
+				}
+			}
+		})
+	}
+}
+
+// commitDuringReconcile is a tree whose components are removed by a commit
+// right after the reconciler reads it
+type commitDuringReconcile struct {
+	tree.Manager
+	reads int
+}
+
+func (m *commitDuringReconcile) Perm() tree.GetInterface {
+	m.reads++
+	if m.reads > 1 {
+		return fake.MockTreeMemory(nil).Perm()
+	}
+	return m.Manager.Perm()
+}
+
+func TestReconciler_Reconcile_removed(t *testing.T) {
+	runtime := &runtimeMock{resources: map[string]bool{}, failing: map[string]bool{}}
+	r := NewReconciler(&commitDuringReconcile{Manager: reconcilerTree()}, runtime)
+
+	report := r.Reconcile(context.Background(), true)
+	if len(report.Drifts) != 3 {
+		t.Fatalf("Reconcile() drifts = %v, want 3", report.Drifts)
+	}
+	for _, drift := range report.Drifts {
+		if drift.Repaired || drift.RepairError == "" {
+			t.Errorf("Reconcile() repaired %v, which was removed from the tree", drift)
+		}
+	}
+	if len(runtime.resources) != 0 {
+		t.Errorf("Reconcile() created %v", runtime.resources)
+	}
+}
+
+func TestReconciler_Reconcile_transaction(t *testing.T) {
+	memory := tree.GetTreeMemory()
+	memory.InitTransaction()
+	defer memory.Cancel()
+
+	// the reconciler reads the committed tree, so it doesn't wait for transactions
+	done := make(chan struct{})
+	go func() {
+		NewReconciler(memory, &runtimeMock{}).Reconcile(context.Background(), false)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Reconcile() blocked on the running transaction")
+	}
+}
+
+func TestNewReconciler(t *testing.T) {
+	defer os.Unsetenv("INSPR_RECONCILE_INTERVAL")
+	defer os.Unsetenv("INSPR_RECONCILE_REPAIR")
+
+	r := NewReconciler(reconcilerTree(), nil)
+	if r.interval != defaultReconcileInterval || r.repair {
+		t.Errorf("NewReconciler() = %v, %v, want the defaults", r.interval, r.repair)
+	}
+
+	os.Setenv("INSPR_RECONCILE_INTERVAL", "30s")
+	os.Setenv("INSPR_RECONCILE_REPAIR", "true")
+	r = NewReconciler(reconcilerTree(), nil)
+	if r.interval != 30*time.Second || !r.repair {
+		t.Errorf("NewReconciler() = %v, %v, want 30s and repair", r.interval, r.repair)
+	}
+
+	os.Setenv("INSPR_RECONCILE_INTERVAL", "0")
+	r = NewReconciler(reconcilerTree(), nil)
+	done := make(chan struct{})
+	go func() {
+		r.Run(context.Background())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Errorf("Run() didn't return with reconciliations disabled")
+	}
+}
@@ -15,7 +15,10 @@ import (
 type KafkaConfig struct {
 	BootstrapServers string `yaml:"bootstrapServers"`
 	AutoOffsetReset  string `yaml:"autoOffsetReset"`
-	SidecarImage     string `yaml:"sidecarImage"`
+	// Partitioner picks the partition of the messages from their keys, it's
+	// murmur2_random when empty
+	Partitioner  string `yaml:"partitioner"`
+	SidecarImage string `yaml:"sidecarImage"`
 	// KafkaInsprAddr is the port used in the insprd service of your cluster
 	KafkaInsprAddr string `yaml:"sidecarAddr"`
 }
@@ -59,16 +62,23 @@ func SimpleKafkaToDeployment(config KafkaConfig) models.SidecarFactory {
 
 // KafkaEnvConfig adds the necessary env variables to configure kafka
 func KafkaEnvConfig(config KafkaConfig) k8s.ContainerOption {
-	return k8s.ContainerWithEnv(
-		corev1.EnvVar{
+	envVars := []corev1.EnvVar{
+		{
 			Name:  "INSPR_SIDECAR_KAFKA_BOOTSTRAP_SERVERS",
 			Value: config.BootstrapServers,
 		},
-		corev1.EnvVar{
+		{
 			Name:  "INSPR_SIDECAR_KAFKA_AUTO_OFFSET_RESET",
 			Value: config.AutoOffsetReset,
 		},
-	)
+	}
+	if config.Partitioner != "" {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  "INSPR_SIDECAR_KAFKA_PARTITIONER",
+			Value: config.Partitioner,
+		})
+	}
+	return k8s.ContainerWithEnv(envVars...)
 }
 
 // KafkaSidecarConfig adds the necessary env variables to configure the sidecar in the cluster
@@ -11,8 +11,16 @@ import (
 type Environment struct {
 	KafkaBootstrapServers string
 	KafkaAutoOffsetReset  string
+	// KafkaPartitioner picks the partition of the messages written, from
+	// their keys
+	KafkaPartitioner string
 }
 
+// DefaultPartitioner is the partitioner of the Kafka writer when none is
+// set. It's the one used by the Java clients, so that messages with the same
+// key go to the same partition whichever client produced them
+const DefaultPartitioner = "murmur2_random"
+
 var env *Environment
 var logger *zap.Logger
 
@@ -29,6 +37,7 @@ func GetKafkaEnvironment() *Environment {
 		env = &Environment{
 			KafkaBootstrapServers: getEnv("INSPR_SIDECAR_KAFKA_BOOTSTRAP_SERVERS"),
 			KafkaAutoOffsetReset:  getEnv("INSPR_SIDECAR_KAFKA_AUTO_OFFSET_RESET"),
+			KafkaPartitioner:      getPartitioner(),
 		}
 	}
 	return env
@@ -41,12 +50,22 @@ func getEnv(name string) string {
 	panic("[ENV VAR] " + name + " not found")
 }
 
+// getPartitioner returns the partitioner set for the Kafka writer, which is
+// optional
+func getPartitioner() string {
+	if value := os.Getenv("INSPR_SIDECAR_KAFKA_PARTITIONER"); value != "" {
+		return value
+	}
+	return DefaultPartitioner
+}
+
 // RefreshEnviromentVariables "refreshes" the value of kafka environment variables.
 // This was develop for testing and probably sholdn't be used in other cases.
 func RefreshEnviromentVariables() *Environment {
 	env = &Environment{
 		KafkaBootstrapServers: getEnv("INSPR_SIDECAR_KAFKA_BOOTSTRAP_SERVERS"),
 		KafkaAutoOffsetReset:  getEnv("INSPR_SIDECAR_KAFKA_AUTO_OFFSET_RESET"),
+		KafkaPartitioner:      getPartitioner(),
 	}
 	return env
 }
@@ -10,6 +10,7 @@ func mockKafkaEnvironment() *Environment {
 	return &Environment{
 		KafkaBootstrapServers: "localhost",
 		KafkaAutoOffsetReset:  "101019",
+		KafkaPartitioner:      DefaultPartitioner,
 	}
 }
 
@@ -86,9 +87,10 @@ func TestRefreshEnviromentVariables(t *testing.T) {
 	os.Setenv("INSPR_SIDECAR_KAFKA_AUTO_OFFSET_RESET", "two")
 	defer deleteMockEnv()
 	tests := []struct {
-		name    string
-		refresh bool
-		want    *Environment
+		name        string
+		refresh     bool
+		partitioner string
+		want        *Environment
 	}{
 		{
 			name:    "Changed and refreshed environment variables",
@@ -96,11 +98,25 @@ func TestRefreshEnviromentVariables(t *testing.T) {
 			want: &Environment{
 				KafkaBootstrapServers: "one",
 				KafkaAutoOffsetReset:  "two",
+				KafkaPartitioner:      DefaultPartitioner,
+			},
+		},
+		{
+			name:        "Refreshed environment variables with a partitioner",
+			partitioner: "consistent_random",
+			want: &Environment{
+				KafkaBootstrapServers: "one",
+				KafkaAutoOffsetReset:  "two",
+				KafkaPartitioner:      "consistent_random",
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			if tt.partitioner != "" {
+				os.Setenv("INSPR_SIDECAR_KAFKA_PARTITIONER", tt.partitioner)
+				defer os.Unsetenv("INSPR_SIDECAR_KAFKA_PARTITIONER")
+			}
 
 			if got := RefreshEnviromentVariables(); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("GetEnvironment() = %v, want %v", got, tt.want)
@@ -0,0 +1,73 @@
+package kafkasc
+
+import (
+	"strings"
+
+	"gopkg.in/confluentinc/confluent-kafka-go.v1/kafka"
+	"inspr.dev/inspr/pkg/sidecars/models"
+)
+
+// newKafkaMessage returns the Kafka message in which a message is written to
+// the topic. Its key and produce time are stored in the ones of the Kafka
+// message and the rest of its envelope, including its trace context, in the
+// Kafka headers
+func newKafkaMessage(record models.BrokerRecord, topic string) *kafka.Message {
+	envelope := record.Envelope
+	msg := &kafka.Message{
+		TopicPartition: kafka.TopicPartition{
+			Topic:     &topic,
+			Partition: kafka.PartitionAny,
+		},
+		Value:     record.Value,
+		Timestamp: envelope.ProducedAt,
+	}
+
+	if envelope.Key != "" {
+		msg.Key = []byte(envelope.Key)
+	}
+	for key, value := range envelope.Headers {
+		msg.Headers = append(msg.Headers, kafka.Header{Key: key, Value: []byte(value)})
+	}
+	if envelope.SourceApp != "" {
+		msg.Headers = append(msg.Headers, kafka.Header{
+			Key:   models.SourceAppHeader,
+			Value: []byte(envelope.SourceApp),
+		})
+	}
+	for field, value := range envelope.Trace {
+		msg.Headers = append(msg.Headers, kafka.Header{
+			Key:   models.TraceHeaderPrefix + field,
+			Value: []byte(value),
+		})
+	}
+	return msg
+}
+
+// envelopeOf returns the envelope of a message read from Kafka
+func envelopeOf(msg *kafka.Message) models.Envelope {
+	envelope := models.Envelope{
+		Key:        string(msg.Key),
+		ProducedAt: msg.Timestamp,
+		Offset:     int64(msg.TopicPartition.Offset),
+	}
+
+	for _, header := range msg.Headers {
+		switch {
+		case header.Key == models.SourceAppHeader:
+			envelope.SourceApp = string(header.Value)
+		case strings.HasPrefix(header.Key, models.TraceHeaderPrefix):
+			if envelope.Trace == nil {
+				envelope.Trace = make(map[string]string)
+			}
+			envelope.Trace[strings.TrimPrefix(header.Key, models.TraceHeaderPrefix)] = string(header.Value)
+		case strings.HasPrefix(header.Key, models.ReservedHeaderPrefix):
+			// headers of Inspr that aren't part of the envelope
+		default:
+			if envelope.Headers == nil {
+				envelope.Headers = make(map[string]string)
+			}
+			envelope.Headers[header.Key] = string(header.Value)
+		}
+	}
+	return envelope
+}
@@ -0,0 +1,61 @@
+package kafkasc
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"inspr.dev/inspr/pkg/sidecars/models"
+)
+
+func Test_newKafkaMessage(t *testing.T) {
+	producedAt := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		envelope models.Envelope
+	}{
+		{
+			name: "message with the whole envelope",
+			envelope: models.Envelope{
+				Headers:    map[string]string{"correlation-id": "42", "content-type": "application/json"},
+				Key:        "order-1",
+				ProducedAt: producedAt,
+				SourceApp:  "app1-orders",
+				Trace: map[string]string{
+					"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+				},
+			},
+		},
+		{
+			name: "message without headers nor key",
+			envelope: models.Envelope{
+				ProducedAt: producedAt,
+				SourceApp:  "app1-orders",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := newKafkaMessage(models.BrokerRecord{Value: []byte("order"), Envelope: tt.envelope}, "ch1_resolved")
+			if *msg.TopicPartition.Topic != "ch1_resolved" || string(msg.Value) != "order" {
+				t.Errorf("newKafkaMessage() = %v", msg)
+			}
+			if tt.envelope.Key == "" && msg.Key != nil {
+				t.Errorf("newKafkaMessage() key = %s, want none", msg.Key)
+			}
+
+			// the envelope is read back from the Kafka message
+			if got := envelopeOf(msg); !reflect.DeepEqual(got, tt.envelope) {
+				t.Errorf("envelopeOf() = %+v, want %+v", got, tt.envelope)
+			}
+		})
+	}
+}
+
+func Test_envelopeOf_offset(t *testing.T) {
+	msg := newKafkaMessage(models.BrokerRecord{Value: []byte("order")}, "ch1_resolved")
+	msg.TopicPartition.Offset = 12
+	if got := envelopeOf(msg); got.Offset != 12 {
+		t.Errorf("envelopeOf() offset = %v, want 12", got.Offset)
+	}
+}
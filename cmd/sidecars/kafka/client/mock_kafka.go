@@ -14,6 +14,8 @@ type MockConsumer struct {
 	topic         string
 	senderChannel string
 	events        chan kafka.Event
+	offset        kafka.Offset
+	committed     []kafka.TopicPartition
 }
 
 //MockEvent mock
@@ -51,10 +53,12 @@ func (mc *MockConsumer) CreateMessage() {
 	msg := []byte(mc.pollMsg)
 	mc.events <- &kafka.Message{
 		TopicPartition: kafka.TopicPartition{
-			Topic: &mc.topic,
+			Topic:  &mc.topic,
+			Offset: mc.offset,
 		},
 		Value: msg,
 	}
+	mc.offset++
 }
 
 //Commit mock
@@ -65,6 +69,15 @@ func (mc *MockConsumer) Commit() ([]kafka.TopicPartition, error) {
 	return nil, nil
 }
 
+//CommitOffsets mock
+func (mc *MockConsumer) CommitOffsets(offsets []kafka.TopicPartition) ([]kafka.TopicPartition, error) {
+	if mc.err {
+		return nil, kafka.NewError(kafka.ErrApplication, "", false)
+	}
+	mc.committed = append(mc.committed, offsets...)
+	return offsets, nil
+}
+
 //Close mock
 func (mc *MockConsumer) Close() (err error) {
 	if mc.err {
@@ -76,8 +89,8 @@ func (mc *MockConsumer) Close() (err error) {
 // createMockEnvVars - sets up the env values to be used in the tests functions
 // createMockEnvVars - sets up the env values to be used in the tests functions
 func createMockEnv() {
-	os.Setenv("INSPR_INPUT_CHANNELS", "ch1@someBroker;ch2@someBroker")
-	os.Setenv("INSPR_OUTPUT_CHANNELS", "ch1@someBroker;ch2@someBroker")
+	os.Setenv("INSPR_INPUT_CHANNELS", "ch1@kafka;ch2@kafka;ch3@memory")
+	os.Setenv("INSPR_OUTPUT_CHANNELS", "ch1@kafka;ch2@kafka;ch3@memory")
 	os.Setenv("INSPR_UNIX_SOCKET", "/addr/to/socket")
 	os.Setenv("INSPR_APP_SCOPE", "")
 	os.Setenv("INSPR_ENV", "random")
@@ -2,6 +2,7 @@ package kafkasc
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -10,6 +11,8 @@ import (
 	"gopkg.in/confluentinc/confluent-kafka-go.v1/kafka"
 	globalEnv "inspr.dev/inspr/pkg/environment"
 	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta/brokers"
+	"inspr.dev/inspr/pkg/sidecars/models"
 )
 
 const pollTimeout = 100
@@ -18,6 +21,7 @@ const pollTimeout = 100
 type Consumer interface {
 	Poll(int) kafka.Event
 	Commit() ([]kafka.TopicPartition, error)
+	CommitOffsets(offsets []kafka.TopicPartition) ([]kafka.TopicPartition, error)
 	Close() (err error)
 }
 
@@ -30,6 +34,11 @@ type Reader struct {
 	consumers map[string]Consumer
 	kafkaEnv  *Environment
 	metrics   map[string]ReaderMetric
+
+	// uncommitted are the positions of the messages read from each channel
+	// that weren't committed yet, in the order they were read
+	uncommitted map[string][]kafka.TopicPartition
+	mutex       sync.Mutex
 }
 
 func (reader *Reader) GetMetric(channel string) ReaderMetric {
@@ -65,14 +74,11 @@ func NewReader() (*Reader, error) {
 	logger.Info("creating new kafka reader")
 	var reader Reader
 	reader.kafkaEnv = GetKafkaEnvironment()
-	channelsList := globalEnv.GetChannelBoundaryList(globalEnv.GetInputChannelsData())
-
-	logger.Debug("getting resolved channels list")
-	resolvedChList := globalEnv.GetResolvedBoundaryChannelList(globalEnv.GetInputChannelsData())
-	if len(resolvedChList) == 0 {
-		logger.Error("invalid resolved channel list")
+	channels := globalEnv.GetInputBrokerChannels(brokers.Kafka)
+	if len(channels) == 0 {
+		logger.Error("invalid channel list")
 		return nil, ierrors.New(
-			"INSPR_INPUT_CHANNELS not specified",
+			"INSPR_INPUT_CHANNELS doesn't have channels of the kafka broker",
 		).InvalidChannel()
 	}
 
@@ -80,8 +86,9 @@ func NewReader() (*Reader, error) {
 	reader.metrics = make(map[string]ReaderMetric)
 
 	logger.Debug("creating new consumer for each channel")
-	for idx, ch := range channelsList {
-		if err := reader.newSingleChannelConsumer(ch, resolvedChList[idx]); err != nil {
+	for _, ch := range channels {
+		resolved, _ := globalEnv.GetResolvedChannel(ch, globalEnv.GetInputChannelsData(), nil)
+		if err := reader.newSingleChannelConsumer(ch, resolved); err != nil {
 			logger.Error("unable to create consumer for channel",
 				zap.String("channel", ch),
 				zap.String("error", err.Error()))
@@ -99,9 +106,11 @@ func (reader *Reader) Consumers() map[string]Consumer {
 	return reader.consumers
 }
 
-// ReadMessage reads message by message. Returns channel the message belongs to,
-// the message and an error if any occurred.
-func (reader *Reader) ReadMessage(ctx context.Context, channel string) ([]byte, error) {
+// ReadMessage reads message by message. Returns the message, along with the
+// envelope stored in its Kafka headers, and an error if any occurred.
+// The messages of each partition are read in the order they were written, which
+// is kept for the messages of each key when they are delivered concurrently.
+func (reader *Reader) ReadMessage(ctx context.Context, channel string) (models.BrokerRecord, error) {
 	resolved, _ := globalEnv.GetResolvedChannel(channel, globalEnv.GetInputChannelsData(), nil)
 
 	logger.Info("trying to read message from topic",
@@ -114,7 +123,7 @@ func (reader *Reader) ReadMessage(ctx context.Context, channel string) ([]byte,
 	for {
 		select {
 		case <-ctx.Done():
-			return nil, ctx.Err()
+			return models.BrokerRecord{}, ctx.Err()
 		default:
 			event := consumer.Poll(pollTimeout)
 			switch ev := event.(type) {
@@ -125,17 +134,24 @@ func (reader *Reader) ReadMessage(ctx context.Context, channel string) ([]byte,
 				elapsed := time.Since(readMsg)
 				reader.GetMetric(channel).readKafkaTimeDuration.Observe(elapsed.Seconds())
 
-				return ev.Value, nil
+				reader.mutex.Lock()
+				if reader.uncommitted == nil {
+					reader.uncommitted = make(map[string][]kafka.TopicPartition)
+				}
+				reader.uncommitted[channel] = append(reader.uncommitted[channel], ev.TopicPartition)
+				reader.mutex.Unlock()
+
+				return models.BrokerRecord{Value: ev.Value, Envelope: envelopeOf(ev)}, nil
 
 			case kafka.Error:
 				if ev.Code() == kafka.ErrAllBrokersDown {
-					return nil, ierrors.Wrap(
+					return models.BrokerRecord{}, ierrors.Wrap(
 						ierrors.New(ev).InternalServer(),
 						"kafka error = all brokers are down",
 					)
 				}
 				logger.Error("error while reading kafka message", zap.String("error", ev.Error()))
-				return nil, ierrors.New("%v", ev)
+				return models.BrokerRecord{}, ierrors.New("%v", ev)
 
 			default:
 				continue
@@ -145,11 +161,30 @@ func (reader *Reader) ReadMessage(ctx context.Context, channel string) ([]byte,
 	}
 }
 
-// Commit commits the last message read by Reader
+// Commit commits the oldest message read by Reader from the channel that
+// wasn't committed yet, so that messages handled concurrently are committed
+// in the order they were read by committing each of them once it's handled
+// along with the ones read before it
 func (reader *Reader) Commit(ctx context.Context, channel string) error {
 	logger.Info("committing to channel", zap.String("channel", channel))
+
+	consumer := reader.consumers[channel]
+	commit := consumer.Commit
+
+	reader.mutex.Lock()
+	if positions := reader.uncommitted[channel]; len(positions) > 0 {
+		// the committed offset is the one of the next message to be read
+		position := positions[0]
+		position.Offset++
+		reader.uncommitted[channel] = positions[1:]
+		commit = func() ([]kafka.TopicPartition, error) {
+			return consumer.CommitOffsets([]kafka.TopicPartition{position})
+		}
+	}
+	reader.mutex.Unlock()
+
 	doneChan := make(chan error)
-	go func() { _, errCommit := reader.consumers[channel].Commit(); doneChan <- errCommit }()
+	go func() { _, errCommit := commit(); doneChan <- errCommit }()
 	select {
 	case <-ctx.Done():
 		<-doneChan
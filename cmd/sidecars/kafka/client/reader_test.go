@@ -31,6 +31,10 @@ func TestNewReader(t *testing.T) {
 				if !(reader.Consumers() != nil && len(reader.Consumers()) > 0) {
 					t.Errorf("check function error = Reader not created successfully")
 				}
+				// only the channels of the kafka broker are read
+				if _, ok := reader.Consumers()["ch3"]; ok || len(reader.Consumers()) != 2 {
+					t.Errorf("check function error = Reader consumers = %v", reader.Consumers())
+				}
 			},
 		},
 		{
@@ -133,7 +137,7 @@ func TestReader_ReadMessage(t *testing.T) {
 
 			reader.consumers[tt.uniqueChannel].(*MockConsumer).CreateMessage()
 			bData, err := reader.ReadMessage(ctx, tt.uniqueChannel)
-			got1 := bData
+			got1 := bData.Value
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Reader.ReadMessage() error = %v, wantErr %v", err, tt.wantErr)
@@ -200,6 +204,51 @@ func TestReader_Commit(t *testing.T) {
 	}
 }
 
+func TestReader_Commit_readMessages(t *testing.T) {
+	createMockEnv()
+	defer deleteMockEnv()
+	environment.RefreshEnviromentVariables()
+	RefreshEnviromentVariables()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	consumer := &MockConsumer{
+		events:  make(chan kafka.Event, 3),
+		pollMsg: "Hello World!",
+		topic:   "ch1_resolved",
+		offset:  10,
+	}
+	reader := &Reader{
+		consumers: map[string]Consumer{"ch1": consumer},
+		metrics:   make(map[string]ReaderMetric),
+	}
+
+	for i := 0; i < 3; i++ {
+		consumer.CreateMessage()
+		if _, err := reader.ReadMessage(ctx, "ch1"); err != nil {
+			t.Fatalf("Reader.ReadMessage() error = %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if err := reader.Commit(ctx, "ch1"); err != nil {
+			t.Fatalf("Reader.Commit() error = %v", err)
+		}
+	}
+
+	got := []kafka.Offset{}
+	for _, position := range consumer.committed {
+		got = append(got, position.Offset)
+	}
+	want := []kafka.Offset{11, 12}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Reader.Commit() committed offsets %v, want %v", got, want)
+	}
+	if len(reader.uncommitted["ch1"]) != 1 {
+		t.Errorf("Reader.Commit() left %v uncommitted messages, want 1", len(reader.uncommitted["ch1"]))
+	}
+}
+
 func TestReader_Close(t *testing.T) {
 	type fields struct {
 		consumers   map[string]Consumer
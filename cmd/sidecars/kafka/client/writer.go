@@ -9,6 +9,8 @@ import (
 	"gopkg.in/confluentinc/confluent-kafka-go.v1/kafka"
 	"inspr.dev/inspr/pkg/environment"
 	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta/brokers"
+	"inspr.dev/inspr/pkg/sidecars/models"
 )
 
 type writerMetrics struct {
@@ -27,10 +29,10 @@ func NewWriter() (*Writer, error) {
 	var kProd *kafka.Producer
 	var err error
 
-	bootstrapServers := GetKafkaEnvironment().KafkaBootstrapServers
-	kProd, err = kafka.NewProducer(&kafka.ConfigMap{
-		"bootstrap.servers": bootstrapServers,
-	})
+	kProd, err = kafka.NewProducer(newProducerConfig(GetKafkaEnvironment()))
+	if err != nil {
+		return nil, ierrors.New(err)
+	}
 
 	go func(events <-chan kafka.Event) {
 		for {
@@ -44,10 +46,6 @@ func NewWriter() (*Writer, error) {
 		}
 	}(kProd.Events())
 
-	if err != nil {
-		return nil, ierrors.New(err)
-	}
-
 	newWriter := &Writer{
 		producer: kProd,
 		metrics:  make(map[string]writerMetrics),
@@ -56,12 +54,29 @@ func NewWriter() (*Writer, error) {
 	return newWriter, nil
 }
 
+// newProducerConfig returns the configuration of the Kafka producer. Messages
+// with the same key are written to the same partition by the partitioner, and
+// the idempotence keeps the messages of each partition in the order they are
+// produced, even when some of them are retried
+func newProducerConfig(kafkaEnv *Environment) *kafka.ConfigMap {
+	return &kafka.ConfigMap{
+		"bootstrap.servers":  kafkaEnv.KafkaBootstrapServers,
+		"partitioner":        kafkaEnv.KafkaPartitioner,
+		"enable.idempotence": true,
+	}
+}
+
 func (writer *Writer) getProducer() *kafka.Producer {
 	return writer.producer
 }
 
 // WriteMessage receives a message and sends it to the topic defined by the given channel
-func (writer *Writer) WriteMessage(channel string, message []byte) error {
+func (writer *Writer) WriteMessage(channel string, message models.BrokerRecord) error {
+	if !environment.GetOutputBrokerChannels(brokers.Kafka).Contains(channel) {
+		return ierrors.New(
+			"channel %s not listed as an output of the kafka broker", channel,
+		).InvalidChannel()
+	}
 	outputChan := environment.GetOutputChannelsData()
 
 	startResolveChannel := time.Now()
@@ -92,19 +107,82 @@ func (writer *Writer) WriteMessage(channel string, message []byte) error {
 	return nil
 }
 
+// WriteMessages receives a batch of messages and sends all of them to the topic
+// defined by the given channel, in the order they are given. It returns once
+// the delivery of every message is reported by Kafka, with an error if any of
+// them wasn't delivered. The batch isn't transactional: the messages delivered
+// before a failed one stay on the topic
+func (writer *Writer) WriteMessages(channel string, messages []models.BrokerRecord) error {
+	if !environment.GetOutputBrokerChannels(brokers.Kafka).Contains(channel) {
+		return ierrors.New(
+			"channel %s not listed as an output of the kafka broker", channel,
+		).InvalidChannel()
+	}
+	outputChan := environment.GetOutputChannelsData()
+
+	startResolveChannel := time.Now()
+
+	resolvedCh, err := environment.GetResolvedChannel(channel, nil, outputChan)
+	if err != nil {
+		return err
+	}
+
+	elapsedResolveChannel := time.Since(startResolveChannel)
+	writer.GetMetric(channel).resolveChannelDuration.Observe(elapsedResolveChannel.Seconds())
+
+	startProduce := time.Now()
+
+	logger.Info("trying to write messages in topic",
+		zap.String("channel", channel),
+		zap.String("resolved channel", resolvedCh),
+		zap.Int("messages", len(messages)))
+
+	errs := ierrors.MultiError{Errors: []error{}}
+	deliveries := make(chan kafka.Event, len(messages))
+	produced := 0
+	for _, message := range messages {
+		errProduceMessage := writer.producer.Produce(newKafkaMessage(message, resolvedCh), deliveries)
+		if errProduceMessage != nil {
+			logger.Error("error while producing message",
+				zap.Any("error", errProduceMessage))
+			errs.Add(errProduceMessage)
+			break
+		}
+		produced++
+	}
+
+	// waits for the delivery reports of the produced messages
+	delivered := 0
+	for i := 0; i < produced; i++ {
+		msg, ok := (<-deliveries).(*kafka.Message)
+		if ok && msg.TopicPartition.Error != nil {
+			logger.Error("message of the batch wasn't delivered",
+				zap.Any("error", msg.TopicPartition.Error))
+			errs.Add(msg.TopicPartition.Error)
+			continue
+		}
+		delivered++
+	}
+	if !errs.Empty() {
+		return ierrors.New(
+			"%d of %d messages of the batch weren't written: %v",
+			len(messages)-delivered, len(messages), errs.Error(),
+		).ExternalErr()
+	}
+
+	elapsedProduce := time.Since(startProduce)
+	writer.GetMetric(channel).produceMessageDuration.Observe(elapsedProduce.Seconds())
+
+	return nil
+}
+
 // creates a Kafka message and sends it through the ProduceChannel
-func (writer *Writer) produceMessage(message []byte, resolvedChannel string) error {
+func (writer *Writer) produceMessage(message models.BrokerRecord, resolvedChannel string) error {
 
 	logger.Debug("writing message into Kafka Topic",
 		zap.String("topic", resolvedChannel))
 
-	return writer.producer.Produce(&kafka.Message{
-		TopicPartition: kafka.TopicPartition{
-			Topic:     &resolvedChannel,
-			Partition: kafka.PartitionAny,
-		},
-		Value: message,
-	}, nil)
+	return writer.producer.Produce(newKafkaMessage(message, resolvedChannel), nil)
 
 }
 
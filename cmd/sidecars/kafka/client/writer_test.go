@@ -6,6 +6,7 @@ import (
 
 	"gopkg.in/confluentinc/confluent-kafka-go.v1/kafka"
 	"inspr.dev/inspr/pkg/environment"
+	"inspr.dev/inspr/pkg/sidecars/models"
 )
 
 func TestNewWriter(t *testing.T) {
@@ -57,7 +58,7 @@ func TestWriter_WriteMessage(t *testing.T) {
 	}
 	type args struct {
 		channel string
-		message []byte
+		message models.BrokerRecord
 	}
 	tests := []struct {
 		name    string
@@ -72,7 +73,18 @@ func TestWriter_WriteMessage(t *testing.T) {
 			},
 			args: args{
 				channel: "invalid",
-				message: []byte("testMessageWriterTest"),
+				message: models.BrokerRecord{Value: []byte("testMessageWriterTest")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Channel of another broker",
+			fields: fields{
+				producer: mProd.getProducer(),
+			},
+			args: args{
+				channel: "ch3",
+				message: models.BrokerRecord{Value: []byte("testMessageWriterTest")},
 			},
 			wantErr: true,
 		},
@@ -83,7 +95,7 @@ func TestWriter_WriteMessage(t *testing.T) {
 			},
 			args: args{
 				channel: "ch1",
-				message: []byte("testMessageWriterTest"),
+				message: models.BrokerRecord{Value: []byte("testMessageWriterTest")},
 			},
 			wantErr: false,
 		},
@@ -101,6 +113,74 @@ func TestWriter_WriteMessage(t *testing.T) {
 	}
 }
 
+func TestWriter_WriteMessages(t *testing.T) {
+	mProd, _ := newMockWriter()
+	defer mProd.Close()
+	createMockEnv()
+	os.Setenv("INSPR_APP_SCOPE", "")
+	environment.RefreshEnviromentVariables()
+	defer deleteMockEnv()
+	// producer of a broker that isn't reachable, whose messages are never delivered
+	unreachable, _ := kafka.NewProducer(&kafka.ConfigMap{
+		"bootstrap.servers":  "localhost:1",
+		"message.timeout.ms": 100,
+	})
+	defer unreachable.Close()
+	// the metrics are registered once for each channel
+	metrics := make(map[string]writerMetrics)
+	type args struct {
+		channel  string
+		messages []models.BrokerRecord
+	}
+	tests := []struct {
+		name     string
+		producer *kafka.Producer
+		args     args
+		wantErr  bool
+	}{
+		{
+			name: "Invalid channel",
+			args: args{
+				channel:  "invalid",
+				messages: []models.BrokerRecord{{Value: []byte("first")}, {Value: []byte("second")}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Valid batch writing",
+			args: args{
+				channel:  "ch2",
+				messages: []models.BrokerRecord{{Value: []byte("first")}, {Value: []byte("second")}},
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Undelivered batch",
+			producer: unreachable,
+			args: args{
+				channel:  "ch2",
+				messages: []models.BrokerRecord{{Value: []byte("first")}, {Value: []byte("second")}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			producer := tt.producer
+			if producer == nil {
+				producer = mProd.getProducer()
+			}
+			writer := &Writer{
+				producer: producer,
+				metrics:  metrics,
+			}
+			if err := writer.WriteMessages(tt.args.channel, tt.args.messages); (err != nil) != tt.wantErr {
+				t.Errorf("Writer.WriteMessages() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestWriter_produceMessage(t *testing.T) {
 	mProd, _ := newMockWriter()
 	defer mProd.Close()
@@ -112,7 +192,7 @@ func TestWriter_produceMessage(t *testing.T) {
 		producer *kafka.Producer
 	}
 	type args struct {
-		message []byte
+		message models.BrokerRecord
 		channel string
 	}
 	tests := []struct {
@@ -127,7 +207,7 @@ func TestWriter_produceMessage(t *testing.T) {
 				producer: mProd.getProducer(),
 			},
 			args: args{
-				message: []byte("testProducingMessage"),
+				message: models.BrokerRecord{Value: []byte("testProducingMessage")},
 				channel: "ch1_resolved",
 			},
 			wantErr: false,
@@ -144,3 +224,59 @@ func TestWriter_produceMessage(t *testing.T) {
 		})
 	}
 }
+
+func Test_newProducerConfig(t *testing.T) {
+	config := newProducerConfig(&Environment{
+		KafkaBootstrapServers: "localhost",
+		KafkaPartitioner:      DefaultPartitioner,
+	})
+	config.SetKey("test.mock.num.brokers", 3)
+	producer, err := kafka.NewProducer(config)
+	if err != nil {
+		t.Fatalf("NewProducer() error = %v", err)
+	}
+	defer producer.Close()
+
+	// messages with the same key are written to the same partition, in the
+	// order they were produced
+	topic := "ch1_resolved"
+	keys := []string{"order-1", "order-2", "order-1", "order-3", "order-1"}
+	for i, key := range keys {
+		record := models.BrokerRecord{
+			Value:    []byte{byte(i)},
+			Envelope: models.Envelope{Key: key},
+		}
+		if err := producer.Produce(newKafkaMessage(record, topic), nil); err != nil {
+			t.Fatalf("Produce() error = %v", err)
+		}
+	}
+
+	partitions := map[string]int32{}
+	offsets := map[int32]kafka.Offset{}
+	for delivered := 0; delivered < len(keys); {
+		msg, ok := (<-producer.Events()).(*kafka.Message)
+		if !ok {
+			continue
+		}
+		delivered++
+		if msg.TopicPartition.Error != nil {
+			t.Fatalf("message not delivered: %v", msg.TopicPartition.Error)
+		}
+
+		key, partition := string(msg.Key), msg.TopicPartition.Partition
+		if want, ok := partitions[key]; ok && want != partition {
+			t.Errorf("key %v written to partitions %v and %v", key, want, partition)
+		}
+		partitions[key] = partition
+
+		if last, ok := offsets[partition]; ok && msg.TopicPartition.Offset <= last {
+			t.Errorf("partition %v written out of order", partition)
+		}
+		offsets[partition] = msg.TopicPartition.Offset
+	}
+
+	config.SetKey("partitioner", "invalid")
+	if _, err := kafka.NewProducer(config); err == nil {
+		t.Errorf("NewProducer() with an invalid partitioner error = nil")
+	}
+}
@@ -95,3 +95,39 @@ func TestKafkaToDeployment(t *testing.T) {
 		})
 	}
 }
+
+func TestKafkaEnvConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		partitioner   string
+		wantEnvLength int
+	}{
+		{
+			name:          "default partitioner",
+			wantEnvLength: 2,
+		},
+		{
+			name:          "partitioner set",
+			partitioner:   "consistent_random",
+			wantEnvLength: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			container := k8s.NewContainer("", "", KafkaEnvConfig(KafkaConfig{
+				BootstrapServers: testBootstrap,
+				AutoOffsetReset:  testAutoOff,
+				Partitioner:      tt.partitioner,
+			}))
+			if len(container.Env) != tt.wantEnvLength {
+				t.Fatalf("KafkaEnvConfig() env = %v", container.Env)
+			}
+			if tt.partitioner != "" && container.Env[2] != (corev1.EnvVar{
+				Name:  "INSPR_SIDECAR_KAFKA_PARTITIONER",
+				Value: tt.partitioner,
+			}) {
+				t.Errorf("KafkaEnvConfig() partitioner = %v", container.Env[2])
+			}
+		})
+	}
+}
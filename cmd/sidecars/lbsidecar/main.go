@@ -6,10 +6,13 @@ import (
 
 	"go.uber.org/zap"
 	kafkasc "inspr.dev/inspr/cmd/sidecars/kafka/client"
+	memorysc "inspr.dev/inspr/cmd/sidecars/memory/client"
 	"inspr.dev/inspr/pkg/environment"
 	"inspr.dev/inspr/pkg/logs"
+	"inspr.dev/inspr/pkg/meta/brokers"
 	"inspr.dev/inspr/pkg/sidecars/lbsidecar"
 	"inspr.dev/inspr/pkg/sidecars/models"
+	"inspr.dev/inspr/pkg/tracing"
 )
 
 var logger *zap.Logger
@@ -27,32 +30,75 @@ func main() {
 	var writer models.Writer
 	var err error
 
-	logger.Info("instantiating Kafka Sidecar reader")
-	if len(environment.GetInputChannelsData()) != 0 {
-		reader, err = kafkasc.NewReader()
-		if err != nil {
-			logger.Error("unable to instantiate Kafka Sidecar reader")
+	logger.Info("setting up tracing")
+	shutdown, err := tracing.Init(ctx, "lbsidecar")
+	if err != nil {
+		logger.Error("unable to set up tracing, spans won't be exported", zap.Error(err))
+	} else {
+		defer shutdown(context.Background())
+	}
+
+	var handlers []*models.BrokerHandler
 
-			fmt.Println(err)
-			return
+	if len(environment.GetInputBrokerChannels(brokers.Kafka)) != 0 ||
+		len(environment.GetOutputBrokerChannels(brokers.Kafka)) != 0 {
+		logger.Info("instantiating Kafka Sidecar reader")
+		if len(environment.GetInputBrokerChannels(brokers.Kafka)) != 0 {
+			reader, err = kafkasc.NewReader()
+			if err != nil {
+				logger.Error("unable to instantiate Kafka Sidecar reader")
+
+				fmt.Println(err)
+				return
+			}
 		}
-	}
 
-	logger.Info("instantiating Kafka Sidecar writer")
-	if len(environment.GetOutputChannelsData()) != 0 {
-		writer, err = kafkasc.NewWriter()
-		if err != nil {
-			logger.Error("unable to instantiate Kafka Sidecar writer")
+		logger.Info("instantiating Kafka Sidecar writer")
+		if len(environment.GetOutputBrokerChannels(brokers.Kafka)) != 0 {
+			writer, err = kafkasc.NewWriter()
+			if err != nil {
+				logger.Error("unable to instantiate Kafka Sidecar writer")
 
-			fmt.Println(err)
-			return
+				fmt.Println(err)
+				return
+			}
 		}
+
+		handlers = append(handlers, models.NewBrokerHandler(brokers.Kafka, reader, writer))
 	}
 
-	kafkaHandler := models.NewBrokerHandler("kafka", reader, writer)
+	if len(environment.GetInputBrokerChannels(brokers.Memory)) != 0 ||
+		len(environment.GetOutputBrokerChannels(brokers.Memory)) != 0 {
+		store := memorysc.NewClient(memorysc.GetBrokerAddr())
+		reader, writer = nil, nil
+
+		logger.Info("instantiating Memory Sidecar reader")
+		if len(environment.GetInputBrokerChannels(brokers.Memory)) != 0 {
+			reader, err = memorysc.NewReader(store)
+			if err != nil {
+				logger.Error("unable to instantiate Memory Sidecar reader")
+
+				fmt.Println(err)
+				return
+			}
+		}
+
+		logger.Info("instantiating Memory Sidecar writer")
+		if len(environment.GetOutputBrokerChannels(brokers.Memory)) != 0 {
+			writer, err = memorysc.NewWriter(store)
+			if err != nil {
+				logger.Error("unable to instantiate Memory Sidecar writer")
+
+				fmt.Println(err)
+				return
+			}
+		}
+
+		handlers = append(handlers, models.NewBrokerHandler(brokers.Memory, reader, writer))
+	}
 
 	logger.Info("initializing LB Sidecar server")
-	lbServer := lbsidecar.Init(kafkaHandler)
+	lbServer := lbsidecar.Init(handlers...)
 
 	logger.Info("running LB Sidecar server")
 	if err := lbServer.Run(ctx); err != nil {
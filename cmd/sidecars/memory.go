@@ -0,0 +1,43 @@
+package sidecars
+
+import (
+	memorysc "inspr.dev/inspr/cmd/sidecars/memory/client"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/meta/brokers"
+	"inspr.dev/inspr/pkg/operator/k8s"
+	"inspr.dev/inspr/pkg/sidecars/models"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// MemoryConfig configurations used to connect the sidecars to the in-memory broker
+type MemoryConfig struct {
+	// Address is the address of the in-memory broker service in the cluster,
+	// such as http://inspr-memory-broker:8080
+	Address string `yaml:"address"`
+}
+
+// Broker is a BrokerConfiguration interface method, it returns the broker name for this config type
+func (mc MemoryConfig) Broker() string {
+	return brokers.Memory
+}
+
+// MemoryToDeployment receives the MemoryConfig variable as a parameter and returns a
+// SidecarFactory function that is used to subscribe to the sidecarFactory
+func MemoryToDeployment(config MemoryConfig) models.SidecarFactory {
+	return func(app *meta.App, conn *models.SidecarConnections, opts ...k8s.ContainerOption) (corev1.Container, []corev1.EnvVar) {
+		opts = append(opts, MemoryEnvConfig(config))
+		return k8s.NewContainer(
+			"",
+			"",
+			opts...,
+		), nil
+	}
+}
+
+// MemoryEnvConfig adds the necessary env variables to connect to the in-memory broker
+func MemoryEnvConfig(config MemoryConfig) k8s.ContainerOption {
+	return k8s.ContainerWithEnv(corev1.EnvVar{
+		Name:  memorysc.AddrEnv,
+		Value: config.Address,
+	})
+}
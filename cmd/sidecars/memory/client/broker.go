@@ -0,0 +1,201 @@
+package memorysc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/sidecars/models"
+)
+
+// Store is where the reader and the writer of the in-memory broker keep the
+// messages of the channels. It's either a Broker embedded in the process, as
+// in tests, or a Client of the broker service running in the cluster.
+type Store interface {
+	// Write appends the messages to the topic, in order
+	Write(ctx context.Context, topic string, records []models.BrokerRecord) error
+	// Read returns the message at the offset of the topic, waiting for it
+	// to be written when there's none yet
+	Read(ctx context.Context, topic string, offset int64) (models.BrokerRecord, error)
+	// Offset returns the offset committed by the consumer group on the
+	// topic, which is the one of the next message it reads
+	Offset(ctx context.Context, topic, group string) (int64, error)
+	// Commit sets the offset committed by the consumer group on the topic
+	Commit(ctx context.Context, topic, group string, offset int64) error
+}
+
+// TopicInfo describes a topic of the in-memory broker
+type TopicInfo struct {
+	Name string `json:"name"`
+	// Messages is the number of messages written to the topic
+	Messages int64 `json:"messages"`
+}
+
+// Broker is a message broker that keeps its topics in memory. The messages
+// of a topic are kept in the order they were written, and each consumer group
+// has its own committed offset on it. Topics are created when they are first
+// written to or read from, as well as by CreateTopic.
+type Broker struct {
+	topics map[string]*topic
+	mutex  sync.Mutex
+	// pollTimeout bounds the time the read requests to Handler wait
+	pollTimeout time.Duration
+}
+
+// topic is a topic of the in-memory broker
+type topic struct {
+	records []models.BrokerRecord
+	// offsets are the offsets committed by each consumer group
+	offsets map[string]int64
+	// written is closed when messages are written to the topic, waking up
+	// the readers waiting for them
+	written chan struct{}
+}
+
+// NewBroker returns an in-memory broker without topics
+func NewBroker() *Broker {
+	return &Broker{
+		topics:      make(map[string]*topic),
+		pollTimeout: defaultPollTimeout,
+	}
+}
+
+// getTopic returns the topic of the given name, creating it when it doesn't
+// exist. It must be called with the mutex of the broker locked
+func (b *Broker) getTopic(name string) *topic {
+	t, ok := b.topics[name]
+	if !ok {
+		t = &topic{
+			offsets: make(map[string]int64),
+			written: make(chan struct{}),
+		}
+		b.topics[name] = t
+	}
+	return t
+}
+
+// Write appends the messages to the topic, in order
+func (b *Broker) Write(ctx context.Context, topic string, records []models.BrokerRecord) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	t := b.getTopic(topic)
+	for _, record := range records {
+		// the attempt of the delivery isn't stored in the broker
+		record.Envelope.Attempt = 0
+		t.records = append(t.records, record)
+	}
+	close(t.written)
+	t.written = make(chan struct{})
+	return nil
+}
+
+// Read returns the message at the offset of the topic, waiting for it to be
+// written when there's none yet, until ctx is done
+func (b *Broker) Read(ctx context.Context, topic string, offset int64) (models.BrokerRecord, error) {
+	if offset < 0 {
+		return models.BrokerRecord{}, ierrors.New("invalid offset %d", offset).BadRequest()
+	}
+
+	for {
+		b.mutex.Lock()
+		t := b.getTopic(topic)
+		if offset < int64(len(t.records)) {
+			record := t.records[offset]
+			b.mutex.Unlock()
+
+			record.Envelope.Offset = offset
+			return record, nil
+		}
+		written := t.written
+		b.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return models.BrokerRecord{}, ctx.Err()
+		case <-written:
+		}
+	}
+}
+
+// Offset returns the offset committed by the consumer group on the topic,
+// which is 0 when it hasn't committed any
+func (b *Broker) Offset(ctx context.Context, topic, group string) (int64, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.getTopic(topic).offsets[group], nil
+}
+
+// Commit sets the offset committed by the consumer group on the topic
+func (b *Broker) Commit(ctx context.Context, topic, group string, offset int64) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	t := b.getTopic(topic)
+	if offset < 0 || offset > int64(len(t.records)) {
+		return ierrors.New(
+			"invalid offset %d for topic '%s', it has %d messages",
+			offset, topic, len(t.records),
+		).BadRequest()
+	}
+	t.offsets[group] = offset
+	return nil
+}
+
+// CreateTopic creates a topic, which is kept as it is when it already exists
+func (b *Broker) CreateTopic(ctx context.Context, topic string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.getTopic(topic)
+	return nil
+}
+
+// DeleteTopic deletes a topic, along with its messages and offsets
+func (b *Broker) DeleteTopic(ctx context.Context, topic string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	t, ok := b.topics[topic]
+	if !ok {
+		return ierrors.New("topic '%s' not found", topic).NotFound()
+	}
+	// readers waiting on the topic wait on the one created in its place
+	close(t.written)
+	delete(b.topics, topic)
+	return nil
+}
+
+// Topic returns the description of a topic
+func (b *Broker) Topic(ctx context.Context, topic string) (TopicInfo, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	t, ok := b.topics[topic]
+	if !ok {
+		return TopicInfo{}, ierrors.New("topic '%s' not found", topic).NotFound()
+	}
+	return TopicInfo{Name: topic, Messages: int64(len(t.records))}, nil
+}
+
+// Messages returns the latest messages of a topic, up to the given limit,
+// oldest first. All of them are returned when there's no limit
+func (b *Broker) Messages(ctx context.Context, topic string, limit int) ([][]byte, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	t, ok := b.topics[topic]
+	if !ok {
+		return nil, ierrors.New("topic '%s' not found", topic).NotFound()
+	}
+
+	records := t.records
+	if limit > 0 && len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+	messages := make([][]byte, 0, len(records))
+	for _, record := range records {
+		messages = append(messages, record.Value)
+	}
+	return messages, nil
+}
@@ -0,0 +1,157 @@
+package memorysc
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/sidecars/models"
+)
+
+func newRecords(values ...string) []models.BrokerRecord {
+	records := make([]models.BrokerRecord, len(values))
+	for i, value := range values {
+		records[i] = models.BrokerRecord{
+			Value:    []byte(value),
+			Envelope: models.Envelope{Key: value, Attempt: 2},
+		}
+	}
+	return records
+}
+
+func TestBroker_Read(t *testing.T) {
+	ctx := context.Background()
+	b := NewBroker()
+	b.Write(ctx, "topic", newRecords("first", "second"))
+
+	tests := []struct {
+		name    string
+		offset  int64
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "first message",
+			offset: 0,
+			want:   "first",
+		},
+		{
+			name:   "second message",
+			offset: 1,
+			want:   "second",
+		},
+		{
+			name:    "negative offset",
+			offset:  -1,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := b.Read(ctx, "topic", tt.offset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Broker.Read() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			want := models.BrokerRecord{
+				Value:    []byte(tt.want),
+				Envelope: models.Envelope{Key: tt.want, Offset: tt.offset},
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Broker.Read() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestBroker_Read_wait(t *testing.T) {
+	b := NewBroker()
+
+	// the read waits for the message to be written
+	got := make(chan models.BrokerRecord)
+	go func() {
+		record, _ := b.Read(context.Background(), "topic", 0)
+		got <- record
+	}()
+	time.Sleep(10 * time.Millisecond)
+	b.Write(context.Background(), "topic", newRecords("late"))
+
+	select {
+	case record := <-got:
+		if string(record.Value) != "late" {
+			t.Errorf("Broker.Read() = %s, want late", record.Value)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Broker.Read() didn't return the message written")
+	}
+
+	// until ctx is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := b.Read(ctx, "topic", 1); err != context.DeadlineExceeded {
+		t.Errorf("Broker.Read() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestBroker_Commit(t *testing.T) {
+	ctx := context.Background()
+	b := NewBroker()
+	b.Write(ctx, "topic", newRecords("first", "second"))
+
+	if offset, _ := b.Offset(ctx, "topic", "group"); offset != 0 {
+		t.Errorf("Broker.Offset() = %v, want 0", offset)
+	}
+	if err := b.Commit(ctx, "topic", "group", 2); err != nil {
+		t.Fatalf("Broker.Commit() error = %v", err)
+	}
+	if err := b.Commit(ctx, "topic", "group", 3); !ierrors.HasCode(err, ierrors.BadRequest) {
+		t.Errorf("Broker.Commit() past the last message error = %v", err)
+	}
+
+	// each consumer group has its own offset
+	if offset, _ := b.Offset(ctx, "topic", "group"); offset != 2 {
+		t.Errorf("Broker.Offset() = %v, want 2", offset)
+	}
+	if offset, _ := b.Offset(ctx, "topic", "other"); offset != 0 {
+		t.Errorf("Broker.Offset() of other group = %v, want 0", offset)
+	}
+}
+
+func TestBroker_topics(t *testing.T) {
+	ctx := context.Background()
+	b := NewBroker()
+
+	if _, err := b.Topic(ctx, "topic"); !ierrors.HasCode(err, ierrors.NotFound) {
+		t.Errorf("Broker.Topic() error = %v, want NotFound", err)
+	}
+	if err := b.CreateTopic(ctx, "topic"); err != nil {
+		t.Fatalf("Broker.CreateTopic() error = %v", err)
+	}
+	b.Write(ctx, "topic", newRecords("first", "second", "third"))
+
+	if info, _ := b.Topic(ctx, "topic"); info != (TopicInfo{Name: "topic", Messages: 3}) {
+		t.Errorf("Broker.Topic() = %v", info)
+	}
+	messages, _ := b.Messages(ctx, "topic", 2)
+	if want := [][]byte{[]byte("second"), []byte("third")}; !reflect.DeepEqual(messages, want) {
+		t.Errorf("Broker.Messages() = %s, want %s", messages, want)
+	}
+	if messages, _ := b.Messages(ctx, "topic", 0); len(messages) != 3 {
+		t.Errorf("Broker.Messages() without limit = %s", messages)
+	}
+
+	if err := b.DeleteTopic(ctx, "topic"); err != nil {
+		t.Fatalf("Broker.DeleteTopic() error = %v", err)
+	}
+	if _, err := b.Messages(ctx, "topic", 0); !ierrors.HasCode(err, ierrors.NotFound) {
+		t.Errorf("Broker.Messages() of deleted topic error = %v, want NotFound", err)
+	}
+	if err := b.DeleteTopic(ctx, "topic"); !ierrors.HasCode(err, ierrors.NotFound) {
+		t.Errorf("Broker.DeleteTopic() of deleted topic error = %v, want NotFound", err)
+	}
+}
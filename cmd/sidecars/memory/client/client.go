@@ -0,0 +1,111 @@
+package memorysc
+
+import (
+	"context"
+	"net/http"
+
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/rest/request"
+	"inspr.dev/inspr/pkg/sidecars/models"
+)
+
+// Client is the client of the in-memory broker service, through which the
+// sidecars and insprd reach the broker running in the cluster
+type Client struct {
+	c *request.Client
+}
+
+// NewClient returns a client of the in-memory broker service at the address
+func NewClient(addr string) *Client {
+	return &Client{c: request.NewJSONClient(addr)}
+}
+
+// Write appends the messages to the topic, in order
+func (c *Client) Write(ctx context.Context, topic string, records []models.BrokerRecord) error {
+	return c.c.Send(ctx, "/messages", http.MethodPost, topicRequest{
+		Topic:   topic,
+		Records: records,
+	}, nil)
+}
+
+// Read returns the message at the offset of the topic, waiting for it to be
+// written when there's none yet, until ctx is done
+func (c *Client) Read(ctx context.Context, topic string, offset int64) (models.BrokerRecord, error) {
+	for {
+		// the service responds with null when the poll times out
+		var record *models.BrokerRecord
+		err := c.c.Send(ctx, "/messages", http.MethodGet, topicRequest{
+			Topic:  topic,
+			Offset: offset,
+		}, &record)
+		if ctx.Err() != nil {
+			return models.BrokerRecord{}, ctx.Err()
+		}
+		if err != nil {
+			return models.BrokerRecord{}, err
+		}
+		if record != nil {
+			return *record, nil
+		}
+	}
+}
+
+// Offset returns the offset committed by the consumer group on the topic
+func (c *Client) Offset(ctx context.Context, topic, group string) (int64, error) {
+	var resp offsetResponse
+	err := c.c.Send(ctx, "/offsets", http.MethodGet, topicRequest{
+		Topic: topic,
+		Group: group,
+	}, &resp)
+	return resp.Offset, err
+}
+
+// Commit sets the offset committed by the consumer group on the topic
+func (c *Client) Commit(ctx context.Context, topic, group string, offset int64) error {
+	return c.c.Send(ctx, "/offsets", http.MethodPost, topicRequest{
+		Topic:  topic,
+		Group:  group,
+		Offset: offset,
+	}, nil)
+}
+
+// CreateTopic creates a topic, which is kept as it is when it already exists
+func (c *Client) CreateTopic(ctx context.Context, topic string) error {
+	return c.c.Send(ctx, "/topics", http.MethodPost, topicRequest{Topic: topic}, nil)
+}
+
+// DeleteTopic deletes a topic, along with its messages and offsets. Deleting
+// a topic that doesn't exist doesn't fail
+func (c *Client) DeleteTopic(ctx context.Context, topic string) error {
+	return c.c.Send(ctx, "/topics", http.MethodDelete, topicRequest{Topic: topic}, nil)
+}
+
+// Topic returns the description of a topic
+func (c *Client) Topic(ctx context.Context, topic string) (TopicInfo, error) {
+	var info *TopicInfo
+	err := c.c.Send(ctx, "/topics", http.MethodGet, topicRequest{Topic: topic}, &info)
+	if err != nil {
+		return TopicInfo{}, err
+	}
+	if info == nil {
+		return TopicInfo{}, ierrors.New("topic '%s' not found", topic).NotFound()
+	}
+	return *info, nil
+}
+
+// Messages returns the latest messages of a topic, up to the given limit,
+// oldest first. All of them are returned when there's no limit
+func (c *Client) Messages(ctx context.Context, topic string, limit int) ([][]byte, error) {
+	var messages [][]byte
+	err := c.c.Send(ctx, "/topics/messages", http.MethodGet, topicRequest{
+		Topic: topic,
+		Limit: limit,
+	}, &messages)
+	if err != nil {
+		return nil, err
+	}
+	if messages == nil {
+		return nil, ierrors.New("topic '%s' not found", topic).NotFound()
+	}
+	return messages, nil
+}
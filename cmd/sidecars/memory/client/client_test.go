@@ -0,0 +1,120 @@
+package memorysc
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/sidecars/models"
+)
+
+// newTestClient returns a client of a broker service served by httptest
+func newTestClient(t *testing.T, pollTimeout time.Duration) *Client {
+	broker := NewBroker()
+	broker.pollTimeout = pollTimeout
+	ts := httptest.NewServer(broker.Handler())
+	t.Cleanup(ts.Close)
+	return NewClient(ts.URL)
+}
+
+func TestClient_messages(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t, defaultPollTimeout)
+
+	if err := c.Write(ctx, "topic", newRecords("first", "second")); err != nil {
+		t.Fatalf("Client.Write() error = %v", err)
+	}
+
+	got, err := c.Read(ctx, "topic", 1)
+	if err != nil {
+		t.Fatalf("Client.Read() error = %v", err)
+	}
+	want := models.BrokerRecord{
+		Value:    []byte("second"),
+		Envelope: models.Envelope{Key: "second", Offset: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Client.Read() = %v, want %v", got, want)
+	}
+
+	if _, err := c.Read(ctx, "topic", -1); !ierrors.HasCode(err, ierrors.BadRequest) {
+		t.Errorf("Client.Read() of negative offset error = %v, want BadRequest", err)
+	}
+}
+
+func TestClient_Read_poll(t *testing.T) {
+	c := newTestClient(t, 10*time.Millisecond)
+
+	// the client polls the service again until the message is written
+	got := make(chan models.BrokerRecord)
+	go func() {
+		record, _ := c.Read(context.Background(), "topic", 0)
+		got <- record
+	}()
+	time.Sleep(50 * time.Millisecond)
+	c.Write(context.Background(), "topic", newRecords("late"))
+
+	select {
+	case record := <-got:
+		if string(record.Value) != "late" {
+			t.Errorf("Client.Read() = %s, want late", record.Value)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Client.Read() didn't return the message written")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.Read(ctx, "topic", 1); err != context.DeadlineExceeded {
+		t.Errorf("Client.Read() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestClient_offsets(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t, defaultPollTimeout)
+	c.Write(ctx, "topic", newRecords("first", "second"))
+
+	if err := c.Commit(ctx, "topic", "group", 1); err != nil {
+		t.Fatalf("Client.Commit() error = %v", err)
+	}
+	if offset, err := c.Offset(ctx, "topic", "group"); err != nil || offset != 1 {
+		t.Errorf("Client.Offset() = %v, %v, want 1", offset, err)
+	}
+	if err := c.Commit(ctx, "topic", "group", 3); !ierrors.HasCode(err, ierrors.BadRequest) {
+		t.Errorf("Client.Commit() past the last message error = %v, want BadRequest", err)
+	}
+}
+
+func TestClient_topics(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t, defaultPollTimeout)
+
+	if _, err := c.Topic(ctx, "topic"); !ierrors.HasCode(err, ierrors.NotFound) {
+		t.Errorf("Client.Topic() error = %v, want NotFound", err)
+	}
+	if _, err := c.Messages(ctx, "topic", 0); !ierrors.HasCode(err, ierrors.NotFound) {
+		t.Errorf("Client.Messages() error = %v, want NotFound", err)
+	}
+
+	if err := c.CreateTopic(ctx, "topic"); err != nil {
+		t.Fatalf("Client.CreateTopic() error = %v", err)
+	}
+	if messages, err := c.Messages(ctx, "topic", 0); err != nil || len(messages) != 0 {
+		t.Errorf("Client.Messages() of empty topic = %v, %v", messages, err)
+	}
+	c.Write(ctx, "topic", newRecords("first"))
+	if info, err := c.Topic(ctx, "topic"); err != nil || info != (TopicInfo{Name: "topic", Messages: 1}) {
+		t.Errorf("Client.Topic() = %v, %v", info, err)
+	}
+
+	if err := c.DeleteTopic(ctx, "topic"); err != nil {
+		t.Fatalf("Client.DeleteTopic() error = %v", err)
+	}
+	if _, err := c.Topic(ctx, "topic"); !ierrors.HasCode(err, ierrors.NotFound) {
+		t.Errorf("Client.Topic() of deleted topic error = %v, want NotFound", err)
+	}
+}
@@ -0,0 +1,29 @@
+package memorysc
+
+import (
+	"os"
+
+	"go.uber.org/zap"
+	"inspr.dev/inspr/pkg/logs"
+)
+
+// AddrEnv is the environment variable of the address of the in-memory broker
+// service the sidecar reads from and writes to
+const AddrEnv = "INSPR_MEMORY_BROKER_ADDR"
+
+var logger *zap.Logger
+
+// init is called after all the variable declarations in the package have evaluated
+// their initializers, and those are evaluated only after all the imported packages
+// have been initialized
+func init() {
+	logger, _ = logs.Logger(zap.Fields(zap.String("section", "memory-sidecar")))
+}
+
+// GetBrokerAddr returns the address of the in-memory broker service
+func GetBrokerAddr() string {
+	if value, exists := os.LookupEnv(AddrEnv); exists {
+		return value
+	}
+	panic("[ENV VAR] " + AddrEnv + " not found")
+}
@@ -0,0 +1,142 @@
+package memorysc
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+	globalEnv "inspr.dev/inspr/pkg/environment"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta/brokers"
+	"inspr.dev/inspr/pkg/sidecars/models"
+)
+
+// Reader reads/commit messages from the channels of the in-memory broker
+// defined in the env. It reads as the consumer group of the dApp, so that the
+// dApp goes on from the offset it last committed on each channel
+type Reader struct {
+	store     Store
+	group     string
+	consumers map[string]*consumer
+}
+
+// consumer keeps the position of Reader on the topic of a channel
+type consumer struct {
+	topic string
+	// reading is locked while a message is read, so that the messages of the
+	// channel are read in order
+	reading sync.Mutex
+
+	mutex sync.Mutex
+	// next is the offset of the next message to be read, which is -1 until
+	// the committed offset is fetched from the broker
+	next int64
+	// uncommitted are the offsets of the messages read that weren't
+	// committed yet, in the order they were read
+	uncommitted []int64
+}
+
+// NewReader returns a new Reader of the input channels of the in-memory broker,
+// which reads from the given store
+func NewReader(store Store) (*Reader, error) {
+	logger.Info("creating new memory reader")
+	channels := globalEnv.GetInputBrokerChannels(brokers.Memory)
+	if len(channels) == 0 {
+		logger.Error("invalid channel list")
+		return nil, ierrors.New(
+			"INSPR_INPUT_CHANNELS doesn't have channels of the memory broker",
+		).InvalidChannel()
+	}
+
+	reader := &Reader{
+		store:     store,
+		group:     globalEnv.GetInsprAppID(),
+		consumers: make(map[string]*consumer),
+	}
+	for _, ch := range channels {
+		resolved, _ := globalEnv.GetResolvedChannel(ch, globalEnv.GetInputChannelsData(), nil)
+		reader.consumers[ch] = &consumer{topic: resolved, next: -1}
+	}
+
+	logger.Debug("new reader created!")
+	return reader, nil
+}
+
+// ReadMessage reads the next message of the channel, waiting for it to be
+// written when there's none yet. Returns the message, along with its envelope,
+// and an error if any occurred
+func (reader *Reader) ReadMessage(ctx context.Context, channel string) (models.BrokerRecord, error) {
+	c, ok := reader.consumers[channel]
+	if !ok {
+		return models.BrokerRecord{}, ierrors.New(
+			"channel %s not listed as an input of the memory broker", channel,
+		).InvalidChannel()
+	}
+
+	logger.Info("trying to read message from topic",
+		zap.String("channel", channel),
+		zap.String("topic", c.topic),
+	)
+
+	c.reading.Lock()
+	defer c.reading.Unlock()
+
+	c.mutex.Lock()
+	next := c.next
+	c.mutex.Unlock()
+	if next < 0 {
+		offset, err := reader.store.Offset(ctx, c.topic, reader.group)
+		if err != nil {
+			return models.BrokerRecord{}, err
+		}
+		next = offset
+	}
+
+	record, err := reader.store.Read(ctx, c.topic, next)
+	if err != nil {
+		return models.BrokerRecord{}, err
+	}
+
+	c.mutex.Lock()
+	c.next = next + 1
+	c.uncommitted = append(c.uncommitted, next)
+	c.mutex.Unlock()
+
+	return record, nil
+}
+
+// Commit commits the oldest message read by Reader from the channel that
+// wasn't committed yet, so that messages handled concurrently are committed
+// in the order they were read by committing each of them once it's handled
+// along with the ones read before it
+func (reader *Reader) Commit(ctx context.Context, channel string) error {
+	logger.Info("committing to channel", zap.String("channel", channel))
+
+	c, ok := reader.consumers[channel]
+	if !ok {
+		return ierrors.New(
+			"channel %s not listed as an input of the memory broker", channel,
+		).InvalidChannel()
+	}
+
+	// the offset is only dropped once it's committed, so that a failed commit
+	// is retried by the next one
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if len(c.uncommitted) == 0 {
+		return nil
+	}
+	// the committed offset is the one of the next message to be read
+	offset := c.uncommitted[0] + 1
+	if err := reader.store.Commit(ctx, c.topic, reader.group, offset); err != nil {
+		return ierrors.Wrap(err, "failed to commit last message")
+	}
+	c.uncommitted = c.uncommitted[1:]
+	return nil
+}
+
+// Close closes the reader. The offsets it didn't commit are read again by the
+// next reader of the dApp
+func (reader *Reader) Close() error {
+	return nil
+}
@@ -0,0 +1,123 @@
+package memorysc
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"inspr.dev/inspr/pkg/ierrors"
+)
+
+func createMockEnv() {
+	os.Setenv("INSPR_INPUT_CHANNELS", "ch1@memory;ch2@kafka")
+	os.Setenv("INSPR_OUTPUT_CHANNELS", "ch1@memory")
+	os.Setenv("ch1_RESOLVED", "ch1_resolved")
+	os.Setenv("ch2_RESOLVED", "ch2_resolved")
+	os.Setenv("INSPR_APP_ID", "testappid1")
+}
+
+func deleteMockEnv() {
+	os.Unsetenv("INSPR_INPUT_CHANNELS")
+	os.Unsetenv("INSPR_OUTPUT_CHANNELS")
+	os.Unsetenv("ch1_RESOLVED")
+	os.Unsetenv("ch2_RESOLVED")
+	os.Unsetenv("INSPR_APP_ID")
+}
+
+func TestNewReader(t *testing.T) {
+	createMockEnv()
+	defer deleteMockEnv()
+
+	reader, err := NewReader(NewBroker())
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	// only the channels of the memory broker are read
+	if len(reader.consumers) != 1 || reader.consumers["ch1"].topic != "ch1_resolved" {
+		t.Errorf("NewReader() consumers = %v", reader.consumers)
+	}
+
+	os.Setenv("INSPR_INPUT_CHANNELS", "ch2@kafka")
+	if _, err := NewReader(NewBroker()); !ierrors.HasCode(err, ierrors.InvalidChannel) {
+		t.Errorf("NewReader() without memory channels error = %v, want InvalidChannel", err)
+	}
+}
+
+func TestReader_ReadMessage(t *testing.T) {
+	createMockEnv()
+	defer deleteMockEnv()
+	ctx := context.Background()
+
+	broker := NewBroker()
+	broker.Write(ctx, "ch1_resolved", newRecords("first", "second", "third"))
+	broker.Commit(ctx, "ch1_resolved", "testappid1", 1)
+
+	reader, _ := NewReader(broker)
+	if _, err := reader.ReadMessage(ctx, "ch2"); !ierrors.HasCode(err, ierrors.InvalidChannel) {
+		t.Errorf("Reader.ReadMessage() of kafka channel error = %v, want InvalidChannel", err)
+	}
+
+	// the reader goes on from the offset committed by the dApp
+	for _, want := range []string{"second", "third"} {
+		record, err := reader.ReadMessage(ctx, "ch1")
+		if err != nil {
+			t.Fatalf("Reader.ReadMessage() error = %v", err)
+		}
+		if string(record.Value) != want {
+			t.Errorf("Reader.ReadMessage() = %s, want %s", record.Value, want)
+		}
+	}
+
+	// each commit commits the oldest message read that wasn't committed
+	reader.Commit(ctx, "ch1")
+	if offset, _ := broker.Offset(ctx, "ch1_resolved", "testappid1"); offset != 2 {
+		t.Errorf("Reader.Commit() offset = %v, want 2", offset)
+	}
+	reader.Commit(ctx, "ch1")
+	if offset, _ := broker.Offset(ctx, "ch1_resolved", "testappid1"); offset != 3 {
+		t.Errorf("Reader.Commit() offset = %v, want 3", offset)
+	}
+	if err := reader.Commit(ctx, "ch1"); err != nil {
+		t.Errorf("Reader.Commit() without messages read error = %v", err)
+	}
+}
+
+// failingStore is a store whose commits fail while fail is set
+type failingStore struct {
+	*Broker
+	fail bool
+}
+
+func (s *failingStore) Commit(ctx context.Context, topic, group string, offset int64) error {
+	if s.fail {
+		return ierrors.New("commit failed").InternalServer()
+	}
+	return s.Broker.Commit(ctx, topic, group, offset)
+}
+
+func TestReader_Commit_failed(t *testing.T) {
+	createMockEnv()
+	defer deleteMockEnv()
+	ctx := context.Background()
+
+	store := &failingStore{Broker: NewBroker(), fail: true}
+	store.Write(ctx, "ch1_resolved", newRecords("first"))
+
+	reader, _ := NewReader(store)
+	if _, err := reader.ReadMessage(ctx, "ch1"); err != nil {
+		t.Fatalf("Reader.ReadMessage() error = %v", err)
+	}
+
+	if err := reader.Commit(ctx, "ch1"); err == nil {
+		t.Fatalf("Reader.Commit() with failing store error = nil")
+	}
+
+	// the message that failed to be committed is committed by the next commit
+	store.fail = false
+	if err := reader.Commit(ctx, "ch1"); err != nil {
+		t.Fatalf("Reader.Commit() error = %v", err)
+	}
+	if offset, _ := store.Offset(ctx, "ch1_resolved", "testappid1"); offset != 1 {
+		t.Errorf("Reader.Commit() offset = %v, want 1", offset)
+	}
+}
@@ -0,0 +1,155 @@
+package memorysc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/rest"
+	"inspr.dev/inspr/pkg/sidecars/models"
+)
+
+// defaultPollTimeout bounds the time a read request waits for a message to be
+// written. Clients send the read again when it runs out
+const defaultPollTimeout = 10 * time.Second
+
+// topicRequest is the body of the requests to the broker service
+type topicRequest struct {
+	Topic   string                `json:"topic"`
+	Group   string                `json:"group,omitempty"`
+	Offset  int64                 `json:"offset,omitempty"`
+	Limit   int                   `json:"limit,omitempty"`
+	Records []models.BrokerRecord `json:"records,omitempty"`
+}
+
+// offsetResponse is the body of the responses to offset requests
+type offsetResponse struct {
+	Offset int64 `json:"offset"`
+}
+
+// Handler returns the handler of the HTTP API of the broker, through which
+// the broker service is reached by Client. Lookups of topics and messages
+// that don't exist respond with null, which Client turns into NotFound errors
+func (b *Broker) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", rest.Healthz())
+	mux.Handle("/topics", b.topicsHandler().JSON())
+	mux.Handle("/topics/messages", b.latestMessagesHandler().JSON().Get())
+	mux.Handle("/messages", b.messagesHandler().JSON())
+	mux.Handle("/offsets", b.offsetsHandler().JSON())
+	return mux
+}
+
+// topicsHandler describes, creates and deletes topics
+func (b *Broker) topicsHandler() rest.Handler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, err := decodeTopicRequest(r)
+		if err != nil {
+			rest.ERROR(w, err)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			info, err := b.Topic(r.Context(), req.Topic)
+			respond(w, &info, err)
+		case http.MethodPost:
+			respond(w, nil, b.CreateTopic(r.Context(), req.Topic))
+		case http.MethodDelete:
+			respond(w, nil, b.DeleteTopic(r.Context(), req.Topic))
+		default:
+			rest.ERROR(w, ierrors.New("method %s not allowed", r.Method).BadRequest())
+		}
+	}
+}
+
+// latestMessagesHandler returns the latest messages of topics
+func (b *Broker) latestMessagesHandler() rest.Handler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, err := decodeTopicRequest(r)
+		if err != nil {
+			rest.ERROR(w, err)
+			return
+		}
+
+		messages, err := b.Messages(r.Context(), req.Topic, req.Limit)
+		respond(w, messages, err)
+	}
+}
+
+// messagesHandler reads and writes the messages of topics. Reads wait for
+// the message to be written up to the poll timeout
+func (b *Broker) messagesHandler() rest.Handler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, err := decodeTopicRequest(r)
+		if err != nil {
+			rest.ERROR(w, err)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			ctx, cancel := context.WithTimeout(r.Context(), b.pollTimeout)
+			defer cancel()
+
+			record, err := b.Read(ctx, req.Topic, req.Offset)
+			if err == context.DeadlineExceeded {
+				err = ierrors.New(
+					"no message at offset %d of topic '%s'", req.Offset, req.Topic,
+				).NotFound()
+			}
+			respond(w, &record, err)
+		case http.MethodPost:
+			respond(w, nil, b.Write(r.Context(), req.Topic, req.Records))
+		default:
+			rest.ERROR(w, ierrors.New("method %s not allowed", r.Method).BadRequest())
+		}
+	}
+}
+
+// offsetsHandler returns and commits the offsets of consumer groups
+func (b *Broker) offsetsHandler() rest.Handler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, err := decodeTopicRequest(r)
+		if err != nil {
+			rest.ERROR(w, err)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			offset, err := b.Offset(r.Context(), req.Topic, req.Group)
+			respond(w, offsetResponse{Offset: offset}, err)
+		case http.MethodPost:
+			respond(w, nil, b.Commit(r.Context(), req.Topic, req.Group, req.Offset))
+		default:
+			rest.ERROR(w, ierrors.New("method %s not allowed", r.Method).BadRequest())
+		}
+	}
+}
+
+// decodeTopicRequest decodes the body of a request to the broker service,
+// which must have a topic
+func decodeTopicRequest(r *http.Request) (topicRequest, error) {
+	var req topicRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Topic == "" {
+		return req, ierrors.New("invalid request, it must have a topic").BadRequest()
+	}
+	return req, nil
+}
+
+// respond writes the result of an operation of the broker, which is null
+// when what it looked up wasn't found
+func respond(w http.ResponseWriter, data interface{}, err error) {
+	if ierrors.HasCode(err, ierrors.NotFound) {
+		rest.JSON(w, http.StatusOK, nil)
+		return
+	}
+	if err != nil {
+		rest.ERROR(w, err)
+		return
+	}
+	rest.JSON(w, http.StatusOK, data)
+}
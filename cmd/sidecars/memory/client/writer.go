@@ -0,0 +1,47 @@
+package memorysc
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"inspr.dev/inspr/pkg/environment"
+	"inspr.dev/inspr/pkg/sidecars/models"
+)
+
+// Writer writes messages to the topics of the in-memory broker
+type Writer struct {
+	store Store
+}
+
+// NewWriter returns a new Writer, which writes to the given store
+func NewWriter(store Store) (*Writer, error) {
+	return &Writer{store: store}, nil
+}
+
+// WriteMessage receives a message and sends it to the topic defined by the given channel
+func (writer *Writer) WriteMessage(channel string, message models.BrokerRecord) error {
+	return writer.WriteMessages(channel, []models.BrokerRecord{message})
+}
+
+// WriteMessages receives a batch of messages and sends all of them to the topic
+// defined by the given channel, in the order they are given
+func (writer *Writer) WriteMessages(channel string, messages []models.BrokerRecord) error {
+	resolvedCh, err := environment.GetResolvedChannel(channel, nil, environment.GetOutputChannelsData())
+	if err != nil {
+		return err
+	}
+
+	logger.Info("trying to write messages in topic",
+		zap.String("channel", channel),
+		zap.String("resolved channel", resolvedCh),
+		zap.Int("messages", len(messages)))
+
+	if err := writer.store.Write(context.Background(), resolvedCh, messages); err != nil {
+		logger.Error("error while writing messages", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Close closes the writer
+func (writer *Writer) Close() {}
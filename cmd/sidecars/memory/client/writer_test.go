@@ -0,0 +1,35 @@
+package memorysc
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"inspr.dev/inspr/pkg/ierrors"
+)
+
+func TestWriter_WriteMessages(t *testing.T) {
+	createMockEnv()
+	defer deleteMockEnv()
+	ctx := context.Background()
+
+	broker := NewBroker()
+	writer, _ := NewWriter(broker)
+	defer writer.Close()
+
+	if err := writer.WriteMessage("ch1", newRecords("first")[0]); err != nil {
+		t.Fatalf("Writer.WriteMessage() error = %v", err)
+	}
+	if err := writer.WriteMessages("ch1", newRecords("second", "third")); err != nil {
+		t.Fatalf("Writer.WriteMessages() error = %v", err)
+	}
+	if err := writer.WriteMessage("ch2", newRecords("first")[0]); !ierrors.HasCode(err, ierrors.InvalidChannel) {
+		t.Errorf("Writer.WriteMessage() to input channel error = %v, want InvalidChannel", err)
+	}
+
+	got, _ := broker.Messages(ctx, "ch1_resolved", 0)
+	want := [][]byte{[]byte("first"), []byte("second"), []byte("third")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Writer.WriteMessages() wrote %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"go.uber.org/zap"
+	memorysc "inspr.dev/inspr/cmd/sidecars/memory/client"
+	"inspr.dev/inspr/pkg/logs"
+)
+
+// defaultPort is the port the in-memory broker listens on when
+// INSPR_MEMORY_BROKER_PORT isn't set
+const defaultPort = "8080"
+
+var logger *zap.Logger
+
+// init is called after all the variable declarations in the package have evaluated
+// their initializers, and those are evaluated only after all the imported packages
+// have been initialized
+func init() {
+	logger, _ = logs.Logger(zap.Fields(zap.String("section", "memory-broker")))
+}
+
+func main() {
+	port, exists := os.LookupEnv("INSPR_MEMORY_BROKER_PORT")
+	if !exists {
+		port = defaultPort
+	}
+
+	broker := memorysc.NewBroker()
+
+	logger.Info("in-memory broker is up!", zap.String("port", port))
+	logger.Fatal("in-memory broker crashed",
+		zap.Error(http.ListenAndServe(fmt.Sprintf(":%s", port), broker.Handler())))
+}
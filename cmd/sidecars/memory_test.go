@@ -0,0 +1,32 @@
+package sidecars
+
+import (
+	"reflect"
+	"testing"
+
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/meta/brokers"
+	"inspr.dev/inspr/pkg/operator/k8s"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestMemoryToDeployment(t *testing.T) {
+	config := MemoryConfig{Address: "http://memory-broker:8080"}
+	if config.Broker() != brokers.Memory {
+		t.Errorf("MemoryConfig.Broker() = %v, want %v", config.Broker(), brokers.Memory)
+	}
+
+	app := meta.App{Meta: meta.Metadata{Name: "dapp", UUID: "dappUUID"}}
+	got, env := MemoryToDeployment(config)(&app, &testPorts, InsprAppIDConfig(&app))
+	if env != nil {
+		t.Errorf("MemoryToDeployment() env = %v, want nil", env)
+	}
+
+	want := k8s.NewContainer("", "", InsprAppIDConfig(&app), k8s.ContainerWithEnv(corev1.EnvVar{
+		Name:  "INSPR_MEMORY_BROKER_ADDR",
+		Value: "http://memory-broker:8080",
+	}))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MemoryToDeployment() = %v, want %v", got, want)
+	}
+}
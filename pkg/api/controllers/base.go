@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"context"
 	"log"
 	"net/http"
 
@@ -24,10 +25,11 @@ func init() {
 // Server is a struct that contains the variables necessary
 // to handle the necessary routes of the rest API
 type Server struct {
-	mux    *http.ServeMux
-	memory memory.Manager
-	op     operators.OperatorInterface
-	auth   auth.Auth
+	mux        *http.ServeMux
+	memory     memory.Manager
+	op         operators.OperatorInterface
+	auth       auth.Auth
+	reconciler *operators.Reconciler
 }
 
 // Init - configures the server
@@ -38,6 +40,7 @@ func (s *Server) Init(mem memory.Manager, op operators.OperatorInterface, auth a
 	s.memory = mem
 	s.op = op
 	s.auth = auth
+	s.reconciler = operators.NewReconciler(mem.Tree(), op)
 	s.initRoutes()
 }
 
@@ -46,5 +49,7 @@ func (s *Server) Run(addr string) {
 	logger = logger.With(zap.String("port", addr))
 	logger.Info("running Insprd server")
 
+	go s.reconciler.Run(context.Background())
+
 	log.Fatal(http.ListenAndServe(addr, s.mux))
 }
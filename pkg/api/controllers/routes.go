@@ -19,6 +19,7 @@ func (s *Server) initRoutes() {
 
 	chandler := h.NewChannelHandler()
 	s.mux.Handle("/channels", rest.HandleCRUD(chandler))
+	s.mux.Handle("/channels/deadletters", chandler.HandleDeadLetters().JSON().Validate(s.auth).Get())
 
 	thandler := h.NewTypeHandler()
 	s.mux.Handle("/types", rest.HandleCRUD(thandler))
@@ -26,12 +27,57 @@ func (s *Server) initRoutes() {
 	aliasHandler := h.NewAliasHandler()
 	s.mux.Handle("/alias", rest.HandleCRUD(aliasHandler))
 
+	templateHandler := h.NewTemplateHandler()
+	s.mux.Handle("/templates", rest.HandleCRUD(templateHandler))
+
+	instanceHandler := h.NewInstanceHandler()
+	s.mux.Handle("/templates/instances", rest.HandleCRUD(instanceHandler))
+
 	brokersHandler := h.NewBrokerHandler()
 	s.mux.Handle("/brokers", brokersHandler.HandleGet().JSON().Validate(s.auth).Get())
 	s.mux.Handle(
 		"/brokers/"+metabrokers.Kafka,
 		brokersHandler.KafkaCreateHandler().JSON().Validate(s.auth).Post(),
 	)
+	s.mux.Handle(
+		"/brokers/"+metabrokers.Memory,
+		brokersHandler.MemoryCreateHandler().JSON().Validate(s.auth).Post(),
+	)
+
+	revisionHandler := h.NewRevisionHandler()
+	s.mux.Handle("/revisions", revisionHandler.HandleHistory().JSON().Validate(s.auth).Get())
+	s.mux.Handle(
+		"/revisions/rollback",
+		revisionHandler.HandleRollback().
+			JSON().
+			Validate(s.auth).
+			Recover(h.GetCancel()).
+			Put(),
+	)
+
+	snapshotHandler := h.NewSnapshotHandler()
+	s.mux.Handle("/snapshot", snapshotHandler.HandleExport().JSON().Validate(s.auth).Get())
+	s.mux.Handle(
+		"/snapshot/import",
+		snapshotHandler.HandleImport().
+			JSON().
+			Validate(s.auth).
+			Recover(h.GetCancel()).
+			Put(),
+	)
+
+	statusHandler := h.NewStatusHandler(s.reconciler)
+	s.mux.Handle("/status", statusHandler.HandleStatus().JSON().Validate(s.auth).Get())
+	s.mux.Handle(
+		"/status/reconcile",
+		statusHandler.HandleReconcile().JSON().Validate(s.auth).Put(),
+	)
+
+	graphHandler := h.NewGraphHandler()
+	s.mux.Handle("/graph", graphHandler.HandleGet().JSON().Validate(s.auth).Get())
+
+	watchHandler := h.NewWatchHandler()
+	s.mux.Handle("/watch", watchHandler.HandleWatch().Validate(s.auth).Get())
 
 	s.mux.Handle("/auth", h.TokenHandler().Validate(s.auth))
 	s.mux.Handle("/refreshController", h.ControllerRefreshHandler())
@@ -56,6 +56,16 @@ func TestServer_initRoutes(t *testing.T) {
 				http.StatusMethodNotAllowed,
 			},
 		},
+		{
+			name: "channels/deadletters",
+			want: [...]int{
+				http.StatusInternalServerError,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+			},
+		},
 		{
 			name: "types",
 			want: [...]int{
@@ -76,6 +86,26 @@ func TestServer_initRoutes(t *testing.T) {
 				http.StatusMethodNotAllowed,
 			},
 		},
+		{
+			name: "templates",
+			want: [...]int{
+				http.StatusInternalServerError,
+				http.StatusInternalServerError,
+				http.StatusInternalServerError,
+				http.StatusInternalServerError,
+				http.StatusMethodNotAllowed,
+			},
+		},
+		{
+			name: "templates/instances",
+			want: [...]int{
+				http.StatusInternalServerError,
+				http.StatusInternalServerError,
+				http.StatusInternalServerError,
+				http.StatusInternalServerError,
+				http.StatusMethodNotAllowed,
+			},
+		},
 		{
 			name: "brokers",
 			want: [...]int{
@@ -96,6 +126,96 @@ func TestServer_initRoutes(t *testing.T) {
 				http.StatusMethodNotAllowed,
 			},
 		},
+		{
+			name: "brokers/memory",
+			want: [...]int{
+				http.StatusMethodNotAllowed,
+				http.StatusInternalServerError,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+			},
+		},
+		{
+			name: "revisions",
+			want: [...]int{
+				http.StatusInternalServerError,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+			},
+		},
+		{
+			name: "revisions/rollback",
+			want: [...]int{
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+				http.StatusInternalServerError,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+			},
+		},
+		{
+			name: "snapshot",
+			want: [...]int{
+				http.StatusInternalServerError,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+			},
+		},
+		{
+			name: "snapshot/import",
+			want: [...]int{
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+				http.StatusInternalServerError,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+			},
+		},
+		{
+			name: "status",
+			want: [...]int{
+				http.StatusInternalServerError,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+			},
+		},
+		{
+			name: "status/reconcile",
+			want: [...]int{
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+				http.StatusInternalServerError,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+			},
+		},
+		{
+			name: "graph",
+			want: [...]int{
+				http.StatusInternalServerError,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+			},
+		},
+		{
+			name: "watch",
+			want: [...]int{
+				http.StatusInternalServerError,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+				http.StatusMethodNotAllowed,
+			},
+		},
 		{
 			name: "wrong_route",
 			want: [...]int{
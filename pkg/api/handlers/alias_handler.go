@@ -6,6 +6,8 @@ import (
 
 	"go.uber.org/zap"
 	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/meta"
+	metautils "inspr.dev/inspr/pkg/meta/utils"
 	"inspr.dev/inspr/pkg/rest"
 )
 
@@ -67,16 +69,12 @@ func (ah *AliasHandler) HandleCreate() rest.Handler {
 
 		if !data.DryRun {
 			l.Debug("applying Alias create changes in diff")
-			err = ah.applyChangesInDiff(changes)
+			err = ah.applyChangesInDiff(changes, author(r))
 			if err != nil {
 				l.Error("unable to apply Alias create changes in diff", zap.Error(err))
 				rest.ERROR(w, err)
-				ah.Memory.Tree().Cancel()
 				return
 			}
-
-			l.Info("committing Alias create changes")
-			defer ah.Memory.Tree().Commit()
 		} else {
 			l.Debug("cancelling Alias create changes")
 			defer ah.Memory.Tree().Cancel()
@@ -100,7 +98,6 @@ func (ah *AliasHandler) HandleGet() rest.Handler {
 		if err != nil {
 			l.Error("unable to decode Alias get request data", zap.Error(err))
 			rest.ERROR(w, err)
-			ah.Memory.Tree().Cancel()
 			return
 		}
 
@@ -109,19 +106,21 @@ func (ah *AliasHandler) HandleGet() rest.Handler {
 			zap.String("scope", scope),
 			zap.Bool("dry-run", data.DryRun),
 		)
-		l.Debug("initiating Alias get transaction")
-		ah.Memory.Tree().InitTransaction()
+		if data.Selector != nil {
+			ah.selectComponents(w, l, scope, *data.Selector,
+				func(s metautils.Selector, app *meta.App) interface{} {
+					return s.SelectAliases(app, scope)
+				})
+			return
+		}
 
 		app, err := ah.Memory.Tree().Perm().Alias().Get(scope, data.Name)
 		if err != nil {
 			l.Error("unable to get Alias", zap.Error(err))
 			rest.ERROR(w, err)
-			ah.Memory.Tree().Cancel()
 			return
 		}
 
-		defer ah.Memory.Tree().Cancel()
-
 		rest.JSON(w, http.StatusOK, app)
 	}
 	return rest.Handler(handler)
@@ -169,16 +168,12 @@ func (ah *AliasHandler) HandleUpdate() rest.Handler {
 
 		if !data.DryRun {
 			l.Debug("applying Alias update changes in diff")
-			err = ah.applyChangesInDiff(changes)
+			err = ah.applyChangesInDiff(changes, author(r))
 			if err != nil {
 				l.Error("unable to apply Alias update changes in diff", zap.Error(err))
 				rest.ERROR(w, err)
-				ah.Memory.Tree().Cancel()
 				return
 			}
-
-			l.Info("committing Alias update changes")
-			defer ah.Memory.Tree().Commit()
 		} else {
 			l.Debug("cancelling Alias update changes")
 			defer ah.Memory.Tree().Cancel()
@@ -213,6 +208,17 @@ func (ah *AliasHandler) HandleDelete() rest.Handler {
 		l.Debug("initiating Alias delete transaction")
 		ah.Memory.Tree().InitTransaction()
 
+		current, err := ah.Memory.Tree().Alias().Get(scope, data.Name)
+		if err == nil {
+			err = metautils.ValidateResourceVersion(current.Meta, data.ResourceVersion)
+		}
+		if err != nil {
+			l.Error("unable to delete Alias", zap.Error(err))
+			rest.ERROR(w, err)
+			ah.Memory.Tree().Cancel()
+			return
+		}
+
 		err = ah.Memory.Tree().Alias().Delete(scope, data.Name)
 		if err != nil {
 			l.Error("unable to delete Alias", zap.Error(err))
@@ -231,16 +237,12 @@ func (ah *AliasHandler) HandleDelete() rest.Handler {
 
 		if !data.DryRun {
 			l.Debug("applying Alias delete changes in diff")
-			err = ah.applyChangesInDiff(changes)
+			err = ah.applyChangesInDiff(changes, author(r))
 			if err != nil {
 				l.Error("unable to apply Alias delete changes in diff", zap.Error(err))
 				rest.ERROR(w, err)
-				ah.Memory.Tree().Cancel()
 				return
 			}
-
-			l.Info("committing Alias delete changes")
-			defer ah.Memory.Tree().Commit()
 		} else {
 			l.Debug("cancelling Alias delete changes")
 			defer ah.Memory.Tree().Cancel()
@@ -11,6 +11,7 @@ import (
 	"inspr.dev/inspr/pkg/ierrors"
 	"inspr.dev/inspr/pkg/meta"
 	"inspr.dev/inspr/pkg/meta/utils"
+	metautils "inspr.dev/inspr/pkg/meta/utils"
 	"inspr.dev/inspr/pkg/rest"
 )
 
@@ -77,16 +78,12 @@ func (ah *AppHandler) HandleCreate() rest.Handler {
 
 		if !data.DryRun {
 			l.Debug("applying changes to the cluster")
-			err = ah.applyChangesInDiff(changes)
+			err = ah.applyChangesInDiff(changes, author(r))
 			if err != nil {
 				l.Error("unable to apply dApp create changes in diff", zap.Error(err))
 				rest.ERROR(w, err)
-				ah.Memory.Tree().Cancel()
 				return
 			}
-
-			l.Info("changes completed - committing...")
-			defer ah.Memory.Tree().Commit()
 		} else {
 			l.Debug("cancelling dApp create changes")
 			defer ah.Memory.Tree().Cancel()
@@ -115,19 +112,21 @@ func (ah *AppHandler) HandleGet() rest.Handler {
 		}
 		l = l.With(zap.String("scope", scope), zap.Bool("dry-run", data.DryRun))
 
-		l.Debug("initiating dApp get transaction")
-		ah.Memory.Tree().InitTransaction()
+		if data.Selector != nil {
+			ah.selectComponents(w, l, scope, *data.Selector,
+				func(s metautils.Selector, app *meta.App) interface{} {
+					return s.SelectApps(app, scope)
+				})
+			return
+		}
 
-		app, err := ah.Memory.Tree().Apps().Get(scope)
+		app, err := ah.Memory.Tree().Perm().Apps().Get(scope)
 		if err != nil {
 			l.Error("unable to get dApp", zap.Error(err))
 			rest.ERROR(w, err)
-			ah.Memory.Tree().Cancel()
 			return
 		}
 
-		defer ah.Memory.Tree().Cancel()
-
 		rest.JSON(w, http.StatusOK, app)
 	}
 	return rest.Handler(handler)
@@ -179,18 +178,15 @@ func (ah *AppHandler) HandleUpdate() rest.Handler {
 
 		if !data.DryRun {
 			l.Debug("applying dApp update changes in diff")
-			err = ah.applyChangesInDiff(changes)
+			err = ah.applyChangesInDiff(changes, author(r))
 			if err != nil {
 				l.Error("unable to apply dApp update changes in diff", zap.Error(err))
 				rest.ERROR(w, err)
-				ah.Memory.Tree().Cancel()
 				return
 			}
-
-			l.Info("committing dApp update changes")
-			defer ah.Memory.Tree().Commit()
 		} else {
 			l.Debug("cancelling dApp update changes")
+			ah.addCompatibility(changes)
 			defer ah.Memory.Tree().Cancel()
 		}
 
@@ -219,6 +215,17 @@ func (ah *AppHandler) HandleDelete() rest.Handler {
 		l.Info("initiating dApp delete transaction")
 		ah.Memory.Tree().InitTransaction()
 
+		current, err := ah.Memory.Tree().Apps().Get(scope)
+		if err == nil {
+			err = metautils.ValidateResourceVersion(current.Meta, data.ResourceVersion)
+		}
+		if err != nil {
+			l.Error("unable to delete dApp", zap.Error(err))
+			rest.ERROR(w, err)
+			ah.Memory.Tree().Cancel()
+			return
+		}
+
 		err = ah.Memory.Tree().Apps().Delete(scope)
 		if err != nil {
 			l.Error("unable to delete dApp", zap.Error(err))
@@ -237,16 +244,12 @@ func (ah *AppHandler) HandleDelete() rest.Handler {
 
 		if !data.DryRun {
 			l.Debug("applying dApp delete changes in diff")
-			err = ah.applyChangesInDiff(changes)
+			err = ah.applyChangesInDiff(changes, author(r))
 			if err != nil {
 				l.Error("unable to apply dApp delete changes in diff", zap.Error(err))
 				rest.ERROR(w, err)
-				ah.Memory.Tree().Cancel()
 				return
 			}
-
-			l.Info("committing dApp delete changes")
-			defer ah.Memory.Tree().Commit()
 		} else {
 			l.Debug("cancelling dApp delete changes")
 			defer ah.Memory.Tree().Cancel()
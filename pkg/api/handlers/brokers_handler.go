@@ -78,3 +78,38 @@ func (bh *BrokerHandler) KafkaCreateHandler() rest.Handler {
 	}
 	return rest.Handler(handler)
 }
+
+// MemoryCreateHandler is the function that processes requests at the /brokers/memory endpoint
+func (bh *BrokerHandler) MemoryCreateHandler() rest.Handler {
+	l := bh.logger.With(zap.String("operation", "create"), zap.String("broker", "memory"))
+	l.Info("received memory broker create request")
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		// decode into the bytes of yaml file
+		var content models.BrokerConfigDI
+		err := json.NewDecoder(r.Body).Decode(&content)
+		if err != nil {
+			rest.ERROR(w, err)
+			return
+		}
+
+		var memoryConfig sidecars.MemoryConfig
+		// parsing the bytes into a memory broker config structure
+		err = yaml.Unmarshal(content.FileContents, &memoryConfig)
+		if err != nil {
+			l.Error("unable to unmarshall config", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+
+		if err = bh.Memory.Brokers().Create(
+			&memoryConfig,
+		); err != nil {
+			l.Error("error creating memory broker on memory", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+
+		rest.JSON(w, http.StatusOK, nil)
+	}
+	return rest.Handler(handler)
+}
@@ -215,3 +215,52 @@ func TestBrokerHandler_KafkaHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestBrokerHandler_MemoryHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     []byte
+		brokers  error
+		wantCode int
+	}{
+		{
+			name:     "error_reading_body",
+			body:     []byte{1},
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name:     "broker error",
+			body:     []byte(`{}`),
+			brokers:  errors.New("brokerManager_error"),
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name:     "working",
+			body:     []byte(`{}`),
+			wantCode: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bh := &BrokerHandler{
+				Handler: &Handler{Memory: fake.GetMockMemoryManager(nil, tt.brokers)},
+				logger:  logger,
+			}
+
+			ts := httptest.NewServer(bh.MemoryCreateHandler().HTTPHandlerFunc())
+			defer ts.Close()
+
+			res, err := ts.Client().Post(ts.URL, "application/json", bytes.NewBuffer(tt.body))
+			if err != nil {
+				t.Fatalf("error making a POST in the httptest server: %v", err)
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != tt.wantCode {
+				t.Errorf("BrokerHandler.MemoryHandler() = %v, want %v",
+					res.StatusCode,
+					tt.wantCode)
+			}
+		})
+	}
+}
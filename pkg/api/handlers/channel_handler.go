@@ -6,7 +6,11 @@ import (
 
 	"go.uber.org/zap"
 	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	metautils "inspr.dev/inspr/pkg/meta/utils"
 	"inspr.dev/inspr/pkg/rest"
+	"inspr.dev/inspr/pkg/sidecars/codecs"
 )
 
 // ChannelHandler - contains handlers that uses the ChannelMemory interface methods
@@ -69,17 +73,13 @@ func (ch *ChannelHandler) HandleCreate() rest.Handler {
 
 		if !data.DryRun {
 			logger.Debug("applying Channel create changes in diff")
-			err = ch.applyChangesInDiff(changes)
+			err = ch.applyChangesInDiff(changes, author(r))
 			if err != nil {
 				logger.Error("unable to apply Channel create changes in diff",
 					zap.Any("error", err))
 				rest.ERROR(w, err)
-				ch.Memory.Tree().Cancel()
 				return
 			}
-
-			logger.Info("committing Channel create changes")
-			defer ch.Memory.Tree().Commit()
 		} else {
 			logger.Info("cancelling Channel create changes")
 			defer ch.Memory.Tree().Cancel()
@@ -106,8 +106,13 @@ func (ch *ChannelHandler) HandleGet() rest.Handler {
 			return
 		}
 
-		logger.Debug("initiating Channel get transaction")
-		ch.Memory.Tree().InitTransaction()
+		if data.Selector != nil {
+			ch.selectComponents(w, logger.With(zap.String("scope", scope)), scope, *data.Selector,
+				func(s metautils.Selector, app *meta.App) interface{} {
+					return s.SelectChannels(app, scope)
+				})
+			return
+		}
 
 		channel, err := ch.Memory.Tree().Perm().Channels().Get(scope, data.ChName)
 		if err != nil {
@@ -116,12 +121,9 @@ func (ch *ChannelHandler) HandleGet() rest.Handler {
 				zap.String("scope", scope),
 				zap.Any("error", err))
 			rest.ERROR(w, err)
-			ch.Memory.Tree().Cancel()
 			return
 		}
 
-		defer ch.Memory.Tree().Cancel()
-
 		rest.JSON(w, http.StatusOK, channel)
 	}
 	return rest.Handler(handler)
@@ -172,17 +174,13 @@ func (ch *ChannelHandler) HandleUpdate() rest.Handler {
 
 		if !data.DryRun {
 			logger.Debug("applying Channel update changes in diff")
-			err = ch.applyChangesInDiff(changes)
+			err = ch.applyChangesInDiff(changes, author(r))
 			if err != nil {
 				logger.Error("unable to apply Channel update changes in diff",
 					zap.Any("error", err))
 				rest.ERROR(w, err)
-				ch.Memory.Tree().Cancel()
 				return
 			}
-
-			logger.Info("committing Channel update changes")
-			defer ch.Memory.Tree().Commit()
 		} else {
 			logger.Info("cancelling Channel update changes")
 			defer ch.Memory.Tree().Cancel()
@@ -212,6 +210,20 @@ func (ch *ChannelHandler) HandleDelete() rest.Handler {
 		logger.Debug("initiating Channel delete transaction")
 		ch.Memory.Tree().InitTransaction()
 
+		current, err := ch.Memory.Tree().Channels().Get(scope, data.ChName)
+		if err == nil {
+			err = metautils.ValidateResourceVersion(current.Meta, data.ResourceVersion)
+		}
+		if err != nil {
+			logger.Error("unable to delete Channel",
+				zap.String("channel", data.ChName),
+				zap.String("scope", scope),
+				zap.Any("error", err))
+			rest.ERROR(w, err)
+			ch.Memory.Tree().Cancel()
+			return
+		}
+
 		err = ch.Memory.Tree().Channels().Delete(scope, data.ChName)
 		if err != nil {
 			logger.Error("unable to delete Channel",
@@ -234,17 +246,13 @@ func (ch *ChannelHandler) HandleDelete() rest.Handler {
 
 		if !data.DryRun {
 			logger.Debug("applying Channel delete changes in diff")
-			err = ch.applyChangesInDiff(changes)
+			err = ch.applyChangesInDiff(changes, author(r))
 			if err != nil {
 				logger.Error("unable to apply Channel delete changes in diff",
 					zap.Any("error", err))
 				rest.ERROR(w, err)
-				ch.Memory.Tree().Cancel()
 				return
 			}
-
-			logger.Info("committing Channel delete changes")
-			defer ch.Memory.Tree().Commit()
 		} else {
 			logger.Info("cancelling Channel delete changes")
 			defer ch.Memory.Tree().Cancel()
@@ -254,3 +262,72 @@ func (ch *ChannelHandler) HandleDelete() rest.Handler {
 	}
 	return rest.Handler(handler)
 }
+
+// HandleDeadLetters - returns a handle function that obtains the messages of
+// a Channel that couldn't be delivered and were sent to its dead-letter Channel
+func (ch *ChannelHandler) HandleDeadLetters() rest.Handler {
+	logger.Info("handling Channel dead-letters request")
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		data := models.DeadLetterQueryDI{}
+		scope := r.Header.Get(rest.HeaderScopeKey)
+
+		err := json.NewDecoder(r.Body).Decode(&data)
+		if err != nil {
+			logger.Error("unable to decode Channel dead-letters request data",
+				zap.Any("error", err))
+			rest.ERROR(w, err)
+			return
+		}
+
+		channel, err := ch.Memory.Tree().Perm().Channels().Get(scope, data.ChName)
+		if err != nil {
+			logger.Error("unable to get Channel",
+				zap.String("channel", data.ChName),
+				zap.String("scope", scope),
+				zap.Any("error", err))
+			rest.ERROR(w, err)
+			return
+		}
+
+		if channel.Spec.DeadLetter == nil {
+			rest.ERROR(w, ierrors.New(
+				"channel '%v' doesn't have a dead-letter channel", data.ChName,
+			).BadRequest())
+			return
+		}
+
+		limit := data.Limit
+		if limit <= 0 {
+			limit = models.DefaultDeadLetterLimit
+		}
+
+		messages, err := ch.Operator.Channels().Messages(r.Context(), scope, channel.Spec.DeadLetter.Channel, limit)
+		if err != nil {
+			logger.Error("unable to read the messages of the dead-letter Channel",
+				zap.String("channel", data.ChName),
+				zap.String("dead-letter", channel.Spec.DeadLetter.Channel),
+				zap.Any("error", err))
+			rest.ERROR(w, err)
+			return
+		}
+
+		// a dead-letter channel may be shared by many channels
+		resolved := "INSPR_" + channel.Meta.UUID
+		deadLetters := []meta.DeadLetterMessage{}
+		for _, message := range messages {
+			payload, _ := codecs.SplitSchemaVersion(message)
+
+			var deadLetter meta.DeadLetterMessage
+			if err := json.Unmarshal(payload, &deadLetter); err != nil {
+				logger.Debug("skipping message that isn't a dead-letter", zap.Any("error", err))
+				continue
+			}
+			if deadLetter.ResolvedChannel == resolved {
+				deadLetters = append(deadLetters, deadLetter)
+			}
+		}
+
+		rest.JSON(w, http.StatusOK, deadLetters)
+	}
+	return rest.Handler(handler)
+}
@@ -18,6 +18,7 @@ import (
 	authmock "inspr.dev/inspr/pkg/auth/mocks"
 	"inspr.dev/inspr/pkg/ierrors"
 	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/rest"
 )
 
 type channelAPITest struct {
@@ -322,3 +323,187 @@ func TestChannelHandler_HandleDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestChannelHandler_HandleDelete_staleVersion(t *testing.T) {
+	ch := NewHandler(fake.GetMockMemoryManager(nil, nil), ofake.NewFakeOperator(), authmock.NewMockAuth(nil)).NewChannelHandler()
+	ts := httptest.NewServer(ch.HandleDelete())
+	defer ts.Close()
+
+	brokers, _ := ch.Memory.Brokers().Get()
+	ch.Memory.Tree().Channels().Create("", &meta.Channel{
+		Meta: meta.Metadata{Name: "mock_channel", ResourceVersion: 2},
+	}, brokers)
+
+	body, _ := json.Marshal(models.ChannelQueryDI{
+		ChName:          "mock_channel",
+		ResourceVersion: 1,
+	})
+	res, err := ts.Client().Post(ts.URL, "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatalf("error making a POST in the httptest server")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusConflict {
+		t.Errorf("ChannelHandler.HandleDelete() = %v, want %v", res.StatusCode, http.StatusConflict)
+	}
+	if _, err = ch.Memory.Tree().Channels().Get("", "mock_channel"); err != nil {
+		t.Errorf("ChannelHandler.HandleDelete() deleted a channel with an outdated version")
+	}
+}
+
+func TestChannelHandler_HandleGet_selector(t *testing.T) {
+	ch := NewHandler(fake.GetMockMemoryManager(nil, nil), ofake.NewFakeOperator(), authmock.NewMockAuth(nil)).NewChannelHandler()
+	ch.Memory.Tree().Apps().Create("", &meta.App{
+		Meta: meta.Metadata{Name: "app1"},
+		Spec: meta.AppSpec{
+			Channels: map[string]*meta.Channel{
+				"ch1": {Meta: meta.Metadata{Name: "ch1", Annotations: map[string]string{"env": "prod"}}},
+				"ch2": {Meta: meta.Metadata{Name: "ch2", Annotations: map[string]string{"env": "dev"}}},
+			},
+		},
+	}, nil)
+
+	tests := []struct {
+		name       string
+		selector   string
+		wantStatus int
+		want       []string
+	}{
+		{
+			name:       "selects the matching channels",
+			selector:   "env=prod",
+			wantStatus: http.StatusOK,
+			want:       []string{"ch1"},
+		},
+		{
+			name:       "selects every channel with an empty selector",
+			selector:   "",
+			wantStatus: http.StatusOK,
+			want:       []string{"ch1", "ch2"},
+		},
+		{
+			name:       "rejects an invalid selector",
+			selector:   "env in prod",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(ch.HandleGet())
+			defer ts.Close()
+
+			body, _ := json.Marshal(models.ChannelQueryDI{Selector: &tt.selector})
+			req, _ := http.NewRequest(http.MethodGet, ts.URL, bytes.NewBuffer(body))
+			req.Header.Set(rest.HeaderScopeKey, "app1")
+			res, err := ts.Client().Do(req)
+			if err != nil {
+				t.Fatalf("error making a GET in the httptest server")
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("ChannelHandler.HandleGet() = %v, want %v", res.StatusCode, tt.wantStatus)
+			}
+			if tt.want == nil {
+				return
+			}
+
+			channels := []meta.Channel{}
+			json.NewDecoder(res.Body).Decode(&channels)
+			got := []string{}
+			for _, c := range channels {
+				got = append(got, c.Meta.Name)
+				if c.Meta.Parent != "app1" {
+					t.Errorf("ChannelHandler.HandleGet() parent = %v, want app1", c.Meta.Parent)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChannelHandler.HandleGet() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChannelHandler_HandleDeadLetters(t *testing.T) {
+	h := NewHandler(fake.GetMockMemoryManager(nil, nil), ofake.NewFakeOperator(), authmock.NewMockAuth(nil))
+	h.Memory.Tree().Channels().Create("", &meta.Channel{
+		Meta: meta.Metadata{Name: "orders", UUID: "orders-uuid"},
+		Spec: meta.ChannelSpec{DeadLetter: &meta.DeadLetter{Channel: "failures"}},
+	}, nil)
+	h.Memory.Tree().Channels().Create("", &meta.Channel{
+		Meta: meta.Metadata{Name: "failures", UUID: "failures-uuid"},
+	}, nil)
+
+	deadLetter := meta.DeadLetterMessage{
+		Channel:         "orders",
+		ResolvedChannel: "INSPR_orders-uuid",
+		Error:           "node responded with status 500",
+		Attempts:        3,
+		Message:         []byte("order"),
+	}
+	fromOrders, _ := json.Marshal(deadLetter)
+	deadLetter.ResolvedChannel = "INSPR_payments-uuid"
+	fromPayments, _ := json.Marshal(deadLetter)
+
+	channels := h.Operator.Channels()
+	channels.Create(context.Background(), "", &meta.Channel{Meta: meta.Metadata{Name: "failures"}})
+	channels.(*ofake.ChannelOperator).WriteMessages("", "failures",
+		// versioned message, as written by the load balancer sidecar
+		append([]byte{0xC3, 0x02, 0, 0, 0, 1}, fromOrders...),
+		fromPayments,
+	)
+
+	tests := []struct {
+		name    string
+		body    models.DeadLetterQueryDI
+		want    int
+		wantLen int
+	}{
+		{
+			name:    "dead-letters of the channel",
+			body:    models.DeadLetterQueryDI{ChName: "orders"},
+			want:    http.StatusOK,
+			wantLen: 1,
+		},
+		{
+			name: "channel without dead-letter",
+			body: models.DeadLetterQueryDI{ChName: "failures"},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "channel doesn't exist",
+			body: models.DeadLetterQueryDI{ChName: "payments"},
+			want: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(h.NewChannelHandler().HandleDeadLetters().HTTPHandlerFunc())
+			defer ts.Close()
+
+			body, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest(http.MethodGet, ts.URL, bytes.NewBuffer(body))
+			req.Header.Set(rest.HeaderScopeKey, "")
+			res, err := ts.Client().Do(req)
+			if err != nil {
+				t.Fatalf("error making a GET in the httptest server")
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != tt.want {
+				t.Fatalf("ChannelHandler.HandleDeadLetters() = %v, want %v", res.StatusCode, tt.want)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+
+			got := []meta.DeadLetterMessage{}
+			json.NewDecoder(res.Body).Decode(&got)
+			if len(got) != tt.wantLen || got[0].ResolvedChannel != "INSPR_orders-uuid" ||
+				string(got[0].Message) != "order" {
+				t.Errorf("ChannelHandler.HandleDeadLetters() = %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"go.uber.org/zap"
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	metautils "inspr.dev/inspr/pkg/meta/utils"
+	"inspr.dev/inspr/pkg/rest"
+)
+
+// GraphHandler - contains handlers that render the data flow between the
+// components of the cluster
+type GraphHandler struct {
+	*Handler
+	logger *zap.Logger
+}
+
+// NewGraphHandler - returns the handle function that regards the graph of the
+// data flow of a scope
+func (handler *Handler) NewGraphHandler() *GraphHandler {
+	return &GraphHandler{
+		Handler: handler,
+		logger:  logger.With(zap.String("subSection", "graph")),
+	}
+}
+
+// HandleGet - returns the handle function that builds the graph of the resolved
+// data flow of the scope of the request, the nodes and the channels and routes
+// they are connected to, and renders it in the requested format
+func (gh *GraphHandler) HandleGet() rest.Handler {
+	l := gh.logger.With(zap.String("operation", "get"))
+	l.Info("received graph get request")
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		data := models.GraphQueryDI{}
+		scope := r.Header.Get(rest.HeaderScopeKey)
+
+		err := json.NewDecoder(r.Body).Decode(&data)
+		if err != nil {
+			l.Error("unable to decode graph get request data", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+		l = l.With(zap.String("scope", scope), zap.String("format", data.Format))
+
+		graph, err := gh.buildGraph(scope)
+		if err != nil {
+			l.Error("unable to build the graph", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+
+		switch data.Format {
+		case "", models.GraphJSON:
+			rest.JSON(w, http.StatusOK, graph)
+		case models.GraphDOT:
+			rest.JSON(w, http.StatusOK, graph.DOT())
+		case models.GraphMermaid:
+			rest.JSON(w, http.StatusOK, graph.Mermaid())
+		default:
+			l.Error("invalid graph format")
+			rest.ERROR(w, ierrors.New(
+				"invalid graph format %v, use %v, %v or %v",
+				data.Format, models.GraphJSON, models.GraphDOT, models.GraphMermaid,
+			).BadRequest())
+		}
+	}
+	return rest.Handler(handler)
+}
+
+// graphBuilder keeps the vertices already added to a graph
+type graphBuilder struct {
+	*GraphHandler
+	graph    *models.Graph
+	vertices map[string]bool
+}
+
+// buildGraph builds the graph of the dApp of the given scope from the cluster tree
+func (gh *GraphHandler) buildGraph(scope string) (*models.Graph, error) {
+	app, err := gh.Memory.Tree().Perm().Apps().Get(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	b := graphBuilder{
+		GraphHandler: gh,
+		graph: &models.Graph{
+			Scope:    scope,
+			Vertices: []models.GraphVertex{},
+			Edges:    []models.GraphEdge{},
+		},
+		vertices: map[string]bool{},
+	}
+	if err := b.addApp(app, scope); err != nil {
+		return nil, err
+	}
+	b.graph.Sort()
+	return b.graph, nil
+}
+
+// addApp adds the channels, routes and nodes of the dApp and of its children to the graph
+func (b *graphBuilder) addApp(app *meta.App, scope string) error {
+	for name, ch := range app.Spec.Channels {
+		b.addVertex(models.GraphChannel, scope, name, ch.Spec.Type)
+	}
+
+	for name := range app.Spec.Routes {
+		// the routes of a dApp are copied to its nodes, the ones that
+		// are served by a child are the ones defined by the dApp
+		if _, ok := app.Spec.Apps[name]; !ok {
+			continue
+		}
+		nodePath, _ := metautils.JoinScopes(scope, name)
+		b.graph.Edges = append(b.graph.Edges, models.GraphEdge{
+			From:     b.addVertex(models.GraphRoute, scope, name, ""),
+			To:       vertexID(models.GraphNode, nodePath),
+			Kind:     models.GraphRoute,
+			Boundary: name,
+		})
+	}
+
+	if app.Spec.Node.Spec.Image != "" {
+		if err := b.addNode(app, scope); err != nil {
+			return err
+		}
+	}
+
+	names := make([]string, 0, len(app.Spec.Apps))
+	for name := range app.Spec.Apps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		childScope, _ := metautils.JoinScopes(scope, name)
+		if err := b.addApp(app.Spec.Apps[name], childScope); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addNode adds the node to the graph, connected to the channels and routes its
+// boundary resolves to
+func (b *graphBuilder) addNode(app *meta.App, path string) error {
+	parent, name, _ := metautils.RemoveLastPartInScope(path)
+	id := b.addVertex(models.GraphNode, parent, name, "")
+
+	routes, channels, err := b.Memory.Tree().Apps().ResolveBoundary(app, true)
+	if err != nil {
+		return ierrors.Wrap(err, "unable to resolve the boundary of "+path)
+	}
+
+	edge := func(kind, boundary, resolved string) models.GraphEdge {
+		scope, name, _ := metautils.RemoveLastPartInScope(resolved)
+		vertexKind := models.GraphChannel
+		typeName := ""
+		if kind == models.GraphRoute {
+			vertexKind = models.GraphRoute
+		} else if ch, err := b.Memory.Tree().Perm().Channels().Get(scope, name); err == nil {
+			typeName = ch.Spec.Type
+		}
+		direct, _ := metautils.JoinScopes(parent, boundary)
+		return models.GraphEdge{
+			From:     id,
+			To:       b.addVertex(vertexKind, scope, name, typeName),
+			Kind:     kind,
+			Boundary: boundary,
+			Alias:    resolved != direct,
+		}
+	}
+
+	for _, boundary := range app.Spec.Boundary.Channels.Input {
+		e := edge(models.GraphInput, boundary, channels[boundary])
+		e.From, e.To = e.To, e.From
+		b.graph.Edges = append(b.graph.Edges, e)
+	}
+	for _, boundary := range app.Spec.Boundary.Channels.Output {
+		b.graph.Edges = append(b.graph.Edges, edge(models.GraphOutput, boundary, channels[boundary]))
+	}
+	for _, boundary := range app.Spec.Boundary.Routes {
+		b.graph.Edges = append(b.graph.Edges, edge(models.GraphRoute, boundary, routes[boundary]))
+	}
+	return nil
+}
+
+// addVertex adds a vertex to the graph if it wasn't added yet, returning its ID
+func (b *graphBuilder) addVertex(kind, scope, name, typeName string) string {
+	path, _ := metautils.JoinScopes(scope, name)
+	id := vertexID(kind, path)
+	if !b.vertices[id] {
+		b.vertices[id] = true
+		b.graph.Vertices = append(b.graph.Vertices, models.GraphVertex{
+			ID:    id,
+			Kind:  kind,
+			Scope: scope,
+			Name:  name,
+			Type:  typeName,
+		})
+	}
+	return id
+}
+
+// vertexID returns the ID of the vertex of a component, its kind and path, since
+// a route and the node that serves it have the same path
+func vertexID(kind, path string) string {
+	return kind + ":" + path
+}
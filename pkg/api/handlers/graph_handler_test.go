@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"inspr.dev/inspr/cmd/insprd/memory/fake"
+	ofake "inspr.dev/inspr/cmd/insprd/operators/fake"
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/rest"
+)
+
+func graphHandler() *GraphHandler {
+	h := &Handler{
+		Memory:   fake.GetMockMemoryManager(nil, nil),
+		Operator: ofake.NewFakeOperator(),
+	}
+	node := func(name string, input, output []string) *meta.App {
+		return &meta.App{
+			Meta: meta.Metadata{Name: name},
+			Spec: meta.AppSpec{
+				Node: meta.Node{Spec: meta.NodeSpec{Image: "image"}},
+				Boundary: meta.AppBoundary{
+					Channels: meta.Boundary{Input: input, Output: output},
+				},
+			},
+		}
+	}
+	h.Memory.Tree().Apps().Create("", &meta.App{
+		Spec: meta.AppSpec{
+			Channels: map[string]*meta.Channel{
+				"ch1": {Meta: meta.Metadata{Name: "ch1"}, Spec: meta.ChannelSpec{Type: "t1"}},
+				"ch2": {Meta: meta.Metadata{Name: "ch2"}, Spec: meta.ChannelSpec{Type: "t1"}},
+			},
+			Apps: map[string]*meta.App{
+				"reader": node("reader", []string{"ch1"}, []string{"ch2"}),
+				"writer": node("writer", nil, []string{"ch1"}),
+			},
+			Routes: map[string]*meta.RouteConnection{
+				"reader": {Address: "http://node-reader:3000"},
+			},
+		},
+	}, nil)
+	return h.NewGraphHandler()
+}
+
+func TestGraphHandler_HandleGet(t *testing.T) {
+	wantGraph := models.Graph{
+		Vertices: []models.GraphVertex{
+			{ID: "channel:ch1", Kind: models.GraphChannel, Name: "ch1", Type: "t1"},
+			{ID: "channel:ch2", Kind: models.GraphChannel, Name: "ch2", Type: "t1"},
+			{ID: "node:reader", Kind: models.GraphNode, Name: "reader"},
+			{ID: "node:writer", Kind: models.GraphNode, Name: "writer"},
+			{ID: "route:reader", Kind: models.GraphRoute, Name: "reader"},
+		},
+		Edges: []models.GraphEdge{
+			{From: "channel:ch1", To: "node:reader", Kind: models.GraphInput, Boundary: "ch1"},
+			{From: "node:reader", To: "channel:ch2", Kind: models.GraphOutput, Boundary: "ch2"},
+			{From: "node:writer", To: "channel:ch1", Kind: models.GraphOutput, Boundary: "ch1"},
+			{From: "route:reader", To: "node:reader", Kind: models.GraphRoute, Boundary: "reader"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		body     []byte
+		want     int
+		contains []string
+	}{
+		{
+			name: "graph as json",
+			body: []byte(`{"format": "json"}`),
+			want: http.StatusOK,
+		},
+		{
+			name: "graph in dot",
+			body: []byte(`{"format": "dot"}`),
+			want: http.StatusOK,
+			contains: []string{
+				"digraph inspr {",
+				`"channel:ch1" [label="ch1 (t1)", shape=ellipse];`,
+				`"node:writer" -> "channel:ch1" [label="ch1"];`,
+				`"route:reader" -> "node:reader" [label="reader", style=dashed];`,
+			},
+		},
+		{
+			name: "graph in mermaid",
+			body: []byte(`{"format": "mermaid"}`),
+			want: http.StatusOK,
+			contains: []string{
+				"flowchart LR",
+				`v0(["ch1 (t1)"])`,
+				`v3 -->|"ch1"| v0`,
+				`v4 -.->|"reader"| v2`,
+			},
+		},
+		{
+			name: "invalid format",
+			body: []byte(`{"format": "svg"}`),
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "invalid request body",
+			body: []byte{1},
+			want: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(graphHandler().HandleGet().HTTPHandlerFunc())
+			defer ts.Close()
+
+			req, _ := http.NewRequest(http.MethodGet, ts.URL, bytes.NewBuffer(tt.body))
+			req.Header.Set(rest.HeaderScopeKey, "")
+			res, err := ts.Client().Do(req)
+			if err != nil {
+				t.Fatalf("error making a GET in the httptest server")
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != tt.want {
+				t.Errorf("GraphHandler.HandleGet() = %v, want %v", res.StatusCode, tt.want)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+
+			if tt.contains == nil {
+				graph := models.Graph{}
+				json.NewDecoder(res.Body).Decode(&graph)
+				if !reflect.DeepEqual(graph, wantGraph) {
+					t.Errorf("GraphHandler.HandleGet() = %v, want %v", graph, wantGraph)
+				}
+				return
+			}
+
+			var rendered string
+			json.NewDecoder(res.Body).Decode(&rendered)
+			for _, line := range tt.contains {
+				if !strings.Contains(rendered, line) {
+					t.Errorf("GraphHandler.HandleGet() = %v, want it to contain %v", rendered, line)
+				}
+			}
+		})
+	}
+}
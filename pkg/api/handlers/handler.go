@@ -1,13 +1,20 @@
 package handler
 
 import (
+	"fmt"
+	"net/http"
+
 	"go.uber.org/zap"
 	"inspr.dev/inspr/cmd/insprd/memory"
 	"inspr.dev/inspr/cmd/insprd/operators"
+	"inspr.dev/inspr/pkg/api/models"
 	"inspr.dev/inspr/pkg/auth"
 	"inspr.dev/inspr/pkg/ierrors"
 	"inspr.dev/inspr/pkg/logs"
+	"inspr.dev/inspr/pkg/meta"
+	metautils "inspr.dev/inspr/pkg/meta/utils"
 	"inspr.dev/inspr/pkg/meta/utils/diff"
+	"inspr.dev/inspr/pkg/rest"
 )
 
 var logger *zap.Logger
@@ -58,22 +65,104 @@ func (handler *Handler) addChangeReactor(op ...diff.ChangeReaction) {
 	handler.changeReactions = append(handler.changeReactions, op...)
 }
 
-func (handler *Handler) applyChangesInDiff(changes diff.Changelog) error {
+// applyChangesInDiff applies the reactions to the changes of the current transaction
+// and commits it as the last step of the same saga, so a failed commit undoes the
+// reactions as well. The transaction is always ended: it's canceled if a reaction
+// fails, and the commit discards it when it can't be persisted.
+func (handler *Handler) applyChangesInDiff(changes diff.Changelog, author string) error {
 	logger.Debug("trying to apply changes in diff",
 		zap.Any("changes", changes))
-	errs := ierrors.MultiError{
-		Errors: []error{},
-	}
-	errs.Add(changes.ForEachDiffFiltered(handler.diffReactions...))
-	errs.Add(changes.ForEachFiltered(handler.changeReactions...))
-	if errs.Empty() {
-		return nil
+	committed := false
+	report, err := changes.ApplyReactions(
+		handler.diffReactions,
+		handler.changeReactions,
+		func() error {
+			committed = true
+			return handler.Memory.Tree().Commit(author)
+		},
+	)
+	if err != nil {
+		if !committed {
+			handler.Memory.Tree().Cancel()
+		}
+		logger.Error("unable to apply changes in diff, undid the applied reactions",
+			zap.Any("report", report),
+			zap.Error(err))
+		return &models.ReactionsErrorDI{
+			Stack:  err.Error(),
+			Code:   ierrors.Code(err),
+			Report: report,
+		}
 	}
+	return nil
+}
+
+// addCompatibility adds to the changes of a dry-run request the compatibility
+// reports of the Types whose schemas are updated by them, comparing the Types
+// of the current transaction with the committed ones
+func (handler *Handler) addCompatibility(changes diff.Changelog) {
+	for i, change := range changes {
+		for _, d := range change.Diff {
+			if d.Kind != diff.TypeKind || d.Field != fmt.Sprintf("Spec.Types[%s].Spec.Schema", d.Name) {
+				continue
+			}
 
-	return ierrors.New(errs.Error())
+			current, err := handler.Memory.Tree().Perm().Types().Get(change.Scope, d.Name)
+			if err != nil {
+				continue
+			}
+			updated, err := handler.Memory.Tree().Types().Get(change.Scope, d.Name)
+			if err != nil {
+				continue
+			}
+
+			if report, ok := metautils.CompatibilityReport(current, updated); ok {
+				changes[i].Compatibility = append(changes[i].Compatibility, diff.TypeCompatibility{
+					Type:   d.Name,
+					Report: report,
+				})
+			}
+		}
+	}
 }
 
 // GetCancel returns the transaction cancelation function for the operations
 func (handler *Handler) GetCancel() func() {
 	return handler.Memory.Tree().Cancel
 }
+
+// author returns the UID of the user that made the request, taken from the
+// token payload made available by the authorization middleware
+func author(r *http.Request) string {
+	if payload, ok := auth.PayloadFromContext(r.Context()); ok {
+		return payload.UID
+	}
+	return ""
+}
+
+// selectComponents writes to the response the components that the given function
+// selects, with the parsed selector expression, from the dApp of the given scope
+// on the committed tree
+func (handler *Handler) selectComponents(
+	w http.ResponseWriter,
+	l *zap.Logger,
+	scope, expression string,
+	selectFrom func(metautils.Selector, *meta.App) interface{},
+) {
+	l = l.With(zap.String("selector", expression))
+	selector, err := metautils.ParseSelector(expression)
+	if err != nil {
+		l.Debug("invalid selector", zap.Error(err))
+		rest.ERROR(w, err)
+		return
+	}
+
+	app, err := handler.Memory.Tree().Perm().Apps().Get(scope)
+	if err != nil {
+		l.Error("unable to get dApp to select from", zap.Error(err))
+		rest.ERROR(w, err)
+		return
+	}
+
+	rest.JSON(w, http.StatusOK, selectFrom(selector, app))
+}
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	metautils "inspr.dev/inspr/pkg/meta/utils"
+	"inspr.dev/inspr/pkg/rest"
+)
+
+// InstanceHandler - contains handlers that manage the dApps
+// instantiated from Templates
+type InstanceHandler struct {
+	*Handler
+	logger *zap.Logger
+}
+
+// NewInstanceHandler - returns the handle function that
+// manages the instances of Templates
+func (handler *Handler) NewInstanceHandler() *InstanceHandler {
+	return &InstanceHandler{
+		Handler: handler,
+		logger:  logger.With(zap.String("subSection", "instance")),
+	}
+}
+
+// HandleCreate - returns the handle function that creates, in the scope of
+// the request, the dApp of an instance of a Template
+func (ih *InstanceHandler) HandleCreate() rest.Handler {
+	l := ih.logger.With(zap.String("operation", "create"))
+	l.Info("received instance create request")
+	return ih.handleApply(l, func(scope string, app *meta.App, brokers *models.BrokersDI) error {
+		return ih.Memory.Tree().Apps().Create(scope, app, brokers)
+	})
+}
+
+// HandleUpdate - returns the handle function that renders again the dApp of
+// an instance of a Template, with the values given in the request
+func (ih *InstanceHandler) HandleUpdate() rest.Handler {
+	l := ih.logger.With(zap.String("operation", "update"))
+	l.Info("received instance update request")
+	return ih.handleApply(l, func(scope string, app *meta.App, brokers *models.BrokersDI) error {
+		query, err := metautils.JoinScopes(scope, app.Meta.Name)
+		if err != nil {
+			return err
+		}
+
+		current, err := ih.Memory.Tree().Apps().Get(query)
+		if err != nil {
+			return err
+		}
+		if _, ok := metautils.InstanceOf(current); !ok {
+			return ierrors.New("dApp %v wasn't instantiated from a Template", query).BadRequest()
+		}
+		return ih.Memory.Tree().Apps().Replace(query, app, brokers)
+	})
+}
+
+// handleApply returns a handle function that renders the dApp of the instance
+// of the request and passes it to the given function, in a transaction
+func (ih *InstanceHandler) handleApply(
+	l *zap.Logger,
+	apply func(scope string, app *meta.App, brokers *models.BrokersDI) error,
+) rest.Handler {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		data := models.InstanceDI{}
+		scope := r.Header.Get(rest.HeaderScopeKey)
+
+		err := json.NewDecoder(r.Body).Decode(&data)
+		if err != nil {
+			l.Error("unable to decode instance request data", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+		l := l.With(
+			zap.String("instance", data.Instance.Meta.Name),
+			zap.String("template", data.Instance.Template),
+			zap.String("scope", scope),
+			zap.Bool("dry-run", data.DryRun),
+		)
+
+		l.Debug("initiating instance transaction")
+		ih.Memory.Tree().InitTransaction()
+
+		err = ih.applyInstance(scope, &data.Instance, apply)
+		if err != nil {
+			l.Error("unable to apply instance", zap.Error(err))
+			rest.ERROR(w, err)
+			ih.Memory.Tree().Cancel()
+			return
+		}
+
+		changes, err := ih.Memory.Tree().GetTransactionChanges()
+		if err != nil {
+			l.Error("unable to get instance request changes", zap.Error(err))
+			rest.ERROR(w, err)
+			ih.Memory.Tree().Cancel()
+			return
+		}
+
+		if !data.DryRun {
+			l.Debug("applying instance changes in diff")
+			err = ih.applyChangesInDiff(changes, author(r))
+			if err != nil {
+				l.Error("unable to apply instance changes in diff", zap.Error(err))
+				rest.ERROR(w, err)
+				return
+			}
+		} else {
+			l.Debug("canceling instance changes")
+			defer ih.Memory.Tree().Cancel()
+		}
+
+		rest.JSON(w, http.StatusOK, changes)
+	}
+	return rest.Handler(handler)
+}
+
+// applyInstance renders the Template of the given instance and applies the
+// resulting dApp with the given function
+func (ih *InstanceHandler) applyInstance(
+	scope string,
+	instance *meta.TemplateInstance,
+	apply func(scope string, app *meta.App, brokers *models.BrokersDI) error,
+) error {
+	if err := metautils.StructureNameIsValid(instance.Meta.Name); err != nil {
+		return err
+	}
+
+	templateScope, templateName, err := metautils.RemoveLastPartInScope(instance.Template)
+	if err != nil {
+		return ierrors.New("invalid template path '%v'", instance.Template).BadRequest()
+	}
+
+	template, err := ih.Memory.Tree().Templates().Get(templateScope, templateName)
+	if err != nil {
+		return err
+	}
+
+	instance.Meta.Parent = scope
+	app, err := metautils.InstantiateTemplate(template, instance.Template, instance)
+	if err != nil {
+		return err
+	}
+
+	brokers, err := ih.Memory.Brokers().Get()
+	if err != nil {
+		return err
+	}
+	return apply(scope, app, brokers)
+}
+
+// HandleGet - returns the handle function that obtains the instance
+// a dApp of the scope of the request was created from
+func (ih *InstanceHandler) HandleGet() rest.Handler {
+	l := ih.logger.With(zap.String("operation", "get"))
+	l.Info("received instance get request")
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		data := models.InstanceQueryDI{}
+		scope := r.Header.Get(rest.HeaderScopeKey)
+
+		err := json.NewDecoder(r.Body).Decode(&data)
+		if err != nil {
+			l.Error("unable to decode instance get request data", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+		l = l.With(zap.String("instance", data.InstanceName), zap.String("scope", scope))
+
+		query, err := metautils.JoinScopes(scope, data.InstanceName)
+		if err != nil {
+			l.Error("invalid instance scope", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+
+		app, err := ih.Memory.Tree().Perm().Apps().Get(query)
+		if err != nil {
+			l.Error("unable to get instance", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+
+		instance, ok := metautils.InstanceOf(app)
+		if !ok {
+			l.Error("dApp isn't an instance of a Template")
+			rest.ERROR(w, ierrors.New("dApp %v wasn't instantiated from a Template", query).NotFound())
+			return
+		}
+		rest.JSON(w, http.StatusOK, instance)
+	}
+	return rest.Handler(handler)
+}
+
+// HandleDelete - returns the handle function that deletes the dApp of an
+// instance of a Template
+func (ih *InstanceHandler) HandleDelete() rest.Handler {
+	l := ih.logger.With(zap.String("operation", "delete"))
+	l.Info("received instance delete request")
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		data := models.InstanceQueryDI{}
+		scope := r.Header.Get(rest.HeaderScopeKey)
+
+		err := json.NewDecoder(r.Body).Decode(&data)
+		if err != nil {
+			l.Error("unable to decode instance delete request data", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+		l = l.With(
+			zap.String("instance", data.InstanceName),
+			zap.String("scope", scope),
+			zap.Bool("dry-run", data.DryRun),
+		)
+
+		l.Debug("initiating instance delete transaction")
+		ih.Memory.Tree().InitTransaction()
+
+		query, err := metautils.JoinScopes(scope, data.InstanceName)
+		var app *meta.App
+		if err == nil {
+			app, err = ih.Memory.Tree().Apps().Get(query)
+		}
+		if err == nil {
+			if _, ok := metautils.InstanceOf(app); !ok {
+				err = ierrors.New("dApp %v wasn't instantiated from a Template", query).BadRequest()
+			}
+		}
+		if err == nil {
+			err = ih.Memory.Tree().Apps().Delete(query)
+		}
+		if err != nil {
+			l.Error("unable to delete instance", zap.Error(err))
+			rest.ERROR(w, err)
+			ih.Memory.Tree().Cancel()
+			return
+		}
+
+		changes, err := ih.Memory.Tree().GetTransactionChanges()
+		if err != nil {
+			l.Error("unable to get instance delete request changes", zap.Error(err))
+			rest.ERROR(w, err)
+			ih.Memory.Tree().Cancel()
+			return
+		}
+
+		if !data.DryRun {
+			l.Debug("applying instance delete changes in diff")
+			err = ih.applyChangesInDiff(changes, author(r))
+			if err != nil {
+				l.Error("unable to apply instance delete changes in diff", zap.Error(err))
+				rest.ERROR(w, err)
+				return
+			}
+		} else {
+			l.Debug("canceling instance delete changes")
+			defer ih.Memory.Tree().Cancel()
+		}
+
+		rest.JSON(w, http.StatusOK, changes)
+	}
+	return rest.Handler(handler)
+}
@@ -0,0 +1,343 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"inspr.dev/inspr/cmd/insprd/memory/fake"
+	ofake "inspr.dev/inspr/cmd/insprd/operators/fake"
+	"inspr.dev/inspr/pkg/api/models"
+	authmock "inspr.dev/inspr/pkg/auth/mocks"
+	"inspr.dev/inspr/pkg/meta"
+	metautils "inspr.dev/inspr/pkg/meta/utils"
+	"inspr.dev/inspr/pkg/rest"
+)
+
+// newInstanceHandler returns the handlers of a memory that has the pipeline
+// Template, an instance of it for acme and a dApp that isn't an instance
+func newInstanceHandler(t *testing.T) *Handler {
+	h := NewHandler(fake.GetMockMemoryManager(nil, nil), ofake.NewFakeOperator(), authmock.NewMockAuth(nil))
+	tmpl := templateWithImage("{{ .customer }}/pipeline:v1")
+	h.Memory.Tree().Templates().Create("", &tmpl)
+
+	app, err := metautils.InstantiateTemplate(&tmpl, "pipeline", &meta.TemplateInstance{
+		Meta:   meta.Metadata{Name: "acme"},
+		Values: map[string]string{"customer": "acme"},
+	})
+	if err != nil {
+		t.Fatalf("InstantiateTemplate() error = %v", err)
+	}
+	h.Memory.Tree().Apps().Create("", app, nil)
+	h.Memory.Tree().Apps().Create("", &meta.App{Meta: meta.Metadata{Name: "plain"}}, nil)
+	return h
+}
+
+func TestInstanceHandler_HandleCreate(t *testing.T) {
+	tests := []struct {
+		name      string
+		instance  meta.TemplateInstance
+		want      int
+		wantImage string
+	}{
+		{
+			name: "dApp rendered from the template",
+			instance: meta.TemplateInstance{
+				Meta:     meta.Metadata{Name: "globex"},
+				Template: "pipeline",
+				Values:   map[string]string{"customer": "globex"},
+			},
+			want:      http.StatusOK,
+			wantImage: "globex/pipeline:v1",
+		},
+		{
+			name: "missing required value",
+			instance: meta.TemplateInstance{
+				Meta:     meta.Metadata{Name: "globex"},
+				Template: "pipeline",
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "unknown template",
+			instance: meta.TemplateInstance{
+				Meta:     meta.Metadata{Name: "globex"},
+				Template: "other",
+				Values:   map[string]string{"customer": "globex"},
+			},
+			want: http.StatusNotFound,
+		},
+		{
+			name: "invalid instance name",
+			instance: meta.TemplateInstance{
+				Meta:     meta.Metadata{Name: "glo.bex"},
+				Template: "pipeline",
+				Values:   map[string]string{"customer": "globex"},
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "dApp that already exists",
+			instance: meta.TemplateInstance{
+				Meta:     meta.Metadata{Name: "acme"},
+				Template: "pipeline",
+				Values:   map[string]string{"customer": "acme"},
+			},
+			want: http.StatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newInstanceHandler(t)
+
+			status := sendToHandler(t, h.NewInstanceHandler().HandleCreate(), http.MethodPost,
+				models.InstanceDI{Instance: tt.instance})
+			if status != tt.want {
+				t.Errorf("InstanceHandler.HandleCreate() = %v, want %v", status, tt.want)
+			}
+			if tt.wantImage == "" {
+				return
+			}
+
+			app, err := h.Memory.Tree().Apps().Get(tt.instance.Meta.Name)
+			if err != nil || app.Spec.Node.Spec.Image != tt.wantImage {
+				t.Fatalf("InstanceHandler.HandleCreate() created %v, %v, want image %v", app, err, tt.wantImage)
+			}
+			if instance, ok := metautils.InstanceOf(app); !ok || !reflect.DeepEqual(instance.Values, tt.instance.Values) {
+				t.Errorf("InstanceHandler.HandleCreate() instance = %v, want values %v", instance, tt.instance.Values)
+			}
+		})
+	}
+}
+
+func TestInstanceHandler_HandleGet(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      models.InstanceQueryDI
+		want       int
+		wantValues map[string]string
+	}{
+		{
+			name:       "instance of the dApp",
+			query:      models.InstanceQueryDI{InstanceName: "acme"},
+			want:       http.StatusOK,
+			wantValues: map[string]string{"customer": "acme"},
+		},
+		{
+			name:  "dApp that isn't an instance",
+			query: models.InstanceQueryDI{InstanceName: "plain"},
+			want:  http.StatusNotFound,
+		},
+		{
+			name:  "dApp that doesn't exist",
+			query: models.InstanceQueryDI{InstanceName: "globex"},
+			want:  http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newInstanceHandler(t)
+			ts := httptest.NewServer(h.NewInstanceHandler().HandleGet().HTTPHandlerFunc())
+			defer ts.Close()
+
+			body, _ := json.Marshal(tt.query)
+			req, _ := http.NewRequest(http.MethodGet, ts.URL, bytes.NewBuffer(body))
+			req.Header.Set(rest.HeaderScopeKey, "")
+			res, err := ts.Client().Do(req)
+			if err != nil {
+				t.Fatalf("error making a GET in the httptest server")
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != tt.want {
+				t.Errorf("InstanceHandler.HandleGet() = %v, want %v", res.StatusCode, tt.want)
+			}
+			if tt.wantValues == nil {
+				return
+			}
+
+			var instance meta.TemplateInstance
+			json.NewDecoder(res.Body).Decode(&instance)
+			if instance.Template != "pipeline" || !reflect.DeepEqual(instance.Values, tt.wantValues) {
+				t.Errorf("InstanceHandler.HandleGet() = %v, want values %v", instance, tt.wantValues)
+			}
+		})
+	}
+}
+
+func TestInstanceHandler_HandleUpdate(t *testing.T) {
+	tests := []struct {
+		name      string
+		instance  meta.TemplateInstance
+		want      int
+		wantImage string
+	}{
+		{
+			name: "dApp rendered again with the new values",
+			instance: meta.TemplateInstance{
+				Meta:     meta.Metadata{Name: "acme"},
+				Template: "pipeline",
+				Values:   map[string]string{"customer": "acme-corp"},
+			},
+			want:      http.StatusOK,
+			wantImage: "acme-corp/pipeline:v1",
+		},
+		{
+			name: "missing required value",
+			instance: meta.TemplateInstance{
+				Meta:     meta.Metadata{Name: "acme"},
+				Template: "pipeline",
+			},
+			want:      http.StatusBadRequest,
+			wantImage: "acme/pipeline:v1",
+		},
+		{
+			name: "dApp that isn't an instance",
+			instance: meta.TemplateInstance{
+				Meta:     meta.Metadata{Name: "plain"},
+				Template: "pipeline",
+				Values:   map[string]string{"customer": "acme"},
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "dApp that doesn't exist",
+			instance: meta.TemplateInstance{
+				Meta:     meta.Metadata{Name: "globex"},
+				Template: "pipeline",
+				Values:   map[string]string{"customer": "globex"},
+			},
+			want: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newInstanceHandler(t)
+
+			status := sendToHandler(t, h.NewInstanceHandler().HandleUpdate(), http.MethodPut,
+				models.InstanceDI{Instance: tt.instance})
+			if status != tt.want {
+				t.Errorf("InstanceHandler.HandleUpdate() = %v, want %v", status, tt.want)
+			}
+			if tt.wantImage == "" {
+				return
+			}
+
+			app, _ := h.Memory.Tree().Apps().Get(tt.instance.Meta.Name)
+			if app.Spec.Node.Spec.Image != tt.wantImage {
+				t.Errorf("InstanceHandler.HandleUpdate() image = %v, want %v", app.Spec.Node.Spec.Image, tt.wantImage)
+			}
+		})
+	}
+}
+
+func TestInstanceHandler_HandleDelete(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       models.InstanceQueryDI
+		want        int
+		wantDeleted bool
+	}{
+		{
+			name:        "instance deleted",
+			query:       models.InstanceQueryDI{InstanceName: "acme"},
+			want:        http.StatusOK,
+			wantDeleted: true,
+		},
+		{
+			name:  "dApp that isn't an instance",
+			query: models.InstanceQueryDI{InstanceName: "plain"},
+			want:  http.StatusBadRequest,
+		},
+		{
+			name:  "dApp that doesn't exist",
+			query: models.InstanceQueryDI{InstanceName: "globex"},
+			want:  http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newInstanceHandler(t)
+
+			status := sendToHandler(t, h.NewInstanceHandler().HandleDelete(), http.MethodDelete, tt.query)
+			if status != tt.want {
+				t.Errorf("InstanceHandler.HandleDelete() = %v, want %v", status, tt.want)
+			}
+
+			_, err := h.Memory.Tree().Apps().Get(tt.query.InstanceName)
+			if tt.wantDeleted && err == nil {
+				t.Errorf("InstanceHandler.HandleDelete() kept dApp %v", tt.query.InstanceName)
+			}
+			if tt.query.InstanceName == "plain" && err != nil {
+				t.Errorf("InstanceHandler.HandleDelete() deleted dApp %v, which isn't an instance", tt.query.InstanceName)
+			}
+		})
+	}
+}
+
+func TestInstanceHandler_rollout(t *testing.T) {
+	withRegion := templateWithImage("{{ .customer }}/pipeline:{{ .region }}")
+	withRegion.Parameters = append(withRegion.Parameters, meta.TemplateParameter{Name: "region", Required: true})
+
+	tests := []struct {
+		name       string
+		template   meta.Template
+		want       int
+		wantImages map[string]string
+	}{
+		{
+			name:     "instances rendered again with their values",
+			template: templateWithImage("{{ .customer }}/pipeline:v2"),
+			want:     http.StatusOK,
+			wantImages: map[string]string{
+				"acme":   "acme/pipeline:v2",
+				"globex": "globex/pipeline:v2",
+			},
+		},
+		{
+			name:     "template that the values of the instances can't render",
+			template: withRegion,
+			want:     http.StatusBadRequest,
+			wantImages: map[string]string{
+				"acme":   "acme/pipeline:v1",
+				"globex": "globex/pipeline:v1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newInstanceHandler(t)
+			status := sendToHandler(t, h.NewInstanceHandler().HandleCreate(), http.MethodPost, models.InstanceDI{
+				Instance: meta.TemplateInstance{
+					Meta:     meta.Metadata{Name: "globex"},
+					Template: "pipeline",
+					Values:   map[string]string{"customer": "globex"},
+				},
+			})
+			if status != http.StatusOK {
+				t.Fatalf("InstanceHandler.HandleCreate() = %v, want %v", status, http.StatusOK)
+			}
+
+			status = sendToHandler(t, h.NewTemplateHandler().HandleUpdate(), http.MethodPut,
+				models.TemplateDI{Template: tt.template})
+			if status != tt.want {
+				t.Errorf("TemplateHandler.HandleUpdate() = %v, want %v", status, tt.want)
+			}
+
+			for name, image := range tt.wantImages {
+				app, _ := h.Memory.Tree().Apps().Get(name)
+				if app.Spec.Node.Spec.Image != image {
+					t.Errorf("TemplateHandler.HandleUpdate() image of %v = %v, want %v", name, app.Spec.Node.Spec.Image, image)
+				}
+			}
+
+			// the dApp that isn't an instance isn't rendered
+			if app, _ := h.Memory.Tree().Apps().Get("plain"); app.Spec.Node.Spec.Image != "" {
+				t.Errorf("TemplateHandler.HandleUpdate() rendered dApp plain, which isn't an instance")
+			}
+		})
+	}
+}
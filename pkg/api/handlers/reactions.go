@@ -29,6 +29,12 @@ var createdNodes func(handler *Handler) diff.ChangeReaction = func(handler *Hand
 			_, err := handler.Operator.Nodes().CreateNode(context.Background(), to)
 			return err
 		},
+	).WithName("createdNodes").WithUndo(
+		func(c diff.Change) error {
+			l.Info("undoing node creation", zap.String("node", c.Scope))
+			parent, name, _ := utils.RemoveLastPartInScope(c.Scope)
+			return handler.Operator.Nodes().DeleteNode(context.Background(), parent, name)
+		},
 	)
 }
 
@@ -48,6 +54,15 @@ var deletedChannels func(handler *Handler) diff.DifferenceReaction = func(handle
 			}
 			return err
 		},
+	).WithName("deletedChannels").WithUndo(
+		func(scope string, d diff.Difference) error {
+			l.Info("undoing channel deletion", zap.Any("channel", d.Name))
+			ch, err := handler.Memory.Tree().Perm().Channels().Get(scope, d.Name) // get the channel definition from the cluster
+			if err != nil {
+				return err
+			}
+			return handler.Operator.Channels().Create(context.Background(), scope, ch)
+		},
 	)
 }
 
@@ -69,6 +84,11 @@ var createdChannels func(handler *Handler) diff.DifferenceReaction = func(handle
 			return err
 
 		},
+	).WithName("createdChannels").WithUndo(
+		func(scope string, d diff.Difference) error {
+			l.Info("undoing channel creation", zap.Any("channel", d.Name))
+			return handler.Operator.Channels().Delete(context.Background(), scope, d.Name)
+		},
 	)
 }
 
@@ -95,7 +115,7 @@ var deletedApps func(handler *Handler) diff.DifferenceReaction = func(handler *H
 			}
 			return err // delete app recursively (all nodes and channels defined) from the cluster
 		},
-	)
+	).WithName("deletedApps")
 }
 
 // apply this on updated Types
@@ -136,6 +156,23 @@ var updatedTypes func(handler *Handler) diff.DifferenceReaction = func(handler *
 			}
 			return nil
 		},
+	).WithName("updatedTypes").WithUndo(
+		func(scope string, d diff.Difference) error {
+			l.Info("undoing the update of the components that depend on type", zap.Any("type", d.Name))
+			ct, err := handler.Memory.Tree().Perm().Types().Get(scope, d.Name)
+			if err != nil {
+				return err
+			}
+
+			apps := []string{}
+			for _, channelName := range ct.ConnectedChannels {
+				channel, err := handler.Memory.Tree().Perm().Channels().Get(scope, channelName)
+				if err == nil {
+					apps = append(apps, channel.ConnectedApps...)
+				}
+			}
+			return handler.revertNodes(apps...)
+		},
 	)
 }
 
@@ -178,6 +215,19 @@ var updatedChannels func(handler *Handler) diff.DifferenceReaction = func(handle
 			}
 			return nil
 		},
+	).WithName("updatedChannels").WithUndo(
+		func(scope string, d diff.Difference) error {
+			l.Info("undoing the update of channel and nodes that are connected to it", zap.Any("channel", d.Name))
+			channel, err := handler.Memory.Tree().Perm().Channels().Get(scope, d.Name)
+			if err != nil {
+				return err
+			}
+			err = handler.Operator.Channels().Update(context.Background(), scope, channel)
+			if err != nil {
+				return err
+			}
+			return handler.revertNodes(channel.ConnectedApps...)
+		},
 	)
 }
 
@@ -209,6 +259,11 @@ var updatedNodes func(handler *Handler) diff.ChangeReaction = func(handler *Hand
 			}
 			return nil
 		},
+	).WithName("updatedNodes").WithUndo(
+		func(c diff.Change) error {
+			l.Info("undoing node update", zap.Any("node", c.Scope))
+			return handler.revertNodes(c.Scope)
+		},
 	)
 }
 
@@ -233,9 +288,36 @@ var updatedAliases func(handler *Handler) diff.DifferenceReaction = func(handler
 			}
 			return nil
 		},
+	).WithName("updatedAliases").WithUndo(
+		func(scope string, d diff.Difference) error {
+			l.Info("undoing the update of the node that depends on alias", zap.Any("alias", d.Name))
+			appName, _, _ := utils.RemoveLastPartInScope(d.Name)
+			newScope, _ := utils.JoinScopes(scope, appName)
+			return handler.revertNodes(newScope)
+		},
 	)
 }
 
+// revertNodes updates the nodes of the given scopes back to their definitions in
+// the cluster, undoing the updates made by the reactions of a transaction
+func (h *Handler) revertNodes(scopes ...string) error {
+	errs := ierrors.MultiError{
+		Errors: []error{},
+	}
+	for _, scope := range scopes {
+		app, err := h.Memory.Tree().Perm().Apps().Get(scope)
+		if err != nil || app.Spec.Node.Spec.Image == "" {
+			continue
+		}
+		_, err = h.Operator.Nodes().UpdateNode(context.Background(), app)
+		errs.Add(err)
+	}
+	if !errs.Empty() {
+		return &errs
+	}
+	return nil
+}
+
 func (h *Handler) initReactions() {
 	h.addChangeReactor(
 		updatedNodes(h),
@@ -245,9 +327,10 @@ func (h *Handler) initReactions() {
 	h.addDiffReactor(
 		createdChannels(h),
 		deletedChannels(h),
-		deletedApps(h),
 		updatedChannels(h),
 		updatedTypes(h),
 		updatedAliases(h),
+		// deleted dApps can't be brought back, so they are deleted last
+		deletedApps(h),
 	)
 }
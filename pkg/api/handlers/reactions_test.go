@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"inspr.dev/inspr/cmd/insprd/memory/fake"
+	"inspr.dev/inspr/cmd/insprd/operators"
+	ofake "inspr.dev/inspr/cmd/insprd/operators/fake"
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+	"inspr.dev/inspr/pkg/rest"
+)
+
+// operatorMock joins a channel and a node operator
+type operatorMock struct {
+	channels operators.ChannelOperatorInterface
+	nodes    operators.NodeOperatorInterface
+}
+
+func (o operatorMock) Channels() operators.ChannelOperatorInterface { return o.channels }
+func (o operatorMock) Nodes() operators.NodeOperatorInterface       { return o.nodes }
+
+func TestHandler_applyChangesInDiff(t *testing.T) {
+	changes := diff.Changelog{
+		{
+			Scope: "",
+			Diff: []diff.Difference{
+				{Kind: diff.ChannelKind, Name: "ch1", Operation: diff.Create},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		failing     bool
+		wantChannel bool
+	}{
+		{
+			name:        "applied reactions",
+			wantChannel: true,
+		},
+		{
+			name:    "failed reaction undoes the created channel",
+			failing: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{
+				Memory: fake.GetMockMemoryManager(nil, nil),
+				Operator: operatorMock{
+					channels: ofake.NewChannelOperator(nil),
+					nodes:    ofake.NewNodeOperator(nil),
+				},
+			}
+			h.Memory.Tree().Channels().Create("", &meta.Channel{Meta: meta.Metadata{Name: "ch1"}}, nil)
+
+			h.addDiffReactor(createdChannels(h))
+			h.addChangeReactor(diff.NewChangeReaction(
+				func(c diff.Change) bool { return tt.failing },
+				func(c diff.Change) error { return ierrors.New("node failed").ExternalErr() },
+			).WithName("failingNodes"))
+
+			err := h.applyChangesInDiff(changes, "")
+			if (err != nil) != tt.failing {
+				t.Fatalf("Handler.applyChangesInDiff() error = %v, wantErr %v", err, tt.failing)
+			}
+			_, getErr := h.Operator.Channels().Get(context.Background(), "", "ch1")
+			if (getErr == nil) != tt.wantChannel {
+				t.Errorf("Handler.applyChangesInDiff() channel created = %v, want %v", getErr == nil, tt.wantChannel)
+			}
+			if !tt.failing {
+				return
+			}
+
+			reactionsErr, ok := err.(*models.ReactionsErrorDI)
+			if !ok {
+				t.Fatalf("Handler.applyChangesInDiff() error = %T, want a ReactionsErrorDI", err)
+			}
+			want := []diff.ReactionStep{
+				{Reaction: "createdChannels", Name: "ch1", Reverted: true},
+				{
+					Reaction:    "failingNodes",
+					Error:       "error : node failed",
+					RevertError: "the reaction can't be undone",
+				},
+			}
+			if len(reactionsErr.Report.Steps) != len(want) ||
+				reactionsErr.Report.Steps[0] != want[0] ||
+				reactionsErr.Report.Steps[1] != want[1] {
+				t.Errorf("Handler.applyChangesInDiff() report = %v, want %v", reactionsErr.Report.Steps, want)
+			}
+
+			w := httptest.NewRecorder()
+			rest.ERROR(w, err)
+			if w.Code != http.StatusInternalServerError {
+				t.Errorf("rest.ERROR() status = %v, want %v", w.Code, http.StatusInternalServerError)
+			}
+			body := w.Body.Bytes()
+			if decoded := rest.UnmarshalERROR(bytes.NewReader(body)); !strings.Contains(decoded.Error(), "node failed") {
+				t.Errorf("rest.UnmarshalERROR() = %v, want the error of the failed reaction", decoded)
+			}
+			decoded := models.ReactionsErrorDI{}
+			json.Unmarshal(body, &decoded)
+			if len(decoded.Report.Steps) != len(want) {
+				t.Errorf("response report = %v, want %v", decoded.Report.Steps, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/rest"
+)
+
+// RevisionHandler - contains handlers that uses the RevisionMemory interface methods
+type RevisionHandler struct {
+	*Handler
+	logger *zap.Logger
+}
+
+// NewRevisionHandler - returns the handle functions that regard the tree revisions
+func (handler *Handler) NewRevisionHandler() *RevisionHandler {
+	return &RevisionHandler{
+		Handler: handler,
+		logger:  logger.With(zap.String("subSection", "revisions")),
+	}
+}
+
+// HandleHistory - returns the handle function that lists
+// the revisions committed on the tree
+func (rh *RevisionHandler) HandleHistory() rest.Handler {
+	l := rh.logger.With(zap.String("operation", "history"))
+	l.Info("received revision history request")
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		revisions, err := rh.Memory.Tree().Revisions().History()
+		if err != nil {
+			l.Error("unable to get revision history", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+
+		rest.JSON(w, http.StatusOK, revisions)
+	}
+	return rest.Handler(handler)
+}
+
+// HandleRollback - returns the handle function that reverts the tree
+// to a given revision, reconciling the cluster with the reverted tree
+func (rh *RevisionHandler) HandleRollback() rest.Handler {
+	l := rh.logger.With(zap.String("operation", "rollback"))
+	l.Info("received tree rollback request")
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		data := models.RevisionQueryDI{}
+		err := json.NewDecoder(r.Body).Decode(&data)
+		if err != nil {
+			l.Error("unable to decode rollback request data", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+		l = l.With(zap.Int("revision", data.Revision), zap.Bool("dry-run", data.DryRun))
+
+		l.Debug("initiating rollback transaction")
+		rh.Memory.Tree().InitTransaction()
+
+		err = rh.Memory.Tree().Revisions().Rollback(data.Revision)
+		if err != nil {
+			l.Error("unable to rollback tree", zap.Error(err))
+			rest.ERROR(w, err)
+			rh.Memory.Tree().Cancel()
+			return
+		}
+
+		changes, err := rh.Memory.Tree().GetTransactionChanges()
+		if err != nil {
+			l.Error("unable to get rollback changes", zap.Error(err))
+			rest.ERROR(w, err)
+			rh.Memory.Tree().Cancel()
+			return
+		}
+
+		if !data.DryRun {
+			l.Debug("applying rollback changes in diff")
+			err = rh.applyChangesInDiff(changes, author(r))
+			if err != nil {
+				l.Error("unable to apply rollback changes in diff", zap.Error(err))
+				rest.ERROR(w, err)
+				return
+			}
+		} else {
+			l.Debug("cancelling rollback changes")
+			defer rh.Memory.Tree().Cancel()
+		}
+
+		rest.JSON(w, http.StatusOK, changes)
+	}
+	return rest.Handler(handler)
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"inspr.dev/inspr/cmd/insprd/memory/fake"
+	ofake "inspr.dev/inspr/cmd/insprd/operators/fake"
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
+)
+
+func TestRevisionHandler_HandleHistory(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler *Handler
+		want    int
+	}{
+		{
+			name: "valid revision history request",
+			handler: &Handler{
+				Memory: fake.GetMockMemoryManager(nil, nil),
+			},
+			want: http.StatusOK,
+		},
+		{
+			name: "invalid revision history request",
+			handler: &Handler{
+				Memory: fake.GetMockMemoryManager(
+					ierrors.New("storage error").InternalServer(), nil,
+				),
+			},
+			want: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rh := tt.handler.NewRevisionHandler()
+			ts := httptest.NewServer(rh.HandleHistory().HTTPHandlerFunc())
+			defer ts.Close()
+
+			res, err := ts.Client().Get(ts.URL)
+			if err != nil {
+				t.Fatalf("error making a GET in the httptest server")
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != tt.want {
+				t.Errorf("RevisionHandler.HandleHistory() = %v, want %v", res.StatusCode, tt.want)
+			}
+
+			if tt.want == http.StatusOK {
+				revisions := []models.Revision{}
+				if err = json.NewDecoder(res.Body).Decode(&revisions); err != nil {
+					t.Errorf("RevisionHandler.HandleHistory() returned invalid body: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestRevisionHandler_HandleRollback(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler *Handler
+		body    []byte
+		want    int
+	}{
+		{
+			name: "invalid request body",
+			handler: &Handler{
+				Memory:   fake.GetMockMemoryManager(nil, nil),
+				Operator: ofake.NewFakeOperator(),
+			},
+			body: []byte{1},
+			want: http.StatusInternalServerError,
+		},
+		{
+			name: "nonexistent revision",
+			handler: &Handler{
+				Memory:   fake.GetMockMemoryManager(nil, nil),
+				Operator: ofake.NewFakeOperator(),
+			},
+			body: func() []byte {
+				data, _ := json.Marshal(models.RevisionQueryDI{Revision: 1})
+				return data
+			}(),
+			want: http.StatusNotFound,
+		},
+		{
+			name: "memory error",
+			handler: &Handler{
+				Memory:   fake.GetMockMemoryManager(errors.New("memory error"), nil),
+				Operator: ofake.NewFakeOperator(),
+			},
+			body: func() []byte {
+				data, _ := json.Marshal(models.RevisionQueryDI{Revision: 1, DryRun: true})
+				return data
+			}(),
+			want: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rh := tt.handler.NewRevisionHandler()
+			ts := httptest.NewServer(rh.HandleRollback().HTTPHandlerFunc())
+			defer ts.Close()
+
+			req, _ := http.NewRequest(http.MethodPut, ts.URL, bytes.NewBuffer(tt.body))
+			res, err := ts.Client().Do(req)
+			if err != nil {
+				t.Fatalf("error making a PUT in the httptest server")
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != tt.want {
+				t.Errorf("RevisionHandler.HandleRollback() = %v, want %v", res.StatusCode, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+	"inspr.dev/inspr/cmd/sidecars"
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta/brokers"
+	"inspr.dev/inspr/pkg/rest"
+	"inspr.dev/inspr/pkg/utils"
+)
+
+// SnapshotHandler - contains handlers that export and import the state of the cluster
+type SnapshotHandler struct {
+	*Handler
+	logger *zap.Logger
+}
+
+// NewSnapshotHandler - returns the handle functions that regard snapshots
+func (handler *Handler) NewSnapshotHandler() *SnapshotHandler {
+	return &SnapshotHandler{
+		Handler: handler,
+		logger:  logger.With(zap.String("subSection", "snapshots")),
+	}
+}
+
+// HandleExport - returns the handle function that takes a snapshot of the
+// subtree of the scope in the request header and of the configured brokers
+func (sh *SnapshotHandler) HandleExport() rest.Handler {
+	l := sh.logger.With(zap.String("operation", "export"))
+	l.Info("received snapshot export request")
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		scope := r.Header.Get(rest.HeaderScopeKey)
+		l := l.With(zap.String("scope", scope))
+
+		app, err := sh.Memory.Tree().Perm().Apps().Get(scope)
+		if err != nil {
+			l.Error("unable to get dApp to export", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+
+		available, err := sh.Memory.Brokers().Get()
+		if err != nil {
+			l.Error("unable to get configured brokers", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+
+		snapshot := models.Snapshot{
+			Scope:         scope,
+			App:           app,
+			Brokers:       []models.BrokerConfigDI{},
+			DefaultBroker: available.Default,
+		}
+
+		names := append([]string{}, available.Available...)
+		sort.Strings(names)
+		for _, broker := range names {
+			config, err := sh.Memory.Brokers().Configs(broker)
+			if err != nil {
+				l.Error("unable to get broker configs", zap.String("broker", broker), zap.Error(err))
+				rest.ERROR(w, err)
+				return
+			}
+
+			contents, err := yaml.Marshal(config)
+			if err != nil {
+				l.Error("unable to encode broker configs", zap.String("broker", broker), zap.Error(err))
+				rest.ERROR(w, ierrors.New(err).InternalServer())
+				return
+			}
+
+			snapshot.Brokers = append(snapshot.Brokers, models.BrokerConfigDI{
+				BrokerName:   broker,
+				FileContents: contents,
+			})
+		}
+
+		rest.JSON(w, http.StatusOK, snapshot)
+	}
+	return rest.Handler(handler)
+}
+
+// HandleImport - returns the handle function that restores a snapshot in the
+// scope in the request header, replacing its subtree in a single transaction.
+// The brokers of the snapshot that aren't configured on the cluster are only
+// configured once the snapshot is validated, right before its changes are
+// applied, and they are removed again if the changes can't be applied.
+func (sh *SnapshotHandler) HandleImport() rest.Handler {
+	l := sh.logger.With(zap.String("operation", "import"))
+	l.Info("received snapshot import request")
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		data := models.SnapshotQueryDI{}
+		err := json.NewDecoder(r.Body).Decode(&data)
+		if err != nil {
+			l.Error("unable to decode snapshot import request data", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+
+		scope := r.Header.Get(rest.HeaderScopeKey)
+		l := l.With(zap.String("scope", scope), zap.Bool("dry-run", data.DryRun))
+
+		if data.Snapshot.App == nil {
+			l.Error("received snapshot without a dApp")
+			rest.ERROR(w, ierrors.New("snapshot has no dApp to import").BadRequest())
+			return
+		}
+
+		plan, err := sh.planBrokers(data.Snapshot)
+		if err != nil {
+			l.Error("invalid snapshot brokers", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+
+		l.Debug("initiating snapshot import transaction")
+		sh.Memory.Tree().InitTransaction()
+
+		err = sh.Memory.Tree().Apps().Replace(scope, data.Snapshot.App, plan.brokers)
+		if err != nil {
+			l.Error("unable to replace the dApp with the snapshot", zap.Error(err))
+			rest.ERROR(w, err)
+			sh.Memory.Tree().Cancel()
+			return
+		}
+
+		changes, err := sh.Memory.Tree().GetTransactionChanges()
+		if err != nil {
+			l.Error("unable to get snapshot import changes", zap.Error(err))
+			rest.ERROR(w, err)
+			sh.Memory.Tree().Cancel()
+			return
+		}
+
+		if !data.DryRun {
+			l.Debug("configuring snapshot brokers")
+			err = sh.configureBrokers(plan)
+			if err != nil {
+				l.Error("unable to configure snapshot brokers", zap.Error(err))
+				rest.ERROR(w, err)
+				sh.Memory.Tree().Cancel()
+				return
+			}
+
+			l.Debug("applying snapshot import changes in diff")
+			err = sh.applyChangesInDiff(changes, author(r))
+			if err != nil {
+				l.Error("unable to apply snapshot import changes in diff", zap.Error(err))
+				sh.revertBrokers(plan, len(plan.create))
+				rest.ERROR(w, err)
+				return
+			}
+		} else {
+			l.Debug("cancelling snapshot import changes")
+			defer sh.Memory.Tree().Cancel()
+		}
+
+		rest.JSON(w, http.StatusOK, changes)
+	}
+	return rest.Handler(handler)
+}
+
+// brokersPlan holds the broker changes needed to import a snapshot
+type brokersPlan struct {
+	// brokers are the brokers of the cluster once the snapshot is imported
+	brokers *models.BrokersDI
+	// create has the configs of the brokers that aren't configured yet
+	create []brokers.BrokerConfiguration
+	// previousDefault is the default broker before the import
+	previousDefault string
+}
+
+// planBrokers validates the brokers of the snapshot and returns the changes
+// needed to configure the ones that aren't configured on the cluster yet and
+// to set its default broker, without applying them
+func (sh *SnapshotHandler) planBrokers(snapshot models.Snapshot) (*brokersPlan, error) {
+	available, err := sh.Memory.Brokers().Get()
+	if err != nil {
+		return nil, err
+	}
+	plan := &brokersPlan{
+		brokers:         available,
+		create:          []brokers.BrokerConfiguration{},
+		previousDefault: available.Default,
+	}
+
+	for _, broker := range snapshot.Brokers {
+		if utils.Includes(available.Available, broker.BrokerName) {
+			continue
+		}
+
+		config, err := brokerConfig(broker)
+		if err != nil {
+			return nil, err
+		}
+		plan.create = append(plan.create, config)
+		available.Available = append(available.Available, broker.BrokerName)
+	}
+
+	if snapshot.DefaultBroker != "" {
+		if !utils.Includes(available.Available, snapshot.DefaultBroker) {
+			return nil, ierrors.New(
+				"default broker %v is not configured", snapshot.DefaultBroker,
+			).BadRequest()
+		}
+		available.Default = snapshot.DefaultBroker
+	}
+	return plan, nil
+}
+
+// configureBrokers applies the broker changes of the plan, reverting the
+// ones already applied if any of them fails
+func (sh *SnapshotHandler) configureBrokers(plan *brokersPlan) error {
+	for i, config := range plan.create {
+		if err := sh.Memory.Brokers().Create(config); err != nil {
+			sh.revertBrokers(plan, i)
+			return err
+		}
+	}
+
+	if plan.brokers.Default != "" && plan.brokers.Default != plan.previousDefault {
+		if err := sh.Memory.Brokers().SetDefault(plan.brokers.Default); err != nil {
+			sh.revertBrokers(plan, len(plan.create))
+			return err
+		}
+	}
+	return nil
+}
+
+// revertBrokers deletes the first created brokers of the plan and restores
+// the previous default broker. Failures are only logged, as they can't be undone.
+func (sh *SnapshotHandler) revertBrokers(plan *brokersPlan, created int) {
+	l := sh.logger.With(zap.String("operation", "import"))
+	for i := created - 1; i >= 0; i-- {
+		broker := plan.create[i].Broker()
+		if err := sh.Memory.Brokers().Delete(broker); err != nil {
+			l.Error("unable to delete imported broker", zap.String("broker", broker), zap.Error(err))
+		}
+	}
+
+	if plan.previousDefault != "" {
+		if err := sh.Memory.Brokers().SetDefault(plan.previousDefault); err != nil {
+			l.Error("unable to restore the default broker",
+				zap.String("broker", plan.previousDefault), zap.Error(err))
+		}
+	}
+}
+
+// brokerConfig parses the configuration file contents of a broker
+func brokerConfig(broker models.BrokerConfigDI) (brokers.BrokerConfiguration, error) {
+	switch broker.BrokerName {
+	case brokers.Kafka:
+		var kafkaConfig sidecars.KafkaConfig
+		if err := yaml.Unmarshal(broker.FileContents, &kafkaConfig); err != nil {
+			return nil, ierrors.Wrap(
+				ierrors.New(err).BadRequest(),
+				fmt.Sprintf("invalid configs for broker %v", broker.BrokerName),
+			)
+		}
+		return &kafkaConfig, nil
+	case brokers.Memory:
+		var memoryConfig sidecars.MemoryConfig
+		if err := yaml.Unmarshal(broker.FileContents, &memoryConfig); err != nil {
+			return nil, ierrors.Wrap(
+				ierrors.New(err).BadRequest(),
+				fmt.Sprintf("invalid configs for broker %v", broker.BrokerName),
+			)
+		}
+		return &memoryConfig, nil
+	default:
+		return nil, ierrors.New("broker %v is not supported", broker.BrokerName).BadRequest()
+	}
+}
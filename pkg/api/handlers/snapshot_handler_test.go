@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"inspr.dev/inspr/cmd/insprd/memory/fake"
+	ofake "inspr.dev/inspr/cmd/insprd/operators/fake"
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/rest"
+	"inspr.dev/inspr/pkg/utils"
+)
+
+func TestSnapshotHandler_HandleExport(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler *Handler
+		scope   string
+		want    int
+	}{
+		{
+			name: "valid snapshot export request",
+			handler: func() *Handler {
+				h := &Handler{Memory: fake.GetMockMemoryManager(nil, nil)}
+				h.Memory.Tree().Apps().Create("", &meta.App{Meta: meta.Metadata{Name: "app1"}}, nil)
+				return h
+			}(),
+			scope: "app1",
+			want:  http.StatusOK,
+		},
+		{
+			name: "nonexistent scope",
+			handler: &Handler{
+				Memory: fake.GetMockMemoryManager(nil, nil),
+			},
+			scope: "app2",
+			want:  http.StatusNotFound,
+		},
+		{
+			name: "brokers memory error",
+			handler: func() *Handler {
+				h := &Handler{Memory: fake.GetMockMemoryManager(nil, errors.New("memory error"))}
+				h.Memory.Tree().Apps().Create("", &meta.App{Meta: meta.Metadata{Name: "app1"}}, nil)
+				return h
+			}(),
+			scope: "app1",
+			want:  http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sh := tt.handler.NewSnapshotHandler()
+			ts := httptest.NewServer(sh.HandleExport().HTTPHandlerFunc())
+			defer ts.Close()
+
+			req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+			req.Header.Set(rest.HeaderScopeKey, tt.scope)
+			res, err := ts.Client().Do(req)
+			if err != nil {
+				t.Fatalf("error making a GET in the httptest server")
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != tt.want {
+				t.Fatalf("SnapshotHandler.HandleExport() = %v, want %v", res.StatusCode, tt.want)
+			}
+
+			if tt.want == http.StatusOK {
+				snapshot := models.Snapshot{}
+				json.NewDecoder(res.Body).Decode(&snapshot)
+				if snapshot.Scope != tt.scope || snapshot.App == nil || snapshot.App.Meta.Name != "app1" {
+					t.Errorf("SnapshotHandler.HandleExport() returned the wrong dApp: %v", snapshot)
+				}
+				if len(snapshot.Brokers) != 1 || snapshot.DefaultBroker != "default_mock" {
+					t.Errorf("SnapshotHandler.HandleExport() returned the wrong brokers: %v", snapshot)
+				}
+			}
+		})
+	}
+}
+
+func TestSnapshotHandler_HandleImport(t *testing.T) {
+	kafka := models.BrokerConfigDI{
+		BrokerName:   "kafka",
+		FileContents: []byte("bootstrapServers: kafka:9092"),
+	}
+	snapshotBody := func(snapshot models.Snapshot, dryRun bool) []byte {
+		data, _ := json.Marshal(models.SnapshotQueryDI{Snapshot: snapshot, DryRun: dryRun})
+		return data
+	}
+	tests := []struct {
+		name        string
+		body        []byte
+		want        int
+		wantBrokers []string
+	}{
+		{
+			name: "invalid request body",
+			body: []byte{1},
+			want: http.StatusInternalServerError,
+		},
+		{
+			name: "snapshot without a dApp",
+			body: snapshotBody(models.Snapshot{Scope: "app1"}, false),
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "unsupported broker",
+			body: snapshotBody(models.Snapshot{
+				Scope:   "app1",
+				App:     &meta.App{},
+				Brokers: []models.BrokerConfigDI{{BrokerName: "unknown"}},
+			}, false),
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "default broker that isn't configured",
+			body: snapshotBody(models.Snapshot{
+				Scope:         "app1",
+				App:           &meta.App{},
+				Brokers:       []models.BrokerConfigDI{kafka},
+				DefaultBroker: "unknown",
+			}, false),
+			want:        http.StatusBadRequest,
+			wantBrokers: []string{"default_mock"},
+		},
+		{
+			name: "dry run import",
+			body: snapshotBody(models.Snapshot{
+				Scope:   "app1",
+				App:     &meta.App{},
+				Brokers: []models.BrokerConfigDI{kafka},
+			}, true),
+			want:        http.StatusOK,
+			wantBrokers: []string{"default_mock"},
+		},
+		{
+			name: "valid import",
+			body: snapshotBody(models.Snapshot{
+				Scope:         "app1",
+				App:           &meta.App{},
+				Brokers:       []models.BrokerConfigDI{kafka},
+				DefaultBroker: "kafka",
+			}, false),
+			want:        http.StatusOK,
+			wantBrokers: []string{"default_mock", "kafka"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{
+				Memory:   fake.GetMockMemoryManager(nil, nil),
+				Operator: ofake.NewFakeOperator(),
+			}
+			sh := h.NewSnapshotHandler()
+			ts := httptest.NewServer(sh.HandleImport().HTTPHandlerFunc())
+			defer ts.Close()
+
+			req, _ := http.NewRequest(http.MethodPut, ts.URL, bytes.NewBuffer(tt.body))
+			req.Header.Set(rest.HeaderScopeKey, "app1")
+			res, err := ts.Client().Do(req)
+			if err != nil {
+				t.Fatalf("error making a PUT in the httptest server")
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != tt.want {
+				t.Fatalf("SnapshotHandler.HandleImport() = %v, want %v", res.StatusCode, tt.want)
+			}
+
+			if tt.wantBrokers != nil {
+				brokers, _ := h.Memory.Brokers().Get()
+				if !utils.StringArray(brokers.Available).Equal(tt.wantBrokers) {
+					t.Errorf("SnapshotHandler.HandleImport() brokers = %v, want %v", brokers.Available, tt.wantBrokers)
+				}
+			}
+		})
+	}
+}
+
+func TestSnapshotHandler_revertBrokers(t *testing.T) {
+	h := &Handler{
+		Memory:   fake.GetMockMemoryManager(nil, nil),
+		Operator: ofake.NewFakeOperator(),
+	}
+	sh := h.NewSnapshotHandler()
+	previous, _ := h.Memory.Brokers().Get()
+
+	plan, err := sh.planBrokers(models.Snapshot{
+		Brokers: []models.BrokerConfigDI{{
+			BrokerName:   "kafka",
+			FileContents: []byte("bootstrapServers: kafka:9092"),
+		}},
+		DefaultBroker: "kafka",
+	})
+	if err != nil {
+		t.Fatalf("SnapshotHandler.planBrokers() error = %v", err)
+	}
+
+	if err = sh.configureBrokers(plan); err != nil {
+		t.Fatalf("SnapshotHandler.configureBrokers() error = %v", err)
+	}
+	configured, _ := h.Memory.Brokers().Get()
+	if configured.Default != "kafka" || len(configured.Available) != 2 {
+		t.Fatalf("SnapshotHandler.configureBrokers() brokers = %v", configured)
+	}
+
+	sh.revertBrokers(plan, len(plan.create))
+	reverted, _ := h.Memory.Brokers().Get()
+	if reverted.Default != previous.Default ||
+		!utils.StringArray(reverted.Available).Equal(previous.Available) {
+		t.Errorf("SnapshotHandler.revertBrokers() brokers = %v, want %v", reverted, previous)
+	}
+}
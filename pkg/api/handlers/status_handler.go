@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+	"inspr.dev/inspr/cmd/insprd/operators"
+	"inspr.dev/inspr/pkg/api/models"
+	metautils "inspr.dev/inspr/pkg/meta/utils"
+	"inspr.dev/inspr/pkg/rest"
+)
+
+// StatusHandler - contains handlers that report the drift between the tree and the runtime
+type StatusHandler struct {
+	*Handler
+	reconciler *operators.Reconciler
+	logger     *zap.Logger
+}
+
+// NewStatusHandler - returns the handle functions that regard the
+// reconciliation of the runtime with the tree
+func (handler *Handler) NewStatusHandler(reconciler *operators.Reconciler) *StatusHandler {
+	return &StatusHandler{
+		Handler:    handler,
+		reconciler: reconciler,
+		logger:     logger.With(zap.String("subSection", "status")),
+	}
+}
+
+// HandleStatus - returns the handle function that reports the drifts
+// found by the last reconciliation in the scope of the request
+func (sh *StatusHandler) HandleStatus() rest.Handler {
+	l := sh.logger.With(zap.String("operation", "get"))
+	l.Info("received status request")
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		scope := r.Header.Get(rest.HeaderScopeKey)
+		rest.JSON(w, http.StatusOK, scopedReport(sh.reconciler.Status(), scope))
+	}
+	return rest.Handler(handler)
+}
+
+// HandleReconcile - returns the handle function that reconciles the runtime with
+// the tree, repairing the drifts found if requested, and reports them
+func (sh *StatusHandler) HandleReconcile() rest.Handler {
+	l := sh.logger.With(zap.String("operation", "reconcile"))
+	l.Info("received reconcile request")
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		data := models.ReconcileQueryDI{}
+		err := json.NewDecoder(r.Body).Decode(&data)
+		if err != nil {
+			l.Error("unable to decode reconcile request data", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+		scope := r.Header.Get(rest.HeaderScopeKey)
+
+		l.Debug("reconciling runtime", zap.Bool("repair", data.Repair))
+		report := sh.reconciler.Reconcile(r.Context(), data.Repair)
+		rest.JSON(w, http.StatusOK, scopedReport(report, scope))
+	}
+	return rest.Handler(handler)
+}
+
+// scopedReport returns the report with only the drifts in the given scope. The
+// errors, which may refer to any component, are only reported on the root scope,
+// and the amounts of checked components are always the ones of the whole tree.
+func scopedReport(report models.DriftReport, scope string) models.DriftReport {
+	if scope == "" {
+		return report
+	}
+
+	scoped := models.DriftReport{
+		Timestamp: report.Timestamp,
+		Channels:  report.Channels,
+		Nodes:     report.Nodes,
+		Drifts:    []models.Drift{},
+	}
+	for _, drift := range report.Drifts {
+		component, _ := metautils.JoinScopes(drift.Scope, drift.Name)
+		if component == scope || strings.HasPrefix(component, scope+".") {
+			scoped.Drifts = append(scoped.Drifts, drift)
+		}
+	}
+	return scoped
+}
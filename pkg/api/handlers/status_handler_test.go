@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"inspr.dev/inspr/cmd/insprd/memory/fake"
+	"inspr.dev/inspr/cmd/insprd/operators"
+	ofake "inspr.dev/inspr/cmd/insprd/operators/fake"
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/rest"
+)
+
+func statusHandler() *StatusHandler {
+	h := &Handler{
+		Memory:   fake.GetMockMemoryManager(nil, nil),
+		Operator: ofake.NewFakeOperator(),
+	}
+	h.Memory.Tree().Apps().Create("", &meta.App{
+		Spec: meta.AppSpec{
+			Channels: map[string]*meta.Channel{"ch1": {Meta: meta.Metadata{Name: "ch1"}}},
+			Apps: map[string]*meta.App{
+				"app1": {
+					Meta: meta.Metadata{Name: "app1"},
+					Spec: meta.AppSpec{
+						Channels: map[string]*meta.Channel{
+							"ch2": {Meta: meta.Metadata{Name: "ch2", Parent: "app1"}},
+						},
+					},
+				},
+			},
+		},
+	}, nil)
+	return h.NewStatusHandler(operators.NewReconciler(h.Memory.Tree(), h.Operator))
+}
+
+func TestStatusHandler_HandleReconcile(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       []byte
+		scope      string
+		want       int
+		wantDrifts int
+	}{
+		{
+			name:       "reconcile the whole tree",
+			body:       []byte(`{"repair": false}`),
+			want:       http.StatusOK,
+			wantDrifts: 2,
+		},
+		{
+			name:       "reconcile a scope",
+			body:       []byte(`{"repair": false}`),
+			scope:      "app1",
+			want:       http.StatusOK,
+			wantDrifts: 1,
+		},
+		{
+			name: "invalid request body",
+			body: []byte{1},
+			want: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sh := statusHandler()
+			ts := httptest.NewServer(sh.HandleReconcile().HTTPHandlerFunc())
+			defer ts.Close()
+
+			req, _ := http.NewRequest(http.MethodPut, ts.URL, bytes.NewBuffer(tt.body))
+			req.Header.Set(rest.HeaderScopeKey, tt.scope)
+			res, err := ts.Client().Do(req)
+			if err != nil {
+				t.Fatalf("error making a PUT in the httptest server")
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != tt.want {
+				t.Errorf("StatusHandler.HandleReconcile() = %v, want %v", res.StatusCode, tt.want)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+
+			report := models.DriftReport{}
+			json.NewDecoder(res.Body).Decode(&report)
+			if len(report.Drifts) != tt.wantDrifts {
+				t.Errorf("StatusHandler.HandleReconcile() drifts = %v, want %v of them",
+					report.Drifts, tt.wantDrifts)
+			}
+			if status := sh.reconciler.Status(); len(status.Drifts) != 2 {
+				t.Errorf("StatusHandler.HandleReconcile() didn't keep the report of the whole tree")
+			}
+		})
+	}
+}
+
+func TestStatusHandler_HandleStatus(t *testing.T) {
+	sh := statusHandler()
+	ts := httptest.NewServer(sh.HandleStatus().HTTPHandlerFunc())
+	defer ts.Close()
+
+	get := func() models.DriftReport {
+		res, err := ts.Client().Get(ts.URL)
+		if err != nil {
+			t.Fatalf("error making a GET in the httptest server")
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			t.Errorf("StatusHandler.HandleStatus() = %v, want %v", res.StatusCode, http.StatusOK)
+		}
+		report := models.DriftReport{}
+		json.NewDecoder(res.Body).Decode(&report)
+		return report
+	}
+
+	if report := get(); len(report.Drifts) != 0 || !report.Timestamp.IsZero() {
+		t.Errorf("StatusHandler.HandleStatus() = %v before any reconciliation", report)
+	}
+
+	sh.reconciler.Reconcile(context.Background(), true)
+	report := get()
+	if len(report.Drifts) != 2 || !report.Drifts[0].Repaired {
+		t.Errorf("StatusHandler.HandleStatus() = %v, want the repaired drifts", report)
+	}
+}
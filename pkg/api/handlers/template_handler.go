@@ -0,0 +1,297 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	metautils "inspr.dev/inspr/pkg/meta/utils"
+	"inspr.dev/inspr/pkg/rest"
+)
+
+// TemplateHandler - contains handlers that uses the
+// TemplateMemory interface methods
+type TemplateHandler struct {
+	*Handler
+	logger *zap.Logger
+}
+
+// NewTemplateHandler - returns the handle function that
+// manages the Templates of dApps
+func (handler *Handler) NewTemplateHandler() *TemplateHandler {
+	return &TemplateHandler{
+		Handler: handler,
+		logger:  logger.With(zap.String("subSection", "template")),
+	}
+}
+
+// HandleCreate - returns the handle function that
+// manages the creation of a Template
+func (th *TemplateHandler) HandleCreate() rest.Handler {
+	l := th.logger.With(zap.String("operation", "create"))
+	l.Info("received template create request")
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		data := models.TemplateDI{}
+		scope := r.Header.Get(rest.HeaderScopeKey)
+
+		err := json.NewDecoder(r.Body).Decode(&data)
+		if err != nil {
+			l.Error("unable to decode Template create request data", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+		l = l.With(
+			zap.String("template", data.Template.Meta.Name),
+			zap.String("scope", scope),
+			zap.Bool("dry-run", data.DryRun),
+		)
+
+		l.Debug("initiating Template create transaction")
+		th.Memory.Tree().InitTransaction()
+
+		err = th.Memory.Tree().Templates().Create(scope, &data.Template)
+		if err != nil {
+			l.Error("unable to create Template", zap.Error(err))
+			rest.ERROR(w, err)
+			th.Memory.Tree().Cancel()
+			return
+		}
+
+		diff, err := th.Memory.Tree().GetTransactionChanges()
+		if err != nil {
+			l.Error("unable to get Template create request changes", zap.Error(err))
+			rest.ERROR(w, err)
+			th.Memory.Tree().Cancel()
+			return
+		}
+
+		if !data.DryRun {
+			l.Info("committing Template create changes")
+			err = th.Memory.Tree().Commit(author(r))
+			if err != nil {
+				l.Error("unable to commit Template create changes", zap.Error(err))
+				rest.ERROR(w, err)
+				return
+			}
+		} else {
+			l.Debug("canceling Template create changes")
+			defer th.Memory.Tree().Cancel()
+		}
+
+		rest.JSON(w, http.StatusOK, diff)
+	}
+	return rest.Handler(handler)
+}
+
+// HandleGet - return a handle function that obtains a Template by the
+// reference given, along with the scopes of the dApps instantiated from it
+func (th *TemplateHandler) HandleGet() rest.Handler {
+	l := th.logger.With(zap.String("operation", "get"))
+	l.Info("handling Template get request")
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		data := models.TemplateQueryDI{}
+		scope := r.Header.Get(rest.HeaderScopeKey)
+
+		err := json.NewDecoder(r.Body).Decode(&data)
+		if err != nil {
+			l.Error("unable to decode Template get request data", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+		l = l.With(
+			zap.String("template", data.TemplateName),
+			zap.String("scope", scope),
+		)
+
+		template, err := th.Memory.Tree().Perm().Templates().Get(scope, data.TemplateName)
+		if err != nil {
+			l.Error("unable to get Template", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+
+		root, err := th.Memory.Tree().Perm().Apps().Get("")
+		if err != nil {
+			l.Error("unable to get the root dApp", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+		path, _ := metautils.JoinScopes(scope, data.TemplateName)
+
+		rest.JSON(w, http.StatusOK, models.TemplateDO{
+			Template:  *template,
+			Instances: metautils.TemplateInstances(root, "", path),
+		})
+	}
+	return rest.Handler(handler)
+}
+
+// HandleUpdate - returns a handle function that updates the Template with the
+// parameters given in the request, rendering again every dApp instantiated
+// from it in the same transaction
+func (th *TemplateHandler) HandleUpdate() rest.Handler {
+	l := th.logger.With(zap.String("operation", "update"))
+	l.Info("handling Template update request")
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		data := models.TemplateDI{}
+		scope := r.Header.Get(rest.HeaderScopeKey)
+
+		err := json.NewDecoder(r.Body).Decode(&data)
+		if err != nil {
+			l.Error("unable to decode Template update request data", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+		l = l.With(
+			zap.String("template", data.Template.Meta.Name),
+			zap.String("scope", scope),
+			zap.Bool("dry-run", data.DryRun),
+		)
+
+		l.Debug("initiating Template update transaction")
+		th.Memory.Tree().InitTransaction()
+
+		err = th.Memory.Tree().Templates().Update(scope, &data.Template)
+		if err != nil {
+			l.Error("unable to update Template", zap.Error(err))
+			rest.ERROR(w, err)
+			th.Memory.Tree().Cancel()
+			return
+		}
+
+		err = th.rollout(scope, &data.Template)
+		if err != nil {
+			l.Error("unable to roll out the Template to its instances", zap.Error(err))
+			rest.ERROR(w, err)
+			th.Memory.Tree().Cancel()
+			return
+		}
+
+		diff, err := th.Memory.Tree().GetTransactionChanges()
+		if err != nil {
+			l.Error("unable to get Template update request changes", zap.Error(err))
+			rest.ERROR(w, err)
+			th.Memory.Tree().Cancel()
+			return
+		}
+
+		if !data.DryRun {
+			l.Debug("applying Template update changes in diff")
+			err = th.applyChangesInDiff(diff, author(r))
+			if err != nil {
+				l.Error("unable to apply Template update changes in diff", zap.Error(err))
+				rest.ERROR(w, err)
+				return
+			}
+		} else {
+			l.Debug("canceling Template update changes")
+			defer th.Memory.Tree().Cancel()
+		}
+
+		rest.JSON(w, http.StatusOK, diff)
+	}
+	return rest.Handler(handler)
+}
+
+// HandleDelete - returns a handle function that deletes the Template of
+// the given path, which is refused while it has instances
+func (th *TemplateHandler) HandleDelete() rest.Handler {
+	l := th.logger.With(zap.String("operation", "delete"))
+	l.Info("handling Template delete request")
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		data := models.TemplateQueryDI{}
+		scope := r.Header.Get(rest.HeaderScopeKey)
+
+		err := json.NewDecoder(r.Body).Decode(&data)
+		if err != nil {
+			l.Error("unable to decode Template delete request data", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+		l = l.With(
+			zap.String("template", data.TemplateName),
+			zap.String("scope", scope),
+			zap.Bool("dry-run", data.DryRun),
+		)
+
+		l.Debug("initiating Template delete transaction")
+		th.Memory.Tree().InitTransaction()
+
+		current, err := th.Memory.Tree().Templates().Get(scope, data.TemplateName)
+		if err == nil {
+			err = metautils.ValidateResourceVersion(current.Meta, data.ResourceVersion)
+		}
+		if err == nil {
+			err = th.Memory.Tree().Templates().Delete(scope, data.TemplateName)
+		}
+		if err != nil {
+			l.Error("unable to delete Template", zap.Error(err))
+			rest.ERROR(w, err)
+			th.Memory.Tree().Cancel()
+			return
+		}
+
+		diff, err := th.Memory.Tree().GetTransactionChanges()
+		if err != nil {
+			l.Error("unable to get Template delete request changes", zap.Error(err))
+			rest.ERROR(w, err)
+			th.Memory.Tree().Cancel()
+			return
+		}
+
+		if !data.DryRun {
+			l.Info("committing Template delete changes")
+			err = th.Memory.Tree().Commit(author(r))
+			if err != nil {
+				l.Error("unable to commit Template delete changes", zap.Error(err))
+				rest.ERROR(w, err)
+				return
+			}
+		} else {
+			l.Debug("canceling Template delete changes")
+			defer th.Memory.Tree().Cancel()
+		}
+
+		rest.JSON(w, http.StatusOK, diff)
+	}
+	return rest.Handler(handler)
+}
+
+// rollout renders the given Template again for each of its instances, with
+// the values they were created with, and replaces their dApps in the tree
+func (th *TemplateHandler) rollout(scope string, template *meta.Template) error {
+	path, err := metautils.JoinScopes(scope, template.Meta.Name)
+	if err != nil {
+		return err
+	}
+
+	instances, err := th.Memory.Tree().Templates().Instances(scope, template.Meta.Name)
+	if err != nil {
+		return err
+	}
+
+	brokers, err := th.Memory.Brokers().Get()
+	if err != nil {
+		return err
+	}
+
+	for _, query := range instances {
+		app, err := th.Memory.Tree().Apps().Get(query)
+		if err != nil {
+			return err
+		}
+		instance, _ := metautils.InstanceOf(app)
+
+		rendered, err := metautils.InstantiateTemplate(template, path, instance)
+		if err == nil {
+			err = th.Memory.Tree().Apps().Replace(query, rendered, brokers)
+		}
+		if err != nil {
+			return ierrors.Wrap(err, "unable to roll out the template to "+query)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"inspr.dev/inspr/cmd/insprd/memory/fake"
+	ofake "inspr.dev/inspr/cmd/insprd/operators/fake"
+	"inspr.dev/inspr/pkg/api/models"
+	authmock "inspr.dev/inspr/pkg/auth/mocks"
+	"inspr.dev/inspr/pkg/meta"
+	metautils "inspr.dev/inspr/pkg/meta/utils"
+	"inspr.dev/inspr/pkg/rest"
+)
+
+func templateWithImage(image string) meta.Template {
+	return meta.Template{
+		Meta: meta.Metadata{Name: "pipeline"},
+		Parameters: []meta.TemplateParameter{
+			{Name: "customer", Required: true},
+		},
+		App: meta.App{
+			Spec: meta.AppSpec{
+				Node: meta.Node{Spec: meta.NodeSpec{Image: image}},
+			},
+		},
+	}
+}
+
+// sendToHandler sends the given data to the handler, in the root scope,
+// and returns the status of the response
+func sendToHandler(t *testing.T, h rest.Handler, method string, data interface{}) int {
+	body, _ := json.Marshal(data)
+	ts := httptest.NewServer(h.HTTPHandlerFunc())
+	defer ts.Close()
+
+	req, _ := http.NewRequest(method, ts.URL, bytes.NewBuffer(body))
+	req.Header.Set(rest.HeaderScopeKey, "")
+	res, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatalf("error making a %v in the httptest server", method)
+	}
+	defer res.Body.Close()
+	return res.StatusCode
+}
+
+func TestTemplateHandler_rollout(t *testing.T) {
+	h := NewHandler(fake.GetMockMemoryManager(nil, nil), ofake.NewFakeOperator(), authmock.NewMockAuth(nil))
+	th := h.NewTemplateHandler()
+	ih := h.NewInstanceHandler()
+
+	status := sendToHandler(t, th.HandleCreate(), http.MethodPost, models.TemplateDI{
+		Template: templateWithImage("{{ .customer }}/pipeline:v1"),
+	})
+	if status != http.StatusOK {
+		t.Fatalf("TemplateHandler.HandleCreate() = %v, want %v", status, http.StatusOK)
+	}
+
+	instance := meta.TemplateInstance{
+		Meta:     meta.Metadata{Name: "acme"},
+		Template: "pipeline",
+		Values:   map[string]string{"customer": "acme"},
+	}
+	status = sendToHandler(t, ih.HandleCreate(), http.MethodPost, models.InstanceDI{Instance: instance})
+	if status != http.StatusOK {
+		t.Fatalf("InstanceHandler.HandleCreate() = %v, want %v", status, http.StatusOK)
+	}
+
+	app, err := h.Memory.Tree().Apps().Get("acme")
+	if err != nil || app.Spec.Node.Spec.Image != "acme/pipeline:v1" {
+		t.Fatalf("InstanceHandler.HandleCreate() created %v, %v, want the rendered dApp", app, err)
+	}
+	if app.Meta.Annotations[metautils.TemplateAnnotation] != "pipeline" {
+		t.Errorf("InstanceHandler.HandleCreate() annotations = %v, want the template path", app.Meta.Annotations)
+	}
+
+	status = sendToHandler(t, th.HandleUpdate(), http.MethodPut, models.TemplateDI{
+		Template: templateWithImage("{{ .customer }}/pipeline:v2"),
+	})
+	if status != http.StatusOK {
+		t.Fatalf("TemplateHandler.HandleUpdate() = %v, want %v", status, http.StatusOK)
+	}
+	app, _ = h.Memory.Tree().Apps().Get("acme")
+	if app.Spec.Node.Spec.Image != "acme/pipeline:v2" {
+		t.Errorf("TemplateHandler.HandleUpdate() instance image = %v, want acme/pipeline:v2", app.Spec.Node.Spec.Image)
+	}
+
+	status = sendToHandler(t, th.HandleDelete(), http.MethodDelete, models.TemplateQueryDI{TemplateName: "pipeline"})
+	if status != http.StatusOK {
+		t.Errorf("TemplateHandler.HandleDelete() = %v, want %v", status, http.StatusOK)
+	}
+}
@@ -6,6 +6,8 @@ import (
 
 	"go.uber.org/zap"
 	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/meta"
+	metautils "inspr.dev/inspr/pkg/meta/utils"
 	"inspr.dev/inspr/pkg/rest"
 )
 
@@ -68,7 +70,12 @@ func (th *TypeHandler) HandleCreate() rest.Handler {
 
 		if !data.DryRun {
 			l.Info("committing Type create changes")
-			defer th.Memory.Tree().Commit()
+			err = th.Memory.Tree().Commit(author(r))
+			if err != nil {
+				l.Error("unable to commit Type create changes", zap.Error(err))
+				rest.ERROR(w, err)
+				return
+			}
 		} else {
 			l.Debug("canceling Type create changes")
 			defer th.Memory.Tree().Cancel()
@@ -100,19 +107,21 @@ func (th *TypeHandler) HandleGet() rest.Handler {
 			zap.Bool("dry-run", data.DryRun),
 		)
 
-		l.Debug("initiating Type get transaction")
-		th.Memory.Tree().InitTransaction()
+		if data.Selector != nil {
+			th.selectComponents(w, l, scope, *data.Selector,
+				func(s metautils.Selector, app *meta.App) interface{} {
+					return s.SelectTypes(app, scope)
+				})
+			return
+		}
 
 		insprType, err := th.Memory.Tree().Perm().Types().Get(scope, data.TypeName)
 		if err != nil {
 			l.Error("unable to get Type", zap.Error(err))
 			rest.ERROR(w, err)
-			th.Memory.Tree().Cancel()
 			return
 		}
 
-		defer th.Memory.Tree().Cancel()
-
 		rest.JSON(w, http.StatusOK, insprType)
 	}
 	return rest.Handler(handler)
@@ -160,18 +169,15 @@ func (th *TypeHandler) HandleUpdate() rest.Handler {
 
 		if !data.DryRun {
 			l.Debug("applying Type update changes in diff")
-			err = th.applyChangesInDiff(diff)
+			err = th.applyChangesInDiff(diff, author(r))
 			if err != nil {
 				l.Error("unable to apply Type update changes in diff", zap.Error(err))
 				rest.ERROR(w, err)
-				th.Memory.Tree().Cancel()
 				return
 			}
-
-			l.Info("committing Type update changes")
-			defer th.Memory.Tree().Commit()
 		} else {
 			l.Debug("canceling Type update changes")
+			th.addCompatibility(diff)
 			defer th.Memory.Tree().Cancel()
 		}
 
@@ -204,6 +210,17 @@ func (th *TypeHandler) HandleDelete() rest.Handler {
 		l.Debug("initiating Type delete transaction")
 		th.Memory.Tree().InitTransaction()
 
+		current, err := th.Memory.Tree().Types().Get(scope, data.TypeName)
+		if err == nil {
+			err = metautils.ValidateResourceVersion(current.Meta, data.ResourceVersion)
+		}
+		if err != nil {
+			l.Error("unable to delete Type", zap.Error(err))
+			rest.ERROR(w, err)
+			th.Memory.Tree().Cancel()
+			return
+		}
+
 		err = th.Memory.Tree().Types().Delete(scope, data.TypeName)
 		if err != nil {
 			l.Error("unable to delete Type", zap.Error(err))
@@ -222,7 +239,12 @@ func (th *TypeHandler) HandleDelete() rest.Handler {
 
 		if !data.DryRun {
 			l.Info("committing Type delete changes")
-			defer th.Memory.Tree().Commit()
+			err = th.Memory.Tree().Commit(author(r))
+			if err != nil {
+				l.Error("unable to commit Type delete changes", zap.Error(err))
+				rest.ERROR(w, err)
+				return
+			}
 		} else {
 			l.Debug("canceling Type delete changes")
 			defer th.Memory.Tree().Cancel()
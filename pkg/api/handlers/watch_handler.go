@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"go.uber.org/zap"
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
+	metautils "inspr.dev/inspr/pkg/meta/utils"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+	"inspr.dev/inspr/pkg/rest"
+)
+
+// WatchHandler - contains handlers that stream the changes committed on the tree
+type WatchHandler struct {
+	*Handler
+	logger *zap.Logger
+}
+
+// NewWatchHandler - returns the handle functions that regard watching the tree
+func (handler *Handler) NewWatchHandler() *WatchHandler {
+	return &WatchHandler{
+		Handler: handler,
+		logger:  logger.With(zap.String("subSection", "watch")),
+	}
+}
+
+// HandleWatch - returns the handle function that streams, as newline delimited
+// JSON, the changes committed on the tree under the scope of the request. The
+// stream starts with a bookmark event and ends when the client disconnects.
+func (wh *WatchHandler) HandleWatch() rest.Handler {
+	l := wh.logger.With(zap.String("operation", "watch"))
+	l.Info("received watch request")
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		data := models.WatchDI{}
+		err := json.NewDecoder(r.Body).Decode(&data)
+		if err != nil && err != io.EOF {
+			l.Error("unable to decode watch request data", zap.Error(err))
+			rest.ERROR(w, ierrors.New(err).BadRequest())
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			l.Error("response writer doesn't support streaming")
+			rest.ERROR(w, ierrors.New("streaming unsupported").InternalServer())
+			return
+		}
+
+		scope := r.Header.Get(rest.HeaderScopeKey)
+		l := l.With(zap.String("scope", scope), zap.Int("since", data.Since))
+
+		// subscribing before reading the history leaves no revision unsent
+		current, revisions, cancel := wh.Memory.Tree().Revisions().Subscribe()
+		defer cancel()
+
+		missed := []models.Revision{}
+		if data.Since > 0 && data.Since < current {
+			history, err := wh.Memory.Tree().Revisions().History()
+			if err != nil {
+				l.Error("unable to get revision history", zap.Error(err))
+				rest.ERROR(w, err)
+				return
+			}
+			for _, revision := range history {
+				if revision.Number > data.Since && revision.Number <= current {
+					missed = append(missed, revision)
+				}
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+		encoder := json.NewEncoder(w)
+
+		send := func(revision models.Revision) error {
+			for _, change := range revision.Changes {
+				change, ok := filterChange(change, scope, data.Kinds)
+				if !ok {
+					continue
+				}
+				err := encoder.Encode(models.WatchEvent{
+					Revision:  revision.Number,
+					Author:    revision.Author,
+					Timestamp: revision.Timestamp,
+					Change:    change,
+				})
+				if err != nil {
+					return err
+				}
+			}
+			flusher.Flush()
+			return nil
+		}
+
+		for _, revision := range missed {
+			if err = send(revision); err != nil {
+				l.Debug("unable to send revision, ending watch", zap.Error(err))
+				return
+			}
+		}
+		if err = encoder.Encode(models.WatchEvent{Revision: current, Bookmark: true}); err != nil {
+			l.Debug("unable to send bookmark, ending watch", zap.Error(err))
+			return
+		}
+		flusher.Flush()
+
+		l.Debug("watching tree changes")
+		for {
+			select {
+			case <-r.Context().Done():
+				l.Debug("client disconnected, ending watch")
+				return
+			case revision, ok := <-revisions:
+				if !ok {
+					l.Info("watcher fell behind the commits, ending watch")
+					return
+				}
+				if revision.Number <= current {
+					continue
+				}
+				if err = send(revision); err != nil {
+					l.Debug("unable to send revision, ending watch", zap.Error(err))
+					return
+				}
+			}
+		}
+	}
+	return rest.Handler(handler)
+}
+
+// filterChange returns the given change with only the differences of the
+// given kinds, or all of them if kinds is zero, and whether the change
+// is inside the given scope and has any difference left
+func filterChange(change diff.Change, scope string, kinds diff.Kind) (diff.Change, bool) {
+	if !metautils.IsInnerScope(scope, change.Scope) {
+		return change, false
+	}
+	if kinds == 0 {
+		return change, true
+	}
+
+	filtered := diff.Change{
+		Scope: change.Scope,
+		Diff:  []diff.Difference{},
+	}
+	for _, d := range change.Diff {
+		if d.Kind&kinds != 0 {
+			filtered.Diff = append(filtered.Diff, d)
+			filtered.Kind |= d.Kind
+			filtered.Operation |= d.Operation
+		}
+	}
+	return filtered, len(filtered.Diff) > 0
+}
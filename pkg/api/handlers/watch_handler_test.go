@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"inspr.dev/inspr/cmd/insprd/memory"
+	"inspr.dev/inspr/cmd/insprd/memory/fake"
+	"inspr.dev/inspr/cmd/insprd/memory/tree"
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+	"inspr.dev/inspr/pkg/rest"
+)
+
+// watchMemory is a memory manager whose revisions are fed by the tests
+type watchMemory struct {
+	memory.Manager
+	revisions *watchRevisions
+}
+
+func (wm watchMemory) Tree() tree.Manager {
+	return watchTree{wm.Manager.Tree(), wm.revisions}
+}
+
+type watchTree struct {
+	tree.Manager
+	revisions *watchRevisions
+}
+
+func (wt watchTree) Revisions() tree.RevisionMemory {
+	return wt.revisions
+}
+
+type watchRevisions struct {
+	tree.RevisionMemory
+	current int
+	history []models.Revision
+	events  chan models.Revision
+}
+
+func (wr *watchRevisions) History() ([]models.Revision, error) {
+	return wr.history, nil
+}
+
+func (wr *watchRevisions) Subscribe() (int, <-chan models.Revision, func()) {
+	return wr.current, wr.events, func() {}
+}
+
+func watchRevision(number int, changes ...diff.Change) models.Revision {
+	return models.Revision{Number: number, Author: "user", Changes: changes}
+}
+
+func TestWatchHandler_HandleWatch(t *testing.T) {
+	appChange := diff.Change{
+		Scope: "app1",
+		Kind:  diff.AppKind | diff.ChannelKind,
+		Diff: []diff.Difference{
+			{Kind: diff.AppKind, Name: "app1", Operation: diff.Update},
+			{Kind: diff.ChannelKind, Name: "ch1", Operation: diff.Create},
+		},
+		Operation: diff.Update | diff.Create,
+	}
+	otherChange := diff.Change{
+		Scope:     "app2",
+		Kind:      diff.ChannelKind,
+		Diff:      []diff.Difference{{Kind: diff.ChannelKind, Name: "ch2", Operation: diff.Create}},
+		Operation: diff.Create,
+	}
+	channelChange := diff.Change{
+		Scope:     "app1",
+		Kind:      diff.ChannelKind,
+		Diff:      []diff.Difference{{Kind: diff.ChannelKind, Name: "ch1", Operation: diff.Create}},
+		Operation: diff.Create,
+	}
+
+	tests := []struct {
+		name    string
+		scope   string
+		body    interface{}
+		current int
+		history []models.Revision
+		live    []models.Revision
+		want    []models.WatchEvent
+	}{
+		{
+			name:    "streams every change",
+			current: 1,
+			live: []models.Revision{
+				watchRevision(1, otherChange),
+				watchRevision(2, appChange, otherChange),
+			},
+			want: []models.WatchEvent{
+				{Revision: 1, Bookmark: true},
+				{Revision: 2, Author: "user", Change: appChange},
+				{Revision: 2, Author: "user", Change: otherChange},
+			},
+		},
+		{
+			name:  "filters changes by scope and kind",
+			scope: "app1",
+			body:  models.WatchDI{Kinds: diff.ChannelKind},
+			live: []models.Revision{
+				watchRevision(1, appChange, otherChange),
+			},
+			want: []models.WatchEvent{
+				{Revision: 0, Bookmark: true},
+				{Revision: 1, Author: "user", Change: channelChange},
+			},
+		},
+		{
+			name:    "replays the changes since the given revision",
+			body:    models.WatchDI{Since: 1},
+			current: 2,
+			history: []models.Revision{
+				watchRevision(1, appChange),
+				watchRevision(2, otherChange),
+			},
+			live: []models.Revision{
+				watchRevision(3, appChange),
+			},
+			want: []models.WatchEvent{
+				{Revision: 2, Author: "user", Change: otherChange},
+				{Revision: 2, Bookmark: true},
+				{Revision: 3, Author: "user", Change: appChange},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revisions := &watchRevisions{
+				current: tt.current,
+				history: tt.history,
+				events:  make(chan models.Revision, len(tt.live)),
+			}
+			for _, revision := range tt.live {
+				revisions.events <- revision
+			}
+			// the stream ends once the subscription does
+			close(revisions.events)
+
+			h := &Handler{
+				Memory: watchMemory{fake.GetMockMemoryManager(nil, nil), revisions},
+			}
+			ts := httptest.NewServer(h.NewWatchHandler().HandleWatch().HTTPHandlerFunc())
+			defer ts.Close()
+
+			var body []byte
+			if tt.body != nil {
+				body, _ = json.Marshal(tt.body)
+			}
+			req, _ := http.NewRequest(http.MethodGet, ts.URL, bytes.NewBuffer(body))
+			req.Header.Set(rest.HeaderScopeKey, tt.scope)
+			res, err := ts.Client().Do(req)
+			if err != nil {
+				t.Fatalf("error making a GET in the httptest server")
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != http.StatusOK {
+				t.Fatalf("WatchHandler.HandleWatch() status = %v", res.StatusCode)
+			}
+
+			got := []models.WatchEvent{}
+			scanner := bufio.NewScanner(res.Body)
+			for scanner.Scan() {
+				event := models.WatchEvent{}
+				if err = json.Unmarshal(scanner.Bytes(), &event); err != nil {
+					t.Fatalf("WatchHandler.HandleWatch() sent invalid event: %v", err)
+				}
+				got = append(got, event)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WatchHandler.HandleWatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatchHandler_HandleWatch_invalidBody(t *testing.T) {
+	h := &Handler{Memory: fake.GetMockMemoryManager(nil, nil)}
+	ts := httptest.NewServer(h.NewWatchHandler().HandleWatch().HTTPHandlerFunc())
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL, bytes.NewBuffer([]byte{1}))
+	res, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatalf("error making a GET in the httptest server")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusBadRequest {
+		t.Errorf("WatchHandler.HandleWatch() = %v, want %v", res.StatusCode, http.StatusBadRequest)
+	}
+}
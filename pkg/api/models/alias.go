@@ -8,8 +8,13 @@ type AliasDI struct {
 	DryRun bool       `json:"dry"`
 }
 
-// AliasQueryDI - Data Input format for queries requests
+// AliasQueryDI - Data Input format for queries requests. When the resource
+// version is set, deletes are rejected if it is outdated. When the selector
+// is set, gets return every one of the aliases in the subtree of the scope
+// whose annotations match it
 type AliasQueryDI struct {
-	Name   string `json:"name"`
-	DryRun bool   `json:"dry"`
+	Name            string  `json:"name"`
+	DryRun          bool    `json:"dry"`
+	ResourceVersion int     `json:"resourceVersion,omitempty"`
+	Selector        *string `json:"selector,omitempty"`
 }
@@ -8,8 +8,25 @@ type ChannelDI struct {
 	DryRun  bool         `json:"dry"`
 }
 
-// ChannelQueryDI - Data Input format for queries requests
+// ChannelQueryDI - Data Input format for queries requests. When the resource
+// version is set, deletes are rejected if it is outdated. When the selector
+// is set, gets return every one of the channels in the subtree of the scope
+// whose annotations match it
 type ChannelQueryDI struct {
+	ChName          string  `json:"chname"`
+	DryRun          bool    `json:"dry"`
+	ResourceVersion int     `json:"resourceVersion,omitempty"`
+	Selector        *string `json:"selector,omitempty"`
+}
+
+// DefaultDeadLetterLimit is the amount of dead-letter messages returned when
+// the request doesn't set a limit
+const DefaultDeadLetterLimit = 20
+
+// DeadLetterQueryDI - Data Input format for requests of the messages of a
+// channel that were sent to its dead-letter channel. Only the latest 'Limit'
+// messages of the dead-letter channel are looked at
+type DeadLetterQueryDI struct {
 	ChName string `json:"chname"`
-	DryRun bool   `json:"dry"`
+	Limit  int    `json:"limit,omitempty"`
 }
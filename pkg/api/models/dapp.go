@@ -10,7 +10,12 @@ type AppDI struct {
 	DryRun bool     `json:"dry"`
 }
 
-// AppQueryDI - Data Input format for queries requests
+// AppQueryDI - Data Input format for queries requests. When the resource
+// version is set, deletes are rejected if it is outdated. When the selector
+// is set, gets return every one of the dApps in the subtree of the scope
+// whose annotations match it
 type AppQueryDI struct {
-	DryRun bool `json:"dry"`
+	DryRun          bool    `json:"dry"`
+	ResourceVersion int     `json:"resourceVersion,omitempty"`
+	Selector        *string `json:"selector,omitempty"`
 }
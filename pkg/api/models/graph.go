@@ -0,0 +1,182 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Formats in which a graph can be rendered
+const (
+	GraphJSON    = "json"
+	GraphDOT     = "dot"
+	GraphMermaid = "mermaid"
+)
+
+// Kinds of the vertices and edges of a graph
+const (
+	GraphNode    = "node"
+	GraphChannel = "channel"
+	GraphRoute   = "route"
+
+	GraphInput  = "input"
+	GraphOutput = "output"
+)
+
+// GraphVertex - Data Output format of a component of the data flow of a scope.
+// The ID of a vertex is its kind and the full path of the component, such as
+// "channel:app1.ch1", and Type is the Type of the messages of a channel
+type GraphVertex struct {
+	ID    string `json:"id"`
+	Kind  string `json:"kind"`
+	Scope string `json:"scope"`
+	Name  string `json:"name"`
+	Type  string `json:"type,omitempty"`
+}
+
+// GraphEdge - Data Output format of a connection between two components,
+// with the name of the boundary it was resolved from. Alias is set when the
+// boundary is resolved through aliases
+type GraphEdge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Kind     string `json:"kind"`
+	Boundary string `json:"boundary"`
+	Alias    bool   `json:"alias,omitempty"`
+}
+
+// Graph - Data Output format of the resolved data flow of a scope: the nodes
+// and the channels and routes they are connected to
+type Graph struct {
+	Scope    string        `json:"scope"`
+	Vertices []GraphVertex `json:"vertices"`
+	Edges    []GraphEdge   `json:"edges"`
+}
+
+// GraphQueryDI - Data Input format for graph requests. When the format is dot
+// or mermaid the response is the rendered graph, otherwise it's the graph itself
+type GraphQueryDI struct {
+	Format string `json:"format"`
+}
+
+// Sort sorts the vertices and the edges of the graph, so it's always rendered
+// the same way
+func (g *Graph) Sort() {
+	sort.Slice(g.Vertices, func(i, j int) bool {
+		return g.Vertices[i].ID < g.Vertices[j].ID
+	})
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		if g.Edges[i].To != g.Edges[j].To {
+			return g.Edges[i].To < g.Edges[j].To
+		}
+		return g.Edges[i].Boundary < g.Edges[j].Boundary
+	})
+}
+
+// DOT renders the graph in the Graphviz DOT language, grouping the
+// components of each dApp in a cluster
+func (g *Graph) DOT() string {
+	b := &strings.Builder{}
+	fmt.Fprintln(b, "digraph inspr {")
+	fmt.Fprintln(b, "\trankdir=LR;")
+
+	for i, scope := range g.scopes() {
+		indent := "\t"
+		if scope != "" {
+			fmt.Fprintf(b, "\tsubgraph cluster_%d {\n", i)
+			fmt.Fprintf(b, "\t\tlabel=%q;\n", scope)
+			indent = "\t\t"
+		}
+		for _, v := range g.Vertices {
+			if v.Scope == scope {
+				fmt.Fprintf(b, "%s%q [label=%q, shape=%s];\n", indent, v.ID, v.label(), dotShapes[v.Kind])
+			}
+		}
+		if scope != "" {
+			fmt.Fprintln(b, "\t}")
+		}
+	}
+
+	for _, e := range g.Edges {
+		attrs := fmt.Sprintf("label=%q", e.Boundary)
+		if e.Alias || e.Kind == GraphRoute {
+			attrs += ", style=dashed"
+		}
+		fmt.Fprintf(b, "\t%q -> %q [%s];\n", e.From, e.To, attrs)
+	}
+	fmt.Fprintln(b, "}")
+	return b.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart, grouping the components
+// of each dApp in a subgraph
+func (g *Graph) Mermaid() string {
+	ids := map[string]string{}
+	for i, v := range g.Vertices {
+		ids[v.ID] = fmt.Sprintf("v%d", i)
+	}
+
+	b := &strings.Builder{}
+	fmt.Fprintln(b, "flowchart LR")
+	for i, scope := range g.scopes() {
+		indent := "    "
+		if scope != "" {
+			fmt.Fprintf(b, "    subgraph s%d [\"%s\"]\n", i, scope)
+			indent = "        "
+		}
+		for _, v := range g.Vertices {
+			if v.Scope == scope {
+				shape := mermaidShapes[v.Kind]
+				fmt.Fprintf(b, "%s%s%s\"%s\"%s\n", indent, ids[v.ID], shape[0], v.label(), shape[1])
+			}
+		}
+		if scope != "" {
+			fmt.Fprintln(b, "    end")
+		}
+	}
+
+	for _, e := range g.Edges {
+		arrow := "-->"
+		if e.Alias || e.Kind == GraphRoute {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(b, "    %s %s|\"%s\"| %s\n", ids[e.From], arrow, e.Boundary, ids[e.To])
+	}
+	return b.String()
+}
+
+var dotShapes = map[string]string{
+	GraphNode:    "box",
+	GraphChannel: "ellipse",
+	GraphRoute:   "diamond",
+}
+
+var mermaidShapes = map[string][2]string{
+	GraphNode:    {"[", "]"},
+	GraphChannel: {"([", "])"},
+	GraphRoute:   {"{", "}"},
+}
+
+// scopes returns the sorted scopes of the vertices of the graph
+func (g *Graph) scopes() []string {
+	set := map[string]bool{}
+	scopes := []string{}
+	for _, v := range g.Vertices {
+		if !set[v.Scope] {
+			set[v.Scope] = true
+			scopes = append(scopes, v.Scope)
+		}
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+func (v GraphVertex) label() string {
+	if v.Type != "" {
+		return fmt.Sprintf("%s (%s)", v.Name, v.Type)
+	}
+	return v.Name
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+)
+
+// ReactionsErrorDI - Data Output format of a request whose changes couldn't be
+// applied on the runtime. Stack and Code are the ones of an ierror, so the
+// response can still be read as one, and Report has every reaction that was
+// applied and whether it was undone
+type ReactionsErrorDI struct {
+	Stack  string               `json:"stack"`
+	Code   ierrors.ErrCode      `json:"code"`
+	Report diff.ReactionsReport `json:"report"`
+}
+
+// Error returns the stack of the error that made the changes fail
+func (e *ReactionsErrorDI) Error() string {
+	return e.Stack
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+)
+
+// Revision - Data Output format of a transaction committed on insprd's tree.
+// The tree is only filled when a specific revision is requested.
+type Revision struct {
+	Number    int            `json:"number"`
+	Author    string         `json:"author"`
+	Timestamp time.Time      `json:"timestamp"`
+	Changes   diff.Changelog `json:"changes"`
+	Tree      *meta.App      `json:"tree,omitempty"`
+}
+
+// RevisionQueryDI - Data Input format for requests on a given revision
+type RevisionQueryDI struct {
+	Revision int  `json:"revision"`
+	DryRun   bool `json:"dry"`
+}
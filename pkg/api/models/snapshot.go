@@ -0,0 +1,21 @@
+package models
+
+import (
+	"inspr.dev/inspr/pkg/meta"
+)
+
+// Snapshot - Data format of the state of a subtree of insprd's tree,
+// along with the brokers configured on the cluster, so that it can be
+// restored on the same or on another cluster.
+type Snapshot struct {
+	Scope         string           `json:"scope"`
+	App           *meta.App        `json:"app"`
+	Brokers       []BrokerConfigDI `json:"brokers"`
+	DefaultBroker string           `json:"defaultbroker"`
+}
+
+// SnapshotQueryDI - Data Input format for snapshot imports
+type SnapshotQueryDI struct {
+	Snapshot Snapshot `json:"snapshot"`
+	DryRun   bool     `json:"dry"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Kinds of components whose runtime resources are reconciled with the tree
+const (
+	DriftChannel = "channel"
+	DriftNode    = "node"
+)
+
+// Drift - Data Output format of a component of the tree whose resources
+// are missing from the runtime
+type Drift struct {
+	Kind        string `json:"kind"`
+	Scope       string `json:"scope"`
+	Name        string `json:"name"`
+	Reason      string `json:"reason"`
+	Repaired    bool   `json:"repaired"`
+	RepairError string `json:"repairerror,omitempty"`
+}
+
+// DriftReport - Data Output format of a reconciliation of the runtime
+// with the tree. Errors holds the components that couldn't be checked.
+type DriftReport struct {
+	Timestamp time.Time `json:"timestamp"`
+	Channels  int       `json:"channels"`
+	Nodes     int       `json:"nodes"`
+	Drifts    []Drift   `json:"drifts"`
+	Errors    []string  `json:"errors,omitempty"`
+}
+
+// ReconcileQueryDI - Data Input format for requests to reconcile the runtime
+type ReconcileQueryDI struct {
+	Repair bool `json:"repair"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import "inspr.dev/inspr/pkg/meta"
+
+// TemplateDI - Data Input format for requests that pass the Template data
+type TemplateDI struct {
+	Template meta.Template `json:"template"`
+	DryRun   bool          `json:"dry"`
+}
+
+// TemplateQueryDI - Data Input format for queries requests. When the resource
+// version is set, deletes are rejected if it is outdated
+type TemplateQueryDI struct {
+	TemplateName    string `json:"templatename"`
+	DryRun          bool   `json:"dry"`
+	ResourceVersion int    `json:"resourceVersion,omitempty"`
+}
+
+// TemplateDO - Data Output format of a Template and of the scopes of the
+// dApps that were instantiated from it
+type TemplateDO struct {
+	Template  meta.Template `json:"template"`
+	Instances []string      `json:"instances"`
+}
+
+// InstanceDI - Data Input format for requests that pass the data of an
+// instance of a Template
+type InstanceDI struct {
+	Instance meta.TemplateInstance `json:"instance"`
+	DryRun   bool                  `json:"dry"`
+}
+
+// InstanceQueryDI - Data Input format for queries requests on the instances of Templates
+type InstanceQueryDI struct {
+	InstanceName string `json:"instancename"`
+	DryRun       bool   `json:"dry"`
+}
@@ -8,8 +8,13 @@ type TypeDI struct {
 	DryRun bool      `json:"dry"`
 }
 
-// TypeQueryDI - Data Input format for queries requests
+// TypeQueryDI - Data Input format for queries requests. When the resource
+// version is set, deletes are rejected if it is outdated. When the selector
+// is set, gets return every one of the Types in the subtree of the scope
+// whose annotations match it
 type TypeQueryDI struct {
-	TypeName string `json:"typename"`
-	DryRun   bool   `json:"dry"`
+	TypeName        string  `json:"typename"`
+	DryRun          bool    `json:"dry"`
+	ResourceVersion int     `json:"resourceVersion,omitempty"`
+	Selector        *string `json:"selector,omitempty"`
 }
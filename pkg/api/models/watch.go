@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+)
+
+// WatchDI - Data Input format for watch requests. When Since is set, the
+// changes committed after the given revision are sent before the new ones.
+// A zero Kinds watches every kind of change.
+type WatchDI struct {
+	Kinds diff.Kind `json:"kinds"`
+	Since int       `json:"since"`
+}
+
+// WatchEvent - Data Output format of a change streamed by a watch request.
+// Bookmark events carry no change, only the last revision committed when
+// the watch started, from which a dropped watch can be resumed.
+type WatchEvent struct {
+	Revision  int         `json:"revision"`
+	Author    string      `json:"author"`
+	Timestamp time.Time   `json:"timestamp"`
+	Change    diff.Change `json:"change"`
+	Bookmark  bool        `json:"bookmark,omitempty"`
+}
@@ -0,0 +1,18 @@
+package auth
+
+import "context"
+
+// payloadKey is the context key under which the request's payload is stored
+type payloadKey struct{}
+
+// WithPayload returns a copy of the given context carrying the payload
+// of the token used on the request
+func WithPayload(ctx context.Context, payload *Payload) context.Context {
+	return context.WithValue(ctx, payloadKey{}, payload)
+}
+
+// PayloadFromContext returns the payload carried by the given context, if any
+func PayloadFromContext(ctx context.Context) (*Payload, bool) {
+	payload, ok := ctx.Value(payloadKey{}).(*Payload)
+	return payload, ok && payload != nil
+}
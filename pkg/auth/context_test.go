@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestPayloadFromContext(t *testing.T) {
+	payload := &Payload{UID: "uid"}
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		want   *Payload
+		wantOk bool
+	}{
+		{
+			name:   "context with payload",
+			ctx:    WithPayload(context.Background(), payload),
+			want:   payload,
+			wantOk: true,
+		},
+		{
+			name: "context without payload",
+			ctx:  context.Background(),
+		},
+		{
+			name: "context with nil payload",
+			ctx:  WithPayload(context.Background(), nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := PayloadFromContext(tt.ctx)
+			if ok != tt.wantOk {
+				t.Errorf("PayloadFromContext() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PayloadFromContext() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
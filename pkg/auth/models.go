@@ -16,7 +16,7 @@ type InitDO struct {
 	Key string
 }
 
-//Payload is information caried by a Inspr acceess token
+// Payload is information caried by a Inspr acceess token
 type Payload struct {
 	UID string `json:"uid"`
 	// Permissions is a map where key is the Scope and values are permissions
@@ -39,27 +39,40 @@ func (pld *Payload) ImportPermissionList(permissions []string, scope string) {
 
 // All Permissions possible values
 const (
-	CreateDapp    string = "create:dapp"
-	CreateChannel string = "create:channel"
-	CreateType    string = "create:type"
-	CreateAlias   string = "create:alias"
-	CreateBroker  string = "create:broker"
-
-	GetDapp    string = "get:dapp"
-	GetChannel string = "get:channel"
-	GetType    string = "get:type"
-	GetAlias   string = "get:alias"
-	GetBroker  string = "get:broker"
-
-	UpdateDapp    string = "update:dapp"
-	UpdateChannel string = "update:channel"
-	UpdateType    string = "update:type"
-	UpdateAlias   string = "update:alias"
-
-	DeleteDapp    string = "delete:dapp"
-	DeleteChannel string = "delete:channel"
-	DeleteType    string = "delete:type"
-	DeleteAlias   string = "delete:alias"
+	CreateDapp     string = "create:dapp"
+	CreateChannel  string = "create:channel"
+	CreateType     string = "create:type"
+	CreateAlias    string = "create:alias"
+	CreateTemplate string = "create:template"
+	CreateBroker   string = "create:broker"
+
+	GetDapp     string = "get:dapp"
+	GetChannel  string = "get:channel"
+	GetType     string = "get:type"
+	GetAlias    string = "get:alias"
+	GetTemplate string = "get:template"
+	GetBroker   string = "get:broker"
+
+	UpdateDapp     string = "update:dapp"
+	UpdateChannel  string = "update:channel"
+	UpdateType     string = "update:type"
+	UpdateAlias    string = "update:alias"
+	UpdateTemplate string = "update:template"
+
+	DeleteDapp     string = "delete:dapp"
+	DeleteChannel  string = "delete:channel"
+	DeleteType     string = "delete:type"
+	DeleteAlias    string = "delete:alias"
+	DeleteTemplate string = "delete:template"
+
+	GetRevision    string = "get:revision"
+	UpdateRevision string = "update:revision"
+
+	GetSnapshot    string = "get:snapshot"
+	UpdateSnapshot string = "update:snapshot"
+
+	GetStatus    string = "get:status"
+	UpdateStatus string = "update:status"
 
 	CreateToken string = "create:token"
 )
@@ -88,8 +101,22 @@ var AdminPermissions = map[string][]string{
 	GetType:    {""},
 	UpdateType: {""},
 
+	CreateTemplate: {""},
+	DeleteTemplate: {""},
+	GetTemplate:    {""},
+	UpdateTemplate: {""},
+
 	GetBroker:    nil,
 	CreateBroker: nil,
 
+	GetRevision:    {""},
+	UpdateRevision: {""},
+
+	GetSnapshot:    {""},
+	UpdateSnapshot: {""},
+
+	GetStatus:    {""},
+	UpdateStatus: {""},
+
 	CreateToken: nil,
 }
@@ -1,10 +1,12 @@
 package dappclient
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"strings"
@@ -13,11 +15,18 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"inspr.dev/inspr/pkg/environment"
+	"inspr.dev/inspr/pkg/ierrors"
 	"inspr.dev/inspr/pkg/logs"
+	"inspr.dev/inspr/pkg/meta"
 	"inspr.dev/inspr/pkg/rest"
 	"inspr.dev/inspr/pkg/rest/request"
 	"inspr.dev/inspr/pkg/sidecars/models"
+	"inspr.dev/inspr/pkg/sidecars/transport"
+	"inspr.dev/inspr/pkg/tracing"
 )
 
 var logger *zap.Logger
@@ -32,7 +41,28 @@ type Client struct {
 	client   *request.Client
 	mux      *http.ServeMux
 	readAddr string
-	metrics  map[string]routeMetric
+	// readSocket is the Unix socket the client is served on, when it shares
+	// one with the sidecar, instead of readAddr
+	readSocket string
+	metrics    map[string]routeMetric
+	// sidecar and routes reach the sidecar through gRPC, when it's the
+	// transport set for them, and are nil otherwise. The Node service is
+	// then served on grpcAddr
+	sidecar  *transport.Streamer
+	routes   transport.SidecarClient
+	grpcAddr string
+	// channels has the handlers of the channels, which handle the messages
+	// delivered through either transport
+	channels map[string]channelHandler
+	// shutdownTracing exports the remaining spans of the node
+	shutdownTracing func(context.Context) error
+}
+
+// channelHandler handles the messages of a channel, given in the format of
+// their HTTP bodies, and their batches, each message in its own body
+type channelHandler struct {
+	message func(body io.Reader) error
+	batch   func(msgs [][]byte) error
 }
 
 type routeMetric struct {
@@ -84,7 +114,7 @@ func NewAppClient() *Client {
 	logger.Info("got configuration from environment variables")
 	logger = logger.With(zap.String("read-address", readAddr), zap.String("write-address", writeAddr))
 
-	return &Client{
+	c := &Client{
 		readAddr: readAddr,
 		client: request.NewClient().
 			BaseURL(writeAddr).
@@ -94,39 +124,197 @@ func NewAppClient() *Client {
 		mux:     http.NewServeMux(),
 		metrics: make(map[string]routeMetric),
 	}
+	// the node and its sidecar communicate through the Unix sockets of the
+	// volume they share, when there's one, instead of localhost ports
+	socketDir := environment.GetUnixSocketAddr()
+	if socketDir != "" {
+		sidecarSocket := transport.SidecarSocket(socketDir)
+		c.readSocket = transport.NodeSocket(socketDir)
+		c.client = request.NewClient().
+			BaseURL("http://unix").
+			HTTPClient(*transport.UnixClient(sidecarSocket)).
+			Encoder(json.Marshal).
+			Decoder(request.JSONDecoderGenerator).
+			Pointer()
+		logger = logger.With(zap.String("read-socket", c.readSocket), zap.String("write-socket", sidecarSocket))
+	}
+
+	if transport.FromEnv() == meta.TransportGRPC {
+		c.grpcAddr = transport.NodeAddr(socketDir)
+		conn, err := transport.Dial(transport.SidecarAddr(socketDir))
+		if err != nil {
+			logger.Fatal("unable to connect to the sidecar through gRPC", zap.Error(err))
+		}
+		c.sidecar = transport.NewSidecarStreamer(conn)
+		c.routes = transport.NewSidecarClient(conn)
+	}
+	logger.Info("using sidecar transport", zap.String("transport", transport.FromEnv()))
+
+	shutdown, err := tracing.Init(context.Background(), os.Getenv("INSPR_APP_ID"))
+	if err != nil {
+		logger.Error("unable to set up tracing, spans won't be exported", zap.Error(err))
+	} else {
+		c.shutdownTracing = shutdown
+	}
+
+	return c
 }
 
-// WriteMessage receives a channel and a message and sends it in a request to the sidecar server
-func (c *Client) WriteMessage(ctx context.Context, channel string, msg interface{}) error {
+// WriteMessage receives a channel and a message and sends it in a request to
+// the sidecar server. The options set the headers and the key of the message.
+// The message carries the trace of ctx, which is continued by its handlers.
+func (c *Client) WriteMessage(ctx context.Context, channel string, msg interface{}, opts ...MessageOption) error {
 	l := logger.With(zap.String("operation", "write"), zap.String("channel", channel))
 	l.Info("received write message request")
+	ctx, span := startChannelSpan(ctx, "send", channel, trace.SpanKindProducer)
 	data := models.BrokerMessage{
-		Data: msg,
+		Data:     msg,
+		Envelope: newEnvelope(ctx, opts),
 	}
 
-	var resp interface{}
+	var err error
 	// sends a message to the corresponding channel route on the sidecar
 	l.Debug("sending message to load balancer")
-	err := c.client.Send(
-		ctx,
-		"/channel/"+channel,
-		http.MethodPost,
-		data,
-		&resp)
+	if c.sidecar != nil {
+		err = c.sidecar.Send(ctx, channel, false, []models.BrokerMessage{data})
+	} else {
+		var resp interface{}
+		err = c.client.Send(
+			ctx,
+			"/channel/"+channel,
+			http.MethodPost,
+			data,
+			&resp)
+	}
 	if err != nil {
 		l.Error("error sending message to load balancer")
 	} else {
 		l.Info("message sent")
 	}
+	tracing.End(span, err)
 	return err
 }
 
-// HandleChannel handles messages received in a given channel.
+// WriteMessages receives a channel and a batch of messages and sends them in a
+// single request to the sidecar server, which writes them to the broker at once.
+// An error means that some of the messages may not have been written, while the
+// others were, so retried batches can have duplicated messages.
+// The options set the headers and the key of every message of the batch.
+func (c *Client) WriteMessages(ctx context.Context, channel string, msgs []interface{}, opts ...MessageOption) error {
+	l := logger.With(zap.String("operation", "write"), zap.String("channel", channel))
+	l.Info("received write messages request", zap.Int("messages", len(msgs)))
+	ctx, span := startChannelSpan(ctx, "send batch", channel, trace.SpanKindProducer)
+	data := models.BrokerBatch{
+		Messages: make([]models.BrokerMessage, 0, len(msgs)),
+	}
+	for _, msg := range msgs {
+		data.Messages = append(data.Messages, models.BrokerMessage{
+			Data:     msg,
+			Envelope: newEnvelope(ctx, opts),
+		})
+	}
+
+	var err error
+	// sends the batch to the corresponding batch channel route on the sidecar
+	l.Debug("sending messages to load balancer")
+	if c.sidecar != nil {
+		err = c.sidecar.Send(ctx, channel, true, data.Messages)
+	} else {
+		var resp interface{}
+		err = c.client.Send(
+			ctx,
+			"/batch/channel/"+channel,
+			http.MethodPost,
+			data,
+			&resp)
+	}
+	if err != nil {
+		l.Error("error sending messages to load balancer")
+	} else {
+		l.Info("messages sent")
+	}
+	tracing.End(span, err)
+	return err
+}
+
+// HandleChannel handles messages received in a given channel. Messages
+// delivered in batches are given to the handler one at a time, in order.
+// The envelope of each message is in the context given to the handler, see
+// EnvelopeFromContext, as is the span that continues the trace of the message.
 func (c *Client) HandleChannel(channel string, handler func(ctx context.Context, body io.Reader) error) {
+	c.handleChannel(channel, channelHandler{
+		message: func(body io.Reader) error {
+			return handleMessage(context.Background(), channel, body, handler)
+		},
+		batch: func(msgs [][]byte) error {
+			return handleMessages(channel, msgs, func(ctx context.Context, bodies []io.Reader) error {
+				for _, body := range bodies {
+					if err := handleMessage(ctx, channel, body, handler); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		},
+	})
+}
+
+// HandleChannelBatch handles the batches of messages received in a given
+// channel. Messages that aren't delivered in a batch are given to the handler
+// in a batch of their own. Each body carries the envelope of its message, in
+// its "envelope" field.
+func (c *Client) HandleChannelBatch(channel string, handler func(ctx context.Context, bodies []io.Reader) error) {
+	c.handleChannel(channel, channelHandler{
+		message: func(body io.Reader) error {
+			buf, err := ioutil.ReadAll(body)
+			if err != nil {
+				return ierrors.New(err).BadRequest()
+			}
+			return handleMessages(channel, [][]byte{buf}, handler)
+		},
+		batch: func(msgs [][]byte) error {
+			return handleMessages(channel, msgs, handler)
+		},
+	})
+}
+
+// handleChannel registers the handler of a channel and the routes the
+// sidecar delivers its messages and batches to
+func (c *Client) handleChannel(channel string, handler channelHandler) {
+	if c.channels == nil {
+		c.channels = make(map[string]channelHandler)
+	}
+	c.channels[channel] = handler
+
 	c.mux.HandleFunc("/channel/"+channel, func(w http.ResponseWriter, r *http.Request) {
 		logger.Info("received request on client handle channel", zap.String("channel", channel))
 		// user defined handler. Returns error if the user wants to return it
-		err := handler(context.Background(), r.Body)
+		err := handler.message(r.Body)
+		if err != nil {
+			logger.Error("error returned by client handler", zap.Error(err))
+			rest.ERROR(w, err)
+			return
+		}
+		rest.JSON(w, 200, nil)
+	})
+
+	c.mux.HandleFunc("/batch/channel/"+channel, func(w http.ResponseWriter, r *http.Request) {
+		logger.Info("received batch on client handle channel", zap.String("channel", channel))
+		var batch struct {
+			Messages []json.RawMessage `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			logger.Error("unable to decode batch", zap.Error(err))
+			rest.ERROR(w, ierrors.New("invalid batch for channel '%s'", channel).BadRequest())
+			return
+		}
+
+		msgs := make([][]byte, 0, len(batch.Messages))
+		for _, msg := range batch.Messages {
+			msgs = append(msgs, msg)
+		}
+		// user defined handler. Returns error if the user wants to return it
+		err := handler.batch(msgs)
 		if err != nil {
 			logger.Error("error returned by client handler", zap.Error(err))
 			rest.ERROR(w, err)
@@ -136,29 +324,74 @@ func (c *Client) HandleChannel(channel string, handler func(ctx context.Context,
 	})
 }
 
-// HandleRoute handles messages received in a given route.
+// handleStream handles a message, or a batch of messages, delivered by the
+// sidecar through gRPC with the handler of its channel. Each message is
+// given to it in the same JSON body it has when delivered through HTTP
+func (c *Client) handleStream(ctx context.Context, channel string, batch bool, msgs []models.BrokerMessage) error {
+	logger.Info("received message through gRPC", zap.String("channel", channel), zap.Bool("batch", batch))
+	handler, ok := c.channels[channel]
+	if !ok {
+		return ierrors.New("channel '%s' isn't handled by the node", channel).NotFound()
+	}
+
+	bodies := make([][]byte, 0, len(msgs))
+	for _, msg := range msgs {
+		body, err := json.Marshal(msg)
+		if err != nil {
+			return ierrors.New(err).BadRequest()
+		}
+		bodies = append(bodies, body)
+	}
+
+	var err error
+	if batch {
+		err = handler.batch(bodies)
+	} else if len(bodies) == 1 {
+		err = handler.message(bytes.NewReader(bodies[0]))
+	} else {
+		err = ierrors.New("invalid message for channel '%s'", channel).BadRequest()
+	}
+	if err != nil {
+		logger.Error("error returned by client handler", zap.Error(err))
+	}
+	return err
+}
+
+// HandleRoute handles messages received in a given route. The context of the
+// request has the span that continues the trace of its sender.
 func (c *Client) HandleRoute(path string, handler func(w http.ResponseWriter, r *http.Request)) {
 	path = strings.TrimPrefix(path, "/")
 	c.mux.HandleFunc("/route/"+path, func(w http.ResponseWriter, r *http.Request) {
 		logger.Info("received request on client handle route", zap.String("route", path))
-		handler(w, r)
+		ctx := tracing.ExtractHeader(r.Context(), r.Header)
+		ctx, span := startRouteSpan(ctx, "handle route", path, trace.SpanKindServer)
+		defer span.End()
+		handler(w, r.WithContext(ctx))
 	})
 }
 
-// SendRequest receives the http request informations and send it to the sidecar server
+// SendRequest receives the http request informations and send it to the sidecar server.
+// The request carries the trace of ctx, which is continued by the route's handler.
 func (c *Client) SendRequest(ctx context.Context, nodeName, path, method string, body interface{}, responsePtr interface{}) error {
 	l := logger.With(zap.String("operation", "sendRequest"), zap.String("route", nodeName))
 
 	start := time.Now()
+	ctx, span := startRouteSpan(ctx, "request", nodeName, trace.SpanKindClient)
 
 	// sends a message to the corresponding route on the sidecar
 	l.Debug("sending message to load balancer")
-	err := c.client.Send(
-		ctx,
-		fmt.Sprintf("/route/%s/%s", nodeName, path),
-		method,
-		body,
-		responsePtr)
+	var err error
+	if c.routes != nil {
+		err = c.sendRouteRequest(ctx, nodeName, path, method, body, responsePtr)
+	} else {
+		err = c.client.Send(
+			ctx,
+			fmt.Sprintf("/route/%s/%s", nodeName, path),
+			method,
+			body,
+			responsePtr)
+	}
+	tracing.End(span, err)
 	if err != nil {
 		l.Error("error sending request to load balancer", zap.Error(err))
 		c.GetRouteMetric(nodeName).routeSendError.Inc()
@@ -173,6 +406,43 @@ func (c *Client) SendRequest(ctx context.Context, nodeName, path, method string,
 	return err
 }
 
+// sendRouteRequest sends a request to a route through the gRPC service of the
+// sidecar, decoding its response as the HTTP client does
+func (c *Client) sendRouteRequest(ctx context.Context, nodeName, path, method string, body interface{}, responsePtr interface{}) error {
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return ierrors.Wrap(ierrors.New(err).BadRequest(), "error encoding body to json")
+	}
+
+	header := map[string]string{"Content-Type": "application/json"}
+	for key, value := range tracing.Inject(ctx) {
+		header[key] = value
+	}
+
+	resp, err := c.routes.SendRequest(ctx, &transport.RouteRequest{
+		Route:  nodeName,
+		Path:   path,
+		Method: method,
+		Header: header,
+		Body:   buf,
+	})
+	if err != nil {
+		return ierrors.New(err).BadRequest()
+	}
+
+	if resp.GetStatus() != http.StatusOK {
+		return rest.UnmarshalERROR(bytes.NewReader(resp.GetBody()))
+	}
+
+	if responsePtr != nil {
+		err = json.Unmarshal(resp.GetBody(), responsePtr)
+		if err != nil && len(bytes.TrimSpace(resp.GetBody())) == 0 {
+			return nil
+		}
+	}
+	return err
+}
+
 //Run runs the server with the handlers defined in HandleChannel
 func (c *Client) Run(ctx context.Context) error {
 
@@ -198,11 +468,23 @@ func (c *Client) Run(ctx context.Context) error {
 	}()
 
 	go func() {
-		if err = server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err = c.serve(&server); err != nil && err != http.ErrServerClosed {
 			logger.Fatal("error serving dApp", zap.Error(err))
 		}
 	}()
 
+	// the sidecar reaches the gRPC service of the node on a listener of its own
+	if c.grpcAddr != "" {
+		grpcServer := grpc.NewServer()
+		transport.RegisterNodeServer(grpcServer, transport.NewNodeServer(c.handleStream, c.mux))
+		defer grpcServer.Stop()
+		go func() {
+			if err := c.serveGRPC(grpcServer); err != nil {
+				logger.Fatal("error serving dApp through gRPC", zap.Error(err))
+			}
+		}()
+	}
+
 	logger.Info("inspr client server is running", zap.String("log-level", alevel.String()))
 
 	<-ctx.Done()
@@ -219,9 +501,38 @@ func (c *Client) Run(ctx context.Context) error {
 		logger.Fatal("error in server shitting down", zap.Error(err))
 	}
 
+	if c.shutdownTracing != nil {
+		if err := c.shutdownTracing(ctxShutdown); err != nil {
+			logger.Error("unable to export the remaining spans", zap.Error(err))
+		}
+	}
+
 	// has to be the last method called in the shutdown
 	if err = server.Shutdown(ctxShutdown); err != nil {
 		return err
 	}
 	return ctx.Err()
 }
+
+// serve serves the client on its Unix socket, when it shares one with the
+// sidecar, or on its read address otherwise
+func (c *Client) serve(server *http.Server) error {
+	if c.readSocket == "" {
+		return server.ListenAndServe()
+	}
+
+	listener, err := transport.ListenUnix(c.readSocket)
+	if err != nil {
+		return err
+	}
+	return server.Serve(listener)
+}
+
+// serveGRPC serves the Node service on its own address
+func (c *Client) serveGRPC(server *grpc.Server) error {
+	listener, err := transport.Listen(c.grpcAddr)
+	if err != nil {
+		return err
+	}
+	return server.Serve(listener)
+}
@@ -15,10 +15,13 @@ import (
 	"testing"
 	"time"
 
+	"google.golang.org/grpc"
 	"inspr.dev/inspr/pkg/environment"
 	"inspr.dev/inspr/pkg/ierrors"
 	"inspr.dev/inspr/pkg/rest"
 	"inspr.dev/inspr/pkg/rest/request"
+	"inspr.dev/inspr/pkg/sidecars/models"
+	"inspr.dev/inspr/pkg/sidecars/transport"
 )
 
 func mockHTTPClient(addr string) *http.Client {
@@ -205,6 +208,65 @@ func TestClient_WriteMessage(t *testing.T) {
 	}
 }
 
+func TestClient_WriteMessages(t *testing.T) {
+	tests := []struct {
+		name            string
+		msgs            []interface{}
+		wantErr         bool
+		interruptServer bool
+	}{
+		{
+			name: "Valid request",
+			msgs: []interface{}{"first", "second"},
+		},
+		{
+			name:            "Invalid request - server died",
+			msgs:            []interface{}{"first"},
+			wantErr:         true,
+			interruptServer: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/batch/channel/chan1" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				var batch models.BrokerBatch
+				json.NewDecoder(r.Body).Decode(&batch)
+
+				got := []interface{}{}
+				for _, msg := range batch.Messages {
+					got = append(got, msg.Data)
+				}
+				if !reflect.DeepEqual(got, tt.msgs) {
+					t.Errorf("Client.WriteMessages() sent = %v, want %v", got, tt.msgs)
+				}
+				rest.JSON(w, http.StatusOK, nil)
+			}))
+			defer s.Close()
+			c := Client{
+				client: request.NewClient().
+					BaseURL(s.URL).
+					HTTPClient(*http.DefaultClient).
+					Encoder(json.Marshal).
+					Decoder(request.JSONDecoderGenerator).
+					Pointer(),
+			}
+
+			if tt.interruptServer {
+				s.Close()
+			}
+
+			err := c.WriteMessages(context.Background(), "chan1", tt.msgs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.WriteMessages() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestClient_HandleChannel(t *testing.T) {
 	type fields struct {
 	}
@@ -296,6 +358,131 @@ func TestClient_HandleChannel(t *testing.T) {
 	}
 }
 
+func TestClient_HandleChannelBatch(t *testing.T) {
+	decode := func(bodies []io.Reader) ([]string, error) {
+		messages := []string{}
+		for _, body := range bodies {
+			message := struct {
+				Data string `json:"data"`
+			}{}
+			if err := json.NewDecoder(body).Decode(&message); err != nil {
+				return nil, err
+			}
+			messages = append(messages, message.Data)
+		}
+		return messages, nil
+	}
+
+	tests := []struct {
+		name         string
+		register     func(c *Client, received *[]string)
+		path         string
+		body         interface{}
+		wantErr      bool
+		wantReceived []string
+	}{
+		{
+			name: "batch given to the batch handler",
+			register: func(c *Client, received *[]string) {
+				c.HandleChannelBatch("channel", func(ctx context.Context, bodies []io.Reader) error {
+					messages, err := decode(bodies)
+					*received = append(*received, messages...)
+					return err
+				})
+			},
+			path: "/batch/channel/channel",
+			body: models.BrokerBatch{Messages: []models.BrokerMessage{
+				{Data: "first"}, {Data: "second"},
+			}},
+			wantReceived: []string{"first", "second"},
+		},
+		{
+			name: "single message given to the batch handler",
+			register: func(c *Client, received *[]string) {
+				c.HandleChannelBatch("channel", func(ctx context.Context, bodies []io.Reader) error {
+					messages, err := decode(bodies)
+					*received = append(*received, messages...)
+					return err
+				})
+			},
+			path:         "/channel/channel",
+			body:         models.BrokerMessage{Data: "first"},
+			wantReceived: []string{"first"},
+		},
+		{
+			name: "batch given to the channel handler one message at a time",
+			register: func(c *Client, received *[]string) {
+				c.HandleChannel("channel", func(ctx context.Context, body io.Reader) error {
+					messages, err := decode([]io.Reader{body})
+					*received = append(*received, messages...)
+					return err
+				})
+			},
+			path: "/batch/channel/channel",
+			body: models.BrokerBatch{Messages: []models.BrokerMessage{
+				{Data: "first"}, {Data: "second"}, {Data: "third"},
+			}},
+			wantReceived: []string{"first", "second", "third"},
+		},
+		{
+			name: "error on channel handler stops the batch",
+			register: func(c *Client, received *[]string) {
+				c.HandleChannel("channel", func(ctx context.Context, body io.Reader) error {
+					messages, _ := decode([]io.Reader{body})
+					*received = append(*received, messages...)
+					return errors.New("Error")
+				})
+			},
+			path: "/batch/channel/channel",
+			body: models.BrokerBatch{Messages: []models.BrokerMessage{
+				{Data: "first"}, {Data: "second"},
+			}},
+			wantErr:      true,
+			wantReceived: []string{"first"},
+		},
+		{
+			name: "invalid batch",
+			register: func(c *Client, received *[]string) {
+				c.HandleChannelBatch("channel", func(ctx context.Context, bodies []io.Reader) error {
+					return nil
+				})
+			},
+			path:         "/batch/channel/channel",
+			body:         "not a batch",
+			wantErr:      true,
+			wantReceived: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{
+				mux: http.NewServeMux(),
+			}
+			received := []string{}
+			tt.register(c, &received)
+			s := httptest.NewServer(c.mux)
+			defer s.Close()
+			client := request.NewJSONClient(s.URL)
+			response := struct {
+				Status string `json:"status"`
+			}{}
+			err := client.Send(
+				context.Background(),
+				tt.path,
+				http.MethodPost,
+				tt.body,
+				&response)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client_HandleChannelBatch error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(received, tt.wantReceived) {
+				t.Errorf("Client_HandleChannelBatch received = %v, want %v", received, tt.wantReceived)
+			}
+		})
+	}
+}
+
 func TestClient_HandleRoute(t *testing.T) {
 	type args struct {
 		path    string
@@ -555,3 +742,241 @@ func createMockedLBsidecar(port, nodeName, path, method, want string, body inter
 	return mockServer
 
 }
+
+// mockGRPCSidecar serves the Sidecar service, handling the messages written
+// with the handler and the route requests with the routes
+type mockGRPCSidecar struct {
+	transport.UnimplementedSidecarServer
+	handle transport.MessageHandler
+	routes http.Handler
+}
+
+func (s *mockGRPCSidecar) WriteMessage(stream transport.Sidecar_WriteMessageServer) error {
+	return transport.ServeStream(stream, s.handle)
+}
+
+func (s *mockGRPCSidecar) SendRequest(ctx context.Context, req *transport.RouteRequest) (*transport.RouteResponse, error) {
+	return transport.ServeRoute(ctx, s.routes, req), nil
+}
+
+// serveGRPC serves the gRPC server on a Unix socket of its own and returns a
+// connection to it
+func serveGRPC(t *testing.T, server *grpc.Server) *grpc.ClientConn {
+	addr := transport.NodeAddr(t.TempDir())
+	listener, err := transport.Listen(addr)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := transport.Dial(addr)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestClient_grpcTransport(t *testing.T) {
+	var written []interface{}
+	handle := func(ctx context.Context, channel string, batch bool, msgs []models.BrokerMessage) error {
+		if channel != "chan1" {
+			return ierrors.New("channel '%s' not found", channel).BadRequest()
+		}
+		for _, msg := range msgs {
+			written = append(written, msg.Data)
+		}
+		return nil
+	}
+	routes := http.NewServeMux()
+	routes.HandleFunc("/route/grpcrt/hello", func(w http.ResponseWriter, r *http.Request) {
+		var msg string
+		json.NewDecoder(r.Body).Decode(&msg)
+		rest.JSON(w, http.StatusOK, msg+" world")
+	})
+
+	server := grpc.NewServer()
+	transport.RegisterSidecarServer(server, &mockGRPCSidecar{handle: handle, routes: routes})
+	conn := serveGRPC(t, server)
+
+	c := &Client{
+		sidecar: transport.NewSidecarStreamer(conn),
+		routes:  transport.NewSidecarClient(conn),
+		metrics: make(map[string]routeMetric),
+	}
+	defer c.sidecar.Close()
+	ctx := context.Background()
+
+	t.Run("messages written through the stream", func(t *testing.T) {
+		written = nil
+		if err := c.WriteMessage(ctx, "chan1", "first"); err != nil {
+			t.Errorf("Client.WriteMessage() error = %v", err)
+		}
+		if err := c.WriteMessages(ctx, "chan1", []interface{}{"second", "third"}); err != nil {
+			t.Errorf("Client.WriteMessages() error = %v", err)
+		}
+		want := []interface{}{"first", "second", "third"}
+		if !reflect.DeepEqual(written, want) {
+			t.Errorf("Client wrote %v, want %v", written, want)
+		}
+	})
+
+	t.Run("error of the sidecar", func(t *testing.T) {
+		err := c.WriteMessage(ctx, "invalid", "first")
+		if !ierrors.HasCode(err, ierrors.BadRequest) {
+			t.Errorf("Client.WriteMessage() error = %v, want a bad request", err)
+		}
+	})
+
+	t.Run("route request sent through gRPC", func(t *testing.T) {
+		var resp string
+		err := c.SendRequest(ctx, "grpcrt", "hello", http.MethodPost, "hello", &resp)
+		if err != nil {
+			t.Errorf("Client.SendRequest() error = %v", err)
+		}
+		if resp != "hello world" {
+			t.Errorf("Client.SendRequest() = %v, want %v", resp, "hello world")
+		}
+	})
+}
+
+func TestClient_handleStream(t *testing.T) {
+	c := &Client{mux: http.NewServeMux()}
+	var received []models.BrokerMessage
+	c.HandleChannel("chan1", func(ctx context.Context, body io.Reader) error {
+		var msg models.BrokerMessage
+		if err := json.NewDecoder(body).Decode(&msg); err != nil {
+			return err
+		}
+		if envelope, ok := EnvelopeFromContext(ctx); !ok || envelope.Attempt != msg.Envelope.Attempt {
+			t.Errorf("Client.HandleChannel() envelope = %v, want %v", envelope, msg.Envelope)
+		}
+		received = append(received, msg)
+		return nil
+	})
+	c.HandleChannel("invalid", func(ctx context.Context, body io.Reader) error {
+		return ierrors.New("invalid message").BadRequest()
+	})
+
+	server := grpc.NewServer()
+	transport.RegisterNodeServer(server, transport.NewNodeServer(c.handleStream, c.mux))
+	streamer := transport.NewNodeStreamer(serveGRPC(t, server))
+	defer streamer.Close()
+
+	tests := []struct {
+		name     string
+		channel  string
+		batch    bool
+		msgs     []models.BrokerMessage
+		wantCode ierrors.ErrCode
+		want     []interface{}
+	}{
+		{
+			name:    "message given to the handler of its channel",
+			channel: "chan1",
+			msgs: []models.BrokerMessage{
+				{Data: "first", Envelope: &models.Envelope{Attempt: 1}},
+			},
+			want: []interface{}{"first"},
+		},
+		{
+			name:    "batch given to the handler one message at a time",
+			channel: "chan1",
+			batch:   true,
+			msgs: []models.BrokerMessage{
+				{Data: "second", Envelope: &models.Envelope{Attempt: 2}},
+				{Data: "third", Envelope: &models.Envelope{Attempt: 2}},
+			},
+			want: []interface{}{"second", "third"},
+		},
+		{
+			name:     "error of the handler",
+			channel:  "invalid",
+			msgs:     []models.BrokerMessage{{Data: "first"}},
+			wantCode: ierrors.BadRequest,
+		},
+		{
+			name:     "channel without a handler",
+			channel:  "chan2",
+			msgs:     []models.BrokerMessage{{Data: "first"}},
+			wantCode: ierrors.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received = nil
+			err := streamer.Send(context.Background(), tt.channel, tt.batch, tt.msgs)
+			if (err != nil) != (tt.wantCode != 0) || (err != nil && !ierrors.HasCode(err, tt.wantCode)) {
+				t.Fatalf("Client.handleStream() error = %v, want code %v", err, tt.wantCode)
+			}
+
+			var got []interface{}
+			for _, msg := range received {
+				got = append(got, msg.Data)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.handleStream() handled %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_unixSocket(t *testing.T) {
+	dir := t.TempDir()
+	os.Setenv("INSPR_UNIX_SOCKET", dir)
+	defer os.Unsetenv("INSPR_UNIX_SOCKET")
+
+	var written []interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/channel/chan1", func(w http.ResponseWriter, r *http.Request) {
+		var msg models.BrokerMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+		written = append(written, msg.Data)
+		rest.JSON(w, http.StatusOK, nil)
+	})
+	listener, err := transport.ListenUnix(transport.SidecarSocket(dir))
+	if err != nil {
+		t.Fatalf("ListenUnix() error = %v", err)
+	}
+	sidecar := &http.Server{Handler: mux}
+	go sidecar.Serve(listener)
+	defer sidecar.Close()
+
+	c := NewAppClient()
+
+	t.Run("message written through the socket", func(t *testing.T) {
+		if err := c.WriteMessage(context.Background(), "chan1", "hello"); err != nil {
+			t.Errorf("Client.WriteMessage() error = %v", err)
+		}
+		if !reflect.DeepEqual(written, []interface{}{"hello"}) {
+			t.Errorf("Client wrote %v, want %v", written, []interface{}{"hello"})
+		}
+	})
+
+	t.Run("client served on the socket", func(t *testing.T) {
+		c.HandleChannel("chan1", func(ctx context.Context, body io.Reader) error {
+			return nil
+		})
+		server := &http.Server{Handler: c.mux}
+		go c.serve(server)
+		defer server.Close()
+
+		client := transport.UnixClient(transport.NodeSocket(dir))
+		var resp *http.Response
+		// waits for the client to listen on the socket
+		for i := 0; i < 50; i++ {
+			if resp, err = client.Post("http://unix/channel/chan1", "application/json", nil); err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if err != nil {
+			t.Fatalf("Client.serve() error = %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Client.serve() status = %v, want %v", resp.StatusCode, http.StatusOK)
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package dappclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	"go.opentelemetry.io/otel/trace"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/sidecars/models"
+	"inspr.dev/inspr/pkg/tracing"
+)
+
+// MessageOption sets the envelope of the messages written by the client
+type MessageOption func(envelope *models.Envelope)
+
+// WithHeader sets a header of the messages written, such as a correlation ID
+// or the content type of their data. Headers starting with "inspr-" are
+// reserved and the sidecar refuses the messages that have them.
+func WithHeader(key, value string) MessageOption {
+	return func(envelope *models.Envelope) {
+		if envelope.Headers == nil {
+			envelope.Headers = make(map[string]string)
+		}
+		envelope.Headers[key] = value
+	}
+}
+
+// WithKey sets the key of the messages written, which identifies what they
+// are about, such as the entity they change
+func WithKey(key string) MessageOption {
+	return func(envelope *models.Envelope) {
+		envelope.Key = key
+	}
+}
+
+// newEnvelope returns the envelope set by the options, carrying the trace
+// context of ctx, or nil when there's neither
+func newEnvelope(ctx context.Context, opts []MessageOption) *models.Envelope {
+	trace := tracing.Inject(ctx)
+	if len(opts) == 0 && trace == nil {
+		return nil
+	}
+	envelope := &models.Envelope{Trace: trace}
+	for _, opt := range opts {
+		opt(envelope)
+	}
+	return envelope
+}
+
+type envelopeKey struct{}
+
+// EnvelopeFromContext returns the envelope of the message given to a channel
+// handler, with its headers, key, produce time, source dApp and the number of
+// the delivery attempt. It returns false when the context carries none.
+func EnvelopeFromContext(ctx context.Context) (models.Envelope, bool) {
+	envelope, ok := ctx.Value(envelopeKey{}).(models.Envelope)
+	return envelope, ok
+}
+
+// handleMessage gives a message delivered by the sidecar to the handler of its
+// channel, with its envelope in the context, in a span that continues the
+// trace of the message
+func handleMessage(
+	ctx context.Context,
+	channel string,
+	body io.Reader,
+	handler func(ctx context.Context, body io.Reader) error,
+) error {
+	buf, err := ioutil.ReadAll(body)
+	if err != nil {
+		return ierrors.New(err).BadRequest()
+	}
+
+	if envelope := envelopeOf(buf); envelope != nil {
+		ctx = context.WithValue(ctx, envelopeKey{}, *envelope)
+		ctx = tracing.Extract(ctx, envelope.Trace)
+	}
+
+	ctx, span := startChannelSpan(ctx, "handle", channel, trace.SpanKindConsumer)
+	err = handler(ctx, bytes.NewReader(buf))
+	tracing.End(span, err)
+	return err
+}
+
+// handleMessages gives a batch of messages delivered by the sidecar to the
+// handler of its channel, in a span that continues the trace of the batch
+func handleMessages(
+	channel string,
+	msgs [][]byte,
+	handler func(ctx context.Context, bodies []io.Reader) error,
+) error {
+	ctx := context.Background()
+	bodies := make([]io.Reader, 0, len(msgs))
+	for i, msg := range msgs {
+		// every message of a batch carries the trace of its delivery
+		if envelope := envelopeOf(msg); i == 0 && envelope != nil {
+			ctx = tracing.Extract(ctx, envelope.Trace)
+		}
+		bodies = append(bodies, bytes.NewReader(msg))
+	}
+
+	ctx, span := startChannelSpan(ctx, "handle batch", channel, trace.SpanKindConsumer)
+	err := handler(ctx, bodies)
+	tracing.End(span, err)
+	return err
+}
+
+// envelopeOf returns the envelope of an encoded message, or nil when it has none
+func envelopeOf(buf []byte) *models.Envelope {
+	var msg struct {
+		Envelope *models.Envelope `json:"envelope"`
+	}
+	if err := json.Unmarshal(buf, &msg); err != nil {
+		return nil
+	}
+	return msg.Envelope
+}
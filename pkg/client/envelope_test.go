@@ -0,0 +1,147 @@
+package dappclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"inspr.dev/inspr/pkg/rest"
+	"inspr.dev/inspr/pkg/rest/request"
+	"inspr.dev/inspr/pkg/sidecars/models"
+)
+
+func TestClient_WriteMessage_envelope(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []MessageOption
+		want *models.Envelope
+	}{
+		{
+			name: "message without options",
+			want: &models.Envelope{},
+		},
+		{
+			name: "message with headers and key",
+			opts: []MessageOption{
+				WithHeader("correlation-id", "42"),
+				WithHeader("content-type", "text/plain"),
+				WithKey("order-1"),
+			},
+			want: &models.Envelope{
+				Headers: map[string]string{"correlation-id": "42", "content-type": "text/plain"},
+				Key:     "order-1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []*models.Envelope
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var batch models.BrokerBatch
+				if r.URL.Path == "/channel/chan1" {
+					var msg models.BrokerMessage
+					json.NewDecoder(r.Body).Decode(&msg)
+					batch.Messages = append(batch.Messages, msg)
+				} else {
+					json.NewDecoder(r.Body).Decode(&batch)
+				}
+				for _, msg := range batch.Messages {
+					// every message carries the trace of its write
+					if msg.Envelope == nil || msg.Envelope.Trace["traceparent"] == "" {
+						t.Errorf("Client sent a message without trace context: %+v", msg.Envelope)
+						continue
+					}
+					msg.Envelope.Trace = nil
+					got = append(got, msg.Envelope)
+				}
+				rest.JSON(w, http.StatusOK, nil)
+			}))
+			defer s.Close()
+			c := Client{client: request.NewJSONClient(s.URL)}
+
+			if err := c.WriteMessage(context.Background(), "chan1", "order", tt.opts...); err != nil {
+				t.Fatalf("Client.WriteMessage() error = %v", err)
+			}
+			if err := c.WriteMessages(context.Background(), "chan1", []interface{}{"first", "second"}, tt.opts...); err != nil {
+				t.Fatalf("Client.WriteMessages() error = %v", err)
+			}
+
+			want := []*models.Envelope{tt.want, tt.want, tt.want}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Client sent envelopes %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestClient_HandleChannel_envelope(t *testing.T) {
+	envelope := models.Envelope{
+		Headers:    map[string]string{"correlation-id": "42"},
+		Key:        "order-1",
+		ProducedAt: time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC),
+		SourceApp:  "app1-orders",
+		Offset:     7,
+		Attempt:    2,
+	}
+
+	var got []models.Envelope
+	c := &Client{mux: http.NewServeMux()}
+	c.HandleChannel("orders", func(ctx context.Context, body io.Reader) error {
+		var msg models.BrokerMessage
+		if err := json.NewDecoder(body).Decode(&msg); err != nil || msg.Data != "order" {
+			t.Errorf("Client.HandleChannel() body = %+v, err = %v", msg, err)
+		}
+		if envelope, ok := EnvelopeFromContext(ctx); ok {
+			got = append(got, envelope)
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name string
+		path string
+		body interface{}
+		want []models.Envelope
+	}{
+		{
+			name: "message with envelope",
+			path: "/channel/orders",
+			body: models.BrokerMessage{Data: "order", Envelope: &envelope},
+			want: []models.Envelope{envelope},
+		},
+		{
+			name: "message without envelope",
+			path: "/channel/orders",
+			body: models.BrokerMessage{Data: "order"},
+		},
+		{
+			name: "batch with an envelope per message",
+			path: "/batch/channel/orders",
+			body: models.BrokerBatch{Messages: []models.BrokerMessage{
+				{Data: "order", Envelope: &envelope},
+				{Data: "order", Envelope: &models.Envelope{Key: "order-2"}},
+			}},
+			want: []models.Envelope{envelope, {Key: "order-2"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			body, _ := json.Marshal(tt.body)
+			w := httptest.NewRecorder()
+			c.mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.path, bytes.NewReader(body)))
+			if w.Code != http.StatusOK {
+				t.Fatalf("Client.HandleChannel() status = %v", w.Code)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EnvelopeFromContext() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
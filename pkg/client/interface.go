@@ -6,7 +6,8 @@ import (
 
 // AppClient defines an interface and its methods for a dApp Client
 type AppClient interface {
-	WriteMessage(ctx context.Context, channel string, msg interface{}) error
+	WriteMessage(ctx context.Context, channel string, msg interface{}, opts ...MessageOption) error
+	WriteMessages(ctx context.Context, channel string, msgs []interface{}, opts ...MessageOption) error
 	ReadMessage(ctx context.Context, channel string, message interface{}) error
 	CommitMessage(ctx context.Context, channel string) error
 }
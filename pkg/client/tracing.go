@@ -0,0 +1,31 @@
+package dappclient
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+	"inspr.dev/inspr/pkg/tracing"
+)
+
+// startChannelSpan starts the span of the node writing or handling messages
+// of a channel
+func startChannelSpan(ctx context.Context, name, channel string, kind trace.SpanKind) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(
+		ctx,
+		name+" "+channel,
+		trace.WithSpanKind(kind),
+		trace.WithAttributes(semconv.MessagingDestinationKey.String(channel)),
+	)
+}
+
+// startRouteSpan starts the span of the node sending or handling requests of
+// a route
+func startRouteSpan(ctx context.Context, name, route string, kind trace.SpanKind) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(
+		ctx,
+		name+" "+route,
+		trace.WithSpanKind(kind),
+		trace.WithAttributes(semconv.HTTPRouteKey.String(route)),
+	)
+}
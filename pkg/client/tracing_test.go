@@ -0,0 +1,84 @@
+package dappclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"inspr.dev/inspr/pkg/sidecars/models"
+	"inspr.dev/inspr/pkg/tracing"
+)
+
+func TestClient_HandleChannel_trace(t *testing.T) {
+	ctx, span := tracing.Tracer().Start(context.Background(), "deliver")
+	span.End()
+	fields := tracing.Inject(ctx)
+
+	var got []sdktrace.ReadOnlySpan
+	c := &Client{mux: http.NewServeMux()}
+	c.HandleChannel("traced", func(ctx context.Context, body io.Reader) error {
+		got = append(got, trace.SpanFromContext(ctx).(sdktrace.ReadOnlySpan))
+		return nil
+	})
+
+	tests := []struct {
+		name string
+		path string
+		body interface{}
+	}{
+		{
+			name: "message",
+			path: "/channel/traced",
+			body: models.BrokerMessage{Data: "order", Envelope: &models.Envelope{Trace: fields}},
+		},
+		{
+			name: "batch",
+			path: "/batch/channel/traced",
+			body: models.BrokerBatch{Messages: []models.BrokerMessage{
+				{Data: "order", Envelope: &models.Envelope{Trace: fields}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			body, _ := json.Marshal(tt.body)
+			w := httptest.NewRecorder()
+			c.mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.path, bytes.NewReader(body)))
+			if w.Code != http.StatusOK || len(got) != 1 {
+				t.Fatalf("Client.HandleChannel() status = %v, spans = %v", w.Code, got)
+			}
+
+			// the handler's span continues the trace of the delivery
+			if got[0].Name() != "handle traced" || got[0].SpanContext().TraceID() != span.SpanContext().TraceID() {
+				t.Errorf("Client.HandleChannel() span = %v %v, want trace %v",
+					got[0].Name(), got[0].SpanContext().TraceID(), span.SpanContext().TraceID())
+			}
+		})
+	}
+}
+
+func TestClient_HandleRoute_trace(t *testing.T) {
+	ctx, span := tracing.Tracer().Start(context.Background(), "route")
+	span.End()
+
+	var got trace.SpanContext
+	c := &Client{mux: http.NewServeMux()}
+	c.HandleRoute("/traced", func(w http.ResponseWriter, r *http.Request) {
+		got = trace.SpanContextFromContext(r.Context())
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/route/traced", nil)
+	tracing.InjectHeader(ctx, r.Header)
+	c.mux.ServeHTTP(httptest.NewRecorder(), r)
+
+	if got.TraceID() != span.SpanContext().TraceID() || got.SpanID() == span.SpanContext().SpanID() {
+		t.Errorf("Client.HandleRoute() span = %v, want a span of trace %v", got, span.SpanContext().TraceID())
+	}
+}
@@ -13,6 +13,20 @@ var InsprOptions struct {
 	DryRun bool
 	// Update defines if Apply is going to create a new app or update an existing one
 	Update bool
+	// Force defines if Apply is going to overwrite components regardless of their resource versions
+	Force bool
+	// Selector filters the components listed by Get by their annotations
+	Selector string
+	// ExportFolder receives the folder to which Export writes the snapshot
+	ExportFolder string
+	// Reconcile defines if Status is going to reconcile the runtime instead of reporting the last reconciliation
+	Reconcile bool
+	// Repair defines if the drifts found when reconciling the runtime are going to be repaired
+	Repair bool
+	// GraphFormat receives the format in which Graph renders the data flow of a scope
+	GraphFormat string
+	// DeadLetterLimit receives the number of messages of a dead-letter channel DeadLetters looks at
+	DeadLetterLimit int
 
 	Token string
 
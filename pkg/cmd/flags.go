@@ -52,7 +52,7 @@ var flagRegistry = []Flag{
 		DefValue:      false,
 		FlagAddMethod: "BoolVar",
 		DefinedOn: []string{"apply", "delete", "apps", "channels",
-			"types", "alias"},
+			"types", "alias", "rollback", "import"},
 	},
 	{
 		Name:      "token",
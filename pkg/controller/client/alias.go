@@ -36,6 +36,26 @@ func (ac *AliasClient) Get(ctx context.Context, scope, name string) (*meta.Alias
 	return &resp, nil
 }
 
+// List gets the aliases of the whole subtree of the given scope whose annotations
+// match the selector, which is a comma separated list of requirements such as
+// "env=prod", "env!=dev", "env in (prod,staging)", "env notin (dev)", "env" or "!env".
+// The parent of each alias is set to the scope of the dApp that holds it.
+func (ac *AliasClient) List(ctx context.Context, scope, selector string) ([]meta.Alias, error) {
+	adi := models.AliasQueryDI{
+		Selector: &selector,
+	}
+	var resp []meta.Alias
+
+	err := ac.reqClient.
+		Header(rest.HeaderScopeKey, scope).
+		Send(ctx, "/alias", http.MethodGet, adi, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 // Create creates an alias inside the Insprd
 // The scope refers to the app of the given alias, represented with a dot separated query
 // such as app1.app2.
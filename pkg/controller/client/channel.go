@@ -35,6 +35,26 @@ func (cc *ChannelClient) Get(ctx context.Context, scope, name string) (*meta.Cha
 	return &resp, nil
 }
 
+// List gets the channels of the whole subtree of the given scope whose annotations
+// match the selector, which is a comma separated list of requirements such as
+// "env=prod", "env!=dev", "env in (prod,staging)", "env notin (dev)", "env" or "!env".
+// The parent of each channel is set to the scope of the dApp that holds it.
+func (cc *ChannelClient) List(ctx context.Context, scope, selector string) ([]meta.Channel, error) {
+	cdi := models.ChannelQueryDI{
+		Selector: &selector,
+	}
+	var resp []meta.Channel
+
+	err := cc.reqClient.
+		Header(rest.HeaderScopeKey, scope).
+		Send(ctx, "/channels", http.MethodGet, cdi, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 // Create creates given channel inside of Insprd
 // The scope refers to the parent app of the given channel, represented with a dot separated query
 // such as app1.app2
@@ -94,3 +114,23 @@ func (cc *ChannelClient) Update(ctx context.Context, scope string, ch *meta.Chan
 
 	return resp, nil
 }
+
+// DeadLetters gets the messages of a channel that couldn't be delivered and were
+// sent to its dead-letter channel, looking at the latest 'limit' messages of the
+// dead-letter channel, oldest first. The default limit is used when it's 0
+func (cc *ChannelClient) DeadLetters(ctx context.Context, scope, name string, limit int) ([]meta.DeadLetterMessage, error) {
+	ddi := models.DeadLetterQueryDI{
+		ChName: name,
+		Limit:  limit,
+	}
+	var resp []meta.DeadLetterMessage
+
+	err := cc.reqClient.
+		Header(rest.HeaderScopeKey, scope).
+		Send(ctx, "/channels/deadletters", http.MethodGet, ddi, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -197,6 +197,123 @@ func TestChannelClient_Get(t *testing.T) {
 	}
 }
 
+func TestChannelClient_List(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    []meta.Channel
+		wantErr bool
+	}{
+		{
+			name: "lists the selected channels",
+			want: []meta.Channel{
+				{Meta: meta.Metadata{Name: "ch1", Parent: "app1"}},
+				{Meta: meta.Metadata{Name: "ch2", Parent: "app1.app2"}},
+			},
+		},
+		{
+			name:    "fails on an invalid selector",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				encoder := json.NewEncoder(w)
+				if tt.wantErr {
+					w.WriteHeader(http.StatusBadRequest)
+					encoder.Encode(ierrors.New("invalid selector").BadRequest())
+					return
+				}
+
+				var di models.ChannelQueryDI
+				json.NewDecoder(r.Body).Decode(&di)
+				if r.URL.Path != "/channels" || r.Method != http.MethodGet {
+					t.Errorf("request = %v %v, want GET /channels", r.Method, r.URL.Path)
+				}
+				if di.Selector == nil || *di.Selector != "env=prod" {
+					t.Errorf("selector set incorrectly. got = %v", di.Selector)
+				}
+				if scope := r.Header.Get(rest.HeaderScopeKey); scope != "app1" {
+					t.Errorf("scope set incorrectly. got = %v", scope)
+				}
+
+				encoder.Encode(tt.want)
+			}
+
+			s := httptest.NewServer(http.HandlerFunc(handler))
+			defer s.Close()
+			cc := &ChannelClient{
+				reqClient: request.NewJSONClient(s.URL),
+			}
+			got, err := cc.List(context.Background(), "app1", "env=prod")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ChannelClient.List() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChannelClient.List() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChannelClient_DeadLetters(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    []meta.DeadLetterMessage
+		wantErr bool
+	}{
+		{
+			name: "gets the dead-letters of the channel",
+			want: []meta.DeadLetterMessage{
+				{Channel: "ch1", Error: "node responded with status 500", Attempts: 3, Message: []byte("hello")},
+			},
+		},
+		{
+			name:    "fails on a channel without dead-letter",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				encoder := json.NewEncoder(w)
+				if tt.wantErr {
+					w.WriteHeader(http.StatusBadRequest)
+					encoder.Encode(ierrors.New("no dead-letter").BadRequest())
+					return
+				}
+
+				var di models.DeadLetterQueryDI
+				json.NewDecoder(r.Body).Decode(&di)
+				if r.URL.Path != "/channels/deadletters" || r.Method != http.MethodGet {
+					t.Errorf("request = %v %v, want GET /channels/deadletters", r.Method, r.URL.Path)
+				}
+				if di.ChName != "ch1" || di.Limit != 5 {
+					t.Errorf("request data set incorrectly. got = %v", di)
+				}
+				if scope := r.Header.Get(rest.HeaderScopeKey); scope != "app1" {
+					t.Errorf("scope set incorrectly. got = %v", scope)
+				}
+
+				encoder.Encode(tt.want)
+			}
+
+			s := httptest.NewServer(http.HandlerFunc(handler))
+			defer s.Close()
+			cc := &ChannelClient{
+				reqClient: request.NewJSONClient(s.URL),
+			}
+			got, err := cc.DeadLetters(context.Background(), "app1", "ch1", 5)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ChannelClient.DeadLetters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChannelClient.DeadLetters() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestChannelClient_Create(t *testing.T) {
 
 	type args struct {
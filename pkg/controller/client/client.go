@@ -85,6 +85,20 @@ func (c *Client) Types() controller.TypeInterface {
 	}
 }
 
+// Templates interacts with the templates of dApps on the Insprd
+func (c *Client) Templates() controller.TemplateInterface {
+	return &TemplateClient{
+		reqClient: c.HTTPClient,
+	}
+}
+
+// Instances interacts with the instances of templates on the Insprd
+func (c *Client) Instances() controller.InstanceInterface {
+	return &InstanceClient{
+		reqClient: c.HTTPClient,
+	}
+}
+
 // Authorization interacts with Insprd's auth
 func (c *Client) Authorization() controller.AuthorizationInterface {
 	return &AuthClient{
@@ -105,3 +119,31 @@ func (c *Client) Brokers() controller.BrokersInterface {
 		reqClient: c.HTTPClient,
 	}
 }
+
+// Revisions interacts with the tree revisions on the Insprd
+func (c *Client) Revisions() controller.RevisionInterface {
+	return &RevisionClient{
+		reqClient: c.HTTPClient,
+	}
+}
+
+// Snapshots interacts with the snapshots of the Insprd
+func (c *Client) Snapshots() controller.SnapshotInterface {
+	return &SnapshotClient{
+		reqClient: c.HTTPClient,
+	}
+}
+
+// Status interacts with the reconciliation of the Insprd runtime
+func (c *Client) Status() controller.StatusInterface {
+	return &StatusClient{
+		reqClient: c.HTTPClient,
+	}
+}
+
+// Graph interacts with the graph of the data flow of the Insprd
+func (c *Client) Graph() controller.GraphInterface {
+	return &GraphClient{
+		reqClient: c.HTTPClient,
+	}
+}
@@ -33,6 +33,27 @@ func (ac *AppClient) Get(ctx context.Context, scope string) (*meta.App, error) {
 	return &resp, nil
 }
 
+// List gets the dApps of the whole subtree of the given scope whose annotations
+// match the selector, which is a comma separated list of requirements such as
+// "env=prod", "env!=dev", "env in (prod,staging)", "env notin (dev)", "env" or "!env".
+// The parent of each dApp is set to the scope of its parent dApp. The dApp of the scope itself is
+// also selected, unless it's the root dApp.
+func (ac *AppClient) List(ctx context.Context, scope, selector string) ([]meta.App, error) {
+	adi := models.AppQueryDI{
+		Selector: &selector,
+	}
+	var resp []meta.App
+
+	err := ac.reqClient.
+		Header(rest.HeaderScopeKey, scope).
+		Send(ctx, "/apps", http.MethodGet, adi, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 // Create creates given dApp in Insprd
 // The scope refers to the parent app where the actual app will be instantiated,
 // represented with a dot separated query such as **app1.app2**.
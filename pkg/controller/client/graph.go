@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/rest"
+	"inspr.dev/inspr/pkg/rest/request"
+)
+
+// GraphClient interacts with the graph of the data flow of the Insprd
+type GraphClient struct {
+	reqClient *request.Client
+}
+
+// Get returns the graph of the resolved data flow of the given scope
+func (gc *GraphClient) Get(ctx context.Context, scope string) (*models.Graph, error) {
+	gdi := models.GraphQueryDI{
+		Format: models.GraphJSON,
+	}
+	resp := &models.Graph{}
+
+	err := gc.reqClient.
+		Header(rest.HeaderScopeKey, scope).
+		Send(ctx, "/graph", http.MethodGet, gdi, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Render returns the graph of the resolved data flow of the given scope
+// rendered in the given format, dot or mermaid
+func (gc *GraphClient) Render(ctx context.Context, scope, format string) (string, error) {
+	gdi := models.GraphQueryDI{
+		Format: format,
+	}
+	var resp string
+
+	err := gc.reqClient.
+		Header(rest.HeaderScopeKey, scope).
+		Send(ctx, "/graph", http.MethodGet, gdi, &resp)
+	if err != nil {
+		return "", err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/rest"
+	"inspr.dev/inspr/pkg/rest/request"
+)
+
+func TestGraphClient(t *testing.T) {
+	graph := &models.Graph{
+		Scope: "app1",
+		Vertices: []models.GraphVertex{
+			{ID: "node:app1.node", Kind: models.GraphNode, Scope: "app1", Name: "node"},
+		},
+		Edges: []models.GraphEdge{},
+	}
+	tests := []struct {
+		name    string
+		format  string
+		wantErr bool
+	}{
+		{
+			name:   "get graph",
+			format: models.GraphJSON,
+		},
+		{
+			name:   "render graph",
+			format: models.GraphDOT,
+		},
+		{
+			name:    "failed request",
+			format:  models.GraphJSON,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				encoder := json.NewEncoder(w)
+				if tt.wantErr {
+					w.WriteHeader(http.StatusInternalServerError)
+					encoder.Encode(ierrors.New("").InternalServer())
+					return
+				}
+
+				if scope := r.Header.Get(rest.HeaderScopeKey); scope != "app1" {
+					t.Errorf("scope = %v, want app1", scope)
+				}
+				if r.URL.Path != "/graph" || r.Method != http.MethodGet {
+					t.Errorf("request = %v %v, want GET /graph", r.Method, r.URL.Path)
+				}
+				data := models.GraphQueryDI{}
+				json.NewDecoder(r.Body).Decode(&data)
+				if data.Format != tt.format {
+					t.Errorf("request format = %v, want %v", data.Format, tt.format)
+				}
+
+				if data.Format == models.GraphJSON {
+					encoder.Encode(graph)
+					return
+				}
+				encoder.Encode(graph.DOT())
+			}
+			s := httptest.NewServer(http.HandlerFunc(handler))
+			defer s.Close()
+			gc := &GraphClient{
+				reqClient: request.NewJSONClient(s.URL),
+			}
+
+			if tt.format == models.GraphJSON {
+				got, err := gc.Get(context.Background(), "app1")
+				if (err != nil) != tt.wantErr {
+					t.Errorf("GraphClient.Get() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if !tt.wantErr && !reflect.DeepEqual(got, graph) {
+					t.Errorf("GraphClient.Get() = %v, want %v", got, graph)
+				}
+				return
+			}
+
+			got, err := gc.Render(context.Background(), "app1", tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GraphClient.Render() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != graph.DOT() {
+				t.Errorf("GraphClient.Render() = %v, want %v", got, graph.DOT())
+			}
+		})
+	}
+}
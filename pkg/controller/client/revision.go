@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+	"inspr.dev/inspr/pkg/rest"
+	"inspr.dev/inspr/pkg/rest/request"
+)
+
+// RevisionClient interacts with the tree revisions on the Insprd
+type RevisionClient struct {
+	reqClient *request.Client
+}
+
+// History gets the revisions committed on the Insprd, from the oldest to the newest
+func (rc *RevisionClient) History(ctx context.Context) ([]models.Revision, error) {
+	var resp []models.Revision
+
+	err := rc.reqClient.
+		Header(rest.HeaderScopeKey, "").
+		Send(ctx, "/revisions", http.MethodGet, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Rollback reverts the Insprd's tree to the given revision, the
+// changes needed to do so are applied to the cluster and returned
+func (rc *RevisionClient) Rollback(ctx context.Context, revision int, dryRun bool) (diff.Changelog, error) {
+	rdi := models.RevisionQueryDI{
+		Revision: revision,
+		DryRun:   dryRun,
+	}
+	var resp diff.Changelog
+
+	err := rc.reqClient.
+		Header(rest.HeaderScopeKey, "").
+		Send(ctx, "/revisions/rollback", http.MethodPut, rdi, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+	"inspr.dev/inspr/pkg/rest/request"
+)
+
+func TestRevisionClient_History(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    []models.Revision
+		wantErr bool
+	}{
+		{
+			name: "get revision history",
+			want: []models.Revision{
+				{Number: 1, Author: "user", Changes: diff.Changelog{}},
+			},
+		},
+		{
+			name:    "failed revision history",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				encoder := json.NewEncoder(w)
+				if tt.wantErr {
+					w.WriteHeader(http.StatusBadRequest)
+					encoder.Encode(ierrors.New("").BadRequest())
+					return
+				}
+
+				if r.URL.Path != "/revisions" {
+					t.Errorf("path is not revisions")
+				}
+				if r.Method != http.MethodGet {
+					t.Errorf("method is not GET")
+				}
+				encoder.Encode(tt.want)
+			}
+			s := httptest.NewServer(http.HandlerFunc(handler))
+			defer s.Close()
+			rc := &RevisionClient{
+				reqClient: request.NewJSONClient(s.URL),
+			}
+
+			got, err := rc.History(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RevisionClient.History() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RevisionClient.History() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevisionClient_Rollback(t *testing.T) {
+	tests := []struct {
+		name     string
+		revision int
+		dryRun   bool
+		want     diff.Changelog
+		wantErr  bool
+	}{
+		{
+			name:     "rollback to revision",
+			revision: 2,
+			dryRun:   true,
+			want:     diff.Changelog{{Scope: "app1", Diff: []diff.Difference{}}},
+		},
+		{
+			name:     "failed rollback",
+			revision: 2,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				encoder := json.NewEncoder(w)
+				if tt.wantErr {
+					w.WriteHeader(http.StatusBadRequest)
+					encoder.Encode(ierrors.New("").BadRequest())
+					return
+				}
+
+				data := models.RevisionQueryDI{}
+				json.NewDecoder(r.Body).Decode(&data)
+				if r.URL.Path != "/revisions/rollback" || r.Method != http.MethodPut {
+					t.Errorf("request is not a PUT on revisions/rollback")
+				}
+				if data.Revision != tt.revision || data.DryRun != tt.dryRun {
+					t.Errorf("request body = %v", data)
+				}
+				encoder.Encode(tt.want)
+			}
+			s := httptest.NewServer(http.HandlerFunc(handler))
+			defer s.Close()
+			rc := &RevisionClient{
+				reqClient: request.NewJSONClient(s.URL),
+			}
+
+			got, err := rc.Rollback(context.Background(), tt.revision, tt.dryRun)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RevisionClient.Rollback() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RevisionClient.Rollback() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+	"inspr.dev/inspr/pkg/rest"
+	"inspr.dev/inspr/pkg/rest/request"
+)
+
+// SnapshotClient interacts with snapshots of the Insprd
+type SnapshotClient struct {
+	reqClient *request.Client
+}
+
+// Export gets a snapshot of the subtree of the given scope
+// and of the brokers configured on the Insprd
+func (sc *SnapshotClient) Export(ctx context.Context, scope string) (*models.Snapshot, error) {
+	resp := &models.Snapshot{}
+
+	err := sc.reqClient.
+		Header(rest.HeaderScopeKey, scope).
+		Send(ctx, "/snapshot", http.MethodGet, nil, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Import restores the given snapshot on the Insprd, replacing the subtree of
+// its scope, the changes needed to do so are applied to the cluster and returned
+func (sc *SnapshotClient) Import(ctx context.Context, snapshot *models.Snapshot, dryRun bool) (diff.Changelog, error) {
+	sdi := models.SnapshotQueryDI{
+		Snapshot: *snapshot,
+		DryRun:   dryRun,
+	}
+	var resp diff.Changelog
+
+	err := sc.reqClient.
+		Header(rest.HeaderScopeKey, snapshot.Scope).
+		Send(ctx, "/snapshot/import", http.MethodPut, sdi, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+	"inspr.dev/inspr/pkg/rest"
+	"inspr.dev/inspr/pkg/rest/request"
+)
+
+func TestSnapshotClient_Export(t *testing.T) {
+	tests := []struct {
+		name    string
+		scope   string
+		want    *models.Snapshot
+		wantErr bool
+	}{
+		{
+			name:  "export scope",
+			scope: "app1",
+			want: &models.Snapshot{
+				Scope:         "app1",
+				App:           &meta.App{Meta: meta.Metadata{Name: "app1"}},
+				Brokers:       []models.BrokerConfigDI{{BrokerName: "kafka", FileContents: []byte("a: b")}},
+				DefaultBroker: "kafka",
+			},
+		},
+		{
+			name:    "failed export",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				encoder := json.NewEncoder(w)
+				if tt.wantErr {
+					w.WriteHeader(http.StatusBadRequest)
+					encoder.Encode(ierrors.New("").BadRequest())
+					return
+				}
+
+				if r.URL.Path != "/snapshot" {
+					t.Errorf("path is not snapshot")
+				}
+				if r.Method != http.MethodGet {
+					t.Errorf("method is not GET")
+				}
+				if scope := r.Header.Get(rest.HeaderScopeKey); scope != tt.scope {
+					t.Errorf("scope = %v, want %v", scope, tt.scope)
+				}
+				encoder.Encode(tt.want)
+			}
+			s := httptest.NewServer(http.HandlerFunc(handler))
+			defer s.Close()
+			sc := &SnapshotClient{
+				reqClient: request.NewJSONClient(s.URL),
+			}
+
+			got, err := sc.Export(context.Background(), tt.scope)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SnapshotClient.Export() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SnapshotClient.Export() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnapshotClient_Import(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot *models.Snapshot
+		dryRun   bool
+		want     diff.Changelog
+		wantErr  bool
+	}{
+		{
+			name:     "import snapshot",
+			snapshot: &models.Snapshot{Scope: "app1", App: &meta.App{}},
+			dryRun:   true,
+			want:     diff.Changelog{{Scope: "app1", Diff: []diff.Difference{}}},
+		},
+		{
+			name:     "failed import",
+			snapshot: &models.Snapshot{},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				encoder := json.NewEncoder(w)
+				if tt.wantErr {
+					w.WriteHeader(http.StatusBadRequest)
+					encoder.Encode(ierrors.New("").BadRequest())
+					return
+				}
+
+				if r.URL.Path != "/snapshot/import" {
+					t.Errorf("path is not snapshot/import")
+				}
+				if r.Method != http.MethodPut {
+					t.Errorf("method is not PUT")
+				}
+				if scope := r.Header.Get(rest.HeaderScopeKey); scope != tt.snapshot.Scope {
+					t.Errorf("scope = %v, want %v", scope, tt.snapshot.Scope)
+				}
+
+				data := models.SnapshotQueryDI{}
+				json.NewDecoder(r.Body).Decode(&data)
+				if data.DryRun != tt.dryRun || data.Snapshot.Scope != tt.snapshot.Scope {
+					t.Errorf("request body = %v, want the snapshot and dry run", data)
+				}
+				encoder.Encode(tt.want)
+			}
+			s := httptest.NewServer(http.HandlerFunc(handler))
+			defer s.Close()
+			sc := &SnapshotClient{
+				reqClient: request.NewJSONClient(s.URL),
+			}
+
+			got, err := sc.Import(context.Background(), tt.snapshot, tt.dryRun)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SnapshotClient.Import() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SnapshotClient.Import() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/rest"
+	"inspr.dev/inspr/pkg/rest/request"
+)
+
+// StatusClient interacts with the reconciliation of the Insprd runtime
+type StatusClient struct {
+	reqClient *request.Client
+}
+
+// Get returns the drifts in the given scope found by the last reconciliation
+// of the runtime with the tree
+func (sc *StatusClient) Get(ctx context.Context, scope string) (*models.DriftReport, error) {
+	resp := &models.DriftReport{}
+
+	err := sc.reqClient.
+		Header(rest.HeaderScopeKey, scope).
+		Send(ctx, "/status", http.MethodGet, nil, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Reconcile reconciles the runtime with the tree, creating again the missing
+// resources if repair is set, and returns the drifts found in the given scope
+func (sc *StatusClient) Reconcile(ctx context.Context, scope string, repair bool) (*models.DriftReport, error) {
+	rdi := models.ReconcileQueryDI{
+		Repair: repair,
+	}
+	resp := &models.DriftReport{}
+
+	err := sc.reqClient.
+		Header(rest.HeaderScopeKey, scope).
+		Send(ctx, "/status/reconcile", http.MethodPut, rdi, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
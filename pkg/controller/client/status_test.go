@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/rest"
+	"inspr.dev/inspr/pkg/rest/request"
+)
+
+func TestStatusClient(t *testing.T) {
+	report := &models.DriftReport{
+		Channels: 1,
+		Drifts: []models.Drift{
+			{Kind: models.DriftChannel, Scope: "app1", Name: "ch1", Reason: "not found"},
+		},
+	}
+	tests := []struct {
+		name    string
+		repair  *bool
+		wantErr bool
+	}{
+		{
+			name: "get status",
+		},
+		{
+			name:   "reconcile",
+			repair: func() *bool { b := true; return &b }(),
+		},
+		{
+			name:    "failed request",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				encoder := json.NewEncoder(w)
+				if tt.wantErr {
+					w.WriteHeader(http.StatusInternalServerError)
+					encoder.Encode(ierrors.New("").InternalServer())
+					return
+				}
+
+				if scope := r.Header.Get(rest.HeaderScopeKey); scope != "app1" {
+					t.Errorf("scope = %v, want app1", scope)
+				}
+				if tt.repair == nil {
+					if r.URL.Path != "/status" || r.Method != http.MethodGet {
+						t.Errorf("request = %v %v, want GET /status", r.Method, r.URL.Path)
+					}
+				} else {
+					if r.URL.Path != "/status/reconcile" || r.Method != http.MethodPut {
+						t.Errorf("request = %v %v, want PUT /status/reconcile", r.Method, r.URL.Path)
+					}
+					data := models.ReconcileQueryDI{}
+					json.NewDecoder(r.Body).Decode(&data)
+					if data.Repair != *tt.repair {
+						t.Errorf("request body = %v, want repair %v", data, *tt.repair)
+					}
+				}
+				encoder.Encode(report)
+			}
+			s := httptest.NewServer(http.HandlerFunc(handler))
+			defer s.Close()
+			sc := &StatusClient{
+				reqClient: request.NewJSONClient(s.URL),
+			}
+
+			var got *models.DriftReport
+			var err error
+			if tt.repair == nil {
+				got, err = sc.Get(context.Background(), "app1")
+			} else {
+				got, err = sc.Reconcile(context.Background(), "app1", *tt.repair)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("StatusClient error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, report) {
+				t.Errorf("StatusClient = %v, want %v", got, report)
+			}
+		})
+	}
+}
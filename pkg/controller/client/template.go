@@ -0,0 +1,182 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+	"inspr.dev/inspr/pkg/rest"
+	"inspr.dev/inspr/pkg/rest/request"
+)
+
+// TemplateClient is a client for manipulating Template structures in Insprd
+type TemplateClient struct {
+	reqClient *request.Client
+}
+
+// Get gets a Template from Insprd, if it exists, along with the scopes of the
+// dApps instantiated from it.
+// The scope refers to the dApp in which the Template is in, represented with a dot separated query
+// such as app1.app2
+func (tc *TemplateClient) Get(ctx context.Context, scope, name string) (*models.TemplateDO, error) {
+	tdi := models.TemplateQueryDI{
+		TemplateName: name,
+	}
+	var resp models.TemplateDO
+
+	err := tc.reqClient.
+		Header(rest.HeaderScopeKey, scope).
+		Send(ctx, "/templates", http.MethodGet, tdi, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Create creates given Template inside of Insprd
+// The scope refers to the dApp in which the Template will be in, represented with a dot separated query
+// such as app1.app2
+func (tc *TemplateClient) Create(ctx context.Context, scope string, t *meta.Template, dryRun bool) (diff.Changelog, error) {
+	tdi := models.TemplateDI{
+		Template: *t,
+		DryRun:   dryRun,
+	}
+	var resp diff.Changelog
+
+	err := tc.reqClient.
+		Header(rest.HeaderScopeKey, scope).
+		Send(ctx, "/templates", http.MethodPost, tdi, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Delete deletes a Template from Insprd, if it exists and has no instances.
+// The scope refers to the dApp in which the Template is in, represented with a dot separated query
+// such as app1.app2. The name is the name of the Template to be deleted
+func (tc *TemplateClient) Delete(ctx context.Context, scope, name string, dryRun bool) (diff.Changelog, error) {
+	tdi := models.TemplateQueryDI{
+		TemplateName: name,
+		DryRun:       dryRun,
+	}
+	var resp diff.Changelog
+
+	err := tc.reqClient.
+		Header(rest.HeaderScopeKey, scope).
+		Send(ctx, "/templates", http.MethodDelete, tdi, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Update updates given Template in Insprd, if it exists, rendering again
+// every dApp instantiated from it.
+// The scope refers to the dApp in which the Template is in, represented with a dot separated query
+// such as app1.app2
+func (tc *TemplateClient) Update(ctx context.Context, scope string, t *meta.Template, dryRun bool) (diff.Changelog, error) {
+	tdi := models.TemplateDI{
+		Template: *t,
+		DryRun:   dryRun,
+	}
+	var resp diff.Changelog
+
+	err := tc.reqClient.
+		Header(rest.HeaderScopeKey, scope).
+		Send(ctx, "/templates", http.MethodPut, tdi, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// InstanceClient is a client for instantiating Templates in Insprd
+type InstanceClient struct {
+	reqClient *request.Client
+}
+
+// Get gets the instance a dApp was created from, if it was instantiated from a Template.
+// The scope refers to the parent of the dApp, represented with a dot separated query
+// such as app1.app2
+func (ic *InstanceClient) Get(ctx context.Context, scope, name string) (*meta.TemplateInstance, error) {
+	idi := models.InstanceQueryDI{
+		InstanceName: name,
+	}
+	var resp meta.TemplateInstance
+
+	err := ic.reqClient.
+		Header(rest.HeaderScopeKey, scope).
+		Send(ctx, "/templates/instances", http.MethodGet, idi, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Create creates the dApp of the given instance of a Template in Insprd.
+// The scope refers to the dApp in which the instance will be in, represented with a dot separated query
+// such as app1.app2
+func (ic *InstanceClient) Create(ctx context.Context, scope string, instance *meta.TemplateInstance, dryRun bool) (diff.Changelog, error) {
+	idi := models.InstanceDI{
+		Instance: *instance,
+		DryRun:   dryRun,
+	}
+	var resp diff.Changelog
+
+	err := ic.reqClient.
+		Header(rest.HeaderScopeKey, scope).
+		Send(ctx, "/templates/instances", http.MethodPost, idi, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Delete deletes the dApp of an instance of a Template from Insprd.
+// The scope refers to the parent of the dApp, represented with a dot separated query
+// such as app1.app2
+func (ic *InstanceClient) Delete(ctx context.Context, scope, name string, dryRun bool) (diff.Changelog, error) {
+	idi := models.InstanceQueryDI{
+		InstanceName: name,
+		DryRun:       dryRun,
+	}
+	var resp diff.Changelog
+
+	err := ic.reqClient.
+		Header(rest.HeaderScopeKey, scope).
+		Send(ctx, "/templates/instances", http.MethodDelete, idi, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Update renders again the dApp of the given instance of a Template in Insprd.
+// The scope refers to the parent of the dApp, represented with a dot separated query
+// such as app1.app2
+func (ic *InstanceClient) Update(ctx context.Context, scope string, instance *meta.TemplateInstance, dryRun bool) (diff.Changelog, error) {
+	idi := models.InstanceDI{
+		Instance: *instance,
+		DryRun:   dryRun,
+	}
+	var resp diff.Changelog
+
+	err := ic.reqClient.
+		Header(rest.HeaderScopeKey, scope).
+		Send(ctx, "/templates/instances", http.MethodPut, idi, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
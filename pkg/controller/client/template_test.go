@@ -0,0 +1,179 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+	"inspr.dev/inspr/pkg/rest"
+	"inspr.dev/inspr/pkg/rest/request"
+)
+
+func TestTemplateClient(t *testing.T) {
+	tmpl := &meta.Template{Meta: meta.Metadata{Name: "pipeline"}}
+	tests := []struct {
+		name    string
+		method  string
+		call    func(tc *TemplateClient) error
+		wantErr bool
+	}{
+		{
+			name:   "get template",
+			method: http.MethodGet,
+			call: func(tc *TemplateClient) error {
+				do, err := tc.Get(context.Background(), "app1", "pipeline")
+				if err == nil && (len(do.Instances) != 1 || do.Template.Meta.Name != "pipeline") {
+					t.Errorf("TemplateClient.Get() = %v", do)
+				}
+				return err
+			},
+		},
+		{
+			name:   "create template",
+			method: http.MethodPost,
+			call: func(tc *TemplateClient) error {
+				_, err := tc.Create(context.Background(), "app1", tmpl, false)
+				return err
+			},
+		},
+		{
+			name:   "update template",
+			method: http.MethodPut,
+			call: func(tc *TemplateClient) error {
+				_, err := tc.Update(context.Background(), "app1", tmpl, true)
+				return err
+			},
+		},
+		{
+			name:   "delete template",
+			method: http.MethodDelete,
+			call: func(tc *TemplateClient) error {
+				_, err := tc.Delete(context.Background(), "app1", "pipeline", false)
+				return err
+			},
+		},
+		{
+			name:   "failed request",
+			method: http.MethodPost,
+			call: func(tc *TemplateClient) error {
+				_, err := tc.Create(context.Background(), "app1", tmpl, false)
+				return err
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				encoder := json.NewEncoder(w)
+				if tt.wantErr {
+					w.WriteHeader(http.StatusBadRequest)
+					encoder.Encode(ierrors.New("").BadRequest())
+					return
+				}
+
+				if scope := r.Header.Get(rest.HeaderScopeKey); scope != "app1" {
+					t.Errorf("scope = %v, want app1", scope)
+				}
+				if r.URL.Path != "/templates" || r.Method != tt.method {
+					t.Errorf("request = %v %v, want %v /templates", r.Method, r.URL.Path, tt.method)
+				}
+
+				if r.Method == http.MethodGet {
+					encoder.Encode(models.TemplateDO{Template: *tmpl, Instances: []string{"app1.acme"}})
+					return
+				}
+				encoder.Encode(diff.Changelog{})
+			}
+			s := httptest.NewServer(http.HandlerFunc(handler))
+			defer s.Close()
+
+			err := tt.call(&TemplateClient{reqClient: request.NewJSONClient(s.URL)})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TemplateClient error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInstanceClient(t *testing.T) {
+	instance := &meta.TemplateInstance{
+		Meta:     meta.Metadata{Name: "acme"},
+		Template: "pipeline",
+		Values:   map[string]string{"customer": "acme"},
+	}
+	tests := []struct {
+		name   string
+		method string
+		call   func(ic *InstanceClient) error
+	}{
+		{
+			name:   "get instance",
+			method: http.MethodGet,
+			call: func(ic *InstanceClient) error {
+				got, err := ic.Get(context.Background(), "app1", "acme")
+				if err == nil && got.Values["customer"] != "acme" {
+					t.Errorf("InstanceClient.Get() = %v, want %v", got, instance)
+				}
+				return err
+			},
+		},
+		{
+			name:   "create instance",
+			method: http.MethodPost,
+			call: func(ic *InstanceClient) error {
+				_, err := ic.Create(context.Background(), "app1", instance, false)
+				return err
+			},
+		},
+		{
+			name:   "update instance",
+			method: http.MethodPut,
+			call: func(ic *InstanceClient) error {
+				_, err := ic.Update(context.Background(), "app1", instance, false)
+				return err
+			},
+		},
+		{
+			name:   "delete instance",
+			method: http.MethodDelete,
+			call: func(ic *InstanceClient) error {
+				_, err := ic.Delete(context.Background(), "app1", "acme", false)
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				encoder := json.NewEncoder(w)
+				if r.URL.Path != "/templates/instances" || r.Method != tt.method {
+					t.Errorf("request = %v %v, want %v /templates/instances", r.Method, r.URL.Path, tt.method)
+				}
+
+				if r.Method == http.MethodGet {
+					encoder.Encode(instance)
+					return
+				}
+				data := models.InstanceDI{}
+				json.NewDecoder(r.Body).Decode(&data)
+				if r.Method != http.MethodDelete && data.Instance.Template != "pipeline" {
+					t.Errorf("request instance = %v, want %v", data.Instance, instance)
+				}
+				encoder.Encode(diff.Changelog{})
+			}
+			s := httptest.NewServer(http.HandlerFunc(handler))
+			defer s.Close()
+
+			if err := tt.call(&InstanceClient{reqClient: request.NewJSONClient(s.URL)}); err != nil {
+				t.Errorf("InstanceClient error = %v", err)
+			}
+		})
+	}
+}
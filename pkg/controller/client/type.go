@@ -35,6 +35,26 @@ func (tc *TypeClient) Get(ctx context.Context, scope, name string) (*meta.Type,
 	return &resp, nil
 }
 
+// List gets the Types of the whole subtree of the given scope whose annotations
+// match the selector, which is a comma separated list of requirements such as
+// "env=prod", "env!=dev", "env in (prod,staging)", "env notin (dev)", "env" or "!env".
+// The parent of each Type is set to the scope of the dApp that holds it.
+func (tc *TypeClient) List(ctx context.Context, scope, selector string) ([]meta.Type, error) {
+	tdi := models.TypeQueryDI{
+		Selector: &selector,
+	}
+	var resp []meta.Type
+
+	err := tc.reqClient.
+		Header(rest.HeaderScopeKey, scope).
+		Send(ctx, "/types", http.MethodGet, tdi, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 // Create creates given Type inside of Insprd
 // The scope refers to the dApp in which the Type will be in, represented with a dot separated query
 // such as app1.app2
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+	"inspr.dev/inspr/pkg/rest"
+	"inspr.dev/inspr/pkg/rest/request"
+)
+
+const (
+	// watchMinBackoff is the time waited before the first reconnection attempt
+	watchMinBackoff = 500 * time.Millisecond
+	// watchMaxBackoff is the longest time waited between reconnection attempts
+	watchMaxBackoff = 30 * time.Second
+)
+
+// Watch streams the changes committed on the Insprd under the given scope,
+// only with the differences of the given kinds, or all of them if kinds is
+// zero. Dropped connections are reestablished, resuming from the last revision
+// fully received, so the changes of a revision being read when the connection
+// dropped may be sent again. The returned channel is closed once the context
+// is done or the watch is no longer authorized.
+func (c *Client) Watch(ctx context.Context, scope string, kinds diff.Kind) (<-chan models.WatchEvent, error) {
+	reqClient := c.HTTPClient.Header(rest.HeaderScopeKey, scope)
+
+	body, err := watch(ctx, reqClient, kinds, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan models.WatchEvent)
+	go func() {
+		defer close(events)
+
+		since := 0
+		backoff := watchMinBackoff
+		for {
+			since = readWatchEvents(ctx, body, events, since)
+			body.Close()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(backoff):
+				}
+
+				body, err = watch(ctx, reqClient, kinds, since)
+				if err == nil {
+					backoff = watchMinBackoff
+					break
+				}
+				if ierrors.HasCode(err, ierrors.Unauthorized) ||
+					ierrors.HasCode(err, ierrors.Forbidden) {
+					return
+				}
+
+				backoff *= 2
+				if backoff > watchMaxBackoff {
+					backoff = watchMaxBackoff
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// watch opens a watch stream on the Insprd, replaying the
+// changes committed after the given revision, if any
+func watch(ctx context.Context, reqClient request.Client, kinds diff.Kind, since int) (io.ReadCloser, error) {
+	wdi := models.WatchDI{
+		Kinds: kinds,
+		Since: since,
+	}
+	return reqClient.Stream(ctx, "/watch", http.MethodGet, wdi)
+}
+
+// readWatchEvents sends the events read from the given stream to the events
+// channel until the stream ends, returning the last revision fully received
+func readWatchEvents(ctx context.Context, body io.Reader, events chan<- models.WatchEvent, since int) int {
+	// a revision may span many events, so it is only taken as
+	// received once the events of the next one start to arrive
+	current := since
+	decoder := json.NewDecoder(body)
+	for {
+		event := models.WatchEvent{}
+		if err := decoder.Decode(&event); err != nil {
+			return since
+		}
+
+		if event.Bookmark {
+			since, current = event.Revision, event.Revision
+			continue
+		}
+		if event.Revision > current {
+			since, current = current, event.Revision
+		}
+
+		select {
+		case events <- event:
+		case <-ctx.Done():
+			return since
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+	"inspr.dev/inspr/pkg/rest"
+	"inspr.dev/inspr/pkg/rest/request"
+)
+
+func TestClient_Watch(t *testing.T) {
+	// every connection sends the events after the requested revision and drops
+	streams := map[int][]models.WatchEvent{
+		0: {
+			{Revision: 1, Bookmark: true},
+			{Revision: 2, Change: diff.Change{Scope: "app1"}},
+			{Revision: 3, Change: diff.Change{Scope: "app1.app2"}},
+			{Revision: 3, Change: diff.Change{Scope: "app1.app3"}},
+		},
+		2: {
+			{Revision: 3, Change: diff.Change{Scope: "app1.app2"}},
+			{Revision: 3, Change: diff.Change{Scope: "app1.app3"}},
+			{Revision: 3, Bookmark: true},
+			{Revision: 4, Change: diff.Change{Scope: "app1"}},
+		},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/watch" || r.Method != http.MethodGet {
+			t.Errorf("request is not a GET on /watch")
+		}
+		if r.Header.Get(rest.HeaderScopeKey) != "app1" {
+			t.Errorf("scope header = %v, want app1", r.Header.Get(rest.HeaderScopeKey))
+		}
+
+		data := models.WatchDI{}
+		json.NewDecoder(r.Body).Decode(&data)
+		if data.Kinds != diff.AppKind {
+			t.Errorf("kinds = %v, want %v", data.Kinds, diff.AppKind)
+		}
+
+		events, ok := streams[data.Since]
+		if !ok {
+			// no more changes, keep the stream open until the client leaves
+			<-r.Context().Done()
+			return
+		}
+		encoder := json.NewEncoder(w)
+		for _, event := range events {
+			encoder.Encode(event)
+		}
+	}
+	s := httptest.NewServer(http.HandlerFunc(handler))
+	defer s.Close()
+
+	c := &Client{HTTPClient: request.NewJSONClient(s.URL)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := c.Watch(ctx, "app1", diff.AppKind)
+	if err != nil {
+		t.Fatalf("Client.Watch() error = %v", err)
+	}
+
+	// the changes of the revision being read when the stream
+	// dropped are sent again, the bookmarks are never sent
+	want := []int{2, 3, 3, 3, 3, 4}
+	for i, revision := range want {
+		event := <-events
+		if event.Revision != revision || event.Bookmark {
+			t.Errorf("Client.Watch() event %d = %v, want revision %v", i, event, revision)
+		}
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestClient_Watch_error(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(ierrors.New("forbidden").Forbidden())
+	}
+	s := httptest.NewServer(http.HandlerFunc(handler))
+	defer s.Close()
+
+	c := &Client{HTTPClient: request.NewJSONClient(s.URL)}
+	if _, err := c.Watch(context.Background(), "app1", 0); err == nil {
+		t.Errorf("Client.Watch() error = nil, want error")
+	}
+}
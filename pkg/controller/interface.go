@@ -14,9 +14,11 @@ import (
 // the Channels in the cluster
 type ChannelInterface interface {
 	Get(ctx context.Context, scope, name string) (*meta.Channel, error)
+	List(ctx context.Context, scope, selector string) ([]meta.Channel, error)
 	Create(ctx context.Context, scope string, ch *meta.Channel, dryRun bool) (diff.Changelog, error)
 	Delete(ctx context.Context, scope, name string, dryRun bool) (diff.Changelog, error)
 	Update(ctx context.Context, scope string, ch *meta.Channel, dryRun bool) (diff.Changelog, error)
+	DeadLetters(ctx context.Context, scope, name string, limit int) ([]meta.DeadLetterMessage, error)
 }
 
 // AppInterface is the interface that allows to obtain or
@@ -24,6 +26,7 @@ type ChannelInterface interface {
 // the DApps in the cluster
 type AppInterface interface {
 	Get(ctx context.Context, scope string) (*meta.App, error)
+	List(ctx context.Context, scope, selector string) ([]meta.App, error)
 	Create(ctx context.Context, scope string, app *meta.App, dryRun bool) (diff.Changelog, error)
 	Delete(ctx context.Context, scope string, dryRun bool) (diff.Changelog, error)
 	Update(ctx context.Context, scope string, app *meta.App, dryRun bool) (diff.Changelog, error)
@@ -34,11 +37,32 @@ type AppInterface interface {
 // state of the Types in the cluster
 type TypeInterface interface {
 	Get(ctx context.Context, scope, name string) (*meta.Type, error)
+	List(ctx context.Context, scope, selector string) ([]meta.Type, error)
 	Create(ctx context.Context, scope string, t *meta.Type, dryRun bool) (diff.Changelog, error)
 	Delete(ctx context.Context, scope, name string, dryRun bool) (diff.Changelog, error)
 	Update(ctx context.Context, scope string, t *meta.Type, dryRun bool) (diff.Changelog, error)
 }
 
+// TemplateInterface is the interface that allows to
+// obtain or change information related to the current
+// state of the Templates of dApps in the cluster
+type TemplateInterface interface {
+	Get(ctx context.Context, scope, name string) (*models.TemplateDO, error)
+	Create(ctx context.Context, scope string, t *meta.Template, dryRun bool) (diff.Changelog, error)
+	Delete(ctx context.Context, scope, name string, dryRun bool) (diff.Changelog, error)
+	Update(ctx context.Context, scope string, t *meta.Template, dryRun bool) (diff.Changelog, error)
+}
+
+// InstanceInterface is the interface that allows to
+// instantiate Templates in the cluster, and to obtain or
+// change the dApps instantiated from them
+type InstanceInterface interface {
+	Get(ctx context.Context, scope, name string) (*meta.TemplateInstance, error)
+	Create(ctx context.Context, scope string, instance *meta.TemplateInstance, dryRun bool) (diff.Changelog, error)
+	Delete(ctx context.Context, scope, name string, dryRun bool) (diff.Changelog, error)
+	Update(ctx context.Context, scope string, instance *meta.TemplateInstance, dryRun bool) (diff.Changelog, error)
+}
+
 // AuthorizationInterface is the interface that allows to
 // obtain information related to the authorization necessary
 // to make changes in structures inside of the cluster
@@ -52,6 +76,7 @@ type AuthorizationInterface interface {
 // state of the Alias in the cluster
 type AliasInterface interface {
 	Get(ctx context.Context, scope, key string) (*meta.Alias, error)
+	List(ctx context.Context, scope, selector string) ([]meta.Alias, error)
 	Create(ctx context.Context, scope string, alias *meta.Alias, dryRun bool) (diff.Changelog, error)
 	Delete(ctx context.Context, scope, name string, dryRun bool) (diff.Changelog, error)
 	Update(ctx context.Context, scope string, alias *meta.Alias, dryRun bool) (diff.Changelog, error)
@@ -65,6 +90,38 @@ type BrokersInterface interface {
 	Create(ctx context.Context, name string, config []byte) error
 }
 
+// RevisionInterface is the interface that allows to
+// obtain the history of changes made on the cluster and
+// to roll the cluster back to a previous revision
+type RevisionInterface interface {
+	History(ctx context.Context) ([]models.Revision, error)
+	Rollback(ctx context.Context, revision int, dryRun bool) (diff.Changelog, error)
+}
+
+// SnapshotInterface is the interface that allows to
+// export the state of a subtree of the cluster, along with
+// its brokers, and to restore it on the same or on another cluster
+type SnapshotInterface interface {
+	Export(ctx context.Context, scope string) (*models.Snapshot, error)
+	Import(ctx context.Context, snapshot *models.Snapshot, dryRun bool) (diff.Changelog, error)
+}
+
+// StatusInterface is the interface that allows to obtain
+// the drifts between the cluster and its runtime, and to
+// reconcile the runtime with the cluster
+type StatusInterface interface {
+	Get(ctx context.Context, scope string) (*models.DriftReport, error)
+	Reconcile(ctx context.Context, scope string, repair bool) (*models.DriftReport, error)
+}
+
+// GraphInterface is the interface that allows to obtain
+// the graph of the data flow between the nodes of a scope
+// and the channels and routes they are connected to
+type GraphInterface interface {
+	Get(ctx context.Context, scope string) (*models.Graph, error)
+	Render(ctx context.Context, scope, format string) (string, error)
+}
+
 // Interface is the interface that allows the management
 // of the current state of the cluster. Permiting the
 // modification of Channels, DApps and Types
@@ -72,7 +129,14 @@ type Interface interface {
 	Channels() ChannelInterface
 	Apps() AppInterface
 	Types() TypeInterface
+	Templates() TemplateInterface
+	Instances() InstanceInterface
 	Authorization() AuthorizationInterface
 	Alias() AliasInterface
 	Brokers() BrokersInterface
+	Revisions() RevisionInterface
+	Snapshots() SnapshotInterface
+	Status() StatusInterface
+	Graph() GraphInterface
+	Watch(ctx context.Context, scope string, kinds diff.Kind) (<-chan models.WatchEvent, error)
 }
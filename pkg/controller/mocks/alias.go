@@ -28,6 +28,14 @@ func (am *AliasMock) Get(ctx context.Context, scope, name string) (*meta.Alias,
 	return &meta.Alias{}, nil
 }
 
+// List is the AliasMock List
+func (am *AliasMock) List(ctx context.Context, scope, selector string) ([]meta.Alias, error) {
+	if am.err != nil {
+		return nil, am.err
+	}
+	return []meta.Alias{}, nil
+}
+
 // Create is the AliasMock Create
 func (am *AliasMock) Create(ctx context.Context, scope string, alias *meta.Alias, dryRun bool) (diff.Changelog, error) {
 	if am.err != nil {
@@ -26,6 +26,14 @@ func (cm *ChannelMock) Get(ctx context.Context, scope, name string) (*meta.Chann
 	return &meta.Channel{}, nil
 }
 
+// List is the channelmock List
+func (cm *ChannelMock) List(ctx context.Context, scope, selector string) ([]meta.Channel, error) {
+	if cm.err != nil {
+		return nil, cm.err
+	}
+	return []meta.Channel{}, nil
+}
+
 // Create is the channelmock Create
 func (cm *ChannelMock) Create(ctx context.Context, scope string, ch *meta.Channel, dryRun bool) (diff.Changelog, error) {
 	if cm.err != nil {
@@ -49,3 +57,11 @@ func (cm *ChannelMock) Update(ctx context.Context, scope string, ch *meta.Channe
 	}
 	return diff.Changelog{}, nil
 }
+
+// DeadLetters is the channelmock DeadLetters
+func (cm *ChannelMock) DeadLetters(ctx context.Context, scope, name string, limit int) ([]meta.DeadLetterMessage, error) {
+	if cm.err != nil {
+		return nil, cm.err
+	}
+	return []meta.DeadLetterMessage{}, nil
+}
@@ -1,6 +1,12 @@
 package mocks
 
-import "inspr.dev/inspr/pkg/controller"
+import (
+	"context"
+
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/controller"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+)
 
 //ClientMock test asset for mocking a controller
 type ClientMock struct {
@@ -29,6 +35,16 @@ func (cm *ClientMock) Types() controller.TypeInterface {
 	return NewTypeMock(cm.err)
 }
 
+//Templates mocks a templates controller
+func (cm *ClientMock) Templates() controller.TemplateInterface {
+	return NewTemplateMock(cm.err)
+}
+
+//Instances mocks a template instances controller
+func (cm *ClientMock) Instances() controller.InstanceInterface {
+	return NewInstanceMock(cm.err)
+}
+
 //Authorization mocks a app controller
 func (cm *ClientMock) Authorization() controller.AuthorizationInterface {
 	return NewAuthMock(cm.err)
@@ -43,3 +59,37 @@ func (cm *ClientMock) Alias() controller.AliasInterface {
 func (cm *ClientMock) Brokers() controller.BrokersInterface {
 	return NewBrokersMock(cm.err)
 }
+
+//Revisions mocks revisions controller
+func (cm *ClientMock) Revisions() controller.RevisionInterface {
+	return NewRevisionMock(cm.err)
+}
+
+//Snapshots mocks snapshots controller
+func (cm *ClientMock) Snapshots() controller.SnapshotInterface {
+	return NewSnapshotMock(cm.err)
+}
+
+//Status mocks status controller
+func (cm *ClientMock) Status() controller.StatusInterface {
+	return NewStatusMock(cm.err)
+}
+
+//Graph mocks graph controller
+func (cm *ClientMock) Graph() controller.GraphInterface {
+	return NewGraphMock(cm.err)
+}
+
+//Watch mocks a watch on the controller, the returned channel is
+//closed once the context is done
+func (cm *ClientMock) Watch(ctx context.Context, scope string, kinds diff.Kind) (<-chan models.WatchEvent, error) {
+	if cm.err != nil {
+		return nil, cm.err
+	}
+	events := make(chan models.WatchEvent)
+	go func() {
+		<-ctx.Done()
+		close(events)
+	}()
+	return events, nil
+}
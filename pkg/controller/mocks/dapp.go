@@ -26,6 +26,14 @@ func (am *AppMock) Get(ctx context.Context, scope string) (*meta.App, error) {
 	return &meta.App{}, nil
 }
 
+// List is the AppMock List
+func (am *AppMock) List(ctx context.Context, scope, selector string) ([]meta.App, error) {
+	if am.err != nil {
+		return nil, am.err
+	}
+	return []meta.App{}, nil
+}
+
 // Create is the AppMock Create
 func (am *AppMock) Create(ctx context.Context, scope string, app *meta.App, dryRun bool) (diff.Changelog, error) {
 	if am.err != nil {
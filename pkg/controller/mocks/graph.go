@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"context"
+
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/controller"
+)
+
+// GraphMock mock structure for the operations of the controller.Graph()
+type GraphMock struct {
+	err error
+}
+
+// NewGraphMock exports a mock of the Graph.interface
+func NewGraphMock(err error) controller.GraphInterface {
+	return &GraphMock{err: err}
+}
+
+// Get is the GraphMock Get
+func (gm *GraphMock) Get(ctx context.Context, scope string) (*models.Graph, error) {
+	if gm.err != nil {
+		return nil, gm.err
+	}
+	return &models.Graph{Vertices: []models.GraphVertex{}, Edges: []models.GraphEdge{}}, nil
+}
+
+// Render is the GraphMock Render
+func (gm *GraphMock) Render(ctx context.Context, scope, format string) (string, error) {
+	if gm.err != nil {
+		return "", gm.err
+	}
+	return "", nil
+}
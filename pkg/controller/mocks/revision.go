@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"context"
+
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/controller"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+)
+
+// RevisionMock mock structure for the operations of the controller.Revisions()
+type RevisionMock struct {
+	err error
+}
+
+// NewRevisionMock exports a mock of the Revision.interface
+func NewRevisionMock(err error) controller.RevisionInterface {
+	return &RevisionMock{err: err}
+}
+
+// History is the RevisionMock History
+func (rm *RevisionMock) History(ctx context.Context) ([]models.Revision, error) {
+	if rm.err != nil {
+		return nil, rm.err
+	}
+	return []models.Revision{}, nil
+}
+
+// Rollback is the RevisionMock Rollback
+func (rm *RevisionMock) Rollback(ctx context.Context, revision int, dryRun bool) (diff.Changelog, error) {
+	if rm.err != nil {
+		return diff.Changelog{}, rm.err
+	}
+	return diff.Changelog{}, nil
+}
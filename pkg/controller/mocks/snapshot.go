@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"context"
+
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/controller"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+)
+
+// SnapshotMock mock structure for the operations of the controller.Snapshots()
+type SnapshotMock struct {
+	err error
+}
+
+// NewSnapshotMock exports a mock of the Snapshot.interface
+func NewSnapshotMock(err error) controller.SnapshotInterface {
+	return &SnapshotMock{err: err}
+}
+
+// Export is the SnapshotMock Export
+func (sm *SnapshotMock) Export(ctx context.Context, scope string) (*models.Snapshot, error) {
+	if sm.err != nil {
+		return nil, sm.err
+	}
+	return &models.Snapshot{Scope: scope, App: &meta.App{}}, nil
+}
+
+// Import is the SnapshotMock Import
+func (sm *SnapshotMock) Import(ctx context.Context, snapshot *models.Snapshot, dryRun bool) (diff.Changelog, error) {
+	if sm.err != nil {
+		return diff.Changelog{}, sm.err
+	}
+	return diff.Changelog{}, nil
+}
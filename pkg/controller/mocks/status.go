@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"context"
+
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/controller"
+)
+
+// StatusMock mock structure for the operations of the controller.Status()
+type StatusMock struct {
+	err error
+}
+
+// NewStatusMock exports a mock of the Status.interface
+func NewStatusMock(err error) controller.StatusInterface {
+	return &StatusMock{err: err}
+}
+
+// Get is the StatusMock Get
+func (sm *StatusMock) Get(ctx context.Context, scope string) (*models.DriftReport, error) {
+	if sm.err != nil {
+		return nil, sm.err
+	}
+	return &models.DriftReport{Drifts: []models.Drift{}}, nil
+}
+
+// Reconcile is the StatusMock Reconcile
+func (sm *StatusMock) Reconcile(ctx context.Context, scope string, repair bool) (*models.DriftReport, error) {
+	if sm.err != nil {
+		return nil, sm.err
+	}
+	return &models.DriftReport{Drifts: []models.Drift{}}, nil
+}
@@ -0,0 +1,94 @@
+package mocks
+
+import (
+	"context"
+
+	"inspr.dev/inspr/pkg/api/models"
+	"inspr.dev/inspr/pkg/controller"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/meta/utils/diff"
+)
+
+// TemplateMock mock structure for the operations of the controller.Templates()
+type TemplateMock struct {
+	err error
+}
+
+// NewTemplateMock exports a mock of the Template.interface
+func NewTemplateMock(err error) controller.TemplateInterface {
+	return &TemplateMock{err: err}
+}
+
+// Get is the TemplateMock Get
+func (tm *TemplateMock) Get(ctx context.Context, scope, name string) (*models.TemplateDO, error) {
+	if tm.err != nil {
+		return nil, tm.err
+	}
+	return &models.TemplateDO{}, nil
+}
+
+// Create is the TemplateMock Create
+func (tm *TemplateMock) Create(ctx context.Context, scope string, t *meta.Template, dryRun bool) (diff.Changelog, error) {
+	if tm.err != nil {
+		return diff.Changelog{}, tm.err
+	}
+	return diff.Changelog{}, nil
+}
+
+// Delete is the TemplateMock Delete
+func (tm *TemplateMock) Delete(ctx context.Context, scope, name string, dryRun bool) (diff.Changelog, error) {
+	if tm.err != nil {
+		return diff.Changelog{}, tm.err
+	}
+	return diff.Changelog{}, nil
+}
+
+// Update is the TemplateMock Update
+func (tm *TemplateMock) Update(ctx context.Context, scope string, t *meta.Template, dryRun bool) (diff.Changelog, error) {
+	if tm.err != nil {
+		return diff.Changelog{}, tm.err
+	}
+	return diff.Changelog{}, nil
+}
+
+// InstanceMock mock structure for the operations of the controller.Instances()
+type InstanceMock struct {
+	err error
+}
+
+// NewInstanceMock exports a mock of the Instance.interface
+func NewInstanceMock(err error) controller.InstanceInterface {
+	return &InstanceMock{err: err}
+}
+
+// Get is the InstanceMock Get
+func (im *InstanceMock) Get(ctx context.Context, scope, name string) (*meta.TemplateInstance, error) {
+	if im.err != nil {
+		return nil, im.err
+	}
+	return &meta.TemplateInstance{}, nil
+}
+
+// Create is the InstanceMock Create
+func (im *InstanceMock) Create(ctx context.Context, scope string, instance *meta.TemplateInstance, dryRun bool) (diff.Changelog, error) {
+	if im.err != nil {
+		return diff.Changelog{}, im.err
+	}
+	return diff.Changelog{}, nil
+}
+
+// Delete is the InstanceMock Delete
+func (im *InstanceMock) Delete(ctx context.Context, scope, name string, dryRun bool) (diff.Changelog, error) {
+	if im.err != nil {
+		return diff.Changelog{}, im.err
+	}
+	return diff.Changelog{}, nil
+}
+
+// Update is the InstanceMock Update
+func (im *InstanceMock) Update(ctx context.Context, scope string, instance *meta.TemplateInstance, dryRun bool) (diff.Changelog, error) {
+	if im.err != nil {
+		return diff.Changelog{}, im.err
+	}
+	return diff.Changelog{}, nil
+}
@@ -26,6 +26,14 @@ func (tm *TypeMock) Get(ctx context.Context, scope, ctName string) (*meta.Type,
 	return &meta.Type{}, nil
 }
 
+// List is the TypeMock List
+func (tm *TypeMock) List(ctx context.Context, scope, selector string) ([]meta.Type, error) {
+	if tm.err != nil {
+		return nil, tm.err
+	}
+	return []meta.Type{}, nil
+}
+
 // Create is the TypeMock Create
 func (tm *TypeMock) Create(ctx context.Context, scope string, ct *meta.Type, dryRun bool) (diff.Changelog, error) {
 	if tm.err != nil {
@@ -29,6 +29,7 @@ func GetEnvironment() *InsprEnvVars {
 		env = &InsprEnvVars{
 			InputChannels:    getRawInputChannels(),
 			OutputChannels:   getRawInputChannels(),
+			UnixSocketAddr:   GetUnixSocketAddr(),
 			SidecarImage:     GetSidecarImage(),
 			InsprAppContext:  GetInsprAppScope(),
 			InsprEnvironment: GetInsprEnvironment(),
@@ -52,6 +53,7 @@ func RefreshEnviromentVariables() *InsprEnvVars {
 	env = &InsprEnvVars{
 		InputChannels:    getRawInputChannels(),
 		OutputChannels:   getRawOutputChannels(),
+		UnixSocketAddr:   GetUnixSocketAddr(),
 		SidecarImage:     GetSidecarImage(),
 		InsprAppContext:  GetInsprAppScope(),
 		InsprEnvironment: GetInsprEnvironment(),
@@ -129,6 +131,14 @@ func GetSidecarImage() string {
 	return getEnv("INSPR_LBSIDECAR_IMAGE")
 }
 
+// GetUnixSocketAddr returns environment variable which contains the directory
+// shared by the node and its load balancer sidecar, in which they create the
+// Unix sockets they communicate through. It's empty when they communicate
+// through localhost ports
+func GetUnixSocketAddr() string {
+	return os.Getenv("INSPR_UNIX_SOCKET")
+}
+
 // GetInsprAppScope returns environment variable which contains the current
 // dApp context
 func GetInsprAppScope() string {
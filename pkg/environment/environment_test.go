@@ -114,6 +114,7 @@ func TestRefreshEnviromentVariables(t *testing.T) {
 			want: &InsprEnvVars{
 				InputChannels:    "one",
 				OutputChannels:   "two",
+				UnixSocketAddr:   "three",
 				InsprAppContext:  "four",
 				InsprEnvironment: "five",
 				SidecarImage:     "seven",
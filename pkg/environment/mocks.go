@@ -34,4 +34,5 @@ func UnsetMockEnv() {
 	os.Unsetenv("INSPR_SIDECAR_TEST_WRITE_PORT")
 	os.Unsetenv("INSPR_SIDECAR_TEST_READ_PORT")
 	os.Unsetenv("INSPR_SIDECAR_TEST_ADDR")
+	os.Unsetenv("INSPR_UNIX_SOCKET")
 }
@@ -1,7 +1,9 @@
 package environment
 
 import (
+	"encoding/json"
 	"os"
+	"strconv"
 	"strings"
 
 	"inspr.dev/inspr/pkg/ierrors"
@@ -46,6 +48,128 @@ func GetSchema(channel string) (string, error) {
 	return schema, nil
 }
 
+// GetSchemaFormat returns the format of the schema of a channel, which
+// is Avro when the channel doesn't define one
+func GetSchemaFormat(channel string) string {
+	if format := os.Getenv(channel + "_SCHEMA_FORMAT"); format != "" {
+		return format
+	}
+	return meta.FormatAvro
+}
+
+// GetSchemaVersion returns the version of the schema of a channel, which
+// is 0 when the schema of the channel isn't versioned
+func GetSchemaVersion(channel string) int {
+	version, _ := strconv.Atoi(os.Getenv(channel + "_SCHEMA_VERSION"))
+	return version
+}
+
+// GetSchemaHistory returns every version of the schema of a channel's Type,
+// oldest first, or nil when the schema of the channel isn't versioned
+func GetSchemaHistory(channel string) ([]meta.TypeVersion, error) {
+	value, ok := os.LookupEnv(channel + "_SCHEMA_HISTORY")
+	if !ok {
+		return nil, nil
+	}
+
+	history := []meta.TypeVersion{}
+	if err := json.Unmarshal([]byte(value), &history); err != nil {
+		return nil, ierrors.New(
+			"invalid schema history for channel %s: %v", channel, err,
+		).BadRequest()
+	}
+	return history, nil
+}
+
+// DeadLetterSuffix is appended to an input channel to name the output channel
+// its undeliverable messages are sent to. Channel names can't contain dots,
+// so it never collides with a boundary of the dApp
+const DeadLetterSuffix = ".deadletter"
+
+// IsDeadLetterChannel returns whether the channel is the dead-letter
+// channel of an input channel, written to by the sidecar only
+func IsDeadLetterChannel(channel string) bool {
+	return strings.HasSuffix(channel, DeadLetterSuffix)
+}
+
+// GetDeadLetter returns the dead-letter configuration of an input channel,
+// with the name its dead-letter channel is resolved with, or nil when
+// the channel has none
+func GetDeadLetter(channel string) *meta.DeadLetter {
+	deadLetter, ok := os.LookupEnv(channel + "_DEADLETTER")
+	if !ok || deadLetter == "" {
+		return nil
+	}
+
+	attempts, _ := strconv.Atoi(os.Getenv(channel + "_DEADLETTER_ATTEMPTS"))
+	if attempts <= 0 {
+		attempts = meta.DefaultDeadLetterAttempts
+	}
+	return &meta.DeadLetter{
+		Channel:     deadLetter,
+		MaxAttempts: attempts,
+	}
+}
+
+// GetConcurrency returns the concurrency of an input channel, or nil when
+// its messages are delivered one at a time
+func GetConcurrency(channel string) *meta.Concurrency {
+	maxInFlight, _ := strconv.Atoi(os.Getenv(channel + "_CONCURRENCY"))
+	if maxInFlight <= 1 {
+		return nil
+	}
+	return &meta.Concurrency{
+		MaxInFlight: maxInFlight,
+		OrderingKey: os.Getenv(channel + "_ORDERING_KEY"),
+	}
+}
+
+// GetBatch returns the batch configuration of an input channel, with the
+// default linger when it isn't defined, or nil when its messages aren't
+// delivered in batches
+func GetBatch(channel string) *meta.Batch {
+	maxSize, _ := strconv.Atoi(os.Getenv(channel + "_BATCH_SIZE"))
+	if maxSize <= 1 {
+		return nil
+	}
+
+	linger := os.Getenv(channel + "_BATCH_LINGER")
+	if linger == "" {
+		linger = meta.DefaultBatchLinger
+	}
+	return &meta.Batch{
+		MaxSize: maxSize,
+		Linger:  linger,
+	}
+}
+
+// GetDeliveryPolicy returns the delivery policy of an input channel, with the
+// defaults of the fields it doesn't define, or nil when the channel has none
+func GetDeliveryPolicy(channel string) (*meta.DeliveryPolicy, error) {
+	value, ok := os.LookupEnv(channel + "_DELIVERY_POLICY")
+	if !ok || value == "" {
+		return nil, nil
+	}
+
+	policy := &meta.DeliveryPolicy{}
+	if err := json.Unmarshal([]byte(value), policy); err != nil {
+		return nil, ierrors.New(
+			"invalid delivery policy for channel %s: %v", channel, err,
+		).BadRequest()
+	}
+
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = meta.DefaultDeliveryAttempts
+	}
+	if policy.InitialBackoff == "" {
+		policy.InitialBackoff = meta.DefaultDeliveryBackoff
+	}
+	if policy.MaxBackoff == "" {
+		policy.MaxBackoff = meta.DefaultDeliveryMaxBackoff
+	}
+	return policy, nil
+}
+
 // OutputChannelList returns a list of input channels
 func OutputChannelList() utils.StringArray {
 	return GetChannelBoundaryList(GetOutputChannelsData())
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"testing"
 
+	"inspr.dev/inspr/pkg/meta"
 	"inspr.dev/inspr/pkg/meta/brokers"
 	"inspr.dev/inspr/pkg/utils"
 )
@@ -247,3 +248,191 @@ func TestGetResolvedChannel(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDeadLetter(t *testing.T) {
+	os.Setenv("ch1_DEADLETTER", "ch1.deadletter")
+	os.Setenv("ch1_DEADLETTER_ATTEMPTS", "5")
+	os.Setenv("ch2_DEADLETTER", "ch2.deadletter")
+	defer func() {
+		os.Unsetenv("ch1_DEADLETTER")
+		os.Unsetenv("ch1_DEADLETTER_ATTEMPTS")
+		os.Unsetenv("ch2_DEADLETTER")
+	}()
+
+	tests := []struct {
+		name    string
+		channel string
+		want    *meta.DeadLetter
+	}{
+		{
+			name:    "Returns dead-letter configuration",
+			channel: "ch1",
+			want:    &meta.DeadLetter{Channel: "ch1.deadletter", MaxAttempts: 5},
+		},
+		{
+			name:    "Defaults the attempts",
+			channel: "ch2",
+			want:    &meta.DeadLetter{Channel: "ch2.deadletter", MaxAttempts: meta.DefaultDeadLetterAttempts},
+		},
+		{
+			name:    "Channel without dead-letter",
+			channel: "ch3",
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetDeadLetter(tt.channel); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetDeadLetter() = %v, want %v", got, tt.want)
+			}
+			if !IsDeadLetterChannel(tt.channel + DeadLetterSuffix) {
+				t.Errorf("IsDeadLetterChannel() = false, want true")
+			}
+		})
+	}
+}
+
+func TestGetDeliveryPolicy(t *testing.T) {
+	os.Setenv("ch1_DELIVERY_POLICY", `{"maxattempts":5,"initialbackoff":"1s","timeout":"3s"}`)
+	os.Setenv("ch2_DELIVERY_POLICY", `{"maxattempts":`)
+	defer func() {
+		os.Unsetenv("ch1_DELIVERY_POLICY")
+		os.Unsetenv("ch2_DELIVERY_POLICY")
+	}()
+
+	tests := []struct {
+		name    string
+		channel string
+		want    *meta.DeliveryPolicy
+		wantErr bool
+	}{
+		{
+			name:    "Returns delivery policy with defaults",
+			channel: "ch1",
+			want: &meta.DeliveryPolicy{
+				MaxAttempts:    5,
+				InitialBackoff: "1s",
+				MaxBackoff:     meta.DefaultDeliveryMaxBackoff,
+				Timeout:        "3s",
+			},
+		},
+		{
+			name:    "Invalid delivery policy",
+			channel: "ch2",
+			wantErr: true,
+		},
+		{
+			name:    "Channel without delivery policy",
+			channel: "ch3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetDeliveryPolicy(tt.channel)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDeliveryPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetDeliveryPolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetConcurrency(t *testing.T) {
+	env := map[string]string{
+		"ch1_CONCURRENCY":  "8",
+		"ch1_ORDERING_KEY": "user.id",
+		"ch2_CONCURRENCY":  "4",
+		"ch3_CONCURRENCY":  "1",
+	}
+	for key, value := range env {
+		os.Setenv(key, value)
+	}
+	defer func() {
+		for key := range env {
+			os.Unsetenv(key)
+		}
+	}()
+
+	tests := []struct {
+		name    string
+		channel string
+		want    *meta.Concurrency
+	}{
+		{
+			name:    "Returns concurrency with ordering key",
+			channel: "ch1",
+			want:    &meta.Concurrency{MaxInFlight: 8, OrderingKey: "user.id"},
+		},
+		{
+			name:    "Returns concurrency without ordering key",
+			channel: "ch2",
+			want:    &meta.Concurrency{MaxInFlight: 4},
+		},
+		{
+			name:    "Channel with a single message in flight",
+			channel: "ch3",
+		},
+		{
+			name:    "Channel without concurrency",
+			channel: "ch4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetConcurrency(tt.channel); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetConcurrency() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetBatch(t *testing.T) {
+	env := map[string]string{
+		"ch1_BATCH_SIZE":   "50",
+		"ch1_BATCH_LINGER": "1s",
+		"ch2_BATCH_SIZE":   "10",
+		"ch3_BATCH_SIZE":   "1",
+	}
+	for key, value := range env {
+		os.Setenv(key, value)
+	}
+	defer func() {
+		for key := range env {
+			os.Unsetenv(key)
+		}
+	}()
+
+	tests := []struct {
+		name    string
+		channel string
+		want    *meta.Batch
+	}{
+		{
+			name:    "Returns batch with linger",
+			channel: "ch1",
+			want:    &meta.Batch{MaxSize: 50, Linger: "1s"},
+		},
+		{
+			name:    "Returns batch with the default linger",
+			channel: "ch2",
+			want:    &meta.Batch{MaxSize: 10, Linger: meta.DefaultBatchLinger},
+		},
+		{
+			name:    "Channel with batches of a single message",
+			channel: "ch3",
+		},
+		{
+			name:    "Channel without batches",
+			channel: "ch4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetBatch(tt.channel); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetBatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -21,4 +21,5 @@ const (
 	Unauthorized
 	Forbidden
 	ExternalPkg
+	Conflict
 )
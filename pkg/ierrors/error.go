@@ -83,8 +83,8 @@ func newIerror(format string, args ...interface{}) *ierror {
 // from is the func to create an ierror structure using as a base the an error
 // interface
 func from(err error) *ierror {
-	ierr, ok := err.(*ierror)
-	if !ok {
+	var ierr *ierror
+	if !errors.As(err, &ierr) {
 		ierr = &ierror{
 			err:  err,
 			code: Unknown,
@@ -352,6 +352,12 @@ func (ie *ierror) Unauthorized() *ierror {
 	return ie
 }
 
+// Conflict adds Conflict code to Inspr Error
+func (ie *ierror) Conflict() *ierror {
+	ie.code = Conflict
+	return ie
+}
+
 // ExternalErr adds ExternalPkgError code to Inspr Error
 func (ie *ierror) ExternalErr() *ierror {
 	ie.code = ExternalPkg
@@ -703,6 +703,16 @@ func TestErrCode(t *testing.T) {
 			},
 			want: Unauthorized,
 		},
+		{
+			name: "It should add the code Conflict the new error",
+			fields: fields{
+				err: New(""),
+			},
+			exec: func(e *ierror) *ierror {
+				return e.Conflict()
+			},
+			want: Conflict,
+		},
 		{
 			name: "It should add the code ExternalErr the new error",
 			fields: fields{
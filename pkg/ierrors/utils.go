@@ -1,10 +1,13 @@
 package ierrors
 
-// HasCode tries to convert the error interface to an ierror structure and then
-// assert if the structure contains the ErrCode provided as an argument
+import "errors"
+
+// HasCode tries to convert the error interface, or the errors it wraps, to an
+// ierror structure and then assert if the structure contains the ErrCode
+// provided as an argument
 func HasCode(err error, code ErrCode) bool {
-	e, ok := err.(*ierror)
-	if !ok {
+	var e *ierror
+	if !errors.As(err, &e) {
 		return false
 	}
 	return (code & e.code) > 0
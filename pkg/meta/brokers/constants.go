@@ -2,10 +2,12 @@ package brokers
 
 // All possible broker names supported by Inspr
 const (
-	Kafka string = "kafka"
+	Kafka  string = "kafka"
+	Memory string = "memory"
 )
 
 // SupportedBrokers is a list of all supported brokers
 var SupportedBrokers = []string{
 	Kafka,
+	Memory,
 }
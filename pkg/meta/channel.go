@@ -1,6 +1,10 @@
 package meta
 
-import "inspr.dev/inspr/pkg/utils"
+import (
+	"time"
+
+	"inspr.dev/inspr/pkg/utils"
+)
 
 // Channel is an Inspr component that represents a Channel.
 type Channel struct {
@@ -10,9 +14,86 @@ type Channel struct {
 }
 
 // ChannelSpec is the specification of a channel.
-// 'Type' string references a Type structure name
+// 'Type' string references a Type structure name and 'TypeVersion' pins
+// the version of its schema that is used, the latest one when it's 0.
+// 'DeadLetter' defines where the messages that can't be delivered go and
+// 'DeliveryPolicy' how the deliveries of its messages are retried,
+// 'Concurrency' how many of them are handled by a node at the same time
+// and 'Batch' how they are grouped into batches delivered at once
 type ChannelSpec struct {
-	Type               string   `yaml:"type,omitempty"  json:"type" `
-	BrokerPriorityList []string `yaml:"brokerlist,omitempty" json:"brokerlist"`
-	SelectedBroker     string   `yaml:"selectedbroker,omitempty" json:"selectedbroker"`
+	Type               string          `yaml:"type,omitempty"  json:"type" `
+	TypeVersion        int             `yaml:"typeversion,omitempty" json:"typeversion,omitempty"`
+	BrokerPriorityList []string        `yaml:"brokerlist,omitempty" json:"brokerlist"`
+	SelectedBroker     string          `yaml:"selectedbroker,omitempty" json:"selectedbroker"`
+	DeadLetter         *DeadLetter     `yaml:"deadletter,omitempty" json:"deadletter,omitempty"`
+	DeliveryPolicy     *DeliveryPolicy `yaml:"deliverypolicy,omitempty" json:"deliverypolicy,omitempty"`
+	Concurrency        *Concurrency    `yaml:"concurrency,omitempty" json:"concurrency,omitempty"`
+	Batch              *Batch          `yaml:"batch,omitempty" json:"batch,omitempty"`
+}
+
+// DefaultDeadLetterAttempts is the amount of failed deliveries of a message
+// after which it's sent to the dead-letter channel, when it isn't defined
+const DefaultDeadLetterAttempts = 3
+
+// DeadLetter is the dead-letter configuration of a channel. The messages read
+// from the channel that can't be delivered to a node after 'MaxAttempts' tries
+// are sent to 'Channel', a channel of the same dApp, and skipped
+type DeadLetter struct {
+	Channel     string `yaml:"channel" json:"channel"`
+	MaxAttempts int    `yaml:"maxattempts,omitempty" json:"maxattempts,omitempty"`
+}
+
+// Defaults of the fields of delivery policies that aren't defined
+const (
+	DefaultDeliveryAttempts   = 3
+	DefaultDeliveryBackoff    = "100ms"
+	DefaultDeliveryMaxBackoff = "10s"
+)
+
+// DeliveryPolicy is how the load balancer sidecar retries reading the messages
+// of a channel from its broker and delivering them to a node. Each operation
+// is tried up to 'MaxAttempts' times, waiting between the attempts for a time
+// that starts at 'InitialBackoff' and doubles up to 'MaxBackoff', with jitter.
+// 'Timeout' limits each attempt of delivering a message to the node, which
+// isn't limited when it's empty. Durations are in the format of Go, as "1.5s"
+type DeliveryPolicy struct {
+	MaxAttempts    int    `yaml:"maxattempts,omitempty" json:"maxattempts,omitempty"`
+	InitialBackoff string `yaml:"initialbackoff,omitempty" json:"initialbackoff,omitempty"`
+	MaxBackoff     string `yaml:"maxbackoff,omitempty" json:"maxbackoff,omitempty"`
+	Timeout        string `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+// Concurrency is how many messages of a channel the load balancer sidecar
+// delivers to a node at the same time, up to 'MaxInFlight'. The messages are
+// still committed in the order they were read. When 'OrderingKey' is set,
+// the messages with the same value in that field, a path such as "user.id",
+// are delivered one at a time in the order they were read
+type Concurrency struct {
+	MaxInFlight int    `yaml:"maxinflight" json:"maxinflight"`
+	OrderingKey string `yaml:"orderingkey,omitempty" json:"orderingkey,omitempty"`
+}
+
+// DefaultBatchLinger is how long the load balancer sidecar waits for the
+// messages of a batch when the linger of the batch isn't defined
+const DefaultBatchLinger = "100ms"
+
+// Batch is how the load balancer sidecar groups the messages of a channel
+// into batches that are delivered to a node in a single request. A batch is
+// delivered once it has 'MaxSize' messages or 'Linger' has passed since its
+// first message was read, in the format of Go durations such as "250ms"
+type Batch struct {
+	MaxSize int    `yaml:"maxsize" json:"maxsize"`
+	Linger  string `yaml:"linger,omitempty" json:"linger,omitempty"`
+}
+
+// DeadLetterMessage is a message sent to a dead-letter channel, with
+// the raw message that couldn't be delivered and why it failed
+type DeadLetterMessage struct {
+	Channel         string    `json:"channel"`
+	ResolvedChannel string    `json:"resolvedchannel"`
+	Node            string    `json:"node"`
+	Error           string    `json:"error"`
+	Attempts        int       `json:"attempts"`
+	FailedAt        time.Time `json:"failedat"`
+	Message         []byte    `json:"message"`
 }
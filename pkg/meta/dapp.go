@@ -14,10 +14,18 @@ type NodePort struct {
 	TargetPort int `yaml:"targetPort" json:"targetPort"`
 }
 
-// SidecarPort represents the port for communication between node and load balancer sidecar
+// Transports through which a node communicates with its load balancer sidecar
+const (
+	TransportHTTP = "http"
+	TransportGRPC = "grpc"
+)
+
+// SidecarPort represents the port for communication between node and load balancer sidecar.
+// The transport is either http, the default, or grpc
 type SidecarPort struct {
-	LBRead  int `json:"lbRead"`
-	LBWrite int `json:"lbWrite"`
+	LBRead    int    `json:"lbRead"`
+	LBWrite   int    `json:"lbWrite"`
+	Transport string `json:"transport,omitempty"`
 }
 
 // NodeSpec represents a configuration for a node. The image represents the Docker image for the main container of the Node.
@@ -53,15 +61,16 @@ type Boundary struct {
 //
 // The boundary represent the possible connections to other apps, and the fields that can be overriten when instantiating the app.
 type AppSpec struct {
-	Node     Node                        `yaml:"node,omitempty"   json:"node"`
-	Apps     map[string]*App             `yaml:"apps,omitempty"   json:"apps"`
-	Channels map[string]*Channel         `yaml:"channels,omitempty"   json:"channels"`
-	Types    map[string]*Type            `yaml:"types,omitempty"   json:"types"`
-	Aliases  map[string]*Alias           `yaml:"aliases"   json:"aliases"`
-	Boundary AppBoundary                 `yaml:"boundary,omitempty"   json:"boundary"`
-	Auth     AppAuth                     `yaml:"auth"  json:"auth"`
-	LogLevel string                      `yaml:"logLevel" json:"logLevel"`
-	Routes   map[string]*RouteConnection `yaml:"routes,omitempty" json:"routes"`
+	Node      Node                        `yaml:"node,omitempty"   json:"node"`
+	Apps      map[string]*App             `yaml:"apps,omitempty"   json:"apps"`
+	Channels  map[string]*Channel         `yaml:"channels,omitempty"   json:"channels"`
+	Types     map[string]*Type            `yaml:"types,omitempty"   json:"types"`
+	Aliases   map[string]*Alias           `yaml:"aliases"   json:"aliases"`
+	Templates map[string]*Template        `yaml:"templates,omitempty"   json:"templates,omitempty"`
+	Boundary  AppBoundary                 `yaml:"boundary,omitempty"   json:"boundary"`
+	Auth      AppAuth                     `yaml:"auth"  json:"auth"`
+	LogLevel  string                      `yaml:"logLevel" json:"logLevel"`
+	Routes    map[string]*RouteConnection `yaml:"routes,omitempty" json:"routes"`
 }
 
 // AppAuth represents the permissions that a dApp (and its children) contains
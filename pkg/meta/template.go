@@ -0,0 +1,38 @@
+package meta
+
+// Types of the parameters of a Template
+const (
+	ParameterString = "string"
+	ParameterInt    = "int"
+	ParameterBool   = "bool"
+)
+
+// TemplateParameter is a typed value that is given when a Template is instantiated.
+// Type is one of string, int or bool, and defaults to string. Parameters that
+// aren't required take their Default value when they are not given.
+type TemplateParameter struct {
+	Name     string `yaml:"name" json:"name"`
+	Type     string `yaml:"type,omitempty" json:"type"`
+	Default  string `yaml:"default,omitempty" json:"default"`
+	Required bool   `yaml:"required,omitempty" json:"required"`
+}
+
+// Template is an inspr component that defines the shape of a dApp that can be
+// instantiated many times, with different values for its parameters.
+//
+// The names, images, environment variables, annotations, boundaries and aliases
+// of the App and of its children can reference the parameters, as in "{{ .customer }}".
+type Template struct {
+	Meta       Metadata            `yaml:"meta,omitempty" json:"meta"`
+	Parameters []TemplateParameter `yaml:"parameters,omitempty" json:"parameters"`
+	App        App                 `yaml:"app,omitempty" json:"app"`
+}
+
+// TemplateInstance is a dApp created from a Template. Meta.Name is the name of
+// the created dApp, Template is the full path of the Template (x.y.template)
+// and Values are the values given to its parameters.
+type TemplateInstance struct {
+	Meta     Metadata          `yaml:"meta,omitempty" json:"meta"`
+	Template string            `yaml:"template" json:"template"`
+	Values   map[string]string `yaml:"values,omitempty" json:"values"`
+}
@@ -5,7 +5,24 @@ package meta
 //
 // Type will be defined via the workspace and instantiated as a string on the cluster
 type Type struct {
-	Meta              Metadata `yaml:"meta,omitempty" json:"meta"`
-	Schema            string   `yaml:"schema,omitempty" json:"schema"`
-	ConnectedChannels []string `yaml:"connectedchannels,omitempty"  json:"connectedchannels"`
+	Meta              Metadata      `yaml:"meta,omitempty" json:"meta"`
+	Format            string        `yaml:"format,omitempty" json:"format,omitempty"`
+	Schema            string        `yaml:"schema,omitempty" json:"schema"`
+	Versions          []TypeVersion `yaml:"versions,omitempty" json:"versions,omitempty"`
+	ConnectedChannels []string      `yaml:"connectedchannels,omitempty"  json:"connectedchannels"`
+}
+
+// Formats of the schemas of Types, Types without a format use Avro schemas
+const (
+	FormatAvro       = "avro"
+	FormatJSONSchema = "jsonschema"
+	FormatProtobuf   = "protobuf"
+)
+
+// TypeVersion is a schema that a Type had, kept so that the data written with it
+// can still be read. Versions are numbered from 1, in the order they were created,
+// and the last one is always the current Schema of the Type
+type TypeVersion struct {
+	Version int    `yaml:"version" json:"version"`
+	Schema  string `yaml:"schema" json:"schema"`
 }
@@ -0,0 +1,320 @@
+package utils
+
+import (
+	"fmt"
+
+	"inspr.dev/inspr/pkg/ierrors"
+)
+
+// ResolveAvroDatum converts a datum decoded with the writer schema, in the native
+// form used by goavro, into the datum that would have been decoded with the
+// reader schema, following the schema resolution rules of the Avro specification
+func ResolveAvroDatum(readerSchema, writerSchema string, datum interface{}) (interface{}, error) {
+	reader, err := parseAvroSchema(readerSchema)
+	if err != nil {
+		return nil, ierrors.Wrap(err, "invalid reader schema")
+	}
+	writer, err := parseAvroSchema(writerSchema)
+	if err != nil {
+		return nil, ierrors.Wrap(err, "invalid writer schema")
+	}
+
+	d := &datumResolver{reader: reader, writer: writer}
+	return d.resolve("", reader.root, "", writer.root, "", datum)
+}
+
+// datumResolver converts data written with the writer schema
+// into data of the reader schema
+type datumResolver struct {
+	reader, writer *avroSchema
+}
+
+func (d *datumResolver) resolve(
+	path string,
+	reader interface{}, readerNs string,
+	writer interface{}, writerNs string,
+	datum interface{},
+) (interface{}, error) {
+	writerKind, writerDef, writerChildNs := d.writer.resolve(writer, writerNs)
+	if writerKind == "union" {
+		branch, value, err := d.writerBranch(path, writer, writerNs, datum)
+		if err != nil {
+			return nil, err
+		}
+		return d.resolve(path, reader, readerNs, branch, writerNs, value)
+	}
+
+	readerKind, readerDef, readerChildNs := d.reader.resolve(reader, readerNs)
+	if readerKind == "union" {
+		for _, branch := range unionBranches(reader) {
+			kind, def, _ := d.reader.resolve(branch, readerNs)
+			if !avroMatches(kind, def, writerKind, writerDef) {
+				continue
+			}
+			value, err := d.resolve(path, branch, readerNs, writer, writerNs, datum)
+			if err != nil || kind == "null" {
+				return nil, err
+			}
+			return map[string]interface{}{d.reader.typeName(branch, readerNs): value}, nil
+		}
+		return nil, resolutionError(path, "%v isn't one of the types of the reader union", writerKind)
+	}
+
+	if !avroMatches(readerKind, readerDef, writerKind, writerDef) {
+		return nil, resolutionError(path, "%v can't be read as %v", writerKind, readerKind)
+	}
+
+	switch readerKind {
+	case "record", "error":
+		record, ok := datum.(map[string]interface{})
+		if !ok {
+			return nil, resolutionError(path, "expected a record, got %T", datum)
+		}
+		return d.resolveRecord(path, readerDef, readerChildNs, writerDef, writerChildNs, record)
+
+	case "enum":
+		symbol, _ := datum.(string)
+		if stringArray(readerDef["symbols"]).Contains(symbol) {
+			return symbol, nil
+		}
+		if def, ok := readerDef["default"].(string); ok {
+			return def, nil
+		}
+		return nil, resolutionError(path, "symbol '%v' isn't defined in the reader schema", symbol)
+
+	case "array":
+		items, _ := datum.([]interface{})
+		ret := make([]interface{}, len(items))
+		for i, item := range items {
+			value, err := d.resolve(path+"[]", readerDef["items"], readerChildNs, writerDef["items"], writerChildNs, item)
+			if err != nil {
+				return nil, err
+			}
+			ret[i] = value
+		}
+		return ret, nil
+
+	case "map":
+		values, _ := datum.(map[string]interface{})
+		ret := make(map[string]interface{}, len(values))
+		for key, item := range values {
+			value, err := d.resolve(path+"{}", readerDef["values"], readerChildNs, writerDef["values"], writerChildNs, item)
+			if err != nil {
+				return nil, err
+			}
+			ret[key] = value
+		}
+		return ret, nil
+	}
+	return promote(readerKind, datum), nil
+}
+
+func (d *datumResolver) resolveRecord(
+	path string,
+	readerDef map[string]interface{}, readerNs string,
+	writerDef map[string]interface{}, writerNs string,
+	record map[string]interface{},
+) (interface{}, error) {
+	if path == "" {
+		path, _ = readerDef["name"].(string)
+	}
+
+	writerFields := map[string]map[string]interface{}{}
+	for _, f := range fields(writerDef) {
+		name, _ := f["name"].(string)
+		writerFields[name] = f
+	}
+
+	ret := map[string]interface{}{}
+	for _, readerField := range fields(readerDef) {
+		name, _ := readerField["name"].(string)
+		fieldPath := path + "." + name
+
+		writerName := name
+		writerField, ok := writerFields[name]
+		for _, alias := range stringArray(readerField["aliases"]) {
+			if ok {
+				break
+			}
+			writerName = alias
+			writerField, ok = writerFields[alias]
+		}
+
+		if ok {
+			value, err := d.resolve(fieldPath, readerField["type"], readerNs, writerField["type"], writerNs, record[writerName])
+			if err != nil {
+				return nil, err
+			}
+			ret[name] = value
+			continue
+		}
+
+		def, hasDefault := readerField["default"]
+		if !hasDefault {
+			return nil, resolutionError(fieldPath, "field isn't written and has no default")
+		}
+		value, err := d.defaultDatum(fieldPath, readerField["type"], readerNs, def)
+		if err != nil {
+			return nil, err
+		}
+		ret[name] = value
+	}
+	return ret, nil
+}
+
+// writerBranch returns the type of the union of the writer schema
+// the datum was written with, and the datum without the union
+func (d *datumResolver) writerBranch(path string, union interface{}, namespace string, datum interface{}) (interface{}, interface{}, error) {
+	name := "null"
+	var value interface{}
+	if wrapped, ok := datum.(map[string]interface{}); ok && len(wrapped) == 1 {
+		for name, value = range wrapped {
+		}
+	}
+
+	for _, branch := range unionBranches(union) {
+		if d.writer.typeName(branch, namespace) == name {
+			return branch, value, nil
+		}
+	}
+	return nil, nil, resolutionError(path, "datum of type %v isn't one of the types of the writer union", name)
+}
+
+// defaultDatum converts the JSON default of a field into the native
+// form of its type. Defaults of unions are of their first type
+func (d *datumResolver) defaultDatum(path string, schema interface{}, namespace string, def interface{}) (interface{}, error) {
+	kind, definition, childNs := d.reader.resolve(schema, namespace)
+	switch kind {
+	case "union":
+		branches := unionBranches(schema)
+		if len(branches) == 0 {
+			return nil, resolutionError(path, "empty union")
+		}
+		branchKind, _, _ := d.reader.resolve(branches[0], namespace)
+		value, err := d.defaultDatum(path, branches[0], namespace, def)
+		if err != nil || branchKind == "null" {
+			return nil, err
+		}
+		return map[string]interface{}{d.reader.typeName(branches[0], namespace): value}, nil
+
+	case "record", "error":
+		values, _ := def.(map[string]interface{})
+		ret := map[string]interface{}{}
+		for _, field := range fields(definition) {
+			name, _ := field["name"].(string)
+			value, ok := values[name]
+			if !ok {
+				value, ok = field["default"]
+			}
+			if !ok {
+				return nil, resolutionError(path+"."+name, "default has no value for the field")
+			}
+			converted, err := d.defaultDatum(path+"."+name, field["type"], childNs, value)
+			if err != nil {
+				return nil, err
+			}
+			ret[name] = converted
+		}
+		return ret, nil
+
+	case "array":
+		items, _ := def.([]interface{})
+		ret := make([]interface{}, len(items))
+		for i, item := range items {
+			value, err := d.defaultDatum(path+"[]", definition["items"], childNs, item)
+			if err != nil {
+				return nil, err
+			}
+			ret[i] = value
+		}
+		return ret, nil
+
+	case "map":
+		values, _ := def.(map[string]interface{})
+		ret := make(map[string]interface{}, len(values))
+		for key, item := range values {
+			value, err := d.defaultDatum(path+"{}", definition["values"], childNs, item)
+			if err != nil {
+				return nil, err
+			}
+			ret[key] = value
+		}
+		return ret, nil
+
+	case "bytes", "fixed":
+		s, _ := def.(string)
+		return []byte(s), nil
+	}
+
+	// JSON numbers are decoded as float64
+	if number, ok := def.(float64); ok {
+		switch kind {
+		case "int":
+			return int32(number), nil
+		case "long":
+			return int64(number), nil
+		case "float":
+			return float32(number), nil
+		}
+	}
+	return def, nil
+}
+
+// typeName returns the name goavro gives to a type in the native form of unions
+func (s *avroSchema) typeName(schema interface{}, namespace string) string {
+	kind, definition, _ := s.resolve(schema, namespace)
+	switch kind {
+	case "record", "error", "enum", "fixed":
+		if name, ok := schema.(string); ok {
+			if _, defined := s.names[name]; !defined && namespace != "" {
+				return namespace + "." + name
+			}
+			return name
+		}
+		name, _ := fullName(definition, namespace)
+		return name
+	}
+	return kind
+}
+
+// promote converts a primitive datum into the type of the reader
+func promote(readerKind string, datum interface{}) interface{} {
+	switch v := datum.(type) {
+	case int32:
+		switch readerKind {
+		case "long":
+			return int64(v)
+		case "float":
+			return float32(v)
+		case "double":
+			return float64(v)
+		}
+	case int64:
+		switch readerKind {
+		case "float":
+			return float32(v)
+		case "double":
+			return float64(v)
+		}
+	case float32:
+		if readerKind == "double" {
+			return float64(v)
+		}
+	case string:
+		if readerKind == "bytes" {
+			return []byte(v)
+		}
+	case []byte:
+		if readerKind == "string" {
+			return string(v)
+		}
+	}
+	return datum
+}
+
+func resolutionError(path, format string, args ...interface{}) error {
+	if path == "" {
+		path = "<root>"
+	}
+	return ierrors.New("%v: %v", path, fmt.Sprintf(format, args...)).BadRequest()
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolveAvroDatum(t *testing.T) {
+	tests := []struct {
+		name    string
+		reader  string
+		writer  string
+		datum   interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name:   "promoted primitive",
+			reader: `"double"`,
+			writer: `"int"`,
+			datum:  int32(3),
+			want:   float64(3),
+		},
+		{
+			name:   "field with a default and renamed field",
+			reader: `{"type":"record","name":"User","namespace":"inspr","fields":[
+				{"name":"fullname","type":"string","aliases":["name"]},
+				{"name":"tags","type":{"type":"array","items":"string"},"default":["new"]},
+				{"name":"score","type":"long","default":10}
+			]}`,
+			writer: `{"type":"record","name":"User","namespace":"inspr","fields":[
+				{"name":"name","type":"string"},
+				{"name":"age","type":"int"}
+			]}`,
+			datum: map[string]interface{}{"name": "john", "age": int32(42)},
+			want: map[string]interface{}{
+				"fullname": "john",
+				"tags":     []interface{}{"new"},
+				"score":    int64(10),
+			},
+		},
+		{
+			name:   "writer union read as a wider union",
+			reader: `{"type":"record","name":"R","fields":[{"name":"v","type":["null","long","string"]}]}`,
+			writer: `{"type":"record","name":"R","fields":[{"name":"v","type":["null","int"]}]}`,
+			datum:  map[string]interface{}{"v": map[string]interface{}{"int": int32(7)}},
+			want:   map[string]interface{}{"v": map[string]interface{}{"long": int64(7)}},
+		},
+		{
+			name:   "named types in unions",
+			reader: `{"type":"record","name":"R","namespace":"ns","fields":[
+				{"name":"s","type":["null",{"type":"enum","name":"Status","symbols":["ON","OFF"]}]}
+			]}`,
+			writer: `{"type":"record","name":"R","namespace":"ns","fields":[
+				{"name":"s","type":{"type":"enum","name":"Status","symbols":["ON"]}}
+			]}`,
+			datum: map[string]interface{}{"s": "ON"},
+			want:  map[string]interface{}{"s": map[string]interface{}{"ns.Status": "ON"}},
+		},
+		{
+			name:   "enum symbol with the default of the reader",
+			reader: `{"type":"enum","name":"Status","symbols":["ON","UNKNOWN"],"default":"UNKNOWN"}`,
+			writer: `{"type":"enum","name":"Status","symbols":["ON","OFF"]}`,
+			datum:  "OFF",
+			want:   "UNKNOWN",
+		},
+		{
+			name:    "field without a default",
+			reader:  `{"type":"record","name":"R","fields":[{"name":"a","type":"int"}]}`,
+			writer:  `{"type":"record","name":"R","fields":[]}`,
+			datum:   map[string]interface{}{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveAvroDatum(tt.reader, tt.writer, tt.datum)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveAvroDatum() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResolveAvroDatum() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
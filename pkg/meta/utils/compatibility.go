@@ -0,0 +1,500 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/linkedin/goavro"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/utils"
+)
+
+// SchemaCompatibilityAnnotation is the annotation of a Type that defines how
+// updates of its schema are checked against the schema it had before
+const SchemaCompatibilityAnnotation = "inspr.dev/schema-compatibility"
+
+// Compatibility modes of the schemas of Types
+const (
+	// CompatibilityBackward makes sure that consumers using the new schema
+	// can read data written with the old one
+	CompatibilityBackward = "backward"
+	// CompatibilityForward makes sure that consumers using the old schema
+	// can read data written with the new one
+	CompatibilityForward = "forward"
+	// CompatibilityFull is both backward and forward compatibility
+	CompatibilityFull = "full"
+	// CompatibilityNone disables the compatibility checks. It's the default mode
+	CompatibilityNone = "none"
+)
+
+// CompatibilityOf returns the compatibility mode defined in the annotations
+// of the given Type. Types are only checked when they opt in, so the mode is
+// none when it isn't defined
+func CompatibilityOf(t *meta.Type) (string, error) {
+	mode, ok := t.Meta.Annotations[SchemaCompatibilityAnnotation]
+	if !ok || mode == "" {
+		return CompatibilityNone, nil
+	}
+
+	switch mode {
+	case CompatibilityBackward, CompatibilityForward, CompatibilityFull, CompatibilityNone:
+		return mode, nil
+	}
+	return "", ierrors.New(
+		"invalid schema compatibility '%v', must be one of %v, %v, %v or %v",
+		mode, CompatibilityBackward, CompatibilityForward, CompatibilityFull, CompatibilityNone,
+	).BadRequest()
+}
+
+// SchemaIncompatibilities returns the changes from the old to the new Avro
+// schema that break the given compatibility mode, following the schema
+// resolution rules of the Avro specification
+func SchemaIncompatibilities(mode, oldSchema, newSchema string) ([]string, error) {
+	oldAvro, err := parseAvroSchema(oldSchema)
+	if err != nil {
+		return nil, ierrors.Wrap(err, "invalid current schema")
+	}
+	newAvro, err := parseAvroSchema(newSchema)
+	if err != nil {
+		return nil, ierrors.Wrap(err, "invalid new schema")
+	}
+
+	incompatibilities := []string{}
+	if mode == CompatibilityBackward || mode == CompatibilityFull {
+		incompatibilities = append(incompatibilities,
+			newSchemaResolver(newAvro, "new", oldAvro, "old").resolve()...)
+	}
+	if mode == CompatibilityForward || mode == CompatibilityFull {
+		incompatibilities = append(incompatibilities,
+			newSchemaResolver(oldAvro, "old", newAvro, "new").resolve()...)
+	}
+	return incompatibilities, nil
+}
+
+// TypeFormat returns the format of the schema of a Type
+func TypeFormat(t *meta.Type) string {
+	if t.Format == "" {
+		return meta.FormatAvro
+	}
+	return t.Format
+}
+
+// UpdateCompatibility returns the compatibility mode an update of a Type is
+// checked in, which is the stricter of the modes of the current and the
+// updated Type. That way an update can't skip its check by lowering the mode
+// along with the schema; the mode can only be lowered by an update that
+// leaves the schema as it is
+func UpdateCompatibility(current, updated *meta.Type) (string, error) {
+	mode, err := CompatibilityOf(updated)
+	if err != nil {
+		return "", err
+	}
+	// the mode of the current Type was validated when it was applied
+	currentMode, err := CompatibilityOf(current)
+	if err != nil || currentMode == mode || currentMode == CompatibilityNone {
+		return mode, nil
+	}
+	if mode == CompatibilityNone {
+		return currentMode, nil
+	}
+	// backward and forward together, or either of them along with full
+	return CompatibilityFull, nil
+}
+
+// CheckTypeCompatibility checks whether the schema of the updated Type is
+// compatible with the schema of the current one, in the mode returned by
+// UpdateCompatibility. The error lists the changes that break compatibility.
+// The format of a Type can't change, and only Avro schemas are checked,
+// so Types whose current schema isn't a valid Avro schema aren't checked
+func CheckTypeCompatibility(current, updated *meta.Type) error {
+	if TypeFormat(current) != TypeFormat(updated) {
+		return ierrors.New(
+			"format of Type '%v' can't change from %v to %v",
+			updated.Meta.Name, TypeFormat(current), TypeFormat(updated),
+		).BadRequest()
+	}
+	if TypeFormat(updated) != meta.FormatAvro {
+		return nil
+	}
+
+	mode, err := UpdateCompatibility(current, updated)
+	if err != nil {
+		return err
+	}
+	if mode == CompatibilityNone || current.Schema == updated.Schema {
+		return nil
+	}
+	if _, err := goavro.NewCodec(current.Schema); err != nil {
+		return nil
+	}
+
+	incompatibilities, err := SchemaIncompatibilities(mode, current.Schema, updated.Schema)
+	if err != nil {
+		return ierrors.New(err).BadRequest()
+	}
+	if len(incompatibilities) > 0 {
+		return ierrors.New(
+			"schema of Type '%v' isn't %v compatible:\n\t%v",
+			updated.Meta.Name, mode, strings.Join(incompatibilities, "\n\t"),
+		).BadRequest()
+	}
+	return nil
+}
+
+var avroPrimitives = utils.StringArray{
+	"null", "boolean", "int", "long", "float", "double", "bytes", "string",
+}
+
+// avroPromotions are the types each writer type can be read as,
+// besides itself
+var avroPromotions = map[string]utils.StringArray{
+	"int":    {"long", "float", "double"},
+	"long":   {"float", "double"},
+	"float":  {"double"},
+	"string": {"bytes"},
+	"bytes":  {"string"},
+}
+
+// avroSchema is a parsed Avro schema along with its named types
+type avroSchema struct {
+	root  interface{}
+	names map[string]avroNamed
+}
+
+// avroNamed is the definition of a named type and its namespace
+type avroNamed struct {
+	definition map[string]interface{}
+	namespace  string
+}
+
+func parseAvroSchema(schema string) (*avroSchema, error) {
+	if _, err := goavro.NewCodec(schema); err != nil {
+		return nil, err
+	}
+
+	var root interface{}
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		// primitive types can be given without quotes
+		root = strings.TrimSpace(schema)
+	}
+
+	s := &avroSchema{root: root, names: map[string]avroNamed{}}
+	if err := s.define(root, ""); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// define registers the named types declared in the given schema
+func (s *avroSchema) define(schema interface{}, namespace string) error {
+	switch sch := schema.(type) {
+	case []interface{}:
+		for _, branch := range sch {
+			if err := s.define(branch, namespace); err != nil {
+				return err
+			}
+		}
+
+	case map[string]interface{}:
+		switch sch["type"] {
+		case "record", "error", "enum", "fixed":
+			name, ns := fullName(sch, namespace)
+			if name == "" {
+				return ierrors.New("named type without name")
+			}
+			s.names[name] = avroNamed{definition: sch, namespace: ns}
+
+			fields, _ := sch["fields"].([]interface{})
+			for _, f := range fields {
+				if field, ok := f.(map[string]interface{}); ok {
+					if err := s.define(field["type"], ns); err != nil {
+						return err
+					}
+				}
+			}
+		case "array":
+			return s.define(sch["items"], namespace)
+		case "map":
+			return s.define(sch["values"], namespace)
+		default:
+			return s.define(sch["type"], namespace)
+		}
+	}
+	return nil
+}
+
+// fullName returns the full name of a named type and the namespace
+// of the types declared in it
+func fullName(definition map[string]interface{}, namespace string) (string, string) {
+	name, _ := definition["name"].(string)
+	if strings.Contains(name, ".") {
+		return name, name[:strings.LastIndex(name, ".")]
+	}
+	if ns, ok := definition["namespace"].(string); ok {
+		namespace = ns
+	}
+	if namespace == "" {
+		return name, ""
+	}
+	return namespace + "." + name, namespace
+}
+
+// resolve returns the kind of the given schema (a primitive, record, enum, fixed,
+// array, map or union), its definition and the namespace of its children
+func (s *avroSchema) resolve(schema interface{}, namespace string) (string, map[string]interface{}, string) {
+	switch sch := schema.(type) {
+	case string:
+		if avroPrimitives.Contains(sch) {
+			return sch, nil, namespace
+		}
+		named, ok := s.names[sch]
+		if !ok && namespace != "" {
+			named, ok = s.names[namespace+"."+sch]
+		}
+		if !ok {
+			return sch, nil, namespace
+		}
+		kind, _ := named.definition["type"].(string)
+		return kind, named.definition, named.namespace
+
+	case []interface{}:
+		return "union", nil, namespace
+
+	case map[string]interface{}:
+		switch kind := sch["type"].(type) {
+		case string:
+			switch kind {
+			case "record", "error", "enum", "fixed":
+				_, ns := fullName(sch, namespace)
+				return kind, sch, ns
+			case "array", "map":
+				return kind, sch, namespace
+			}
+			return s.resolve(kind, namespace)
+		default:
+			return s.resolve(kind, namespace)
+		}
+	}
+	return "", nil, namespace
+}
+
+// schemaResolver checks whether data written with the writer schema
+// can be read with the reader schema
+type schemaResolver struct {
+	reader, writer         *avroSchema
+	readerName, writerName string
+	visited                map[string]bool
+	incompatibilities      []string
+}
+
+func newSchemaResolver(reader *avroSchema, readerName string, writer *avroSchema, writerName string) *schemaResolver {
+	return &schemaResolver{
+		reader:     reader,
+		readerName: readerName,
+		writer:     writer,
+		writerName: writerName,
+		visited:    map[string]bool{},
+	}
+}
+
+func (r *schemaResolver) resolve() []string {
+	r.check("", r.reader.root, "", r.writer.root, "")
+	return r.incompatibilities
+}
+
+func (r *schemaResolver) report(path, format string, args ...interface{}) {
+	if path == "" {
+		path = "<root>"
+	}
+	r.incompatibilities = append(r.incompatibilities, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (r *schemaResolver) check(path string, reader interface{}, readerNs string, writer interface{}, writerNs string) {
+	readerKind, readerDef, readerNs := r.reader.resolve(reader, readerNs)
+	writerKind, writerDef, writerNs := r.writer.resolve(writer, writerNs)
+
+	if writerKind == "union" {
+		// every type of the union may have been written
+		for _, branch := range unionBranches(writer) {
+			r.check(path, reader, readerNs, branch, writerNs)
+		}
+		return
+	}
+
+	if readerKind == "union" {
+		for _, branch := range unionBranches(reader) {
+			kind, def, _ := r.reader.resolve(branch, readerNs)
+			if avroMatches(kind, def, writerKind, writerDef) {
+				r.check(path, branch, readerNs, writer, writerNs)
+				return
+			}
+		}
+		r.report(path, "%v of the %v schema isn't one of the types of the union of the %v schema",
+			writerKind, r.writerName, r.readerName)
+		return
+	}
+
+	if !avroMatches(readerKind, readerDef, writerKind, writerDef) {
+		r.report(path, "type is %v in the %v schema and %v in the %v schema",
+			describe(writerKind, writerDef), r.writerName, describe(readerKind, readerDef), r.readerName)
+		return
+	}
+
+	switch readerKind {
+	case "record", "error":
+		key := fmt.Sprintf("%v|%v|%p|%p", path, readerKind, readerDef, writerDef)
+		if r.visited[key] {
+			return
+		}
+		r.visited[key] = true
+		if path == "" {
+			path, _ = readerDef["name"].(string)
+		}
+		r.checkFields(path, readerDef, readerNs, writerDef, writerNs)
+
+	case "enum":
+		if _, ok := readerDef["default"]; ok {
+			return
+		}
+		readerSymbols := stringArray(readerDef["symbols"])
+		missing := utils.StringArray{}
+		for _, symbol := range stringArray(writerDef["symbols"]) {
+			if !readerSymbols.Contains(symbol) {
+				missing = append(missing, symbol)
+			}
+		}
+		if len(missing) > 0 {
+			r.report(path, "symbols %v of the %v schema are missing from the %v schema, which has no default",
+				missing, r.writerName, r.readerName)
+		}
+
+	case "fixed":
+		if fmt.Sprint(readerDef["size"]) != fmt.Sprint(writerDef["size"]) {
+			r.report(path, "fixed size is %v in the %v schema and %v in the %v schema",
+				writerDef["size"], r.writerName, readerDef["size"], r.readerName)
+		}
+
+	case "array":
+		r.check(path+"[]", readerDef["items"], readerNs, writerDef["items"], writerNs)
+
+	case "map":
+		r.check(path+"{}", readerDef["values"], readerNs, writerDef["values"], writerNs)
+	}
+}
+
+func (r *schemaResolver) checkFields(path string, readerDef map[string]interface{}, readerNs string, writerDef map[string]interface{}, writerNs string) {
+	writerFields := map[string]map[string]interface{}{}
+	for _, f := range fields(writerDef) {
+		name, _ := f["name"].(string)
+		writerFields[name] = f
+	}
+
+	for _, readerField := range fields(readerDef) {
+		name, _ := readerField["name"].(string)
+		fieldPath := path + "." + name
+
+		writerField, ok := writerFields[name]
+		for _, alias := range stringArray(readerField["aliases"]) {
+			if ok {
+				break
+			}
+			writerField, ok = writerFields[alias]
+		}
+
+		if !ok {
+			if _, hasDefault := readerField["default"]; !hasDefault {
+				r.report(fieldPath, "field is missing from the %v schema and has no default in the %v schema",
+					r.writerName, r.readerName)
+			}
+			continue
+		}
+		r.check(fieldPath, readerField["type"], readerNs, writerField["type"], writerNs)
+	}
+}
+
+// avroMatches returns whether a writer type can be read as the reader type,
+// without looking into their children
+func avroMatches(readerKind string, readerDef map[string]interface{}, writerKind string, writerDef map[string]interface{}) bool {
+	if readerKind != writerKind {
+		return avroPromotions[writerKind].Contains(readerKind)
+	}
+
+	switch readerKind {
+	case "record", "error", "enum", "fixed":
+		return shortName(readerDef) == shortName(writerDef) ||
+			stringArray(readerDef["aliases"]).Contains(shortName(writerDef))
+	}
+	return true
+}
+
+func unionBranches(schema interface{}) []interface{} {
+	if sch, ok := schema.(map[string]interface{}); ok {
+		return unionBranches(sch["type"])
+	}
+	branches, _ := schema.([]interface{})
+	return branches
+}
+
+func fields(definition map[string]interface{}) []map[string]interface{} {
+	ret := []map[string]interface{}{}
+	list, _ := definition["fields"].([]interface{})
+	for _, f := range list {
+		if field, ok := f.(map[string]interface{}); ok {
+			ret = append(ret, field)
+		}
+	}
+	return ret
+}
+
+func shortName(definition map[string]interface{}) string {
+	name, _ := definition["name"].(string)
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+func describe(kind string, definition map[string]interface{}) string {
+	if definition != nil {
+		if name := shortName(definition); name != "" {
+			return fmt.Sprintf("%v '%v'", kind, name)
+		}
+	}
+	return kind
+}
+
+func stringArray(value interface{}) utils.StringArray {
+	ret := utils.StringArray{}
+	list, _ := value.([]interface{})
+	for _, v := range list {
+		if s, ok := v.(string); ok {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+// CompatibilityReport describes whether the schema of the updated Type is
+// compatible with the current one, in the mode the update is checked in.
+// There is nothing to report unless both schemas are valid Avro schemas
+func CompatibilityReport(current, updated *meta.Type) (string, bool) {
+	if TypeFormat(current) != meta.FormatAvro || TypeFormat(updated) != meta.FormatAvro {
+		return "", false
+	}
+
+	mode, err := UpdateCompatibility(current, updated)
+	if err != nil {
+		return err.Error(), true
+	}
+
+	incompatibilities, err := SchemaIncompatibilities(mode, current.Schema, updated.Schema)
+	if err != nil {
+		return "", false
+	}
+	if mode == CompatibilityNone {
+		return "not checked", true
+	}
+	if len(incompatibilities) > 0 {
+		return fmt.Sprintf("%s incompatible: %s", mode, strings.Join(incompatibilities, "; ")), true
+	}
+	return mode + " compatible", true
+}
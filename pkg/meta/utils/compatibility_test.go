@@ -0,0 +1,323 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+)
+
+const userSchema = `{"type":"record","name":"User","namespace":"inspr","fields":[
+	{"name":"name","type":"string"},
+	{"name":"age","type":"int"},
+	{"name":"status","type":{"type":"enum","name":"Status","symbols":["ACTIVE","INACTIVE"]}}
+]}`
+
+func TestSchemaIncompatibilities(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      string
+		newSchema string
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name: "field added with a default is fully compatible",
+			mode: CompatibilityFull,
+			newSchema: `{"type":"record","name":"User","namespace":"inspr","fields":[
+				{"name":"name","type":"string"},
+				{"name":"age","type":"int"},
+				{"name":"status","type":{"type":"enum","name":"Status","symbols":["ACTIVE","INACTIVE"]}},
+				{"name":"email","type":["null","string"],"default":null}
+			]}`,
+			want: []string{},
+		},
+		{
+			name: "field added without a default breaks backward compatibility",
+			mode: CompatibilityBackward,
+			newSchema: `{"type":"record","name":"User","namespace":"inspr","fields":[
+				{"name":"name","type":"string"},
+				{"name":"age","type":"int"},
+				{"name":"status","type":{"type":"enum","name":"Status","symbols":["ACTIVE","INACTIVE"]}},
+				{"name":"previous","type":["null","Status"],"default":null},
+				{"name":"email","type":"string"}
+			]}`,
+			want: []string{
+				"User.email: field is missing from the old schema and has no default in the new schema",
+			},
+		},
+		{
+			name: "promoted field is backward compatible",
+			mode: CompatibilityBackward,
+			newSchema: `{"type":"record","name":"User","namespace":"inspr","fields":[
+				{"name":"name","type":"string"},
+				{"name":"age","type":"long"},
+				{"name":"status","type":{"type":"enum","name":"Status","symbols":["ACTIVE","INACTIVE","BLOCKED"]}}
+			]}`,
+			want: []string{},
+		},
+		{
+			name: "promoted field and new symbol break forward compatibility",
+			mode: CompatibilityForward,
+			newSchema: `{"type":"record","name":"User","namespace":"inspr","fields":[
+				{"name":"name","type":"string"},
+				{"name":"age","type":"long"},
+				{"name":"status","type":{"type":"enum","name":"Status","symbols":["ACTIVE","INACTIVE","BLOCKED"]}}
+			]}`,
+			want: []string{
+				"User.age: type is long in the new schema and int in the old schema",
+				"User.status: symbols [BLOCKED] of the new schema are missing from the old schema, which has no default",
+			},
+		},
+		{
+			name: "removed field without a default breaks forward compatibility",
+			mode: CompatibilityForward,
+			newSchema: `{"type":"record","name":"User","namespace":"inspr","fields":[
+				{"name":"name","type":"string"},
+				{"name":"status","type":{"type":"enum","name":"Status","symbols":["ACTIVE","INACTIVE"]}}
+			]}`,
+			want: []string{
+				"User.age: field is missing from the new schema and has no default in the old schema",
+			},
+		},
+		{
+			name: "renamed field with an alias is backward compatible",
+			mode: CompatibilityBackward,
+			newSchema: `{"type":"record","name":"User","namespace":"inspr","fields":[
+				{"name":"fullname","type":"string","aliases":["name"]},
+				{"name":"age","type":"int"},
+				{"name":"status","type":{"type":"enum","name":"Status","symbols":["ACTIVE","INACTIVE"]}}
+			]}`,
+			want: []string{},
+		},
+		{
+			name:      "changed type isn't compatible",
+			mode:      CompatibilityBackward,
+			newSchema: `{"type":"string"}`,
+			want: []string{
+				"<root>: type is record 'User' in the old schema and string in the new schema",
+			},
+		},
+		{
+			name:      "invalid new schema",
+			mode:      CompatibilityBackward,
+			newSchema: `{"type":"record"}`,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SchemaIncompatibilities(tt.mode, userSchema, tt.newSchema)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SchemaIncompatibilities() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("SchemaIncompatibilities() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckTypeCompatibility(t *testing.T) {
+	withMode := func(mode, schema string) *meta.Type {
+		return &meta.Type{
+			Meta: meta.Metadata{
+				Name:        "user",
+				Annotations: map[string]string{SchemaCompatibilityAnnotation: mode},
+			},
+			Schema: schema,
+		}
+	}
+	withFormat := func(format, schema string) *meta.Type {
+		t := withMode("", schema)
+		t.Format = format
+		return t
+	}
+	breaking := `{"type":"record","name":"User","namespace":"inspr","fields":[{"name":"id","type":"long"}]}`
+
+	tests := []struct {
+		name     string
+		current  *meta.Type
+		updated  *meta.Type
+		wantCode ierrors.ErrCode
+	}{
+		{
+			name:    "not checked by default",
+			current: withMode("", userSchema),
+			updated: withMode("", breaking),
+		},
+		{
+			name:     "backward incompatible",
+			current:  withMode("", userSchema),
+			updated:  withMode(CompatibilityBackward, breaking),
+			wantCode: ierrors.BadRequest,
+		},
+		{
+			name:    "checks disabled",
+			current: withMode("", userSchema),
+			updated: withMode(CompatibilityNone, breaking),
+		},
+		{
+			name:     "annotation dropped along with an incompatible schema",
+			current:  withMode(CompatibilityBackward, userSchema),
+			updated:  withMode("", breaking),
+			wantCode: ierrors.BadRequest,
+		},
+		{
+			name:     "checks disabled along with an incompatible schema",
+			current:  withMode(CompatibilityBackward, userSchema),
+			updated:  withMode(CompatibilityNone, breaking),
+			wantCode: ierrors.BadRequest,
+		},
+		{
+			name:    "checks disabled without changing the schema",
+			current: withMode(CompatibilityFull, userSchema),
+			updated: withMode(CompatibilityNone, userSchema),
+		},
+		{
+			name:     "invalid compatibility mode",
+			current:  withMode("", userSchema),
+			updated:  withMode("sideways", userSchema),
+			wantCode: ierrors.BadRequest,
+		},
+		{
+			name:    "current schema isn't valid",
+			current: withMode("", ""),
+			updated: withMode("", breaking),
+		},
+		{
+			name:    "explicit avro format is the default format",
+			current: withMode("", userSchema),
+			updated: withFormat(meta.FormatAvro, userSchema),
+		},
+		{
+			name:     "format changed",
+			current:  withMode("", userSchema),
+			updated:  withFormat(meta.FormatJSONSchema, `{"type":"object"}`),
+			wantCode: ierrors.BadRequest,
+		},
+		{
+			name:    "other formats aren't checked",
+			current: withFormat(meta.FormatJSONSchema, `{"type":"object"}`),
+			updated: withFormat(meta.FormatJSONSchema, `{"type":"string"}`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckTypeCompatibility(tt.current, tt.updated)
+			if tt.wantCode == 0 {
+				if err != nil {
+					t.Errorf("CheckTypeCompatibility() error = %v", err)
+				}
+				return
+			}
+			if !ierrors.HasCode(err, tt.wantCode) {
+				t.Errorf("CheckTypeCompatibility() error = %v, want code %v", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestUpdateCompatibility(t *testing.T) {
+	withMode := func(mode string) *meta.Type {
+		return &meta.Type{Meta: meta.Metadata{
+			Name:        "user",
+			Annotations: map[string]string{SchemaCompatibilityAnnotation: mode},
+		}}
+	}
+	tests := []struct {
+		name    string
+		current string
+		updated string
+		want    string
+		wantErr bool
+	}{
+		{name: "same mode", current: CompatibilityForward, updated: CompatibilityForward, want: CompatibilityForward},
+		{name: "mode raised", current: "", updated: CompatibilityBackward, want: CompatibilityBackward},
+		{name: "annotation dropped", current: CompatibilityBackward, updated: "", want: CompatibilityBackward},
+		{name: "mode lowered", current: CompatibilityFull, updated: CompatibilityForward, want: CompatibilityFull},
+		{name: "backward and forward", current: CompatibilityForward, updated: CompatibilityBackward, want: CompatibilityFull},
+		{name: "invalid current mode", current: "sideways", updated: CompatibilityNone, want: CompatibilityNone},
+		{name: "invalid updated mode", current: CompatibilityFull, updated: "sideways", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UpdateCompatibility(withMode(tt.current), withMode(tt.updated))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateCompatibility() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("UpdateCompatibility() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompatibilityReport(t *testing.T) {
+	withMode := func(mode, schema string) *meta.Type {
+		return &meta.Type{
+			Meta: meta.Metadata{
+				Name:        "user",
+				Annotations: map[string]string{SchemaCompatibilityAnnotation: mode},
+			},
+			Schema: schema,
+		}
+	}
+	tests := []struct {
+		name    string
+		current *meta.Type
+		updated *meta.Type
+		want    string
+		wantOk  bool
+	}{
+		{
+			name:    "not checked by default",
+			current: withMode("", `{"type":"int"}`),
+			updated: withMode("", `{"type":"long"}`),
+			want:    "not checked",
+			wantOk:  true,
+		},
+		{
+			name:    "compatible schema",
+			current: withMode("", `{"type":"int"}`),
+			updated: withMode(CompatibilityBackward, `{"type":"long"}`),
+			want:    "backward compatible",
+			wantOk:  true,
+		},
+		{
+			name:    "incompatible schema",
+			current: withMode("", `{"type":"long"}`),
+			updated: withMode(CompatibilityBackward, `{"type":"int"}`),
+			want:    "backward incompatible",
+			wantOk:  true,
+		},
+		{
+			name:    "annotation dropped along with the schema change",
+			current: withMode(CompatibilityBackward, `{"type":"long"}`),
+			updated: withMode("", `{"type":"int"}`),
+			want:    "backward incompatible",
+			wantOk:  true,
+		},
+		{
+			name:    "invalid schema",
+			current: withMode("", `{"type":"int"}`),
+			updated: withMode(CompatibilityBackward, `{`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := CompatibilityReport(tt.current, tt.updated)
+			if ok != tt.wantOk {
+				t.Fatalf("CompatibilityReport() ok = %v, want %v", ok, tt.wantOk)
+			}
+			// incompatible reports are followed by the incompatibilities
+			if !strings.HasPrefix(got, tt.want) {
+				t.Errorf("CompatibilityReport() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
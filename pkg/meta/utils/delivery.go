@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"strconv"
+	"time"
+
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+)
+
+// Annotations of a Channel that define the fields of its delivery policy,
+// overriding the ones defined in its spec
+const (
+	DeliveryMaxAttemptsAnnotation    = "inspr.dev/delivery-max-attempts"
+	DeliveryInitialBackoffAnnotation = "inspr.dev/delivery-initial-backoff"
+	DeliveryMaxBackoffAnnotation     = "inspr.dev/delivery-max-backoff"
+	DeliveryTimeoutAnnotation        = "inspr.dev/delivery-timeout"
+)
+
+// DeliveryPolicyOf returns the delivery policy of the given Channel, made of
+// the one in its spec and the ones in its annotations, or nil when neither
+// define it. It returns an error when the resulting policy is invalid
+func DeliveryPolicyOf(ch *meta.Channel) (*meta.DeliveryPolicy, error) {
+	policy := &meta.DeliveryPolicy{}
+	defined := false
+	if ch.Spec.DeliveryPolicy != nil {
+		*policy = *ch.Spec.DeliveryPolicy
+		defined = true
+	}
+
+	if value, ok := ch.Meta.Annotations[DeliveryMaxAttemptsAnnotation]; ok {
+		attempts, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, ierrors.New(
+				"invalid annotation %v of channel %v: '%v' isn't a number",
+				DeliveryMaxAttemptsAnnotation, ch.Meta.Name, value,
+			).InvalidChannel()
+		}
+		policy.MaxAttempts = attempts
+		defined = true
+	}
+
+	durations := []struct {
+		annotation string
+		field      *string
+	}{
+		{DeliveryInitialBackoffAnnotation, &policy.InitialBackoff},
+		{DeliveryMaxBackoffAnnotation, &policy.MaxBackoff},
+		{DeliveryTimeoutAnnotation, &policy.Timeout},
+	}
+	for _, d := range durations {
+		if value, ok := ch.Meta.Annotations[d.annotation]; ok {
+			*d.field = value
+			defined = true
+		}
+	}
+
+	if !defined {
+		return nil, nil
+	}
+	if err := validDeliveryPolicy(policy); err != nil {
+		return nil, ierrors.Wrap(err, "invalid delivery policy of channel "+ch.Meta.Name)
+	}
+	return policy, nil
+}
+
+// validDeliveryPolicy checks that the attempts of the policy aren't negative
+// and that its durations are valid, with the maximum backoff not smaller than
+// the initial one
+func validDeliveryPolicy(policy *meta.DeliveryPolicy) error {
+	if policy.MaxAttempts < 0 {
+		return ierrors.New(
+			"max attempts can't be negative, got %v", policy.MaxAttempts,
+		).InvalidChannel()
+	}
+
+	initial, err := parsePolicyDuration("initial backoff", policy.InitialBackoff, meta.DefaultDeliveryBackoff)
+	if err != nil {
+		return err
+	}
+	max, err := parsePolicyDuration("max backoff", policy.MaxBackoff, meta.DefaultDeliveryMaxBackoff)
+	if err != nil {
+		return err
+	}
+	if _, err := parsePolicyDuration("timeout", policy.Timeout, "0s"); err != nil {
+		return err
+	}
+
+	if max < initial {
+		return ierrors.New(
+			"max backoff %v is smaller than the initial backoff %v", max, initial,
+		).InvalidChannel()
+	}
+	return nil
+}
+
+func parsePolicyDuration(field, value, def string) (time.Duration, error) {
+	if value == "" {
+		value = def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, ierrors.New(
+			"invalid %v '%v', must be a positive duration such as \"1.5s\"", field, value,
+		).InvalidChannel()
+	}
+	return d, nil
+}
@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+)
+
+func TestDeliveryPolicyOf(t *testing.T) {
+	tests := []struct {
+		name    string
+		ch      *meta.Channel
+		want    *meta.DeliveryPolicy
+		wantErr bool
+	}{
+		{
+			name: "channel without delivery policy",
+			ch:   &meta.Channel{Meta: meta.Metadata{Name: "ch1"}},
+		},
+		{
+			name: "delivery policy of the spec",
+			ch: &meta.Channel{
+				Meta: meta.Metadata{Name: "ch1"},
+				Spec: meta.ChannelSpec{
+					DeliveryPolicy: &meta.DeliveryPolicy{MaxAttempts: 5, Timeout: "2s"},
+				},
+			},
+			want: &meta.DeliveryPolicy{MaxAttempts: 5, Timeout: "2s"},
+		},
+		{
+			name: "annotations override the spec",
+			ch: &meta.Channel{
+				Meta: meta.Metadata{
+					Name: "ch1",
+					Annotations: map[string]string{
+						DeliveryMaxAttemptsAnnotation:    "7",
+						DeliveryInitialBackoffAnnotation: "1s",
+						DeliveryMaxBackoffAnnotation:     "1m",
+					},
+				},
+				Spec: meta.ChannelSpec{
+					DeliveryPolicy: &meta.DeliveryPolicy{MaxAttempts: 5, Timeout: "2s"},
+				},
+			},
+			want: &meta.DeliveryPolicy{
+				MaxAttempts:    7,
+				InitialBackoff: "1s",
+				MaxBackoff:     "1m",
+				Timeout:        "2s",
+			},
+		},
+		{
+			name: "invalid max attempts annotation",
+			ch: &meta.Channel{
+				Meta: meta.Metadata{
+					Name:        "ch1",
+					Annotations: map[string]string{DeliveryMaxAttemptsAnnotation: "many"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative max attempts",
+			ch: &meta.Channel{
+				Meta: meta.Metadata{Name: "ch1"},
+				Spec: meta.ChannelSpec{
+					DeliveryPolicy: &meta.DeliveryPolicy{MaxAttempts: -1},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid duration",
+			ch: &meta.Channel{
+				Meta: meta.Metadata{Name: "ch1"},
+				Spec: meta.ChannelSpec{
+					DeliveryPolicy: &meta.DeliveryPolicy{Timeout: "soon"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "max backoff smaller than the default initial backoff",
+			ch: &meta.Channel{
+				Meta: meta.Metadata{Name: "ch1"},
+				Spec: meta.ChannelSpec{
+					DeliveryPolicy: &meta.DeliveryPolicy{MaxBackoff: "10ms"},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DeliveryPolicyOf(tt.ch)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DeliveryPolicyOf() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && !ierrors.HasCode(err, ierrors.InvalidChannel) {
+				t.Errorf("DeliveryPolicyOf() error = %v, want an invalid channel error", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DeliveryPolicyOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
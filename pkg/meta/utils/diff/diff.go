@@ -3,6 +3,9 @@ package diff
 import (
 	"fmt"
 	"io"
+	"reflect"
+	"strconv"
+	"strings"
 	"text/tabwriter"
 
 	"inspr.dev/inspr/pkg/meta"
@@ -25,6 +28,7 @@ const (
 	AnnotationKind
 	AliasKind
 	EnvironmentKind
+	TemplateKind
 )
 
 // Operation represents an operation that has been applied in a diff
@@ -56,7 +60,18 @@ type Change struct {
 	Diff      []Difference `json:"diff"`
 	Kind      Kind
 	Operation Operation
-	changelog *Changelog
+	// Compatibility has the compatibility reports of the updated Type schemas.
+	// It's only set on the responses of dry-run requests, and it isn't part
+	// of the changes that are committed
+	Compatibility []TypeCompatibility `json:"compatibility,omitempty"`
+	changelog     *Changelog
+}
+
+// TypeCompatibility reports whether the updated schema of a Type is compatible
+// with its current one
+type TypeCompatibility struct {
+	Type   string `json:"type"`
+	Report string `json:"report"`
 }
 
 //Changelog log of all changes between two app trees.
@@ -88,10 +103,17 @@ func (cl Changelog) Print(out io.Writer) {
 			)
 		}
 		w.Flush()
+		for _, compatibility := range change.Compatibility {
+			fmt.Fprintf(out, "Schema of Type %s: %s\n", compatibility.Type, compatibility.Report)
+		}
 	}
 }
 
 func (cl *Changelog) diff(from, to *meta.App, scope string) (Changelog, error) {
+	// trees share the dApps that didn't change between them
+	if from == to {
+		return *cl, nil
+	}
 
 	change := Change{
 		Scope:     scope,
@@ -132,6 +154,11 @@ func (change *Change) diffAppSpec(from, to meta.AppSpec) error {
 		return err
 	}
 
+	err = change.diffTemplates(from.Templates, to.Templates)
+	if err != nil {
+		return err
+	}
+
 	change.diffBoudaries(from.Boundary, to.Boundary)
 	change.diffAliases(from.Aliases, to.Aliases)
 	return nil
@@ -432,6 +459,71 @@ func (change *Change) diffChannels(from, to metautils.MChannels) error {
 			change.Operation |= Update
 		}
 
+		if fromCh.Spec.TypeVersion != toCh.Spec.TypeVersion {
+			change.Diff = append(change.Diff, Difference{
+				Field:     fmt.Sprintf("Spec.Channels[%s].Spec.TypeVersion", ch),
+				From:      strconv.Itoa(fromCh.Spec.TypeVersion),
+				To:        strconv.Itoa(toCh.Spec.TypeVersion),
+				Kind:      ChannelKind,
+				Operation: Update,
+				Name:      ch,
+			})
+			change.Kind |= ChannelKind
+			change.Operation |= Update
+		}
+
+		if fromDL, toDL := deadLetterString(fromCh.Spec.DeadLetter), deadLetterString(toCh.Spec.DeadLetter); fromDL != toDL {
+			change.Diff = append(change.Diff, Difference{
+				Field:     fmt.Sprintf("Spec.Channels[%s].Spec.DeadLetter", ch),
+				From:      fromDL,
+				To:        toDL,
+				Kind:      ChannelKind,
+				Operation: Update,
+				Name:      ch,
+			})
+			change.Kind |= ChannelKind
+			change.Operation |= Update
+		}
+
+		if fromDP, toDP := deliveryPolicyString(fromCh.Spec.DeliveryPolicy), deliveryPolicyString(toCh.Spec.DeliveryPolicy); fromDP != toDP {
+			change.Diff = append(change.Diff, Difference{
+				Field:     fmt.Sprintf("Spec.Channels[%s].Spec.DeliveryPolicy", ch),
+				From:      fromDP,
+				To:        toDP,
+				Kind:      ChannelKind,
+				Operation: Update,
+				Name:      ch,
+			})
+			change.Kind |= ChannelKind
+			change.Operation |= Update
+		}
+
+		if fromC, toC := concurrencyString(fromCh.Spec.Concurrency), concurrencyString(toCh.Spec.Concurrency); fromC != toC {
+			change.Diff = append(change.Diff, Difference{
+				Field:     fmt.Sprintf("Spec.Channels[%s].Spec.Concurrency", ch),
+				From:      fromC,
+				To:        toC,
+				Kind:      ChannelKind,
+				Operation: Update,
+				Name:      ch,
+			})
+			change.Kind |= ChannelKind
+			change.Operation |= Update
+		}
+
+		if fromB, toB := batchString(fromCh.Spec.Batch), batchString(toCh.Spec.Batch); fromB != toB {
+			change.Diff = append(change.Diff, Difference{
+				Field:     fmt.Sprintf("Spec.Channels[%s].Spec.Batch", ch),
+				From:      fromB,
+				To:        toB,
+				Kind:      ChannelKind,
+				Operation: Update,
+				Name:      ch,
+			})
+			change.Kind |= ChannelKind
+			change.Operation |= Update
+		}
+
 		err := change.diffMetadata(ch, ChannelKind, fromCh.Meta, toCh.Meta, "Spec.Channels["+ch+"].")
 		if err != nil {
 			return err
@@ -441,6 +533,56 @@ func (change *Change) diffChannels(from, to metautils.MChannels) error {
 	return nil
 }
 
+// deadLetterString returns the representation of a dead-letter configuration in a diff
+func deadLetterString(deadLetter *meta.DeadLetter) string {
+	if deadLetter == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s (max attempts: %d)", deadLetter.Channel, deadLetter.MaxAttempts)
+}
+
+// deliveryPolicyString returns the representation of a delivery policy in a
+// diff, with the fields that it defines
+func deliveryPolicyString(policy *meta.DeliveryPolicy) string {
+	if policy == nil {
+		return ""
+	}
+
+	fields := []string{fmt.Sprintf("max attempts: %d", policy.MaxAttempts)}
+	if policy.InitialBackoff != "" {
+		fields = append(fields, "initial backoff: "+policy.InitialBackoff)
+	}
+	if policy.MaxBackoff != "" {
+		fields = append(fields, "max backoff: "+policy.MaxBackoff)
+	}
+	if policy.Timeout != "" {
+		fields = append(fields, "timeout: "+policy.Timeout)
+	}
+	return strings.Join(fields, ", ")
+}
+
+// concurrencyString returns the representation of a concurrency configuration in a diff
+func concurrencyString(concurrency *meta.Concurrency) string {
+	if concurrency == nil {
+		return ""
+	}
+	if concurrency.OrderingKey == "" {
+		return fmt.Sprintf("max in flight: %d", concurrency.MaxInFlight)
+	}
+	return fmt.Sprintf("max in flight: %d, ordering key: %s", concurrency.MaxInFlight, concurrency.OrderingKey)
+}
+
+// batchString returns the representation of a batch configuration in a diff
+func batchString(batch *meta.Batch) string {
+	if batch == nil {
+		return ""
+	}
+	if batch.Linger == "" {
+		return fmt.Sprintf("max size: %d", batch.MaxSize)
+	}
+	return fmt.Sprintf("max size: %d, linger: %s", batch.MaxSize, batch.Linger)
+}
+
 func (change *Change) diffTypes(from, to metautils.MTypes) error {
 	fromSet, _ := metautils.MakeStrSet(from)
 	toSet, _ := metautils.MakeStrSet(to)
@@ -480,6 +622,19 @@ func (change *Change) diffTypes(from, to metautils.MTypes) error {
 		fromCT := from[ct]
 		toCT := to[ct]
 
+		if fromCT.Format != toCT.Format {
+			change.Diff = append(change.Diff, Difference{
+				Field:     fmt.Sprintf("Spec.Types[%s].Spec.Format", ct),
+				From:      fromCT.Format,
+				To:        toCT.Format,
+				Kind:      TypeKind,
+				Operation: Update,
+				Name:      ct,
+			})
+			change.Kind |= TypeKind
+			change.Operation |= Update
+		}
+
 		if string(fromCT.Schema) != string(toCT.Schema) {
 			change.Diff = append(change.Diff, Difference{
 				Field:     fmt.Sprintf("Spec.Types[%s].Spec.Schema", ct),
@@ -503,6 +658,60 @@ func (change *Change) diffTypes(from, to metautils.MTypes) error {
 	return nil
 }
 
+func (change *Change) diffTemplates(from, to map[string]*meta.Template) error {
+	for name := range from {
+		if _, ok := to[name]; ok {
+			continue
+		}
+		change.Diff = append(change.Diff, Difference{
+			Field:     fmt.Sprintf("Spec.Templates[%s]", name),
+			From:      "{...}",
+			To:        "<nil>",
+			Kind:      TemplateKind,
+			Operation: Delete,
+			Name:      name,
+		})
+		change.Kind |= TemplateKind
+		change.Operation |= Delete
+	}
+
+	for name, toT := range to {
+		fromT, ok := from[name]
+		if !ok {
+			change.Diff = append(change.Diff, Difference{
+				Field:     fmt.Sprintf("Spec.Templates[%s]", name),
+				From:      "<nil>",
+				To:        "{...}",
+				Kind:      TemplateKind,
+				Operation: Create,
+				Name:      name,
+			})
+			change.Kind |= TemplateKind
+			change.Operation |= Create
+			continue
+		}
+
+		if !reflect.DeepEqual(fromT.Parameters, toT.Parameters) || !reflect.DeepEqual(fromT.App, toT.App) {
+			change.Diff = append(change.Diff, Difference{
+				Field:     fmt.Sprintf("Spec.Templates[%s].App", name),
+				From:      "{...}",
+				To:        "{...}",
+				Kind:      TemplateKind,
+				Operation: Update,
+				Name:      name,
+			})
+			change.Kind |= TemplateKind
+			change.Operation |= Update
+		}
+
+		err := change.diffMetadata(name, TemplateKind, fromT.Meta, toT.Meta, fmt.Sprintf("Spec.Templates[%s].", name))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (change *Change) diffMetadata(parentElement string, parentKind Kind, from, to meta.Metadata, ctx string) error {
 
 	if from.Name != to.Name {
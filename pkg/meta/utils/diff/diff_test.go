@@ -668,6 +668,165 @@ func TestChange_diffChannels(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:   "Channel dead-letter changed",
+			fields: fields{},
+			args: args{
+				chOrig: metautils.MChannels{
+					"ch1": &meta.Channel{
+						Meta: meta.Metadata{},
+						Spec: meta.ChannelSpec{
+							Type: "type",
+						},
+					},
+				},
+				chCurr: metautils.MChannels{
+					"ch1": &meta.Channel{
+						Meta: meta.Metadata{},
+						Spec: meta.ChannelSpec{
+							Type:       "type",
+							DeadLetter: &meta.DeadLetter{Channel: "ch2", MaxAttempts: 5},
+						},
+					},
+				},
+			},
+			wantErr: false,
+			want: Change{
+				Kind:      ChannelKind,
+				Operation: Update,
+				Diff: []Difference{
+					{
+						Field:     "Spec.Channels[ch1].Spec.DeadLetter",
+						From:      "",
+						To:        "ch2 (max attempts: 5)",
+						Kind:      ChannelKind,
+						Operation: Update,
+						Name:      "ch1",
+					},
+				},
+			},
+		},
+		{
+			name:   "Channel delivery policy changed",
+			fields: fields{},
+			args: args{
+				chOrig: metautils.MChannels{
+					"ch1": &meta.Channel{
+						Meta: meta.Metadata{},
+						Spec: meta.ChannelSpec{
+							Type:           "type",
+							DeliveryPolicy: &meta.DeliveryPolicy{MaxAttempts: 3},
+						},
+					},
+				},
+				chCurr: metautils.MChannels{
+					"ch1": &meta.Channel{
+						Meta: meta.Metadata{},
+						Spec: meta.ChannelSpec{
+							Type: "type",
+							DeliveryPolicy: &meta.DeliveryPolicy{
+								MaxAttempts:    5,
+								InitialBackoff: "1s",
+								MaxBackoff:     "1m",
+								Timeout:        "5s",
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+			want: Change{
+				Kind:      ChannelKind,
+				Operation: Update,
+				Diff: []Difference{
+					{
+						Field:     "Spec.Channels[ch1].Spec.DeliveryPolicy",
+						From:      "max attempts: 3",
+						To:        "max attempts: 5, initial backoff: 1s, max backoff: 1m, timeout: 5s",
+						Kind:      ChannelKind,
+						Operation: Update,
+						Name:      "ch1",
+					},
+				},
+			},
+		},
+		{
+			name:   "Channel concurrency changed",
+			fields: fields{},
+			args: args{
+				chOrig: metautils.MChannels{
+					"ch1": &meta.Channel{
+						Meta: meta.Metadata{},
+						Spec: meta.ChannelSpec{
+							Type:        "type",
+							Concurrency: &meta.Concurrency{MaxInFlight: 2},
+						},
+					},
+				},
+				chCurr: metautils.MChannels{
+					"ch1": &meta.Channel{
+						Meta: meta.Metadata{},
+						Spec: meta.ChannelSpec{
+							Type:        "type",
+							Concurrency: &meta.Concurrency{MaxInFlight: 8, OrderingKey: "user.id"},
+						},
+					},
+				},
+			},
+			wantErr: false,
+			want: Change{
+				Kind:      ChannelKind,
+				Operation: Update,
+				Diff: []Difference{
+					{
+						Field:     "Spec.Channels[ch1].Spec.Concurrency",
+						From:      "max in flight: 2",
+						To:        "max in flight: 8, ordering key: user.id",
+						Kind:      ChannelKind,
+						Operation: Update,
+						Name:      "ch1",
+					},
+				},
+			},
+		},
+		{
+			name:   "Channel batch changed",
+			fields: fields{},
+			args: args{
+				chOrig: metautils.MChannels{
+					"ch1": &meta.Channel{
+						Meta: meta.Metadata{},
+						Spec: meta.ChannelSpec{
+							Type: "type",
+						},
+					},
+				},
+				chCurr: metautils.MChannels{
+					"ch1": &meta.Channel{
+						Meta: meta.Metadata{},
+						Spec: meta.ChannelSpec{
+							Type:  "type",
+							Batch: &meta.Batch{MaxSize: 100, Linger: "250ms"},
+						},
+					},
+				},
+			},
+			wantErr: false,
+			want: Change{
+				Kind:      ChannelKind,
+				Operation: Update,
+				Diff: []Difference{
+					{
+						Field:     "Spec.Channels[ch1].Spec.Batch",
+						From:      "",
+						To:        "max size: 100, linger: 250ms",
+						Kind:      ChannelKind,
+						Operation: Update,
+						Name:      "ch1",
+					},
+				},
+			},
+		},
 		{
 			name:   "Channel deleted",
 			fields: fields{},
@@ -894,6 +1053,39 @@ func TestChange_diffTypes(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:   "Schema change of an avro Type",
+			fields: fields{},
+			args: args{
+				chtOrig: metautils.MTypes{
+					"ct1": &meta.Type{
+						Meta:   meta.Metadata{Name: "ct1"},
+						Schema: `{"type":"int"}`,
+					},
+				},
+				chtCurr: metautils.MTypes{
+					"ct1": &meta.Type{
+						Meta:   meta.Metadata{Name: "ct1"},
+						Schema: `{"type":"long"}`,
+					},
+				},
+			},
+			wantErr: false,
+			want: Change{
+				Kind:      TypeKind,
+				Operation: Update,
+				Diff: []Difference{
+					{
+						Field:     "Spec.Types[ct1].Spec.Schema",
+						From:      `{"type":"int"}`,
+						To:        `{"type":"long"}`,
+						Kind:      TypeKind,
+						Operation: Update,
+						Name:      "ct1",
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -61,8 +61,10 @@ type DifferenceOperation func(scope string, d Difference) error
 // a Changelog. The filter determines which differences will be applied to and the operation defines
 // what will be applied to each difference.
 type DifferenceReaction struct {
+	name      string
 	filter    DifferenceFilter
 	operation DifferenceOperation
+	undo      DifferenceOperation
 }
 
 // NewDifferenceKindReaction creates a DifferenceOperation that filters per type.
@@ -82,10 +84,25 @@ func NewDifferenceKindReaction(kind Kind, apply DifferenceOperation) DifferenceR
 // See DifferenceOperation
 func NewDifferenceReaction(filter DifferenceFilter, apply DifferenceOperation) DifferenceReaction {
 	return DifferenceReaction{
-		filter, apply,
+		filter:    filter,
+		operation: apply,
 	}
 }
 
+// WithName returns a copy of the reaction with the given name, which identifies
+// the reaction in the report of ApplyReactions
+func (r DifferenceReaction) WithName(name string) DifferenceReaction {
+	r.name = name
+	return r
+}
+
+// WithUndo returns a copy of the reaction with an operation that undoes its own,
+// used by ApplyReactions to compensate the reaction when a latter one fails
+func (r DifferenceReaction) WithUndo(undo DifferenceOperation) DifferenceReaction {
+	r.undo = undo
+	return r
+}
+
 // ForEachDiffFiltered applies each operation on the diffs contained in the changelog.
 //
 // The operations are applied only if the filters defined on them return true, and every filter
@@ -124,8 +141,10 @@ type ChangeOperation func(c Change) error
 // a Changelog. The filter determines which changess will be applied to and the operation defines
 // what will be applied to each change.
 type ChangeReaction struct {
+	name   string
 	filter ChangeFilter
 	apply  ChangeOperation
+	undo   ChangeOperation
 }
 
 // NewChangeReaction creates a ChangeOperation for the given filter and apply function
@@ -133,10 +152,25 @@ type ChangeReaction struct {
 // See ChangeOperation
 func NewChangeReaction(filter ChangeFilter, apply ChangeOperation) ChangeReaction {
 	return ChangeReaction{
-		filter, apply,
+		filter: filter,
+		apply:  apply,
 	}
 }
 
+// WithName returns a copy of the reaction with the given name, which identifies
+// the reaction in the report of ApplyReactions
+func (r ChangeReaction) WithName(name string) ChangeReaction {
+	r.name = name
+	return r
+}
+
+// WithUndo returns a copy of the reaction with an operation that undoes its own,
+// used by ApplyReactions to compensate the reaction when a latter one fails
+func (r ChangeReaction) WithUndo(undo ChangeOperation) ChangeReaction {
+	r.undo = undo
+	return r
+}
+
 // NewChangeKindReaction creates a ChangeOperation that filters per type.
 //
 // See ChangeOperation
@@ -0,0 +1,167 @@
+package diff
+
+import (
+	"fmt"
+
+	"inspr.dev/inspr/pkg/ierrors"
+	metautils "inspr.dev/inspr/pkg/meta/utils"
+)
+
+// ReactionStep is the report of a reaction applied on a difference or on a change
+// of a changelog. Name is empty for the reactions applied on changes.
+type ReactionStep struct {
+	Reaction    string `json:"reaction"`
+	Scope       string `json:"scope"`
+	Name        string `json:"name,omitempty"`
+	Error       string `json:"error,omitempty"`
+	Reverted    bool   `json:"reverted"`
+	RevertError string `json:"reverterror,omitempty"`
+}
+
+// ReactionsReport is the report of the reactions applied on a changelog by
+// ApplyReactions, in the order they were applied
+type ReactionsReport struct {
+	Steps []ReactionStep `json:"steps"`
+}
+
+// Reverted returns how many of the steps of the report were undone
+func (r ReactionsReport) Reverted() int {
+	reverted := 0
+	for _, step := range r.Steps {
+		if step.Reverted {
+			reverted++
+		}
+	}
+	return reverted
+}
+
+// CommitReaction is the name of the final step of ApplyReactions, that commits
+// the changes once every reaction was applied
+const CommitReaction = "commit"
+
+// sagaStep is a reaction of the saga that wasn't applied yet
+type sagaStep struct {
+	report ReactionStep
+	do     func() error
+	undo   func() error
+}
+
+// ApplyReactions applies the difference reactions and then the change reactions on
+// the changelog, in the same order as ForEachDiffFiltered and ForEachFiltered, and
+// then commits the changelog with commit, as a single saga: on the first step that
+// fails no other step is applied and the ones already applied, including the one
+// that failed, are undone in reverse order. Undo operations must be idempotent, an
+// undo that fails with NotFound is considered done.
+//
+// Reactions created without an undo operation can't be compensated, so they are
+// applied after all the others, right before the commit. A nil commit is skipped.
+//
+// The returned report has every step that was applied and whether it was undone.
+func (c Changelog) ApplyReactions(
+	diffReactions []DifferenceReaction,
+	changeReactions []ChangeReaction,
+	commit func() error,
+) (ReactionsReport, error) {
+	steps := []sagaStep{}
+	for _, change := range c {
+		if change.Scope == "*" {
+			change.Scope = ""
+		}
+		for _, d := range change.Diff {
+			for _, reaction := range diffReactions {
+				if !reaction.filter(change.Scope, d) {
+					continue
+				}
+				scope, d, reaction := change.Scope, d, reaction
+				step := sagaStep{
+					report: ReactionStep{Reaction: reaction.name, Scope: scope, Name: d.Name},
+					do:     func() error { return reaction.operation(scope, d) },
+				}
+				if reaction.undo != nil {
+					step.undo = func() error { return reaction.undo(scope, d) }
+				}
+				steps = append(steps, step)
+			}
+		}
+	}
+
+	for _, change := range c {
+		if change.Scope == "*" {
+			change.Scope = ""
+		}
+		for _, reaction := range changeReactions {
+			if !reaction.filter(change) {
+				continue
+			}
+			change, reaction := change, reaction
+			step := sagaStep{
+				report: ReactionStep{Reaction: reaction.name, Scope: change.Scope},
+				do:     func() error { return reaction.apply(change) },
+			}
+			if reaction.undo != nil {
+				step.undo = func() error { return reaction.undo(change) }
+			}
+			steps = append(steps, step)
+		}
+	}
+
+	// the irreversible steps go last, so that a failure on any other step
+	// doesn't leave them applied
+	sorted := make([]sagaStep, 0, len(steps)+1)
+	for _, step := range steps {
+		if step.undo != nil {
+			sorted = append(sorted, step)
+		}
+	}
+	for _, step := range steps {
+		if step.undo == nil {
+			sorted = append(sorted, step)
+		}
+	}
+	if commit != nil {
+		sorted = append(sorted, sagaStep{
+			report: ReactionStep{Reaction: CommitReaction},
+			do:     commit,
+			// a failed commit doesn't change anything to be undone
+			undo: func() error { return nil },
+		})
+	}
+
+	report := ReactionsReport{Steps: []ReactionStep{}}
+	var err error
+	for _, step := range sorted {
+		err = step.do()
+		if err != nil {
+			step.report.Error = err.Error()
+		}
+		report.Steps = append(report.Steps, step.report)
+		if err != nil {
+			break
+		}
+	}
+	if err == nil {
+		return report, nil
+	}
+
+	// the failed step may have been partially applied, so it's undone as well
+	for i := len(report.Steps) - 1; i >= 0; i-- {
+		step := &report.Steps[i]
+		if sorted[i].undo == nil {
+			step.RevertError = "the reaction can't be undone"
+			continue
+		}
+		if undoErr := sorted[i].undo(); undoErr != nil && !ierrors.HasCode(undoErr, ierrors.NotFound) {
+			step.RevertError = undoErr.Error()
+			continue
+		}
+		step.Reverted = true
+	}
+
+	failed := report.Steps[len(report.Steps)-1]
+	component, _ := metautils.JoinScopes(failed.Scope, failed.Name)
+	return report, ierrors.Wrap(
+		err,
+		fmt.Sprintf("unable to apply the reaction %v on '%v', %d of %d applied reactions undone",
+			failed.Reaction, component, report.Reverted(), len(report.Steps)),
+	)
+}
@@ -0,0 +1,174 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	"inspr.dev/inspr/pkg/ierrors"
+)
+
+func TestChangelog_ApplyReactions(t *testing.T) {
+	changelog := Changelog{
+		{
+			Scope: "*",
+			Diff: []Difference{
+				{Kind: ChannelKind, Name: "ch1", Operation: Create},
+				{Kind: ChannelKind, Name: "ch2", Operation: Create},
+			},
+		},
+		{
+			Scope: "app1",
+			Kind:  NodeKind,
+			Diff: []Difference{
+				{Kind: NodeKind, Name: "app1", Operation: Create},
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		failing      string
+		undoChannels bool
+		undoNodes    bool
+		wantLog      []string
+		wantSteps    []ReactionStep
+		wantErr      bool
+	}{
+		{
+			name:         "every reaction applied and committed",
+			undoChannels: true,
+			undoNodes:    true,
+			wantLog:      []string{"create ch1", "create ch2", "node app1", "commit"},
+			wantSteps: []ReactionStep{
+				{Reaction: "channels", Name: "ch1"},
+				{Reaction: "channels", Name: "ch2"},
+				{Reaction: "nodes", Scope: "app1"},
+				{Reaction: CommitReaction},
+			},
+		},
+		{
+			name:         "failed change reaction undoes the applied ones in reverse order",
+			failing:      "node app1",
+			undoChannels: true,
+			undoNodes:    true,
+			wantLog: []string{
+				"create ch1", "create ch2", "node app1",
+				"undo node app1", "delete ch2", "delete ch1",
+			},
+			wantSteps: []ReactionStep{
+				{Reaction: "channels", Name: "ch1", Reverted: true},
+				{Reaction: "channels", Name: "ch2", Reverted: true},
+				{Reaction: "nodes", Scope: "app1", Error: "error : node failed", Reverted: true},
+			},
+			wantErr: true,
+		},
+		{
+			name:         "failed difference reaction stops the saga and is undone",
+			failing:      "create ch2",
+			undoChannels: true,
+			undoNodes:    true,
+			wantLog:      []string{"create ch1", "create ch2", "delete ch2", "delete ch1"},
+			wantSteps: []ReactionStep{
+				{Reaction: "channels", Name: "ch1", Reverted: true},
+				{Reaction: "channels", Name: "ch2", Error: "error : channel failed", Reverted: true},
+			},
+			wantErr: true,
+		},
+		{
+			name:         "failed commit undoes every reaction",
+			failing:      "commit",
+			undoChannels: true,
+			undoNodes:    true,
+			wantLog: []string{
+				"create ch1", "create ch2", "node app1", "commit",
+				"undo node app1", "delete ch2", "delete ch1",
+			},
+			wantSteps: []ReactionStep{
+				{Reaction: "channels", Name: "ch1", Reverted: true},
+				{Reaction: "channels", Name: "ch2", Reverted: true},
+				{Reaction: "nodes", Scope: "app1", Reverted: true},
+				{Reaction: CommitReaction, Error: "error : commit failed", Reverted: true},
+			},
+			wantErr: true,
+		},
+		{
+			name:      "reactions that can't be undone are applied last",
+			failing:   "node app1",
+			undoNodes: true,
+			wantLog:   []string{"node app1", "undo node app1"},
+			wantSteps: []ReactionStep{
+				{Reaction: "nodes", Scope: "app1", Error: "error : node failed", Reverted: true},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "reactions that can't be undone",
+			failing: "create ch2",
+			wantLog: []string{"create ch1", "create ch2"},
+			wantSteps: []ReactionStep{
+				{Reaction: "channels", Name: "ch1", RevertError: "the reaction can't be undone"},
+				{
+					Reaction:    "channels",
+					Name:        "ch2",
+					Error:       "error : channel failed",
+					RevertError: "the reaction can't be undone",
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := []string{}
+			channels := NewDifferenceKindReaction(ChannelKind, func(scope string, d Difference) error {
+				log = append(log, "create "+d.Name)
+				if tt.failing == "create "+d.Name {
+					return ierrors.New("channel failed")
+				}
+				return nil
+			}).WithName("channels")
+			nodes := NewChangeKindReaction(NodeKind, func(c Change) error {
+				log = append(log, "node "+c.Scope)
+				if tt.failing == "node "+c.Scope {
+					return ierrors.New("node failed")
+				}
+				return nil
+			}).WithName("nodes")
+			if tt.undoChannels {
+				channels = channels.WithUndo(func(scope string, d Difference) error {
+					log = append(log, "delete "+d.Name)
+					return nil
+				})
+			}
+			if tt.undoNodes {
+				// undos that find nothing to undo are considered done
+				nodes = nodes.WithUndo(func(c Change) error {
+					log = append(log, "undo node "+c.Scope)
+					return ierrors.New("node not found").NotFound()
+				})
+			}
+			commit := func() error {
+				log = append(log, "commit")
+				if tt.failing == "commit" {
+					return ierrors.New("commit failed")
+				}
+				return nil
+			}
+
+			report, err := changelog.ApplyReactions(
+				[]DifferenceReaction{channels},
+				[]ChangeReaction{nodes},
+				commit,
+			)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Changelog.ApplyReactions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(log, tt.wantLog) {
+				t.Errorf("Changelog.ApplyReactions() applied %v, want %v", log, tt.wantLog)
+			}
+			if !reflect.DeepEqual(report.Steps, tt.wantSteps) {
+				t.Errorf("Changelog.ApplyReactions() report = %v, want %v", report.Steps, tt.wantSteps)
+			}
+		})
+	}
+}
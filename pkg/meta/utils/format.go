@@ -115,6 +115,9 @@ func populateMeta(metaTree gotree.Tree, meta *meta.Metadata) {
 	if meta.UUID != "" {
 		metaTree.Add("UUID: " + meta.UUID)
 	}
+	if meta.ResourceVersion != 0 {
+		metaTree.Add(fmt.Sprintf("ResourceVersion: %d", meta.ResourceVersion))
+	}
 	var annotations gotree.Tree
 	if len(meta.Annotations) > 0 {
 		annotations = metaTree.Add("Annotations")
@@ -188,7 +191,7 @@ func addNodesTree(spec gotree.Tree, app *meta.App) {
 
 		if len(app.Spec.Node.Spec.Environment) > 0 {
 			env := spec.Add("Environment")
-			for name, value := range app.Spec.Types {
+			for name, value := range app.Spec.Node.Spec.Environment {
 				env.Add(fmt.Sprintf("%s: %s", name, value))
 			}
 		}
@@ -0,0 +1,281 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/utils"
+)
+
+// Operator is the relation a selector requirement checks between an annotation and its values
+type Operator string
+
+const (
+	// Equals requires the annotation to be set to the value
+	Equals Operator = "="
+	// NotEquals requires the annotation to be unset or set to a different value
+	NotEquals Operator = "!="
+	// In requires the annotation to be set to one of the values
+	In Operator = "in"
+	// NotIn requires the annotation to be unset or set to none of the values
+	NotIn Operator = "notin"
+	// Exists requires the annotation to be set
+	Exists Operator = "exists"
+	// DoesNotExist requires the annotation to be unset
+	DoesNotExist Operator = "!"
+)
+
+// Requirement is a single condition on the annotations of a component
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Values   []string
+}
+
+// Selector is a set of requirements that the annotations of a component
+// must all meet for the component to be selected
+type Selector []Requirement
+
+// ParseSelector parses a comma separated list of requirements, each one of them being
+// one of 'key=value', 'key==value', 'key!=value', 'key in (v1,v2)', 'key notin (v1,v2)',
+// 'key' or '!key'. An empty expression results in a selector that matches everything.
+func ParseSelector(expression string) (Selector, error) {
+	selector := Selector{}
+	for _, term := range splitSelector(expression) {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		requirement, err := parseRequirement(term)
+		if err != nil {
+			return nil, err
+		}
+		selector = append(selector, requirement)
+	}
+	return selector, nil
+}
+
+// Matches returns whether the given annotations meet every requirement of the selector
+func (s Selector) Matches(annotations map[string]string) bool {
+	for _, r := range s {
+		value, ok := annotations[r.Key]
+		switch r.Operator {
+		case Equals:
+			if !ok || value != r.Values[0] {
+				return false
+			}
+		case NotEquals:
+			if ok && value == r.Values[0] {
+				return false
+			}
+		case In:
+			if !ok || !utils.Includes(r.Values, value) {
+				return false
+			}
+		case NotIn:
+			if ok && utils.Includes(r.Values, value) {
+				return false
+			}
+		case Exists:
+			if !ok {
+				return false
+			}
+		case DoesNotExist:
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// String returns the selector as an expression that parses back into it
+func (s Selector) String() string {
+	terms := make([]string, 0, len(s))
+	for _, r := range s {
+		switch r.Operator {
+		case Equals, NotEquals:
+			terms = append(terms, r.Key+string(r.Operator)+r.Values[0])
+		case In, NotIn:
+			terms = append(terms, r.Key+" "+string(r.Operator)+" ("+strings.Join(r.Values, ",")+")")
+		case Exists:
+			terms = append(terms, r.Key)
+		case DoesNotExist:
+			terms = append(terms, "!"+r.Key)
+		}
+	}
+	return strings.Join(terms, ",")
+}
+
+// SelectApps returns the dApps of the subtree of the given dApp, which is in the
+// given scope, whose annotations match the selector. The root dApp is never selected.
+func (s Selector) SelectApps(app *meta.App, scope string) []meta.App {
+	apps := []meta.App{}
+	walkApps(app, scope, func(app *meta.App, scope string) {
+		if scope != "" && s.Matches(app.Meta.Annotations) {
+			selected := *app
+			selected.Meta.Parent, _, _ = RemoveLastPartInScope(scope)
+			apps = append(apps, selected)
+		}
+	})
+	return apps
+}
+
+// SelectChannels returns the channels of the subtree of the given dApp, which is in the
+// given scope, whose annotations match the selector. The parent of each of the returned
+// channels is set to the scope of the dApp that holds it.
+func (s Selector) SelectChannels(app *meta.App, scope string) []meta.Channel {
+	channels := []meta.Channel{}
+	walkApps(app, scope, func(app *meta.App, scope string) {
+		for _, name := range sortedKeys(MChannels(app.Spec.Channels)) {
+			ch := *app.Spec.Channels[name]
+			if s.Matches(ch.Meta.Annotations) {
+				ch.Meta.Parent = scope
+				channels = append(channels, ch)
+			}
+		}
+	})
+	return channels
+}
+
+// SelectTypes is the Type counterpart of SelectChannels
+func (s Selector) SelectTypes(app *meta.App, scope string) []meta.Type {
+	types := []meta.Type{}
+	walkApps(app, scope, func(app *meta.App, scope string) {
+		for _, name := range sortedKeys(MTypes(app.Spec.Types)) {
+			t := *app.Spec.Types[name]
+			if s.Matches(t.Meta.Annotations) {
+				t.Meta.Parent = scope
+				types = append(types, t)
+			}
+		}
+	})
+	return types
+}
+
+// SelectAliases is the Alias counterpart of SelectChannels
+func (s Selector) SelectAliases(app *meta.App, scope string) []meta.Alias {
+	aliases := []meta.Alias{}
+	walkApps(app, scope, func(app *meta.App, scope string) {
+		for _, name := range sortedKeys(MAliases(app.Spec.Aliases)) {
+			alias := *app.Spec.Aliases[name]
+			if s.Matches(alias.Meta.Annotations) {
+				alias.Meta.Parent = scope
+				aliases = append(aliases, alias)
+			}
+		}
+	})
+	return aliases
+}
+
+// walkApps visits the given dApp and every dApp under it, parents before
+// their children and siblings ordered by name, along with their scopes
+func walkApps(app *meta.App, scope string, visit func(*meta.App, string)) {
+	visit(app, scope)
+	for _, name := range sortedKeys(MApps(app.Spec.Apps)) {
+		childScope, _ := JoinScopes(scope, name)
+		walkApps(app.Spec.Apps[name], childScope, visit)
+	}
+}
+
+// sortedKeys returns the sorted keys of a map supported by MakeStrSet
+func sortedKeys(components interface{}) []string {
+	set, _ := MakeStrSet(components)
+	keys := set.ToArray()
+	sort.Strings(keys)
+	return keys
+}
+
+// splitSelector splits the expression on the commas that are not inside of a set of values
+func splitSelector(expression string) []string {
+	terms := []string{}
+	depth, start := 0, 0
+	for i, c := range expression {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = append(terms, expression[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(terms, expression[start:])
+}
+
+func parseRequirement(term string) (Requirement, error) {
+	if strings.HasPrefix(term, "!") && !strings.Contains(term, "=") {
+		key := strings.TrimSpace(term[1:])
+		return Requirement{Key: key, Operator: DoesNotExist}, validSelectorKey(key, term)
+	}
+
+	if fields := strings.Fields(term); len(fields) > 1 {
+		operator := Operator(fields[1])
+		if operator == In || operator == NotIn {
+			return parseSetRequirement(fields[0], operator, term)
+		}
+	}
+
+	for _, operator := range []string{"!=", "==", "="} {
+		if i := strings.Index(term, operator); i >= 0 {
+			key := strings.TrimSpace(term[:i])
+			value := strings.TrimSpace(term[i+len(operator):])
+
+			r := Requirement{Key: key, Operator: Equals, Values: []string{value}}
+			if operator == "!=" {
+				r.Operator = NotEquals
+			}
+			if err := validSelectorKey(key, term); err != nil {
+				return r, err
+			}
+			return r, validSelectorValue(value, term)
+		}
+	}
+
+	return Requirement{Key: term, Operator: Exists}, validSelectorKey(term, term)
+}
+
+func parseSetRequirement(key string, operator Operator, term string) (Requirement, error) {
+	r := Requirement{Key: key, Operator: operator, Values: []string{}}
+	if err := validSelectorKey(key, term); err != nil {
+		return r, err
+	}
+
+	// the term starts with the key, followed by the operator
+	set := strings.TrimSpace(term[len(key):])
+	set = strings.TrimSpace(set[len(operator):])
+	if !strings.HasPrefix(set, "(") || !strings.HasSuffix(set, ")") {
+		return r, ierrors.New(
+			"invalid selector requirement '%v', the values must be enclosed in parentheses", term,
+		).BadRequest()
+	}
+
+	for _, value := range strings.Split(set[1:len(set)-1], ",") {
+		value = strings.TrimSpace(value)
+		if err := validSelectorValue(value, term); err != nil {
+			return r, err
+		}
+		r.Values = append(r.Values, value)
+	}
+	return r, nil
+}
+
+func validSelectorKey(key, term string) error {
+	if key == "" || strings.ContainsAny(key, " \t!=(),") {
+		return ierrors.New("invalid key '%v' in selector requirement '%v'", key, term).BadRequest()
+	}
+	return nil
+}
+
+func validSelectorValue(value, term string) error {
+	if strings.ContainsAny(value, " \t!=(),") {
+		return ierrors.New("invalid value '%v' in selector requirement '%v'", value, term).BadRequest()
+	}
+	return nil
+}
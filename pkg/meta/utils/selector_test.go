@@ -0,0 +1,186 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+)
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		want       Selector
+		wantErr    bool
+	}{
+		{
+			name:       "empty selector",
+			expression: "",
+			want:       Selector{},
+		},
+		{
+			name:       "equality requirements",
+			expression: "env=prod, tier==web,team!=data",
+			want: Selector{
+				{Key: "env", Operator: Equals, Values: []string{"prod"}},
+				{Key: "tier", Operator: Equals, Values: []string{"web"}},
+				{Key: "team", Operator: NotEquals, Values: []string{"data"}},
+			},
+		},
+		{
+			name:       "set requirements",
+			expression: "env in (prod, staging),domain notin (a)",
+			want: Selector{
+				{Key: "env", Operator: In, Values: []string{"prod", "staging"}},
+				{Key: "domain", Operator: NotIn, Values: []string{"a"}},
+			},
+		},
+		{
+			name:       "existence requirements",
+			expression: "inspr.dev/owner,!deprecated",
+			want: Selector{
+				{Key: "inspr.dev/owner", Operator: Exists},
+				{Key: "deprecated", Operator: DoesNotExist},
+			},
+		},
+		{
+			name:       "missing key",
+			expression: "=prod",
+			wantErr:    true,
+		},
+		{
+			name:       "set without parentheses",
+			expression: "env in prod",
+			wantErr:    true,
+		},
+		{
+			name:       "invalid value",
+			expression: "env=a=b",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSelector(tt.expression)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSelector() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if !ierrors.HasCode(err, ierrors.BadRequest) {
+					t.Errorf("ParseSelector() error = %v, want BadRequest", err)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSelector() = %v, want %v", got, tt.want)
+			}
+
+			again, _ := ParseSelector(got.String())
+			if !reflect.DeepEqual(again, got) {
+				t.Errorf("Selector.String() = %v doesn't parse back into the selector", got.String())
+			}
+		})
+	}
+}
+
+func TestSelector_Matches(t *testing.T) {
+	annotations := map[string]string{"env": "prod", "tier": "web"}
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		{expression: "", want: true},
+		{expression: "env=prod", want: true},
+		{expression: "env=dev", want: false},
+		{expression: "env!=dev", want: true},
+		{expression: "team!=data", want: true},
+		{expression: "env in (dev,prod)", want: true},
+		{expression: "team in (data)", want: false},
+		{expression: "env notin (prod)", want: false},
+		{expression: "team notin (data)", want: true},
+		{expression: "tier", want: true},
+		{expression: "team", want: false},
+		{expression: "!team", want: true},
+		{expression: "env=prod,!tier", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			selector, err := ParseSelector(tt.expression)
+			if err != nil {
+				t.Fatalf("ParseSelector() error = %v", err)
+			}
+			if got := selector.Matches(annotations); got != tt.want {
+				t.Errorf("Selector.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelector_Select(t *testing.T) {
+	prod := map[string]string{"env": "prod"}
+	root := &meta.App{
+		Spec: meta.AppSpec{
+			Channels: map[string]*meta.Channel{
+				"ch1": {Meta: meta.Metadata{Name: "ch1", Annotations: prod}},
+				"ch2": {Meta: meta.Metadata{Name: "ch2"}},
+			},
+			Apps: map[string]*meta.App{
+				"app1": {
+					Meta: meta.Metadata{Name: "app1", Annotations: prod},
+					Spec: meta.AppSpec{
+						Types: map[string]*meta.Type{
+							"t1": {Meta: meta.Metadata{Name: "t1", Annotations: prod}},
+						},
+						Aliases: map[string]*meta.Alias{
+							"a1": {Meta: meta.Metadata{Name: "a1", Annotations: prod}},
+						},
+						Apps: map[string]*meta.App{
+							"app2": {
+								Meta: meta.Metadata{Name: "app2"},
+								Spec: meta.AppSpec{
+									Channels: map[string]*meta.Channel{
+										"ch3": {Meta: meta.Metadata{Name: "ch3", Annotations: prod}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	selector, _ := ParseSelector("env=prod")
+
+	apps := selector.SelectApps(root, "")
+	if len(apps) != 1 || apps[0].Meta.Name != "app1" {
+		t.Errorf("Selector.SelectApps() = %v, want app1", apps)
+	}
+
+	channels := selector.SelectChannels(root, "")
+	if len(channels) != 2 ||
+		channels[0].Meta.Name != "ch1" || channels[0].Meta.Parent != "" ||
+		channels[1].Meta.Name != "ch3" || channels[1].Meta.Parent != "app1.app2" {
+		t.Errorf("Selector.SelectChannels() = %v, want ch1 and app1.app2's ch3", channels)
+	}
+	if root.Spec.Apps["app1"].Spec.Apps["app2"].Spec.Channels["ch3"].Meta.Parent != "" {
+		t.Errorf("Selector.SelectChannels() changed the tree")
+	}
+
+	channels = selector.SelectChannels(root.Spec.Apps["app1"], "app1")
+	if len(channels) != 1 || channels[0].Meta.Name != "ch3" {
+		t.Errorf("Selector.SelectChannels() on a scope = %v, want ch3", channels)
+	}
+
+	types := selector.SelectTypes(root, "")
+	if len(types) != 1 || types[0].Meta.Parent != "app1" {
+		t.Errorf("Selector.SelectTypes() = %v, want app1's t1", types)
+	}
+
+	aliases := selector.SelectAliases(root, "")
+	if len(aliases) != 1 || aliases[0].Meta.Parent != "app1" {
+		t.Errorf("Selector.SelectAliases() = %v, want app1's a1", aliases)
+	}
+}
@@ -0,0 +1,312 @@
+package utils
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/utils"
+)
+
+// Annotations that insprd sets on the dApps created from a Template, so that
+// they can be found and rendered again when the Template is updated
+const (
+	TemplateAnnotation       = "inspr.dev/template"
+	TemplateValuesAnnotation = "inspr.dev/template-values"
+)
+
+var parameterName = regexp.MustCompile("^[A-Za-z_][A-Za-z0-9_]*$")
+
+// ValidateTemplate checks the parameters of a Template and that its App can
+// be rendered, giving the required parameters their zero values
+func ValidateTemplate(t *meta.Template) error {
+	values := map[string]string{}
+	names := utils.StringArray{}
+	for _, p := range t.Parameters {
+		if !parameterName.MatchString(p.Name) {
+			return ierrors.New(
+				"invalid parameter name '%v', must be a letter followed by letters, digits or '_'", p.Name,
+			).BadRequest()
+		}
+		if names.Contains(p.Name) {
+			return ierrors.New("parameter '%v' is defined more than once", p.Name).BadRequest()
+		}
+		names = append(names, p.Name)
+
+		if _, err := parameterValue(p, p.Default); err != nil {
+			return ierrors.Wrap(err, "invalid default value")
+		}
+		if p.Required {
+			values[p.Name] = ""
+		}
+	}
+
+	_, err := RenderTemplate(t, values)
+	return err
+}
+
+// RenderTemplate returns a copy of the App of the Template with the given values
+// substituted into its names, images, environment variables, annotations,
+// boundaries and aliases, and into the ones of its children
+func RenderTemplate(t *meta.Template, values map[string]string) (*meta.App, error) {
+	typed, err := parameterValues(t, values)
+	if err != nil {
+		return nil, err
+	}
+
+	app := &meta.App{}
+	if err := DeepCopy(t.App, app); err != nil {
+		return nil, ierrors.Wrap(err, "unable to copy the template's dApp")
+	}
+
+	r := renderer{values: typed}
+	r.app(app)
+	if r.err != nil {
+		return nil, ierrors.Wrap(r.err, "unable to render template "+t.Meta.Name)
+	}
+	return app, nil
+}
+
+// InstantiateTemplate returns the dApp of the given instance of the Template in
+// the given path, which is annotated with the path and with the instance's values
+func InstantiateTemplate(t *meta.Template, path string, instance *meta.TemplateInstance) (*meta.App, error) {
+	app, err := RenderTemplate(t, instance.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	values, _ := json.Marshal(instance.Values)
+	app.Meta.Name = instance.Meta.Name
+	app.Meta.Parent = instance.Meta.Parent
+	if app.Meta.Annotations == nil {
+		app.Meta.Annotations = map[string]string{}
+	}
+	for key, value := range instance.Meta.Annotations {
+		app.Meta.Annotations[key] = value
+	}
+	app.Meta.Annotations[TemplateAnnotation] = path
+	app.Meta.Annotations[TemplateValuesAnnotation] = string(values)
+	return app, nil
+}
+
+// InstanceOf returns the instance a dApp was created from, and false if
+// it wasn't created from a Template
+func InstanceOf(app *meta.App) (*meta.TemplateInstance, bool) {
+	path, ok := app.Meta.Annotations[TemplateAnnotation]
+	if !ok {
+		return nil, false
+	}
+
+	instance := &meta.TemplateInstance{
+		Meta: meta.Metadata{
+			Name:        app.Meta.Name,
+			Parent:      app.Meta.Parent,
+			Annotations: app.Meta.Annotations,
+		},
+		Template: path,
+		Values:   map[string]string{},
+	}
+	json.Unmarshal([]byte(app.Meta.Annotations[TemplateValuesAnnotation]), &instance.Values)
+	return instance, true
+}
+
+// TemplateInstances returns the sorted scopes of the dApps under the given
+// dApp, which is in the given scope, created from the Template in path
+func TemplateInstances(app *meta.App, scope, path string) []string {
+	instances := []string{}
+	if app.Meta.Annotations[TemplateAnnotation] == path {
+		instances = append(instances, scope)
+	}
+	for name, child := range app.Spec.Apps {
+		childScope, _ := JoinScopes(scope, name)
+		instances = append(instances, TemplateInstances(child, childScope, path)...)
+	}
+	sort.Strings(instances)
+	return instances
+}
+
+// parameterValues returns the typed values of the parameters of the Template,
+// taking the default of the ones that were not given
+func parameterValues(t *meta.Template, values map[string]string) (map[string]interface{}, error) {
+	params := map[string]meta.TemplateParameter{}
+	for _, p := range t.Parameters {
+		params[p.Name] = p
+	}
+
+	given := utils.StringArray{}
+	for name := range values {
+		given = append(given, name)
+	}
+	for _, name := range given.Sorted() {
+		if _, ok := params[name]; !ok {
+			return nil, ierrors.New(
+				"template %v has no parameter '%v'", t.Meta.Name, name,
+			).BadRequest()
+		}
+	}
+
+	typed := map[string]interface{}{}
+	for _, p := range t.Parameters {
+		value, ok := values[p.Name]
+		if !ok {
+			if p.Required {
+				return nil, ierrors.New(
+					"missing value for the required parameter '%v' of template %v", p.Name, t.Meta.Name,
+				).BadRequest()
+			}
+			value = p.Default
+		}
+
+		v, err := parameterValue(p, value)
+		if err != nil {
+			return nil, err
+		}
+		typed[p.Name] = v
+	}
+	return typed, nil
+}
+
+// parameterValue converts the value of a parameter to its type, the empty
+// string being the zero value of every type
+func parameterValue(p meta.TemplateParameter, value string) (interface{}, error) {
+	switch p.Type {
+	case "", meta.ParameterString:
+		return value, nil
+
+	case meta.ParameterInt:
+		if value == "" {
+			return 0, nil
+		}
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, ierrors.New("value '%v' of parameter '%v' isn't an int", value, p.Name).BadRequest()
+		}
+		return v, nil
+
+	case meta.ParameterBool:
+		if value == "" {
+			return false, nil
+		}
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, ierrors.New("value '%v' of parameter '%v' isn't a bool", value, p.Name).BadRequest()
+		}
+		return v, nil
+	}
+	return nil, ierrors.New(
+		"invalid type '%v' for parameter '%v', use %v, %v or %v",
+		p.Type, p.Name, meta.ParameterString, meta.ParameterInt, meta.ParameterBool,
+	).BadRequest()
+}
+
+// renderer substitutes the values of the parameters of a Template into the
+// fields of a dApp, keeping the first error found
+type renderer struct {
+	values map[string]interface{}
+	err    error
+}
+
+func (r *renderer) str(s string) string {
+	if r.err != nil || !strings.Contains(s, "{{") {
+		return s
+	}
+
+	tmpl, err := template.New("").Option("missingkey=error").Parse(s)
+	if err != nil {
+		r.err = ierrors.New(err).BadRequest()
+		return s
+	}
+
+	b := &strings.Builder{}
+	if err := tmpl.Execute(b, r.values); err != nil {
+		r.err = ierrors.New(err).BadRequest()
+		return s
+	}
+	return b.String()
+}
+
+func (r *renderer) strs(arr utils.StringArray) utils.StringArray {
+	if arr == nil {
+		return nil
+	}
+	return arr.Map(r.str)
+}
+
+func (r *renderer) meta(m *meta.Metadata) {
+	m.Name = r.str(m.Name)
+	if m.Annotations == nil {
+		return
+	}
+	annotations := make(map[string]string, len(m.Annotations))
+	for key, value := range m.Annotations {
+		annotations[r.str(key)] = r.str(value)
+	}
+	m.Annotations = annotations
+}
+
+func (r *renderer) app(app *meta.App) {
+	r.meta(&app.Meta)
+	r.meta(&app.Spec.Node.Meta)
+	app.Spec.Node.Spec.Image = r.str(app.Spec.Node.Spec.Image)
+	for key, value := range app.Spec.Node.Spec.Environment {
+		app.Spec.Node.Spec.Environment[key] = r.str(value)
+	}
+
+	app.Spec.Boundary.Channels.Input = r.strs(app.Spec.Boundary.Channels.Input)
+	app.Spec.Boundary.Channels.Output = r.strs(app.Spec.Boundary.Channels.Output)
+	app.Spec.Boundary.Routes = r.strs(app.Spec.Boundary.Routes)
+
+	if app.Spec.Apps != nil {
+		apps := make(map[string]*meta.App, len(app.Spec.Apps))
+		for name, child := range app.Spec.Apps {
+			if child.Meta.Name == "" {
+				child.Meta.Name = name
+			}
+			r.app(child)
+			apps[r.str(name)] = child
+		}
+		app.Spec.Apps = apps
+	}
+
+	if app.Spec.Channels != nil {
+		channels := make(map[string]*meta.Channel, len(app.Spec.Channels))
+		for name, ch := range app.Spec.Channels {
+			if ch.Meta.Name == "" {
+				ch.Meta.Name = name
+			}
+			r.meta(&ch.Meta)
+			ch.Spec.Type = r.str(ch.Spec.Type)
+			channels[r.str(name)] = ch
+		}
+		app.Spec.Channels = channels
+	}
+
+	if app.Spec.Types != nil {
+		types := make(map[string]*meta.Type, len(app.Spec.Types))
+		for name, t := range app.Spec.Types {
+			if t.Meta.Name == "" {
+				t.Meta.Name = name
+			}
+			r.meta(&t.Meta)
+			types[r.str(name)] = t
+		}
+		app.Spec.Types = types
+	}
+
+	if app.Spec.Aliases != nil {
+		aliases := make(map[string]*meta.Alias, len(app.Spec.Aliases))
+		for name, alias := range app.Spec.Aliases {
+			r.meta(&alias.Meta)
+			alias.Resource = r.str(alias.Resource)
+			alias.Source = r.str(alias.Source)
+			alias.Destination = r.str(alias.Destination)
+			aliases[r.str(name)] = alias
+		}
+		app.Spec.Aliases = aliases
+	}
+}
@@ -0,0 +1,238 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/utils"
+)
+
+func pipelineTemplate() *meta.Template {
+	return &meta.Template{
+		Meta: meta.Metadata{Name: "pipeline"},
+		Parameters: []meta.TemplateParameter{
+			{Name: "customer", Required: true},
+			{Name: "version", Default: "latest"},
+			{Name: "workers", Type: meta.ParameterInt, Default: "1"},
+			{Name: "debug", Type: meta.ParameterBool},
+		},
+		App: meta.App{
+			Meta: meta.Metadata{
+				Annotations: map[string]string{"customer": "{{ .customer }}"},
+			},
+			Spec: meta.AppSpec{
+				Channels: map[string]*meta.Channel{
+					"{{ .customer }}-input": {Spec: meta.ChannelSpec{Type: "event"}},
+				},
+				Apps: map[string]*meta.App{
+					"reader": {
+						Spec: meta.AppSpec{
+							Node: meta.Node{Spec: meta.NodeSpec{
+								Image: "reader:{{ .version }}",
+								Environment: utils.EnvironmentMap{
+									"WORKERS": "{{ .workers }}",
+									"LOG":     "{{ if .debug }}debug{{ else }}info{{ end }}",
+								},
+							}},
+							Boundary: meta.AppBoundary{
+								Channels: meta.Boundary{Input: utils.StringArray{"input"}},
+							},
+						},
+					},
+				},
+				Aliases: map[string]*meta.Alias{
+					"reader.input": {Resource: "{{ .customer }}-input"},
+				},
+			},
+		},
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   map[string]string
+		wantErr  bool
+		wantCode ierrors.ErrCode
+		check    func(t *testing.T, app *meta.App)
+	}{
+		{
+			name:   "values and defaults are substituted",
+			values: map[string]string{"customer": "acme", "debug": "true"},
+			check: func(t *testing.T, app *meta.App) {
+				reader := app.Spec.Apps["reader"]
+				want := utils.EnvironmentMap{"WORKERS": "1", "LOG": "debug"}
+				if !reflect.DeepEqual(reader.Spec.Node.Spec.Environment, want) {
+					t.Errorf("environment = %v, want %v", reader.Spec.Node.Spec.Environment, want)
+				}
+				if reader.Spec.Node.Spec.Image != "reader:latest" {
+					t.Errorf("image = %v, want reader:latest", reader.Spec.Node.Spec.Image)
+				}
+				if reader.Meta.Name != "reader" {
+					t.Errorf("child name = %v, want reader", reader.Meta.Name)
+				}
+				ch, ok := app.Spec.Channels["acme-input"]
+				if !ok || ch.Meta.Name != "acme-input" {
+					t.Errorf("channels = %v, want acme-input", app.Spec.Channels)
+				}
+				if app.Spec.Aliases["reader.input"].Resource != "acme-input" {
+					t.Errorf("alias resource = %v, want acme-input", app.Spec.Aliases["reader.input"].Resource)
+				}
+				if app.Meta.Annotations["customer"] != "acme" {
+					t.Errorf("annotations = %v, want customer acme", app.Meta.Annotations)
+				}
+			},
+		},
+		{
+			name:     "missing required parameter",
+			values:   map[string]string{},
+			wantErr:  true,
+			wantCode: ierrors.BadRequest,
+		},
+		{
+			name:     "unknown parameter",
+			values:   map[string]string{"customer": "acme", "region": "us"},
+			wantErr:  true,
+			wantCode: ierrors.BadRequest,
+		},
+		{
+			name:     "value of the wrong type",
+			values:   map[string]string{"customer": "acme", "workers": "many"},
+			wantErr:  true,
+			wantCode: ierrors.BadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := pipelineTemplate()
+			got, err := RenderTemplate(tmpl, tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if ierrors.Code(err) != tt.wantCode {
+					t.Errorf("RenderTemplate() error code = %v, want %v", ierrors.Code(err), tt.wantCode)
+				}
+				return
+			}
+			tt.check(t, got)
+			if _, ok := tmpl.App.Spec.Channels["{{ .customer }}-input"]; !ok {
+				t.Errorf("RenderTemplate() changed the template")
+			}
+		})
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(tmpl *meta.Template)
+		wantErr bool
+	}{
+		{
+			name:   "valid template",
+			change: func(tmpl *meta.Template) {},
+		},
+		{
+			name: "invalid parameter name",
+			change: func(tmpl *meta.Template) {
+				tmpl.Parameters[0].Name = "my-customer"
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicated parameter",
+			change: func(tmpl *meta.Template) {
+				tmpl.Parameters = append(tmpl.Parameters, meta.TemplateParameter{Name: "customer"})
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid parameter type",
+			change: func(tmpl *meta.Template) {
+				tmpl.Parameters[0].Type = "float"
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid default value",
+			change: func(tmpl *meta.Template) {
+				tmpl.Parameters[2].Default = "one"
+			},
+			wantErr: true,
+		},
+		{
+			name: "undefined parameter",
+			change: func(tmpl *meta.Template) {
+				tmpl.App.Spec.Apps["reader"].Spec.Node.Spec.Image = "reader:{{ .tag }}"
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid template syntax",
+			change: func(tmpl *meta.Template) {
+				tmpl.App.Spec.Apps["reader"].Spec.Node.Spec.Image = "reader:{{ .version"
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := pipelineTemplate()
+			tt.change(tmpl)
+			err := ValidateTemplate(tmpl)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && ierrors.Code(err) != ierrors.BadRequest {
+				t.Errorf("ValidateTemplate() error code = %v, want %v", ierrors.Code(err), ierrors.BadRequest)
+			}
+		})
+	}
+}
+
+func TestInstantiateTemplate(t *testing.T) {
+	instance := &meta.TemplateInstance{
+		Meta: meta.Metadata{
+			Name:        "acme",
+			Parent:      "customers",
+			Annotations: map[string]string{"team": "data"},
+		},
+		Template: "templates.pipeline",
+		Values:   map[string]string{"customer": "acme"},
+	}
+
+	app, err := InstantiateTemplate(pipelineTemplate(), "templates.pipeline", instance)
+	if err != nil {
+		t.Fatalf("InstantiateTemplate() error = %v", err)
+	}
+	if app.Meta.Name != "acme" || app.Meta.Parent != "customers" {
+		t.Errorf("InstantiateTemplate() meta = %v, want the name and parent of the instance", app.Meta)
+	}
+	if app.Meta.Annotations["team"] != "data" || app.Meta.Annotations["customer"] != "acme" {
+		t.Errorf("InstantiateTemplate() annotations = %v, want the ones of the template and of the instance",
+			app.Meta.Annotations)
+	}
+
+	got, ok := InstanceOf(app)
+	if !ok {
+		t.Fatalf("InstanceOf() = false, want the instance")
+	}
+	if got.Template != instance.Template || !reflect.DeepEqual(got.Values, instance.Values) {
+		t.Errorf("InstanceOf() = %v, want %v", got, instance)
+	}
+
+	root := &meta.App{Spec: meta.AppSpec{Apps: map[string]*meta.App{
+		"customers": {Spec: meta.AppSpec{Apps: map[string]*meta.App{"acme": app}}},
+		"other":     {},
+	}}}
+	instances := TemplateInstances(root, "", "templates.pipeline")
+	if !reflect.DeepEqual(instances, []string{"customers.acme"}) {
+		t.Errorf("TemplateInstances() = %v, want [customers.acme]", instances)
+	}
+	if _, ok := InstanceOf(root.Spec.Apps["other"]); ok {
+		t.Errorf("InstanceOf() = true for a dApp that isn't an instance")
+	}
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+)
+
+// TypeHistory returns a copy of the versions of the schema of the given Type,
+// oldest first. When the last version isn't the current schema of the Type,
+// which is the case of Types created before their schemas were versioned,
+// the current schema is added as a new version
+func TypeHistory(t *meta.Type) []meta.TypeVersion {
+	history := make([]meta.TypeVersion, len(t.Versions), len(t.Versions)+1)
+	copy(history, t.Versions)
+
+	if len(history) == 0 || history[len(history)-1].Schema != t.Schema {
+		history = append(history, meta.TypeVersion{
+			Version: len(history) + 1,
+			Schema:  t.Schema,
+		})
+	}
+	return history
+}
+
+// AddTypeVersion sets the versions of the updated Type to the ones of the
+// current Type, adding its schema as a new version when it changed
+func AddTypeVersion(current, updated *meta.Type) {
+	updated.Versions = TypeHistory(current)
+	updated.Versions = TypeHistory(updated)
+}
+
+// TypeSchema returns the schema of the given version of a Type, along with
+// the number of the version. Version 0 refers to the latest version
+func TypeSchema(t *meta.Type, version int) (string, int, error) {
+	history := TypeHistory(t)
+	if version == 0 {
+		latest := history[len(history)-1]
+		return latest.Schema, latest.Version, nil
+	}
+
+	for _, v := range history {
+		if v.Version == version {
+			return v.Schema, v.Version, nil
+		}
+	}
+	return "", 0, ierrors.New(
+		"Type '%v' doesn't have a version %v", t.Meta.Name, version,
+	).BadRequest()
+}
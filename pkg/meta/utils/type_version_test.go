@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"inspr.dev/inspr/pkg/meta"
+)
+
+func TestAddTypeVersion(t *testing.T) {
+	current := &meta.Type{Meta: meta.Metadata{Name: "user"}, Schema: `"int"`}
+
+	updated := &meta.Type{Meta: meta.Metadata{Name: "user"}, Schema: `"long"`}
+	AddTypeVersion(current, updated)
+	want := []meta.TypeVersion{{Version: 1, Schema: `"int"`}, {Version: 2, Schema: `"long"`}}
+	if !reflect.DeepEqual(updated.Versions, want) {
+		t.Errorf("AddTypeVersion() = %v, want %v", updated.Versions, want)
+	}
+	if len(current.Versions) != 0 {
+		t.Errorf("AddTypeVersion() changed the current Type")
+	}
+
+	unchanged := &meta.Type{Meta: meta.Metadata{Name: "user"}, Schema: `"long"`}
+	AddTypeVersion(updated, unchanged)
+	if !reflect.DeepEqual(unchanged.Versions, want) {
+		t.Errorf("AddTypeVersion() = %v, want %v", unchanged.Versions, want)
+	}
+}
+
+func TestTypeSchema(t *testing.T) {
+	insprType := &meta.Type{
+		Meta:   meta.Metadata{Name: "user"},
+		Schema: `"long"`,
+		Versions: []meta.TypeVersion{
+			{Version: 1, Schema: `"int"`},
+			{Version: 2, Schema: `"long"`},
+		},
+	}
+	tests := []struct {
+		name        string
+		version     int
+		wantSchema  string
+		wantVersion int
+		wantErr     bool
+	}{
+		{name: "latest version", version: 0, wantSchema: `"long"`, wantVersion: 2},
+		{name: "pinned version", version: 1, wantSchema: `"int"`, wantVersion: 1},
+		{name: "unknown version", version: 3, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, version, err := TypeSchema(insprType, tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TypeSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if schema != tt.wantSchema || version != tt.wantVersion {
+				t.Errorf("TypeSchema() = %v, %v, want %v, %v", schema, version, tt.wantSchema, tt.wantVersion)
+			}
+		})
+	}
+}
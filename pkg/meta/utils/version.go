@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+)
+
+// ValidateResourceVersion returns a Conflict error when the given resource version
+// is set and differs from the one on the current metadata. Unset versions are always
+// valid, so that requests without them overwrite the component unconditionally;
+// insprctl only sends them unset when an update is forced.
+func ValidateResourceVersion(current meta.Metadata, version int) error {
+	if version != 0 && version != current.ResourceVersion {
+		return ierrors.New(
+			"'%v' was changed since it was read, its resource version is %v and not %v",
+			current.Name, current.ResourceVersion, version,
+		).Conflict()
+	}
+	return nil
+}
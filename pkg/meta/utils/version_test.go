@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"testing"
+
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+)
+
+func TestValidateResourceVersion(t *testing.T) {
+	current := meta.Metadata{Name: "app1", ResourceVersion: 3}
+	tests := []struct {
+		name    string
+		version int
+		wantErr bool
+	}{
+		{
+			name:    "current version",
+			version: 3,
+		},
+		{
+			name:    "unset version",
+			version: 0,
+		},
+		{
+			name:    "stale version",
+			version: 2,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateResourceVersion(current, tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateResourceVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !ierrors.HasCode(err, ierrors.Conflict) {
+				t.Errorf("ValidateResourceVersion() error = %v, want Conflict", err)
+			}
+		})
+	}
+}
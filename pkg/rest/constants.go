@@ -26,12 +26,30 @@ var operationTranslator = map[string]string{
 // proceeds to translate into a string that represents its abbreviation used for
 // the const permission in the auth pkg.
 var routeTranslator = map[string]string{
-	"apps":          "dapp",
-	"channels":      "channel",
-	"types":         "type",
-	"auth":          "token",
-	"brokers":       "broker",
-	"brokers/kafka": "broker",
+	"apps":           "dapp",
+	"channels":       "channel",
+	"types":          "type",
+	"auth":           "token",
+	"brokers":        "broker",
+	"brokers/kafka":  "broker",
+	"brokers/memory": "broker",
+
+	"channels/deadletters": "channel",
+
+	"templates":           "template",
+	"templates/instances": "dapp",
+
+	"revisions":          "revision",
+	"revisions/rollback": "revision",
+
+	"snapshot":        "snapshot",
+	"snapshot/import": "snapshot",
+
+	"status":           "status",
+	"status/reconcile": "status",
+
+	"graph": "dapp",
+	"watch": "dapp",
 }
 
 var defaultErr = ierrors.
@@ -26,11 +26,12 @@ func HandleCRUD(handler CRUDHandler) Handler {
 		switch r.Method {
 
 		case http.MethodGet:
+			// reads don't start transactions, so there is nothing to cancel
 			handler.
 				HandleGet().
 				Validate(handler.GetAuth()).
 				JSON().
-				Recover(handler.GetCancel())(w, r)
+				Recover()(w, r)
 
 		case http.MethodPost:
 			handler.
@@ -69,7 +70,7 @@ func (h Handler) JSON() Handler {
 }
 
 // Validate handles the token validation of the http requests made, it receives an implementation of the auth interface as a parameter.
-func (h Handler) Validate(auth auth.Auth) Handler {
+func (h Handler) Validate(authenticator auth.Auth) Handler {
 	logger, _ := logs.Logger(zap.Fields(zap.String("section", "api"), zap.String("subSection", "authorization-middleware")))
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Authorization: Bearer <token>
@@ -83,7 +84,7 @@ func (h Handler) Validate(auth auth.Auth) Handler {
 		}
 
 		token := strings.TrimPrefix(headerContent[0], "Bearer ")
-		payload, newToken, err := auth.Validate([]byte(token))
+		payload, newToken, err := authenticator.Validate([]byte(token))
 		logger.Debug("payload after validation")
 
 		// returns the same token or a refreshed one in the header of the response
@@ -115,6 +116,8 @@ func (h Handler) Validate(auth auth.Auth) Handler {
 
 		authScopes, allowed := payload.Permissions[perm]
 		if allowed {
+			// the payload is made available for the handlers, i.e. for auditing
+			r = r.WithContext(auth.WithPayload(r.Context(), payload))
 			if len(authScopes) == 0 {
 				logger.Info("permission granted for request", zap.String("request", perm))
 				h(w, r)
@@ -295,6 +295,16 @@ func Test_getTarget(t *testing.T) {
 			args: args{createReq("types")},
 			want: "type",
 		},
+		{
+			name: "exception_memory_broker",
+			args: args{createReq("brokers/memory")},
+			want: "broker",
+		},
+		{
+			name: "exception_deadletters",
+			args: args{createReq("channels/deadletters")},
+			want: "channel",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -304,3 +314,48 @@ func Test_getTarget(t *testing.T) {
 		})
 	}
 }
+
+// Test_routePermissions checks that the permission required by each route is
+// one the admin of the cluster holds, so that no route is left unreachable
+func Test_routePermissions(t *testing.T) {
+	tests := []struct {
+		route  string
+		method string
+	}{
+		{route: "brokers", method: http.MethodGet},
+		{route: "brokers/kafka", method: http.MethodPost},
+		{route: "brokers/memory", method: http.MethodPost},
+		{route: "channels/deadletters", method: http.MethodGet},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, "https://localhost:8080/"+tt.route, nil)
+
+			perm := getOperation(req) + ":" + getTarget(req)
+			if _, ok := auth.AdminPermissions[perm]; !ok {
+				t.Errorf("%v %v requires %v, which no admin holds", tt.method, tt.route, perm)
+			}
+		})
+	}
+}
+
+func TestHandler_Validate_payload(t *testing.T) {
+	var got *auth.Payload
+	handler := Handler(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = auth.PayloadFromContext(r.Context())
+	})
+
+	ts := httptest.NewServer(handler.Validate(authMock.NewMockAuth(nil)))
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/channels", nil)
+	req.Header.Add("Authorization", "Bearer mock_token")
+	req.Header.Add("Scope", "scope_1")
+	if _, err := ts.Client().Do(req); err != nil {
+		t.Fatal("couldn't receive response")
+	}
+
+	if got == nil || got.UID != "uid" {
+		t.Errorf("Handler.Validate() payload on context = %v, want uid", got)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"go.uber.org/zap"
 	"inspr.dev/inspr/pkg/ierrors"
 	"inspr.dev/inspr/pkg/logs"
+	"inspr.dev/inspr/pkg/tracing"
 )
 
 var logger *zap.Logger
@@ -35,14 +36,65 @@ var (
 		InternalServer()
 )
 
+// ResponseError is the error of a response whose body has more details than
+// the ierror in it, such as the report of the reactions that insprd undid. It
+// wraps the ierror, so its code can still be checked, and keeps the body so
+// that the caller can decode the details it knows about.
+type ResponseError struct {
+	Err  error
+	Body []byte
+}
+
+// Error returns the message of the ierror in the response
+func (e *ResponseError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the ierror in the response
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
+
 // Send sends a request to the url specified in instantiation, with the given
 // route and method, using
 // the encoder to encode the body and the decoder to decode the response into
 // the responsePtr
 func (c Client) Send(ctx context.Context, route, method string, body, responsePtr interface{}) (err error) {
+	resp, err := c.do(ctx, route, method, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if responsePtr != nil {
+		decoder := json.NewDecoder(resp.Body)
+		err = decoder.Decode(responsePtr)
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// Stream sends a request just like Send, but instead of decoding the response
+// it returns its body, so that long lived responses can be read as they arrive.
+// The caller is responsible for closing the returned body.
+func (c Client) Stream(ctx context.Context, route, method string, body interface{}) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, route, method, body)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// do sends the request and handles the response status and token refresh,
+// returning the response with its body still open
+func (c Client) do(ctx context.Context, route, method string, body interface{}) (*http.Response, error) {
 	buf, err := c.encoder(body)
 	if err != nil {
-		return ierrors.Wrap(
+		return nil, ierrors.Wrap(
 			ierrors.New(err).BadRequest(),
 			"error encoding body to json",
 		)
@@ -58,52 +110,45 @@ func (c Client) Send(ctx context.Context, route, method string, body, responsePt
 	logger.Debug("Sending request to:" + c.routeToURL(route))
 
 	if err != nil {
-		return ierrors.Wrap(err, "error creating request")
+		return nil, ierrors.Wrap(err, "error creating request")
 	}
 
 	for key, values := range c.headers {
 		req.Header[key] = values
 	}
+	tracing.InjectHeader(ctx, req.Header)
 
 	req.Host = c.host
 
 	if c.auth != nil {
 		token, err := c.auth.GetToken()
 		if err != nil {
-			return ierrors.Wrap(err, "unable to get token from configuration")
+			return nil, ierrors.Wrap(err, "unable to get token from configuration")
 		}
 		req.Header.Add("Authorization", string(token))
 	}
 
 	resp, err := c.c.Do(req)
 	if err != nil {
-		return ierrors.New(err).BadRequest()
+		return nil, ierrors.New(err).BadRequest()
 	}
-	defer resp.Body.Close()
 
 	err = c.handleResponseErr(resp)
 	if err != nil {
-		return err
+		resp.Body.Close()
+		return nil, err
 	}
 
 	updatedToken := resp.Header.Get("Authorization")
 	if c.auth != nil && updatedToken != "" {
 		err := c.auth.SetToken([]byte(updatedToken))
 		if err != nil {
-			return ierrors.Wrap(err, "unable to update token")
+			resp.Body.Close()
+			return nil, ierrors.Wrap(err, "unable to update token")
 		}
 	}
 
-	if responsePtr != nil {
-		decoder := json.NewDecoder(resp.Body)
-		err = decoder.Decode(responsePtr)
-
-		if errors.Is(err, io.EOF) {
-			return nil
-		}
-	}
-
-	return err
+	return resp, nil
 }
 
 func (c Client) handleResponseErr(resp *http.Response) error {
@@ -130,10 +175,29 @@ func (c Client) handleResponseErr(resp *http.Response) error {
 		return ierrors.New("route not found")
 
 	default:
-		decoder.Decode(&err)
+		body, _ := io.ReadAll(resp.Body)
+		c.decoderGenerator(bytes.NewReader(body)).Decode(&err)
 		if err == nil {
 			return DefaultErr
 		}
+		if hasErrorDetails(body) {
+			return &ResponseError{Err: err, Body: body}
+		}
 		return err
 	}
 }
+
+// hasErrorDetails returns whether the json body of an error response has more
+// fields than the ones of an ierror
+func hasErrorDetails(body []byte) bool {
+	fields := map[string]json.RawMessage{}
+	if json.Unmarshal(body, &fields) != nil {
+		return false
+	}
+	for field := range fields {
+		if field != "stack" && field != "code" {
+			return true
+		}
+	}
+	return false
+}
@@ -5,11 +5,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 
 	"inspr.dev/inspr/pkg/ierrors"
@@ -393,3 +395,72 @@ func TestClient_handleResponseErr(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_handleResponseErr_details(t *testing.T) {
+	c := &Client{decoderGenerator: JSONDecoderGenerator}
+	body := fmt.Sprintf(`{"stack":"reaction failed","code":%d,"report":{"steps":[]}}`, ierrors.InternalServer)
+	err := c.handleResponseErr(&http.Response{
+		StatusCode: http.StatusInternalServerError,
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	})
+
+	respErr, ok := err.(*ResponseError)
+	if !ok {
+		t.Fatalf("Client.handleResponseErr() error = %T, want a ResponseError", err)
+	}
+	if string(respErr.Body) != body {
+		t.Errorf("Client.handleResponseErr() body = %s, want %s", respErr.Body, body)
+	}
+	if !ierrors.HasCode(err, ierrors.InternalServer) || err.Error() != respErr.Err.Error() {
+		t.Errorf("Client.handleResponseErr() error = %v, want the response ierror", err)
+	}
+}
+
+func TestClient_Stream(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{
+			name:   "returns the open response body",
+			status: http.StatusOK,
+		},
+		{
+			name:    "fails on error responses",
+			status:  http.StatusBadRequest,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				if tt.wantErr {
+					json.NewEncoder(w).Encode(ierrors.New("wants error").BadRequest())
+					return
+				}
+				w.Write([]byte("first\n"))
+				w.(http.Flusher).Flush()
+				w.Write([]byte("second\n"))
+			}
+			s := httptest.NewServer(http.HandlerFunc(handler))
+			defer s.Close()
+
+			c := NewJSONClient(s.URL)
+			body, err := c.Stream(context.Background(), "/", http.MethodGet, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Client.Stream() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			defer body.Close()
+
+			got, _ := ioutil.ReadAll(body)
+			if string(got) != "first\nsecond\n" {
+				t.Errorf("Client.Stream() body = %q", got)
+			}
+		})
+	}
+}
@@ -64,6 +64,8 @@ func ERROR(w http.ResponseWriter, err error) {
 		JSON(w, http.StatusUnauthorized, err)
 	case ierrors.Forbidden:
 		JSON(w, http.StatusForbidden, err)
+	case ierrors.Conflict:
+		JSON(w, http.StatusConflict, err)
 	default: // default case
 		JSON(w, http.StatusInternalServerError, err)
 	}
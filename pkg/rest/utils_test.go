@@ -103,6 +103,11 @@ func TestERROR(t *testing.T) {
 			err:  ierrors.New("").BadRequest(),
 			want: http.StatusBadRequest,
 		},
+		{
+			name: "InsprErrors_Conflict",
+			err:  ierrors.New("").Conflict(),
+			want: http.StatusConflict,
+		},
 		{
 			name: "InsprErrors_Unknown_ErrCode",
 			err:  ierrors.New(""),
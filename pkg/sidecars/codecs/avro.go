@@ -0,0 +1,36 @@
+package codecs
+
+import (
+	"github.com/linkedin/goavro"
+	"inspr.dev/inspr/pkg/ierrors"
+)
+
+type avroCodec struct {
+	codec *goavro.Codec
+}
+
+// NewAvroCodec returns a Codec that encodes messages in the binary
+// encoding of Avro
+func NewAvroCodec(schema string) (Codec, error) {
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		return nil, err
+	}
+	return &avroCodec{codec: codec}, nil
+}
+
+func (c *avroCodec) Encode(message interface{}) ([]byte, error) {
+	encoded, err := c.codec.BinaryFromNative(nil, message)
+	if err != nil {
+		return nil, ierrors.New("[ENCODE] %v", err).BadRequest()
+	}
+	return encoded, nil
+}
+
+func (c *avroCodec) Decode(data []byte) (interface{}, error) {
+	message, _, err := c.codec.NativeFromBinary(data)
+	if err != nil {
+		return nil, ierrors.New("[DECODE] %v", err)
+	}
+	return message, nil
+}
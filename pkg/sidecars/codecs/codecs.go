@@ -0,0 +1,72 @@
+// Package codecs contains the codecs the load balancer sidecar uses to encode
+// and decode the messages of channels, one for each format of the schemas of
+// Types, along with the registry that selects them by format.
+package codecs
+
+import (
+	"sync"
+
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+)
+
+// Codec encodes and decodes messages with the schema it was created with.
+// Messages are in their native form, as they are decoded from JSON
+type Codec interface {
+	// Encode validates the message against the schema and encodes it,
+	// returning a BadRequest error with the path of the invalid fields
+	Encode(message interface{}) ([]byte, error)
+	// Decode decodes an encoded message
+	Decode(data []byte) (interface{}, error)
+}
+
+// Factory creates the Codec of a schema, failing when the schema is invalid
+type Factory func(schema string) (Codec, error)
+
+var (
+	mutex     sync.RWMutex
+	factories = map[string]Factory{
+		meta.FormatAvro:       NewAvroCodec,
+		meta.FormatJSONSchema: NewJSONSchemaCodec,
+		meta.FormatProtobuf:   NewProtobufCodec,
+	}
+)
+
+// Subscribe includes a codec for the given format in the registry
+func Subscribe(format string, factory Factory) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if _, ok := factories[format]; ok {
+		return ierrors.New("%s format already subscribed", format).AlreadyExists()
+	}
+	factories[format] = factory
+	return nil
+}
+
+// New returns the Codec of the given schema, in the given format. Schemas
+// without a format are Avro schemas
+func New(format, schema string) (Codec, error) {
+	format = Format(format)
+
+	mutex.RLock()
+	factory, ok := factories[format]
+	mutex.RUnlock()
+	if !ok {
+		return nil, ierrors.New("unknown schema format '%s'", format).BadRequest()
+	}
+
+	codec, err := factory(schema)
+	if err != nil {
+		return nil, ierrors.New("invalid %s schema: %v", format, err).BadRequest()
+	}
+	return codec, nil
+}
+
+// Format returns the given format, or the default format when it's empty
+func Format(format string) string {
+	if format == "" {
+		return meta.FormatAvro
+	}
+	return format
+}
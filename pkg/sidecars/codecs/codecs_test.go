@@ -0,0 +1,123 @@
+package codecs
+
+import (
+	"reflect"
+	"testing"
+
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+)
+
+type fakeCodec struct{}
+
+func (fakeCodec) Encode(message interface{}) ([]byte, error) { return []byte("fake"), nil }
+func (fakeCodec) Decode(data []byte) (interface{}, error)    { return "fake", nil }
+
+func TestSubscribe(t *testing.T) {
+	factory := func(schema string) (Codec, error) { return fakeCodec{}, nil }
+
+	if err := Subscribe("fake", factory); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	t.Cleanup(func() {
+		mutex.Lock()
+		delete(factories, "fake")
+		mutex.Unlock()
+	})
+
+	if err := Subscribe("fake", factory); !ierrors.HasCode(err, ierrors.AlreadyExists) {
+		t.Errorf("Subscribe() of a subscribed format error = %v, want AlreadyExists", err)
+	}
+	if err := Subscribe(meta.FormatAvro, factory); !ierrors.HasCode(err, ierrors.AlreadyExists) {
+		t.Errorf("Subscribe() of a built-in format error = %v, want AlreadyExists", err)
+	}
+
+	codec, err := New("fake", "")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, ok := codec.(fakeCodec); !ok {
+		t.Errorf("New() = %T, want the subscribed codec", codec)
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		schema  string
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name:   "format defaults to avro",
+			format: "",
+			schema: `{"type":"string"}`,
+			want:   &avroCodec{},
+		},
+		{
+			name:   "avro",
+			format: meta.FormatAvro,
+			schema: `{"type":"string"}`,
+			want:   &avroCodec{},
+		},
+		{
+			name:   "jsonschema",
+			format: meta.FormatJSONSchema,
+			schema: `{"type":"string"}`,
+			want:   &jsonSchemaCodec{},
+		},
+		{
+			name:    "invalid schema",
+			format:  meta.FormatAvro,
+			schema:  `{"type":"invalid"}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown format",
+			format:  "xml",
+			schema:  `<schema/>`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.format, tt.schema)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !ierrors.HasCode(err, ierrors.BadRequest) {
+					t.Errorf("New() error = %v, want BadRequest", err)
+				}
+				return
+			}
+			if reflect.TypeOf(got) != reflect.TypeOf(tt.want) {
+				t.Errorf("New() = %T, want %T", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_avroCodec(t *testing.T) {
+	codec, err := NewAvroCodec(`{"type":"record","name":"User","fields":[{"name":"name","type":"string"}]}`)
+	if err != nil {
+		t.Fatalf("NewAvroCodec() error = %v", err)
+	}
+
+	encoded, err := codec.Encode(map[string]interface{}{"name": "john"})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	got, err := codec.Decode(encoded)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if want := map[string]interface{}{"name": "john"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode() = %v, want %v", got, want)
+	}
+
+	if _, err = codec.Encode(map[string]interface{}{"name": 42}); !ierrors.HasCode(err, ierrors.BadRequest) {
+		t.Errorf("Encode() of an invalid message error = %v, want BadRequest", err)
+	}
+}
@@ -0,0 +1,361 @@
+package codecs
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"inspr.dev/inspr/pkg/ierrors"
+)
+
+// jsonSchemaCodec validates messages against a JSON Schema and encodes
+// them as JSON. It supports the validation keywords of draft 7 that don't
+// depend on external resources, references included as long as they
+// point to the schema itself
+type jsonSchemaCodec struct {
+	root     interface{}
+	patterns map[string]*regexp.Regexp
+}
+
+// NewJSONSchemaCodec returns a Codec that validates messages against
+// the given JSON Schema and encodes them as JSON
+func NewJSONSchemaCodec(schema string) (Codec, error) {
+	var root interface{}
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		return nil, err
+	}
+
+	c := &jsonSchemaCodec{root: root, patterns: map[string]*regexp.Regexp{}}
+	if err := c.compile(root, "#"); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *jsonSchemaCodec) Encode(message interface{}) ([]byte, error) {
+	// messages are validated in the form they have in JSON
+	encoded, err := json.Marshal(message)
+	if err != nil {
+		return nil, ierrors.New("[ENCODE] %v", err).BadRequest()
+	}
+
+	var value interface{}
+	json.Unmarshal(encoded, &value)
+
+	violations := c.validate(c.root, value, "$", 0)
+	if len(violations) > 0 {
+		return nil, ierrors.New(
+			"[ENCODE] message doesn't match the schema: %v", strings.Join(violations, "; "),
+		).BadRequest()
+	}
+	return encoded, nil
+}
+
+func (c *jsonSchemaCodec) Decode(data []byte) (interface{}, error) {
+	var message interface{}
+	if err := json.Unmarshal(data, &message); err != nil {
+		return nil, ierrors.New("[DECODE] %v", err)
+	}
+	return message, nil
+}
+
+// compile checks the structure of the schema and compiles its patterns
+func (c *jsonSchemaCodec) compile(schema interface{}, location string) error {
+	switch sch := schema.(type) {
+	case bool:
+		return nil
+	case map[string]interface{}:
+		if pattern, ok := sch["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("%s/pattern: %v", location, err)
+			}
+			c.patterns[pattern] = re
+		}
+		if ref, ok := sch["$ref"].(string); ok {
+			if _, err := c.resolveRef(ref); err != nil {
+				return fmt.Errorf("%s/$ref: %v", location, err)
+			}
+		}
+
+		for _, keyword := range []string{"items", "additionalProperties", "not"} {
+			if sub, ok := sch[keyword]; ok {
+				if err := c.compile(sub, location+"/"+keyword); err != nil {
+					return err
+				}
+			}
+		}
+		for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+			list, _ := sch[keyword].([]interface{})
+			for i, sub := range list {
+				if err := c.compile(sub, fmt.Sprintf("%s/%s/%d", location, keyword, i)); err != nil {
+					return err
+				}
+			}
+		}
+		for _, keyword := range []string{"properties", "definitions", "$defs"} {
+			subs, _ := sch[keyword].(map[string]interface{})
+			for name, sub := range subs {
+				if err := c.compile(sub, location+"/"+keyword+"/"+name); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("%s: a schema must be an object or a boolean", location)
+}
+
+// resolveRef returns the part of the schema a reference points to
+func (c *jsonSchemaCodec) resolveRef(ref string) (interface{}, error) {
+	if ref != "#" && !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("only references to the schema itself are supported, got '%s'", ref)
+	}
+
+	current := c.root
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#"), "/")[1:] {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("reference '%s' not found", ref)
+		}
+		if current, ok = object[token]; !ok {
+			return nil, fmt.Errorf("reference '%s' not found", ref)
+		}
+	}
+	return current, nil
+}
+
+// maxRefDepth limits the references followed while validating a value,
+// so that recursive schemas can't loop forever
+const maxRefDepth = 64
+
+// validate returns the violations of the schema by the value at the given path
+func (c *jsonSchemaCodec) validate(schema, value interface{}, path string, depth int) []string {
+	sch, ok := schema.(map[string]interface{})
+	if !ok {
+		if schema == false {
+			return []string{path + ": no value is allowed"}
+		}
+		return nil
+	}
+
+	violations := []string{}
+	fail := func(format string, args ...interface{}) {
+		violations = append(violations, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if ref, ok := sch["$ref"].(string); ok {
+		if depth >= maxRefDepth {
+			fail("too many nested references")
+			return violations
+		}
+		target, _ := c.resolveRef(ref)
+		// in draft 7 the other keywords are ignored next to a reference
+		return c.validate(target, value, path, depth+1)
+	}
+
+	if types, ok := sch["type"]; ok && !matchesType(types, value) {
+		fail("expected %v, got %v", types, jsonType(value))
+		return violations
+	}
+
+	if enum, ok := sch["enum"].([]interface{}); ok {
+		found := false
+		for _, option := range enum {
+			found = found || reflect.DeepEqual(option, value)
+		}
+		if !found {
+			fail("must be one of %v", enum)
+		}
+	}
+	if constant, ok := sch["const"]; ok && !reflect.DeepEqual(constant, value) {
+		fail("must be %v", constant)
+	}
+
+	switch v := value.(type) {
+	case float64:
+		violations = append(violations, c.validateNumber(sch, v, path)...)
+	case string:
+		violations = append(violations, c.validateString(sch, v, path)...)
+	case []interface{}:
+		violations = append(violations, c.validateArray(sch, v, path, depth)...)
+	case map[string]interface{}:
+		violations = append(violations, c.validateObject(sch, v, path, depth)...)
+	}
+
+	if all, ok := sch["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			violations = append(violations, c.validate(sub, value, path, depth)...)
+		}
+	}
+	if any, ok := sch["anyOf"].([]interface{}); ok {
+		matched := false
+		for _, sub := range any {
+			matched = matched || len(c.validate(sub, value, path, depth)) == 0
+		}
+		if !matched {
+			fail("doesn't match any of the schemas of anyOf")
+		}
+	}
+	if one, ok := sch["oneOf"].([]interface{}); ok {
+		matches := 0
+		for _, sub := range one {
+			if len(c.validate(sub, value, path, depth)) == 0 {
+				matches++
+			}
+		}
+		if matches != 1 {
+			fail("must match exactly one of the schemas of oneOf, matches %d", matches)
+		}
+	}
+	if not, ok := sch["not"]; ok && len(c.validate(not, value, path, depth)) == 0 {
+		fail("must not match the schema of not")
+	}
+	return violations
+}
+
+func (c *jsonSchemaCodec) validateNumber(sch map[string]interface{}, v float64, path string) []string {
+	violations := []string{}
+	if min, ok := sch["minimum"].(float64); ok && v < min {
+		violations = append(violations, fmt.Sprintf("%s: must be >= %v", path, min))
+	}
+	if max, ok := sch["maximum"].(float64); ok && v > max {
+		violations = append(violations, fmt.Sprintf("%s: must be <= %v", path, max))
+	}
+	if min, ok := sch["exclusiveMinimum"].(float64); ok && v <= min {
+		violations = append(violations, fmt.Sprintf("%s: must be > %v", path, min))
+	}
+	if max, ok := sch["exclusiveMaximum"].(float64); ok && v >= max {
+		violations = append(violations, fmt.Sprintf("%s: must be < %v", path, max))
+	}
+	if multiple, ok := sch["multipleOf"].(float64); ok && multiple > 0 {
+		if q := v / multiple; q != math.Trunc(q) {
+			violations = append(violations, fmt.Sprintf("%s: must be a multiple of %v", path, multiple))
+		}
+	}
+	return violations
+}
+
+func (c *jsonSchemaCodec) validateString(sch map[string]interface{}, v string, path string) []string {
+	violations := []string{}
+	length := float64(utf8.RuneCountInString(v))
+	if min, ok := sch["minLength"].(float64); ok && length < min {
+		violations = append(violations, fmt.Sprintf("%s: must have at least %v characters", path, min))
+	}
+	if max, ok := sch["maxLength"].(float64); ok && length > max {
+		violations = append(violations, fmt.Sprintf("%s: must have at most %v characters", path, max))
+	}
+	if pattern, ok := sch["pattern"].(string); ok && !c.patterns[pattern].MatchString(v) {
+		violations = append(violations, fmt.Sprintf("%s: must match '%v'", path, pattern))
+	}
+	return violations
+}
+
+func (c *jsonSchemaCodec) validateArray(sch map[string]interface{}, v []interface{}, path string, depth int) []string {
+	violations := []string{}
+	if min, ok := sch["minItems"].(float64); ok && float64(len(v)) < min {
+		violations = append(violations, fmt.Sprintf("%s: must have at least %v items", path, min))
+	}
+	if max, ok := sch["maxItems"].(float64); ok && float64(len(v)) > max {
+		violations = append(violations, fmt.Sprintf("%s: must have at most %v items", path, max))
+	}
+	if unique, _ := sch["uniqueItems"].(bool); unique {
+		for i := range v {
+			for j := i + 1; j < len(v); j++ {
+				if reflect.DeepEqual(v[i], v[j]) {
+					violations = append(violations, fmt.Sprintf("%s[%d]: duplicates item %d", path, j, i))
+				}
+			}
+		}
+	}
+	if items, ok := sch["items"]; ok {
+		for i, item := range v {
+			violations = append(violations, c.validate(items, item, fmt.Sprintf("%s[%d]", path, i), depth)...)
+		}
+	}
+	return violations
+}
+
+func (c *jsonSchemaCodec) validateObject(sch map[string]interface{}, v map[string]interface{}, path string, depth int) []string {
+	violations := []string{}
+	required, _ := sch["required"].([]interface{})
+	for _, r := range required {
+		if name, ok := r.(string); ok {
+			if _, present := v[name]; !present {
+				violations = append(violations, fmt.Sprintf("%s.%s: is required", path, name))
+			}
+		}
+	}
+
+	if min, ok := sch["minProperties"].(float64); ok && float64(len(v)) < min {
+		violations = append(violations, fmt.Sprintf("%s: must have at least %v properties", path, min))
+	}
+	if max, ok := sch["maxProperties"].(float64); ok && float64(len(v)) > max {
+		violations = append(violations, fmt.Sprintf("%s: must have at most %v properties", path, max))
+	}
+
+	// properties are visited in order so that violations are deterministic
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	properties, _ := sch["properties"].(map[string]interface{})
+	additional, hasAdditional := sch["additionalProperties"]
+	for _, name := range names {
+		if property, ok := properties[name]; ok {
+			violations = append(violations, c.validate(property, v[name], path+"."+name, depth)...)
+		} else if hasAdditional {
+			if additional == false {
+				violations = append(violations, fmt.Sprintf("%s.%s: is not allowed", path, name))
+				continue
+			}
+			violations = append(violations, c.validate(additional, v[name], path+"."+name, depth)...)
+		}
+	}
+	return violations
+}
+
+// matchesType returns whether the value is of the type, or one of the types, given
+func matchesType(types, value interface{}) bool {
+	switch t := types.(type) {
+	case string:
+		actual := jsonType(value)
+		return actual == t || (t == "number" && actual == "integer")
+	case []interface{}:
+		for _, option := range t {
+			if matchesType(option, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,221 @@
+package codecs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"inspr.dev/inspr/pkg/ierrors"
+)
+
+const userJSONSchema = `{
+	"type": "object",
+	"properties": {
+		"name": {"type": "string", "minLength": 1, "pattern": "^[a-z]+$"},
+		"age": {"type": "integer", "minimum": 0, "exclusiveMaximum": 150},
+		"role": {"enum": ["admin", "user"]},
+		"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2, "uniqueItems": true},
+		"address": {"$ref": "#/definitions/address"},
+		"contact": {"oneOf": [{"type": "string"}, {"type": "integer"}]}
+	},
+	"required": ["name", "age"],
+	"additionalProperties": false,
+	"definitions": {
+		"address": {
+			"type": "object",
+			"properties": {"city": {"type": "string"}},
+			"required": ["city"]
+		}
+	}
+}`
+
+func TestNewJSONSchemaCodec(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr bool
+	}{
+		{
+			name:   "valid schema",
+			schema: userJSONSchema,
+		},
+		{
+			name:   "boolean schema",
+			schema: `true`,
+		},
+		{
+			name:    "not json",
+			schema:  `{"type":`,
+			wantErr: true,
+		},
+		{
+			name:    "schema isn't an object",
+			schema:  `{"items": 42}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid pattern",
+			schema:  `{"pattern": "(["}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown reference",
+			schema:  `{"$ref": "#/definitions/missing"}`,
+			wantErr: true,
+		},
+		{
+			name:    "external reference",
+			schema:  `{"$ref": "http://inspr.dev/schema.json"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewJSONSchemaCodec(tt.schema)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewJSONSchemaCodec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_jsonSchemaCodec_Encode(t *testing.T) {
+	codec, err := NewJSONSchemaCodec(userJSONSchema)
+	if err != nil {
+		t.Fatalf("NewJSONSchemaCodec() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		message interface{}
+		wantErr []string
+	}{
+		{
+			name: "valid message",
+			message: map[string]interface{}{
+				"name":    "john",
+				"age":     42,
+				"role":    "admin",
+				"tags":    []string{"a", "b"},
+				"address": map[string]interface{}{"city": "bh"},
+				"contact": 5555,
+			},
+		},
+		{
+			name:    "missing required field",
+			message: map[string]interface{}{"name": "john"},
+			wantErr: []string{"$.age: is required"},
+		},
+		{
+			name:    "wrong type",
+			message: map[string]interface{}{"name": "john", "age": 4.2},
+			wantErr: []string{"$.age: expected integer, got number"},
+		},
+		{
+			name:    "out of range",
+			message: map[string]interface{}{"name": "john", "age": 150},
+			wantErr: []string{"$.age: must be < 150"},
+		},
+		{
+			name:    "invalid string",
+			message: map[string]interface{}{"name": "John", "age": 42},
+			wantErr: []string{"$.name: must match '^[a-z]+$'"},
+		},
+		{
+			name:    "not in enum",
+			message: map[string]interface{}{"name": "john", "age": 42, "role": "root"},
+			wantErr: []string{"$.role: must be one of [admin user]"},
+		},
+		{
+			name:    "invalid items",
+			message: map[string]interface{}{"name": "john", "age": 42, "tags": []interface{}{"a", "a", 1}},
+			wantErr: []string{
+				"$.tags: must have at most 2 items",
+				"$.tags[1]: duplicates item 0",
+				"$.tags[2]: expected string, got integer",
+			},
+		},
+		{
+			name:    "invalid reference",
+			message: map[string]interface{}{"name": "john", "age": 42, "address": map[string]interface{}{}},
+			wantErr: []string{"$.address.city: is required"},
+		},
+		{
+			name:    "no schema of oneOf matches",
+			message: map[string]interface{}{"name": "john", "age": 42, "contact": true},
+			wantErr: []string{"$.contact: must match exactly one of the schemas of oneOf, matches 0"},
+		},
+		{
+			name:    "additional property",
+			message: map[string]interface{}{"name": "john", "age": 42, "email": "john@inspr.dev"},
+			wantErr: []string{"$.email: is not allowed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := codec.Encode(tt.message)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("Encode() error = %v", err)
+				}
+				return
+			}
+
+			if !ierrors.HasCode(err, ierrors.BadRequest) {
+				t.Fatalf("Encode() error = %v, want BadRequest", err)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Encode() error = %v, want it to contain %v", err, want)
+				}
+			}
+		})
+	}
+}
+
+func Test_jsonSchemaCodec_Decode(t *testing.T) {
+	codec, err := NewJSONSchemaCodec(userJSONSchema)
+	if err != nil {
+		t.Fatalf("NewJSONSchemaCodec() error = %v", err)
+	}
+
+	message := map[string]interface{}{"name": "john", "age": float64(42)}
+	encoded, err := codec.Encode(message)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	got, err := codec.Decode(encoded)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, message) {
+		t.Errorf("Decode() = %v, want %v", got, message)
+	}
+
+	if _, err = codec.Decode([]byte("{")); err == nil {
+		t.Errorf("Decode() of invalid JSON didn't fail")
+	}
+}
+
+func Test_jsonSchemaCodec_recursiveReference(t *testing.T) {
+	codec, err := NewJSONSchemaCodec(`{
+		"type": "object",
+		"properties": {
+			"value": {"type": "integer"},
+			"next": {"$ref": "#"}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("NewJSONSchemaCodec() error = %v", err)
+	}
+
+	list := map[string]interface{}{
+		"value": 1,
+		"next":  map[string]interface{}{"value": 2, "next": map[string]interface{}{"value": "3"}},
+	}
+	_, err = codec.Encode(list)
+	if err == nil || !strings.Contains(err.Error(), "$.next.next.value: expected integer, got string") {
+		t.Errorf("Encode() error = %v, want the invalid nested value", err)
+	}
+}
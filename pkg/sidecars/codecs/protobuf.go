@@ -0,0 +1,247 @@
+package codecs
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"inspr.dev/inspr/pkg/ierrors"
+)
+
+// ProtobufSchema is the schema of Types in the protobuf format. Its JSON
+// encoding is the schema of the Type
+type ProtobufSchema struct {
+	// DescriptorSet is the base64 encoding of a serialized FileDescriptorSet,
+	// as generated by `protoc --include_imports --descriptor_set_out`
+	DescriptorSet string `json:"descriptorset"`
+	// Message is the full name of the message of the Type, in the descriptor set
+	Message string `json:"message"`
+}
+
+type protobufCodec struct {
+	message protoreflect.MessageDescriptor
+}
+
+// NewProtobufCodec returns a Codec that encodes messages in the wire format
+// of protobuf. Messages are in the JSON mapping of protobuf before
+// encoding and after decoding
+func NewProtobufCodec(schema string) (Codec, error) {
+	var sch ProtobufSchema
+	if err := json.Unmarshal([]byte(schema), &sch); err != nil {
+		return nil, err
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(sch.DescriptorSet)
+	if err != nil {
+		return nil, fmt.Errorf("descriptorset isn't valid base64: %v", err)
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	if err = proto.Unmarshal(raw, set); err != nil {
+		return nil, fmt.Errorf("descriptorset isn't a FileDescriptorSet: %v", err)
+	}
+
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, err
+	}
+
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(sch.Message))
+	if err != nil {
+		return nil, fmt.Errorf("message '%s' not found in the descriptorset", sch.Message)
+	}
+
+	message, ok := desc.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("'%s' isn't a message", sch.Message)
+	}
+	return &protobufCodec{message: message}, nil
+}
+
+func (c *protobufCodec) Encode(message interface{}) ([]byte, error) {
+	encoded, err := json.Marshal(message)
+	if err != nil {
+		return nil, ierrors.New("[ENCODE] %v", err).BadRequest()
+	}
+
+	// the errors of protojson don't name the invalid fields,
+	// so the message is checked against the descriptor first
+	var value interface{}
+	json.Unmarshal(encoded, &value)
+	if violations := checkMessage(c.message, value, string(c.message.FullName())); len(violations) > 0 {
+		return nil, ierrors.New(
+			"[ENCODE] message doesn't match the schema: %v", strings.Join(violations, "; "),
+		).BadRequest()
+	}
+
+	msg := dynamicpb.NewMessage(c.message)
+	if err = protojson.Unmarshal(encoded, msg); err != nil {
+		return nil, ierrors.New("[ENCODE] message doesn't match the schema: %v", err).BadRequest()
+	}
+
+	encoded, err = proto.Marshal(msg)
+	if err != nil {
+		return nil, ierrors.New("[ENCODE] %v", err).BadRequest()
+	}
+	return encoded, nil
+}
+
+func (c *protobufCodec) Decode(data []byte) (interface{}, error) {
+	msg := dynamicpb.NewMessage(c.message)
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, ierrors.New("[DECODE] %v", err)
+	}
+
+	encoded, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil, ierrors.New("[DECODE] %v", err)
+	}
+
+	var message interface{}
+	json.Unmarshal(encoded, &message)
+	return message, nil
+}
+
+// checkMessage returns the fields of the value, in the JSON mapping of
+// protobuf, that don't match the descriptor of the message
+func checkMessage(desc protoreflect.MessageDescriptor, value interface{}, path string) []string {
+	// well known types have JSON mappings of their own, left to protojson
+	if desc.FullName().Parent() == "google.protobuf" {
+		return nil
+	}
+
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return []string{fmt.Sprintf("%s: expected an object, got %v", path, jsonType(value))}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	violations := []string{}
+	fields := desc.Fields()
+	for _, name := range names {
+		fieldPath := path + "." + name
+		field := fields.ByJSONName(name)
+		if field == nil {
+			field = fields.ByName(protoreflect.Name(name))
+		}
+		if field == nil {
+			violations = append(violations, fieldPath+": unknown field")
+			continue
+		}
+
+		fieldValue := object[name]
+		switch {
+		case fieldValue == nil:
+		case field.IsList():
+			items, ok := fieldValue.([]interface{})
+			if !ok {
+				violations = append(violations, fmt.Sprintf("%s: expected an array, got %v", fieldPath, jsonType(fieldValue)))
+				continue
+			}
+			for i, item := range items {
+				violations = append(violations, checkField(field, item, fmt.Sprintf("%s[%d]", fieldPath, i))...)
+			}
+		case field.IsMap():
+			entries, ok := fieldValue.(map[string]interface{})
+			if !ok {
+				violations = append(violations, fmt.Sprintf("%s: expected an object, got %v", fieldPath, jsonType(fieldValue)))
+				continue
+			}
+			for key, entry := range entries {
+				violations = append(violations, checkField(field.MapValue(), entry, fieldPath+"."+key)...)
+			}
+		default:
+			violations = append(violations, checkField(field, fieldValue, fieldPath)...)
+		}
+	}
+	return violations
+}
+
+// checkField returns the violations of the kind of the field by a single value
+func checkField(field protoreflect.FieldDescriptor, value interface{}, path string) []string {
+	fail := func(expected string) []string {
+		return []string{fmt.Sprintf("%s: expected %s, got %v", path, expected, jsonType(value))}
+	}
+
+	switch field.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return checkMessage(field.Message(), value, path)
+
+	case protoreflect.StringKind:
+		if _, ok := value.(string); !ok {
+			return fail("string")
+		}
+
+	case protoreflect.BytesKind:
+		s, ok := value.(string)
+		if !ok {
+			return fail("base64 string")
+		}
+		if _, err := base64.StdEncoding.DecodeString(s); err != nil {
+			if _, err = base64.URLEncoding.DecodeString(s); err != nil {
+				return fail("base64 string")
+			}
+		}
+
+	case protoreflect.BoolKind:
+		if _, ok := value.(bool); !ok {
+			return fail("boolean")
+		}
+
+	case protoreflect.EnumKind:
+		switch v := value.(type) {
+		case string:
+			if field.Enum().Values().ByName(protoreflect.Name(v)) == nil {
+				return []string{fmt.Sprintf("%s: unknown value '%s' of enum %s", path, v, field.Enum().FullName())}
+			}
+		case float64:
+			if v != math.Trunc(v) {
+				return fail("enum")
+			}
+		default:
+			return fail("enum")
+		}
+
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		switch v := value.(type) {
+		case float64:
+		case string:
+			if _, err := strconv.ParseFloat(v, 64); err != nil && v != "NaN" && v != "Infinity" && v != "-Infinity" {
+				return fail("number")
+			}
+		default:
+			return fail("number")
+		}
+
+	default:
+		// integers, which are quoted in JSON when they are 64 bits long
+		switch v := value.(type) {
+		case float64:
+			if v != math.Trunc(v) {
+				return fail("integer")
+			}
+		case string:
+			if _, err := strconv.ParseFloat(v, 64); err != nil {
+				return fail("integer")
+			}
+		default:
+			return fail("integer")
+		}
+	}
+	return nil
+}
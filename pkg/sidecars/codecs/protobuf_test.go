@@ -0,0 +1,133 @@
+package codecs
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"inspr.dev/inspr/pkg/ierrors"
+)
+
+// userProtobufSchema returns the schema of the message
+//
+//	syntax = "proto3";
+//	package test;
+//	message User {
+//		string name = 1;
+//		int32 age = 2;
+//		repeated string tags = 3;
+//	}
+func userProtobufSchema(t *testing.T, message string) string {
+	t.Helper()
+	field := func(name string, number int32, kind descriptorpb.FieldDescriptorProto_Type, label descriptorpb.FieldDescriptorProto_Label) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Type:     kind.Enum(),
+			Label:    label.Enum(),
+		}
+	}
+
+	set := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("user.proto"),
+			Package: proto.String("test"),
+			Syntax:  proto.String("proto3"),
+			MessageType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("User"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL),
+					field("age", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32, descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL),
+					field("tags", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING, descriptorpb.FieldDescriptorProto_LABEL_REPEATED),
+				},
+			}},
+		}},
+	}
+
+	raw, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatalf("unable to marshal descriptor set: %v", err)
+	}
+	schema, _ := json.Marshal(ProtobufSchema{
+		DescriptorSet: base64.StdEncoding.EncodeToString(raw),
+		Message:       message,
+	})
+	return string(schema)
+}
+
+func TestNewProtobufCodec(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr bool
+	}{
+		{
+			name:   "valid schema",
+			schema: userProtobufSchema(t, "test.User"),
+		},
+		{
+			name:    "unknown message",
+			schema:  userProtobufSchema(t, "test.Group"),
+			wantErr: true,
+		},
+		{
+			name:    "not json",
+			schema:  `syntax = "proto3";`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid base64",
+			schema:  `{"descriptorset":"!!","message":"test.User"}`,
+			wantErr: true,
+		},
+		{
+			name:    "not a descriptor set",
+			schema:  `{"descriptorset":"aW5zcHI=","message":"test.User"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewProtobufCodec(tt.schema)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewProtobufCodec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_protobufCodec(t *testing.T) {
+	codec, err := NewProtobufCodec(userProtobufSchema(t, "test.User"))
+	if err != nil {
+		t.Fatalf("NewProtobufCodec() error = %v", err)
+	}
+
+	encoded, err := codec.Encode(map[string]interface{}{"name": "john", "age": 42, "tags": []string{"admin"}})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	got, err := codec.Decode(encoded)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	want := map[string]interface{}{"name": "john", "age": float64(42), "tags": []interface{}{"admin"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode() = %v, want %v", got, want)
+	}
+
+	_, err = codec.Encode(map[string]interface{}{"name": "john", "age": "42 years"})
+	if !ierrors.HasCode(err, ierrors.BadRequest) || !strings.Contains(err.Error(), "test.User.age") {
+		t.Errorf("Encode() of an invalid message error = %v, want BadRequest naming the field", err)
+	}
+
+	_, err = codec.Encode(map[string]interface{}{"email": "john@inspr.dev"})
+	if !ierrors.HasCode(err, ierrors.BadRequest) {
+		t.Errorf("Encode() of an unknown field error = %v, want BadRequest", err)
+	}
+}
@@ -0,0 +1,35 @@
+package codecs
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// schemaVersionMarker prefixes the messages encoded with a versioned schema,
+// followed by the version of the schema as a 4 bytes big endian integer.
+// Messages without it are decoded with the schema of the reader
+var schemaVersionMarker = []byte{0xC3, 0x02}
+
+const versionedHeaderSize = 6
+
+// SchemaVersionHeader returns the header that marks the messages encoded
+// with the given version of a schema, which is empty for version 0
+func SchemaVersionHeader(version int) []byte {
+	if version <= 0 {
+		return nil
+	}
+	header := make([]byte, versionedHeaderSize)
+	copy(header, schemaVersionMarker)
+	binary.BigEndian.PutUint32(header[len(schemaVersionMarker):], uint32(version))
+	return header
+}
+
+// SplitSchemaVersion returns the payload of a message and the version of
+// the schema it was written with, which is 0 when the message isn't versioned
+func SplitSchemaVersion(message []byte) ([]byte, int) {
+	if len(message) < versionedHeaderSize || !bytes.HasPrefix(message, schemaVersionMarker) {
+		return message, 0
+	}
+	version := binary.BigEndian.Uint32(message[len(schemaVersionMarker):versionedHeaderSize])
+	return message[versionedHeaderSize:], int(version)
+}
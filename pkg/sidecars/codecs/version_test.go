@@ -0,0 +1,33 @@
+package codecs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitSchemaVersion(t *testing.T) {
+	payload := []byte(`{"name":"john"}`)
+	tests := []struct {
+		name        string
+		message     []byte
+		wantVersion int
+	}{
+		{
+			name:        "versioned message",
+			message:     append(SchemaVersionHeader(3), payload...),
+			wantVersion: 3,
+		},
+		{
+			name:    "message without version",
+			message: append(SchemaVersionHeader(0), payload...),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, version := SplitSchemaVersion(tt.message)
+			if version != tt.wantVersion || !bytes.Equal(got, payload) {
+				t.Errorf("SplitSchemaVersion() = %s, %v, want %s, %v", got, version, payload, tt.wantVersion)
+			}
+		})
+	}
+}
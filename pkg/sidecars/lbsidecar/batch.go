@@ -0,0 +1,229 @@
+package lbsidecar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"inspr.dev/inspr/pkg/environment"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/rest"
+	"inspr.dev/inspr/pkg/sidecars/models"
+	"inspr.dev/inspr/pkg/tracing"
+)
+
+// writeMessagesHandler handles batches of messages sent to the write message
+// server, which are written to the broker in a single call
+func (s *Server) writeMessagesHandler() rest.Handler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+
+		channel := strings.TrimPrefix(r.URL.Path, "/batch/channel/")
+		logger.Info("handling batch write on " + channel)
+
+		var batch models.BrokerBatch
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			rest.ERROR(
+				w,
+				ierrors.New("invalid batch of messages for channel '%s'", channel).BadRequest(),
+			)
+			return
+		}
+
+		if err := s.writeBatch(r.Context(), channel, batch.Messages); err != nil {
+			rest.ERROR(w, err)
+			return
+		}
+		rest.JSON(w, 200, nil)
+	}
+}
+
+// writeBatch writes a batch of messages of the node to the broker of its
+// channel in a single call. None of them is written if any of them is
+// invalid, and it only succeeds once the broker wrote all of them. Batches
+// aren't transactions: when the broker fails to write some of the messages,
+// the ones it wrote are kept
+func (s *Server) writeBatch(ctx context.Context, channel string, msgs []models.BrokerMessage) error {
+	start := time.Now()
+
+	channelBroker, err := writableChannelBroker(channel)
+	if err != nil {
+		return err
+	}
+
+	if len(msgs) == 0 {
+		return ierrors.New("invalid batch of messages for channel '%s'", channel).BadRequest()
+	}
+
+	logger.Debug("encoding messages", zap.Int("messages", len(msgs)))
+
+	records := make([]models.BrokerRecord, 0, len(msgs))
+	for i, msg := range msgs {
+		record, err := s.encodeMessage(channel, msg)
+		if err != nil {
+			logger.Error("unable to encode message",
+				zap.String("channel", channel),
+				zap.Int("message", i),
+				zap.Any("error", err))
+			return ierrors.Wrap(err, fmt.Sprintf("message %d of the batch", i))
+		}
+		records = append(records, record)
+	}
+
+	logger.Info("writing messages to broker",
+		zap.String("broker", channelBroker),
+		zap.String("channel", channel),
+		zap.Int("messages", len(records)))
+
+	envelopes := make([]*models.Envelope, len(records))
+	for i := range records {
+		envelopes[i] = &records[i].Envelope
+	}
+	_, span := startMessageSpan(ctx, "write batch", trace.SpanKindProducer, channel, envelopes...)
+	err = s.brokerHandlers[channelBroker].Writer().WriteMessages(channel, records)
+	tracing.End(span, err)
+	if err != nil {
+		s.GetChannelMetric(channel).messageSendError.Inc()
+		return ierrors.New("broker's WriteMessages failed, %s", err.Error())
+	}
+
+	s.GetChannelMetric(channel).messagesSent.Add(float64(len(records)))
+	elapsed := time.Since(start)
+	s.GetChannelMetric(channel).writeMessageDuration.Observe(elapsed.Seconds())
+	return nil
+}
+
+// batchReadMessageRoutine reads the messages of a channel into batches that
+// are delivered to the node in a single request. A batch is delivered once
+// it has the max size of the channel's batches or their linger has passed
+// since its first message was read. Its messages are committed once the
+// whole batch is handled
+func (s *Server) batchReadMessageRoutine(
+	ctx context.Context,
+	broker, channel string,
+	policy deliveryPolicy,
+	batch *meta.Batch,
+) error {
+	linger, err := time.ParseDuration(batch.Linger)
+	if err != nil {
+		return ierrors.New(
+			"invalid batch linger for channel %s: %v", channel, err,
+		).BadRequest()
+	}
+
+	logger.Info("reading messages in batches",
+		zap.String("channel", channel),
+		zap.Int("max size", batch.MaxSize),
+		zap.Duration("linger", linger))
+
+	deadLetter := environment.GetDeadLetter(channel)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		start := time.Now()
+
+		// the first message of a batch is waited for as long as it takes
+		record, err := s.readWithRetry(ctx, broker, channel, policy)
+		if err != nil {
+			return err
+		}
+		records := []models.BrokerRecord{record}
+
+		lingerCtx, cancel := context.WithTimeout(ctx, linger)
+		for len(records) < batch.MaxSize {
+			record, err = s.readWithRetry(lingerCtx, broker, channel, policy)
+			if err != nil {
+				break
+			}
+			records = append(records, record)
+		}
+		cancel()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil && lingerCtx.Err() == nil {
+			return err
+		}
+
+		if err = s.handleBatch(ctx, channel, policy, deadLetter, records); err != nil {
+			return err
+		}
+
+		for range records {
+			s.commitMessage(ctx, broker, channel, start)
+		}
+	}
+}
+
+// handleBatch delivers a batch of messages read from a channel to the node in
+// a single request. Messages that can't be decoded are left out of the batch
+// and sent to the dead-letter channel, if there is one. When the batch can't
+// be delivered, each of its messages is sent to the dead-letter channel
+func (s *Server) handleBatch(
+	ctx context.Context,
+	channel string,
+	policy deliveryPolicy,
+	deadLetter *meta.DeadLetter,
+	records []models.BrokerRecord,
+) (err error) {
+	logger.Debug("trying to send batch to loadbalancer",
+		zap.String("channel", channel),
+		zap.Int("messages", len(records)))
+
+	envelopes := make([]*models.Envelope, len(records))
+	for i := range records {
+		envelopes[i] = &records[i].Envelope
+	}
+	ctx, span := startMessageSpan(ctx, "deliver batch", trace.SpanKindConsumer, channel, envelopes...)
+	defer func() { tracing.End(span, err) }()
+
+	decodedMsgs := make([]models.BrokerMessage, 0, len(records))
+	batched := make([]models.BrokerRecord, 0, len(records))
+	for _, record := range records {
+		decodedMsg, err := s.decodeMessage(channel, record)
+		if err != nil {
+			logger.Error("unable to decode message",
+				zap.String("channel", channel),
+				zap.Any("error", err))
+			if deadLetter == nil {
+				return err
+			}
+
+			// retrying won't make the message decodable
+			if err = s.writeDeadLetter(channel, deadLetter, record, 1, err); err != nil {
+				return err
+			}
+			continue
+		}
+		decodedMsgs = append(decodedMsgs, decodedMsg)
+		batched = append(batched, record)
+	}
+	if len(decodedMsgs) == 0 {
+		return nil
+	}
+
+	attempts, err := s.deliverTo(ctx, channel, true, policy, decodedMsgs)
+	if err == nil || deadLetter == nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	for _, record := range batched {
+		if err := s.writeDeadLetter(channel, deadLetter, record, attempts, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
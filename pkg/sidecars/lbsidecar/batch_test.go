@@ -0,0 +1,294 @@
+package lbsidecar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"inspr.dev/inspr/pkg/environment"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/sidecars/models"
+)
+
+func TestServer_writeMessagesHandler(t *testing.T) {
+	createMockEnvVars()
+	defer deleteMockEnvVars()
+	os.Setenv("INSPR_OUTPUT_CHANNELS", "batched@someBroker")
+	os.Setenv("batched_RESOLVED", "someTopic")
+	defer os.Unsetenv("batched_RESOLVED")
+
+	var written [][]byte
+	var writeErr error
+	writer := &mockWriter{writeMessages: func(channel string, messages [][]byte) error {
+		written = messages
+		return writeErr
+	}}
+	s := Init(models.NewBrokerHandler("someBroker", nil, writer))
+
+	tests := []struct {
+		name        string
+		channel     string
+		body        interface{}
+		writeErr    error
+		wantStatus  int
+		wantWritten []interface{}
+	}{
+		{
+			name:    "batch written in a single call",
+			channel: "batched",
+			body: models.BrokerBatch{Messages: []models.BrokerMessage{
+				{Data: "first"}, {Data: "second"}, {Data: "third"},
+			}},
+			wantStatus:  http.StatusOK,
+			wantWritten: []interface{}{"first", "second", "third"},
+		},
+		{
+			name:    "channel isn't an output channel",
+			channel: "chan",
+			body: models.BrokerBatch{Messages: []models.BrokerMessage{
+				{Data: "first"},
+			}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "empty batch",
+			channel:    "batched",
+			body:       models.BrokerBatch{},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "message that doesn't match the schema",
+			channel: "batched",
+			body: models.BrokerBatch{Messages: []models.BrokerMessage{
+				{Data: "first"}, {Data: 2},
+			}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "broker fails to write the batch",
+			channel: "batched",
+			body: models.BrokerBatch{Messages: []models.BrokerMessage{
+				{Data: "first"},
+			}},
+			writeErr:   errors.New("broker unavailable"),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			written, writeErr = nil, tt.writeErr
+
+			body, _ := json.Marshal(tt.body)
+			w := httptest.NewRecorder()
+			s.writeMessagesHandler()(w, httptest.NewRequest(
+				http.MethodPost, "/batch/channel/"+tt.channel, bytes.NewReader(body),
+			))
+			if w.Code != tt.wantStatus {
+				t.Errorf("writeMessagesHandler() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if tt.wantWritten == nil {
+				return
+			}
+
+			if len(written) != len(tt.wantWritten) {
+				t.Fatalf("writeMessagesHandler() wrote %v messages, want %v", len(written), len(tt.wantWritten))
+			}
+			for i, msg := range written {
+				got, _ := s.codecs.decode("someTopic", msg)
+				if got != tt.wantWritten[i] {
+					t.Errorf("writeMessagesHandler() message %v = %v, want %v", i, got, tt.wantWritten[i])
+				}
+			}
+		})
+	}
+}
+
+func TestServer_batchReadMessageRoutine(t *testing.T) {
+	createMockEnvVars()
+	defer deleteMockEnvVars()
+	os.Setenv("shipments_RESOLVED", "someTopic")
+	defer os.Unsetenv("shipments_RESOLVED")
+
+	const messages = 5
+	var mutex sync.Mutex
+	var batches [][]string
+	var commits int
+
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/batch/channel/shipments" {
+			t.Errorf("batchReadMessageRoutine() sent a request to %v", r.URL.Path)
+		}
+		var batch struct {
+			Messages []struct {
+				Data string `json:"data"`
+			} `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&batch)
+
+		data := []string{}
+		for _, msg := range batch.Messages {
+			data = append(data, msg.Data)
+		}
+		mutex.Lock()
+		batches = append(batches, data)
+		mutex.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer node.Close()
+
+	var s *Server
+	read := 0
+	reader := &mockReader{
+		readMessage: func(ctx context.Context, channel string) ([]byte, error) {
+			if read == messages {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			read++
+			return s.codecs.encode("someTopic", string(rune('a'+read-1)))
+		},
+		commit: func(ctx context.Context, channel string) error {
+			mutex.Lock()
+			commits++
+			mutex.Unlock()
+			return nil
+		},
+	}
+	s = Init(models.NewBrokerHandler("someBroker", reader, nil))
+	s.clientAddr = node.URL
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errch := make(chan error, 1)
+	go func() {
+		errch <- s.batchReadMessageRoutine(ctx, "someBroker", "shipments", deliveryPolicy{
+			readAttempts:     1,
+			deliveryAttempts: 1,
+		}, &meta.Batch{MaxSize: 2, Linger: "20ms"})
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		mutex.Lock()
+		done := commits == messages
+		mutex.Unlock()
+		if done {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-errch
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	want := [][]string{{"a", "b"}, {"c", "d"}, {"e"}}
+	if len(batches) != len(want) {
+		t.Fatalf("batchReadMessageRoutine() delivered batches %v, want %v", batches, want)
+	}
+	for i := range want {
+		if len(batches[i]) != len(want[i]) || batches[i][0] != want[i][0] {
+			t.Errorf("batchReadMessageRoutine() delivered batches %v, want %v", batches, want)
+			break
+		}
+	}
+	if commits != messages {
+		t.Errorf("batchReadMessageRoutine() committed %v messages, want %v", commits, messages)
+	}
+}
+
+func TestServer_handleBatch(t *testing.T) {
+	createMockEnvVars()
+	defer deleteMockEnvVars()
+	env := map[string]string{
+		"INSPR_INPUT_CHANNELS":        "returns@someBroker",
+		"INSPR_OUTPUT_CHANNELS":       "returns.deadletter@someBroker",
+		"returns_RESOLVED":            "returnsTopic",
+		"returnsTopic_SCHEMA":         `{"type":"string"}`,
+		"returns.deadletter_RESOLVED": "failuresTopic",
+		"failuresTopic_SCHEMA":        `{"type":"object"}`,
+		"failuresTopic_SCHEMA_FORMAT": "jsonschema",
+		"returns_DEADLETTER":          "returns.deadletter",
+		"returns_DEADLETTER_ATTEMPTS": "2",
+	}
+	for key, value := range env {
+		os.Setenv(key, value)
+	}
+	defer func() {
+		for key := range env {
+			os.Unsetenv(key)
+		}
+	}()
+
+	var status, requests, deadLetters int
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(status)
+	}))
+	defer node.Close()
+
+	writer := &mockWriter{writeMessage: func(channel string, message []byte) error {
+		deadLetters++
+		return nil
+	}}
+	s := Init(models.NewBrokerHandler("someBroker", nil, writer))
+	s.clientAddr = node.URL
+
+	encoded, _ := s.codecs.encode("returnsTopic", "return-1")
+	first := models.BrokerRecord{Value: encoded}
+	encoded, _ = s.codecs.encode("returnsTopic", "return-2")
+	second := models.BrokerRecord{Value: encoded}
+	policy, _ := newDeliveryPolicy("returns")
+
+	tests := []struct {
+		name            string
+		status          int
+		messages        []models.BrokerRecord
+		wantRequests    int
+		wantDeadLetters int
+	}{
+		{
+			name:         "delivered batch",
+			status:       http.StatusOK,
+			messages:     []models.BrokerRecord{first, second},
+			wantRequests: 1,
+		},
+		{
+			name:            "undecodable message is left out of the batch",
+			status:          http.StatusOK,
+			messages:        []models.BrokerRecord{first, {Value: []byte{0xFF}}},
+			wantRequests:    1,
+			wantDeadLetters: 1,
+		},
+		{
+			name:            "messages of a batch that can't be delivered are dead-lettered",
+			status:          http.StatusInternalServerError,
+			messages:        []models.BrokerRecord{first, second},
+			wantRequests:    2,
+			wantDeadLetters: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, requests, deadLetters = tt.status, 0, 0
+
+			err := s.handleBatch(context.Background(), "returns", policy, environment.GetDeadLetter("returns"), tt.messages)
+			if err != nil {
+				t.Errorf("handleBatch() error = %v", err)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("handleBatch() sent %v requests, want %v", requests, tt.wantRequests)
+			}
+			if deadLetters != tt.wantDeadLetters {
+				t.Errorf("handleBatch() wrote %v dead-letters, want %v", deadLetters, tt.wantDeadLetters)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package lbsidecar
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+	"inspr.dev/inspr/pkg/environment"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	metautils "inspr.dev/inspr/pkg/meta/utils"
+	"inspr.dev/inspr/pkg/sidecars/codecs"
+	"inspr.dev/inspr/pkg/sidecars/models"
+)
+
+// codecCache keeps the codecs of the resolved channels of the sidecar, so
+// that their schemas are compiled once instead of for every message. The
+// schemas come from the environment of the sidecar, which only changes when
+// insprd deploys the node again, so the codecs are kept for its whole lifetime
+type codecCache struct {
+	mutex    sync.RWMutex
+	channels map[string]*channelCodec
+}
+
+// channelCodec is the codec of the schema of a resolved channel, along with
+// the codecs of the other versions of the schema, compiled when a message
+// written with them is first read
+type channelCodec struct {
+	format  string
+	schema  string
+	version int
+	codec   codecs.Codec
+	header  []byte
+
+	mutex    sync.Mutex
+	history  map[int]string
+	versions map[int]codecs.Codec
+}
+
+func newCodecCache() *codecCache {
+	return &codecCache{channels: make(map[string]*channelCodec)}
+}
+
+// load compiles the codecs of the given resolved channels. Channels whose
+// codec can't be compiled are left out, failing when they're used instead
+func (c *codecCache) load(channels ...string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, ch := range channels {
+		codec, err := newChannelCodec(ch)
+		if err != nil {
+			logger.Error("unable to compile the codec of channel",
+				zap.String("channel", ch),
+				zap.Any("error", err))
+			continue
+		}
+		c.channels[ch] = codec
+	}
+}
+
+// get returns the codec of a resolved channel, compiling it if it isn't cached
+func (c *codecCache) get(ch string) (*channelCodec, error) {
+	c.mutex.RLock()
+	codec, ok := c.channels[ch]
+	c.mutex.RUnlock()
+	if ok {
+		return codec, nil
+	}
+
+	codec, err := newChannelCodec(ch)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.channels[ch] = codec
+	return codec, nil
+}
+
+// readMessage receives an encoded message and returns a models.BrokerMessage
+// structure that contains the decoded message
+func (c *codecCache) readMessage(ch string, value []byte) (models.BrokerMessage, error) {
+	logger.Info("reading message from channel",
+		zap.String("channel", ch))
+
+	// Decoding Message
+	data, err := c.decode(ch, value)
+	if err != nil {
+		return models.BrokerMessage{}, err
+	}
+
+	return models.BrokerMessage{Data: data}, nil
+}
+
+func (c *codecCache) encode(ch string, message interface{}) ([]byte, error) {
+	logger.Info("encoding message")
+
+	codec, err := c.get(ch)
+	if err != nil {
+		return nil, err
+	}
+
+	messageEncoded, err := codec.codec.Encode(message)
+	if err != nil {
+		logger.Error("unable to encode message", zap.Any("error", err))
+
+		return nil, err
+	}
+
+	encoded := make([]byte, 0, len(codec.header)+len(messageEncoded))
+	encoded = append(encoded, codec.header...)
+	return append(encoded, messageEncoded...), nil
+}
+
+func (c *codecCache) decode(ch string, messageEncoded []byte) (interface{}, error) {
+	logger.Debug("decoding received message")
+
+	codec, err := c.get(ch)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, writerVersion := codecs.SplitSchemaVersion(messageEncoded)
+	// messages of other formats are decoded with the schema of the
+	// reader, as their encodings are resolved when decoding
+	if writerVersion == 0 || writerVersion == codec.version || codec.format != meta.FormatAvro {
+		return decodeWithCodec(codec.codec, payload)
+	}
+
+	logger.Debug("resolving message written with another schema version",
+		zap.Int("writer-version", writerVersion),
+		zap.Int("reader-version", codec.version))
+
+	writerSchema, writerCodec, err := codec.versionCodec(ch, writerVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := decodeWithCodec(writerCodec, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, err := metautils.ResolveAvroDatum(codec.schema, writerSchema, message)
+	if err != nil {
+		logger.Error("unable to resolve message to the reader schema", zap.Any("error", err))
+
+		return nil, ierrors.New("[DECODE] %v", err.Error())
+	}
+	return resolved, nil
+}
+
+func decodeWithCodec(codec codecs.Codec, payload []byte) (interface{}, error) {
+	message, err := codec.Decode(payload)
+	if err != nil {
+		logger.Error("unable to decode message", zap.Any("error", err))
+
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// newChannelCodec compiles the codec of a resolved channel from its configuration
+func newChannelCodec(ch string) (*channelCodec, error) {
+	schema, err := getSchema(ch)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := environment.GetSchemaHistory(ch)
+	if err != nil {
+		return nil, err
+	}
+
+	version := environment.GetSchemaVersion(ch)
+	codec := &channelCodec{
+		format:   environment.GetSchemaFormat(ch),
+		schema:   schema,
+		version:  version,
+		header:   codecs.SchemaVersionHeader(version),
+		history:  make(map[int]string, len(history)),
+		versions: make(map[int]codecs.Codec),
+	}
+	for _, v := range history {
+		codec.history[v.Version] = v.Schema
+	}
+
+	codec.codec, err = getCodec(codec.format, schema)
+	if err != nil {
+		return nil, err
+	}
+	return codec, nil
+}
+
+// versionCodec returns the schema of the given version of the channel type and its codec
+func (cc *channelCodec) versionCodec(ch string, version int) (string, codecs.Codec, error) {
+	schema, ok := cc.history[version]
+	if !ok {
+		logger.Error("unknown schema version", zap.String("channel", ch), zap.Int("version", version))
+		return "", nil, ierrors.New(
+			"[DECODE] version %v of the schema of channel %v not found", version, ch,
+		).NotFound()
+	}
+
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	codec, ok := cc.versions[version]
+	if !ok {
+		var err error
+		codec, err = getCodec(cc.format, schema)
+		if err != nil {
+			return "", nil, err
+		}
+		cc.versions[version] = codec
+	}
+	return schema, codec, nil
+}
+
+// returns the channel type's schema
+func getSchema(ch string) (string, error) {
+	logger.Debug("getting channel schema",
+		zap.String("channel", ch))
+
+	schema, err := environment.GetSchema(ch)
+	if err != nil {
+		logger.Error("unable to get channel schema", zap.Any("error", err))
+
+		return "", err
+	}
+
+	return schema, nil
+}
+
+// creates the codec of the given schema, in the given format
+func getCodec(format, schema string) (codecs.Codec, error) {
+	logger.Debug("getting codec given a schema",
+		zap.String("format", format),
+		zap.String("schema", schema))
+
+	codec, err := codecs.New(format, schema)
+	if err != nil {
+		logger.Error("unable to get codec", zap.Any("error", err))
+
+		return nil, err
+	}
+
+	return codec, nil
+}
@@ -0,0 +1,213 @@
+package lbsidecar
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/sidecars/codecs"
+)
+
+const (
+	userSchemaV1 = `{"type":"record","name":"User","fields":[{"name":"name","type":"string"},{"name":"age","type":"int"}]}`
+	userSchemaV2 = `{"type":"record","name":"User","fields":[{"name":"name","type":"string"},{"name":"age","type":"int"},{"name":"email","type":["null","string"],"default":null}]}`
+)
+
+// setChannelSchema sets the environment of a channel pinned to the given version
+func setChannelSchema(t *testing.T, ch, schema, version string) {
+	t.Helper()
+	os.Setenv(ch+"_SCHEMA", schema)
+	os.Setenv(ch+"_SCHEMA_VERSION", version)
+	history, _ := json.Marshal([]meta.TypeVersion{
+		{Version: 1, Schema: userSchemaV1},
+		{Version: 2, Schema: userSchemaV2},
+	})
+	os.Setenv(ch+"_SCHEMA_HISTORY", string(history))
+	t.Cleanup(func() {
+		os.Unsetenv(ch + "_SCHEMA")
+		os.Unsetenv(ch + "_SCHEMA_VERSION")
+		os.Unsetenv(ch + "_SCHEMA_HISTORY")
+	})
+}
+
+func Test_versionedCodec(t *testing.T) {
+	tests := []struct {
+		name          string
+		writerSchema  string
+		writerVersion string
+		readerSchema  string
+		readerVersion string
+		message       interface{}
+		want          interface{}
+	}{
+		{
+			name:          "same version",
+			writerSchema:  userSchemaV1,
+			writerVersion: "1",
+			readerSchema:  userSchemaV1,
+			readerVersion: "1",
+			message:       map[string]interface{}{"name": "john", "age": 42},
+			want:          map[string]interface{}{"name": "john", "age": int32(42)},
+		},
+		{
+			name:          "older writer version",
+			writerSchema:  userSchemaV1,
+			writerVersion: "1",
+			readerSchema:  userSchemaV2,
+			readerVersion: "2",
+			message:       map[string]interface{}{"name": "john", "age": 42},
+			want:          map[string]interface{}{"name": "john", "age": int32(42), "email": nil},
+		},
+		{
+			name:          "newer writer version",
+			writerSchema:  userSchemaV2,
+			writerVersion: "2",
+			readerSchema:  userSchemaV1,
+			readerVersion: "1",
+			message: map[string]interface{}{
+				"name": "john", "age": 42, "email": map[string]interface{}{"string": "john@inspr.dev"},
+			},
+			want: map[string]interface{}{"name": "john", "age": int32(42)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setChannelSchema(t, "writer", tt.writerSchema, tt.writerVersion)
+			setChannelSchema(t, "reader", tt.readerSchema, tt.readerVersion)
+			c := newCodecCache()
+
+			encoded, err := c.encode("writer", tt.message)
+			if err != nil {
+				t.Fatalf("encode() error = %v", err)
+			}
+			if _, version := codecs.SplitSchemaVersion(encoded); version == 0 {
+				t.Errorf("encode() didn't mark the message with the schema version")
+			}
+
+			got, err := c.decode("reader", encoded)
+			if err != nil {
+				t.Fatalf("decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_decodeUnversioned(t *testing.T) {
+	os.Setenv("legacy_SCHEMA", `{"type":"string"}`)
+	defer os.Unsetenv("legacy_SCHEMA")
+
+	c := newCodecCache()
+	encoded, err := c.encode("legacy", "hello")
+	if err != nil {
+		t.Fatalf("encode() error = %v", err)
+	}
+	if _, version := codecs.SplitSchemaVersion(encoded); version != 0 {
+		t.Errorf("encode() marked a message of an unversioned schema")
+	}
+
+	setChannelSchema(t, "reader", `{"type":"string"}`, "2")
+	got, err := c.decode("reader", encoded)
+	if err != nil || got != "hello" {
+		t.Errorf("decode() = %v, %v, want hello", got, err)
+	}
+}
+
+func Test_codecFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		schema  string
+		message interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name:    "avro by default",
+			schema:  userSchemaV1,
+			message: map[string]interface{}{"name": "john", "age": 42},
+			want:    map[string]interface{}{"name": "john", "age": int32(42)},
+		},
+		{
+			name:    "jsonschema",
+			format:  meta.FormatJSONSchema,
+			schema:  `{"type":"object","properties":{"age":{"type":"integer"}},"required":["age"]}`,
+			message: map[string]interface{}{"age": 42},
+			want:    map[string]interface{}{"age": float64(42)},
+		},
+		{
+			name:    "invalid jsonschema message",
+			format:  meta.FormatJSONSchema,
+			schema:  `{"type":"object","required":["age"]}`,
+			message: map[string]interface{}{"name": "john"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown format",
+			format:  "xml",
+			schema:  `<schema/>`,
+			message: "john",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setChannelSchema(t, "formatted", tt.schema, "1")
+			if tt.format != "" {
+				os.Setenv("formatted_SCHEMA_FORMAT", tt.format)
+				defer os.Unsetenv("formatted_SCHEMA_FORMAT")
+			}
+
+			c := newCodecCache()
+			encoded, err := c.encode("formatted", tt.message)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("encode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !ierrors.HasCode(err, ierrors.BadRequest) {
+					t.Errorf("encode() error = %v, want BadRequest", err)
+				}
+				return
+			}
+
+			got, err := c.decode("formatted", encoded)
+			if err != nil {
+				t.Fatalf("decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_codecCache_load(t *testing.T) {
+	setChannelSchema(t, "cached", userSchemaV1, "1")
+	c := newCodecCache()
+	c.load("cached", "missing")
+
+	if _, ok := c.channels["missing"]; ok {
+		t.Errorf("load() cached the codec of a channel without a schema")
+	}
+	cached, ok := c.channels["cached"]
+	if !ok {
+		t.Fatalf("load() didn't cache the codec of the channel")
+	}
+	if got, _ := c.get("cached"); got != cached {
+		t.Errorf("get() compiled the codec of a cached channel again")
+	}
+
+	// the codecs are kept for the lifetime of the sidecar
+	setChannelSchema(t, "cached", `{"type":"string"}`, "3")
+	if _, err := c.encode("cached", "john"); err == nil {
+		t.Errorf("encode() compiled the codec of a cached channel again")
+	}
+	if _, err := c.get("missing"); err == nil {
+		t.Errorf("get() compiled the codec of a channel without a schema")
+	}
+}
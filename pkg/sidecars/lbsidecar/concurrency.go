@@ -0,0 +1,190 @@
+package lbsidecar
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"inspr.dev/inspr/pkg/environment"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/sidecars/models"
+)
+
+// pendingMessage is a message read from a channel that wasn't committed yet
+type pendingMessage struct {
+	record models.BrokerRecord
+	start  time.Time
+	// done receives the result of handling the message
+	done chan error
+}
+
+// concurrentReadMessageRoutine reads the messages of a channel and handles up
+// to the max in flight of its concurrency at the same time. Each message is
+// committed once it and every message read before it are handled, so that
+// the messages that weren't handled are read again if the sidecar stops.
+//
+// The messages are handled by a worker for each message in flight. Messages
+// with an ordering key are always handled by the same worker, the one the key
+// is hashed to, and so one at a time in the order they were read. When the
+// channel has no ordering key, the key of each message is used instead, which
+// keeps the order the broker has for the messages of each key, such as the
+// order of a Kafka partition for them
+func (s *Server) concurrentReadMessageRoutine(
+	ctx context.Context,
+	broker, channel string,
+	policy deliveryPolicy,
+	concurrency *meta.Concurrency,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	logger.Info("reading messages concurrently",
+		zap.String("channel", channel),
+		zap.Int("max in flight", concurrency.MaxInFlight),
+		zap.String("ordering key", concurrency.OrderingKey))
+
+	deadLetter := environment.GetDeadLetter(channel)
+	inFlight := s.GetChannelMetric(channel).messagesInFlight
+
+	// a slot is taken by each message from when it's read until it's handled
+	slots := make(chan struct{}, concurrency.MaxInFlight)
+	pending := make(chan *pendingMessage, concurrency.MaxInFlight)
+	unordered := make(chan *pendingMessage)
+	lanes := make([]chan *pendingMessage, concurrency.MaxInFlight)
+
+	handle := func(msg *pendingMessage) {
+		inFlight.Inc()
+		msg.done <- s.handleMessage(ctx, channel, policy, deadLetter, msg.record)
+		inFlight.Dec()
+		<-slots
+	}
+	for i := range lanes {
+		lanes[i] = make(chan *pendingMessage, concurrency.MaxInFlight)
+		go func(lane chan *pendingMessage) {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case msg := <-lane:
+					handle(msg)
+				case msg := <-unordered:
+					handle(msg)
+				}
+			}
+		}(lanes[i])
+	}
+
+	errch := make(chan error, 1)
+	go func() { errch <- s.commitInOrder(ctx, broker, channel, pending) }()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errch:
+			return err
+		case slots <- struct{}{}:
+		}
+
+		record, err := s.readWithRetry(ctx, broker, channel, policy)
+		if err != nil {
+			return err
+		}
+		msg := &pendingMessage{
+			record: record,
+			start:  time.Now(),
+			done:   make(chan error, 1),
+		}
+
+		// the committer is given the message before any worker, which keeps
+		// the messages in the order they were read
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errch:
+			return err
+		case pending <- msg:
+		}
+
+		worker := unordered
+		if concurrency.OrderingKey != "" {
+			key := s.orderingKey(channel, concurrency.OrderingKey, record.Value)
+			worker = lanes[laneOf(key, len(lanes))]
+		} else if record.Envelope.Key != "" {
+			worker = lanes[laneOf(record.Envelope.Key, len(lanes))]
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errch:
+			return err
+		case worker <- msg:
+		}
+	}
+}
+
+// commitInOrder commits the pending messages of a channel in the order they
+// were read, waiting for each of them to be handled. It stops at the first
+// message that couldn't be handled, which isn't committed
+func (s *Server) commitInOrder(
+	ctx context.Context,
+	broker, channel string,
+	pending <-chan *pendingMessage,
+) error {
+	for {
+		var msg *pendingMessage
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg = <-pending:
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-msg.done:
+			if err != nil {
+				return err
+			}
+		}
+
+		s.commitMessage(ctx, broker, channel, msg.start)
+	}
+}
+
+// orderingKey returns the value of the field of a message at the path of the
+// ordering key. Messages that don't have the field, or can't be decoded,
+// have an empty key, and so are ordered among themselves
+func (s *Server) orderingKey(channel, path string, brokerMsg []byte) string {
+	resolvedCh, err := getResolvedChannel(channel)
+	if err != nil {
+		return ""
+	}
+
+	value, err := s.codecs.decode(resolvedCh, brokerMsg)
+	if err != nil {
+		return ""
+	}
+
+	for _, field := range strings.Split(path, ".") {
+		record, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		if value, ok = record[field]; !ok {
+			return ""
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// laneOf returns the lane of the workers that handles the messages with the given key
+func laneOf(key string, lanes int) int {
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	return int(hash.Sum32() % uint32(lanes))
+}
@@ -0,0 +1,259 @@
+package lbsidecar
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/sidecars/models"
+)
+
+func TestServer_concurrentReadMessageRoutine(t *testing.T) {
+	createMockEnvVars()
+	defer deleteMockEnvVars()
+	env := map[string]string{
+		"INSPR_INPUT_CHANNELS": "transfers@someBroker",
+		"transfers_RESOLVED":   "transfersTopic",
+		"transfersTopic_SCHEMA": `{"type":"record","name":"Transfer","fields":[
+			{"name":"user","type":{"type":"record","name":"User","fields":[{"name":"id","type":"string"}]}},
+			{"name":"n","type":"int"}
+		]}`,
+	}
+	for key, value := range env {
+		os.Setenv(key, value)
+	}
+	defer func() {
+		for key := range env {
+			os.Unsetenv(key)
+		}
+	}()
+
+	type transfer struct {
+		Data struct {
+			User struct {
+				ID string `json:"id"`
+			} `json:"user"`
+			N int `json:"n"`
+		} `json:"data"`
+	}
+
+	const messages = 12
+	const maxInFlight = 4
+	users := []string{"alice", "bob", "carol"}
+
+	var mutex sync.Mutex
+	var read, inFlight, peakInFlight, commits int
+	var received map[string][]int
+	var hold, messageKeys bool
+	release := make(chan struct{})
+	handled := make(chan struct{}, messages)
+
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg transfer
+		json.NewDecoder(r.Body).Decode(&msg)
+
+		mutex.Lock()
+		inFlight++
+		if inFlight > peakInFlight {
+			peakInFlight = inFlight
+		}
+		received[msg.Data.User.ID] = append(received[msg.Data.User.ID], msg.Data.N)
+		mutex.Unlock()
+
+		if hold && msg.Data.N == 0 {
+			<-release
+		} else {
+			// the messages read first take longer to be handled
+			time.Sleep(time.Duration(messages-msg.Data.N) * time.Millisecond)
+		}
+
+		mutex.Lock()
+		inFlight--
+		mutex.Unlock()
+		handled <- struct{}{}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer node.Close()
+
+	var s *Server
+	reader := &mockReader{
+		readRecord: func(ctx context.Context, channel string) (models.BrokerRecord, error) {
+			if read == messages {
+				<-ctx.Done()
+				return models.BrokerRecord{}, ctx.Err()
+			}
+			user := users[read%len(users)]
+			msg := map[string]interface{}{
+				"user": map[string]interface{}{"id": user},
+				"n":    read,
+			}
+			read++
+
+			var record models.BrokerRecord
+			if messageKeys {
+				record.Envelope.Key = user
+			}
+			var err error
+			record.Value, err = s.codecs.encode("transfersTopic", msg)
+			return record, err
+		},
+		commit: func(ctx context.Context, channel string) error {
+			mutex.Lock()
+			commits++
+			mutex.Unlock()
+			return nil
+		},
+	}
+	s = Init(models.NewBrokerHandler("someBroker", reader, nil))
+	s.clientAddr = node.URL
+
+	waitCommits := func() {
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			mutex.Lock()
+			done := commits == messages
+			mutex.Unlock()
+			if done {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		orderingKey string
+		messageKeys bool
+		hold        bool
+	}{
+		{
+			name: "messages are committed in the order they were read",
+			hold: true,
+		},
+		{
+			name:        "messages with the same key are delivered in order",
+			orderingKey: "user.id",
+		},
+		{
+			name:        "messages with the same message key are delivered in order",
+			messageKeys: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			read, peakInFlight, commits = 0, 0, 0
+			received = map[string][]int{}
+			hold, messageKeys = tt.hold, tt.messageKeys
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			errch := make(chan error, 1)
+			go func() {
+				errch <- s.concurrentReadMessageRoutine(ctx, "someBroker", "transfers", deliveryPolicy{
+					readAttempts:     1,
+					deliveryAttempts: 1,
+				}, &meta.Concurrency{MaxInFlight: maxInFlight, OrderingKey: tt.orderingKey})
+			}()
+
+			if tt.hold {
+				// the messages read after the first one are handled while it's held
+				for i := 0; i < maxInFlight; i++ {
+					select {
+					case <-handled:
+					case <-time.After(5 * time.Second):
+						t.Fatal("concurrentReadMessageRoutine() didn't handle the messages")
+					}
+				}
+				mutex.Lock()
+				if commits != 0 {
+					t.Errorf("concurrentReadMessageRoutine() committed %v messages before the first one was handled", commits)
+				}
+				mutex.Unlock()
+				close(release)
+			}
+
+			waitCommits()
+			cancel()
+			<-errch
+			for len(handled) > 0 {
+				<-handled
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if commits != messages {
+				t.Errorf("concurrentReadMessageRoutine() committed %v messages, want %v", commits, messages)
+			}
+			if peakInFlight < 2 || peakInFlight > maxInFlight {
+				t.Errorf("concurrentReadMessageRoutine() had %v messages in flight, want between 2 and %v",
+					peakInFlight, maxInFlight)
+			}
+			if tt.orderingKey == "" && !tt.messageKeys {
+				return
+			}
+			for user, ns := range received {
+				for i := 1; i < len(ns); i++ {
+					if ns[i] < ns[i-1] {
+						t.Errorf("concurrentReadMessageRoutine() delivered the messages of %v out of order: %v", user, ns)
+						break
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestServer_orderingKey(t *testing.T) {
+	createMockEnvVars()
+	defer deleteMockEnvVars()
+	os.Setenv("ordersTopic_SCHEMA", `{"type":"record","name":"Order","fields":[
+		{"name":"user","type":{"type":"record","name":"User","fields":[{"name":"id","type":"long"}]}}
+	]}`)
+	os.Setenv("orders_RESOLVED", "ordersTopic")
+	defer os.Unsetenv("ordersTopic_SCHEMA")
+	defer os.Unsetenv("orders_RESOLVED")
+
+	s := Init()
+	msg, _ := s.codecs.encode("ordersTopic", map[string]interface{}{
+		"user": map[string]interface{}{"id": int64(42)},
+	})
+
+	tests := []struct {
+		name string
+		path string
+		data []byte
+		want string
+	}{
+		{
+			name: "nested field",
+			path: "user.id",
+			data: msg,
+			want: "42",
+		},
+		{
+			name: "missing field",
+			path: "user.name",
+			data: msg,
+			want: "",
+		},
+		{
+			name: "undecodable message",
+			path: "user.id",
+			data: []byte{0xFF},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.orderingKey("orders", tt.path, tt.data); got != tt.want {
+				t.Errorf("orderingKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
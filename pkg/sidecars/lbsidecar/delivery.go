@@ -0,0 +1,103 @@
+package lbsidecar
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"inspr.dev/inspr/pkg/environment"
+)
+
+// deliveryPolicy is how the sidecar retries reading the messages of an input
+// channel from its broker and delivering them to the node
+type deliveryPolicy struct {
+	readAttempts     int
+	deliveryAttempts int
+	initialBackoff   time.Duration
+	maxBackoff       time.Duration
+	// timeout limits each attempt of delivering a message to the node. Reads
+	// aren't limited by it, since they block until a message is produced
+	timeout time.Duration
+}
+
+// newDeliveryPolicy returns the delivery policy of the given input channel.
+// Channels without one retry reads right away and deliver each message once.
+// When the channel has a dead-letter, its attempts are the ones of deliveries
+func newDeliveryPolicy(channel string) (deliveryPolicy, error) {
+	policy := deliveryPolicy{
+		readAttempts:     maxBrokerRetries + 1,
+		deliveryAttempts: 1,
+	}
+
+	channelPolicy, err := environment.GetDeliveryPolicy(channel)
+	if err != nil {
+		return policy, err
+	}
+	if channelPolicy != nil {
+		policy.readAttempts = channelPolicy.MaxAttempts
+		policy.deliveryAttempts = channelPolicy.MaxAttempts
+
+		policy.initialBackoff, err = parseDuration(channelPolicy.InitialBackoff)
+		if err != nil {
+			return policy, err
+		}
+		policy.maxBackoff, err = parseDuration(channelPolicy.MaxBackoff)
+		if err != nil {
+			return policy, err
+		}
+		policy.timeout, err = parseDuration(channelPolicy.Timeout)
+		if err != nil {
+			return policy, err
+		}
+	}
+
+	if deadLetter := environment.GetDeadLetter(channel); deadLetter != nil {
+		policy.deliveryAttempts = deadLetter.MaxAttempts
+	}
+	return policy, nil
+}
+
+func parseDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// backoff returns how long to wait after the given failed attempt, which
+// starts at the initial backoff and doubles with each attempt up to the max
+// backoff. The jitter keeps the sidecars failing at the same time from
+// trying again all at once, as the wait is anywhere from half of it to it
+func (p deliveryPolicy) backoff(attempt int) time.Duration {
+	d := p.initialBackoff
+	for i := 1; i < attempt && d < p.maxBackoff; i++ {
+		d *= 2
+	}
+	if d > p.maxBackoff {
+		d = p.maxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
+// wait blocks for the backoff of the given failed attempt, returning early
+// with an error when the context is done
+func (p deliveryPolicy) wait(ctx context.Context, attempt int) error {
+	d := p.backoff(attempt)
+	if d == 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,298 @@
+package lbsidecar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"inspr.dev/inspr/pkg/sidecars/models"
+)
+
+type mockReader struct {
+	readMessage func(ctx context.Context, channel string) ([]byte, error)
+	// readRecord is used instead of readMessage, when set, to read messages
+	// along with their envelopes
+	readRecord func(ctx context.Context, channel string) (models.BrokerRecord, error)
+	commit     func(ctx context.Context, channel string) error
+}
+
+func (m *mockReader) ReadMessage(ctx context.Context, channel string) (models.BrokerRecord, error) {
+	if m.readRecord != nil {
+		return m.readRecord(ctx, channel)
+	}
+	value, err := m.readMessage(ctx, channel)
+	return models.BrokerRecord{Value: value}, err
+}
+
+func (m *mockReader) Commit(ctx context.Context, channel string) error {
+	if m.commit == nil {
+		return nil
+	}
+	return m.commit(ctx, channel)
+}
+
+func (m *mockReader) Close() error { return nil }
+
+func Test_newDeliveryPolicy(t *testing.T) {
+	env := map[string]string{
+		"payments_DELIVERY_POLICY":    `{"maxattempts":4,"initialbackoff":"1s","maxbackoff":"1m","timeout":"3s"}`,
+		"refunds_DELIVERY_POLICY":     `{"maxattempts":4}`,
+		"refunds_DEADLETTER":          "refunds.deadletter",
+		"refunds_DEADLETTER_ATTEMPTS": "2",
+		"invalid_DELIVERY_POLICY":     `{"timeout":"soon"}`,
+	}
+	for key, value := range env {
+		os.Setenv(key, value)
+	}
+	defer func() {
+		for key := range env {
+			os.Unsetenv(key)
+		}
+	}()
+
+	tests := []struct {
+		name    string
+		channel string
+		want    deliveryPolicy
+		wantErr bool
+	}{
+		{
+			name:    "channel without delivery policy",
+			channel: "orders",
+			want: deliveryPolicy{
+				readAttempts:     maxBrokerRetries + 1,
+				deliveryAttempts: 1,
+			},
+		},
+		{
+			name:    "channel with delivery policy",
+			channel: "payments",
+			want: deliveryPolicy{
+				readAttempts:     4,
+				deliveryAttempts: 4,
+				initialBackoff:   time.Second,
+				maxBackoff:       time.Minute,
+				timeout:          3 * time.Second,
+			},
+		},
+		{
+			name:    "dead-letter attempts are the delivery attempts",
+			channel: "refunds",
+			want: deliveryPolicy{
+				readAttempts:     4,
+				deliveryAttempts: 2,
+				initialBackoff:   100 * time.Millisecond,
+				maxBackoff:       10 * time.Second,
+			},
+		},
+		{
+			name:    "invalid delivery policy",
+			channel: "invalid",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newDeliveryPolicy(tt.channel)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newDeliveryPolicy() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("newDeliveryPolicy() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_deliveryPolicy_backoff(t *testing.T) {
+	policy := deliveryPolicy{
+		initialBackoff: 100 * time.Millisecond,
+		maxBackoff:     time.Second,
+	}
+	tests := []struct {
+		name     string
+		policy   deliveryPolicy
+		attempt  int
+		min, max time.Duration
+	}{
+		{
+			name:    "first attempt waits the initial backoff",
+			policy:  policy,
+			attempt: 1,
+			min:     50 * time.Millisecond,
+			max:     100 * time.Millisecond,
+		},
+		{
+			name:    "backoff doubles with each attempt",
+			policy:  policy,
+			attempt: 3,
+			min:     200 * time.Millisecond,
+			max:     400 * time.Millisecond,
+		},
+		{
+			name:    "backoff is limited by the max backoff",
+			policy:  policy,
+			attempt: 100,
+			min:     500 * time.Millisecond,
+			max:     time.Second,
+		},
+		{
+			name:    "policy without backoff",
+			policy:  deliveryPolicy{},
+			attempt: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				got := tt.policy.backoff(tt.attempt)
+				if got < tt.min || got > tt.max {
+					t.Fatalf("backoff() = %v, want between %v and %v", got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_readWithRetry(t *testing.T) {
+	createMockEnvVars()
+	defer deleteMockEnvVars()
+
+	var reads, failures int
+	reader := &mockReader{readMessage: func(ctx context.Context, channel string) ([]byte, error) {
+		reads++
+		if reads <= failures {
+			return nil, errors.New("broker unavailable")
+		}
+		return []byte("message"), nil
+	}}
+	s := Init(models.NewBrokerHandler("someBroker", reader, nil))
+	policy := deliveryPolicy{
+		readAttempts:   3,
+		initialBackoff: time.Millisecond,
+		maxBackoff:     time.Millisecond,
+	}
+
+	tests := []struct {
+		name        string
+		failures    int
+		wantReads   int
+		wantRetries float64
+		wantErr     bool
+	}{
+		{
+			name:        "message read after retries",
+			failures:    2,
+			wantReads:   3,
+			wantRetries: 2,
+		},
+		{
+			name:        "attempts run out",
+			failures:    5,
+			wantReads:   3,
+			wantRetries: 4,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reads, failures = 0, tt.failures
+
+			_, err := s.readWithRetry(context.Background(), "someBroker", "payments", policy)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("readWithRetry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if reads != tt.wantReads {
+				t.Errorf("readWithRetry() read %v times, want %v", reads, tt.wantReads)
+			}
+			if got := testutil.ToFloat64(s.GetChannelMetric("payments").readRetries); got != tt.wantRetries {
+				t.Errorf("readWithRetry() retries metric = %v, want %v", got, tt.wantRetries)
+			}
+		})
+	}
+}
+
+func TestServer_deliver(t *testing.T) {
+	createMockEnvVars()
+	defer deleteMockEnvVars()
+
+	var mutex sync.Mutex
+	var requests, slowRequests, lastAttempt int
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg models.BrokerMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+
+		mutex.Lock()
+		requests++
+		slow := requests <= slowRequests
+		lastAttempt = msg.Envelope.Attempt
+		mutex.Unlock()
+		if slow {
+			time.Sleep(50 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer node.Close()
+
+	s := Init()
+	s.clientAddr = node.URL
+	policy := deliveryPolicy{
+		deliveryAttempts: 3,
+		initialBackoff:   time.Millisecond,
+		maxBackoff:       time.Millisecond,
+		timeout:          10 * time.Millisecond,
+	}
+
+	tests := []struct {
+		name         string
+		slowRequests int
+		wantAttempts int
+		wantRetries  float64
+		wantErr      bool
+	}{
+		{
+			name:         "message delivered after a timeout",
+			slowRequests: 1,
+			wantAttempts: 2,
+			wantRetries:  1,
+		},
+		{
+			name:         "every attempt times out",
+			slowRequests: 3,
+			wantAttempts: 3,
+			wantRetries:  3,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mutex.Lock()
+			requests, slowRequests = 0, tt.slowRequests
+			mutex.Unlock()
+
+			attempts, err := s.deliver(context.Background(), "invoices", policy, models.BrokerMessage{Data: "invoice"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("deliver() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("deliver() attempts = %v, want %v", attempts, tt.wantAttempts)
+			}
+			mutex.Lock()
+			if lastAttempt != tt.wantAttempts {
+				t.Errorf("deliver() delivered attempt %v, want %v", lastAttempt, tt.wantAttempts)
+			}
+			mutex.Unlock()
+			if got := testutil.ToFloat64(s.GetChannelMetric("invoices").deliveryRetries); got != tt.wantRetries {
+				t.Errorf("deliver() retries metric = %v, want %v", got, tt.wantRetries)
+			}
+		})
+	}
+}
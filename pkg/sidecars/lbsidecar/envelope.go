@@ -0,0 +1,37 @@
+package lbsidecar
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/sidecars/models"
+)
+
+// newEnvelope returns the envelope a message written by the node is stored
+// with in the broker. Only the headers, the key and the trace context are
+// taken from the node, the rest of the envelope is set by the sidecar
+func newEnvelope(written *models.Envelope) (models.Envelope, error) {
+	envelope := models.Envelope{
+		ProducedAt: time.Now().UTC(),
+		SourceApp:  os.Getenv("INSPR_APP_ID"),
+	}
+	if written == nil {
+		return envelope, nil
+	}
+
+	for header := range written.Headers {
+		if strings.HasPrefix(strings.ToLower(header), models.ReservedHeaderPrefix) {
+			return envelope, ierrors.New(
+				"header '%s' is reserved, headers can't start with '%s'",
+				header, models.ReservedHeaderPrefix,
+			).BadRequest()
+		}
+	}
+
+	envelope.Headers = written.Headers
+	envelope.Key = written.Key
+	envelope.Trace = written.Trace
+	return envelope, nil
+}
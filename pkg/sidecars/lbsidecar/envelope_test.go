@@ -0,0 +1,188 @@
+package lbsidecar
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"inspr.dev/inspr/pkg/rest"
+	"inspr.dev/inspr/pkg/sidecars/models"
+)
+
+func Test_newEnvelope(t *testing.T) {
+	os.Setenv("INSPR_APP_ID", "someApp")
+	defer os.Unsetenv("INSPR_APP_ID")
+
+	tests := []struct {
+		name    string
+		written *models.Envelope
+		want    models.Envelope
+		wantErr bool
+	}{
+		{
+			name: "message without envelope",
+			want: models.Envelope{SourceApp: "someApp"},
+		},
+		{
+			name: "headers and key are kept",
+			written: &models.Envelope{
+				Headers:   map[string]string{"trace": "abc"},
+				Key:       "customer-1",
+				SourceApp: "otherApp",
+				Attempt:   3,
+			},
+			want: models.Envelope{
+				Headers:   map[string]string{"trace": "abc"},
+				Key:       "customer-1",
+				SourceApp: "someApp",
+			},
+		},
+		{
+			name: "reserved header",
+			written: &models.Envelope{
+				Headers: map[string]string{"Inspr-Source-App": "otherApp"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := time.Now()
+			got, err := newEnvelope(tt.written)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newEnvelope() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got.ProducedAt.Before(before.Add(-time.Second)) || got.ProducedAt.Location() != time.UTC {
+				t.Errorf("newEnvelope() producedAt = %v", got.ProducedAt)
+			}
+			got.ProducedAt = time.Time{}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newEnvelope() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_writeMessageHandler_envelope(t *testing.T) {
+	createMockEnvVars()
+	defer deleteMockEnvVars()
+	os.Setenv("INSPR_OUTPUT_CHANNELS", "receipts@someBroker")
+	os.Setenv("receipts_RESOLVED", "someTopic")
+	defer os.Unsetenv("receipts_RESOLVED")
+
+	writer := &mockWriter{
+		writeMessage:  func(channel string, message []byte) error { return nil },
+		writeMessages: func(channel string, messages [][]byte) error { return nil },
+	}
+	s := Init(models.NewBrokerHandler("someBroker", nil, writer))
+
+	tests := []struct {
+		name     string
+		handler  rest.Handler
+		path     string
+		body     interface{}
+		wantCode int
+		want     []models.Envelope
+	}{
+		{
+			name:    "message with headers and key",
+			handler: s.writeMessageHandler(),
+			path:    "/channel/receipts",
+			body: models.BrokerMessage{
+				Data: "hello",
+				Envelope: &models.Envelope{
+					Headers: map[string]string{"trace": "abc"},
+					Key:     "customer-1",
+				},
+			},
+			wantCode: http.StatusOK,
+			want: []models.Envelope{{
+				Headers: map[string]string{"trace": "abc"},
+				Key:     "customer-1",
+			}},
+		},
+		{
+			name:    "batch with an envelope per message",
+			handler: s.writeMessagesHandler(),
+			path:    "/batch/channel/receipts",
+			body: models.BrokerBatch{Messages: []models.BrokerMessage{
+				{Data: "first", Envelope: &models.Envelope{Key: "customer-1"}},
+				{Data: "second"},
+			}},
+			wantCode: http.StatusOK,
+			want: []models.Envelope{
+				{Key: "customer-1"},
+				{},
+			},
+		},
+		{
+			name:    "message with a reserved header",
+			handler: s.writeMessageHandler(),
+			path:    "/channel/receipts",
+			body: models.BrokerMessage{
+				Data: "hello",
+				Envelope: &models.Envelope{
+					Headers: map[string]string{models.SourceAppHeader: "otherApp"},
+				},
+			},
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer.records = nil
+
+			body, _ := json.Marshal(tt.body)
+			w := httptest.NewRecorder()
+			tt.handler(w, httptest.NewRequest(http.MethodPost, tt.path, bytes.NewReader(body)))
+			if w.Code != tt.wantCode {
+				t.Fatalf("handler status = %v, want %v", w.Code, tt.wantCode)
+			}
+
+			var got []models.Envelope
+			for _, record := range writer.records {
+				if record.Envelope.ProducedAt.IsZero() {
+					t.Errorf("handler wrote a record without producedAt")
+				}
+				record.Envelope.ProducedAt = time.Time{}
+				record.Envelope.SourceApp = ""
+				record.Envelope.Trace = nil
+				got = append(got, record.Envelope)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("handler wrote envelopes %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_decodeMessage_envelope(t *testing.T) {
+	createMockEnvVars()
+	defer deleteMockEnvVars()
+
+	s := Init(models.NewBrokerHandler("someBroker", nil, &mockWriter{}))
+	encoded, _ := s.codecs.encode("someTopic", "hello")
+
+	envelope := models.Envelope{
+		Headers:    map[string]string{"trace": "abc"},
+		Key:        "customer-1",
+		ProducedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		SourceApp:  "someApp",
+	}
+	got, err := s.decodeMessage("chan", models.BrokerRecord{Value: encoded, Envelope: envelope})
+	if err != nil {
+		t.Fatalf("decodeMessage() error = %v", err)
+	}
+	if got.Data != "hello" || got.Envelope == nil || !reflect.DeepEqual(*got.Envelope, envelope) {
+		t.Errorf("decodeMessage() = %+v", got)
+	}
+}
@@ -0,0 +1,149 @@
+package lbsidecar
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/sidecars/models"
+	"inspr.dev/inspr/pkg/sidecars/transport"
+	"inspr.dev/inspr/pkg/tracing"
+)
+
+// sidecarService serves the Sidecar gRPC service to the node
+type sidecarService struct {
+	transport.UnimplementedSidecarServer
+	s *Server
+}
+
+// grpcServer returns the gRPC server of the Sidecar service
+func (s *Server) grpcServer() *grpc.Server {
+	server := grpc.NewServer()
+	transport.RegisterSidecarServer(server, &sidecarService{s: s})
+	return server
+}
+
+// WriteMessage writes the messages of the stream to the brokers of their
+// channels, as the write server does with the ones sent through HTTP
+func (svc *sidecarService) WriteMessage(stream transport.Sidecar_WriteMessageServer) error {
+	logger.Info("node opened a stream to write messages")
+	return transport.ServeStream(stream, svc.writeMessages)
+}
+
+// writeMessages writes a message, or a batch of messages, received through
+// the stream
+func (svc *sidecarService) writeMessages(
+	ctx context.Context,
+	channel string,
+	batch bool,
+	msgs []models.BrokerMessage,
+) error {
+	if batch {
+		return svc.s.writeBatch(ctx, channel, msgs)
+	}
+	if len(msgs) != 1 {
+		return ierrors.New("invalid message for channel '%s'", channel).BadRequest()
+	}
+	return svc.s.writeMessage(ctx, channel, msgs[0])
+}
+
+// SendRequest sends a route request of the node to the sidecar of the route.
+// Unlike through HTTP, where the node is redirected to it, the request is
+// sent by the sidecar itself
+func (svc *sidecarService) SendRequest(
+	ctx context.Context,
+	req *transport.RouteRequest,
+) (*transport.RouteResponse, error) {
+	start := time.Now()
+
+	URL, err := svc.s.routeURL(req.GetRoute() + "/" + req.GetPath())
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("sending request to route",
+		zap.String("route", req.GetRoute()),
+		zap.String("URL", URL))
+
+	ctx, span := tracing.Tracer().Start(
+		tracing.Extract(ctx, req.GetHeader()),
+		"request "+req.GetRoute(),
+		trace.WithSpanKind(trace.SpanKindClient),
+	)
+	defer span.End()
+
+	r, err := http.NewRequestWithContext(ctx, req.GetMethod(), URL, bytes.NewReader(req.GetBody()))
+	if err != nil {
+		return nil, ierrors.New(err).BadRequest()
+	}
+	for key, value := range req.GetHeader() {
+		r.Header.Set(key, value)
+	}
+	tracing.InjectHeader(ctx, r.Header)
+
+	resp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		svc.s.getRouteSenderMetric(req.GetRoute()).routeSendError.Inc()
+		tracing.End(span, err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	elapsed := time.Since(start)
+	svc.s.getRouteSenderMetric(req.GetRoute()).routeSendDuration.Observe(elapsed.Seconds())
+
+	return &transport.RouteResponse{
+		Status: int32(resp.StatusCode),
+		Header: flattenHeader(resp.Header),
+		Body:   body,
+	}, nil
+}
+
+// forwardRouteToNode sends a request received on a route to the endpoint of
+// the node through gRPC, writing its response back
+func (s *Server) forwardRouteToNode(w http.ResponseWriter, r *http.Request, endpoint string) error {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+
+	header := flattenHeader(r.Header)
+	header["X-Forwarded-For"] = r.RemoteAddr
+
+	resp, err := s.nodeRoutes.HandleRoute(r.Context(), &transport.RouteRequest{
+		Path:   endpoint,
+		Method: r.Method,
+		Header: header,
+		Body:   body,
+	})
+	if err != nil {
+		return err
+	}
+
+	for key, value := range resp.GetHeader() {
+		w.Header().Set(key, value)
+	}
+	w.WriteHeader(int(resp.GetStatus()))
+	w.Write(resp.GetBody())
+	return nil
+}
+
+// flattenHeader keeps the first value of each field of an HTTP header
+func flattenHeader(header http.Header) map[string]string {
+	flat := make(map[string]string, len(header))
+	for key := range header {
+		flat[key] = header.Get(key)
+	}
+	return flat
+}
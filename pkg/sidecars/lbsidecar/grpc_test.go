@@ -0,0 +1,228 @@
+package lbsidecar
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/sidecars/models"
+	"inspr.dev/inspr/pkg/sidecars/transport"
+)
+
+// serveGRPC serves the gRPC server on a Unix socket of its own, as the node
+// and its sidecar do, and returns a connection to it
+func serveGRPC(t *testing.T, server *grpc.Server) *grpc.ClientConn {
+	addr := transport.SidecarAddr(t.TempDir())
+	listener, err := transport.Listen(addr)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := transport.Dial(addr)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestSidecarService_WriteMessage(t *testing.T) {
+	createMockEnvVars()
+	defer deleteMockEnvVars()
+	os.Setenv("INSPR_OUTPUT_CHANNELS", "streamed@someBroker")
+	os.Setenv("streamed_RESOLVED", "someTopic")
+	defer os.Unsetenv("streamed_RESOLVED")
+
+	var written [][]byte
+	writer := &mockWriter{
+		writeMessage: func(channel string, message []byte) error {
+			written = append(written, message)
+			return nil
+		},
+		writeMessages: func(channel string, messages [][]byte) error {
+			written = append(written, messages...)
+			return nil
+		},
+	}
+	s := Init(models.NewBrokerHandler("someBroker", nil, writer))
+
+	streamer := transport.NewSidecarStreamer(serveGRPC(t, s.grpcServer()))
+	defer streamer.Close()
+
+	tests := []struct {
+		name        string
+		channel     string
+		batch       bool
+		msgs        []models.BrokerMessage
+		wantCode    ierrors.ErrCode
+		wantWritten []interface{}
+	}{
+		{
+			name:        "message written",
+			channel:     "streamed",
+			msgs:        []models.BrokerMessage{{Data: "hello"}},
+			wantWritten: []interface{}{"hello"},
+		},
+		{
+			name:        "batch written",
+			channel:     "streamed",
+			batch:       true,
+			msgs:        []models.BrokerMessage{{Data: "hello"}, {Data: "world"}},
+			wantWritten: []interface{}{"hello", "world"},
+		},
+		{
+			name:     "channel isn't an output channel",
+			channel:  "chan",
+			msgs:     []models.BrokerMessage{{Data: "hello"}},
+			wantCode: ierrors.BadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			written = nil
+
+			err := streamer.Send(context.Background(), tt.channel, tt.batch, tt.msgs)
+			if (err != nil) != (tt.wantCode != 0) {
+				t.Fatalf("WriteMessage() error = %v, want code %v", err, tt.wantCode)
+			}
+			if err != nil && !ierrors.HasCode(err, tt.wantCode) {
+				t.Errorf("WriteMessage() error = %v, want code %v", err, tt.wantCode)
+			}
+
+			if len(written) != len(tt.wantWritten) {
+				t.Fatalf("WriteMessage() wrote %v messages, want %v", len(written), len(tt.wantWritten))
+			}
+			for i, msg := range written {
+				got, _ := s.codecs.decode("someTopic", msg)
+				if got != tt.wantWritten[i] {
+					t.Errorf("WriteMessage() message %v = %v, want %v", i, got, tt.wantWritten[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSidecarService_SendRequest(t *testing.T) {
+	createMockEnvVars()
+	defer deleteMockEnvVars()
+
+	route := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/route/grpcroute/hello" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	}))
+	defer route.Close()
+	os.Setenv("grpcroute_ROUTE", route.URL+";hello")
+	defer os.Unsetenv("grpcroute_ROUTE")
+
+	s := Init()
+	conn := serveGRPC(t, s.grpcServer())
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int32
+		wantErr    bool
+	}{
+		{
+			name:       "request sent to the route",
+			path:       "hello",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:    "endpoint isn't in the route",
+			path:    "goodbye",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := transport.NewSidecarClient(conn).SendRequest(context.Background(), &transport.RouteRequest{
+				Route:  "grpcroute",
+				Path:   tt.path,
+				Method: http.MethodPost,
+				Body:   []byte(`"hello"`),
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SendRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if resp.GetStatus() != tt.wantStatus {
+				t.Errorf("SendRequest() status = %v, want %v", resp.GetStatus(), tt.wantStatus)
+			}
+			if string(resp.GetBody()) != `"hello"` {
+				t.Errorf("SendRequest() body = %s, want %v", resp.GetBody(), `"hello"`)
+			}
+		})
+	}
+}
+
+func TestServer_grpcNode(t *testing.T) {
+	createMockEnvVars()
+	defer deleteMockEnvVars()
+
+	var delivered []models.BrokerMessage
+	handle := func(ctx context.Context, channel string, batch bool, msgs []models.BrokerMessage) error {
+		if channel != "grpcdeliveries" || batch {
+			return ierrors.New("channel '%s' not found", channel).NotFound()
+		}
+		delivered = append(delivered, msgs...)
+		return nil
+	}
+	routes := http.NewServeMux()
+	routes.HandleFunc("/route/hello", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write(body)
+	})
+
+	server := grpc.NewServer()
+	transport.RegisterNodeServer(server, transport.NewNodeServer(handle, routes))
+	conn := serveGRPC(t, server)
+
+	s := Init()
+	s.node = transport.NewNodeStreamer(conn)
+	s.nodeRoutes = transport.NewNodeClient(conn)
+	defer s.node.Close()
+
+	t.Run("message delivered through the stream", func(t *testing.T) {
+		attempts, err := s.deliver(context.Background(), "grpcdeliveries", deliveryPolicy{
+			deliveryAttempts: 1,
+			timeout:          time.Second,
+		}, models.BrokerMessage{Data: "hello"})
+		if err != nil || attempts != 1 {
+			t.Errorf("deliver() = %v, %v, want 1 attempt", attempts, err)
+		}
+		if len(delivered) != 1 || delivered[0].Data != "hello" || delivered[0].Envelope.Attempt != 1 {
+			t.Errorf("deliver() delivered %v", delivered)
+		}
+	})
+
+	t.Run("route request sent through gRPC", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		s.routeReceiveHandler()(w, httptest.NewRequest(
+			http.MethodPost, "/route/grpcnode/hello", bytes.NewBufferString(`"hello"`),
+		))
+		if w.Code != http.StatusAccepted {
+			t.Errorf("routeReceiveHandler() status = %v, want %v", w.Code, http.StatusAccepted)
+		}
+		if w.Body.String() != `"hello"` {
+			t.Errorf("routeReceiveHandler() body = %v, want %v", w.Body.String(), `"hello"`)
+		}
+	})
+}
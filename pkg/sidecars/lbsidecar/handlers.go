@@ -6,19 +6,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"inspr.dev/inspr/pkg/environment"
 	"inspr.dev/inspr/pkg/ierrors"
 	"inspr.dev/inspr/pkg/logs"
+	"inspr.dev/inspr/pkg/meta"
 	"inspr.dev/inspr/pkg/rest"
 	"inspr.dev/inspr/pkg/sidecars/models"
+	"inspr.dev/inspr/pkg/tracing"
 )
 
 var logger *zap.Logger
@@ -33,62 +35,76 @@ func init() {
 // writeMessageHandler handles requests sent to the write message server
 func (s *Server) writeMessageHandler() rest.Handler {
 	return func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
 		defer r.Body.Close()
 
 		channel := strings.TrimPrefix(r.URL.Path, "/channel/")
 		logger.Info("handling message write on " + channel)
 
-		if !environment.OutputChannelList().Contains(channel) {
-			logger.Error(fmt.Sprintf("channel %s not found in output channel list", channel))
+		var receivedMsg models.BrokerMessage
+		json.NewDecoder(r.Body).Decode(&receivedMsg)
 
-			rest.ERROR(
-				w,
-				ierrors.New("channel '%s' not found", channel).BadRequest(),
-			)
+		if err := s.writeMessage(r.Context(), channel, receivedMsg); err != nil {
+			rest.ERROR(w, err)
 			return
 		}
+		rest.JSON(w, 200, nil)
+	}
+}
 
-		channelBroker, err := environment.GetChannelBroker(channel)
-		if err != nil {
-			logger.Error("unable to get channel broker",
-				zap.String("channel", channel),
-				zap.Any("error", err))
+// writeMessage writes a message of the node to the broker of its channel
+func (s *Server) writeMessage(ctx context.Context, channel string, msg models.BrokerMessage) error {
+	start := time.Now()
 
-			rest.ERROR(w, err)
-			return
-		}
+	channelBroker, err := writableChannelBroker(channel)
+	if err != nil {
+		return err
+	}
 
-		logger.Debug("encoding message to Avro schema")
+	logger.Debug("encoding message")
 
-		encodedMsg, err := encodeToAvro(channel, r.Body)
-		if err != nil {
-			logger.Error("unable to encode message to Avro schema",
-				zap.String("channel", channel),
-				zap.Any("error", err))
+	record, err := s.encodeMessage(channel, msg)
+	if err != nil {
+		logger.Error("unable to encode message",
+			zap.String("channel", channel),
+			zap.Any("error", err))
+		return err
+	}
 
-			rest.ERROR(w, err)
-			return
-		}
+	logger.Info("writing message to broker",
+		zap.String("broker", channelBroker),
+		zap.String("channel", channel))
 
-		logger.Info("writing message to broker",
-			zap.String("broker", channelBroker),
-			zap.String("channel", channel))
+	_, span := startMessageSpan(ctx, "write", trace.SpanKindProducer, channel, &record.Envelope)
+	err = s.brokerHandlers[channelBroker].Writer().WriteMessage(channel, record)
+	tracing.End(span, err)
+	if err != nil {
+		s.GetChannelMetric(channel).messageSendError.Inc()
+		return ierrors.New("broker's WriteMessage failed, %s", err.Error())
+	}
 
-		if err := s.brokerHandlers[channelBroker].Writer().WriteMessage(channel, encodedMsg); err != nil {
-			rest.ERROR(
-				w,
-				ierrors.New("broker's WriteMessage failed, %s", err.Error()),
-			)
-			s.GetChannelMetric(channel).messageSendError.Inc()
-			return
-		}
-		rest.JSON(w, 200, nil)
+	s.GetChannelMetric(channel).messagesSent.Inc()
+	elapsed := time.Since(start)
+	s.GetChannelMetric(channel).writeMessageDuration.Observe(elapsed.Seconds())
+	return nil
+}
 
-		s.GetChannelMetric(channel).messagesSent.Inc()
-		elapsed := time.Since(start)
-		s.GetChannelMetric(channel).writeMessageDuration.Observe(elapsed.Seconds())
+// writableChannelBroker returns the broker of a channel the node can write
+// to, which is one of its output channels
+func writableChannelBroker(channel string) (string, error) {
+	// dead-letter channels are written to by the sidecar only
+	if !environment.OutputChannelList().Contains(channel) || environment.IsDeadLetterChannel(channel) {
+		logger.Error(fmt.Sprintf("channel %s not found in output channel list", channel))
+		return "", ierrors.New("channel '%s' not found", channel).BadRequest()
+	}
+
+	channelBroker, err := environment.GetChannelBroker(channel)
+	if err != nil {
+		logger.Error("unable to get channel broker",
+			zap.String("channel", channel),
+			zap.Any("error", err))
+		return "", err
 	}
+	return channelBroker, nil
 }
 
 func (s *Server) sendRouteRequest() rest.Handler {
@@ -96,37 +112,13 @@ func (s *Server) sendRouteRequest() rest.Handler {
 		start := time.Now()
 
 		path := strings.TrimPrefix(r.URL.Path, "/route/")
-		pathArgs := strings.Split(path, "/")
-		route := pathArgs[0]
-		endpoint := pathArgs[1]
-
-		logger.Info("handling route request", zap.String("route", route), zap.String("path", path))
-		resolved, err := environment.GetRouteData(route)
-
+		URL, err := s.routeURL(path)
 		if err != nil {
-			s.getRouteSenderMetric(route).routeSendError.Inc()
-
-			logger.Error("unable to send request to route",
-				zap.String("route", route),
-				zap.Any("error", err))
-
-			rest.ERROR(w, err)
-			return
-		}
-
-		if !resolved.Endpoints.Contains(endpoint) {
-
-			s.getRouteSenderMetric(route).routeSendError.Inc()
-
-			err = ierrors.New("invalid endpoint: %s", endpoint).BadRequest()
-			logger.Error("unable to send request to "+path,
-				zap.Any("error", err))
-
 			rest.ERROR(w, err)
 			return
 		}
-		URL := fmt.Sprintf("%s/route/%s", resolved.Address, path)
 
+		route := strings.Split(path, "/")[0]
 		logger.Info("redirecting request", zap.String("route", route), zap.Any("URL", URL))
 		http.Redirect(w, r, URL, http.StatusPermanentRedirect)
 
@@ -135,6 +127,42 @@ func (s *Server) sendRouteRequest() rest.Handler {
 	}
 }
 
+// routeURL returns the URL the requests to the path of a route are sent to,
+// where the path starts with the name of the route and one of its endpoints
+func (s *Server) routeURL(path string) (string, error) {
+	pathArgs := strings.Split(path, "/")
+	route := pathArgs[0]
+	endpoint := ""
+	if len(pathArgs) > 1 {
+		endpoint = pathArgs[1]
+	}
+
+	logger.Info("handling route request", zap.String("route", route), zap.String("path", path))
+	resolved, err := environment.GetRouteData(route)
+
+	if err != nil {
+		s.getRouteSenderMetric(route).routeSendError.Inc()
+
+		logger.Error("unable to send request to route",
+			zap.String("route", route),
+			zap.Any("error", err))
+
+		return "", err
+	}
+
+	if !resolved.Endpoints.Contains(endpoint) {
+
+		s.getRouteSenderMetric(route).routeSendError.Inc()
+
+		err = ierrors.New("invalid endpoint: %s", endpoint).BadRequest()
+		logger.Error("unable to send request to "+path,
+			zap.Any("error", err))
+
+		return "", err
+	}
+	return fmt.Sprintf("%s/route/%s", resolved.Address, path), nil
+}
+
 // routeReceiveHandler handles any requests received in the "/route" path, for the lbsidecar
 func (s *Server) routeReceiveHandler() rest.Handler {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -150,6 +178,16 @@ func (s *Server) routeReceiveHandler() rest.Handler {
 			endpoint = splitRoute[1]
 		}
 
+		// the request is forwarded to the node in the span of the route
+		ctx, span := tracing.Tracer().Start(
+			tracing.ExtractHeader(r.Context(), r.Header),
+			"route "+splitRoute[0],
+			trace.WithSpanKind(trace.SpanKindServer),
+		)
+		defer span.End()
+		tracing.InjectHeader(ctx, r.Header)
+		r = r.WithContext(ctx)
+
 		// port resolution: using the same as readHandler -> clientReadPort
 		clientReadPort := os.Getenv("INSPR_SCCLIENT_READ_PORT")
 		if clientReadPort == "" {
@@ -164,9 +202,25 @@ func (s *Server) routeReceiveHandler() rest.Handler {
 			return
 		}
 
+		if s.nodeRoutes != nil {
+			if err := s.forwardRouteToNode(w, r, endpoint); err != nil {
+				s.GetRouteHandlerMetric(splitRoute[0]).routeReadError.Inc()
+
+				logger.Error("route: unable to send request from lbsidecar to node",
+					zap.Any("error", err))
+
+				tracing.End(span, err)
+				rest.ERROR(w, err)
+				return
+			}
+			elapsed := time.Since(start)
+			s.GetRouteHandlerMetric(splitRoute[0]).routeHandleDuration.Observe(elapsed.Seconds())
+			return
+		}
+
 		// Redirect the request
 		// localhost:port/route/endpoint
-		client := http.DefaultClient
+		client := s.httpClient()
 
 		URL, _ := url.Parse(fmt.Sprintf("http://localhost:%v/route/%v", clientReadPort, endpoint))
 		r.URL = URL
@@ -182,6 +236,7 @@ func (s *Server) routeReceiveHandler() rest.Handler {
 			logger.Error("route: unable to send request from lbsidecar to node",
 				zap.Any("error", err))
 
+			tracing.End(span, err)
 			rest.ERROR(w, err)
 			return
 		}
@@ -196,13 +251,13 @@ func (s *Server) routeReceiveHandler() rest.Handler {
 	}
 }
 
-func sendRequest(addr string, body []byte) (*http.Response, error) {
-	client := http.DefaultClient
-	req, err := http.NewRequest(http.MethodPost, addr, bytes.NewBuffer(body))
+func sendRequest(ctx context.Context, client *http.Client, addr string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, addr, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
 	defer req.Body.Close()
+	tracing.InjectHeader(ctx, req.Header)
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -212,45 +267,43 @@ func sendRequest(addr string, body []byte) (*http.Response, error) {
 	return resp, nil
 }
 
-func encodeToAvro(channel string, body io.Reader) ([]byte, error) {
-	var receivedMsg models.BrokerMessage
-	json.NewDecoder(body).Decode(&receivedMsg)
-
+// encodeMessage encodes a message written by the node with the codec of its
+// channel, along with its envelope
+func (s *Server) encodeMessage(channel string, receivedMsg models.BrokerMessage) (models.BrokerRecord, error) {
 	resolvedCh, err := getResolvedChannel(channel)
 	if err != nil {
-		return nil, err
+		return models.BrokerRecord{}, err
 	}
 
-	encodedAvroMsg, err := encode(resolvedCh, receivedMsg.Data)
+	envelope, err := newEnvelope(receivedMsg.Envelope)
 	if err != nil {
-		return nil, err
+		return models.BrokerRecord{}, err
 	}
 
-	return encodedAvroMsg, nil
-}
-
-func decodeFromAvro(channel string, body io.Reader) ([]byte, error) {
-	receivedMsg, err := ioutil.ReadAll(body)
+	encodedMsg, err := s.codecs.encode(resolvedCh, receivedMsg.Data)
 	if err != nil {
-		return nil, err
+		return models.BrokerRecord{}, err
 	}
 
-	resolvedCh, err := getResolvedChannel(channel)
-	if err != nil {
-		return nil, err
-	}
+	return models.BrokerRecord{Value: encodedMsg, Envelope: envelope}, nil
+}
 
-	decodedAvroMsg, err := readMessage(resolvedCh, receivedMsg)
+// decodeMessage decodes a message read from a channel into the message
+// delivered to the node, along with its envelope
+func (s *Server) decodeMessage(channel string, record models.BrokerRecord) (models.BrokerMessage, error) {
+	resolvedCh, err := getResolvedChannel(channel)
 	if err != nil {
-		return nil, err
+		return models.BrokerMessage{}, err
 	}
 
-	jsonEncodedMsg, err := json.Marshal(decodedAvroMsg)
+	decodedMsg, err := s.codecs.readMessage(resolvedCh, record.Value)
 	if err != nil {
-		return nil, err
+		return models.BrokerMessage{}, err
 	}
 
-	return jsonEncodedMsg, nil
+	envelope := record.Envelope
+	decodedMsg.Envelope = &envelope
+	return decodedMsg, nil
 }
 
 func getResolvedChannel(channel string) (string, error) {
@@ -272,7 +325,7 @@ func (s *Server) readMessageRoutine(ctx context.Context) error {
 
 	for broker := range s.brokerHandlers {
 		// selects all intalled brokers
-		for _, channel := range environment.InputBrokerChannels(broker) {
+		for _, channel := range environment.GetInputBrokerChannels(broker) {
 			// separates several threads for each channel of this broker
 			go func(broker, channel string) { errch <- s.channelReadMessageRoutine(newCtx, broker, channel) }(
 				broker, channel,
@@ -293,6 +346,23 @@ func (s *Server) channelReadMessageRoutine(
 	ctx context.Context,
 	broker, channel string,
 ) error {
+	policy, err := newDeliveryPolicy(channel)
+	if err != nil {
+		logger.Error("invalid delivery policy",
+			zap.String("channel", channel),
+			zap.Any("error", err))
+		return err
+	}
+
+	if concurrency := environment.GetConcurrency(channel); concurrency != nil {
+		return s.concurrentReadMessageRoutine(ctx, broker, channel, policy, concurrency)
+	}
+
+	if batch := environment.GetBatch(channel); batch != nil {
+		return s.batchReadMessageRoutine(ctx, broker, channel, policy, batch)
+	}
+
+	deadLetter := environment.GetDeadLetter(channel)
 	for {
 		select {
 		case <-ctx.Done():
@@ -300,78 +370,303 @@ func (s *Server) channelReadMessageRoutine(
 		default:
 			start := time.Now()
 
-			var err error
-			var brokerMsg []byte
+			var record models.BrokerRecord
 
-			brokerMsg, err = s.readWithRetry(ctx, broker, channel)
+			record, err = s.readWithRetry(ctx, broker, channel, policy)
 			if err != nil {
 				return err
 			}
 
-			logger.Debug("trying to send request to loadbalancer",
-				zap.String("channel", channel),
-				zap.Any("message", brokerMsg))
-
-			status, err := s.forwardToNode(ctx, channel, brokerMsg) // change to sendo to client
-			if err != nil || status != http.StatusOK {
+			err = s.handleMessage(ctx, channel, policy, deadLetter, record)
+			if err != nil {
 				return err
 			}
 
-			s.brokerHandlers[broker].Reader().Commit(ctx, channel)
-			elapsed := time.Since(start)
-			s.GetChannelMetric(channel).readMessageDuration.Observe(elapsed.Seconds())
-			s.GetChannelMetric(channel).messagesRead.Add(1)
+			s.commitMessage(ctx, broker, channel, start)
 		}
 	}
 }
 
+// handleMessage delivers a message read from a channel to the node, sending
+// it to the dead-letter channel when it can't be delivered, if there is one
+func (s *Server) handleMessage(
+	ctx context.Context,
+	channel string,
+	policy deliveryPolicy,
+	deadLetter *meta.DeadLetter,
+	record models.BrokerRecord,
+) (err error) {
+	logger.Debug("trying to send request to loadbalancer",
+		zap.String("channel", channel),
+		zap.Any("message", record.Value))
+
+	ctx, span := startMessageSpan(ctx, "deliver", trace.SpanKindConsumer, channel, &record.Envelope)
+	defer func() { tracing.End(span, err) }()
+
+	if deadLetter != nil {
+		return s.deliverOrDeadLetter(ctx, channel, policy, deadLetter, record)
+	}
+	return s.forwardToNode(ctx, channel, policy, record)
+}
+
+// commitMessage commits a message handled by the node, which was read at start
+func (s *Server) commitMessage(ctx context.Context, broker, channel string, start time.Time) {
+	s.brokerHandlers[broker].Reader().Commit(ctx, channel)
+	elapsed := time.Since(start)
+	s.GetChannelMetric(channel).readMessageDuration.Observe(elapsed.Seconds())
+	s.GetChannelMetric(channel).messagesRead.Add(1)
+}
+
+// readWithRetry reads a message of the channel from its broker, trying again
+// with the backoff of the delivery policy until its attempts run out
 func (s *Server) readWithRetry(
 	ctx context.Context,
 	broker, channel string,
-) (brokerMsg []byte, err error) {
-	for i := 0; ; i++ {
-		brokerMsg, err = s.brokerHandlers[broker].Reader().ReadMessage(ctx, channel)
-		if err != nil {
-			if i == maxBrokerRetries {
-				return
-			}
-			continue
+	policy deliveryPolicy,
+) (record models.BrokerRecord, err error) {
+	for attempt := 1; ; attempt++ {
+		record, err = s.brokerHandlers[broker].Reader().ReadMessage(ctx, channel)
+		if err == nil {
+			return
 		}
-		return
+		if ctx.Err() != nil {
+			// the read was given up on, it didn't fail
+			return models.BrokerRecord{}, ctx.Err()
+		}
+
+		s.GetChannelMetric(channel).messageReadError.Inc()
+		if attempt >= policy.readAttempts {
+			return
+		}
+
+		logger.Error("unable to read message from broker",
+			zap.String("channel", channel),
+			zap.Int("attempt", attempt),
+			zap.Any("error", err))
+
+		if err = policy.wait(ctx, attempt); err != nil {
+			return
+		}
+		s.GetChannelMetric(channel).readRetries.Inc()
 	}
 }
 
 func (s *Server) forwardToNode(
 	ctx context.Context,
 	channel string,
-	data []byte,
-) (status int, err error) {
-	var resp *http.Response
-
-	logger.Debug("decoding message from Avro schema")
+	policy deliveryPolicy,
+	record models.BrokerRecord,
+) error {
+	logger.Debug("decoding message")
 
-	var decodedMsg []byte
-	decodedMsg, err = decodeFromAvro(channel, bytes.NewReader(data))
+	decodedMsg, err := s.decodeMessage(channel, record)
 	if err != nil {
-		logger.Error("unable to decode message from Avro schema",
+		logger.Error("unable to decode message",
+			zap.String("channel", channel),
+			zap.Any("error", err))
+		return err
+	}
+
+	_, err = s.deliver(ctx, channel, policy, decodedMsg)
+	return err
+}
+
+// deliver sends a decoded message to the node, trying again with the backoff
+// of the delivery policy until it's delivered or the attempts run out. It
+// returns how many attempts were made and the error of the last one
+func (s *Server) deliver(
+	ctx context.Context,
+	channel string,
+	policy deliveryPolicy,
+	decodedMsg models.BrokerMessage,
+) (attempts int, err error) {
+	if decodedMsg.Envelope == nil {
+		decodedMsg.Envelope = &models.Envelope{}
+	}
+	return s.deliverTo(ctx, channel, false, policy, []models.BrokerMessage{decodedMsg})
+}
+
+// deliverTo sends messages of a channel to the node, as a batch when batch
+// is set, with the retries of the delivery policy. The envelopes of the
+// messages are set to the attempt of each delivery
+func (s *Server) deliverTo(
+	ctx context.Context,
+	channel string,
+	batch bool,
+	policy deliveryPolicy,
+	msgs []models.BrokerMessage,
+) (attempts int, err error) {
+	for attempts < policy.deliveryAttempts {
+		if attempts > 0 {
+			if err := policy.wait(ctx, attempts); err != nil {
+				return attempts, err
+			}
+			s.GetChannelMetric(channel).deliveryRetries.Inc()
+		}
+		attempts++
+
+		for _, msg := range msgs {
+			msg.Envelope.Attempt = attempts
+		}
+
+		err = s.sendToNode(ctx, channel, batch, policy.timeout, msgs)
+		if err == nil {
+			return attempts, nil
+		}
+
+		logger.Error("unable to deliver message to node",
 			zap.String("channel", channel),
+			zap.Int("attempt", attempts),
 			zap.Any("error", err))
-		return
+	}
+	return attempts, err
+}
+
+// sendToNode sends messages of a channel to the node, as a batch when batch
+// is set, failing when they aren't handled within the timeout, if there is one
+func (s *Server) sendToNode(
+	ctx context.Context,
+	channel string,
+	batch bool,
+	timeout time.Duration,
+	msgs []models.BrokerMessage,
+) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
 
 	logger.Info("sending message to node through: ",
 		zap.String("channel", channel), zap.String("node address", s.clientAddr))
 
-	requestAddress := fmt.Sprintf("%v/channel/%v", s.clientAddr, channel)
+	if s.node != nil {
+		err := s.node.Send(ctx, channel, batch, msgs)
+		if err != nil {
+			logger.Error("unable to send message from lbsidecar to node",
+				zap.Any("error", err))
+		}
+		return err
+	}
 
-	resp, err = sendRequest(requestAddress, decodedMsg)
+	endpoint := "channel"
+	var body []byte
+	var err error
+	if batch {
+		endpoint = "batch/channel"
+		body, err = json.Marshal(models.BrokerBatch{Messages: msgs})
+	} else {
+		body, err = json.Marshal(msgs[0])
+	}
+	if err != nil {
+		return ierrors.New(err).BadRequest()
+	}
+	requestAddress := fmt.Sprintf("%v/%v/%v", s.clientAddr, endpoint, channel)
+
+	resp, err := sendRequest(ctx, s.httpClient(), requestAddress, body)
 	if err != nil {
 		logger.Error("unable to send request from lbsidecar to node",
 			zap.Any("error", err))
-		return
+		return err
 	}
 	defer resp.Body.Close()
 
-	status = resp.StatusCode
-	return
+	if resp.StatusCode != http.StatusOK {
+		return ierrors.New("node responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// deliverOrDeadLetter forwards a message read from a channel to the node,
+// trying again until it's delivered or the attempts of the delivery policy
+// run out. Messages that can't be delivered are written to the dead-letter
+// channel with the reason of the failure, so that the channel moves on
+func (s *Server) deliverOrDeadLetter(
+	ctx context.Context,
+	channel string,
+	policy deliveryPolicy,
+	deadLetter *meta.DeadLetter,
+	record models.BrokerRecord,
+) error {
+	decodedMsg, err := s.decodeMessage(channel, record)
+	if err != nil {
+		// retrying won't make the message decodable
+		logger.Error("unable to decode message, sending it to the dead-letter channel",
+			zap.String("channel", channel),
+			zap.Any("error", err))
+		return s.writeDeadLetter(channel, deadLetter, record, 1, err)
+	}
+
+	attempts, err := s.deliver(ctx, channel, policy, decodedMsg)
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return s.writeDeadLetter(channel, deadLetter, record, attempts, err)
+}
+
+// writeDeadLetter writes a message that couldn't be delivered to the
+// dead-letter channel of the channel it was read from. The dead-letter keeps
+// the headers, key and trace context of the message
+func (s *Server) writeDeadLetter(
+	channel string,
+	deadLetter *meta.DeadLetter,
+	record models.BrokerRecord,
+	attempts int,
+	cause error,
+) error {
+	resolvedCh, _ := getResolvedChannel(channel)
+	message := meta.DeadLetterMessage{
+		Channel:         channel,
+		ResolvedChannel: resolvedCh,
+		Node:            os.Getenv("INSPR_APP_ID"),
+		Error:           cause.Error(),
+		Attempts:        attempts,
+		FailedAt:        time.Now().UTC(),
+		Message:         record.Value,
+	}
+
+	resolvedDLQ, err := getResolvedChannel(deadLetter.Channel)
+	if err != nil {
+		return err
+	}
+
+	encodedMsg, err := s.codecs.encode(resolvedDLQ, message)
+	if err != nil {
+		logger.Error("unable to encode dead-letter message",
+			zap.String("channel", channel),
+			zap.Any("error", err))
+		return err
+	}
+
+	broker, err := environment.GetChannelBroker(deadLetter.Channel)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("writing message to dead-letter channel",
+		zap.String("channel", channel),
+		zap.String("dead-letter", deadLetter.Channel),
+		zap.Int("attempts", attempts))
+
+	handler, ok := s.brokerHandlers[broker]
+	if !ok {
+		return ierrors.New("broker '%s' of the dead-letter channel isn't installed", broker).NotFound()
+	}
+
+	envelope, _ := newEnvelope(&models.Envelope{
+		Headers: record.Envelope.Headers,
+		Key:     record.Envelope.Key,
+		Trace:   record.Envelope.Trace,
+	})
+	deadLetterRecord := models.BrokerRecord{Value: encodedMsg, Envelope: envelope}
+	if err = handler.Writer().WriteMessage(deadLetter.Channel, deadLetterRecord); err != nil {
+		return ierrors.New("broker's WriteMessage failed, %s", err.Error())
+	}
+
+	s.GetChannelMetric(channel).messagesDeadLettered.Inc()
+	return nil
 }
@@ -2,6 +2,7 @@ package lbsidecar
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,9 +14,13 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 
+	"go.uber.org/zap"
 	"gopkg.in/confluentinc/confluent-kafka-go.v1/kafka"
+	"inspr.dev/inspr/pkg/environment"
+	"inspr.dev/inspr/pkg/meta"
 	"inspr.dev/inspr/pkg/rest"
 	"inspr.dev/inspr/pkg/sidecars/models"
 )
@@ -84,11 +89,29 @@ func createRouteMockedServer(port string, expectedMsg interface{}) *httptest.Ser
 }
 
 type mockWriter struct {
-	writeMessage func(channel string, message []byte) error
+	writeMessage  func(channel string, message []byte) error
+	writeMessages func(channel string, messages [][]byte) error
+	// records are the messages written, along with their envelopes
+	records []models.BrokerRecord
+	mutex   sync.Mutex
 }
 
-func (m *mockWriter) WriteMessage(channel string, message []byte) error {
-	return m.writeMessage(channel, message)
+func (m *mockWriter) WriteMessage(channel string, message models.BrokerRecord) error {
+	m.mutex.Lock()
+	m.records = append(m.records, message)
+	m.mutex.Unlock()
+	return m.writeMessage(channel, message.Value)
+}
+
+func (m *mockWriter) WriteMessages(channel string, messages []models.BrokerRecord) error {
+	m.mutex.Lock()
+	m.records = append(m.records, messages...)
+	m.mutex.Unlock()
+	values := make([][]byte, 0, len(messages))
+	for _, message := range messages {
+		values = append(values, message.Value)
+	}
+	return m.writeMessages(channel, values)
 }
 
 func (m *mockWriter) Close() {}
@@ -147,6 +170,7 @@ func TestServer_writeMessageHandler(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			var s *Server
 			if tt.writerFunc == nil {
 				tt.writerFunc = func(t *testing.T) models.Writer {
 					return &mockWriter{
@@ -164,7 +188,7 @@ func TestServer_writeMessageHandler(t *testing.T) {
 
 							resolvedCh, _ := getResolvedChannel(channel)
 
-							bMessage, err := readMessage(resolvedCh, message)
+							bMessage, err := s.codecs.readMessage(resolvedCh, message)
 							if err != nil {
 								t.Error(err)
 							}
@@ -183,7 +207,7 @@ func TestServer_writeMessageHandler(t *testing.T) {
 				}
 			}
 			bh := models.NewBrokerHandler("someBroker", nil, tt.writerFunc(t))
-			s := Init(bh)
+			s = Init(bh)
 			server := httptest.NewServer(s.writeMessageHandler())
 			defer server.Close()
 			client := &http.Client{}
@@ -409,3 +433,240 @@ func createMockedRoute(port string, expectedMsg interface{}) *httptest.Server {
 	return mockServer
 
 }
+
+const benchmarkSchema = `{
+	"type": "record",
+	"name": "Order",
+	"fields": [
+		{"name": "id", "type": "string"},
+		{"name": "customer", "type": {"type": "record", "name": "Customer", "fields": [
+			{"name": "name", "type": "string"},
+			{"name": "email", "type": ["null", "string"], "default": null}
+		]}},
+		{"name": "items", "type": {"type": "array", "items": {"type": "record", "name": "Item", "fields": [
+			{"name": "sku", "type": "string"},
+			{"name": "quantity", "type": "int"},
+			{"name": "price", "type": "double"}
+		]}}},
+		{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["NEW", "PAID", "SHIPPED"]}}
+	]
+}`
+
+var benchmarkMessage = map[string]interface{}{
+	"id":       "order-1",
+	"customer": map[string]interface{}{"name": "john", "email": map[string]interface{}{"string": "john@inspr.dev"}},
+	"items": []interface{}{
+		map[string]interface{}{"sku": "sku-1", "quantity": 2, "price": 9.9},
+		map[string]interface{}{"sku": "sku-2", "quantity": 1, "price": 19.9},
+	},
+	"status": "PAID",
+}
+
+// benchServer is shared by the benchmarks, since the metrics of
+// its channels can only be registered once
+var benchServer *Server
+
+// benchmarkServer returns a server for the mock channels, whose type is
+// the benchmark schema, writing to a broker that discards the messages
+func benchmarkServer(b *testing.B) *Server {
+	createMockEnvVars()
+	os.Setenv("someTopic_SCHEMA", benchmarkSchema)
+	b.Cleanup(deleteMockEnvVars)
+
+	level := alevel.Level()
+	alevel.SetLevel(zap.ErrorLevel)
+	b.Cleanup(func() { alevel.SetLevel(level) })
+
+	if benchServer == nil {
+		writer := &mockWriter{writeMessage: func(channel string, message []byte) error { return nil }}
+		benchServer = Init(models.NewBrokerHandler("someBroker", nil, writer))
+	}
+	benchServer.codecs = newCodecCache()
+	benchServer.codecs.load(resolvedChannels()...)
+	return benchServer
+}
+
+func benchmarkWriteMessageHandler(b *testing.B, cached bool) {
+	s := benchmarkServer(b)
+	handler := s.writeMessageHandler()
+	body, _ := json.Marshal(models.BrokerMessage{Data: benchmarkMessage})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !cached {
+			s.codecs = newCodecCache()
+		}
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodPost, "/channel/chan", bytes.NewReader(body)))
+		if w.Code != http.StatusOK {
+			b.Fatalf("writeMessageHandler() status = %v", w.Code)
+		}
+	}
+}
+
+// BenchmarkServer_writeMessageHandler_uncached measures writing messages as
+// it was done before the codecs were cached, compiling the schema every time
+func BenchmarkServer_writeMessageHandler_uncached(b *testing.B) {
+	benchmarkWriteMessageHandler(b, false)
+}
+
+// BenchmarkServer_writeMessageHandler_cached measures writing messages with
+// the codec of the channel compiled when the server starts
+func BenchmarkServer_writeMessageHandler_cached(b *testing.B) {
+	benchmarkWriteMessageHandler(b, true)
+}
+
+func benchmarkDecodeMessage(b *testing.B, cached bool) {
+	s := benchmarkServer(b)
+	encoded, err := s.codecs.encode("someTopic", benchmarkMessage)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !cached {
+			s.codecs = newCodecCache()
+		}
+		if _, err := s.decodeMessage("chan", models.BrokerRecord{Value: encoded}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkServer_decodeMessage_uncached measures decoding the messages read
+// from the broker, before they are forwarded to the node, compiling the
+// schema every time as it was done before the codecs were cached
+func BenchmarkServer_decodeMessage_uncached(b *testing.B) {
+	benchmarkDecodeMessage(b, false)
+}
+
+// BenchmarkServer_decodeMessage_cached measures decoding the messages read
+// from the broker with the codec of the channel compiled when the server starts
+func BenchmarkServer_decodeMessage_cached(b *testing.B) {
+	benchmarkDecodeMessage(b, true)
+}
+
+func TestServer_deliverOrDeadLetter(t *testing.T) {
+	createMockEnvVars()
+	defer deleteMockEnvVars()
+	env := map[string]string{
+		"INSPR_INPUT_CHANNELS":        "orders@someBroker",
+		"INSPR_OUTPUT_CHANNELS":       "orders.deadletter@someBroker",
+		"orders_RESOLVED":             "ordersTopic",
+		"ordersTopic_SCHEMA":          `{"type":"string"}`,
+		"orders.deadletter_RESOLVED":  "failuresTopic",
+		"failuresTopic_SCHEMA":        `{"type":"object","required":["channel","error","attempts","message"]}`,
+		"failuresTopic_SCHEMA_FORMAT": "jsonschema",
+		"orders_DEADLETTER":           "orders.deadletter",
+		"orders_DEADLETTER_ATTEMPTS":  "2",
+	}
+	for key, value := range env {
+		os.Setenv(key, value)
+	}
+	defer func() {
+		for key := range env {
+			os.Unsetenv(key)
+		}
+	}()
+
+	var status, requests int
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(status)
+	}))
+	defer node.Close()
+
+	var written []byte
+	var writeErr error
+	writer := &mockWriter{writeMessage: func(channel string, message []byte) error {
+		if channel != "orders.deadletter" {
+			t.Errorf("WriteMessage() channel = %v, want orders.deadletter", channel)
+		}
+		written = message
+		return writeErr
+	}}
+	s := Init(models.NewBrokerHandler("someBroker", nil, writer))
+	s.clientAddr = node.URL
+
+	valid, _ := s.codecs.encode("ordersTopic", "order-1")
+
+	tests := []struct {
+		name         string
+		status       int
+		message      []byte
+		writeErr     error
+		wantRequests int
+		wantAttempts int
+		wantErr      bool
+	}{
+		{
+			name:         "delivered message",
+			status:       http.StatusOK,
+			message:      valid,
+			wantRequests: 1,
+		},
+		{
+			name:         "message dead-lettered after the attempts",
+			status:       http.StatusInternalServerError,
+			message:      valid,
+			wantRequests: 2,
+			wantAttempts: 2,
+		},
+		{
+			name:         "undecodable message is dead-lettered right away",
+			status:       http.StatusOK,
+			message:      []byte{0xFF},
+			wantRequests: 0,
+			wantAttempts: 1,
+		},
+		{
+			name:         "dead-letter write fails",
+			status:       http.StatusInternalServerError,
+			message:      valid,
+			writeErr:     errors.New("broker unavailable"),
+			wantRequests: 2,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, requests, written, writeErr = tt.status, 0, nil, tt.writeErr
+
+			policy, _ := newDeliveryPolicy("orders")
+			err := s.deliverOrDeadLetter(context.Background(), "orders", policy, environment.GetDeadLetter("orders"), models.BrokerRecord{Value: tt.message})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("deliverOrDeadLetter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("deliverOrDeadLetter() sent %v requests, want %v", requests, tt.wantRequests)
+			}
+			if tt.wantAttempts == 0 {
+				return
+			}
+
+			decoded, err := s.codecs.decode("failuresTopic", written)
+			if err != nil {
+				t.Fatalf("decode() of the dead-letter message error = %v", err)
+			}
+			raw, _ := json.Marshal(decoded)
+			var got meta.DeadLetterMessage
+			json.Unmarshal(raw, &got)
+
+			if got.Channel != "orders" || got.ResolvedChannel != "ordersTopic" ||
+				got.Attempts != tt.wantAttempts || got.Error == "" ||
+				!bytes.Equal(got.Message, tt.message) {
+				t.Errorf("deliverOrDeadLetter() wrote %+v", got)
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	body, _ := json.Marshal(models.BrokerMessage{Data: map[string]interface{}{}})
+	s.writeMessageHandler()(w, httptest.NewRequest(http.MethodPost, "/channel/orders.deadletter", bytes.NewReader(body)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("writeMessageHandler() to the dead-letter channel status = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+}
@@ -11,9 +11,13 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 	"inspr.dev/inspr/pkg/environment"
+	"inspr.dev/inspr/pkg/meta"
 	"inspr.dev/inspr/pkg/rest"
 	"inspr.dev/inspr/pkg/sidecars/models"
+	"inspr.dev/inspr/pkg/sidecars/transport"
+	"inspr.dev/inspr/pkg/utils"
 )
 
 type channelMetric struct {
@@ -21,6 +25,10 @@ type channelMetric struct {
 	messageSendError     prometheus.Counter
 	messageReadError     prometheus.Counter
 	messagesSent         prometheus.Counter
+	messagesDeadLettered prometheus.Counter
+	readRetries          prometheus.Counter
+	deliveryRetries      prometheus.Counter
+	messagesInFlight     prometheus.Gauge
 	readMessageDuration  prometheus.Summary
 	writeMessageDuration prometheus.Summary
 }
@@ -41,6 +49,19 @@ type Server struct {
 	clientAddr     string
 	channelMetric  map[string]channelMetric
 	routeMetric    map[string]routeMetric
+	codecs         *codecCache
+	// node and nodeRoutes reach the node through gRPC, when it's the
+	// transport set for them, and are nil otherwise. The Sidecar service is
+	// then served on grpcAddr
+	node       *transport.Streamer
+	nodeRoutes transport.NodeClient
+	grpcAddr   string
+	// writeSocket and nodeClient are set when the node and the sidecar
+	// communicate through the Unix sockets of the volume they share. The
+	// write server is served on writeSocket instead of writeAddr and the
+	// node is reached through nodeClient
+	writeSocket string
+	nodeClient  *http.Client
 }
 
 func (s *Server) GetChannelMetric(channel string) channelMetric {
@@ -93,6 +114,46 @@ func (s *Server) GetChannelMetric(channel string) channelMetric {
 				"broker":                 broker,
 			},
 		}),
+		messagesDeadLettered: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: "inspr",
+			Subsystem: "lbsidecar",
+			Name:      "message_dead_lettered",
+			ConstLabels: prometheus.Labels{
+				"inspr_channel":          channel,
+				"inspr_resolved_channel": resolved,
+				"broker":                 broker,
+			},
+		}),
+		readRetries: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: "inspr",
+			Subsystem: "lbsidecar",
+			Name:      "message_read_retries",
+			ConstLabels: prometheus.Labels{
+				"inspr_channel":          channel,
+				"inspr_resolved_channel": resolved,
+				"broker":                 broker,
+			},
+		}),
+		deliveryRetries: promauto.NewCounter(prometheus.CounterOpts{
+			Namespace: "inspr",
+			Subsystem: "lbsidecar",
+			Name:      "message_delivery_retries",
+			ConstLabels: prometheus.Labels{
+				"inspr_channel":          channel,
+				"inspr_resolved_channel": resolved,
+				"broker":                 broker,
+			},
+		}),
+		messagesInFlight: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: "inspr",
+			Subsystem: "lbsidecar",
+			Name:      "messages_in_flight",
+			ConstLabels: prometheus.Labels{
+				"inspr_channel":          channel,
+				"inspr_resolved_channel": resolved,
+				"broker":                 broker,
+			},
+		}),
 
 		readMessageDuration: promauto.NewSummary(prometheus.SummaryOpts{
 			Namespace: "inspr",
@@ -214,9 +275,43 @@ func Init(handlers ...*models.BrokerHandler) *Server {
 		s.brokerHandlers[handler.Broker] = handler
 	}
 
+	s.codecs = newCodecCache()
+	s.codecs.load(resolvedChannels()...)
+
+	socketDir := environment.GetUnixSocketAddr()
+	if socketDir != "" {
+		nodeSocket := transport.NodeSocket(socketDir)
+		s.writeSocket = transport.SidecarSocket(socketDir)
+		s.nodeClient = transport.UnixClient(nodeSocket)
+		logger = logger.With(zap.String("write-socket", s.writeSocket), zap.String("node-socket", nodeSocket))
+	}
+
+	if transport.FromEnv() == meta.TransportGRPC {
+		s.grpcAddr = transport.SidecarAddr(socketDir)
+		conn, err := transport.Dial(transport.NodeAddr(socketDir))
+		if err != nil {
+			panic(fmt.Sprintf("unable to connect to the node through gRPC: %v", err))
+		}
+		s.node = transport.NewNodeStreamer(conn)
+		s.nodeRoutes = transport.NewNodeClient(conn)
+	}
+	logger.Info("using node transport", zap.String("transport", transport.FromEnv()))
+
 	return &s
 }
 
+// resolvedChannels returns the resolved names of the input and output channels
+func resolvedChannels() []string {
+	channels := append(environment.GetInputChannelsData(), environment.GetOutputChannelsData()...)
+	resolved := utils.StringArray{}
+	for _, ch := range environment.GetResolvedBoundaryChannelList(channels) {
+		if ch != "" && !resolved.Contains(ch) {
+			resolved = append(resolved, ch)
+		}
+	}
+	return resolved
+}
+
 // Run starts the server on the port given in addr
 func (s *Server) Run(ctx context.Context) error {
 
@@ -242,6 +337,7 @@ func (s *Server) Run(ctx context.Context) error {
 	muxWriter := http.NewServeMux()
 
 	muxWriter.Handle("/channel/", s.writeMessageHandler().Post().JSON())
+	muxWriter.Handle("/batch/channel/", s.writeMessagesHandler().Post().JSON())
 	muxWriter.Handle("/route/", s.sendRouteRequest().JSON())
 
 	writeServer := &http.Server{
@@ -249,7 +345,7 @@ func (s *Server) Run(ctx context.Context) error {
 		Addr:    s.writeAddr,
 	}
 	go func() {
-		if err := writeServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := s.serveWriter(writeServer); err != nil && err != http.ErrServerClosed {
 			errCh <- err
 			logger.Error("an error occurred in LB Sidecar write server",
 				zap.Error(err))
@@ -272,11 +368,29 @@ func (s *Server) Run(ctx context.Context) error {
 		}
 	}()
 
+	// nodes that communicate through gRPC reach its services on a listener
+	// of their own
+	if s.grpcAddr != "" {
+		grpcServer := s.grpcServer()
+		defer grpcServer.Stop()
+		go func() {
+			if err := s.serveGRPC(grpcServer); err != nil {
+				errCh <- err
+				logger.Error("an error occurred in LB Sidecar gRPC server",
+					zap.Error(err))
+			}
+		}()
+	}
+
 	// create read message routine and captures its error
 	go func() { errCh <- s.readMessageRoutine(ctx) }()
 
 	logger.Info("LB Sidecar listener is up...")
 
+	if s.node != nil {
+		defer s.node.Close()
+	}
+
 	select {
 	case <-ctx.Done():
 		gracefulShutdown(writeServer, readServer, adminServer, nil)
@@ -287,6 +401,37 @@ func (s *Server) Run(ctx context.Context) error {
 	}
 }
 
+// serveWriter serves the write server on the Unix socket shared with the
+// node, when there's one, or on its write address otherwise
+func (s *Server) serveWriter(server *http.Server) error {
+	if s.writeSocket == "" {
+		return server.ListenAndServe()
+	}
+
+	listener, err := transport.ListenUnix(s.writeSocket)
+	if err != nil {
+		return err
+	}
+	return server.Serve(listener)
+}
+
+// serveGRPC serves the Sidecar service on its own address
+func (s *Server) serveGRPC(server *grpc.Server) error {
+	listener, err := transport.Listen(s.grpcAddr)
+	if err != nil {
+		return err
+	}
+	return server.Serve(listener)
+}
+
+// httpClient returns the HTTP client through which the node is reached
+func (s *Server) httpClient() *http.Client {
+	if s.nodeClient != nil {
+		return s.nodeClient
+	}
+	return http.DefaultClient
+}
+
 func gracefulShutdown(w, r, a *http.Server, err error) {
 	logger.Info("gracefully shutting down...")
 
@@ -2,13 +2,18 @@ package lbsidecar
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"inspr.dev/inspr/pkg/rest"
 	"inspr.dev/inspr/pkg/sidecars/models"
+	"inspr.dev/inspr/pkg/sidecars/transport"
 )
 
 // The environment variables used here are declared in handlers_test.go
@@ -35,7 +40,13 @@ func TestInit(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := Init(); !reflect.DeepEqual(got, tt.want) {
+			got := Init()
+			if _, ok := got.codecs.channels["someTopic"]; !ok {
+				t.Errorf("Init() didn't compile the codecs of the channels")
+			}
+
+			got.codecs = nil
+			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Init() = %v, want %v", got, tt.want)
 			}
 		})
@@ -122,3 +133,69 @@ func TestServer_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_unixSocket(t *testing.T) {
+	createMockEnvVars()
+	defer deleteMockEnvVars()
+	dir := t.TempDir()
+	os.Setenv("INSPR_UNIX_SOCKET", dir)
+	defer os.Unsetenv("INSPR_UNIX_SOCKET")
+
+	var delivered []models.BrokerMessage
+	node := http.NewServeMux()
+	node.HandleFunc("/channel/sockets", func(w http.ResponseWriter, r *http.Request) {
+		var msg models.BrokerMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+		delivered = append(delivered, msg)
+		rest.JSON(w, http.StatusOK, nil)
+	})
+	listener, err := transport.ListenUnix(transport.NodeSocket(dir))
+	if err != nil {
+		t.Fatalf("ListenUnix() error = %v", err)
+	}
+	nodeServer := &http.Server{Handler: node}
+	go nodeServer.Serve(listener)
+	defer nodeServer.Close()
+
+	s := Init()
+
+	t.Run("write server served on the socket", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/channel/sockets", func(w http.ResponseWriter, r *http.Request) {
+			rest.JSON(w, http.StatusOK, nil)
+		})
+		writeServer := &http.Server{Handler: mux}
+		go s.serveWriter(writeServer)
+		defer writeServer.Close()
+
+		client := transport.UnixClient(transport.SidecarSocket(dir))
+		var resp *http.Response
+		// waits for the write server to listen on the socket
+		for i := 0; i < 50; i++ {
+			if resp, err = client.Post("http://unix/channel/sockets", "application/json", nil); err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if err != nil {
+			t.Fatalf("serveWriter() error = %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("serveWriter() status = %v, want %v", resp.StatusCode, http.StatusOK)
+		}
+	})
+
+	t.Run("message delivered through the socket", func(t *testing.T) {
+		attempts, err := s.deliver(context.Background(), "sockets", deliveryPolicy{
+			deliveryAttempts: 1,
+			timeout:          time.Second,
+		}, models.BrokerMessage{Data: "hello"})
+		if err != nil || attempts != 1 {
+			t.Errorf("deliver() = %v, %v, want 1 attempt", attempts, err)
+		}
+		if len(delivered) != 1 || delivered[0].Data != "hello" || delivered[0].Envelope.Attempt != 1 {
+			t.Errorf("deliver() delivered %v", delivered)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package lbsidecar
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+	"inspr.dev/inspr/pkg/sidecars/models"
+	"inspr.dev/inspr/pkg/tracing"
+)
+
+// startMessageSpan starts the span of the sidecar handling messages of a
+// channel, continuing the trace of the first message and linking the traces of
+// the others, when they come from different spans. The messages are then set
+// to carry the trace on from the new span
+func startMessageSpan(
+	ctx context.Context,
+	name string,
+	kind trace.SpanKind,
+	channel string,
+	envelopes ...*models.Envelope,
+) (context.Context, trace.Span) {
+	parent := tracing.SpanContext(envelopes[0].Trace)
+
+	var links []trace.Link
+	for _, envelope := range envelopes[1:] {
+		link := tracing.SpanContext(envelope.Trace)
+		if link.IsValid() && !link.Equal(parent) {
+			links = append(links, trace.Link{SpanContext: link})
+		}
+	}
+
+	ctx, span := tracing.Tracer().Start(
+		tracing.Extract(ctx, envelopes[0].Trace),
+		name+" "+channel,
+		trace.WithSpanKind(kind),
+		trace.WithLinks(links...),
+		trace.WithAttributes(semconv.MessagingDestinationKey.String(channel)),
+	)
+
+	fields := tracing.Inject(ctx)
+	for _, envelope := range envelopes {
+		envelope.Trace = fields
+	}
+	return ctx, span
+}
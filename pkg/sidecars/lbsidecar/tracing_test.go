@@ -0,0 +1,116 @@
+package lbsidecar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"inspr.dev/inspr/pkg/sidecars/models"
+	"inspr.dev/inspr/pkg/tracing"
+)
+
+// newTrace returns the trace context of a new span, as the node writes it
+func newTrace() (map[string]string, trace.SpanContext) {
+	ctx, span := tracing.Tracer().Start(context.Background(), "send")
+	defer span.End()
+	return tracing.Inject(ctx), span.SpanContext()
+}
+
+func Test_startMessageSpan(t *testing.T) {
+	first, firstSpan := newTrace()
+	second, secondSpan := newTrace()
+
+	tests := []struct {
+		name       string
+		envelopes  []*models.Envelope
+		wantParent trace.SpanContext
+		wantLinks  []trace.SpanContext
+	}{
+		{
+			name:      "message without trace",
+			envelopes: []*models.Envelope{{}},
+		},
+		{
+			name:       "message with trace",
+			envelopes:  []*models.Envelope{{Trace: first}},
+			wantParent: firstSpan,
+		},
+		{
+			name: "batch with different traces",
+			envelopes: []*models.Envelope{
+				{Trace: first},
+				{Trace: second},
+				{Trace: first},
+				{},
+			},
+			wantParent: firstSpan,
+			wantLinks:  []trace.SpanContext{secondSpan},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, span := startMessageSpan(context.Background(), "deliver", trace.SpanKindConsumer, "chan", tt.envelopes...)
+			span.End()
+
+			got := span.(sdktrace.ReadOnlySpan)
+			if got.Name() != "deliver chan" || got.SpanKind() != trace.SpanKindConsumer {
+				t.Errorf("startMessageSpan() = %v %v", got.Name(), got.SpanKind())
+			}
+			if got.Parent().SpanID() != tt.wantParent.SpanID() {
+				t.Errorf("startMessageSpan() parent = %v, want %v", got.Parent().SpanID(), tt.wantParent.SpanID())
+			}
+			if len(got.Links()) != len(tt.wantLinks) {
+				t.Fatalf("startMessageSpan() links = %v, want %v", got.Links(), tt.wantLinks)
+			}
+			for i, link := range got.Links() {
+				if link.SpanContext.SpanID() != tt.wantLinks[i].SpanID() {
+					t.Errorf("startMessageSpan() link %d = %v, want %v", i, link.SpanContext, tt.wantLinks[i])
+				}
+			}
+
+			// the messages carry the trace on from the new span
+			for _, envelope := range tt.envelopes {
+				if tracing.SpanContext(envelope.Trace).SpanID() != trace.SpanContextFromContext(ctx).SpanID() {
+					t.Errorf("startMessageSpan() envelope trace = %v, want span %v", envelope.Trace, span.SpanContext())
+				}
+			}
+		})
+	}
+}
+
+func TestServer_writeMessageHandler_trace(t *testing.T) {
+	createMockEnvVars()
+	defer deleteMockEnvVars()
+	os.Setenv("INSPR_OUTPUT_CHANNELS", "traces@someBroker")
+	os.Setenv("traces_RESOLVED", "someTopic")
+	defer os.Unsetenv("traces_RESOLVED")
+
+	writer := &mockWriter{
+		writeMessage: func(channel string, message []byte) error { return nil },
+	}
+	s := Init(models.NewBrokerHandler("someBroker", nil, writer))
+
+	fields, nodeSpan := newTrace()
+	body, _ := json.Marshal(models.BrokerMessage{
+		Data:     "hello",
+		Envelope: &models.Envelope{Trace: fields},
+	})
+	w := httptest.NewRecorder()
+	s.writeMessageHandler()(w, httptest.NewRequest(http.MethodPost, "/channel/traces", bytes.NewReader(body)))
+	if w.Code != http.StatusOK || len(writer.records) != 1 {
+		t.Fatalf("writeMessageHandler() status = %v, records = %v", w.Code, writer.records)
+	}
+
+	// the message is stored with the trace of the sidecar's span, which
+	// continues the trace of the node
+	got := tracing.SpanContext(writer.records[0].Envelope.Trace)
+	if got.TraceID() != nodeSpan.TraceID() || got.SpanID() == nodeSpan.SpanID() {
+		t.Errorf("writeMessageHandler() wrote trace %v, want a span of trace %v", got, nodeSpan.TraceID())
+	}
+}
@@ -4,16 +4,21 @@ import (
 	"context"
 )
 
-// Reader reads from a message broker
+// Reader reads from a message broker. The messages are read with the
+// envelopes they were written with
 type Reader interface {
-	ReadMessage(ctx context.Context, channel string) ([]byte, error)
+	ReadMessage(ctx context.Context, channel string) (BrokerRecord, error)
 	Commit(ctx context.Context, channel string) error
 	Close() error
 }
 
-// Writer writes messages in a message broker
+// Writer writes messages in a message broker. WriteMessages writes
+// a batch of messages to a channel in a single call to the broker, and only
+// returns once all of them are written, with an error if any of them wasn't.
+// The envelope of each message is stored in the native headers of the broker
 type Writer interface {
-	WriteMessage(channel string, msg []byte) error
+	WriteMessage(channel string, msg BrokerRecord) error
+	WriteMessages(channel string, msgs []BrokerRecord) error
 	Close()
 }
 
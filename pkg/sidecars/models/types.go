@@ -1,8 +1,60 @@
 package models
 
-// BrokerMessage is the struct that represents the client's request format
+import "time"
+
+// BrokerMessage is the struct that represents the client's request format.
+// The envelope is optional on the messages written by the client, which can
+// only set its headers and key, and is always set on the ones delivered to it
 type BrokerMessage struct {
-	Data interface{} `json:"data"`
+	Data     interface{} `json:"data"`
+	Envelope *Envelope   `json:"envelope,omitempty"`
+}
+
+// ReservedHeaderPrefix is the prefix of the headers used by Inspr itself in
+// the brokers, which can't be set by the producers of messages
+const ReservedHeaderPrefix = "inspr-"
+
+// SourceAppHeader is the broker header of the ID of the dApp that produced a message
+const SourceAppHeader = ReservedHeaderPrefix + "source-app"
+
+// TraceHeaderPrefix is the prefix of the broker headers of the fields of the
+// trace context of a message
+const TraceHeaderPrefix = ReservedHeaderPrefix + "trace-"
+
+// Envelope is the metadata of a message, carried along with its data through
+// the broker, in the broker's native headers, and delivered with it to the node
+type Envelope struct {
+	// Headers are set by the producer of the message, such as a correlation
+	// ID or the content type of its data
+	Headers map[string]string `json:"headers,omitempty"`
+	// Key identifies what the message is about, such as the entity it changes
+	Key string `json:"key,omitempty"`
+	// ProducedAt is when the message was written to the broker
+	ProducedAt time.Time `json:"producedAt"`
+	// SourceApp is the ID of the dApp that produced the message
+	SourceApp string `json:"sourceApp,omitempty"`
+	// Offset is the position of the message in the broker, set on the
+	// messages read from it
+	Offset int64 `json:"offset"`
+	// Trace is the trace context of the message, which its consumers continue
+	// the trace of its producer from
+	Trace map[string]string `json:"trace,omitempty"`
+	// Attempt is the number of the current delivery of the message to the
+	// node, starting at 1. It isn't stored in the broker
+	Attempt int `json:"attempt,omitempty"`
+}
+
+// BrokerRecord is a message as it's written to and read from a broker, with
+// its data encoded by the codec of its channel
+type BrokerRecord struct {
+	Value    []byte
+	Envelope Envelope
+}
+
+// BrokerBatch is the format of batches of messages, both the ones written by
+// the client and the ones delivered to it, each in the format of a BrokerMessage
+type BrokerBatch struct {
+	Messages []BrokerMessage `json:"messages"`
 }
 
 // ConnectionVariables is the structure resposible for storing
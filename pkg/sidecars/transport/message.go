@@ -0,0 +1,102 @@
+package transport
+
+import (
+	"encoding/json"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/sidecars/models"
+)
+
+// NewMessages converts messages of a channel to the messages sent through
+// gRPC, with their data as protobuf values
+func NewMessages(msgs []models.BrokerMessage) ([]*Message, error) {
+	converted := make([]*Message, 0, len(msgs))
+	for _, msg := range msgs {
+		data, err := newValue(msg.Data)
+		if err != nil {
+			return nil, ierrors.New(err).BadRequest()
+		}
+		converted = append(converted, &Message{
+			Data:     data,
+			Envelope: newEnvelope(msg.Envelope),
+		})
+	}
+	return converted, nil
+}
+
+// BrokerMessages converts the messages received through gRPC back to
+// messages of a channel. Their data has the same types it would have if it
+// was decoded from JSON
+func BrokerMessages(msgs []*Message) []models.BrokerMessage {
+	converted := make([]models.BrokerMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		converted = append(converted, models.BrokerMessage{
+			Data:     msg.GetData().AsInterface(),
+			Envelope: msg.GetEnvelope().brokerEnvelope(),
+		})
+	}
+	return converted
+}
+
+// newValue converts the data of a message to a protobuf value. Data of the
+// types protobuf values can't hold, such as structs, is converted to the
+// value it has in JSON, which is how its fields are named by the client
+func newValue(data interface{}) (*structpb.Value, error) {
+	value, err := structpb.NewValue(data)
+	if err == nil {
+		return value, nil
+	}
+
+	buf, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(buf, &generic); err != nil {
+		return nil, err
+	}
+	return structpb.NewValue(generic)
+}
+
+// newEnvelope converts the envelope of a message, which is nil when the
+// message has none
+func newEnvelope(envelope *models.Envelope) *Envelope {
+	if envelope == nil {
+		return nil
+	}
+
+	converted := &Envelope{
+		Headers:   envelope.Headers,
+		Key:       envelope.Key,
+		SourceApp: envelope.SourceApp,
+		Offset:    envelope.Offset,
+		Trace:     envelope.Trace,
+		Attempt:   int32(envelope.Attempt),
+	}
+	if !envelope.ProducedAt.IsZero() {
+		converted.ProducedAt = timestamppb.New(envelope.ProducedAt)
+	}
+	return converted
+}
+
+// brokerEnvelope converts the envelope back, returning nil when there's none
+func (x *Envelope) brokerEnvelope() *models.Envelope {
+	if x == nil {
+		return nil
+	}
+
+	envelope := &models.Envelope{
+		Headers:   x.GetHeaders(),
+		Key:       x.GetKey(),
+		SourceApp: x.GetSourceApp(),
+		Offset:    x.GetOffset(),
+		Trace:     x.GetTrace(),
+		Attempt:   int(x.GetAttempt()),
+	}
+	if x.GetProducedAt() != nil {
+		envelope.ProducedAt = x.GetProducedAt().AsTime()
+	}
+	return envelope
+}
@@ -0,0 +1,341 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: sidecar.proto
+
+package transport
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// SidecarClient is the client API for Sidecar service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SidecarClient interface {
+	// WriteMessage writes the messages of the stream to their channels,
+	// acknowledging each of them once it's written
+	WriteMessage(ctx context.Context, opts ...grpc.CallOption) (Sidecar_WriteMessageClient, error)
+	// SendRequest sends a request to an endpoint of a route
+	SendRequest(ctx context.Context, in *RouteRequest, opts ...grpc.CallOption) (*RouteResponse, error)
+}
+
+type sidecarClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSidecarClient(cc grpc.ClientConnInterface) SidecarClient {
+	return &sidecarClient{cc}
+}
+
+func (c *sidecarClient) WriteMessage(ctx context.Context, opts ...grpc.CallOption) (Sidecar_WriteMessageClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Sidecar_ServiceDesc.Streams[0], "/inspr.sidecar.Sidecar/WriteMessage", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sidecarWriteMessageClient{stream}
+	return x, nil
+}
+
+type Sidecar_WriteMessageClient interface {
+	Send(*ChannelMessage) error
+	Recv() (*Ack, error)
+	grpc.ClientStream
+}
+
+type sidecarWriteMessageClient struct {
+	grpc.ClientStream
+}
+
+func (x *sidecarWriteMessageClient) Send(m *ChannelMessage) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *sidecarWriteMessageClient) Recv() (*Ack, error) {
+	m := new(Ack)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *sidecarClient) SendRequest(ctx context.Context, in *RouteRequest, opts ...grpc.CallOption) (*RouteResponse, error) {
+	out := new(RouteResponse)
+	err := c.cc.Invoke(ctx, "/inspr.sidecar.Sidecar/SendRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SidecarServer is the server API for Sidecar service.
+// All implementations must embed UnimplementedSidecarServer
+// for forward compatibility
+type SidecarServer interface {
+	// WriteMessage writes the messages of the stream to their channels,
+	// acknowledging each of them once it's written
+	WriteMessage(Sidecar_WriteMessageServer) error
+	// SendRequest sends a request to an endpoint of a route
+	SendRequest(context.Context, *RouteRequest) (*RouteResponse, error)
+	mustEmbedUnimplementedSidecarServer()
+}
+
+// UnimplementedSidecarServer must be embedded to have forward compatible implementations.
+type UnimplementedSidecarServer struct {
+}
+
+func (UnimplementedSidecarServer) WriteMessage(Sidecar_WriteMessageServer) error {
+	return status.Errorf(codes.Unimplemented, "method WriteMessage not implemented")
+}
+func (UnimplementedSidecarServer) SendRequest(context.Context, *RouteRequest) (*RouteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendRequest not implemented")
+}
+func (UnimplementedSidecarServer) mustEmbedUnimplementedSidecarServer() {}
+
+// UnsafeSidecarServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SidecarServer will
+// result in compilation errors.
+type UnsafeSidecarServer interface {
+	mustEmbedUnimplementedSidecarServer()
+}
+
+func RegisterSidecarServer(s grpc.ServiceRegistrar, srv SidecarServer) {
+	s.RegisterService(&Sidecar_ServiceDesc, srv)
+}
+
+func _Sidecar_WriteMessage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SidecarServer).WriteMessage(&sidecarWriteMessageServer{stream})
+}
+
+type Sidecar_WriteMessageServer interface {
+	Send(*Ack) error
+	Recv() (*ChannelMessage, error)
+	grpc.ServerStream
+}
+
+type sidecarWriteMessageServer struct {
+	grpc.ServerStream
+}
+
+func (x *sidecarWriteMessageServer) Send(m *Ack) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *sidecarWriteMessageServer) Recv() (*ChannelMessage, error) {
+	m := new(ChannelMessage)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Sidecar_SendRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RouteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SidecarServer).SendRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/inspr.sidecar.Sidecar/SendRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SidecarServer).SendRequest(ctx, req.(*RouteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Sidecar_ServiceDesc is the grpc.ServiceDesc for Sidecar service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Sidecar_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "inspr.sidecar.Sidecar",
+	HandlerType: (*SidecarServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SendRequest",
+			Handler:    _Sidecar_SendRequest_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WriteMessage",
+			Handler:       _Sidecar_WriteMessage_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "sidecar.proto",
+}
+
+// NodeClient is the client API for Node service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type NodeClient interface {
+	// HandleChannel delivers the messages of the stream to the handlers of
+	// their channels, which acknowledge each of them once it's handled
+	HandleChannel(ctx context.Context, opts ...grpc.CallOption) (Node_HandleChannelClient, error)
+	// HandleRoute delivers a request to the handler of a route
+	HandleRoute(ctx context.Context, in *RouteRequest, opts ...grpc.CallOption) (*RouteResponse, error)
+}
+
+type nodeClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewNodeClient(cc grpc.ClientConnInterface) NodeClient {
+	return &nodeClient{cc}
+}
+
+func (c *nodeClient) HandleChannel(ctx context.Context, opts ...grpc.CallOption) (Node_HandleChannelClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Node_ServiceDesc.Streams[0], "/inspr.sidecar.Node/HandleChannel", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &nodeHandleChannelClient{stream}
+	return x, nil
+}
+
+type Node_HandleChannelClient interface {
+	Send(*ChannelMessage) error
+	Recv() (*Ack, error)
+	grpc.ClientStream
+}
+
+type nodeHandleChannelClient struct {
+	grpc.ClientStream
+}
+
+func (x *nodeHandleChannelClient) Send(m *ChannelMessage) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *nodeHandleChannelClient) Recv() (*Ack, error) {
+	m := new(Ack)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *nodeClient) HandleRoute(ctx context.Context, in *RouteRequest, opts ...grpc.CallOption) (*RouteResponse, error) {
+	out := new(RouteResponse)
+	err := c.cc.Invoke(ctx, "/inspr.sidecar.Node/HandleRoute", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NodeServer is the server API for Node service.
+// All implementations must embed UnimplementedNodeServer
+// for forward compatibility
+type NodeServer interface {
+	// HandleChannel delivers the messages of the stream to the handlers of
+	// their channels, which acknowledge each of them once it's handled
+	HandleChannel(Node_HandleChannelServer) error
+	// HandleRoute delivers a request to the handler of a route
+	HandleRoute(context.Context, *RouteRequest) (*RouteResponse, error)
+	mustEmbedUnimplementedNodeServer()
+}
+
+// UnimplementedNodeServer must be embedded to have forward compatible implementations.
+type UnimplementedNodeServer struct {
+}
+
+func (UnimplementedNodeServer) HandleChannel(Node_HandleChannelServer) error {
+	return status.Errorf(codes.Unimplemented, "method HandleChannel not implemented")
+}
+func (UnimplementedNodeServer) HandleRoute(context.Context, *RouteRequest) (*RouteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HandleRoute not implemented")
+}
+func (UnimplementedNodeServer) mustEmbedUnimplementedNodeServer() {}
+
+// UnsafeNodeServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to NodeServer will
+// result in compilation errors.
+type UnsafeNodeServer interface {
+	mustEmbedUnimplementedNodeServer()
+}
+
+func RegisterNodeServer(s grpc.ServiceRegistrar, srv NodeServer) {
+	s.RegisterService(&Node_ServiceDesc, srv)
+}
+
+func _Node_HandleChannel_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(NodeServer).HandleChannel(&nodeHandleChannelServer{stream})
+}
+
+type Node_HandleChannelServer interface {
+	Send(*Ack) error
+	Recv() (*ChannelMessage, error)
+	grpc.ServerStream
+}
+
+type nodeHandleChannelServer struct {
+	grpc.ServerStream
+}
+
+func (x *nodeHandleChannelServer) Send(m *Ack) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *nodeHandleChannelServer) Recv() (*ChannelMessage, error) {
+	m := new(ChannelMessage)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Node_HandleRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RouteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServer).HandleRoute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/inspr.sidecar.Node/HandleRoute",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServer).HandleRoute(ctx, req.(*RouteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Node_ServiceDesc is the grpc.ServiceDesc for Node service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Node_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "inspr.sidecar.Node",
+	HandlerType: (*NodeServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "HandleRoute",
+			Handler:    _Node_HandleRoute_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "HandleChannel",
+			Handler:       _Node_HandleChannel_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "sidecar.proto",
+}
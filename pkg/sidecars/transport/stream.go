@@ -0,0 +1,173 @@
+package transport
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/sidecars/models"
+)
+
+// messageClientStream is a stream of messages sent to a gRPC service
+type messageClientStream interface {
+	Send(*ChannelMessage) error
+	Recv() (*Ack, error)
+}
+
+// Streamer sends messages through a stream of a gRPC service and waits for
+// them to be acknowledged. The stream is opened with the first message and
+// opened again after it fails, failing the messages that weren't
+// acknowledged yet
+type Streamer struct {
+	open func(ctx context.Context) (messageClientStream, error)
+
+	mutex   sync.Mutex
+	stream  messageClientStream
+	cancel  context.CancelFunc
+	nextID  uint64
+	pending map[uint64]chan *Ack
+}
+
+// NewSidecarStreamer returns a Streamer that writes messages through the
+// Sidecar service of the connection
+func NewSidecarStreamer(conn grpc.ClientConnInterface) *Streamer {
+	client := NewSidecarClient(conn)
+	return &Streamer{
+		open: func(ctx context.Context) (messageClientStream, error) {
+			return client.WriteMessage(ctx)
+		},
+	}
+}
+
+// NewNodeStreamer returns a Streamer that delivers messages through the Node
+// service of the connection
+func NewNodeStreamer(conn grpc.ClientConnInterface) *Streamer {
+	client := NewNodeClient(conn)
+	return &Streamer{
+		open: func(ctx context.Context) (messageClientStream, error) {
+			return client.HandleChannel(ctx)
+		},
+	}
+}
+
+// Send sends a message, or a batch of messages when batch is set, of the
+// channel through the stream and waits for it to be acknowledged. It returns
+// the error the message was acknowledged with
+func (s *Streamer) Send(ctx context.Context, channel string, batch bool, msgs []models.BrokerMessage) error {
+	converted, err := NewMessages(msgs)
+	if err != nil {
+		return err
+	}
+	ack := make(chan *Ack, 1)
+
+	s.mutex.Lock()
+	if s.stream == nil {
+		if err := s.openStream(); err != nil {
+			s.mutex.Unlock()
+			return err
+		}
+	}
+	stream := s.stream
+	s.nextID++
+	id := s.nextID
+	s.pending[id] = ack
+
+	// messages can't be sent concurrently through a stream
+	err = stream.Send(&ChannelMessage{
+		Id:       id,
+		Channel:  channel,
+		Batch:    batch,
+		Messages: converted,
+	})
+	s.mutex.Unlock()
+	if err != nil {
+		s.reset(stream)
+		return ierrors.New(err).InternalServer()
+	}
+
+	select {
+	case <-ctx.Done():
+		s.forget(stream, id)
+		return ctx.Err()
+	case a, ok := <-ack:
+		if !ok {
+			return ierrors.New(
+				"stream closed before message of channel '%s' was acknowledged", channel,
+			).InternalServer()
+		}
+		return a.Err()
+	}
+}
+
+// Close closes the stream, failing the messages that weren't acknowledged yet
+func (s *Streamer) Close() {
+	s.mutex.Lock()
+	stream := s.stream
+	s.mutex.Unlock()
+	if stream != nil {
+		s.reset(stream)
+	}
+}
+
+// openStream opens a new stream and starts receiving its acknowledgements.
+// It must be called with the mutex locked
+func (s *Streamer) openStream() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := s.open(ctx)
+	if err != nil {
+		cancel()
+		return ierrors.New(err).InternalServer()
+	}
+	s.stream = stream
+	s.cancel = cancel
+	s.pending = make(map[uint64]chan *Ack)
+
+	go s.receive(stream)
+	return nil
+}
+
+// receive gives each acknowledgement received through the stream to the
+// message it acknowledges, until the stream fails
+func (s *Streamer) receive(stream messageClientStream) {
+	for {
+		a, err := stream.Recv()
+		if err != nil {
+			s.reset(stream)
+			return
+		}
+
+		s.mutex.Lock()
+		if ack, ok := s.pending[a.GetId()]; ok && s.stream == stream {
+			delete(s.pending, a.GetId())
+			ack <- a
+		}
+		s.mutex.Unlock()
+	}
+}
+
+// forget stops waiting for the acknowledgement of a message
+func (s *Streamer) forget(stream messageClientStream, id uint64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.stream == stream {
+		delete(s.pending, id)
+	}
+}
+
+// reset closes the stream, if it's still the current one, and fails the
+// messages that weren't acknowledged, so that the next message opens a new one
+func (s *Streamer) reset(stream messageClientStream) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.stream != stream {
+		return
+	}
+
+	s.cancel()
+	for _, ack := range s.pending {
+		close(ack)
+	}
+	s.stream = nil
+	s.pending = nil
+}
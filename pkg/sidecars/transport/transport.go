@@ -0,0 +1,227 @@
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative sidecar.proto
+
+// Package transport defines the gRPC services through which a dApp node and
+// its load balancer sidecar can communicate instead of HTTP.
+//
+// The gRPC services are served on listeners of their own, apart from the HTTP
+// API. Messages are sent as protobuf messages and given straight to the
+// handlers of their channels, while route requests are handled by the HTTP
+// handlers of the routes, which is how the node defines them.
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/rest"
+	"inspr.dev/inspr/pkg/sidecars/models"
+)
+
+// EnvVar is the environment variable that tells a node and its sidecar which
+// transport they communicate through
+const EnvVar = "INSPR_SIDECAR_TRANSPORT"
+
+// environment variables of the localhost ports the gRPC services are served
+// on when the node and its sidecar don't share a directory of Unix sockets
+const (
+	SidecarPortEnvVar = "INSPR_LBSIDECAR_GRPC_PORT"
+	NodePortEnvVar    = "INSPR_SCCLIENT_GRPC_PORT"
+)
+
+// FromEnv returns the transport set for the node and its sidecar, which is
+// HTTP when none is set
+func FromEnv() string {
+	if os.Getenv(EnvVar) == meta.TransportGRPC {
+		return meta.TransportGRPC
+	}
+	return meta.TransportHTTP
+}
+
+// SidecarAddr returns the address of the Sidecar service, which is its Unix
+// socket in the given directory or, when there's none, its localhost port
+func SidecarAddr(socketDir string) string {
+	if socketDir != "" {
+		return UnixTarget(filepath.Join(socketDir, sidecarGRPCSocket))
+	}
+	return "localhost:" + os.Getenv(SidecarPortEnvVar)
+}
+
+// NodeAddr returns the address of the Node service, which is its Unix socket
+// in the given directory or, when there's none, its localhost port
+func NodeAddr(socketDir string) string {
+	if socketDir != "" {
+		return UnixTarget(filepath.Join(socketDir, nodeGRPCSocket))
+	}
+	return "localhost:" + os.Getenv(NodePortEnvVar)
+}
+
+// Listen listens on the address of a gRPC service, as returned by SidecarAddr
+// or NodeAddr
+func Listen(addr string) (net.Listener, error) {
+	if strings.HasPrefix(addr, "unix:") {
+		return ListenUnix(strings.TrimPrefix(addr, "unix:"))
+	}
+	return net.Listen("tcp", addr)
+}
+
+// Dial connects to the gRPC services served at the given address, in the
+// same pod, without TLS
+func Dial(addr string) (*grpc.ClientConn, error) {
+	return grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+}
+
+// Err returns the error of the acknowledged message, which is nil when it
+// was handled
+func (x *Ack) Err() error {
+	if len(x.GetError()) == 0 {
+		return nil
+	}
+	return rest.UnmarshalERROR(bytes.NewReader(x.GetError()))
+}
+
+// newAck acknowledges a message with the error it was handled with, if any
+func newAck(id uint64, err error) *Ack {
+	ack := &Ack{Id: id}
+	if err != nil {
+		ack.Error, _ = json.Marshal(ierrors.New(err))
+	}
+	return ack
+}
+
+// MessageHandler handles a message of a channel, or a batch of messages when
+// batch is set, received through a stream
+type MessageHandler func(ctx context.Context, channel string, batch bool, msgs []models.BrokerMessage) error
+
+// messageServerStream is a stream of messages received by a gRPC service
+type messageServerStream interface {
+	Context() context.Context
+	Recv() (*ChannelMessage, error)
+	Send(*Ack) error
+}
+
+// ServeStream handles each message received through the stream with the
+// handler and sends its acknowledgement back. Messages are handled
+// concurrently, so they can be acknowledged in any order
+func ServeStream(stream messageServerStream, handle MessageHandler) error {
+	acks := make(chan *Ack)
+	errch := make(chan error, 1)
+	go func() {
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				errch <- err
+				return
+			}
+			go func() {
+				err := handle(stream.Context(), msg.GetChannel(), msg.GetBatch(), BrokerMessages(msg.GetMessages()))
+				select {
+				case acks <- newAck(msg.GetId(), err):
+				case <-stream.Context().Done():
+				}
+			}()
+		}
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case err := <-errch:
+			return err
+		case ack := <-acks:
+			if err := stream.Send(ack); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ServeRoute handles a route request received through gRPC with the HTTP
+// handler of the node's routes, as the routes of a node are HTTP handlers
+func ServeRoute(ctx context.Context, handler http.Handler, req *RouteRequest) *RouteResponse {
+	path := "/route/" + req.GetPath()
+	if req.GetRoute() != "" {
+		path = "/route/" + req.GetRoute() + "/" + req.GetPath()
+	}
+	w := &responseWriter{header: make(http.Header)}
+
+	r, err := http.NewRequestWithContext(ctx, req.GetMethod(), path, bytes.NewReader(req.GetBody()))
+	if err != nil {
+		rest.ERROR(w, err)
+	} else {
+		for key, value := range req.GetHeader() {
+			r.Header.Set(key, value)
+		}
+		r.RequestURI = path
+		handler.ServeHTTP(w, r)
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	resp := &RouteResponse{
+		Status: int32(w.status),
+		Header: make(map[string]string),
+		Body:   w.body.Bytes(),
+	}
+	for key := range w.header {
+		resp.Header[key] = w.header.Get(key)
+	}
+	return resp
+}
+
+// responseWriter records the response of a route handler to a request
+// received through gRPC
+type responseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *responseWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(data)
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+// NewNodeServer returns the Node service served by a dApp client, which
+// gives the messages it receives to the handler of their channels and the
+// route requests to the HTTP handler of its routes
+func NewNodeServer(handle MessageHandler, routes http.Handler) NodeServer {
+	return &nodeServer{handle: handle, routes: routes}
+}
+
+type nodeServer struct {
+	UnimplementedNodeServer
+	handle MessageHandler
+	routes http.Handler
+}
+
+func (s *nodeServer) HandleChannel(stream Node_HandleChannelServer) error {
+	return ServeStream(stream, s.handle)
+}
+
+func (s *nodeServer) HandleRoute(ctx context.Context, req *RouteRequest) (*RouteResponse, error) {
+	return ServeRoute(ctx, s.routes, req), nil
+}
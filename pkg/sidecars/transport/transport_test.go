@@ -0,0 +1,425 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"inspr.dev/inspr/pkg/ierrors"
+	"inspr.dev/inspr/pkg/meta"
+	"inspr.dev/inspr/pkg/rest"
+	"inspr.dev/inspr/pkg/sidecars/models"
+)
+
+// newNode serves the Node service on a listener of its own, as the dApp
+// client does, and returns a connection to it
+func newNode(t *testing.T, handle MessageHandler, routes http.Handler) *grpc.ClientConn {
+	server := grpc.NewServer()
+	RegisterNodeServer(server, NewNodeServer(handle, routes))
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := Dial(listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want string
+	}{
+		{
+			name: "grpc transport",
+			env:  "grpc",
+			want: meta.TransportGRPC,
+		},
+		{
+			name: "http transport",
+			env:  "http",
+			want: meta.TransportHTTP,
+		},
+		{
+			name: "transport not set",
+			want: meta.TransportHTTP,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(EnvVar, tt.env)
+			defer os.Unsetenv(EnvVar)
+			if got := FromEnv(); got != tt.want {
+				t.Errorf("FromEnv() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamer_Send(t *testing.T) {
+	var mutex sync.Mutex
+	received := map[string][]models.BrokerMessage{}
+	release := make(chan struct{})
+
+	handle := func(ctx context.Context, channel string, batch bool, msgs []models.BrokerMessage) error {
+		switch channel {
+		case "invalid":
+			return ierrors.New("invalid message").BadRequest()
+		case "slow":
+			<-release
+		}
+		key := channel
+		if batch {
+			key = "batch/" + channel
+		}
+		mutex.Lock()
+		received[key] = msgs
+		mutex.Unlock()
+		return nil
+	}
+
+	conn := newNode(t, handle, http.NotFoundHandler())
+	defer close(release)
+
+	streamer := NewNodeStreamer(conn)
+	defer streamer.Close()
+
+	producedAt := time.Date(2021, 10, 4, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		channel  string
+		batch    bool
+		msgs     []models.BrokerMessage
+		timeout  time.Duration
+		wantCode ierrors.ErrCode
+		wantErr  bool
+		wantKey  string
+	}{
+		{
+			name:    "message handled",
+			channel: "orders",
+			msgs: []models.BrokerMessage{{
+				Data: map[string]interface{}{"id": 1.0, "items": []interface{}{"book"}},
+				Envelope: &models.Envelope{
+					Headers:    map[string]string{"correlation-id": "abc"},
+					Key:        "order-1",
+					ProducedAt: producedAt,
+					SourceApp:  "app1",
+					Offset:     42,
+					Trace:      map[string]string{"traceparent": "00-01"},
+					Attempt:    2,
+				},
+			}},
+			wantKey: "orders",
+		},
+		{
+			name:    "batch handled",
+			channel: "orders",
+			batch:   true,
+			msgs:    []models.BrokerMessage{{Data: "order"}, {Data: 2.0}},
+			wantKey: "batch/orders",
+		},
+		{
+			name:     "message acknowledged with the error of its handler",
+			channel:  "invalid",
+			msgs:     []models.BrokerMessage{{Data: "order"}},
+			wantErr:  true,
+			wantCode: ierrors.BadRequest,
+		},
+		{
+			name:    "message that isn't acknowledged in time",
+			channel: "slow",
+			msgs:    []models.BrokerMessage{{Data: "order"}},
+			timeout: 20 * time.Millisecond,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			err := streamer.Send(ctx, tt.channel, tt.batch, tt.msgs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Streamer.Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantCode != 0 && !ierrors.HasCode(err, tt.wantCode) {
+				t.Errorf("Streamer.Send() error = %v, want code %v", err, tt.wantCode)
+			}
+			if tt.wantKey == "" {
+				return
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if got := received[tt.wantKey]; !reflect.DeepEqual(got, tt.msgs) {
+				t.Errorf("Streamer.Send() delivered %v, want %v", got, tt.msgs)
+			}
+		})
+	}
+}
+
+func TestNewMessages(t *testing.T) {
+	type order struct {
+		ID    int      `json:"id"`
+		Items []string `json:"items"`
+	}
+
+	tests := []struct {
+		name string
+		data interface{}
+		want interface{}
+	}{
+		{
+			name: "data protobuf values can hold",
+			data: map[string]interface{}{"id": 1, "paid": true},
+			want: map[string]interface{}{"id": 1.0, "paid": true},
+		},
+		{
+			name: "struct converted to its JSON value",
+			data: order{ID: 1, Items: []string{"book"}},
+			want: map[string]interface{}{"id": 1.0, "items": []interface{}{"book"}},
+		},
+		{
+			name: "message without data",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs, err := NewMessages([]models.BrokerMessage{{Data: tt.data}})
+			if err != nil {
+				t.Fatalf("NewMessages() error = %v", err)
+			}
+
+			got := BrokerMessages(msgs)
+			if len(got) != 1 || !reflect.DeepEqual(got[0].Data, tt.want) {
+				t.Errorf("BrokerMessages() = %v, want data %v", got, tt.want)
+			}
+			if got[0].Envelope != nil {
+				t.Errorf("BrokerMessages() envelope = %v, want nil", got[0].Envelope)
+			}
+		})
+	}
+
+	t.Run("data that can't be converted", func(t *testing.T) {
+		_, err := NewMessages([]models.BrokerMessage{{Data: make(chan int)}})
+		if !ierrors.HasCode(err, ierrors.BadRequest) {
+			t.Errorf("NewMessages() error = %v, want code %v", err, ierrors.BadRequest)
+		}
+	})
+}
+
+func TestStreamer_Send_concurrently(t *testing.T) {
+	const messages = 50
+
+	var mutex sync.Mutex
+	received := map[interface{}]bool{}
+	handle := func(ctx context.Context, channel string, batch bool, msgs []models.BrokerMessage) error {
+		mutex.Lock()
+		received[msgs[0].Data] = true
+		mutex.Unlock()
+		return nil
+	}
+
+	streamer := NewNodeStreamer(newNode(t, handle, http.NotFoundHandler()))
+	defer streamer.Close()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, messages)
+	for i := 0; i < messages; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- streamer.Send(context.Background(), "orders", false, []models.BrokerMessage{{Data: fmt.Sprint(i)}})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Streamer.Send() error = %v", err)
+		}
+	}
+	if len(received) != messages {
+		t.Errorf("Streamer.Send() delivered %v messages, want %v", len(received), messages)
+	}
+}
+
+func TestStreamer_Send_reopen(t *testing.T) {
+	handle := func(ctx context.Context, channel string, batch bool, msgs []models.BrokerMessage) error {
+		return nil
+	}
+
+	streamer := NewNodeStreamer(newNode(t, handle, http.NotFoundHandler()))
+	if err := streamer.Send(context.Background(), "orders", false, nil); err != nil {
+		t.Fatalf("Streamer.Send() error = %v", err)
+	}
+
+	// the next message opens a new stream
+	streamer.Close()
+	if err := streamer.Send(context.Background(), "orders", false, nil); err != nil {
+		t.Errorf("Streamer.Send() after Close() error = %v", err)
+	}
+	streamer.Close()
+}
+
+func TestServeRoute(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/route/hello/world", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("X-Method", r.Method)
+		w.Header().Set("X-Forwarded-For", r.Header.Get("X-Forwarded-For"))
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	})
+
+	conn := newNode(t, nil, mux)
+
+	tests := []struct {
+		name       string
+		req        *RouteRequest
+		wantStatus int32
+		wantBody   string
+		wantHeader map[string]string
+	}{
+		{
+			name: "request handled by the route",
+			req: &RouteRequest{
+				Path:   "hello/world",
+				Method: http.MethodPut,
+				Header: map[string]string{"X-Forwarded-For": "10.0.0.1"},
+				Body:   []byte(`"hello"`),
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   `"hello"`,
+			wantHeader: map[string]string{
+				"X-Method":        http.MethodPut,
+				"X-Forwarded-For": "10.0.0.1",
+			},
+		},
+		{
+			name: "path without a handler",
+			req: &RouteRequest{
+				Path:   "goodbye",
+				Method: http.MethodGet,
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := NewNodeClient(conn).HandleRoute(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("HandleRoute() error = %v", err)
+			}
+			if resp.GetStatus() != tt.wantStatus {
+				t.Errorf("HandleRoute() status = %v, want %v", resp.GetStatus(), tt.wantStatus)
+			}
+			if tt.wantBody != "" && string(resp.GetBody()) != tt.wantBody {
+				t.Errorf("HandleRoute() body = %s, want %v", resp.GetBody(), tt.wantBody)
+			}
+			for key, value := range tt.wantHeader {
+				if resp.GetHeader()[key] != value {
+					t.Errorf("HandleRoute() header %v = %v, want %v", key, resp.GetHeader()[key], value)
+				}
+			}
+		})
+	}
+}
+
+func TestUnixSocket(t *testing.T) {
+	dir := t.TempDir()
+	path := NodeSocket(dir)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/channel/orders", func(w http.ResponseWriter, r *http.Request) {
+		rest.JSON(w, http.StatusOK, nil)
+	})
+
+	// a socket left behind by a previous run is replaced
+	if _, err := ListenUnix(path); err != nil {
+		t.Fatalf("ListenUnix() error = %v", err)
+	}
+	listener, err := ListenUnix(path)
+	if err != nil {
+		t.Fatalf("ListenUnix() error = %v", err)
+	}
+	node := &http.Server{Handler: mux}
+	go node.Serve(listener)
+	defer node.Close()
+
+	t.Run("HTTP request sent through the socket", func(t *testing.T) {
+		resp, err := UnixClient(path).Post("http://unix/channel/orders", "application/json", nil)
+		if err != nil {
+			t.Fatalf("UnixClient() error = %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("UnixClient() status = %v, want %v", resp.StatusCode, http.StatusOK)
+		}
+	})
+
+	t.Run("gRPC message sent through the socket of the service", func(t *testing.T) {
+		handle := func(ctx context.Context, channel string, batch bool, msgs []models.BrokerMessage) error {
+			return nil
+		}
+		server := grpc.NewServer()
+		RegisterNodeServer(server, NewNodeServer(handle, mux))
+
+		addr := NodeAddr(dir)
+		if addr == UnixTarget(path) {
+			t.Fatalf("NodeAddr() = %v, the socket of the HTTP API", addr)
+		}
+		listener, err := Listen(addr)
+		if err != nil {
+			t.Fatalf("Listen() error = %v", err)
+		}
+		go server.Serve(listener)
+		defer server.Stop()
+
+		conn, err := Dial(addr)
+		if err != nil {
+			t.Fatalf("Dial() error = %v", err)
+		}
+		defer conn.Close()
+
+		streamer := NewNodeStreamer(conn)
+		defer streamer.Close()
+		if err := streamer.Send(context.Background(), "orders", false, nil); err != nil {
+			t.Errorf("Streamer.Send() error = %v", err)
+		}
+	})
+}
+
+func TestNodeAddr(t *testing.T) {
+	os.Setenv(NodePortEnvVar, "3049")
+	defer os.Unsetenv(NodePortEnvVar)
+
+	if got, want := NodeAddr(""), "localhost:3049"; got != want {
+		t.Errorf("NodeAddr() = %v, want %v", got, want)
+	}
+	if got, want := NodeAddr("/inspr/sockets"), "unix:/inspr/sockets/node-grpc.sock"; got != want {
+		t.Errorf("NodeAddr() = %v, want %v", got, want)
+	}
+}
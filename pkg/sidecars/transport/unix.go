@@ -0,0 +1,59 @@
+package transport
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// names of the Unix sockets created in the directory shared by the node and
+// its load balancer sidecar. The gRPC services have sockets of their own
+const (
+	sidecarSocket     = "lbsidecar.sock"
+	nodeSocket        = "node.sock"
+	sidecarGRPCSocket = "lbsidecar-grpc.sock"
+	nodeGRPCSocket    = "node-grpc.sock"
+)
+
+// SidecarSocket returns the path of the Unix socket, in the given directory,
+// on which the load balancer sidecar receives the messages and requests of
+// the node
+func SidecarSocket(dir string) string {
+	return filepath.Join(dir, sidecarSocket)
+}
+
+// NodeSocket returns the path of the Unix socket, in the given directory, on
+// which the node receives the messages and requests delivered by its sidecar
+func NodeSocket(dir string) string {
+	return filepath.Join(dir, nodeSocket)
+}
+
+// ListenUnix listens on the Unix socket at the given path, removing the one
+// left behind by a previous run of the container
+func ListenUnix(path string) (net.Listener, error) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return net.Listen("unix", path)
+}
+
+// UnixClient returns an HTTP client that sends every request through the Unix
+// socket at the given path, whatever the host of its URL is
+func UnixClient(path string) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", path)
+			},
+		},
+	}
+}
+
+// UnixTarget returns the address through which the gRPC services served on
+// the Unix socket at the given path are dialed
+func UnixTarget(path string) string {
+	return "unix:" + path
+}
@@ -0,0 +1,193 @@
+// Package tracing sets up the OpenTelemetry tracing of dApp nodes and their
+// sidecars, and carries the trace context of messages and requests between
+// them, so that the path of a message through every dApp it crossed is a
+// single trace.
+//
+// The spans are exported with the exporter set in the INSPR_TRACING_EXPORTER
+// environment variable. When there's none, spans are still created and their
+// context carried, but they aren't exported.
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	"go.opentelemetry.io/otel/exporters/stdout"
+	"go.opentelemetry.io/otel/propagation"
+	exporttrace "go.opentelemetry.io/otel/sdk/export/trace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+	"inspr.dev/inspr/pkg/ierrors"
+)
+
+const (
+	// ExporterEnv is the environment variable of the exporter of the spans
+	ExporterEnv = "INSPR_TRACING_EXPORTER"
+	// EndpointEnv is the environment variable of the address of the OTLP
+	// collector the spans are exported to
+	EndpointEnv = "INSPR_TRACING_ENDPOINT"
+)
+
+const (
+	// ExporterOTLP exports the spans to an OTLP collector through gRPC
+	ExporterOTLP = "otlp"
+	// ExporterStdout writes the spans to the standard output, for local testing
+	ExporterStdout = "stdout"
+)
+
+// instrumentationName is the name of the tracer of the spans created by Inspr
+const instrumentationName = "inspr.dev/inspr"
+
+// AppIDKey is the attribute of the ID of the dApp a span was created in
+const AppIDKey = attribute.Key("inspr.app_id")
+
+var (
+	// propagator writes and reads the trace context carried by messages and
+	// requests, in the W3C format
+	propagator = propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	)
+
+	// provider creates the spans of Inspr. It doesn't export them until Init
+	// is called, but they are still created so that their context is carried
+	provider trace.TracerProvider = sdktrace.NewTracerProvider()
+	mutex    sync.RWMutex
+)
+
+// Init sets up the tracing of the process, exporting the spans of the given
+// service with the exporter set in the environment. It returns the function
+// that exports the remaining spans and stops the exporter.
+func Init(ctx context.Context, service string) (func(context.Context) error, error) {
+	exporter, err := newExporter(ctx, os.Getenv(ExporterEnv), os.Getenv(EndpointEnv))
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.ServiceNameKey.String(service),
+			AppIDKey.String(os.Getenv("INSPR_APP_ID")),
+		)),
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	tp := sdktrace.NewTracerProvider(opts...)
+	SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// newExporter returns the exporter of the given kind, which is nil when
+// there's none
+func newExporter(ctx context.Context, kind, endpoint string) (exporttrace.SpanExporter, error) {
+	switch kind {
+	case "":
+		return nil, nil
+	case ExporterStdout:
+		return stdout.NewExporter(stdout.WithoutMetricExport())
+	case ExporterOTLP:
+		opts := []otlpgrpc.Option{otlpgrpc.WithInsecure()}
+		if endpoint != "" {
+			opts = append(opts, otlpgrpc.WithEndpoint(endpoint))
+		}
+		return otlp.NewExporter(ctx, otlpgrpc.NewDriver(opts...))
+	default:
+		return nil, ierrors.New(
+			"invalid tracing exporter '%s', it must be '%s' or '%s'",
+			kind, ExporterOTLP, ExporterStdout,
+		).BadRequest()
+	}
+}
+
+// SetTracerProvider sets the provider of the spans created by Inspr, which is
+// also set as the global provider of OpenTelemetry, so that the handlers of
+// the node can create their own spans with it
+func SetTracerProvider(tp trace.TracerProvider) {
+	mutex.Lock()
+	provider = tp
+	mutex.Unlock()
+	otel.SetTracerProvider(tp)
+}
+
+// Tracer returns the tracer of the spans created by Inspr
+func Tracer() trace.Tracer {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return provider.Tracer(instrumentationName)
+}
+
+// End ends the span, setting its status to the error when there is one
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Inject returns the trace context of ctx, in the fields it's carried in,
+// which is nil when there's none
+func Inject(ctx context.Context) map[string]string {
+	carrier := mapCarrier{}
+	propagator.Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// Extract returns ctx with the trace context carried in the given fields
+func Extract(ctx context.Context, fields map[string]string) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	return propagator.Extract(ctx, mapCarrier(fields))
+}
+
+// SpanContext returns the span context carried in the given fields, which is
+// invalid when there's none
+func SpanContext(fields map[string]string) trace.SpanContext {
+	return trace.RemoteSpanContextFromContext(Extract(context.Background(), fields))
+}
+
+// InjectHeader sets the trace context of ctx in the HTTP header
+func InjectHeader(ctx context.Context, header http.Header) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// ExtractHeader returns ctx with the trace context carried in the HTTP header
+func ExtractHeader(ctx context.Context, header http.Header) context.Context {
+	return propagator.Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// mapCarrier carries the trace context in a map, such as the envelope of a message
+type mapCarrier map[string]string
+
+func (c mapCarrier) Get(key string) string { return c[key] }
+
+// Set leaves out empty fields, such as the trace state of most traces, so that
+// they aren't stored along with the messages
+func (c mapCarrier) Set(key, value string) {
+	if value != "" {
+		c[key] = value
+	}
+}
+
+func (c mapCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,122 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Test_newExporter(t *testing.T) {
+	tests := []struct {
+		name         string
+		kind         string
+		wantExporter bool
+		wantErr      bool
+	}{
+		{
+			name: "no exporter",
+		},
+		{
+			name:         "stdout exporter",
+			kind:         ExporterStdout,
+			wantExporter: true,
+		},
+		{
+			name:         "otlp exporter",
+			kind:         ExporterOTLP,
+			wantExporter: true,
+		},
+		{
+			name:    "invalid exporter",
+			kind:    "zipkin",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newExporter(context.Background(), tt.kind, "localhost:4317")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newExporter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (got != nil) != tt.wantExporter {
+				t.Errorf("newExporter() = %v, wantExporter %v", got, tt.wantExporter)
+			}
+			if got != nil {
+				got.Shutdown(context.Background())
+			}
+		})
+	}
+}
+
+func TestInit(t *testing.T) {
+	defer SetTracerProvider(sdktrace.NewTracerProvider())
+
+	os.Setenv(ExporterEnv, "zipkin")
+	if _, err := Init(context.Background(), "node"); err == nil {
+		t.Errorf("Init() with an invalid exporter didn't fail")
+	}
+
+	os.Setenv(ExporterEnv, ExporterStdout)
+	defer os.Unsetenv(ExporterEnv)
+	shutdown, err := Init(context.Background(), "node")
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	_, span := Tracer().Start(context.Background(), "span")
+	End(span, errors.New("handler failed"))
+	if !span.SpanContext().IsValid() {
+		t.Errorf("Tracer() started an invalid span")
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("Init() shutdown error = %v", err)
+	}
+}
+
+func TestInject(t *testing.T) {
+	if got := Inject(context.Background()); got != nil {
+		t.Errorf("Inject() without a span = %v, want nil", got)
+	}
+
+	ctx, span := Tracer().Start(context.Background(), "span")
+	defer span.End()
+
+	fields := Inject(ctx)
+	if fields["traceparent"] == "" {
+		t.Fatalf("Inject() = %v, want a traceparent", fields)
+	}
+	if got := SpanContext(fields); !got.Equal(span.SpanContext().WithRemote(true)) {
+		t.Errorf("SpanContext() = %v, want %v", got, span.SpanContext())
+	}
+
+	// the span started from the fields continues their trace
+	_, child := Tracer().Start(Extract(context.Background(), fields), "child")
+	defer child.End()
+	if child.SpanContext().TraceID() != span.SpanContext().TraceID() {
+		t.Errorf("Extract() didn't continue the trace %v", span.SpanContext().TraceID())
+	}
+	if parent := child.(sdktrace.ReadOnlySpan).Parent(); parent.SpanID() != span.SpanContext().SpanID() {
+		t.Errorf("Extract() parent = %v, want %v", parent.SpanID(), span.SpanContext().SpanID())
+	}
+}
+
+func TestInjectHeader(t *testing.T) {
+	ctx, span := Tracer().Start(context.Background(), "span")
+	defer span.End()
+
+	header := make(http.Header)
+	InjectHeader(ctx, header)
+	if header.Get("traceparent") == "" {
+		t.Fatalf("InjectHeader() = %v, want a traceparent", header)
+	}
+
+	got := trace.RemoteSpanContextFromContext(ExtractHeader(context.Background(), header))
+	if got.TraceID() != span.SpanContext().TraceID() || got.SpanID() != span.SpanContext().SpanID() {
+		t.Errorf("ExtractHeader() = %v, want %v", got, span.SpanContext())
+	}
+}